@@ -0,0 +1,381 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package benchmark provides a harness to measure the performance of
+// controllers under synthetic churn, e.g. to catch regressions in Go
+// benchmarks.
+//
+// The harness creates, updates and deletes objects through a client, which
+// is typically the client of a manager running against envtest, or a fake
+// client whose writes are delivered to a fake cache. It instruments the
+// reconciler of the controller under test to measure how long it takes for
+// writes to be reconciled, and samples the depth of the controller's queue:
+//
+//	h := &benchmark.Harness{
+//		Client:         mgr.GetClient(),
+//		ControllerName: "configmap",
+//		Workload: benchmark.Workload{
+//			Objects:   1000,
+//			Updates:   5,
+//			NewObject: func(i int) client.Object { ... },
+//		},
+//	}
+//	err := builder.ControllerManagedBy(mgr).For(&corev1.ConfigMap{}).
+//		Named("configmap").Complete(h.Reconciler(r))
+//	...
+//	report, err := h.Run(ctx)
+//	report.ReportMetrics(b)
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// churnAnnotation is the annotation updated by the default update function.
+const churnAnnotation = "benchmark.controller-runtime.sigs.k8s.io/churn"
+
+// Workload describes the churn generated by a Harness.
+type Workload struct {
+	// Objects is the number of objects to create.
+	Objects int
+
+	// Updates is the number of times each object is updated after it has
+	// been created.
+	Updates int
+
+	// Delete makes the harness delete all objects once they have been
+	// updated.
+	Delete bool
+
+	// NewObject returns the i-th object to create. It is required.
+	NewObject func(i int) client.Object
+
+	// Update mutates an object before it is patched. Defaults to setting
+	// an annotation to a new value.
+	Update func(obj client.Object)
+
+	// Concurrency is the number of objects that are written concurrently.
+	// Defaults to 1.
+	Concurrency int
+
+	// Rate is the maximum number of writes per second. Zero means no limit.
+	Rate float64
+}
+
+// Harness generates churn and measures how the controller under test keeps
+// up with it. A Harness must not be copied after first use and runs a single
+// workload at a time.
+type Harness struct {
+	// Client is used to write objects. Writes must be observed by the
+	// controller under test.
+	Client client.Client
+
+	// Workload is the churn to generate.
+	Workload Workload
+
+	// ControllerName is the name of the controller under test. If set, the
+	// depth of its queue is sampled from the controller-runtime metrics.
+	ControllerName string
+
+	// SettleTimeout is how long Run waits for the controller to reconcile
+	// all writes once they have been made. Defaults to one minute.
+	SettleTimeout time.Duration
+
+	// SampleInterval is the interval at which the queue depth is sampled.
+	// Defaults to 10ms.
+	SampleInterval time.Duration
+
+	mu                 sync.Mutex
+	pending            map[types.NamespacedName]time.Time
+	reconciles         int
+	reconcileErrors    int
+	reconcileLatencies []time.Duration
+	eventLatencies     []time.Duration
+}
+
+// Reconciler instruments r to measure its performance. The returned
+// Reconciler must be used by the controller under test.
+func (h *Harness) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		start := time.Now()
+		h.observe(req.NamespacedName, start)
+		result, err := r.Reconcile(ctx, req)
+		h.record(time.Since(start), err)
+		return result, err
+	})
+}
+
+// Run generates the workload, waits for the controller to reconcile all
+// writes and reports the measurements. The controller under test must have
+// been started. Run returns an error if a write fails; writes that are not
+// reconciled within the settle timeout are reported as unobserved.
+func (h *Harness) Run(ctx context.Context) (*Report, error) {
+	if h.Workload.NewObject == nil {
+		return nil, errors.New("must provide Workload.NewObject")
+	}
+	h.reset()
+
+	sampleCtx, stopSampling := context.WithCancel(ctx)
+	defer stopSampling()
+	maxDepth := make(chan int, 1)
+	go func() {
+		maxDepth <- h.sampleQueueDepth(sampleCtx)
+	}()
+
+	start := time.Now()
+	writes, err := h.churn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.settle(ctx)
+	duration := time.Since(start)
+
+	stopSampling()
+	return h.report(duration, writes, <-maxDepth), nil
+}
+
+// churn writes the workload and returns the number of writes.
+func (h *Harness) churn(ctx context.Context) (int, error) {
+	w := h.Workload
+	concurrency := max(w.Concurrency, 1)
+	update := w.Update
+	if update == nil {
+		update = func(obj client.Object) {
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[churnAnnotation] = time.Now().Format(time.RFC3339Nano)
+			obj.SetAnnotations(annotations)
+		}
+	}
+
+	var throttle <-chan time.Time
+	if w.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / w.Rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	write := func(obj client.Object, fn func() error) error {
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle:
+			}
+		}
+		h.written(client.ObjectKeyFromObject(obj), time.Now())
+		return fn()
+	}
+
+	indexes := make(chan int)
+	errs := make(chan error, concurrency)
+	var writes sync.WaitGroup
+	var writeCount int
+	var countMu sync.Mutex
+	count := func() {
+		countMu.Lock()
+		writeCount++
+		countMu.Unlock()
+	}
+	for range concurrency {
+		writes.Add(1)
+		go func() {
+			defer writes.Done()
+			for i := range indexes {
+				obj := w.NewObject(i)
+				if err := write(obj, func() error { return h.Client.Create(ctx, obj) }); err != nil {
+					errs <- fmt.Errorf("failed to create %s: %w", client.ObjectKeyFromObject(obj), err)
+					return
+				}
+				count()
+				for range w.Updates {
+					base := obj.DeepCopyObject().(client.Object)
+					update(obj)
+					if err := write(obj, func() error { return h.Client.Patch(ctx, obj, client.MergeFrom(base)) }); err != nil {
+						errs <- fmt.Errorf("failed to update %s: %w", client.ObjectKeyFromObject(obj), err)
+						return
+					}
+					count()
+				}
+				if w.Delete {
+					if err := write(obj, func() error { return client.IgnoreNotFound(h.Client.Delete(ctx, obj)) }); err != nil {
+						errs <- fmt.Errorf("failed to delete %s: %w", client.ObjectKeyFromObject(obj), err)
+						return
+					}
+					count()
+				}
+			}
+		}()
+	}
+
+	var err error
+loop:
+	for i := range w.Objects {
+		select {
+		case indexes <- i:
+		case err = <-errs:
+			break loop
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+	close(indexes)
+	writes.Wait()
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	return writeCount, err
+}
+
+// settle waits until all writes have been reconciled, or the settle timeout
+// expires.
+func (h *Harness) settle(ctx context.Context) {
+	timeout := h.SettleTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		h.mu.Lock()
+		pending := len(h.pending)
+		h.mu.Unlock()
+		if pending == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sampleQueueDepth samples the depth of the queue of the controller under
+// test until ctx is done, and returns the maximum depth.
+func (h *Harness) sampleQueueDepth(ctx context.Context) int {
+	if h.ControllerName == "" {
+		return 0
+	}
+	interval := h.SampleInterval
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var maxDepth int
+	for {
+		if depth, ok := queueDepth(h.ControllerName); ok {
+			maxDepth = max(maxDepth, depth)
+		}
+		select {
+		case <-ctx.Done():
+			return maxDepth
+		case <-ticker.C:
+		}
+	}
+}
+
+// queueDepth returns the depth of the queue of the given controller from
+// the controller-runtime metrics registry.
+func queueDepth(controller string) (int, bool) {
+	families, err := metrics.Registry.Gather()
+	if err != nil && len(families) == 0 {
+		return 0, false
+	}
+	for _, family := range families {
+		if family.GetName() != metrics.WorkQueueSubsystem+"_"+metrics.DepthKey {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if hasLabel(metric, "name", controller) {
+				return int(metric.GetGauge().GetValue()), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func hasLabel(metric *dto.Metric, name, value string) bool {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name && label.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Harness) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending = map[types.NamespacedName]time.Time{}
+	h.reconciles = 0
+	h.reconcileErrors = 0
+	h.reconcileLatencies = nil
+	h.eventLatencies = nil
+}
+
+// written records a write to the object with the given key.
+func (h *Harness) written(key types.NamespacedName, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// Keep the oldest unobserved write, as the controller coalesces
+	// events for the same object.
+	if _, ok := h.pending[key]; !ok {
+		h.pending[key] = at
+	}
+}
+
+// observe records the start of a reconcile of the object with the given key.
+func (h *Harness) observe(key types.NamespacedName, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if writtenAt, ok := h.pending[key]; ok {
+		h.eventLatencies = append(h.eventLatencies, at.Sub(writtenAt))
+		delete(h.pending, key)
+	}
+}
+
+// record records the end of a reconcile.
+func (h *Harness) record(latency time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reconciles++
+	h.reconcileLatencies = append(h.reconcileLatencies, latency)
+	if err != nil {
+		h.reconcileErrors++
+	}
+}
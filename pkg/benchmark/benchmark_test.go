@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestHarnessRun(t *testing.T) {
+	h := &Harness{
+		Workload: Workload{
+			Objects:     20,
+			Updates:     2,
+			Delete:      true,
+			Concurrency: 4,
+			NewObject: func(i int) client.Object {
+				return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("cm-%d", i), Namespace: "default"}}
+			},
+		},
+		SettleTimeout: 10 * time.Second,
+	}
+
+	// Simulate a controller that reconciles every write.
+	r := h.Reconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		time.Sleep(time.Millisecond)
+		return reconcile.Result{}, nil
+	}))
+	enqueue := func(ctx context.Context, obj client.Object) {
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+		go func() {
+			_, _ = r.Reconcile(ctx, req)
+		}()
+	}
+	h.Client = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			err := c.Create(ctx, obj, opts...)
+			enqueue(ctx, obj)
+			return err
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			err := c.Patch(ctx, obj, patch, opts...)
+			enqueue(ctx, obj)
+			return err
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			err := c.Delete(ctx, obj, opts...)
+			enqueue(ctx, obj)
+			return err
+		},
+	}).Build()
+
+	report, err := h.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Writes != 80 {
+		t.Errorf("expected 80 writes, got %d", report.Writes)
+	}
+	if report.Unobserved != 0 {
+		t.Errorf("expected all writes to be observed, got %d unobserved", report.Unobserved)
+	}
+	if report.Reconciles == 0 || report.Throughput() <= 0 {
+		t.Errorf("expected reconciles to be measured, got %d", report.Reconciles)
+	}
+	if report.ReconcileLatency.P50 < time.Millisecond {
+		t.Errorf("expected reconcile latency of at least 1ms, got %s", report.ReconcileLatency)
+	}
+}
+
+func TestHarnessRunRequiresNewObject(t *testing.T) {
+	h := &Harness{Client: fake.NewClientBuilder().Build()}
+	if _, err := h.Run(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestLatencyOf(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	l := latencyOf(durations)
+	if l.P50 != 50*time.Millisecond || l.P90 != 90*time.Millisecond || l.P99 != 99*time.Millisecond || l.Max != 100*time.Millisecond {
+		t.Errorf("unexpected latency %s", l)
+	}
+	if (latencyOf(nil) != Latency{}) {
+		t.Errorf("expected zero latency for no durations")
+	}
+}
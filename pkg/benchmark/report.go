@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Report holds the measurements of a run of a Harness.
+type Report struct {
+	// Duration is the time it took to write the workload and for the
+	// controller to reconcile all writes.
+	Duration time.Duration
+
+	// Writes is the number of writes made.
+	Writes int
+
+	// Reconciles is the number of reconciles performed.
+	Reconciles int
+
+	// Errors is the number of reconciles that returned an error.
+	Errors int
+
+	// Unobserved is the number of objects whose last writes were not
+	// reconciled within the settle timeout.
+	Unobserved int
+
+	// ReconcileLatency is the distribution of the durations of reconciles.
+	ReconcileLatency Latency
+
+	// EventLatency is the distribution of the time between a write and the
+	// start of the reconcile that observed it.
+	EventLatency Latency
+
+	// MaxQueueDepth is the maximum depth of the queue of the controller
+	// that was sampled.
+	MaxQueueDepth int
+}
+
+// Latency summarizes a distribution of latencies.
+type Latency struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// String returns a human readable representation of the latency.
+func (l Latency) String() string {
+	return fmt.Sprintf("p50=%s p90=%s p99=%s max=%s", l.P50, l.P90, l.P99, l.Max)
+}
+
+// Throughput returns the number of reconciles per second.
+func (r *Report) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Reconciles) / r.Duration.Seconds()
+}
+
+// String returns a human readable representation of the report.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "duration: %s\n", r.Duration)
+	fmt.Fprintf(&b, "writes: %d\n", r.Writes)
+	fmt.Fprintf(&b, "reconciles: %d (%.1f/s, %d errors)\n", r.Reconciles, r.Throughput(), r.Errors)
+	fmt.Fprintf(&b, "unobserved: %d\n", r.Unobserved)
+	fmt.Fprintf(&b, "reconcile latency: %s\n", r.ReconcileLatency)
+	fmt.Fprintf(&b, "event latency: %s\n", r.EventLatency)
+	fmt.Fprintf(&b, "max queue depth: %d\n", r.MaxQueueDepth)
+	return b.String()
+}
+
+// MetricReporter records custom benchmark metrics. It is implemented by
+// *testing.B.
+type MetricReporter interface {
+	ReportMetric(n float64, unit string)
+}
+
+// ReportMetrics reports the measurements as custom benchmark metrics.
+func (r *Report) ReportMetrics(b MetricReporter) {
+	b.ReportMetric(r.Throughput(), "reconciles/s")
+	b.ReportMetric(float64(r.ReconcileLatency.P99.Nanoseconds()), "p99-reconcile-ns")
+	b.ReportMetric(float64(r.EventLatency.P50.Nanoseconds()), "p50-event-ns")
+	b.ReportMetric(float64(r.EventLatency.P99.Nanoseconds()), "p99-event-ns")
+	b.ReportMetric(float64(r.MaxQueueDepth), "max-queue-depth")
+}
+
+func (h *Harness) report(duration time.Duration, writes, maxQueueDepth int) *Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return &Report{
+		Duration:         duration,
+		Writes:           writes,
+		Reconciles:       h.reconciles,
+		Errors:           h.reconcileErrors,
+		Unobserved:       len(h.pending),
+		ReconcileLatency: latencyOf(h.reconcileLatencies),
+		EventLatency:     latencyOf(h.eventLatencies),
+		MaxQueueDepth:    maxQueueDepth,
+	}
+}
+
+func latencyOf(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	percentile := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	return Latency{
+		P50: percentile(0.5),
+		P90: percentile(0.9),
+		P99: percentile(0.99),
+		Max: sorted[len(sorted)-1],
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// patchStatusCondition sets condition in the status.conditions of obj, and
+// patches the status of obj if they changed. The conditions are left
+// untouched if skip returns true for the current condition of the type,
+// nil if there is none. obj must have a status subresource, and conditions
+// of type metav1.Condition. opts are passed to the merge patch.
+func patchStatusCondition(ctx context.Context, c client.Client, obj client.Object, condition metav1.Condition, skip func(current *metav1.Condition) bool, opts ...client.MergeFromOption) error {
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	rawConditions, _, err := unstructured.NestedSlice(content, "status", "conditions")
+	if err != nil {
+		return fmt.Errorf("failed to read conditions: %w", err)
+	}
+	conditions := make([]metav1.Condition, len(rawConditions))
+	for i, raw := range rawConditions {
+		rawCondition, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("failed to read conditions: condition %d is a %T", i, raw)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawCondition, &conditions[i]); err != nil {
+			return fmt.Errorf("failed to read conditions: %w", err)
+		}
+	}
+
+	if skip(meta.FindStatusCondition(conditions, condition.Type)) {
+		return nil
+	}
+	if !meta.SetStatusCondition(&conditions, condition) {
+		return nil
+	}
+
+	rawConditions = make([]interface{}, len(conditions))
+	for i := range conditions {
+		if rawConditions[i], err = runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i]); err != nil {
+			return err
+		}
+	}
+	if content["status"] == nil {
+		// Objects without status may have a null status.
+		delete(content, "status")
+	}
+	if err := unstructured.SetNestedSlice(content, rawConditions, "status", "conditions"); err != nil {
+		return err
+	}
+	patched := obj.DeepCopyObject().(client.Object)
+	if u, ok := patched.(*unstructured.Unstructured); ok {
+		u.Object = content
+	} else if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, patched); err != nil {
+		return err
+	}
+	if err := c.Status().Patch(ctx, patched, client.MergeFromWithOptions(obj, opts...)); err != nil {
+		return fmt.Errorf("failed to set %s condition: %w", condition.Type, err)
+	}
+	return nil
+}
+
+// toUnstructured returns a copy of the content of obj.
+func toUnstructured(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return runtime.DeepCopyJSON(u.UnstructuredContent()), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
@@ -17,20 +17,27 @@ limitations under the License.
 package builder
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	internalcontroller "sigs.k8s.io/controller-runtime/pkg/internal/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -64,6 +71,7 @@ type TypedBuilder[request comparable] struct {
 	ctrl             controller.TypedController[request]
 	ctrlOptions      controller.TypedOptions[request]
 	name             string
+	permissions      []permissionInput
 	newController    func(name string, mgr manager.Manager, options controller.TypedOptions[request]) (controller.TypedController[request], error)
 }
 
@@ -82,6 +90,10 @@ type ForInput struct {
 	object           client.Object
 	predicates       []predicate.Predicate
 	objectProjection objectProjection
+	suspend          *SuspendConfig
+	reconcileStatus  *ReconcileStatusConfig
+	pause            *predicate.PauseOptions[client.Object]
+	correlateByUID   bool
 	err              error
 }
 
@@ -110,6 +122,7 @@ type OwnsInput struct {
 	object           client.Object
 	predicates       []predicate.Predicate
 	objectProjection objectProjection
+	selector         *Selector
 }
 
 // Owns defines types of Objects being *generated* by the ControllerManagedBy, and configures the ControllerManagedBy to respond to
@@ -130,9 +143,19 @@ func (blder *TypedBuilder[request]) Owns(object client.Object, opts ...OwnsOptio
 	return blder
 }
 
+// OwnsMetadata is the same as Owns, but forces the internal cache to only
+// watch PartialObjectMetadata, see OnlyMetadata. The owned objects must then
+// be read as metav1.PartialObjectMetadata in the reconciler, see
+// WatchesMetadata.
+func (blder *TypedBuilder[request]) OwnsMetadata(object client.Object, opts ...OwnsOption) *TypedBuilder[request] {
+	opts = append(opts, OnlyMetadata)
+	return blder.Owns(object, opts...)
+}
+
 type untypedWatchesInput interface {
 	setPredicates([]predicate.Predicate)
 	setObjectProjection(objectProjection)
+	setSelector(*Selector)
 }
 
 // WatchesInput represents the information set by Watches method.
@@ -141,6 +164,7 @@ type WatchesInput[request comparable] struct {
 	handler          handler.TypedEventHandler[client.Object, request]
 	predicates       []predicate.Predicate
 	objectProjection objectProjection
+	selector         *Selector
 }
 
 func (w *WatchesInput[request]) setPredicates(predicates []predicate.Predicate) {
@@ -151,6 +175,10 @@ func (w *WatchesInput[request]) setObjectProjection(objectProjection objectProje
 	w.objectProjection = objectProjection
 }
 
+func (w *WatchesInput[request]) setSelector(selector *Selector) {
+	w.selector = selector
+}
+
 // Watches defines the type of Object to watch, and configures the ControllerManagedBy to respond to create / delete /
 // update events by *reconciling the object* with the given EventHandler.
 //
@@ -175,6 +203,8 @@ func (blder *TypedBuilder[request]) Watches(
 }
 
 // WatchesMetadata is the same as Watches, but forces the internal cache to only watch PartialObjectMetadata.
+// The objects of the events passed to eventHandler are *metav1.PartialObjectMetadata, use
+// MetadataHandler to pass a handler of that type.
 //
 // This is useful when watching lots of objects, really big objects, or objects for which you only know
 // the GVK, but not the structure. You'll need to pass metav1.PartialObjectMetadata to the client
@@ -244,11 +274,35 @@ func (blder *TypedBuilder[request]) WithLogConstructor(logConstructor func(*requ
 	return blder
 }
 
+// StartWhen delays processing of requests until condition is met, while
+// the controller's watches are already started and queue events.
+func (blder *TypedBuilder[request]) StartWhen(condition controller.StartCondition) *TypedBuilder[request] {
+	blder.ctrlOptions.StartWhen = condition
+	return blder
+}
+
+// permissionInput represents the information set by the RequiresPermissions method.
+type permissionInput struct {
+	object client.Object
+	verbs  []string
+}
+
+// RequiresPermissions declares that the reconciler needs the given verbs on
+// the kind of object, in addition to the get, list and watch permissions on
+// the watched objects which are derived automatically. The permissions are
+// reported by the manager's GetControllers and checked when it starts if its
+// PermissionCheck option is set.
+func (blder *TypedBuilder[request]) RequiresPermissions(object client.Object, verbs ...string) *TypedBuilder[request] {
+	blder.permissions = append(blder.permissions, permissionInput{object: object, verbs: verbs})
+	return blder
+}
+
 // Named sets the name of the controller to the given name. The name shows up
 // in metrics, among other things, and thus should be a prometheus compatible name
 // (underscores and alphanumeric characters only).
 //
-// By default, controllers are named using the lowercase version of their kind.
+// By default, controllers are named using the lowercase version of their kind,
+// or using the Controller.NameGenerator setting from the Manager if set.
 //
 // The name must be unique as it is used to identify the controller in metrics and logs.
 func (blder *TypedBuilder[request]) Named(name string) *TypedBuilder[request] {
@@ -348,7 +402,7 @@ func (blder *TypedBuilder[request]) doWatch() error {
 		)))
 		allPredicates := append([]predicate.Predicate(nil), blder.globalPredicates...)
 		allPredicates = append(allPredicates, own.predicates...)
-		src := source.TypedKind(blder.mgr.GetCache(), obj, hdler, allPredicates...)
+		src := source.TypedKind(blder.cacheFor(own.selector), obj, hdler, allPredicates...)
 		if err := blder.ctrl.Watch(src); err != nil {
 			return err
 		}
@@ -365,7 +419,7 @@ func (blder *TypedBuilder[request]) doWatch() error {
 		}
 		allPredicates := append([]predicate.Predicate(nil), blder.globalPredicates...)
 		allPredicates = append(allPredicates, w.predicates...)
-		if err := blder.ctrl.Watch(source.TypedKind(blder.mgr.GetCache(), projected, w.handler, allPredicates...)); err != nil {
+		if err := blder.ctrl.Watch(source.TypedKind(blder.cacheFor(w.selector), projected, w.handler, allPredicates...)); err != nil {
 			return err
 		}
 	}
@@ -377,6 +431,31 @@ func (blder *TypedBuilder[request]) doWatch() error {
 	return nil
 }
 
+// cacheFor returns the cache to watch objects restricted by selector with.
+func (blder *TypedBuilder[request]) cacheFor(selector *Selector) cache.Cache {
+	if selector == nil {
+		return blder.mgr.GetCache()
+	}
+	return &selectingCache{
+		Cache: blder.mgr.GetCache(),
+		opts:  []cache.InformerGetOption{cache.WithInformerSelector(selector.Label, selector.Field, selector.Namespaces...)},
+	}
+}
+
+// selectingCache restricts the informers it returns with opts.
+type selectingCache struct {
+	cache.Cache
+	opts []cache.InformerGetOption
+}
+
+func (c *selectingCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	return c.Cache.GetInformer(ctx, obj, append(opts, c.opts...)...)
+}
+
+func (c *selectingCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	return c.Cache.GetInformerForKind(ctx, gvk, append(opts, c.opts...)...)
+}
+
 func (blder *TypedBuilder[request]) getControllerName(gvk schema.GroupVersionKind, hasGVK bool) (string, error) {
 	if blder.name != "" {
 		return blder.name, nil
@@ -384,10 +463,13 @@ func (blder *TypedBuilder[request]) getControllerName(gvk schema.GroupVersionKin
 	if !hasGVK {
 		return "", errors.New("one of For() or Named() must be called")
 	}
+	if nameGenerator := blder.mgr.GetControllerOptions().NameGenerator; nameGenerator != nil {
+		return nameGenerator(gvk), nil
+	}
 	return strings.ToLower(gvk.Kind), nil
 }
 
-func (blder *TypedBuilder[request]) doController(r reconcile.TypedReconciler[request]) error {
+func (blder *TypedBuilder[request]) doController(r reconcile.TypedReconciler[request]) (err error) {
 	globalOpts := blder.mgr.GetControllerOptions()
 
 	ctrlOptions := blder.ctrlOptions
@@ -429,38 +511,248 @@ func (blder *TypedBuilder[request]) doController(r reconcile.TypedReconciler[req
 		return err
 	}
 
-	// Setup the logger.
-	if ctrlOptions.LogConstructor == nil {
-		log := blder.mgr.GetLogger().WithValues(
-			"controller", controllerName,
-		)
-		if hasGVK {
-			log = log.WithValues(
-				"controllerGroup", gvk.Group,
-				"controllerKind", gvk.Kind,
-			)
+	// Setup the warm-up pass of the For objects, which replaces the events
+	// of their initial list.
+	if ctrlOptions.WarmUp != nil && ctrlOptions.WarmUp.List == nil && hasGVK {
+		warmUp := *ctrlOptions.WarmUp
+		if warmUp.List, err = blder.warmUpList(gvk); err != nil {
+			return err
 		}
+		ctrlOptions.WarmUp = &warmUp
+		blder.forInput.predicates = append(blder.forInput.predicates, predicate.SkipInitialListPredicate{})
+	}
 
-		ctrlOptions.LogConstructor = func(in *request) logr.Logger {
-			log := log
+	// Setup the dropping of the events of paused For objects.
+	pause := blder.forInput.pause
+	if pause == nil && globalOpts.PauseAnnotation != "" && hasGVK {
+		pause = &predicate.PauseOptions[client.Object]{Annotation: globalOpts.PauseAnnotation}
+	}
+	if pause != nil {
+		opts := *pause
+		if opts.Controller == "" {
+			opts.Controller = controllerName
+		}
+		blder.forInput.predicates = append(blder.forInput.predicates, predicate.NotPaused(opts))
+	}
 
-			if req, ok := any(in).(*reconcile.Request); ok && req != nil {
-				if hasGVK {
-					log = log.WithValues(gvk.Kind, klog.KRef(req.Namespace, req.Name))
-				}
-				log = log.WithValues(
-					"namespace", req.Namespace, "name", req.Name,
-				)
-			}
-			return log
+	// Setup the correlation of reconciles by object UID.
+	if blder.forInput.correlateByUID && ctrlOptions.ObjectUID == nil {
+		if ctrlOptions.ObjectUID, err = blder.objectUID(); err != nil {
+			return err
+		}
+	}
+
+	// Setup the object of the events recorded for the requests not
+	// completed at shutdown.
+	if ctrlOptions.Shutdown != nil && ctrlOptions.Shutdown.Recorder != nil && ctrlOptions.Shutdown.EventObject == nil {
+		shutdown := *ctrlOptions.Shutdown
+		if shutdown.EventObject, err = blder.eventObject(); err != nil {
+			return err
+		}
+		ctrlOptions.Shutdown = &shutdown
+	}
+
+	// Setup the permissions required by the controller.
+	permissions, err := blder.requiredPermissions()
+	if err != nil {
+		return err
+	}
+	ctrlOptions.Permissions = append(permissions, ctrlOptions.Permissions...)
+
+	// Reserve the controller name. The builder reserves it itself rather
+	// than leaving it to the controller so that the logger it constructs
+	// carries the final, possibly suffixed, name.
+	if ctrlOptions.SkipNameValidation == nil {
+		ctrlOptions.SkipNameValidation = globalOpts.SkipNameValidation
+	}
+	if ctrlOptions.SuffixDuplicateNames == nil {
+		ctrlOptions.SuffixDuplicateNames = globalOpts.SuffixDuplicateNames
+	}
+	if !ptr.Deref(ctrlOptions.SkipNameValidation, false) {
+		suffix := ptr.Deref(ctrlOptions.SuffixDuplicateNames, false)
+		if controllerName, err = internalcontroller.ReserveName(controllerName, suffix); err != nil {
+			return err
 		}
+		ctrlOptions.SkipNameValidation = ptr.To(true)
+		reservedName := controllerName
+		defer func() {
+			if err != nil {
+				internalcontroller.ReleaseName(reservedName)
+			}
+		}()
 	}
 
 	if blder.newController == nil {
 		blder.newController = controller.NewTyped[request]
 	}
 
+	reconciler := ctrlOptions.Reconciler
+	if blder.forInput.reconcileStatus != nil {
+		if reconciler, err = blder.reportingReconcileStatus(reconciler); err != nil {
+			return err
+		}
+		ctrlOptions.Reconciler = reconciler
+	}
+	if ctrlOptions.LogConstructor == nil {
+		ctrlOptions.LogConstructor = blder.newLogConstructor(controllerName, gvk, hasGVK)
+	}
+	if blder.forInput.suspend != nil {
+		if ctrlOptions.Reconciler, err = blder.suspendable(controllerName, reconciler); err != nil {
+			return err
+		}
+	}
+
 	// Build the controller and return.
 	blder.ctrl, err = blder.newController(controllerName, blder.mgr, ctrlOptions)
 	return err
 }
+
+// suspendable wraps r to skip the reconciliation of suspended objects of
+// the For type.
+func (blder *TypedBuilder[request]) suspendable(controllerName string, r reconcile.TypedReconciler[request]) (reconcile.TypedReconciler[request], error) {
+	untyped, ok := any(r).(reconcile.Reconciler)
+	if !ok {
+		return nil, errors.New("suspendable controllers must reconcile reconcile.Request")
+	}
+	if blder.forInput.object == nil {
+		return nil, errors.New("suspendable controllers must reconcile a For() object")
+	}
+	obj, err := blder.project(blder.forInput.object, blder.forInput.objectProjection)
+	if err != nil {
+		return nil, err
+	}
+	suspendable := newSuspendReconciler(untyped, blder.mgr.GetClient(), obj, *blder.forInput.suspend, controllerName)
+	return any(suspendable).(reconcile.TypedReconciler[request]), nil
+}
+
+// reportingReconcileStatus wraps r to report the outcome of the reconciles
+// in a condition of the For objects.
+func (blder *TypedBuilder[request]) reportingReconcileStatus(r reconcile.TypedReconciler[request]) (reconcile.TypedReconciler[request], error) {
+	untyped, ok := any(r).(reconcile.Reconciler)
+	if !ok {
+		return nil, errors.New("reporting the reconcile status is only supported by controllers of reconcile.Request")
+	}
+	if blder.forInput.object == nil {
+		return nil, errors.New("reporting the reconcile status requires a For() object")
+	}
+	obj, err := blder.project(blder.forInput.object, blder.forInput.objectProjection)
+	if err != nil {
+		return nil, err
+	}
+	reporting := &reconcileStatusReconciler{
+		Reconciler: untyped,
+		client:     blder.mgr.GetClient(),
+		reader:     blder.mgr.GetAPIReader(),
+		object:     obj,
+		config:     *blder.forInput.reconcileStatus,
+	}
+	return any(reporting).(reconcile.TypedReconciler[request]), nil
+}
+
+// objectUID returns a function reading the UID of the For object of a
+// request from the cache.
+func (blder *TypedBuilder[request]) objectUID() (func(context.Context, request) types.UID, error) {
+	var zero request
+	if _, ok := any(zero).(reconcile.Request); !ok {
+		return nil, errors.New("correlating by UID is only supported by controllers of reconcile.Request")
+	}
+	if blder.forInput.object == nil {
+		return nil, errors.New("correlating by UID requires a For() object")
+	}
+	obj, err := blder.project(blder.forInput.object, blder.forInput.objectProjection)
+	if err != nil {
+		return nil, err
+	}
+	reader := blder.mgr.GetClient()
+	return func(ctx context.Context, req request) types.UID {
+		o := obj.DeepCopyObject().(client.Object)
+		if err := reader.Get(ctx, any(req).(reconcile.Request).NamespacedName, o); err != nil {
+			return ""
+		}
+		return o.GetUID()
+	}, nil
+}
+
+// eventObject returns a function returning the For object of a request,
+// with only its name and namespace set, for recording events about it.
+func (blder *TypedBuilder[request]) eventObject() (func(request) runtime.Object, error) {
+	var zero request
+	if _, ok := any(zero).(reconcile.Request); !ok {
+		return nil, errors.New("recording shutdown events requires Shutdown.EventObject for controllers of other requests than reconcile.Request")
+	}
+	if blder.forInput.object == nil {
+		return nil, errors.New("recording shutdown events requires Shutdown.EventObject or a For() object")
+	}
+	obj := blder.forInput.object
+	return func(req request) runtime.Object {
+		o := obj.DeepCopyObject().(client.Object)
+		o.SetNamespace(any(req).(reconcile.Request).Namespace)
+		o.SetName(any(req).(reconcile.Request).Name)
+		return o
+	}, nil
+}
+
+// watchVerbs are the verbs required to watch objects through the cache.
+var watchVerbs = []string{"get", "list", "watch"}
+
+// requiredPermissions returns the permissions required to watch the objects
+// given to For, Owns and Watches, and those declared with RequiresPermissions.
+// Raw sources are opaque to the builder and not taken into account.
+func (blder *TypedBuilder[request]) requiredPermissions() ([]manager.Permission, error) {
+	inputs := make([]permissionInput, 0, 1+len(blder.ownsInput)+len(blder.watchesInput)+len(blder.permissions))
+	if blder.forInput.object != nil {
+		inputs = append(inputs, permissionInput{object: blder.forInput.object, verbs: watchVerbs})
+	}
+	for _, own := range blder.ownsInput {
+		inputs = append(inputs, permissionInput{object: own.object, verbs: watchVerbs})
+	}
+	for _, w := range blder.watchesInput {
+		if w.obj != nil {
+			inputs = append(inputs, permissionInput{object: w.obj, verbs: watchVerbs})
+		}
+	}
+	inputs = append(inputs, blder.permissions...)
+
+	permissions := make([]manager.Permission, 0, len(inputs))
+	for _, input := range inputs {
+		gvk, err := apiutil.GVKForObject(input.object, blder.mgr.GetScheme())
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, manager.Permission{
+			GroupVersionKind: gvk,
+			Verbs:            slices.Clone(input.verbs),
+		})
+	}
+	return permissions, nil
+}
+
+func (blder *TypedBuilder[request]) newLogConstructor(controllerName string, gvk schema.GroupVersionKind, hasGVK bool) func(*request) logr.Logger {
+	log := blder.mgr.GetLogger().WithValues(
+		"controller", controllerName,
+	)
+	if hasGVK {
+		log = log.WithValues(
+			"controllerGroup", gvk.Group,
+			"controllerKind", gvk.Kind,
+		)
+	}
+
+	return func(in *request) logr.Logger {
+		log := log
+
+		if req, ok := any(in).(*reconcile.Request); ok && req != nil {
+			if hasGVK {
+				log = log.WithValues(gvk.Kind, klog.KRef(req.Namespace, req.Name))
+			}
+			log = log.WithValues(
+				"namespace", req.Namespace, "name", req.Name,
+			)
+		} else if req, ok := any(in).(fmt.Stringer); ok && in != nil {
+			// Custom requests, e.g. reconcile.ExternalRequest, are logged
+			// by their string representation.
+			log = log.WithValues("request", req.String())
+		}
+		return log
+	}
+}
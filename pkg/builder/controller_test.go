@@ -19,7 +19,9 @@ package builder
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/go-logr/logr"
@@ -28,6 +30,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -161,6 +164,53 @@ var _ = Describe("application", func() {
 			Expect(instance).NotTo(BeNil())
 		})
 
+		It("should suffix the controller name if it is already in use and SuffixDuplicateNames is set", func() {
+			By("creating a controller manager")
+			m, err := manager.New(cfg, manager.Options{
+				Controller: config.Controller{SuffixDuplicateNames: ptr.To(true)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			newController := func(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
+				c, err := controller.New(name, mgr, options)
+				if err == nil {
+					names = append(names, name)
+				}
+				return c, err
+			}
+
+			for range 2 {
+				blder := ControllerManagedBy(m).
+					Named("suffixed_controller").
+					Watches(&appsv1.ReplicaSet{}, &handler.EnqueueRequestForObject{})
+				blder.newController = newController
+				Expect(blder.Complete(noop)).To(Succeed())
+			}
+			Expect(names).To(Equal([]string{"suffixed_controller", "suffixed_controller_2"}))
+		})
+
+		It("should use the NameGenerator to default the controller name", func() {
+			By("creating a controller manager")
+			m, err := manager.New(cfg, manager.Options{
+				Controller: config.Controller{
+					NameGenerator: func(gvk schema.GroupVersionKind) string {
+						return "generated_" + strings.ToLower(gvk.Kind)
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var name string
+			blder := ControllerManagedBy(m).For(&appsv1.StatefulSet{})
+			blder.newController = func(n string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
+				name = n
+				return controller.New(n, mgr, options)
+			}
+			Expect(blder.Complete(noop)).To(Succeed())
+			Expect(name).To(Equal("generated_statefulset"))
+		})
+
 		It("should return an error if there is no GVK for an object, and thus we can't default the controller name", func() {
 			By("creating a controller manager")
 			m, err := manager.New(cfg, manager.Options{})
@@ -237,6 +287,7 @@ var _ = Describe("application", func() {
 
 			builder := ControllerManagedBy(m).
 				For(&appsv1.ReplicaSet{}).
+				Named("replicaset-error").
 				Owns(&appsv1.ReplicaSet{})
 			builder.newController = func(name string, mgr manager.Manager, options controller.Options) (
 				controller.Controller, error) {
@@ -246,6 +297,14 @@ var _ = Describe("application", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("expected error"))
 			Expect(instance).To(BeNil())
+
+			By("releasing the name of the controller that failed to be created")
+			instance, err = ControllerManagedBy(m).
+				For(&appsv1.ReplicaSet{}).
+				Named("replicaset-error").
+				Build(noop)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instance).NotTo(BeNil())
 		})
 
 		It("should override max concurrent reconcilers during creation of controller", func() {
@@ -527,6 +586,38 @@ var _ = Describe("application", func() {
 		})
 	})
 
+	Describe("watching with selectors", func() {
+		It("should restrict the informers of Owns and Watches", func() {
+			recordingCache := &selectorRecordingCache{restrictions: map[reflect.Type]cache.InformerGetOptions{}}
+			m, err := manager.New(cfg, manager.Options{NewCache: func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+				c, err := cache.New(config, opts)
+				recordingCache.Cache = c
+				return recordingCache, err
+			}})
+			Expect(err).NotTo(HaveOccurred())
+
+			selector := labels.SelectorFromSet(labels.Set{"foo": "bar"})
+			bldr := ControllerManagedBy(m).
+				For(&appsv1.Deployment{}).
+				Named("deployment-selector").
+				Owns(&appsv1.ReplicaSet{}, WithSelector(Selector{Label: selector, Namespaces: []string{"default"}})).
+				Watches(&appsv1.StatefulSet{}, &handler.EnqueueRequestForObject{}, WithSelector(Selector{Namespaces: []string{"default"}}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			doReconcileTest(ctx, "selector", m, false, bldr)
+
+			recordingCache.mu.Lock()
+			defer recordingCache.mu.Unlock()
+			Expect(recordingCache.restrictions).NotTo(HaveKey(reflect.TypeOf(&appsv1.Deployment{})))
+			Expect(recordingCache.restrictions).To(HaveKey(reflect.TypeOf(&appsv1.ReplicaSet{})))
+			Expect(recordingCache.restrictions[reflect.TypeOf(&appsv1.ReplicaSet{})].Selector.Label).To(Equal(selector))
+			Expect(recordingCache.restrictions[reflect.TypeOf(&appsv1.ReplicaSet{})].Namespaces).To(Equal([]string{"default"}))
+			Expect(recordingCache.restrictions[reflect.TypeOf(&appsv1.StatefulSet{})].Selector).To(BeNil())
+			Expect(recordingCache.restrictions[reflect.TypeOf(&appsv1.StatefulSet{})].Namespaces).To(Equal([]string{"default"}))
+		})
+	})
+
 	Describe("watching with projections", func() {
 		var mgr manager.Manager
 		BeforeEach(func() {
@@ -613,9 +704,70 @@ var _ = Describe("application", func() {
 				return true
 			}).Should(BeTrue())
 		})
+
+		It("should support OwnsMetadata and WatchesMetadata with a metadata handler", func() {
+			statefulSetMaps := make(chan *metav1.PartialObjectMetadata, 1)
+
+			bldr := ControllerManagedBy(mgr).
+				For(&appsv1.Deployment{}, OnlyMetadata).
+				Named("deployment-13").
+				OwnsMetadata(&appsv1.ReplicaSet{}).
+				WatchesMetadata(&appsv1.StatefulSet{},
+					MetadataHandler(handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, o *metav1.PartialObjectMetadata) []reconcile.Request {
+						select {
+						case statefulSetMaps <- o:
+						default:
+						}
+						return nil
+					})))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			doReconcileTest(ctx, "13", mgr, true, bldr)
+
+			By("Creating a new stateful set")
+			set := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test13"},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+					},
+				},
+			}
+			Expect(mgr.GetClient().Create(ctx, set)).To(Succeed())
+
+			By("Checking that the mapping function has been called")
+			var metaSet *metav1.PartialObjectMetadata
+			Eventually(statefulSetMaps).Should(Receive(&metaSet))
+			Expect(metaSet.Name).To(Equal(set.Name))
+		})
 	})
 })
 
+// selectorRecordingCache is a cache.Cache that records the restrictions of
+// the informers requested from it.
+type selectorRecordingCache struct {
+	cache.Cache
+
+	mu           sync.Mutex
+	restrictions map[reflect.Type]cache.InformerGetOptions
+}
+
+func (c *selectorRecordingCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	getOpts := cache.InformerGetOptions{}
+	for _, opt := range opts {
+		opt(&getOpts)
+	}
+	if getOpts.Selector != nil || len(getOpts.Namespaces) > 0 {
+		c.mu.Lock()
+		c.restrictions[reflect.TypeOf(obj)] = getOpts
+		c.mu.Unlock()
+	}
+	return c.Cache.GetInformer(ctx, obj, opts...)
+}
+
 // newNonTypedOnlyCache returns a new cache that wraps the normal cache,
 // returning an error if normal, typed objects have informers requested.
 func newNonTypedOnlyCache(config *rest.Config, opts cache.Options) (cache.Cache, error) {
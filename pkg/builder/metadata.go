@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// MetadataHandler adapts an event handler of metadata-only objects, e.g. a
+// handler.TypedEnqueueRequestsFromMapFunc of *metav1.PartialObjectMetadata,
+// to the handler of WatchesMetadata, so that it doesn't need to type-assert
+// the objects of the events itself:
+//
+//	ControllerManagedBy(mgr).
+//		For(&appsv1.Deployment{}).
+//		WatchesMetadata(&corev1.Secret{}, MetadataHandler(handler.TypedEnqueueRequestsFromMapFunc(
+//			func(ctx context.Context, secret *metav1.PartialObjectMetadata) []reconcile.Request {
+//				...
+//			},
+//		)))
+//
+// Events of objects that are not metadata-only are dropped.
+func MetadataHandler[request comparable](h handler.TypedEventHandler[*metav1.PartialObjectMetadata, request]) handler.TypedEventHandler[client.Object, request] {
+	return handler.TypedFuncs[client.Object, request]{
+		CreateFunc: func(ctx context.Context, e event.TypedCreateEvent[client.Object], q workqueue.TypedRateLimitingInterface[request]) {
+			if obj, ok := e.Object.(*metav1.PartialObjectMetadata); ok {
+				h.Create(ctx, event.TypedCreateEvent[*metav1.PartialObjectMetadata]{Object: obj, IsInInitialList: e.IsInInitialList}, q)
+			}
+		},
+		UpdateFunc: func(ctx context.Context, e event.TypedUpdateEvent[client.Object], q workqueue.TypedRateLimitingInterface[request]) {
+			oldObj, okOld := e.ObjectOld.(*metav1.PartialObjectMetadata)
+			newObj, okNew := e.ObjectNew.(*metav1.PartialObjectMetadata)
+			if okOld && okNew {
+				h.Update(ctx, event.TypedUpdateEvent[*metav1.PartialObjectMetadata]{ObjectOld: oldObj, ObjectNew: newObj}, q)
+			}
+		},
+		DeleteFunc: func(ctx context.Context, e event.TypedDeleteEvent[client.Object], q workqueue.TypedRateLimitingInterface[request]) {
+			if obj, ok := e.Object.(*metav1.PartialObjectMetadata); ok {
+				h.Delete(ctx, event.TypedDeleteEvent[*metav1.PartialObjectMetadata]{Object: obj, DeleteStateUnknown: e.DeleteStateUnknown}, q)
+			}
+		},
+		GenericFunc: func(ctx context.Context, e event.TypedGenericEvent[client.Object], q workqueue.TypedRateLimitingInterface[request]) {
+			if obj, ok := e.Object.(*metav1.PartialObjectMetadata); ok {
+				h.Generic(ctx, event.TypedGenericEvent[*metav1.PartialObjectMetadata]{Object: obj}, q)
+			}
+		},
+	}
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package builder
 
 import (
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -77,6 +81,49 @@ var _ WatchesOption = &Predicates{}
 
 // }}}
 
+// {{{ Owns & Watches Dual-Type options
+
+// Selector restricts the objects watched by Owns or Watches. Its label and
+// field selectors are ANDed with the ones the cache is configured with.
+type Selector struct {
+	// Label, if set, restricts the objects to those matching it.
+	Label labels.Selector
+
+	// Field, if set, restricts the objects to those matching it.
+	Field fields.Selector
+
+	// Namespaces, if set, restricts the objects to those of the given
+	// namespaces. It is ignored for cluster-scoped objects.
+	Namespaces []string
+}
+
+// WithSelector restricts Owns or Watches to the objects selected by s. The
+// restriction is pushed down to the cache, which lists and watches only
+// those objects with an informer dedicated to the restriction, so that the
+// handler doesn't get events for the others at all, unlike with predicates.
+//
+// Reading the objects from the cache, e.g. with the manager's client, still
+// uses the unrestricted informer, so restricting a type that is also read
+// keeps both informers in memory.
+func WithSelector(s Selector) Selector {
+	return s
+}
+
+// ApplyToOwns applies this configuration to the given OwnsInput options.
+func (s Selector) ApplyToOwns(opts *OwnsInput) {
+	opts.selector = &s
+}
+
+// ApplyToWatches applies this configuration to the given WatchesInput options.
+func (s Selector) ApplyToWatches(opts untypedWatchesInput) {
+	opts.setSelector(&s)
+}
+
+var _ OwnsOption = Selector{}
+var _ WatchesOption = Selector{}
+
+// }}}
+
 // {{{ For & Owns Dual-Type options
 
 // projectAs configures the projection on the input.
@@ -154,3 +201,36 @@ type matchEveryOwner struct{}
 func (o matchEveryOwner) ApplyToOwns(opts *OwnsInput) {
 	opts.matchEveryOwner = true
 }
+
+// CorrelateByUID makes the controller read the object of each request from
+// the cache before reconciling it, to add its UID to the logger of the
+// reconciliation and make it the default exemplar of its duration metric.
+// This allows tracking an object across delete and recreate cycles, which
+// keep its namespace and name. See controller.Options.ObjectUID.
+//
+// CorrelateByUID is only supported by controllers of reconcile.Request.
+var CorrelateByUID = &correlateByUID{}
+
+type correlateByUID struct{}
+
+// ApplyToFor applies this configuration to the given ForInput options.
+func (c correlateByUID) ApplyToFor(opts *ForInput) {
+	opts.correlateByUID = true
+}
+
+// Pausable makes the controller drop the events of the For objects paused
+// with an annotation, see predicate.NotPaused. The Controller of the options
+// defaults to the name of the controller. Use the PauseAnnotation of the
+// manager's controller options to make every controller pausable.
+func Pausable(opts predicate.PauseOptions[client.Object]) ForOption {
+	return &pausable{opts: opts}
+}
+
+type pausable struct {
+	opts predicate.PauseOptions[client.Object]
+}
+
+// ApplyToFor applies this configuration to the given ForInput options.
+func (p *pausable) ApplyToFor(opts *ForInput) {
+	opts.pause = &p.opts
+}
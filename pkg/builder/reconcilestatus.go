@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultReconciledConditionType is the default type of the condition
+	// set by ReportReconcileStatus.
+	DefaultReconciledConditionType = "Reconciled"
+
+	// ReconcileSucceededReason is the reason of the condition set on
+	// objects whose last reconcile succeeded.
+	ReconcileSucceededReason = "ReconcileSucceeded"
+
+	// ReconcileFailedReason is the reason of the condition set on objects
+	// whose last reconcile failed.
+	ReconcileFailedReason = "ReconcileFailed"
+
+	// maxReconcileErrorLength is the length beyond which reconcile errors
+	// are truncated in the message of the condition.
+	maxReconcileErrorLength = 1024
+)
+
+// ReconcileStatusConfig configures ReportReconcileStatus.
+type ReconcileStatusConfig struct {
+	// ConditionType is the type of the condition set in the
+	// status.conditions of the reconciled objects. Defaults to
+	// DefaultReconciledConditionType.
+	ConditionType string
+
+	// ReportReconcileTime adds the time of the last reconcile to the
+	// message of the condition, which is then patched after every
+	// reconcile rather than only when the outcome changes. Every reconcile
+	// thus triggers an update event, which the controller must ignore,
+	// e.g. with a predicate.GenerationChangedPredicate, not to reconcile
+	// the objects in a loop.
+	ReportReconcileTime bool
+}
+
+// ReportReconcileStatus makes the controller report the outcome of the
+// reconciles of the objects of the For type in a condition of their status,
+// keeping users informed without status plumbing in every reconciler. The
+// condition is True with the ReconcileSucceededReason after a successful
+// reconcile, and False with the ReconcileFailedReason and the error as
+// message after a failed one. Its message ends with the ID of the reconcile
+// that set it, and its LastTransitionTime is the time its status changed.
+// Objects must have a status subresource, and conditions of type
+// metav1.Condition.
+//
+// The objects are read from the cache after every reconcile, and their
+// status is only patched when the outcome of their reconcile changes, or
+// when they failed with another error, unless ReportReconcileTime is set.
+// The patches use optimistic locking, so that conditions set by the
+// reconcile are not overwritten with stale ones from the cache; on
+// conflict, the object is read from the API server and the patch retried.
+// Reconcile errors whose message changes on every reconcile thus trigger
+// an update event on every failure, use a
+// predicate.GenerationChangedPredicate to ignore them.
+//
+// ReportReconcileStatus is only supported by controllers of
+// reconcile.Request.
+func ReportReconcileStatus(config ReconcileStatusConfig) ForOption {
+	if config.ConditionType == "" {
+		config.ConditionType = DefaultReconciledConditionType
+	}
+	return &reportReconcileStatus{config: config}
+}
+
+type reportReconcileStatus struct {
+	config ReconcileStatusConfig
+}
+
+// ApplyToFor applies this configuration to the given ForInput options.
+func (r *reportReconcileStatus) ApplyToFor(opts *ForInput) {
+	opts.reconcileStatus = &r.config
+}
+
+// reconcileStatusReconciler reports the outcome of the reconciles of the
+// wrapped Reconciler in a condition of the reconciled objects.
+type reconcileStatusReconciler struct {
+	reconcile.Reconciler
+
+	// client reads the objects from the cache and patches their status,
+	// reader reads them from the API server on conflicts.
+	client client.Client
+	reader client.Reader
+	object client.Object
+	config ReconcileStatusConfig
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *reconcileStatusReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	reconciledAt := time.Now()
+	result, reconcileErr := r.Reconciler.Reconcile(ctx, req)
+
+	if err := r.report(ctx, req, reconcileErr, reconciledAt); err != nil {
+		// Retry successful reconciles to report their status, unless they
+		// are requeued anyway.
+		if reconcileErr == nil && result.IsZero() {
+			return result, err
+		}
+		logf.FromContext(ctx).Error(err, "Failed to report the reconcile status")
+	}
+	return result, reconcileErr
+}
+
+// report sets the condition of the object of req, read from the cache, and
+// from the API server if the cached one is stale.
+func (r *reconcileStatusReconciler) report(ctx context.Context, req reconcile.Request, reconcileErr error, reconciledAt time.Time) error {
+	obj := r.object.DeepCopyObject().(client.Object)
+	if err := r.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	err := r.patch(ctx, obj, reconcileErr, reconciledAt)
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+
+	obj = r.object.DeepCopyObject().(client.Object)
+	if err := r.reader.Get(ctx, req.NamespacedName, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return r.patch(ctx, obj, reconcileErr, reconciledAt)
+}
+
+func (r *reconcileStatusReconciler) patch(ctx context.Context, obj client.Object, reconcileErr error, reconciledAt time.Time) error {
+	condition := metav1.Condition{
+		Type:               r.config.ConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReconcileSucceededReason,
+		Message:            "Reconciled successfully",
+		ObservedGeneration: obj.GetGeneration(),
+	}
+	if reconcileErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReconcileFailedReason
+		condition.Message = truncateReconcileError(reconcileErr.Error())
+	}
+	outcome := condition.Message
+	if r.config.ReportReconcileTime {
+		condition.Message = fmt.Sprintf("%s (reconcileID %s, reconciled at %s)", outcome, controller.ReconcileIDFromContext(ctx), reconciledAt.UTC().Format(time.RFC3339))
+	} else {
+		condition.Message = fmt.Sprintf("%s (reconcileID %s)", outcome, controller.ReconcileIDFromContext(ctx))
+	}
+
+	return patchStatusCondition(ctx, r.client, obj, condition, func(current *metav1.Condition) bool {
+		return !r.config.ReportReconcileTime &&
+			current != nil &&
+			current.Status == condition.Status &&
+			current.Reason == condition.Reason &&
+			current.ObservedGeneration == condition.ObservedGeneration &&
+			strings.HasPrefix(current.Message, outcome+" (reconcileID ")
+	}, client.MergeFromWithOptimisticLock{})
+}
+
+// truncateReconcileError truncates message to maxReconcileErrorLength
+// bytes, without splitting a multi-byte character.
+func truncateReconcileError(message string) string {
+	if len(message) <= maxReconcileErrorLength {
+		return message
+	}
+	end := maxReconcileErrorLength
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + "..."
+}
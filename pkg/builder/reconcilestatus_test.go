@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("reconcileStatusReconciler", func() {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	newWidget := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "widget"}}
+
+	var (
+		ctx          context.Context
+		widget       *unstructured.Unstructured
+		c            client.WithWatch
+		r            *reconcileStatusReconciler
+		result       reconcile.Result
+		reconcileErr error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		widget = newWidget()
+		widget.SetNamespace(req.Namespace)
+		widget.SetName(req.Name)
+		c = fake.NewClientBuilder().WithObjects(widget).WithStatusSubresource(newWidget()).Build()
+		result, reconcileErr = reconcile.Result{}, nil
+		r = &reconcileStatusReconciler{
+			Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return result, reconcileErr
+			}),
+			client: c,
+			reader: c,
+			object: newWidget(),
+			config: ReconcileStatusConfig{ConditionType: DefaultReconciledConditionType},
+		}
+	})
+
+	// currentCondition returns the condition of the widget and its
+	// resourceVersion.
+	currentCondition := func() (map[string]interface{}, string) {
+		obj := newWidget()
+		ExpectWithOffset(1, c.Get(ctx, req.NamespacedName, obj)).To(Succeed())
+		raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		ExpectWithOffset(1, raw).To(HaveLen(1))
+		return raw[0].(map[string]interface{}), obj.GetResourceVersion()
+	}
+
+	reconcileAndCheck := func(expectedStatus metav1.ConditionStatus, expectedReason, expectedMessage string) string {
+		_, err := r.Reconcile(ctx, req)
+		if reconcileErr == nil {
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		} else {
+			ExpectWithOffset(1, err).To(MatchError(reconcileErr))
+		}
+		condition, rv := currentCondition()
+		ExpectWithOffset(1, condition).To(HaveKeyWithValue("type", DefaultReconciledConditionType))
+		ExpectWithOffset(1, condition).To(HaveKeyWithValue("status", string(expectedStatus)))
+		ExpectWithOffset(1, condition).To(HaveKeyWithValue("reason", expectedReason))
+		ExpectWithOffset(1, condition["message"]).To(HavePrefix(expectedMessage + " (reconcileID "))
+		return rv
+	}
+
+	It("should only patch the condition when the outcome changes", func() {
+		reconcileAndCheck(metav1.ConditionTrue, ReconcileSucceededReason, "Reconciled successfully")
+
+		reconcileErr = errors.New("boom")
+		rv := reconcileAndCheck(metav1.ConditionFalse, ReconcileFailedReason, "boom")
+		Expect(reconcileAndCheck(metav1.ConditionFalse, ReconcileFailedReason, "boom")).To(Equal(rv),
+			"expected the status not to be patched when the reconcile fails with the same error")
+
+		reconcileErr = nil
+		reconcileAndCheck(metav1.ConditionTrue, ReconcileSucceededReason, "Reconciled successfully")
+	})
+
+	It("should ignore objects that don't exist anymore", func() {
+		Expect(c.Delete(ctx, widget)).To(Succeed())
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should read the object from the API server if the cached one is stale", func() {
+		stale := newWidget()
+		Expect(c.Get(ctx, req.NamespacedName, stale)).To(Succeed())
+		r.client = fake.NewClientBuilder().WithObjects(widget).WithStatusSubresource(newWidget()).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, _ client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				stale.DeepCopyInto(obj.(*unstructured.Unstructured))
+				return nil
+			},
+			SubResourcePatch: func(ctx context.Context, _ client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				return c.Status().Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+		// Make the cached object stale.
+		updated := stale.DeepCopy()
+		updated.SetLabels(map[string]string{"updated": "true"})
+		Expect(c.Update(ctx, updated)).To(Succeed())
+
+		reconcileAndCheck(metav1.ConditionTrue, ReconcileSucceededReason, "Reconciled successfully")
+	})
+
+	It("should keep the result of the reconcile if the status can't be patched", func() {
+		r.client = fake.NewClientBuilder().WithObjects(widget).WithStatusSubresource(newWidget()).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				return errors.New("patch failed")
+			},
+		}).Build()
+
+		result = reconcile.Result{RequeueAfter: time.Minute}
+		res, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(result))
+
+		result = reconcile.Result{}
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).To(MatchError(ContainSubstring("patch failed")))
+	})
+
+	It("should report the time of the last reconcile if configured", func() {
+		r.config.ReportReconcileTime = true
+
+		before := time.Now().UTC().Truncate(time.Second)
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		condition, _ := currentCondition()
+		message := condition["message"].(string)
+		Expect(message).To(MatchRegexp(`^Reconciled successfully \(reconcileID .*, reconciled at (.+)\)$`))
+		reconciledAt, err := time.Parse(time.RFC3339, message[strings.LastIndex(message, " ")+1:len(message)-1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciledAt).To(BeTemporally(">=", before))
+	})
+
+	It("should truncate long errors on a character boundary", func() {
+		message := strings.Repeat("a", maxReconcileErrorLength-1) + "é"
+		truncated := truncateReconcileError(message)
+		Expect(utf8.ValidString(truncated)).To(BeTrue())
+		Expect(truncated).To(Equal(strings.Repeat("a", maxReconcileErrorLength-1) + "..."))
+		Expect(truncateReconcileError("short")).To(Equal("short"))
+	})
+})
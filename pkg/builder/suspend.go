@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// SuspendedReason is the reason of the condition set on suspended
+	// objects.
+	SuspendedReason = "Suspended"
+
+	// ResumedReason is the reason of the condition set on objects whose
+	// reconciliation has been resumed.
+	ResumedReason = "Resumed"
+)
+
+// SuspendConfig configures how Suspendable recognizes suspended objects.
+type SuspendConfig struct {
+	// FieldPath is the path of a boolean field that suspends the
+	// reconciliation of an object when true. Defaults to spec.suspend.
+	FieldPath []string
+
+	// Annotation, if set, is an annotation that also suspends the
+	// reconciliation of an object when set to "true".
+	Annotation string
+
+	// ConditionType, if set, is the type of a condition set in the
+	// status.conditions of suspended objects: True while they are suspended,
+	// and False once resumed. Objects must have a status subresource, and
+	// conditions of type metav1.Condition.
+	ConditionType string
+}
+
+// Suspendable makes the controller skip the reconciliation of suspended
+// objects of the For type, following the convention of a spec.suspend
+// field. The objects are read from the cache before every reconcile, and
+// the number of suspended objects is exported in the
+// controller_runtime_suspended_objects metric. Reconciles of objects that
+// don't exist anymore are not skipped, so that their deletion is handled.
+//
+// Objects watched with OnlyMetadata can only be suspended with an
+// annotation. Suspendable is only supported by controllers of
+// reconcile.Request.
+func Suspendable(config SuspendConfig) ForOption {
+	if len(config.FieldPath) == 0 {
+		config.FieldPath = []string{"spec", "suspend"}
+	}
+	return &suspendable{config: config}
+}
+
+type suspendable struct {
+	config SuspendConfig
+}
+
+// ApplyToFor applies this configuration to the given ForInput options.
+func (s *suspendable) ApplyToFor(opts *ForInput) {
+	opts.suspend = &s.config
+}
+
+// suspendReconciler skips the reconciliation of suspended objects.
+type suspendReconciler struct {
+	reconcile.Reconciler
+
+	client     client.Client
+	object     client.Object
+	config     SuspendConfig
+	controller string
+
+	mu        sync.Mutex
+	suspended sets.Set[reconcile.Request]
+}
+
+func newSuspendReconciler(r reconcile.Reconciler, c client.Client, object client.Object, config SuspendConfig, controller string) *suspendReconciler {
+	return &suspendReconciler{
+		Reconciler: r,
+		client:     c,
+		object:     object,
+		config:     config,
+		controller: controller,
+		suspended:  sets.New[reconcile.Request](),
+	}
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *suspendReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := r.object.DeepCopyObject().(client.Object)
+	if err := r.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		r.track(req, false)
+		return r.Reconciler.Reconcile(ctx, req)
+	}
+
+	suspended, err := r.isSuspended(obj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	r.track(req, suspended)
+	if err := r.setCondition(ctx, obj, suspended); err != nil {
+		return reconcile.Result{}, err
+	}
+	if suspended {
+		logf.FromContext(ctx).V(1).Info("Skipping reconcile of suspended object")
+		return reconcile.Result{}, nil
+	}
+	return r.Reconciler.Reconcile(ctx, req)
+}
+
+func (r *suspendReconciler) isSuspended(obj client.Object) (bool, error) {
+	if r.config.Annotation != "" && obj.GetAnnotations()[r.config.Annotation] == "true" {
+		return true, nil
+	}
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return false, err
+	}
+	suspended, _, err := unstructured.NestedBool(content, r.config.FieldPath...)
+	if err != nil {
+		return false, fmt.Errorf("failed to read suspend field: %w", err)
+	}
+	return suspended, nil
+}
+
+func (r *suspendReconciler) track(req reconcile.Request, suspended bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if suspended {
+		r.suspended.Insert(req)
+	} else {
+		r.suspended.Delete(req)
+	}
+	ctrlmetrics.SuspendedObjects.WithLabelValues(r.controller).Set(float64(r.suspended.Len()))
+}
+
+// setCondition sets the suspend condition of obj, if configured. The
+// condition is only set on resumed objects that were suspended before.
+func (r *suspendReconciler) setCondition(ctx context.Context, obj client.Object, suspended bool) error {
+	if r.config.ConditionType == "" {
+		return nil
+	}
+	condition := metav1.Condition{
+		Type:               r.config.ConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             SuspendedReason,
+		Message:            "Reconciliation is suspended",
+		ObservedGeneration: obj.GetGeneration(),
+	}
+	if !suspended {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ResumedReason
+		condition.Message = "Reconciliation is resumed"
+	}
+	return patchStatusCondition(ctx, r.client, obj, condition, func(current *metav1.Condition) bool {
+		return current == nil && !suspended
+	})
+}
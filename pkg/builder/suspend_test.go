@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("suspendReconciler", func() {
+	It("should skip the reconciles of suspended objects and report them", func() {
+		gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+		newWidget := func() *unstructured.Unstructured {
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(gvk)
+			return u
+		}
+		widget := newWidget()
+		widget.SetNamespace("default")
+		widget.SetName("widget")
+
+		c := fake.NewClientBuilder().WithObjects(widget).WithStatusSubresource(newWidget()).Build()
+		reconciled := 0
+		r := newSuspendReconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			reconciled++
+			return reconcile.Result{}, nil
+		}), c, newWidget(), SuspendConfig{
+			FieldPath:     []string{"spec", "suspend"},
+			Annotation:    "example.com/suspend",
+			ConditionType: "Suspended",
+		}, "suspend-test")
+
+		ctx := context.Background()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "widget"}}
+		reconcileAndCheck := func(expectedReconciles int, expectedSuspended float64) {
+			GinkgoHelper()
+			_, err := r.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciled).To(Equal(expectedReconciles))
+			Expect(testutil.ToFloat64(ctrlmetrics.SuspendedObjects.WithLabelValues("suspend-test"))).To(Equal(expectedSuspended))
+		}
+		condition := func() *metav1.Condition {
+			GinkgoHelper()
+			obj := newWidget()
+			Expect(c.Get(ctx, req.NamespacedName, obj)).To(Succeed())
+			raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+			conditions := make([]metav1.Condition, 0, len(raw))
+			for _, r := range raw {
+				m := r.(map[string]interface{})
+				conditions = append(conditions, metav1.Condition{
+					Type:   m["type"].(string),
+					Status: metav1.ConditionStatus(m["status"].(string)),
+					Reason: m["reason"].(string),
+				})
+			}
+			return meta.FindStatusCondition(conditions, "Suspended")
+		}
+		update := func(mutate func(*unstructured.Unstructured)) {
+			GinkgoHelper()
+			obj := newWidget()
+			Expect(c.Get(ctx, req.NamespacedName, obj)).To(Succeed())
+			mutate(obj)
+			Expect(c.Update(ctx, obj)).To(Succeed())
+		}
+
+		reconcileAndCheck(1, 0)
+		Expect(condition()).To(BeNil(), "objects that were never suspended must have no condition")
+
+		By("suspending the object with its field")
+		update(func(u *unstructured.Unstructured) {
+			_ = unstructured.SetNestedField(u.Object, true, "spec", "suspend")
+		})
+		reconcileAndCheck(1, 1)
+		cond := condition()
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(SuspendedReason))
+
+		By("resuming the object")
+		update(func(u *unstructured.Unstructured) {
+			_ = unstructured.SetNestedField(u.Object, false, "spec", "suspend")
+		})
+		reconcileAndCheck(2, 0)
+		cond = condition()
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(ResumedReason))
+
+		By("suspending the object with its annotation")
+		update(func(u *unstructured.Unstructured) {
+			u.SetAnnotations(map[string]string{"example.com/suspend": "true"})
+		})
+		reconcileAndCheck(2, 1)
+
+		By("reconciling deleted objects")
+		Expect(c.Delete(ctx, widget)).To(Succeed())
+		reconcileAndCheck(3, 0)
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// warmUpList returns a function listing the requests of the For objects in
+// the cache, for the warm-up pass of the controller. Objects filtered out by
+// the predicates of the controller, which see them as Create events of the
+// initial list, are skipped.
+func (blder *TypedBuilder[request]) warmUpList(gvk schema.GroupVersionKind) (func(context.Context) ([]request, error), error) {
+	obj, err := blder.project(blder.forInput.object, blder.forInput.objectProjection)
+	if err != nil {
+		return nil, err
+	}
+	newList, err := blder.newListFor(obj, gvk)
+	if err != nil {
+		return nil, err
+	}
+	predicates := append(append([]predicate.Predicate(nil), blder.globalPredicates...), blder.forInput.predicates...)
+
+	list := func(ctx context.Context) ([]reconcile.Request, error) {
+		objs := newList()
+		if err := blder.mgr.GetCache().List(ctx, objs); err != nil {
+			return nil, err
+		}
+		var requests []reconcile.Request
+		err := meta.EachListItem(objs, func(item runtime.Object) error {
+			obj, ok := item.(client.Object)
+			if !ok {
+				return fmt.Errorf("item of type %T is not an object", item)
+			}
+			evt := event.CreateEvent{Object: obj, IsInInitialList: true}
+			for _, p := range predicates {
+				if !p.Create(evt) {
+					return nil
+				}
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+			}})
+			return nil
+		})
+		return requests, err
+	}
+	typed, ok := any(list).(func(context.Context) ([]request, error))
+	if !ok {
+		return nil, errors.New("the warm-up pass can only list the For objects of controllers reconciling reconcile.Request")
+	}
+	return typed, nil
+}
+
+// newListFor returns a function creating lists of the kind of obj.
+func (blder *TypedBuilder[request]) newListFor(obj client.Object, gvk schema.GroupVersionKind) (func() client.ObjectList, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	switch obj.(type) {
+	case *metav1.PartialObjectMetadata:
+		return func() client.ObjectList {
+			l := &metav1.PartialObjectMetadataList{}
+			l.SetGroupVersionKind(listGVK)
+			return l
+		}, nil
+	case *unstructured.Unstructured:
+		return func() client.ObjectList {
+			l := &unstructured.UnstructuredList{}
+			l.SetGroupVersionKind(listGVK)
+			return l
+		}, nil
+	}
+	if _, err := blder.mgr.GetScheme().New(listGVK); err != nil {
+		return nil, fmt.Errorf("unable to create the list of %s for the warm-up pass: %w", gvk, err)
+	}
+	return func() client.ObjectList {
+		l, _ := blder.mgr.GetScheme().New(listGVK)
+		return l.(client.ObjectList)
+	}, nil
+}
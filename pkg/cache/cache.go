@@ -18,12 +18,14 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
 	"time"
 
 	"golang.org/x/exp/maps"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/client-go/rest"
 	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
@@ -42,7 +45,8 @@ import (
 )
 
 var (
-	defaultSyncPeriod = 10 * time.Hour
+	defaultSyncPeriod         = 10 * time.Hour
+	defaultAccessPollInterval = time.Minute
 )
 
 // InformerGetOptions defines the behavior of how informers are retrieved.
@@ -59,6 +63,18 @@ func BlockUntilSynced(shouldBlock bool) InformerGetOption {
 	}
 }
 
+// WithInformerPageSize sets the number of objects the informer requests per
+// page when it lists the objects, to limit the load a big initial list puts
+// on the API server. It only applies when the informer is created: an
+// informer that already exists keeps listing the way it was configured.
+// The API server may ignore the limit when it serves the list from its
+// watch cache.
+func WithInformerPageSize(pageSize int64) InformerGetOption {
+	return func(opts *InformerGetOptions) {
+		opts.PageSize = pageSize
+	}
+}
+
 // Cache knows how to load Kubernetes objects, fetch informers to request
 // to receive events for Kubernetes objects (at a low-level),
 // and add indices to fields on the objects stored in the cache.
@@ -189,6 +205,8 @@ type Options struct {
 	//
 	// The options in the Config that are nil will be defaulted from
 	// the respective Default* settings.
+	//
+	// Namespaces can be added and removed at runtime, see DynamicNamespaces.
 	DefaultNamespaces map[string]Config
 
 	// DefaultLabelSelector will be used as a label selector for all objects
@@ -226,6 +244,82 @@ type Options struct {
 	// If unset, this will fall through to the Default* settings.
 	ByObject map[client.Object]ByObject
 
+	// AccessCheck, if set, makes the cache review with SelfSubjectAccessReviews
+	// whether it is allowed to list and watch objects before starting an
+	// informer for them, and decides what to do when it isn't. This prevents
+	// informers from failing to sync, and thus controllers from failing to
+	// start, when optional resources are not permitted by RBAC.
+	//
+	// Access is reviewed once per informer, in the namespace it is restricted
+	// to, so permissions granted after an informer was started are not taken
+	// into account.
+	AccessCheck *AccessCheck
+
+	// EvictDeletedNamespaces makes the cache watch namespaces and, when one
+	// starts terminating, promptly evict the objects of that namespace that
+	// are cached, rather than waiting for their delete events. The
+	// evictions are delivered as delete events to the event handlers of the
+	// informers. Objects that still have finalizers are added back when
+	// their deletion starts, so that their controllers can finalize them.
+	// Once the namespace is deleted, the controllers of a manager using the
+	// cache drop the pending requests for its objects instead of
+	// reconciling them, which avoids waves of reconciles of objects that
+	// are not found after the teardown of large namespaces.
+	//
+	// This requires permission to list and watch namespaces.
+	EvictDeletedNamespaces bool
+
+	// NamespaceSelector makes the cache watch the Namespaces matching it,
+	// and only cache the objects of those namespaces, including and
+	// excluding namespaces as they start and stop matching. This implements
+	// the pattern of operators that only manage the namespaces that opt in,
+	// typically with a label. The namespaces in DefaultNamespaces are always
+	// cached.
+	//
+	// The informers of the cache emit Create events for the objects of the
+	// namespaces that are included, which requeues them. No events are
+	// emitted for the objects of the namespaces that are excluded; the cache
+	// implements NamespaceEvents to be notified of those.
+	//
+	// It can't be combined with a DefaultNamespaces entry for all
+	// namespaces. Unless DefaultNamespaces is set, the namespaced kinds
+	// configured in ByObject must set their Namespaces. This requires
+	// permission to list and watch namespaces.
+	NamespaceSelector labels.Selector
+
+	// WatchAsMetadata lists typed objects whose kinds are watched as
+	// metadata only, which saves the memory, and the bandwidth, of caching
+	// objects whose content is not used. Reads of those kinds, and the events
+	// of their informers, transparently return typed objects holding only
+	// their type and object metadata, so that controllers can opt in without
+	// being restructured around metav1.PartialObjectMetadata. The
+	// transforms of those kinds receive metav1.PartialObjectMetadata objects.
+	WatchAsMetadata []client.Object
+
+	// SerializedStore makes the informers of typed objects keep the objects
+	// they cache serialized, with protobuf for the types that support it and
+	// JSON for the others, and decode them whenever they are read, handed to
+	// event handlers or indexed. This trades CPU for a large reduction of the
+	// steady-state memory of caches holding many objects. Unstructured and
+	// metadata objects are not affected.
+	//
+	// The transforms of those kinds run before objects are serialized. The
+	// stores of the informers returned by GetInformer hold objects of an
+	// internal type, and must not be accessed directly.
+	//
+	// This is experimental.
+	SerializedStore *SerializedStoreOptions
+
+	// SizeGuardrails reports the objects committed to the cache whose
+	// serialized size exceeds MaxObjectBytes, after their transform ran,
+	// like client.WithSizeGuardrails does for a client. Violations are
+	// recorded with the "cache" verb. MaxListBytes is ignored, as the cache
+	// doesn't see list responses as a whole.
+	SizeGuardrails *client.SizeGuardrails
+
+	// accessReview allows overriding the review of access for testing.
+	accessReview internal.AccessReviewFunc
+
 	// newInformer allows overriding of NewSharedIndexInformer for testing.
 	newInformer *func(toolscache.ListerWatcher, runtime.Object, time.Duration, toolscache.Indexers) toolscache.SharedIndexInformer
 }
@@ -274,6 +368,13 @@ type ByObject struct {
 	UnsafeDisableDeepCopy *bool
 }
 
+// SerializedStoreOptions configures the serialized store of a cache.
+type SerializedStoreOptions struct {
+	// Compress compresses the serialized objects, which further reduces
+	// memory at the cost of more CPU.
+	Compress bool
+}
+
 // Config describes all potential options for a given watch.
 type Config struct {
 	// LabelSelector specifies a label selector. A nil value allows to
@@ -300,6 +401,37 @@ type Config struct {
 	UnsafeDisableDeepCopy *bool
 }
 
+// AccessDeniedPolicy decides what the cache does when it is not allowed to
+// list and watch a kind of object.
+type AccessDeniedPolicy string
+
+const (
+	// AccessDeniedFail makes getting an informer for the objects fail with a
+	// Forbidden error. Controllers watching them fail to start.
+	AccessDeniedFail AccessDeniedPolicy = "Fail"
+
+	// AccessDeniedSkip logs a warning and starts an informer that is synced
+	// but never contains any object.
+	AccessDeniedSkip AccessDeniedPolicy = "Skip"
+
+	// AccessDeniedPoll logs a warning and, if the cache is allowed to list
+	// the objects but not to watch them, starts an informer that lists them
+	// periodically. Otherwise, it behaves like AccessDeniedSkip.
+	AccessDeniedPoll AccessDeniedPolicy = "Poll"
+)
+
+// AccessCheck configures the review of access performed before starting an
+// informer.
+type AccessCheck struct {
+	// OnDenied decides what to do when access is denied. Defaults to
+	// AccessDeniedFail.
+	OnDenied AccessDeniedPolicy
+
+	// PollInterval is the interval at which objects are listed when
+	// OnDenied is AccessDeniedPoll. Defaults to one minute.
+	PollInterval time.Duration
+}
+
 // NewCacheFunc - Function for creating a new cache from the options and a rest config.
 type NewCacheFunc func(config *rest.Config, opts Options) (Cache, error)
 
@@ -310,10 +442,14 @@ func New(cfg *rest.Config, opts Options) (Cache, error) {
 		return nil, err
 	}
 
-	newCacheFunc := newCache(cfg, opts)
+	metadataOnly, err := metadataOnlyKinds(opts.WatchAsMetadata, opts.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	newCacheFunc := newCache(cfg, opts, metadataOnly)
 
 	var defaultCache Cache
-	if len(opts.DefaultNamespaces) > 0 {
+	if len(opts.DefaultNamespaces) > 0 || opts.NamespaceSelector != nil {
 		defaultConfig := optionDefaultsToConfig(&opts)
 		defaultCache = newMultiNamespaceCache(newCacheFunc, opts.Scheme, opts.Mapper, opts.DefaultNamespaces, &defaultConfig)
 	} else {
@@ -321,7 +457,7 @@ func New(cfg *rest.Config, opts Options) (Cache, error) {
 	}
 
 	if len(opts.ByObject) == 0 {
-		return defaultCache, nil
+		return wrapCache(defaultCache, newCacheFunc, opts)
 	}
 
 	delegating := &delegatingByGVKCache{
@@ -344,7 +480,19 @@ func New(cfg *rest.Config, opts Options) (Cache, error) {
 		delegating.caches[gvk] = cache
 	}
 
-	return delegating, nil
+	return wrapCache(delegating, newCacheFunc, opts)
+}
+
+// wrapCache wraps the cache created by New with the caches implementing
+// EvictDeletedNamespaces and NamespaceSelector.
+func wrapCache(c Cache, newCache newCacheFunc, opts Options) (Cache, error) {
+	if opts.EvictDeletedNamespaces {
+		c = newNamespaceEvictingCache(c)
+	}
+	if opts.NamespaceSelector != nil {
+		return newNamespaceDiscoveringCache(c, newCache, opts.NamespaceSelector)
+	}
+	return c, nil
 }
 
 // TransformStripManagedFields strips the managed fields of an object before it is committed to the cache.
@@ -381,26 +529,46 @@ func byObjectToConfig(byObject ByObject) Config {
 
 type newCacheFunc func(config Config, namespace string) Cache
 
-func newCache(restConfig *rest.Config, opts Options) newCacheFunc {
+func newCache(restConfig *rest.Config, opts Options, metadataOnly map[schema.GroupVersionKind]struct{}) newCacheFunc {
 	return func(config Config, namespace string) Cache {
+		informersOpts := internal.InformersOpts{
+			HTTPClient:   opts.HTTPClient,
+			Scheme:       opts.Scheme,
+			Mapper:       opts.Mapper,
+			ResyncPeriod: *opts.SyncPeriod,
+			Namespace:    namespace,
+			Selector: internal.Selector{
+				Label: config.LabelSelector,
+				Field: config.FieldSelector,
+			},
+			Transform:             config.Transform,
+			WatchErrorHandler:     opts.DefaultWatchErrorHandler,
+			UnsafeDisableDeepCopy: ptr.Deref(config.UnsafeDisableDeepCopy, false),
+			NewInformer:           opts.newInformer,
+			AccessCheck:           accessCheckFor(opts),
+		}
+		if opts.SizeGuardrails != nil {
+			informersOpts.Transform = client.SizeGuardrailsTransform(*opts.SizeGuardrails, opts.Scheme, config.Transform)
+		}
+		if opts.SerializedStore != nil {
+			informersOpts.SerializedStore = &internal.SerializedStore{Compress: opts.SerializedStore.Compress}
+		}
 		return &informerCache{
-			scheme: opts.Scheme,
-			Informers: internal.NewInformers(restConfig, &internal.InformersOpts{
-				HTTPClient:   opts.HTTPClient,
-				Scheme:       opts.Scheme,
-				Mapper:       opts.Mapper,
-				ResyncPeriod: *opts.SyncPeriod,
-				Namespace:    namespace,
-				Selector: internal.Selector{
-					Label: config.LabelSelector,
-					Field: config.FieldSelector,
-				},
-				Transform:             config.Transform,
-				WatchErrorHandler:     opts.DefaultWatchErrorHandler,
-				UnsafeDisableDeepCopy: ptr.Deref(config.UnsafeDisableDeepCopy, false),
-				NewInformer:           opts.newInformer,
-			}),
+			scheme:                      opts.Scheme,
+			Informers:                   internal.NewInformers(restConfig, &informersOpts),
 			readerFailOnMissingInformer: opts.ReaderFailOnMissingInformer,
+			metadataOnly:                metadataOnly,
+			variants: &informerVariants{
+				namespace: namespace,
+				selector:  informersOpts.Selector,
+				mapper:    opts.Mapper,
+				newInformers: func(namespace string, selector internal.Selector) *internal.Informers {
+					variantOpts := informersOpts
+					variantOpts.Namespace = namespace
+					variantOpts.Selector = selector
+					return internal.NewInformers(restConfig, &variantOpts)
+				},
+			},
 		}
 	}
 }
@@ -444,6 +612,9 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 		}
 
 		if isNamespaced && byObject.Namespaces == nil {
+			if opts.NamespaceSelector != nil && len(opts.DefaultNamespaces) == 0 {
+				return opts, fmt.Errorf("type %T is namespaced, but its ByObject.Namespaces setting is nil while NamespaceSelector is set", obj)
+			}
 			byObject.Namespaces = maps.Clone(opts.DefaultNamespaces)
 		}
 
@@ -487,6 +658,10 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 		opts.ByObject[obj] = byObject
 	}
 
+	if _, ok := opts.DefaultNamespaces[metav1.NamespaceAll]; ok && opts.NamespaceSelector != nil {
+		return opts, errors.New("NamespaceSelector can't be combined with a DefaultNamespaces entry for all namespaces")
+	}
+
 	// Default namespaces after byObject has been defaulted, otherwise a namespace without selectors
 	// will get the `Default` selectors, then get copied to byObject and then not get defaulted from
 	// byObject, as it already has selectors.
@@ -503,6 +678,33 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 		opts.DefaultNamespaces[namespace] = cfg
 	}
 
+	if opts.AccessCheck != nil {
+		accessCheck := *opts.AccessCheck
+		if accessCheck.OnDenied == "" {
+			accessCheck.OnDenied = AccessDeniedFail
+		}
+		if accessCheck.PollInterval <= 0 {
+			accessCheck.PollInterval = defaultAccessPollInterval
+		}
+		opts.AccessCheck = &accessCheck
+
+		if opts.accessReview == nil {
+			authorizationClient, err := authorizationv1client.NewForConfigAndClient(config, opts.HTTPClient)
+			if err != nil {
+				return Options{}, fmt.Errorf("could not create authorization client: %w", err)
+			}
+			opts.accessReview = func(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error) {
+				review, err := authorizationClient.SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+					Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+				}, metav1.CreateOptions{})
+				if err != nil {
+					return false, err
+				}
+				return review.Status.Allowed, nil
+			}
+		}
+	}
+
 	// Default the resync period to 10 hours if unset
 	if opts.SyncPeriod == nil {
 		opts.SyncPeriod = &defaultSyncPeriod
@@ -510,6 +712,24 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 	return opts, nil
 }
 
+func accessCheckFor(opts Options) *internal.AccessCheck {
+	if opts.AccessCheck == nil {
+		return nil
+	}
+	onDenied := internal.AccessDeniedFail
+	switch opts.AccessCheck.OnDenied {
+	case AccessDeniedSkip:
+		onDenied = internal.AccessDeniedSkip
+	case AccessDeniedPoll:
+		onDenied = internal.AccessDeniedPoll
+	}
+	return &internal.AccessCheck{
+		Review:       opts.accessReview,
+		OnDenied:     onDenied,
+		PollInterval: opts.AccessCheck.PollInterval,
+	}
+}
+
 func defaultConfig(toDefault, defaultFrom Config) Config {
 	if toDefault.LabelSelector == nil {
 		toDefault.LabelSelector = defaultFrom.LabelSelector
@@ -21,9 +21,11 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -442,6 +444,113 @@ var _ = Describe("Cache with selectors", func() {
 	})
 })
 
+var _ = Describe("Cache with restricted informers", func() {
+	var (
+		informerCache       cache.Cache
+		informerCacheCtx    context.Context
+		informerCacheCancel context.CancelFunc
+		pods                []client.Object
+	)
+
+	BeforeEach(func() {
+		informerCacheCtx, informerCacheCancel = context.WithCancel(context.Background())
+		Expect(cfg).NotTo(BeNil())
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ensureNamespace(testNamespaceOne, cl)).To(Succeed())
+		Expect(ensureNamespace(testNamespaceTwo, cl)).To(Succeed())
+		pods = []client.Object{
+			createPodWithLabels("restricted-pod-1", testNamespaceOne, corev1.RestartPolicyNever, map[string]string{"selected": "true"}),
+			createPodWithLabels("restricted-pod-2", testNamespaceTwo, corev1.RestartPolicyNever, map[string]string{"selected": "true"}),
+			createPodWithLabels("restricted-pod-3", testNamespaceTwo, corev1.RestartPolicyNever, nil),
+		}
+
+		informerCache, err = cache.New(cfg, cache.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		go func(ctx context.Context) {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(ctx)).To(Succeed())
+		}(informerCacheCtx)
+		Expect(informerCache.WaitForCacheSync(informerCacheCtx)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		for _, pod := range pods {
+			deletePod(pod)
+		}
+		informerCacheCancel()
+	})
+
+	It("should only deliver the events of the selected objects", func() {
+		selector := labels.SelectorFromSet(labels.Set{"selected": "true"})
+		informer, err := informerCache.GetInformer(informerCacheCtx, &corev1.Pod{}, cache.WithInformerSelector(selector, nil, testNamespaceTwo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informer.HasSynced()).To(BeTrue())
+
+		var mu sync.Mutex
+		var names []string
+		_, err = informer.AddEventHandler(kcache.ResourceEventHandlerFuncs{AddFunc: func(obj interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			names = append(names, obj.(*corev1.Pod).Name)
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Consistently(func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return slices.Clone(names)
+		}).Should(ConsistOf("restricted-pod-2"))
+
+		By("sharing the informer between callers asking for the same restriction")
+		again, err := informerCache.GetInformer(informerCacheCtx, &corev1.Pod{}, cache.WithInformerSelector(selector, nil, testNamespaceTwo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(informer))
+
+		By("reading from the unrestricted informer")
+		var list corev1.PodList
+		Expect(informerCache.List(informerCacheCtx, &list, client.InNamespace(testNamespaceTwo))).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("Cache watching kinds as metadata", func() {
+	It("should serve typed objects holding only their metadata", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ensureNamespace(testNamespaceOne, cl)).To(Succeed())
+		pod := createPodWithLabels("metadata-only-pod", testNamespaceOne, corev1.RestartPolicyNever, map[string]string{"app": "metadata"})
+		defer deletePod(pod)
+
+		informerCache, err := cache.New(cfg, cache.Options{WatchAsMetadata: []client.Object{&corev1.Pod{}}})
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(ctx)).To(Succeed())
+		}()
+		Expect(informerCache.WaitForCacheSync(ctx)).To(BeTrue())
+
+		By("getting the typed object")
+		var got corev1.Pod
+		Expect(informerCache.Get(ctx, client.ObjectKeyFromObject(pod), &got)).To(Succeed())
+		Expect(got.Labels).To(Equal(map[string]string{"app": "metadata"}))
+		Expect(got.Spec.Containers).To(BeEmpty())
+
+		By("listing the typed objects")
+		var list corev1.PodList
+		Expect(informerCache.List(ctx, &list, client.InNamespace(testNamespaceOne), client.MatchingLabels{"app": "metadata"})).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Spec.Containers).To(BeEmpty())
+
+		By("sharing the informer with metadata reads")
+		var metadata metav1.PartialObjectMetadata
+		metadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+		Expect(informerCache.Get(ctx, client.ObjectKeyFromObject(pod), &metadata)).To(Succeed())
+		Expect(metadata.Labels).To(Equal(map[string]string{"app": "metadata"}))
+	})
+})
+
 func CacheTestReaderFailOnMissingInformer(createCacheFunc func(config *rest.Config, opts cache.Options) (cache.Cache, error), opts cache.Options) {
 	Describe("Cache test with ReaderFailOnMissingInformer = true", func() {
 		var (
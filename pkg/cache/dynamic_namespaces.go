@@ -0,0 +1,327 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/exp/maps"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// DynamicNamespaces is implemented by caches restricted to DefaultNamespaces,
+// whose set of namespaces can be changed at runtime, e.g. for operators
+// whose scope is driven by a label on Namespace objects.
+//
+// The informers returned by the cache, along with their event handlers and
+// indexers, and the fields indexed with IndexField, are extended to the
+// namespaces that are added. Objects of a namespace that is removed are no
+// longer cached; no delete events are emitted for them.
+//
+// Namespaces can't be added to caches that also cache all the other
+// namespaces with a metav1.NamespaceAll entry in DefaultNamespaces. Kinds
+// of namespaced objects configured in ByObject keep the namespaces they were
+// created with.
+type DynamicNamespaces interface {
+	// AddNamespace adds a namespace to the cache, using the default
+	// selectors and transform of the cache. If the cache has been started,
+	// it waits for the informers of the namespace to sync. Adding a
+	// namespace that is already cached is a no-op.
+	AddNamespace(ctx context.Context, namespace string) error
+
+	// RemoveNamespace removes a namespace from the cache and stops its
+	// informers. Removing a namespace that is not cached is a no-op.
+	RemoveNamespace(namespace string) error
+}
+
+var _ DynamicNamespaces = &multiNamespaceCache{}
+
+// informerKey identifies the informers of a kind, which are different for
+// typed, unstructured and metadata objects.
+type informerKey struct {
+	gvk     schema.GroupVersionKind
+	objType reflect.Type
+}
+
+func informerKeyFor(obj runtime.Object, scheme *runtime.Scheme) (informerKey, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return informerKey{}, err
+	}
+	return informerKey{gvk: gvk, objType: reflect.TypeOf(obj)}, nil
+}
+
+// informerGetter gets the informer of a kind from the cache of a namespace.
+type informerGetter func(ctx context.Context, cache Cache, opts ...InformerGetOption) (Informer, error)
+
+// fieldIndex is a field indexed with IndexField.
+type fieldIndex struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+type trackedInformer struct {
+	*multiNamespaceInformer
+	get informerGetter
+}
+
+// trackInformer returns the tracked informer of key, tracking the informers
+// of namespaceToInformer if there is none yet.
+func (c *multiNamespaceCache) trackInformer(ctx context.Context, key informerKey, get informerGetter, namespaceToInformer map[string]Informer) (Informer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tracked, ok := c.informers[key]; ok {
+		return tracked.multiNamespaceInformer, nil
+	}
+
+	// Namespaces may have been added or removed since the informers were
+	// got.
+	for ns, cache := range c.namespaceToCache {
+		if _, ok := namespaceToInformer[ns]; ok {
+			continue
+		}
+		informer, err := get(ctx, cache, BlockUntilSynced(false))
+		if err != nil {
+			return nil, err
+		}
+		namespaceToInformer[ns] = informer
+	}
+	for ns := range namespaceToInformer {
+		if _, ok := c.namespaceToCache[ns]; !ok {
+			delete(namespaceToInformer, ns)
+		}
+	}
+
+	if c.informers == nil {
+		c.informers = map[informerKey]trackedInformer{}
+	}
+	informer := &multiNamespaceInformer{namespaceToInformer: namespaceToInformer}
+	c.informers[key] = trackedInformer{multiNamespaceInformer: informer, get: get}
+	return informer, nil
+}
+
+// untrackInformer stops tracking the informers of obj, once they have been
+// removed.
+func (c *multiNamespaceCache) untrackInformer(obj client.Object) {
+	key, err := informerKeyFor(obj, c.Scheme)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.informers, key)
+	switch obj.(type) {
+	case runtime.Unstructured, *metav1.PartialObjectMetadata:
+	default:
+		// GetInformerForKind gets typed informers.
+		delete(c.informers, informerKey{gvk: key.gvk})
+	}
+}
+
+// AddNamespace implements DynamicNamespaces.
+func (c *multiNamespaceCache) AddNamespace(ctx context.Context, namespace string) error {
+	if namespace == metav1.NamespaceAll {
+		return errors.New("can't add all namespaces to the cache")
+	}
+
+	c.mu.Lock()
+	if _, ok := c.namespaceToCache[namespace]; ok {
+		c.mu.Unlock()
+		return nil
+	}
+	if err := c.checkDynamicNamespaces(); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	cache := c.newCache(*c.namespaceConfig, namespace)
+	if err := c.extend(ctx, namespace, cache); err != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("failed to add namespace %s to the cache: %w", namespace, err)
+	}
+	caches := maps.Clone(c.namespaceToCache)
+	caches[namespace] = cache
+	c.namespaceToCache = caches
+
+	started := c.ctx != nil
+	if started {
+		nsCtx, cancel := context.WithCancel(c.ctx)
+		c.cancels[namespace] = cancel
+		go func() {
+			if err := cache.Start(nsCtx); err != nil {
+				log.Error(err, "Failed to start cache", "namespace", namespace)
+			}
+		}()
+	}
+	c.mu.Unlock()
+
+	if started && !cache.WaitForCacheSync(ctx) {
+		return fmt.Errorf("failed waiting for the cache of namespace %s to sync", namespace)
+	}
+	return nil
+}
+
+// checkDynamicNamespaces returns an error if namespaces can't be added to c.
+func (c *multiNamespaceCache) checkDynamicNamespaces() error {
+	if c.namespaceConfig == nil {
+		return errors.New("namespaces can't be added to caches of objects configured in ByObject")
+	}
+	if _, ok := c.namespaceToCache[metav1.NamespaceAll]; ok {
+		return errors.New("namespaces can't be added to caches that cache all the other namespaces")
+	}
+	return nil
+}
+
+// extend indexes the fields indexed with IndexField in the cache of a new
+// namespace, and extends the tracked informers to it. The cache has not
+// been started, so getting its informers doesn't block.
+func (c *multiNamespaceCache) extend(ctx context.Context, namespace string, cache Cache) error {
+	for _, index := range c.indexes {
+		if err := cache.IndexField(ctx, index.obj, index.field, index.extractValue); err != nil {
+			return err
+		}
+	}
+	for _, tracked := range c.informers {
+		informer, err := tracked.get(ctx, cache, BlockUntilSynced(false))
+		if err != nil {
+			return err
+		}
+		if err := tracked.addNamespace(namespace, informer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveNamespace implements DynamicNamespaces.
+func (c *multiNamespaceCache) RemoveNamespace(namespace string) error {
+	c.mu.Lock()
+	if _, ok := c.namespaceToCache[namespace]; !ok {
+		c.mu.Unlock()
+		return nil
+	}
+	var errs []error
+	for _, tracked := range c.informers {
+		errs = append(errs, tracked.removeNamespace(namespace))
+	}
+	caches := maps.Clone(c.namespaceToCache)
+	delete(caches, namespace)
+	c.namespaceToCache = caches
+	cancel := c.cancels[namespace]
+	delete(c.cancels, namespace)
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	return errors.Join(errs...)
+}
+
+// addNamespace adds the informer of a new namespace, with the event
+// handlers and indexers of i.
+func (i *multiNamespaceInformer) addNamespace(namespace string, informer Informer) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, indexers := range i.indexers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	for _, registration := range i.registrations {
+		if err := registration.addTo(namespace, informer); err != nil {
+			return err
+		}
+	}
+	i.namespaceToInformer[namespace] = informer
+	return nil
+}
+
+// removeNamespace removes the informer of a namespace, and the event handlers
+// of i from it.
+func (i *multiNamespaceInformer) removeNamespace(namespace string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	informer, ok := i.namespaceToInformer[namespace]
+	if !ok {
+		return nil
+	}
+	delete(i.namespaceToInformer, namespace)
+	var errs []error
+	for _, registration := range i.registrations {
+		registration.mu.Lock()
+		if handle, ok := registration.handles[namespace]; ok {
+			errs = append(errs, informer.RemoveEventHandler(handle))
+			delete(registration.handles, namespace)
+		}
+		registration.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// AddNamespace implements DynamicNamespaces by adding the namespace to the
+// default cache.
+func (dbt *delegatingByGVKCache) AddNamespace(ctx context.Context, namespace string) error {
+	dynamic, err := dynamicNamespacesOf(dbt.defaultCache)
+	if err != nil {
+		return err
+	}
+	return dynamic.AddNamespace(ctx, namespace)
+}
+
+// RemoveNamespace implements DynamicNamespaces by removing the namespace
+// from the default cache.
+func (dbt *delegatingByGVKCache) RemoveNamespace(namespace string) error {
+	dynamic, err := dynamicNamespacesOf(dbt.defaultCache)
+	if err != nil {
+		return err
+	}
+	return dynamic.RemoveNamespace(namespace)
+}
+
+// AddNamespace implements DynamicNamespaces.
+func (c *namespaceEvictingCache) AddNamespace(ctx context.Context, namespace string) error {
+	dynamic, err := dynamicNamespacesOf(c.Cache)
+	if err != nil {
+		return err
+	}
+	return dynamic.AddNamespace(ctx, namespace)
+}
+
+// RemoveNamespace implements DynamicNamespaces.
+func (c *namespaceEvictingCache) RemoveNamespace(namespace string) error {
+	dynamic, err := dynamicNamespacesOf(c.Cache)
+	if err != nil {
+		return err
+	}
+	return dynamic.RemoveNamespace(namespace)
+}
+
+func dynamicNamespacesOf(c Cache) (DynamicNamespaces, error) {
+	dynamic, ok := c.(DynamicNamespaces)
+	if !ok {
+		return nil, errors.New("namespaces can only be added to or removed from caches restricted to DefaultNamespaces")
+	}
+	return dynamic, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+// fakeNamespaceCache is the cache of a namespace, with a single informer.
+type fakeNamespaceCache struct {
+	Cache
+	informer controllertest.FakeInformer
+	indexed  []string
+	started  chan struct{}
+	stopped  chan struct{}
+	// synced, if set, is closed once the cache has synced.
+	synced chan struct{}
+}
+
+func (c *fakeNamespaceCache) GetInformer(context.Context, client.Object, ...InformerGetOption) (Informer, error) {
+	return &c.informer, nil
+}
+
+func (c *fakeNamespaceCache) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	c.indexed = append(c.indexed, field)
+	return nil
+}
+
+func (c *fakeNamespaceCache) Start(ctx context.Context) error {
+	close(c.started)
+	<-ctx.Done()
+	close(c.stopped)
+	return nil
+}
+
+func (c *fakeNamespaceCache) WaitForCacheSync(ctx context.Context) bool {
+	if c.synced == nil {
+		return true
+	}
+	select {
+	case <-c.synced:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+var _ = Describe("dynamic namespaces", func() {
+	var (
+		mu     sync.Mutex
+		caches map[string]*fakeNamespaceCache
+		c      *multiNamespaceCache
+	)
+
+	BeforeEach(func() {
+		caches = map[string]*fakeNamespaceCache{}
+		newCache := func(_ Config, namespace string) Cache {
+			mu.Lock()
+			defer mu.Unlock()
+			caches[namespace] = &fakeNamespaceCache{started: make(chan struct{}), stopped: make(chan struct{})}
+			return caches[namespace]
+		}
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+		c = newMultiNamespaceCache(newCache, scheme.Scheme, mapper, map[string]Config{"one": {}}, &Config{}).(*multiNamespaceCache)
+		c.clusterCache = nil
+	})
+
+	It("should extend informers and indexes to the namespaces added", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Eventually(caches["one"].started).Should(BeClosed())
+
+		Expect(c.IndexField(ctx, &corev1.Pod{}, "spec.nodeName", func(client.Object) []string { return nil })).To(Succeed())
+		informer, err := c.GetInformer(ctx, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		var added []string
+		_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { added = append(added, obj.(*corev1.Pod).Namespace) },
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.AddNamespace(ctx, "two")).To(Succeed())
+		Expect(c.AddNamespace(ctx, "two")).To(Succeed())
+		Expect(caches["two"].indexed).To(Equal([]string{"spec.nodeName"}))
+		caches["one"].informer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "one"}})
+		caches["two"].informer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "two"}})
+		Expect(added).To(Equal([]string{"one", "two"}))
+
+		again, err := c.GetInformer(ctx, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(informer))
+		Expect(informer.(*multiNamespaceInformer).namespaceToInformer).To(HaveLen(2))
+
+		Expect(c.RemoveNamespace("two")).To(Succeed())
+		Eventually(caches["two"].stopped).Should(BeClosed())
+		Expect(informer.(*multiNamespaceInformer).namespaceToInformer).To(HaveLen(1))
+		err = c.Get(ctx, client.ObjectKey{Namespace: "two", Name: "pod"}, &corev1.Pod{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not add namespaces to caches of all the other namespaces", func() {
+		c.namespaceToCache[metav1.NamespaceAll] = &fakeNamespaceCache{}
+		Expect(c.AddNamespace(context.Background(), "two")).NotTo(Succeed())
+	})
+
+	It("should not add all namespaces", func() {
+		Expect(c.AddNamespace(context.Background(), metav1.NamespaceAll)).NotTo(Succeed())
+	})
+})
@@ -68,6 +68,13 @@ type informerCache struct {
 	scheme *runtime.Scheme
 	*internal.Informers
 	readerFailOnMissingInformer bool
+
+	// variants holds the informers restricted by WithInformerSelector.
+	variants *informerVariants
+
+	// metadataOnly holds the kinds whose typed objects are served from
+	// metadata informers.
+	metadataOnly map[schema.GroupVersionKind]struct{}
 }
 
 // Get implements Reader.
@@ -77,6 +84,13 @@ func (ic *informerCache) Get(ctx context.Context, key client.ObjectKey, out clie
 		return err
 	}
 
+	if pom, ok := ic.asMetadata(gvk, out); ok {
+		if err := ic.Get(ctx, key, pom, opts...); err != nil {
+			return err
+		}
+		return metadataInto(pom, gvk, out)
+	}
+
 	started, cache, err := ic.getInformerForKind(ctx, gvk, out)
 	if err != nil {
 		return err
@@ -95,6 +109,15 @@ func (ic *informerCache) List(ctx context.Context, out client.ObjectList, opts .
 		return err
 	}
 
+	if _, ok := ic.asMetadata(*gvk, cacheTypeObj); ok {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := ic.List(ctx, list, opts...); err != nil {
+			return err
+		}
+		return ic.listFromMetadata(list, *gvk, out)
+	}
+
 	started, cache, err := ic.getInformerForKind(ctx, *gvk, cacheTypeObj)
 	if err != nil {
 		return err
@@ -157,11 +180,7 @@ func (ic *informerCache) GetInformerForKind(ctx context.Context, gvk schema.Grou
 		return nil, err
 	}
 
-	_, i, err := ic.Informers.Get(ctx, gvk, obj, applyGetOptions(opts...))
-	if err != nil {
-		return nil, err
-	}
-	return i.Informer, nil
+	return ic.getInformer(ctx, gvk, obj, opts...)
 }
 
 // GetInformer returns the informer for the obj. If no informer exists, one will be started.
@@ -171,7 +190,23 @@ func (ic *informerCache) GetInformer(ctx context.Context, obj client.Object, opt
 		return nil, err
 	}
 
-	_, i, err := ic.Informers.Get(ctx, gvk, obj, applyGetOptions(opts...))
+	return ic.getInformer(ctx, gvk, obj, opts...)
+}
+
+func (ic *informerCache) getInformer(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object, opts ...InformerGetOption) (Informer, error) {
+	if pom, ok := ic.asMetadata(gvk, obj); ok {
+		informer, err := ic.getInformer(ctx, gvk, pom, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &metadataInformer{Informer: informer, gvk: gvk, cache: ic}, nil
+	}
+
+	getOpts := applyGetOptions(opts...)
+	if isRestricted(getOpts) {
+		return ic.variants.get(ctx, gvk, obj, getOpts)
+	}
+	_, i, err := ic.Informers.Get(ctx, gvk, obj, getOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -197,10 +232,25 @@ func (ic *informerCache) RemoveInformer(_ context.Context, obj client.Object) er
 		return err
 	}
 
+	if pom, ok := ic.asMetadata(gvk, obj); ok {
+		obj = pom
+	}
 	ic.Informers.Remove(gvk, obj)
 	return nil
 }
 
+// Start starts the informers of the cache, including the restricted ones.
+// It blocks until ctx is cancelled.
+func (ic *informerCache) Start(ctx context.Context) error {
+	ic.variants.start(ctx)
+	return ic.Informers.Start(ctx)
+}
+
+// WaitForCacheSync waits for all the informers of the cache to sync.
+func (ic *informerCache) WaitForCacheSync(ctx context.Context) bool {
+	return ic.Informers.WaitForCacheSync(ctx) && ic.variants.waitForCacheSync(ctx)
+}
+
 // NeedLeaderElection implements the LeaderElectionRunnable interface
 // to indicate that this can be started without requiring the leader lock.
 func (ic *informerCache) NeedLeaderElection() bool {
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
@@ -107,3 +108,64 @@ var _ = Describe("ip.objectTypeForListObject", func() {
 		})
 	})
 })
+
+var _ = Describe("informerCache watching kinds as metadata", func() {
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+	ic := &informerCache{
+		scheme:       scheme.Scheme,
+		Informers:    &internal.Informers{},
+		metadataOnly: map[schema.GroupVersionKind]struct{}{podGVK: {}},
+	}
+	metadataOf := func(name string) *metav1.PartialObjectMetadata {
+		pom := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{"app": "test"},
+		}}
+		pom.SetGroupVersionKind(podGVK)
+		return pom
+	}
+
+	It("should only stand in for typed objects of the kinds watched as metadata", func() {
+		_, ok := ic.asMetadata(podGVK, &corev1.Pod{})
+		Expect(ok).To(BeTrue())
+		_, ok = ic.asMetadata(podGVK, metadataOf("a"))
+		Expect(ok).To(BeFalse())
+		_, ok = ic.asMetadata(corev1.SchemeGroupVersion.WithKind("ConfigMap"), &corev1.ConfigMap{})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should set typed objects to the metadata", func() {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "stale"}}
+		Expect(metadataInto(metadataOf("a"), podGVK, pod)).To(Succeed())
+		Expect(pod.Name).To(Equal("a"))
+		Expect(pod.Labels).To(Equal(map[string]string{"app": "test"}))
+		Expect(pod.Kind).To(Equal("Pod"))
+		Expect(pod.Spec).To(Equal(corev1.PodSpec{}))
+	})
+
+	It("should set the items of typed lists to the metadata", func() {
+		list := &metav1.PartialObjectMetadataList{Items: []metav1.PartialObjectMetadata{*metadataOf("a"), *metadataOf("b")}}
+		list.SetResourceVersion("42")
+		pods := &corev1.PodList{}
+		Expect(ic.listFromMetadata(list, podGVK, pods)).To(Succeed())
+		Expect(pods.Items).To(HaveLen(2))
+		Expect(pods.Items[1].Name).To(Equal("b"))
+		Expect(pods.ResourceVersion).To(Equal("42"))
+	})
+
+	It("should deliver typed objects to event handlers", func() {
+		fake := &controllertest.FakeInformer{}
+		informer := &metadataInformer{Informer: fake, gvk: podGVK, cache: ic}
+
+		var added []interface{}
+		_, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { added = append(added, obj) },
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Add(metadataOf("a"))
+		Expect(added).To(HaveLen(1))
+		Expect(added[0]).To(BeAssignableToTypeOf(&corev1.Pod{}))
+		Expect(added[0].(*corev1.Pod).Name).To(Equal("a"))
+	})
+})
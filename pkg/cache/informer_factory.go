@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// InformerFactoryOptions are the options of a Cache created with
+// NewFromInformerFactory.
+type InformerFactoryOptions struct {
+	// Scheme is used to map objects to GroupVersionKinds. Defaults to the
+	// client-go scheme, which holds the types supported by informer
+	// factories.
+	Scheme *runtime.Scheme
+
+	// Mapper maps GroupVersionKinds to the resources of the factory. It is
+	// required.
+	Mapper meta.RESTMapper
+}
+
+// NewFromInformerFactory returns a Cache backed by the informers of a
+// client-go SharedInformerFactory, so that controllers written with
+// client-go and with controller-runtime can share informers in a single
+// process while migrating. Only the built-in types supported by the factory
+// can be read and watched; the namespace, resync period and list options of
+// the factory apply.
+//
+// The factory is started by the Start method of the cache, and may also be
+// started by its owner. Informers can't be removed from the cache.
+func NewFromInformerFactory(factory informers.SharedInformerFactory, opts InformerFactoryOptions) (Cache, error) {
+	if factory == nil {
+		return nil, errors.New("must provide a non-nil SharedInformerFactory")
+	}
+	if opts.Mapper == nil {
+		return nil, errors.New("must provide a Mapper")
+	}
+	if opts.Scheme == nil {
+		opts.Scheme = scheme.Scheme
+	}
+	return &informerFactoryCache{
+		factory:   factory,
+		scheme:    opts.Scheme,
+		mapper:    opts.Mapper,
+		startWait: make(chan struct{}),
+	}, nil
+}
+
+// SharedIndexInformerFor returns the client-go SharedIndexInformer backing
+// the informer of obj in c. It allows sharing the informers of a Cache with
+// client-go controllers and listers, e.g.
+//
+//	informer, err := cache.SharedIndexInformerFor(ctx, mgr.GetCache(), &corev1.Pod{})
+//	...
+//	lister := corelisters.NewPodLister(informer.GetIndexer())
+//
+// It fails for caches whose informers are not SharedIndexInformers, such as
+// caches restricted to multiple namespaces.
+func SharedIndexInformerFor(ctx context.Context, c Informers, obj client.Object, opts ...InformerGetOption) (toolscache.SharedIndexInformer, error) {
+	informer, err := c.GetInformer(ctx, obj, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sharedIndexInformer, ok := informer.(toolscache.SharedIndexInformer)
+	if !ok {
+		return nil, fmt.Errorf("informer of type %T is not a SharedIndexInformer", informer)
+	}
+	return sharedIndexInformer, nil
+}
+
+// informerFactoryCache is a Cache backed by a client-go SharedInformerFactory.
+type informerFactoryCache struct {
+	factory informers.SharedInformerFactory
+	scheme  *runtime.Scheme
+	mapper  meta.RESTMapper
+
+	// mu guards started and stop.
+	mu      sync.Mutex
+	started bool
+	stop    <-chan struct{}
+
+	// startWait is closed once the cache has been started.
+	startWait chan struct{}
+}
+
+// Get implements Reader.
+func (c *informerFactoryCache) Get(ctx context.Context, key client.ObjectKey, out client.Object, opts ...client.GetOption) error {
+	gvk, err := apiutil.GVKForObject(out, c.scheme)
+	if err != nil {
+		return err
+	}
+	reader, err := c.readerFor(ctx, gvk, out)
+	if err != nil {
+		return err
+	}
+	return reader.Get(ctx, key, out, opts...)
+}
+
+// List implements Reader.
+func (c *informerFactoryCache) List(ctx context.Context, out client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(out, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	reader, err := c.readerFor(ctx, gvk, out)
+	if err != nil {
+		return err
+	}
+	return reader.List(ctx, out, opts...)
+}
+
+func (c *informerFactoryCache) readerFor(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object) (*internal.CacheReader, error) {
+	informer, mapping, err := c.informerFor(ctx, gvk, obj, true)
+	if err != nil {
+		return nil, err
+	}
+	if !c.isStarted() {
+		return nil, &ErrCacheNotStarted{}
+	}
+	reader := internal.NewCacheReader(informer.GetIndexer(), gvk, mapping.Scope.Name(), false)
+	return &reader, nil
+}
+
+// GetInformer implements Informers.
+func (c *informerFactoryCache) GetInformer(ctx context.Context, obj client.Object, opts ...InformerGetOption) (Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	if isRestricted(applyGetOptions(opts...)) {
+		return nil, errors.New("informers of caches backed by a SharedInformerFactory can't be restricted")
+	}
+	informer, _, err := c.informerFor(ctx, gvk, obj, blockUntilSynced(opts...))
+	return informer, err
+}
+
+// GetInformerForKind implements Informers.
+func (c *informerFactoryCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...InformerGetOption) (Informer, error) {
+	if isRestricted(applyGetOptions(opts...)) {
+		return nil, errors.New("informers of caches backed by a SharedInformerFactory can't be restricted")
+	}
+	informer, _, err := c.informerFor(ctx, gvk, nil, blockUntilSynced(opts...))
+	return informer, err
+}
+
+// informerFor returns the informer of the factory for the given kind,
+// starting it if the cache has been started.
+func (c *informerFactoryCache) informerFor(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object, block bool) (toolscache.SharedIndexInformer, *meta.RESTMapping, error) {
+	switch obj.(type) {
+	case runtime.Unstructured, *metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList:
+		return nil, nil, fmt.Errorf("caches backed by a SharedInformerFactory only support typed objects, got %T", obj)
+	}
+
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	generic, err := c.factory.ForResource(mapping.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get informer for %s: %w", gvk, err)
+	}
+	informer := generic.Informer()
+
+	c.mu.Lock()
+	started := c.started
+	if started {
+		// Start only starts the informers that have not been started yet.
+		c.factory.Start(c.stop)
+	}
+	c.mu.Unlock()
+
+	if block && started && !informer.HasSynced() {
+		if !toolscache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			return nil, nil, apierrors.NewTimeoutError(fmt.Sprintf("failed waiting for %s Informer to sync", gvk), 0)
+		}
+	}
+	return informer, mapping, nil
+}
+
+// RemoveInformer implements Informers. Informers of a SharedInformerFactory
+// can't be removed.
+func (c *informerFactoryCache) RemoveInformer(context.Context, client.Object) error {
+	return errors.New("removing informers is not supported by caches backed by a SharedInformerFactory")
+}
+
+// Start implements Informers.
+func (c *informerFactoryCache) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return errors.New("informer factory cache already started")
+	}
+	c.started = true
+	c.stop = ctx.Done()
+	c.factory.Start(c.stop)
+	c.mu.Unlock()
+	close(c.startWait)
+
+	<-ctx.Done()
+	return nil
+}
+
+// WaitForCacheSync implements Informers.
+func (c *informerFactoryCache) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-c.startWait:
+	case <-ctx.Done():
+		return false
+	}
+	for _, synced := range c.factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexField implements client.FieldIndexer.
+func (c *informerFactoryCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	informer, err := c.GetInformer(ctx, obj, BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	return indexByField(informer, field, extractValue)
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+func (c *informerFactoryCache) NeedLeaderElection() bool {
+	return false
+}
+
+func (c *informerFactoryCache) isStarted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.started
+}
+
+func blockUntilSynced(opts ...InformerGetOption) bool {
+	getOpts := applyGetOptions(opts...)
+	return getOpts.BlockUntilSynced == nil || *getOpts.BlockUntilSynced
+}
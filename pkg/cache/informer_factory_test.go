@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Cache backed by a SharedInformerFactory", func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		factory informers.SharedInformerFactory
+		c       cache.Cache
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		clientset := kubefake.NewSimpleClientset(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", Labels: map[string]string{"app": "a"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "other"}},
+		)
+		factory = informers.NewSharedInformerFactory(clientset, 0)
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+
+		var err error
+		c, err = cache.NewFromInformerFactory(factory, cache.InformerFactoryOptions{Mapper: mapper})
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Expect(c.WaitForCacheSync(ctx)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		cancel()
+		factory.Shutdown()
+	})
+
+	It("should read objects from the informers of the factory", func() {
+		pod := &corev1.Pod{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "a"}, pod)).To(Succeed())
+		Expect(pod.Labels).To(HaveKeyWithValue("app", "a"))
+
+		pods := &corev1.PodList{}
+		Expect(c.List(ctx, pods, client.InNamespace("other"))).To(Succeed())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Name).To(Equal("b"))
+	})
+
+	It("should share informers with the factory", func() {
+		informer, err := cache.SharedIndexInformerFor(ctx, c, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informer).To(BeIdenticalTo(factory.Core().V1().Pods().Informer()))
+
+		lister := corelisters.NewPodLister(informer.GetIndexer())
+		pods, err := lister.Pods("default").List(labels.Everything())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(HaveLen(1))
+	})
+
+	It("should support field indexes", func() {
+		Expect(c.IndexField(ctx, &corev1.Pod{}, "metadata.name", func(obj client.Object) []string {
+			return []string{obj.GetName()}
+		})).To(Succeed())
+		pods := &corev1.PodList{}
+		Expect(c.List(ctx, pods, client.MatchingFields{"metadata.name": "b"})).To(Succeed())
+		Expect(pods.Items).To(HaveLen(1))
+	})
+
+	It("should reject unstructured and metadata-only objects", func() {
+		pod := &metav1.PartialObjectMetadata{}
+		pod.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "a"}, pod)).NotTo(Succeed())
+	})
+
+	It("should not support removing informers", func() {
+		Expect(c.RemoveInformer(ctx, &corev1.Pod{})).NotTo(Succeed())
+	})
+})
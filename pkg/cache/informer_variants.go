@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+)
+
+// WithInformerSelector restricts the informer returned by GetInformer and
+// GetInformerForKind to the objects matching the given label and field
+// selectors, either of which may be nil, and, if any namespace is given, to
+// the objects of those namespaces. The restriction is pushed down to the API
+// server: a dedicated informer listing and watching only those objects is
+// created next to the unrestricted one, and shared by the callers asking for
+// the same restriction. Namespaces are ignored for cluster-scoped objects.
+//
+// The restriction only applies to the events of the returned informer.
+// Reads from the cache, and indexes added with IndexField, keep using the
+// unrestricted informer.
+func WithInformerSelector(label labels.Selector, field fields.Selector, namespaces ...string) InformerGetOption {
+	return func(opts *InformerGetOptions) {
+		if label != nil || field != nil {
+			opts.Selector = &internal.Selector{Label: label, Field: field}
+		}
+		opts.Namespaces = namespaces
+	}
+}
+
+func isRestricted(opts *internal.GetOptions) bool {
+	return opts.Selector != nil || len(opts.Namespaces) > 0
+}
+
+// informerVariants holds the restricted variants of the informers of an
+// informerCache. Each variant is a set of informers with its own selector
+// and namespace, created on demand and started along with the cache.
+type informerVariants struct {
+	// namespace and selector are those of the informerCache.
+	namespace string
+	selector  internal.Selector
+	mapper    apimeta.RESTMapper
+
+	newInformers func(namespace string, selector internal.Selector) *internal.Informers
+
+	mu        sync.Mutex
+	ctx       context.Context
+	informers map[string]*internal.Informers
+}
+
+// get returns the informer of gvk restricted according to opts.
+func (v *informerVariants) get(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object, opts *internal.GetOptions) (Informer, error) {
+	if v == nil {
+		return nil, fmt.Errorf("informers of %s can't be restricted by this cache", gvk)
+	}
+	selector := v.selector
+	if opts.Selector != nil {
+		selector = internal.Selector{
+			Label: andLabelSelectors(selector.Label, opts.Selector.Label),
+			Field: andFieldSelectors(selector.Field, opts.Selector.Field),
+		}
+	}
+
+	namespaces := []string{v.namespace}
+	if len(opts.Namespaces) > 0 {
+		mapping, err := v.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+		if mapping.Scope.Name() == apimeta.RESTScopeNameNamespace {
+			switch {
+			case v.namespace == "":
+				namespaces = opts.Namespaces
+			case !slices.Contains(opts.Namespaces, v.namespace):
+				return nil, fmt.Errorf("informers of this cache are restricted to namespace %q, which is not one of %v", v.namespace, opts.Namespaces)
+			}
+		}
+	}
+
+	namespaceToInformer := make(map[string]Informer, len(namespaces))
+	for _, namespace := range namespaces {
+		informers, err := v.variant(ctx, namespace, selector, opts)
+		if err != nil {
+			return nil, err
+		}
+		_, i, err := informers.Get(ctx, gvk, obj, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(namespaces) == 1 {
+			return i.Informer, nil
+		}
+		namespaceToInformer[namespace] = i.Informer
+	}
+	return &multiNamespaceInformer{namespaceToInformer: namespaceToInformer}, nil
+}
+
+// variant returns the informers of the given namespace and selector,
+// creating and, if the cache has been started, starting them if needed.
+func (v *informerVariants) variant(ctx context.Context, namespace string, selector internal.Selector, opts *internal.GetOptions) (*internal.Informers, error) {
+	key := variantKey(namespace, selector)
+
+	v.mu.Lock()
+	informers, ok := v.informers[key]
+	if !ok {
+		informers = v.newInformers(namespace, selector)
+		if v.informers == nil {
+			v.informers = map[string]*internal.Informers{}
+		}
+		v.informers[key] = informers
+		if v.ctx != nil {
+			go informers.Start(v.ctx) //nolint:errcheck // Start only fails when already started.
+		}
+	}
+	started := v.ctx != nil
+	v.mu.Unlock()
+
+	// Wait for new informers to be started, so that Get blocks until the
+	// informer has synced if asked to.
+	if started && (opts.BlockUntilSynced == nil || *opts.BlockUntilSynced) && !informers.WaitForCacheSync(ctx) {
+		return nil, fmt.Errorf("failed waiting for informers of namespace %q to start: %w", namespace, ctx.Err())
+	}
+	return informers, nil
+}
+
+// start starts the existing variants, and the ones created from now on.
+func (v *informerVariants) start(ctx context.Context) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.ctx = ctx
+	for _, informers := range v.informers {
+		go informers.Start(ctx) //nolint:errcheck // Start only fails when already started.
+	}
+}
+
+// waitForCacheSync waits for all the variants to sync.
+func (v *informerVariants) waitForCacheSync(ctx context.Context) bool {
+	if v == nil {
+		return true
+	}
+	v.mu.Lock()
+	informers := make([]*internal.Informers, 0, len(v.informers))
+	for _, i := range v.informers {
+		informers = append(informers, i)
+	}
+	v.mu.Unlock()
+	for _, i := range informers {
+		if !i.WaitForCacheSync(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func variantKey(namespace string, selector internal.Selector) string {
+	var label, field string
+	if selector.Label != nil {
+		label = selector.Label.String()
+	}
+	if selector.Field != nil {
+		field = selector.Field.String()
+	}
+	return strings.Join([]string{namespace, label, field}, "|")
+}
+
+func andLabelSelectors(a, b labels.Selector) labels.Selector {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	requirements, _ := b.Requirements()
+	return a.Add(requirements...)
+}
+
+func andFieldSelectors(a, b fields.Selector) fields.Selector {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return fields.AndSelectors(a, b)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+// restrictionRecordingCache records the namespaces its informers are
+// restricted to.
+type restrictionRecordingCache struct {
+	Cache
+	namespaces [][]string
+}
+
+func (c *restrictionRecordingCache) GetInformer(_ context.Context, _ client.Object, opts ...InformerGetOption) (Informer, error) {
+	c.namespaces = append(c.namespaces, applyGetOptions(opts...).Namespaces)
+	return &controllertest.FakeInformer{}, nil
+}
+
+var _ = Describe("restricted informers", func() {
+	It("should AND the selectors of the cache and of the restriction", func() {
+		label := andLabelSelectors(labels.SelectorFromSet(labels.Set{"a": "1"}), labels.SelectorFromSet(labels.Set{"b": "2"}))
+		Expect(label.Matches(labels.Set{"a": "1", "b": "2"})).To(BeTrue())
+		Expect(label.Matches(labels.Set{"a": "1"})).To(BeFalse())
+
+		field := andFieldSelectors(fields.OneTermEqualSelector("metadata.name", "x"), nil)
+		Expect(field.String()).To(Equal("metadata.name=x"))
+		field = andFieldSelectors(field, fields.OneTermEqualSelector("metadata.namespace", "y"))
+		Expect(field.Matches(fields.Set{"metadata.name": "x", "metadata.namespace": "y"})).To(BeTrue())
+		Expect(field.Matches(fields.Set{"metadata.name": "x"})).To(BeFalse())
+	})
+
+	It("should key variants by namespace and selector", func() {
+		a := variantKey("ns", internal.Selector{Label: labels.SelectorFromSet(labels.Set{"a": "1"})})
+		Expect(a).To(Equal(variantKey("ns", internal.Selector{Label: labels.SelectorFromSet(labels.Set{"a": "1"})})))
+		Expect(a).NotTo(Equal(variantKey("other", internal.Selector{Label: labels.SelectorFromSet(labels.Set{"a": "1"})})))
+		Expect(a).NotTo(Equal(variantKey("ns", internal.Selector{Field: fields.OneTermEqualSelector("a", "1")})))
+	})
+
+	It("should fail to restrict informers of caches without variants", func() {
+		ic := &informerCache{scheme: scheme.Scheme}
+		_, err := ic.GetInformer(context.Background(), &corev1.Pod{}, WithInformerSelector(labels.Everything(), nil))
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("of a multi-namespace cache", func() {
+		var (
+			one, two, all *restrictionRecordingCache
+			c             *multiNamespaceCache
+		)
+
+		BeforeEach(func() {
+			one, two, all = &restrictionRecordingCache{}, &restrictionRecordingCache{}, &restrictionRecordingCache{}
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+			c = &multiNamespaceCache{
+				Scheme:           scheme.Scheme,
+				RESTMapper:       mapper,
+				namespaceToCache: map[string]Cache{"one": one, "two": two, "": all},
+			}
+		})
+
+		It("should only get informers from the caches of the restricted namespaces", func() {
+			informer, err := c.GetInformer(context.Background(), &corev1.Pod{}, WithInformerSelector(nil, nil, "one", "three"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(informer.(*multiNamespaceInformer).namespaceToInformer).To(HaveLen(2))
+			Expect(one.namespaces).To(Equal([][]string{{"one", "three"}}))
+			Expect(two.namespaces).To(BeEmpty())
+			Expect(all.namespaces).To(Equal([][]string{{"three"}}))
+		})
+
+		It("should fail if none of the restricted namespaces is cached", func() {
+			delete(c.namespaceToCache, "")
+			_, err := c.GetInformer(context.Background(), &corev1.Pod{}, WithInformerSelector(nil, nil, "three"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should get informers from all the caches without restriction", func() {
+			_, err := c.GetInformer(context.Background(), &corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(one.namespaces).To(HaveLen(1))
+			Expect(two.namespaces).To(HaveLen(1))
+			Expect(all.namespaces).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+var log = logf.RuntimeLog.WithName("cache")
+
+// AccessDeniedAction is what an informer does when it is not allowed to list
+// and watch its objects.
+type AccessDeniedAction int
+
+const (
+	// AccessDeniedFail fails to get the informer.
+	AccessDeniedFail AccessDeniedAction = iota
+
+	// AccessDeniedSkip runs an informer that is synced but never contains
+	// any object.
+	AccessDeniedSkip
+
+	// AccessDeniedPoll runs an informer that periodically lists objects
+	// instead of watching them if it is allowed to list them, and otherwise
+	// behaves like AccessDeniedSkip.
+	AccessDeniedPoll
+)
+
+// AccessReviewFunc reviews whether the given action is allowed.
+type AccessReviewFunc func(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error)
+
+// AccessCheck configures the access review performed before informers are
+// created.
+type AccessCheck struct {
+	// Review reviews access to the resources of informers.
+	Review AccessReviewFunc
+
+	// OnDenied is what informers do when access is denied.
+	OnDenied AccessDeniedAction
+
+	// PollInterval is the interval at which informers list objects when
+	// they are not allowed to watch them and OnDenied is AccessDeniedPoll.
+	PollInterval time.Duration
+}
+
+// access is the result of an access review.
+type access struct {
+	list  bool
+	watch bool
+}
+
+// reviewAccess reviews access to list and watch the objects of the given
+// kind in the namespace of the informers. Access is not reviewed, and thus
+// considered allowed, if no access check is configured.
+func (ip *Informers) reviewAccess(ctx context.Context, gvk schema.GroupVersionKind) (access, error) {
+	if ip.accessCheck == nil {
+		return access{list: true, watch: true}, nil
+	}
+	mapping, err := ip.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return access{}, err
+	}
+
+	attributes := authorizationv1.ResourceAttributes{
+		Group:    mapping.Resource.Group,
+		Version:  mapping.Resource.Version,
+		Resource: mapping.Resource.Resource,
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		attributes.Namespace = restrictNamespaceBySelector(ip.namespace, ip.selector)
+	}
+
+	allowed := make(map[string]bool, 2)
+	for _, verb := range []string{"list", "watch"} {
+		attributes.Verb = verb
+		if allowed[verb], err = ip.accessCheck.Review(ctx, attributes); err != nil {
+			return access{}, fmt.Errorf("failed to review access to %s %s: %w", verb, mapping.Resource, err)
+		}
+	}
+	return access{list: allowed["list"], watch: allowed["watch"]}, nil
+}
+
+// guardListWatch returns the ListWatch to use for an informer given the
+// access to its objects.
+func (ip *Informers) guardListWatch(gvk schema.GroupVersionKind, obj runtime.Object, lw *cache.ListWatch, a access) (*cache.ListWatch, error) {
+	if a.list && a.watch {
+		return lw, nil
+	}
+
+	log := log.WithValues("group", gvk.Group, "version", gvk.Version, "kind", gvk.Kind, "namespace", ip.namespace)
+	switch {
+	case ip.accessCheck.OnDenied == AccessDeniedFail:
+		mapping, err := ip.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+		return nil, apierrors.NewForbidden(mapping.Resource.GroupResource(), "",
+			fmt.Errorf("not allowed to list and watch %s, refusing to start informer", mapping.Resource))
+	case ip.accessCheck.OnDenied == AccessDeniedPoll && a.list:
+		log.Info("Not allowed to watch objects, polling them instead", "interval", ip.accessCheck.PollInterval)
+		return &cache.ListWatch{
+			ListFunc: lw.ListFunc,
+			WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+				return newPollWatcher(ip.accessCheck.PollInterval), nil
+			},
+		}, nil
+	default:
+		log.Info("Not allowed to list and watch objects, the cache will not contain any of them")
+		listObj, err := ip.newListObject(gvk, obj)
+		if err != nil {
+			return nil, err
+		}
+		return &cache.ListWatch{
+			ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+				return listObj.DeepCopyObject(), nil
+			},
+			WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}, nil
+	}
+}
+
+// newListObject returns an empty list for objects of the given kind.
+func (ip *Informers) newListObject(gvk schema.GroupVersionKind, obj runtime.Object) (runtime.Object, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	switch obj.(type) {
+	case runtime.Unstructured:
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listGVK)
+		return list, nil
+	case *metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList:
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(listGVK)
+		return list, nil
+	default:
+		return ip.scheme.New(listGVK)
+	}
+}
+
+// pollWatcher is a watch that never delivers objects and expires after an
+// interval, which makes the reflector of the informer list the objects
+// again.
+type pollWatcher struct {
+	result   chan watch.Event
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newPollWatcher(interval time.Duration) *pollWatcher {
+	w := &pollWatcher{
+		result: make(chan watch.Event),
+		stop:   make(chan struct{}),
+	}
+	go func() {
+		defer close(w.result)
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		select {
+		case <-w.stop:
+			return
+		case <-timer.C:
+		}
+		expired := apierrors.NewResourceExpired("polling interval elapsed")
+		select {
+		case <-w.stop:
+		case w.result <- watch.Event{Type: watch.Error, Object: &expired.ErrStatus}:
+		}
+	}()
+	return w
+}
+
+func (w *pollWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+func (w *pollWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("Informers with an access check", func() {
+	podGVK := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		server  *httptest.Server
+		lists   atomic.Int32
+		reviews []authorizationv1.ResourceAttributes
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		lists.Store(0)
+		reviews = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.URL.Query().Get("watch")).To(BeEmpty(), "unexpected watch request")
+			lists.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(&corev1.PodList{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+				ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+				Items:    []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", ResourceVersion: "1"}}},
+			})).To(Succeed())
+		}))
+	})
+
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+
+	newInformers := func(onDenied AccessDeniedAction, allowed ...string) *Informers {
+		s := runtime.NewScheme()
+		Expect(corev1.AddToScheme(s)).To(Succeed())
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+
+		ip := NewInformers(&rest.Config{Host: server.URL}, &InformersOpts{
+			HTTPClient:   server.Client(),
+			Scheme:       s,
+			Mapper:       mapper,
+			ResyncPeriod: time.Hour,
+			Namespace:    "default",
+			AccessCheck: &AccessCheck{
+				Review: func(_ context.Context, attributes authorizationv1.ResourceAttributes) (bool, error) {
+					reviews = append(reviews, attributes)
+					for _, verb := range allowed {
+						if verb == attributes.Verb {
+							return true, nil
+						}
+					}
+					return false, nil
+				},
+				OnDenied:     onDenied,
+				PollInterval: 50 * time.Millisecond,
+			},
+		})
+		go func() {
+			defer GinkgoRecover()
+			Expect(ip.Start(ctx)).To(Succeed())
+		}()
+		Expect(ip.waitForStarted(ctx)).To(BeTrue())
+		return ip
+	}
+
+	It("should review access to list and watch in the namespace of the informers", func() {
+		ip := newInformers(AccessDeniedSkip)
+		_, _, err := ip.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reviews).To(ConsistOf(
+			authorizationv1.ResourceAttributes{Namespace: "default", Verb: "list", Version: "v1", Resource: "pods"},
+			authorizationv1.ResourceAttributes{Namespace: "default", Verb: "watch", Version: "v1", Resource: "pods"},
+		))
+	})
+
+	It("should fail to get the informer if access is denied and the policy is to fail", func() {
+		ip := newInformers(AccessDeniedFail, "list")
+		_, _, err := ip.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "expected Forbidden error, got %v", err)
+	})
+
+	It("should start an empty informer if access is denied and the policy is to skip", func() {
+		ip := newInformers(AccessDeniedSkip, "list")
+		_, informer, err := ip.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informer.Informer.HasSynced()).To(BeTrue())
+		Consistently(lists.Load, 100*time.Millisecond).Should(BeZero())
+		Expect(informer.Informer.GetStore().List()).To(BeEmpty())
+	})
+
+	It("should poll objects if watching them is denied and the policy is to poll", func() {
+		ip := newInformers(AccessDeniedPoll, "list")
+		_, informer, err := ip.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informer.Informer.GetStore().ListKeys()).To(ConsistOf("default/pod"))
+		Eventually(lists.Load, 5*time.Second).Should(BeNumerically(">=", 2))
+	})
+})
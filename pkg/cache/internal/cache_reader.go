@@ -53,6 +53,17 @@ type CacheReader struct {
 	disableDeepCopy bool
 }
 
+// NewCacheReader returns a CacheReader reading objects of the given kind
+// from indexer.
+func NewCacheReader(indexer cache.Indexer, gvk schema.GroupVersionKind, scopeName apimeta.RESTScopeName, disableDeepCopy bool) CacheReader {
+	return CacheReader{
+		indexer:          indexer,
+		groupVersionKind: gvk,
+		scopeName:        scopeName,
+		disableDeepCopy:  disableDeepCopy,
+	}
+}
+
 // Get checks the indexer for the object and writes a copy of it if found.
 func (c *CacheReader) Get(_ context.Context, key client.ObjectKey, out client.Object, _ ...client.GetOption) error {
 	if c.scopeName == apimeta.RESTScopeNameRoot {
@@ -81,7 +92,15 @@ func (c *CacheReader) Get(_ context.Context, key client.ObjectKey, out client.Ob
 		return fmt.Errorf("cache contained %T, which is not an Object", obj)
 	}
 
-	if c.disableDeepCopy {
+	if serialized, ok := obj.(*SerializedObject); ok {
+		// Decoding returns a new copy of the object.
+		decoded, err := serialized.Decode()
+		if err != nil {
+			return err
+		}
+		decoded.GetObjectKind().SetGroupVersionKind(c.groupVersionKind)
+		obj = decoded
+	} else if c.disableDeepCopy {
 		// skip deep copy which might be unsafe
 		// you must DeepCopy any object before mutating it outside
 	} else {
@@ -164,7 +183,12 @@ func (c *CacheReader) List(_ context.Context, out client.ObjectList, opts ...cli
 		}
 
 		var outObj runtime.Object
-		if c.disableDeepCopy || (listOpts.UnsafeDisableDeepCopy != nil && *listOpts.UnsafeDisableDeepCopy) {
+		if serialized, ok := obj.(*SerializedObject); ok {
+			if outObj, err = serialized.Decode(); err != nil {
+				return err
+			}
+			outObj.GetObjectKind().SetGroupVersionKind(c.groupVersionKind)
+		} else if c.disableDeepCopy || (listOpts.UnsafeDisableDeepCopy != nil && *listOpts.UnsafeDisableDeepCopy) {
 			// skip deep copy which might be unsafe
 			// you must DeepCopy any object before mutating it outside
 			outObj = obj
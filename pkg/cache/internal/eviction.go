@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// watchEvicter injects deletions of objects into the current watch of an
+// informer. The deletions go through the informer's own delete path: they
+// are queued in order with the other events of the watch, remove the objects
+// from the store and its DeltaFIFO's known objects consistently, and are
+// delivered to the informer's event handlers.
+type watchEvicter struct {
+	mu    sync.Mutex
+	watch *evictingWatch
+}
+
+// wrap returns a watch forwarding the events of w, into which evict injects
+// deletions. resourceVersion is the resourceVersion w was started from.
+func (e *watchEvicter) wrap(w watch.Interface, resourceVersion string) watch.Interface {
+	ew := newEvictingWatch(w, resourceVersion)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.watch = ew
+	return ew
+}
+
+// evict injects deletions of objs into the current watch, and returns the
+// number of injected deletions. If the informer isn't watching, nothing is
+// injected: objects that don't exist anymore are dropped from the store by
+// the list preceding the next watch anyway.
+func (e *watchEvicter) evict(objs []runtime.Object) int {
+	e.mu.Lock()
+	w := e.watch
+	e.mu.Unlock()
+	if w == nil || len(objs) == 0 {
+		return 0
+	}
+	return w.evict(objs)
+}
+
+type evictingWatch struct {
+	watch.Interface
+
+	result    chan watch.Event
+	evictions chan []runtime.Object
+
+	// stopped is closed by Stop, exited when run returns.
+	stopped  chan struct{}
+	stopOnce sync.Once
+	exited   chan struct{}
+}
+
+func newEvictingWatch(w watch.Interface, resourceVersion string) *evictingWatch {
+	ew := &evictingWatch{
+		Interface: w,
+		result:    make(chan watch.Event),
+		evictions: make(chan []runtime.Object),
+		stopped:   make(chan struct{}),
+		exited:    make(chan struct{}),
+	}
+	go ew.run(resourceVersion)
+	return ew
+}
+
+// ResultChan implements watch.Interface.
+func (w *evictingWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// Stop implements watch.Interface.
+func (w *evictingWatch) Stop() {
+	w.stopOnce.Do(func() { close(w.stopped) })
+	w.Interface.Stop()
+}
+
+func (w *evictingWatch) evict(objs []runtime.Object) int {
+	select {
+	case w.evictions <- objs:
+		return len(objs)
+	case <-w.exited:
+		return 0
+	}
+}
+
+// run forwards the events of the wrapped watch and the injected deletions.
+// The injected objects carry the resourceVersion of the last event, as the
+// reflector resumes watching from the resourceVersion of the last event it
+// received.
+func (w *evictingWatch) run(resourceVersion string) {
+	defer close(w.exited)
+	defer close(w.result)
+
+	events := w.Interface.ResultChan()
+	for {
+		select {
+		case <-w.stopped:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type != watch.Error {
+				if obj, err := meta.Accessor(event.Object); err == nil {
+					resourceVersion = obj.GetResourceVersion()
+				}
+			}
+			if !w.send(event) {
+				return
+			}
+		case objs := <-w.evictions:
+			for _, obj := range objs {
+				if accessor, err := meta.Accessor(obj); err == nil {
+					accessor.SetResourceVersion(resourceVersion)
+				}
+				if !w.send(watch.Event{Type: watch.Deleted, Object: obj}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *evictingWatch) send(event watch.Event) bool {
+	select {
+	case w.result <- event:
+		return true
+	case <-w.stopped:
+		return false
+	}
+}
+
+// evictionCopy returns a copy of the stored object obj to inject the
+// deletion of, decoded if the store keeps it serialized, as the reflector
+// only accepts objects of the type of the informer.
+func evictionCopy(obj interface{}) (runtime.Object, bool) {
+	switch o := obj.(type) {
+	case *SerializedObject:
+		decoded, err := o.Decode()
+		return decoded, err == nil
+	case runtime.Object:
+		return o.DeepCopyObject(), true
+	default:
+		return nil, false
+	}
+}
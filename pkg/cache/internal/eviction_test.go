@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	fcache "k8s.io/client-go/tools/cache/testing"
+)
+
+var _ = Describe("watchEvicter", func() {
+	It("should evict objects through the delete path of the informer", func(specCtx SpecContext) {
+		source := fcache.NewFakeControllerSource()
+		source.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}})
+
+		evicter := &watchEvicter{}
+		informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+			ListFunc: source.List,
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				w, err := source.Watch(opts)
+				if err != nil {
+					return nil, err
+				}
+				return evicter.wrap(w, opts.ResourceVersion), nil
+			},
+		}, &corev1.Pod{}, 0, cache.Indexers{})
+
+		deleted := make(chan string, 1)
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj interface{}) {
+				deleted <- obj.(*corev1.Pod).Name
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(specCtx)
+		defer cancel()
+		go informer.Run(ctx.Done())
+		Expect(cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)).To(BeTrue())
+
+		// Wait for the informer to watch.
+		Eventually(func() int {
+			obj, _, _ := informer.GetStore().GetByKey("default/foo")
+			copied, _ := evictionCopy(obj)
+			return evicter.evict([]runtime.Object{copied})
+		}).Should(Equal(1))
+
+		Eventually(deleted).Should(Receive(Equal("foo")))
+		Expect(informer.GetStore().ListKeys()).To(BeEmpty())
+
+		// Objects changed afterwards are added back.
+		source.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"}})
+		Eventually(informer.GetStore().ListKeys).Should(ConsistOf("default/bar"))
+	})
+
+	It("should not inject deletions when the informer isn't watching", func() {
+		Expect((&watchEvicter{}).evict([]runtime.Object{&corev1.Pod{}})).To(Equal(0))
+	})
+})
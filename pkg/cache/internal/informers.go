@@ -18,6 +18,7 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -52,6 +53,8 @@ type InformersOpts struct {
 	Transform             cache.TransformFunc
 	UnsafeDisableDeepCopy bool
 	WatchErrorHandler     cache.WatchErrorHandler
+	AccessCheck           *AccessCheck
+	SerializedStore       *SerializedStore
 }
 
 // NewInformers creates a new InformersMap that can create informers under the hood.
@@ -80,6 +83,8 @@ func NewInformers(config *rest.Config, options *InformersOpts) *Informers {
 		unsafeDisableDeepCopy: options.UnsafeDisableDeepCopy,
 		newInformer:           newInformer,
 		watchErrorHandler:     options.WatchErrorHandler,
+		accessCheck:           options.AccessCheck,
+		serializedStore:       options.SerializedStore,
 	}
 }
 
@@ -93,6 +98,46 @@ type Cache struct {
 
 	// Stop can be used to stop this individual informer.
 	stop chan struct{}
+
+	// health tracks the failures of the list and watch requests of the
+	// informer.
+	health *watchHealth
+
+	// evicter injects deletions into the watch of the informer.
+	evicter *watchEvicter
+}
+
+// WatchHealth returns the number of list and watch requests of the informer
+// that failed since the last successful one, and the error of the last
+// failed request.
+func (c *Cache) WatchHealth() (int, error) {
+	if c.health == nil {
+		return 0, nil
+	}
+	return c.health.get()
+}
+
+type watchHealth struct {
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+}
+
+func (h *watchHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		h.failures, h.lastErr = 0, nil
+		return
+	}
+	h.failures++
+	h.lastErr = err
+}
+
+func (h *watchHealth) get() (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.failures, h.lastErr
 }
 
 // Start starts the informer managed by a MapEntry.
@@ -117,6 +162,19 @@ type tracker struct {
 type GetOptions struct {
 	// BlockUntilSynced controls if the informer retrieval will block until the informer is synced. Defaults to `true`.
 	BlockUntilSynced *bool
+
+	// Selector, if set, restricts the informer to the objects it selects.
+	// It is handled by the cache, not by Informers.
+	Selector *Selector
+
+	// Namespaces, if set, restricts the informer to the objects of the given
+	// namespaces. It is handled by the cache, not by Informers.
+	Namespaces []string
+
+	// PageSize, if positive, is the number of objects requested per page
+	// when the informer lists the objects. It only applies when the
+	// informer is created.
+	PageSize int64
 }
 
 // Informers create and caches Informers for (runtime.Object, schema.GroupVersionKind) pairs.
@@ -171,8 +229,12 @@ type Informers struct {
 	// default or empty string means all namespaces
 	namespace string
 
-	selector              Selector
-	transform             cache.TransformFunc
+	selector  Selector
+	transform cache.TransformFunc
+
+	// serializedStore, if set, makes the informers of typed objects keep
+	// them serialized.
+	serializedStore       *SerializedStore
 	unsafeDisableDeepCopy bool
 
 	// NewInformer allows overriding of the shared index informer constructor for testing.
@@ -182,6 +244,10 @@ type Informers struct {
 	// watchErrorHandler to be set by overriding the options
 	// or to use the default watchErrorHandler
 	watchErrorHandler cache.WatchErrorHandler
+
+	// accessCheck, if set, is used to review access to the objects of
+	// informers before creating them.
+	accessCheck *AccessCheck
 }
 
 // Start calls Run on each of the informers and sets started to true. Blocks on the context.
@@ -269,6 +335,64 @@ func (ip *Informers) getHasSyncedFuncs() []cache.InformerSynced {
 	return res
 }
 
+// ObjectStats holds the number of objects held by an informer and their
+// approximate size in bytes.
+type ObjectStats struct {
+	Objects          int
+	ApproximateBytes int64
+}
+
+// ObjectStats returns the ObjectStats of every informer. The size of the
+// objects is extrapolated from the serialized size of up to sampleSize of them.
+// Stats of structured, unstructured and metadata informers for the same GVK are summed.
+func (ip *Informers) ObjectStats(sampleSize int) map[schema.GroupVersionKind]ObjectStats {
+	ip.mu.RLock()
+	defer ip.mu.RUnlock()
+
+	res := map[schema.GroupVersionKind]ObjectStats{}
+	for _, informers := range []map[schema.GroupVersionKind]*Cache{ip.tracker.Structured, ip.tracker.Unstructured, ip.tracker.Metadata} {
+		for gvk, i := range informers {
+			stats := res[gvk]
+			objs := i.Informer.GetStore().List()
+			stats.Objects += len(objs)
+			stats.ApproximateBytes += approximateBytes(objs, sampleSize)
+			res[gvk] = stats
+		}
+	}
+	return res
+}
+
+// approximateBytes extrapolates the serialized size of objs from the
+// serialized size of up to sampleSize of them.
+func approximateBytes(objs []interface{}, sampleSize int) int64 {
+	if len(objs) == 0 || sampleSize <= 0 {
+		return 0
+	}
+	if sampleSize > len(objs) {
+		sampleSize = len(objs)
+	}
+
+	var sampled, sampledBytes int64
+	step := len(objs) / sampleSize
+	for i := 0; i < len(objs) && sampled < int64(sampleSize); i += step {
+		if serialized, ok := objs[i].(*SerializedObject); ok {
+			sampled++
+			sampledBytes += int64(serialized.Size())
+			continue
+		}
+		data, err := json.Marshal(objs[i])
+		if err != nil {
+			continue
+		}
+		sampled++
+		sampledBytes += int64(len(data))
+	}
+	if sampled == 0 {
+		return 0
+	}
+	return sampledBytes * int64(len(objs)) / sampled
+}
+
 // WaitForCacheSync waits until all the caches have been started and synced.
 func (ip *Informers) WaitForCacheSync(ctx context.Context) bool {
 	if !ip.waitForStarted(ctx) {
@@ -292,7 +416,7 @@ func (ip *Informers) Get(ctx context.Context, gvk schema.GroupVersionKind, obj r
 	i, started, ok := ip.Peek(gvk, obj)
 	if !ok {
 		var err error
-		if i, started, err = ip.addInformerToMap(gvk, obj); err != nil {
+		if i, started, err = ip.addInformerToMap(ctx, gvk, obj, opts.PageSize); err != nil {
 			return started, nil, err
 		}
 	}
@@ -327,6 +451,59 @@ func (ip *Informers) Remove(gvk schema.GroupVersionKind, obj runtime.Object) {
 	delete(informerMap, gvk)
 }
 
+// EvictNamespace evicts the objects of the given namespace from the stores
+// of all the informers. The deletions are injected into the watches of the
+// informers, so that their event handlers are notified and their stores stay
+// consistent. Objects that are updated again afterwards are added back by
+// their informer. It returns the number of evicted objects.
+func (ip *Informers) EvictNamespace(namespace string) int {
+	type eviction struct {
+		evicter *watchEvicter
+		objs    []runtime.Object
+	}
+
+	ip.mu.RLock()
+	var evictions []eviction
+	for _, informerMap := range []map[schema.GroupVersionKind]*Cache{ip.tracker.Structured, ip.tracker.Unstructured, ip.tracker.Metadata} {
+		for _, entry := range informerMap {
+			if entry.evicter == nil {
+				continue
+			}
+			stored, err := entry.Reader.indexer.ByIndex(cache.NamespaceIndex, namespace)
+			if err != nil {
+				continue
+			}
+			objs := make([]runtime.Object, 0, len(stored))
+			for _, obj := range stored {
+				if copied, ok := evictionCopy(obj); ok {
+					objs = append(objs, copied)
+				}
+			}
+			evictions = append(evictions, eviction{evicter: entry.evicter, objs: objs})
+		}
+	}
+	ip.mu.RUnlock()
+
+	// Injecting the deletions blocks until the informers receive them, so
+	// it is done without holding the lock.
+	evicted := 0
+	for _, e := range evictions {
+		evicted += e.evicter.evict(e.objs)
+	}
+	return evicted
+}
+
+// isStructured returns whether obj is a typed object, neither unstructured
+// nor metadata.
+func isStructured(obj runtime.Object) bool {
+	switch obj.(type) {
+	case runtime.Unstructured, *metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList:
+		return false
+	default:
+		return true
+	}
+}
+
 func (ip *Informers) informersByType(obj runtime.Object) map[schema.GroupVersionKind]*Cache {
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -339,7 +516,15 @@ func (ip *Informers) informersByType(obj runtime.Object) map[schema.GroupVersion
 }
 
 // addInformerToMap either returns an existing informer or creates a new informer, adds it to the map and returns it.
-func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.Object) (*Cache, bool, error) {
+// If pageSize is positive, the new informer lists the objects in pages of that size.
+func (ip *Informers) addInformerToMap(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object, pageSize int64) (*Cache, bool, error) {
+	// Review access before taking the lock, as it requires calls to the
+	// API server.
+	access, err := ip.reviewAccess(ctx, gvk)
+	if err != nil {
+		return nil, false, err
+	}
+
 	ip.mu.Lock()
 	defer ip.mu.Unlock()
 
@@ -355,15 +540,31 @@ func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.O
 	if err != nil {
 		return nil, false, err
 	}
+	listWatcher, err = ip.guardListWatch(gvk, obj, listWatcher, access)
+	if err != nil {
+		return nil, false, err
+	}
+	health := &watchHealth{}
+	evicter := &watchEvicter{}
 	sharedIndexInformer := ip.newInformer(&cache.ListWatch{
 		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
 			ip.selector.ApplyToList(&opts)
-			return listWatcher.ListFunc(opts)
+			if pageSize > 0 {
+				opts.Limit = pageSize
+			}
+			res, err := listWatcher.ListFunc(opts)
+			health.record(err)
+			return res, err
 		},
 		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
 			ip.selector.ApplyToList(&opts)
 			opts.Watch = true // Watch needs to be set to true separately
-			return listWatcher.WatchFunc(opts)
+			res, err := listWatcher.WatchFunc(opts)
+			health.record(err)
+			if err != nil {
+				return nil, err
+			}
+			return evicter.wrap(res, opts.ResourceVersion), nil
 		},
 	}, obj, calculateResyncPeriod(ip.resync), cache.Indexers{
 		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
@@ -377,7 +578,13 @@ func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.O
 	}
 
 	// Check to see if there is a transformer for this gvk
-	if err := sharedIndexInformer.SetTransform(ip.transform); err != nil {
+	transform := ip.transform
+	var informer cache.SharedIndexInformer = sharedIndexInformer
+	if ip.serializedStore != nil && isStructured(obj) {
+		transform = ip.serializedStore.transform(transform)
+		informer = &serializedInformer{SharedIndexInformer: sharedIndexInformer}
+	}
+	if err := sharedIndexInformer.SetTransform(transform); err != nil {
 		return nil, false, err
 	}
 
@@ -388,14 +595,16 @@ func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.O
 
 	// Create the new entry and set it in the map.
 	i := &Cache{
-		Informer: sharedIndexInformer,
+		Informer: informer,
 		Reader: CacheReader{
 			indexer:          sharedIndexInformer.GetIndexer(),
 			groupVersionKind: gvk,
 			scopeName:        mapping.Scope.Name(),
 			disableDeepCopy:  ip.unsafeDisableDeepCopy,
 		},
-		stop: make(chan struct{}),
+		stop:    make(chan struct{}),
+		health:  health,
+		evicter: evicter,
 	}
 	ip.informersByType(obj)[gvk] = i
 
@@ -21,10 +21,12 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
 )
 
 // Test that gvkFixupWatcher behaves like watch.FakeWatcher
@@ -92,3 +94,51 @@ var _ = Describe("gvkFixupWatcher", func() {
 		consumer(gvkfw)
 	})
 })
+
+var _ = Describe("Informers.ObjectStats", func() {
+	It("should count objects and extrapolate their size per GVK", func() {
+		gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+		informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &corev1.ConfigMap{}, 0, cache.Indexers{})
+		for i := range 20 {
+			Expect(informer.GetStore().Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("cm-%02d", i), Namespace: "default"},
+			})).To(Succeed())
+		}
+
+		ip := &Informers{tracker: tracker{
+			Structured:   map[schema.GroupVersionKind]*Cache{gvk: {Informer: informer}},
+			Unstructured: map[schema.GroupVersionKind]*Cache{},
+			Metadata:     map[schema.GroupVersionKind]*Cache{},
+		}}
+
+		stats := ip.ObjectStats(5)
+		Expect(stats).To(HaveKey(gvk))
+		Expect(stats[gvk].Objects).To(Equal(20))
+		Expect(stats[gvk].ApproximateBytes).To(BeNumerically(">", 20*len(`{"metadata":{}}`)))
+	})
+
+	It("should report no size for empty informers or a zero sample size", func() {
+		Expect(approximateBytes(nil, 10)).To(BeZero())
+		Expect(approximateBytes([]interface{}{&corev1.ConfigMap{}}, 0)).To(BeZero())
+	})
+})
+
+var _ = Describe("watchHealth", func() {
+	It("should count consecutive failures until a request succeeds", func() {
+		c := &Cache{health: &watchHealth{}}
+		failures, lastErr := c.WatchHealth()
+		Expect(failures).To(BeZero())
+		Expect(lastErr).NotTo(HaveOccurred())
+
+		c.health.record(fmt.Errorf("the server could not find the requested resource"))
+		c.health.record(fmt.Errorf("still not found"))
+		failures, lastErr = c.WatchHealth()
+		Expect(failures).To(Equal(2))
+		Expect(lastErr).To(MatchError("still not found"))
+
+		c.health.record(nil)
+		failures, lastErr = c.WatchHealth()
+		Expect(failures).To(BeZero())
+		Expect(lastErr).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SerializedStore configures informers to keep the objects they store
+// serialized, and to decode them on access.
+type SerializedStore struct {
+	// Compress compresses the serialized objects.
+	Compress bool
+}
+
+// protoMessage is implemented by the generated types of the Kubernetes API,
+// which are serialized with protobuf rather than JSON.
+type protoMessage interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// SerializedObject stands for an object in the store of an informer of a
+// SerializedStore. It only keeps the metadata needed to key, index and
+// select objects decoded, along with the serialized object.
+type SerializedObject struct {
+	metav1.ObjectMeta
+
+	typ        reflect.Type
+	data       []byte
+	proto      bool
+	compressed bool
+}
+
+// GetObjectKind implements runtime.Object.
+func (o *SerializedObject) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+// DeepCopyObject implements runtime.Object. SerializedObjects are immutable,
+// so it returns o.
+func (o *SerializedObject) DeepCopyObject() runtime.Object {
+	return o
+}
+
+// Size returns the size of the serialized object.
+func (o *SerializedObject) Size() int {
+	return len(o.data)
+}
+
+// Decode returns a new copy of the object.
+func (o *SerializedObject) Decode() (runtime.Object, error) {
+	data := o.data
+	if o.compressed {
+		r := flate.NewReader(bytes.NewReader(data))
+		defer r.Close()
+		var err error
+		if data, err = io.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("failed to decompress %s %s: %w", o.typ, cache.MetaObjectToName(o), err)
+		}
+	}
+	obj := reflect.New(o.typ.Elem()).Interface().(runtime.Object)
+	var err error
+	if o.proto {
+		err = obj.(protoMessage).Unmarshal(data)
+	} else {
+		err = json.Unmarshal(data, obj)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", o.typ, cache.MetaObjectToName(o), err)
+	}
+	return obj, nil
+}
+
+var flateWriters = sync.Pool{
+	New: func() interface{} {
+		w, _ := flate.NewWriter(nil, flate.BestSpeed)
+		return w
+	},
+}
+
+// serialize returns the SerializedObject standing for obj.
+func (s *SerializedStore) serialize(obj runtime.Object) (*SerializedObject, error) {
+	objMeta, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("%T is not an object", obj)
+	}
+	out := &SerializedObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              objMeta.GetName(),
+			Namespace:         objMeta.GetNamespace(),
+			UID:               objMeta.GetUID(),
+			ResourceVersion:   objMeta.GetResourceVersion(),
+			Labels:            objMeta.GetLabels(),
+			DeletionTimestamp: objMeta.GetDeletionTimestamp(),
+		},
+		typ: reflect.TypeOf(obj),
+	}
+
+	var err error
+	if msg, ok := obj.(protoMessage); ok {
+		out.data, err = msg.Marshal()
+		out.proto = true
+	} else {
+		out.data, err = json.Marshal(obj)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize %T %s: %w", obj, cache.MetaObjectToName(objMeta), err)
+	}
+
+	if s.Compress {
+		var buf bytes.Buffer
+		w := flateWriters.Get().(*flate.Writer)
+		defer flateWriters.Put(w)
+		w.Reset(&buf)
+		if _, err := w.Write(out.data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		out.data = bytes.Clone(buf.Bytes())
+		out.compressed = true
+	}
+	return out, nil
+}
+
+// transform returns a TransformFunc serializing the objects after
+// transforming them with next, if set. It is idempotent, as required by
+// informers.
+func (s *SerializedStore) transform(next cache.TransformFunc) cache.TransformFunc {
+	return func(in interface{}) (interface{}, error) {
+		if _, ok := in.(*SerializedObject); ok {
+			return in, nil
+		}
+		if next != nil {
+			var err error
+			if in, err = next(in); err != nil {
+				return nil, err
+			}
+		}
+		obj, ok := in.(runtime.Object)
+		if !ok {
+			return in, nil
+		}
+		return s.serialize(obj)
+	}
+}
+
+// Deserialize returns the object obj stands for if it is a SerializedObject,
+// or a DeletedFinalStateUnknown holding one, and obj otherwise.
+func Deserialize(obj interface{}) (interface{}, error) {
+	switch o := obj.(type) {
+	case *SerializedObject:
+		return o.Decode()
+	case cache.DeletedFinalStateUnknown:
+		inner, err := Deserialize(o.Obj)
+		if err != nil {
+			return nil, err
+		}
+		o.Obj = inner
+		return o, nil
+	default:
+		return obj, nil
+	}
+}
+
+// serializedInformer wraps the informer of a SerializedStore, so that its
+// event handlers and indexers receive decoded objects.
+type serializedInformer struct {
+	cache.SharedIndexInformer
+}
+
+// AddEventHandler implements cache.SharedInformer.
+func (i *serializedInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	return i.SharedIndexInformer.AddEventHandler(decodingHandler(handler))
+}
+
+// AddEventHandlerWithResyncPeriod implements cache.SharedInformer.
+func (i *serializedInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	return i.SharedIndexInformer.AddEventHandlerWithResyncPeriod(decodingHandler(handler), resyncPeriod)
+}
+
+// AddIndexers implements cache.SharedIndexInformer.
+func (i *serializedInformer) AddIndexers(indexers cache.Indexers) error {
+	decoding := make(cache.Indexers, len(indexers))
+	for name, indexFunc := range indexers {
+		decoding[name] = func(obj interface{}) ([]string, error) {
+			obj, err := Deserialize(obj)
+			if err != nil {
+				return nil, err
+			}
+			return indexFunc(obj)
+		}
+	}
+	return i.SharedIndexInformer.AddIndexers(decoding)
+}
+
+func decodingHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+	decode := func(obj interface{}) (interface{}, bool) {
+		decoded, err := Deserialize(obj)
+		if err != nil {
+			log.Error(err, "Failed to decode serialized object")
+			return nil, false
+		}
+		return decoded, true
+	}
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if obj, ok := decode(obj); ok {
+				handler.OnAdd(obj, isInInitialList)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldObj, oldOK := decode(oldObj)
+			newObj, newOK := decode(newObj)
+			if oldOK && newOK {
+				handler.OnUpdate(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if obj, ok := decode(obj); ok {
+				handler.OnDelete(obj)
+			}
+		},
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type jsonObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              string `json:"spec"`
+}
+
+func (o *jsonObject) DeepCopyObject() runtime.Object {
+	out := *o
+	o.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+var _ = Describe("SerializedStore", func() {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Labels: map[string]string{"app": "foo"}, ResourceVersion: "1"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+	}
+
+	for _, compress := range []bool{false, true} {
+		It("should serialize and decode objects", func() {
+			transform := (&SerializedStore{Compress: compress}).transform(nil)
+			out, err := transform(pod)
+			Expect(err).NotTo(HaveOccurred())
+			serialized := out.(*SerializedObject)
+			Expect(serialized.proto).To(BeTrue())
+			Expect(serialized.compressed).To(Equal(compress))
+			Expect(serialized.GetName()).To(Equal("foo"))
+			Expect(serialized.GetLabels()).To(Equal(pod.Labels))
+
+			again, err := transform(serialized)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again).To(BeIdenticalTo(serialized))
+
+			decoded, err := Deserialize(serialized)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(pod))
+		})
+	}
+
+	It("should serialize objects that don't support protobuf with JSON", func() {
+		obj := &jsonObject{ObjectMeta: metav1.ObjectMeta{Name: "foo"}, Spec: "spec"}
+		out, err := (&SerializedStore{Compress: true}).transform(nil)(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.(*SerializedObject).proto).To(BeFalse())
+
+		decoded, err := Deserialize(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(obj))
+	})
+
+	It("should apply the transform before serializing", func() {
+		transform := (&SerializedStore{}).transform(func(in interface{}) (interface{}, error) {
+			obj := in.(*corev1.Pod).DeepCopy()
+			obj.Spec = corev1.PodSpec{}
+			return obj, nil
+		})
+		out, err := transform(pod)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := Deserialize(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.(*corev1.Pod).Spec).To(Equal(corev1.PodSpec{}))
+	})
+
+	It("should decode the objects read from the store", func() {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		serialized, err := (&SerializedStore{Compress: true}).serialize(pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(indexer.Add(serialized)).To(Succeed())
+		gvk := corev1.SchemeGroupVersion.WithKind("Pod")
+		reader := NewCacheReader(indexer, gvk, meta.RESTScopeNameNamespace, false)
+
+		got := &corev1.Pod{}
+		Expect(reader.Get(context.Background(), client.ObjectKeyFromObject(pod), got)).To(Succeed())
+		Expect(got.Spec).To(Equal(pod.Spec))
+		Expect(got.GroupVersionKind()).To(Equal(gvk))
+
+		list := &corev1.PodList{}
+		Expect(reader.List(context.Background(), list, client.InNamespace("default"), client.MatchingLabels{"app": "foo"})).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Spec).To(Equal(pod.Spec))
+		Expect(list.Items[0].GroupVersionKind()).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+	})
+
+	It("should decode the objects handed to event handlers", func() {
+		serialized, err := (&SerializedStore{}).serialize(pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		var added, deleted interface{}
+		handler := decodingHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { added = obj },
+			DeleteFunc: func(obj interface{}) { deleted = obj },
+		})
+		handler.OnAdd(serialized, false)
+		Expect(added).To(Equal(pod))
+
+		handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/foo", Obj: serialized})
+		Expect(deleted).To(Equal(cache.DeletedFinalStateUnknown{Key: "default/foo", Obj: pod}))
+	})
+})
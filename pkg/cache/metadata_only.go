@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// metadataOnlyKinds returns the GroupVersionKinds of objs.
+func metadataOnlyKinds(objs []client.Object, scheme *runtime.Scheme) (map[schema.GroupVersionKind]struct{}, error) {
+	if len(objs) == 0 {
+		return nil, nil
+	}
+	kinds := make(map[schema.GroupVersionKind]struct{}, len(objs))
+	for _, obj := range objs {
+		switch obj.(type) {
+		case runtime.Unstructured, *metav1.PartialObjectMetadata:
+			return nil, fmt.Errorf("WatchAsMetadata only supports typed objects, got %T", obj)
+		}
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GVK for type %T: %w", obj, err)
+		}
+		kinds[gvk] = struct{}{}
+	}
+	return kinds, nil
+}
+
+// asMetadata returns the PartialObjectMetadata that stands for obj in the
+// informers of the cache, if obj is a typed object of a kind watched as
+// metadata.
+func (ic *informerCache) asMetadata(gvk schema.GroupVersionKind, obj runtime.Object) (*metav1.PartialObjectMetadata, bool) {
+	if _, ok := ic.metadataOnly[gvk]; !ok {
+		return nil, false
+	}
+	switch obj.(type) {
+	case runtime.Unstructured, *metav1.PartialObjectMetadata:
+		return nil, false
+	}
+	pom := &metav1.PartialObjectMetadata{}
+	pom.SetGroupVersionKind(gvk)
+	return pom, true
+}
+
+// metadataInto sets out to an object of kind gvk holding only the type and
+// object metadata of pom.
+func metadataInto(pom *metav1.PartialObjectMetadata, gvk schema.GroupVersionKind, out runtime.Object) error {
+	objectMeta, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pom.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(out).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{
+		"metadata": objectMeta,
+	}, out); err != nil {
+		return err
+	}
+	out.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+// fromMetadata returns a new object of kind gvk holding only the metadata
+// of obj, if it is a PartialObjectMetadata.
+func (ic *informerCache) fromMetadata(gvk schema.GroupVersionKind, obj interface{}) (interface{}, error) {
+	pom, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return obj, nil
+	}
+	out, err := ic.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := metadataInto(pom, gvk, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// listFromMetadata sets the items of out to objects of kind gvk holding
+// only the metadata of the items of list.
+func (ic *informerCache) listFromMetadata(list *metav1.PartialObjectMetadataList, gvk schema.GroupVersionKind, out client.ObjectList) error {
+	items := make([]runtime.Object, 0, len(list.Items))
+	for i := range list.Items {
+		item, err := ic.scheme.New(gvk)
+		if err != nil {
+			return err
+		}
+		if err := metadataInto(&list.Items[i], gvk, item); err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+	if err := apimeta.SetList(out, items); err != nil {
+		return err
+	}
+	out.SetResourceVersion(list.GetResourceVersion())
+	out.SetContinue(list.GetContinue())
+	return nil
+}
+
+// metadataInformer wraps the metadata informer of a kind watched as
+// metadata, so that its event handlers and indexers receive typed objects
+// holding only the metadata.
+type metadataInformer struct {
+	Informer
+	gvk   schema.GroupVersionKind
+	cache *informerCache
+}
+
+// AddEventHandler implements Informer.
+func (i *metadataInformer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandler(i.convertingHandler(handler))
+}
+
+// AddEventHandlerWithResyncPeriod implements Informer.
+func (i *metadataInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandlerWithResyncPeriod(i.convertingHandler(handler), resyncPeriod)
+}
+
+// AddIndexers implements Informer.
+func (i *metadataInformer) AddIndexers(indexers toolscache.Indexers) error {
+	converting := make(toolscache.Indexers, len(indexers))
+	for name, indexFunc := range indexers {
+		converting[name] = func(obj interface{}) ([]string, error) {
+			obj, err := i.cache.fromMetadata(i.gvk, obj)
+			if err != nil {
+				return nil, err
+			}
+			return indexFunc(obj)
+		}
+	}
+	return i.Informer.AddIndexers(converting)
+}
+
+func (i *metadataInformer) convertingHandler(handler toolscache.ResourceEventHandler) toolscache.ResourceEventHandler {
+	convert := func(obj interface{}) (interface{}, bool) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			converted, ok := i.convert(tombstone.Obj)
+			tombstone.Obj = converted
+			return tombstone, ok
+		}
+		return i.convert(obj)
+	}
+	return toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if obj, ok := convert(obj); ok {
+				handler.OnAdd(obj, isInInitialList)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldObj, oldOK := convert(oldObj)
+			newObj, newOK := convert(newObj)
+			if oldOK && newOK {
+				handler.OnUpdate(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if obj, ok := convert(obj); ok {
+				handler.OnDelete(obj)
+			}
+		},
+	}
+}
+
+func (i *metadataInformer) convert(obj interface{}) (interface{}, bool) {
+	converted, err := i.cache.fromMetadata(i.gvk, obj)
+	if err != nil {
+		log.Error(err, "Failed to convert object watched as metadata", "gvk", i.gvk)
+		return nil, false
+	}
+	return converted, true
+}
@@ -19,6 +19,8 @@ package cache
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -59,6 +61,8 @@ func newMultiNamespaceCache(
 		Scheme:           scheme,
 		RESTMapper:       restMapper,
 		clusterCache:     clusterCache,
+		newCache:         newCache,
+		namespaceConfig:  globalConfig,
 	}
 }
 
@@ -67,10 +71,37 @@ func newMultiNamespaceCache(
 // operator to a list of namespaces instead of watching every namespace
 // in the cluster.
 type multiNamespaceCache struct {
-	Scheme           *runtime.Scheme
-	RESTMapper       apimeta.RESTMapper
+	Scheme     *runtime.Scheme
+	RESTMapper apimeta.RESTMapper
+	// namespaceToCache is replaced, rather than modified, when namespaces
+	// are added or removed. It must be read with caches.
 	namespaceToCache map[string]Cache
 	clusterCache     Cache
+
+	// newCache and namespaceConfig create the caches of the namespaces
+	// added with AddNamespace. namespaceConfig is nil if namespaces can't
+	// be added.
+	newCache        newCacheFunc
+	namespaceConfig *Config
+
+	// mu guards namespaceToCache and the fields below.
+	mu sync.RWMutex
+	// ctx is the context the cache was started with, nil until then.
+	ctx context.Context
+	// cancels stop the caches of the namespaces.
+	cancels map[string]context.CancelFunc
+	// informers are the informers returned for all the namespaces, which are
+	// extended to the namespaces added later.
+	informers map[informerKey]trackedInformer
+	// indexes are the fields indexed with IndexField.
+	indexes []fieldIndex
+}
+
+// caches returns the caches of the namespaces.
+func (c *multiNamespaceCache) caches() map[string]Cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.namespaceToCache
 }
 
 var _ Cache = &multiNamespaceCache{}
@@ -97,16 +128,14 @@ func (c *multiNamespaceCache) GetInformer(ctx context.Context, obj client.Object
 		}, nil
 	}
 
-	namespaceToInformer := map[string]Informer{}
-	for ns, cache := range c.namespaceToCache {
-		informer, err := cache.GetInformer(ctx, obj, opts...)
-		if err != nil {
-			return nil, err
-		}
-		namespaceToInformer[ns] = informer
+	key, err := informerKeyFor(obj, c.Scheme)
+	if err != nil {
+		return nil, err
 	}
-
-	return &multiNamespaceInformer{namespaceToInformer: namespaceToInformer}, nil
+	template := obj.DeepCopyObject().(client.Object)
+	return c.getInformer(ctx, key, opts, func(ctx context.Context, cache Cache, opts ...InformerGetOption) (Informer, error) {
+		return cache.GetInformer(ctx, template, opts...)
+	})
 }
 
 func (c *multiNamespaceCache) RemoveInformer(ctx context.Context, obj client.Object) error {
@@ -120,12 +149,13 @@ func (c *multiNamespaceCache) RemoveInformer(ctx context.Context, obj client.Obj
 		return c.clusterCache.RemoveInformer(ctx, obj)
 	}
 
-	for _, cache := range c.namespaceToCache {
+	for _, cache := range c.caches() {
 		err := cache.RemoveInformer(ctx, obj)
 		if err != nil {
 			return err
 		}
 	}
+	c.untrackInformer(obj)
 
 	return nil
 }
@@ -150,20 +180,67 @@ func (c *multiNamespaceCache) GetInformerForKind(ctx context.Context, gvk schema
 		}, nil
 	}
 
+	return c.getInformer(ctx, informerKey{gvk: gvk}, opts, func(ctx context.Context, cache Cache, opts ...InformerGetOption) (Informer, error) {
+		return cache.GetInformerForKind(ctx, gvk, opts...)
+	})
+}
+
+// getInformer gets the informers of the namespaces with get. Informers that
+// are not restricted to some namespaces are tracked, so that they are
+// extended to the namespaces added later.
+func (c *multiNamespaceCache) getInformer(ctx context.Context, key informerKey, opts []InformerGetOption, get informerGetter) (Informer, error) {
 	namespaceToInformer := map[string]Informer{}
-	for ns, cache := range c.namespaceToCache {
-		informer, err := cache.GetInformerForKind(ctx, gvk, opts...)
+	for ns, cache := range c.caches() {
+		opts, ok := c.restrictInformerGetOptions(ns, opts)
+		if !ok {
+			continue
+		}
+		informer, err := get(ctx, cache, opts...)
 		if err != nil {
 			return nil, err
 		}
 		namespaceToInformer[ns] = informer
 	}
+	// Caches of discovered namespaces may have no namespace yet.
+	if namespaces := applyGetOptions(opts...).Namespaces; len(namespaceToInformer) == 0 && len(namespaces) > 0 {
+		return nil, fmt.Errorf("none of the namespaces %v is cached", namespaces)
+	}
+	if isRestricted(applyGetOptions(opts...)) {
+		return &multiNamespaceInformer{namespaceToInformer: namespaceToInformer}, nil
+	}
+	return c.trackInformer(ctx, key, get, namespaceToInformer)
+}
 
-	return &multiNamespaceInformer{namespaceToInformer: namespaceToInformer}, nil
+// restrictInformerGetOptions returns the options to get the informer of the
+// cache of namespace ns with, and false if the namespaces the informer is
+// restricted to by opts don't include ns.
+func (c *multiNamespaceCache) restrictInformerGetOptions(ns string, opts []InformerGetOption) ([]InformerGetOption, bool) {
+	namespaces := applyGetOptions(opts...).Namespaces
+	if len(namespaces) == 0 {
+		return opts, true
+	}
+	if ns != metav1.NamespaceAll {
+		return opts, slices.Contains(namespaces, ns)
+	}
+	// The cache of all namespaces excludes the namespaces of the other
+	// caches.
+	var others []string
+	for _, namespace := range namespaces {
+		if _, ok := c.caches()[namespace]; !ok {
+			others = append(others, namespace)
+		}
+	}
+	if len(others) == 0 {
+		return nil, false
+	}
+	return append(slices.Clone(opts), func(o *InformerGetOptions) { o.Namespaces = others }), true
 }
 
 func (c *multiNamespaceCache) Start(ctx context.Context) error {
 	errs := make(chan error)
+	c.mu.Lock()
+	c.ctx = ctx
+	c.cancels = make(map[string]context.CancelFunc, len(c.namespaceToCache))
 	// start global cache
 	if c.clusterCache != nil {
 		go func() {
@@ -176,12 +253,15 @@ func (c *multiNamespaceCache) Start(ctx context.Context) error {
 
 	// start namespaced caches
 	for ns, cache := range c.namespaceToCache {
+		nsCtx, cancel := context.WithCancel(ctx)
+		c.cancels[ns] = cancel
 		go func(ns string, cache Cache) {
-			if err := cache.Start(ctx); err != nil {
+			if err := cache.Start(nsCtx); err != nil {
 				errs <- fmt.Errorf("failed to start cache for namespace %s: %w", ns, err)
 			}
 		}(ns, cache)
 	}
+	c.mu.Unlock()
 	select {
 	case <-ctx.Done():
 		return nil
@@ -192,7 +272,7 @@ func (c *multiNamespaceCache) Start(ctx context.Context) error {
 
 func (c *multiNamespaceCache) WaitForCacheSync(ctx context.Context) bool {
 	synced := true
-	for _, cache := range c.namespaceToCache {
+	for _, cache := range c.caches() {
 		if !cache.WaitForCacheSync(ctx) {
 			synced = false
 		}
@@ -215,7 +295,13 @@ func (c *multiNamespaceCache) IndexField(ctx context.Context, obj client.Object,
 		return c.clusterCache.IndexField(ctx, obj, field, extractValue)
 	}
 
-	for _, cache := range c.namespaceToCache {
+	// The index is recorded along with the snapshot of the caches to index,
+	// so that the caches of namespaces added concurrently are indexed once.
+	c.mu.Lock()
+	c.indexes = append(c.indexes, fieldIndex{obj: obj, field: field, extractValue: extractValue})
+	caches := c.namespaceToCache
+	c.mu.Unlock()
+	for _, cache := range caches {
 		if err := cache.IndexField(ctx, obj, field, extractValue); err != nil {
 			return err
 		}
@@ -234,9 +320,10 @@ func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj
 		return c.clusterCache.Get(ctx, key, obj)
 	}
 
-	cache, ok := c.namespaceToCache[key.Namespace]
+	caches := c.caches()
+	cache, ok := caches[key.Namespace]
 	if !ok {
-		if global, hasGlobal := c.namespaceToCache[metav1.NamespaceAll]; hasGlobal {
+		if global, hasGlobal := caches[metav1.NamespaceAll]; hasGlobal {
 			return global.Get(ctx, key, obj, opts...)
 		}
 		return fmt.Errorf("unable to get: %v because of unknown namespace for the cache", key)
@@ -260,7 +347,7 @@ func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList,
 	}
 
 	if listOpts.Namespace != corev1.NamespaceAll {
-		cache, ok := c.namespaceToCache[listOpts.Namespace]
+		cache, ok := c.caches()[listOpts.Namespace]
 		if !ok {
 			return fmt.Errorf("unable to list: %v because of unknown namespace for the cache", listOpts.Namespace)
 		}
@@ -280,7 +367,7 @@ func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList,
 	limitSet := listOpts.Limit > 0
 
 	var resourceVersion string
-	for _, cache := range c.namespaceToCache {
+	for _, cache := range c.caches() {
 		listObj := list.DeepCopyObject().(client.ObjectList)
 		err = cache.List(ctx, listObj, &listOpts)
 		if err != nil {
@@ -318,10 +405,21 @@ func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList,
 
 // multiNamespaceInformer knows how to handle interacting with the underlying informer across multiple namespaces.
 type multiNamespaceInformer struct {
+	// mu guards the fields below, which change when namespaces are added to
+	// or removed from the cache.
+	mu                  sync.RWMutex
 	namespaceToInformer map[string]Informer
+	// registrations and indexers are added to the informers of the
+	// namespaces added later.
+	registrations []*handlerRegistration
+	indexers      []toolscache.Indexers
 }
 
 type handlerRegistration struct {
+	handler      toolscache.ResourceEventHandler
+	resyncPeriod *time.Duration
+
+	mu      sync.RWMutex
 	handles map[string]toolscache.ResourceEventHandlerRegistration
 }
 
@@ -331,7 +429,9 @@ type syncer interface {
 
 // HasSynced asserts that the handler has been called for the full initial state of the informer.
 // This uses syncer to be compatible between client-go 1.27+ and older versions when the interface changed.
-func (h handlerRegistration) HasSynced() bool {
+func (h *handlerRegistration) HasSynced() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	for _, reg := range h.handles {
 		if s, ok := reg.(syncer); ok {
 			if !s.HasSynced() {
@@ -342,50 +442,61 @@ func (h handlerRegistration) HasSynced() bool {
 	return true
 }
 
+// addTo adds the handler to the informer of namespace ns.
+func (h *handlerRegistration) addTo(ns string, informer Informer) error {
+	var registration toolscache.ResourceEventHandlerRegistration
+	var err error
+	if h.resyncPeriod != nil {
+		registration, err = informer.AddEventHandlerWithResyncPeriod(h.handler, *h.resyncPeriod)
+	} else {
+		registration, err = informer.AddEventHandler(h.handler)
+	}
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handles[ns] = registration
+	return nil
+}
+
 var _ Informer = &multiNamespaceInformer{}
 
 // AddEventHandler adds the handler to each informer.
 func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
-	handles := handlerRegistration{
-		handles: make(map[string]toolscache.ResourceEventHandlerRegistration, len(i.namespaceToInformer)),
-	}
-
-	for ns, informer := range i.namespaceToInformer {
-		registration, err := informer.AddEventHandler(handler)
-		if err != nil {
-			return nil, err
-		}
-		handles.handles[ns] = registration
-	}
-
-	return handles, nil
+	return i.addEventHandler(&handlerRegistration{handler: handler})
 }
 
 // AddEventHandlerWithResyncPeriod adds the handler with a resync period to each namespaced informer.
 func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
-	handles := handlerRegistration{
-		handles: make(map[string]toolscache.ResourceEventHandlerRegistration, len(i.namespaceToInformer)),
-	}
+	return i.addEventHandler(&handlerRegistration{handler: handler, resyncPeriod: &resyncPeriod})
+}
 
+func (i *multiNamespaceInformer) addEventHandler(handles *handlerRegistration) (toolscache.ResourceEventHandlerRegistration, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	handles.handles = make(map[string]toolscache.ResourceEventHandlerRegistration, len(i.namespaceToInformer))
 	for ns, informer := range i.namespaceToInformer {
-		registration, err := informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
-		if err != nil {
+		if err := handles.addTo(ns, informer); err != nil {
 			return nil, err
 		}
-		handles.handles[ns] = registration
 	}
-
+	i.registrations = append(i.registrations, handles)
 	return handles, nil
 }
 
 // RemoveEventHandler removes a previously added event handler given by its registration handle.
 func (i *multiNamespaceInformer) RemoveEventHandler(h toolscache.ResourceEventHandlerRegistration) error {
-	handles, ok := h.(handlerRegistration)
+	handles, ok := h.(*handlerRegistration)
 	if !ok {
 		return fmt.Errorf("registration is not a registration returned by multiNamespaceInformer")
 	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	for ns, informer := range i.namespaceToInformer {
+		handles.mu.RLock()
 		registration, ok := handles.handles[ns]
+		handles.mu.RUnlock()
 		if !ok {
 			continue
 		}
@@ -393,22 +504,28 @@ func (i *multiNamespaceInformer) RemoveEventHandler(h toolscache.ResourceEventHa
 			return err
 		}
 	}
+	i.registrations = slices.DeleteFunc(i.registrations, func(r *handlerRegistration) bool { return r == handles })
 	return nil
 }
 
 // AddIndexers adds the indexers to each informer.
 func (i *multiNamespaceInformer) AddIndexers(indexers toolscache.Indexers) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	for _, informer := range i.namespaceToInformer {
 		err := informer.AddIndexers(indexers)
 		if err != nil {
 			return err
 		}
 	}
+	i.indexers = append(i.indexers, indexers)
 	return nil
 }
 
 // HasSynced checks if each informer has synced.
 func (i *multiNamespaceInformer) HasSynced() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	for _, informer := range i.namespaceToInformer {
 		if !informer.HasSynced() {
 			return false
@@ -419,6 +536,8 @@ func (i *multiNamespaceInformer) HasSynced() bool {
 
 // IsStopped checks if each namespaced informer has stopped, returns false if any are still running.
 func (i *multiNamespaceInformer) IsStopped() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	for _, informer := range i.namespaceToInformer {
 		if stopped := informer.IsStopped(); !stopped {
 			return false
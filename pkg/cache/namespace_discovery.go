@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// NamespaceEvents is implemented by caches created with a NamespaceSelector.
+type NamespaceEvents interface {
+	// NamespaceEvents returns a channel receiving an event with the
+	// Namespace, as a metav1.PartialObjectMetadata, each time a namespace is
+	// included in the cache, once its objects are synced, or excluded from
+	// it. It can be watched with source.Channel to requeue the objects of
+	// the namespaces, e.g. to clean up after the namespaces that are
+	// excluded, since no delete events are emitted for their objects. Each
+	// call returns a new channel, which must be consumed.
+	NamespaceEvents() <-chan event.GenericEvent
+}
+
+// namespaceDiscoveringCache watches the namespaces matching a selector, and
+// includes them in, or excludes them from, a multi-namespace cache.
+type namespaceDiscoveringCache struct {
+	Cache
+	dynamic DynamicNamespaces
+	// namespaces caches the namespaces matching the selector.
+	namespaces Cache
+	queue      workqueue.TypedRateLimitingInterface[string]
+
+	// ready is closed once the namespaces that match the selector when the
+	// cache starts have been included.
+	ready chan struct{}
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// matching holds the namespaces matching the selector, and their
+	// objects.
+	matching    map[string]*metav1.PartialObjectMetadata
+	subscribers []chan event.GenericEvent
+}
+
+var _ NamespaceEvents = &namespaceDiscoveringCache{}
+
+func newNamespaceDiscoveringCache(c Cache, newCache newCacheFunc, selector labels.Selector) (Cache, error) {
+	dynamic, err := dynamicNamespacesOf(c)
+	if err != nil {
+		return nil, err
+	}
+	discovering := &namespaceDiscoveringCache{
+		Cache:      c,
+		dynamic:    dynamic,
+		namespaces: newCache(Config{LabelSelector: selector}, corev1.NamespaceAll),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](), workqueue.TypedRateLimitingQueueConfig[string]{
+			Name: "namespace-discovery",
+		}),
+		ready:    make(chan struct{}),
+		matching: map[string]*metav1.PartialObjectMetadata{},
+	}
+	if deleted, ok := c.(DeletedNamespaces); ok {
+		return &deletedNamespacesDiscoveringCache{namespaceDiscoveringCache: discovering, DeletedNamespaces: deleted}, nil
+	}
+	return discovering, nil
+}
+
+// deletedNamespacesDiscoveringCache is a namespaceDiscoveringCache wrapping
+// a cache that tracks deleted namespaces.
+type deletedNamespacesDiscoveringCache struct {
+	*namespaceDiscoveringCache
+	DeletedNamespaces
+}
+
+// Start implements Informers. It includes the namespaces that match the
+// selector before starting the cache.
+func (c *namespaceDiscoveringCache) Start(ctx context.Context) error {
+	namespaces := &metav1.PartialObjectMetadata{}
+	namespaces.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	informer, err := c.namespaces.GetInformer(ctx, namespaces, BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ns, ok := obj.(*metav1.PartialObjectMetadata); ok {
+				c.setMatching(ns, true)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if ns, ok := obj.(*metav1.PartialObjectMetadata); ok {
+				c.setMatching(ns, false)
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		if err := c.namespaces.Start(ctx); err != nil {
+			errs <- fmt.Errorf("failed to start namespace cache: %w", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		c.queue.ShutDown()
+	}()
+	if !c.namespaces.WaitForCacheSync(ctx) {
+		return nil
+	}
+	// The cache isn't started yet, so including namespaces doesn't wait for
+	// them to sync.
+	// No events are sent for them, since their objects get Create events.
+	for c.queue.Len() > 0 {
+		c.processNextNamespace(ctx, false)
+	}
+	close(c.ready)
+	go func() {
+		for c.processNextNamespace(ctx, true) {
+		}
+	}()
+
+	go func() {
+		if err := c.Cache.Start(ctx); err != nil {
+			errs <- err
+		}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errs:
+		return err
+	}
+}
+
+// WaitForCacheSync implements Informers. It waits for the namespaces that
+// match the selector when the cache starts to be included.
+func (c *namespaceDiscoveringCache) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-c.ready:
+	case <-ctx.Done():
+		return false
+	}
+	return c.Cache.WaitForCacheSync(ctx)
+}
+
+// NamespaceEvents implements NamespaceEvents.
+func (c *namespaceDiscoveringCache) NamespaceEvents() <-chan event.GenericEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan event.GenericEvent, 16)
+	c.subscribers = append(c.subscribers, ch)
+	return ch
+}
+
+func (c *namespaceDiscoveringCache) setMatching(ns *metav1.PartialObjectMetadata, matching bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if matching {
+		c.matching[ns.Name] = ns
+	} else {
+		delete(c.matching, ns.Name)
+	}
+	c.queue.Add(ns.Name)
+}
+
+// processNextNamespace includes or excludes the next namespace of the queue,
+// depending on whether it matches the selector.
+func (c *namespaceDiscoveringCache) processNextNamespace(ctx context.Context, notify bool) bool {
+	name, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(name)
+
+	c.mu.Lock()
+	ns, matching := c.matching[name]
+	c.mu.Unlock()
+
+	var err error
+	if matching {
+		log.V(1).Info("Including namespace in the cache", "namespace", name)
+		err = c.dynamic.AddNamespace(ctx, name)
+	} else {
+		log.V(1).Info("Excluding namespace from the cache", "namespace", name)
+		err = c.dynamic.RemoveNamespace(name)
+		ns = &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error(err, "Failed to update the namespaces of the cache", "namespace", name)
+			c.queue.AddRateLimited(name)
+		}
+		return true
+	}
+	c.queue.Forget(name)
+	if notify {
+		c.notify(ctx, ns)
+	}
+	return true
+}
+
+func (c *namespaceDiscoveringCache) notify(ctx context.Context, ns *metav1.PartialObjectMetadata) {
+	c.mu.Lock()
+	subscribers := c.subscribers
+	c.mu.Unlock()
+	for _, ch := range subscribers {
+		select {
+		case ch <- event.GenericEvent{Object: ns}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *namespaceDiscoveringCache) objectStats(sampleSize int) map[schema.GroupVersionKind]internal.ObjectStats {
+	if reporter, ok := c.Cache.(statsReporter); ok {
+		return reporter.objectStats(sampleSize)
+	}
+	return nil
+}
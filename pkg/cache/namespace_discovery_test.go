@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// namespaceRecordingCache records the namespaces added and removed.
+type namespaceRecordingCache struct {
+	fakeNamespaceCache
+	mu         sync.Mutex
+	namespaces []string
+}
+
+func (c *namespaceRecordingCache) AddNamespace(_ context.Context, namespace string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.namespaces = append(c.namespaces, namespace)
+	return nil
+}
+
+func (c *namespaceRecordingCache) RemoveNamespace(namespace string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.namespaces = append(c.namespaces, "-"+namespace)
+	return nil
+}
+
+func (c *namespaceRecordingCache) recorded() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.namespaces...)
+}
+
+var _ = Describe("namespace discovery", func() {
+	var (
+		inner      *namespaceRecordingCache
+		namespaces *fakeNamespaceCache
+		c          *namespaceDiscoveringCache
+	)
+
+	namespace := func(name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	BeforeEach(func() {
+		inner = &namespaceRecordingCache{fakeNamespaceCache: fakeNamespaceCache{started: make(chan struct{}), stopped: make(chan struct{})}}
+		namespaces = &fakeNamespaceCache{started: make(chan struct{}), stopped: make(chan struct{}), synced: make(chan struct{})}
+		discovering, err := newNamespaceDiscoveringCache(inner, func(Config, string) Cache { return namespaces }, labels.Everything())
+		Expect(err).NotTo(HaveOccurred())
+		c = discovering.(*namespaceDiscoveringCache)
+	})
+
+	It("should include and exclude the namespaces matching the selector", func() {
+		events := c.NamespaceEvents()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Eventually(namespaces.started).Should(BeClosed())
+		namespaces.informer.Add(namespace("initial"))
+		close(namespaces.synced)
+		Expect(c.WaitForCacheSync(ctx)).To(BeTrue())
+		Expect(inner.recorded()).To(Equal([]string{"initial"}))
+		Eventually(inner.started).Should(BeClosed())
+
+		namespaces.informer.Add(namespace("added"))
+		Eventually(events).Should(Receive(HaveField("Object.GetName()", "added")))
+		namespaces.informer.Delete(namespace("initial"))
+		Eventually(events).Should(Receive(HaveField("Object.GetName()", "initial")))
+		Expect(inner.recorded()).To(Equal([]string{"initial", "added", "-initial"}))
+	})
+
+	It("should require a cache of dynamic namespaces", func() {
+		_, err := newNamespaceDiscoveringCache(&fakeNamespaceCache{}, nil, labels.Everything())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+var log = logf.RuntimeLog.WithName("cache")
+
+// DeletedNamespaces is implemented by caches created with
+// EvictDeletedNamespaces.
+type DeletedNamespaces interface {
+	// IsNamespaceDeleted returns whether the namespace has been deleted,
+	// and not created again since.
+	IsNamespaceDeleted(namespace string) bool
+}
+
+// namespaceEvicter is implemented by caches that can evict the objects of a
+// namespace.
+type namespaceEvicter interface {
+	evictNamespace(namespace string) int
+}
+
+func (ic *informerCache) evictNamespace(namespace string) int {
+	return ic.Informers.EvictNamespace(namespace)
+}
+
+func (c *multiNamespaceCache) evictNamespace(namespace string) int {
+	evicted := 0
+	for _, cache := range c.caches() {
+		if evicter, ok := cache.(namespaceEvicter); ok {
+			evicted += evicter.evictNamespace(namespace)
+		}
+	}
+	return evicted
+}
+
+func (dbt *delegatingByGVKCache) evictNamespace(namespace string) int {
+	evicted := 0
+	for _, cache := range append([]Cache{dbt.defaultCache}, maps.Values(dbt.caches)...) {
+		if evicter, ok := cache.(namespaceEvicter); ok {
+			evicted += evicter.evictNamespace(namespace)
+		}
+	}
+	return evicted
+}
+
+// namespaceEvictingCache watches namespaces, and evicts the objects of the
+// namespaces that start terminating.
+type namespaceEvictingCache struct {
+	Cache
+	evicter namespaceEvicter
+
+	mu sync.RWMutex
+	// terminating holds the namespaces whose objects were evicted, until
+	// they are deleted.
+	terminating sets.Set[string]
+	// deleted holds the namespaces that were deleted, until they are
+	// created again.
+	deleted sets.Set[string]
+}
+
+var _ DeletedNamespaces = &namespaceEvictingCache{}
+
+func newNamespaceEvictingCache(c Cache) Cache {
+	evicter, ok := c.(namespaceEvicter)
+	if !ok {
+		return c
+	}
+	return &namespaceEvictingCache{
+		Cache:       c,
+		evicter:     evicter,
+		terminating: sets.New[string](),
+		deleted:     sets.New[string](),
+	}
+}
+
+// Start implements Informers. It watches namespaces before starting the
+// cache.
+func (c *namespaceEvictingCache) Start(ctx context.Context) error {
+	namespaces := &metav1.PartialObjectMetadata{}
+	namespaces.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	informer, err := c.Cache.GetInformer(ctx, namespaces, BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ns, ok := obj.(*metav1.PartialObjectMetadata); ok {
+				c.observe(ns)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if ns, ok := obj.(*metav1.PartialObjectMetadata); ok {
+				c.observe(ns)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if ns, ok := obj.(*metav1.PartialObjectMetadata); ok {
+				c.delete(ns.Name)
+			}
+		},
+	}); err != nil {
+		return err
+	}
+	return c.Cache.Start(ctx)
+}
+
+// IsNamespaceDeleted implements DeletedNamespaces.
+func (c *namespaceEvictingCache) IsNamespaceDeleted(namespace string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.deleted.Has(namespace)
+}
+
+// observe evicts the objects of the namespace the first time it is seen
+// terminating. The objects that still have finalizers are updated when
+// their deletion starts, and are thus added back for their controllers to
+// finalize them.
+func (c *namespaceEvictingCache) observe(ns *metav1.PartialObjectMetadata) {
+	c.mu.Lock()
+	c.deleted.Delete(ns.Name)
+	if ns.DeletionTimestamp == nil {
+		c.terminating.Delete(ns.Name)
+		c.mu.Unlock()
+		return
+	}
+	if c.terminating.Has(ns.Name) {
+		c.mu.Unlock()
+		return
+	}
+	c.terminating.Insert(ns.Name)
+	c.mu.Unlock()
+
+	evicted := c.evicter.evictNamespace(ns.Name)
+	log.V(1).Info("Evicted objects of terminating namespace", "namespace", ns.Name, "objects", evicted)
+}
+
+// delete records that the namespace was deleted. All its objects are gone
+// by then, so the requests for them can be dropped.
+func (c *namespaceEvictingCache) delete(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.terminating.Delete(namespace)
+	c.deleted.Insert(namespace)
+}
+
+func (c *namespaceEvictingCache) objectStats(sampleSize int) map[schema.GroupVersionKind]internal.ObjectStats {
+	if reporter, ok := c.Cache.(statsReporter); ok {
+		return reporter.objectStats(sampleSize)
+	}
+	return nil
+}
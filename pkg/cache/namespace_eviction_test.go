@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+// fakeEvictingCache serves a single informer, and records the namespaces
+// it is asked to evict.
+type fakeEvictingCache struct {
+	Cache
+	informer controllertest.FakeInformer
+	evicted  []string
+}
+
+func (c *fakeEvictingCache) GetInformer(context.Context, client.Object, ...InformerGetOption) (Informer, error) {
+	return &c.informer, nil
+}
+
+func (c *fakeEvictingCache) Start(context.Context) error {
+	return nil
+}
+
+func (c *fakeEvictingCache) evictNamespace(namespace string) int {
+	c.evicted = append(c.evicted, namespace)
+	return 0
+}
+
+var _ = Describe("namespaceEvictingCache", func() {
+	It("should evict the objects of terminating namespaces, and track them once deleted", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fake := &fakeEvictingCache{}
+		c := newNamespaceEvictingCache(fake)
+		Expect(c.Start(ctx)).To(Succeed())
+
+		deleted, ok := c.(DeletedNamespaces)
+		Expect(ok).To(BeTrue())
+		Expect(deleted.IsNamespaceDeleted("foo")).To(BeFalse())
+
+		informer := &fake.informer
+		foo := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+		informer.Add(foo)
+		Expect(fake.evicted).To(BeEmpty())
+
+		terminating := foo.DeepCopy()
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		informer.Update(foo, terminating)
+		Expect(fake.evicted).To(Equal([]string{"foo"}))
+		Expect(deleted.IsNamespaceDeleted("foo")).To(BeFalse())
+
+		By("evicting the objects only once")
+		informer.Update(terminating, terminating)
+		Expect(fake.evicted).To(Equal([]string{"foo"}))
+
+		informer.Delete(terminating)
+		Expect(fake.evicted).To(Equal([]string{"foo"}))
+		Expect(deleted.IsNamespaceDeleted("foo")).To(BeTrue())
+		Expect(deleted.IsNamespaceDeleted("bar")).To(BeFalse())
+
+		informer.Add(foo)
+		Expect(deleted.IsNamespaceDeleted("foo")).To(BeFalse())
+	})
+
+	It("should evict the objects of namespaces already terminating when first seen", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fake := &fakeEvictingCache{}
+		c := newNamespaceEvictingCache(fake)
+		Expect(c.Start(ctx)).To(Succeed())
+
+		fake.informer.Add(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+			Name:              "foo",
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+		}})
+		Expect(fake.evicted).To(Equal([]string{"foo"}))
+	})
+
+	It("should not wrap caches that can't evict namespaces", func() {
+		var c Cache = &struct{ Cache }{}
+		Expect(newNamespaceEvictingCache(c)).To(BeIdenticalTo(c))
+	})
+})
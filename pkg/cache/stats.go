@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+)
+
+// DefaultStatsSampleSize is the number of objects per GroupVersionKind
+// serialized to approximate the size of the objects in a cache.
+const DefaultStatsSampleSize = 10
+
+// ObjectStats describes the objects held by a cache for a single GroupVersionKind.
+type ObjectStats struct {
+	// GroupVersionKind is the GroupVersionKind of the objects.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Objects is the number of objects held.
+	Objects int
+
+	// ApproximateBytes is the approximate serialized size of the objects
+	// held, extrapolated from a sample of them. It is a proxy for, not a
+	// measure of, the memory used by the objects.
+	ApproximateBytes int64
+}
+
+// statsReporter is implemented by the caches returned from New.
+type statsReporter interface {
+	objectStats(sampleSize int) map[schema.GroupVersionKind]internal.ObjectStats
+}
+
+// Stats returns the ObjectStats of every GroupVersionKind held by c, sorted by
+// GroupVersionKind. The size of the objects is approximated by serializing up
+// to sampleSize objects per GroupVersionKind; zero means DefaultStatsSampleSize.
+// It returns an error if c was not created by New.
+func Stats(c Cache, sampleSize int) ([]ObjectStats, error) {
+	reporter, ok := c.(statsReporter)
+	if !ok {
+		return nil, fmt.Errorf("cache of type %T doesn't support stats", c)
+	}
+	if sampleSize <= 0 {
+		sampleSize = DefaultStatsSampleSize
+	}
+
+	stats := reporter.objectStats(sampleSize)
+	res := make([]ObjectStats, 0, len(stats))
+	for gvk, s := range stats {
+		res = append(res, ObjectStats{GroupVersionKind: gvk, Objects: s.Objects, ApproximateBytes: s.ApproximateBytes})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].GroupVersionKind.String() < res[j].GroupVersionKind.String()
+	})
+	return res, nil
+}
+
+func mergeObjectStats(into, from map[schema.GroupVersionKind]internal.ObjectStats) {
+	for gvk, s := range from {
+		merged := into[gvk]
+		merged.Objects += s.Objects
+		merged.ApproximateBytes += s.ApproximateBytes
+		into[gvk] = merged
+	}
+}
+
+func (ic *informerCache) objectStats(sampleSize int) map[schema.GroupVersionKind]internal.ObjectStats {
+	return ic.Informers.ObjectStats(sampleSize)
+}
+
+func (c *multiNamespaceCache) objectStats(sampleSize int) map[schema.GroupVersionKind]internal.ObjectStats {
+	res := map[schema.GroupVersionKind]internal.ObjectStats{}
+	for _, nsCache := range c.caches() {
+		if reporter, ok := nsCache.(statsReporter); ok {
+			mergeObjectStats(res, reporter.objectStats(sampleSize))
+		}
+	}
+	if reporter, ok := c.clusterCache.(statsReporter); ok {
+		mergeObjectStats(res, reporter.objectStats(sampleSize))
+	}
+	return res
+}
+
+func (dbt *delegatingByGVKCache) objectStats(sampleSize int) map[schema.GroupVersionKind]internal.ObjectStats {
+	res := map[schema.GroupVersionKind]internal.ObjectStats{}
+	for _, c := range append(maps.Values(dbt.caches), dbt.defaultCache) {
+		if reporter, ok := c.(statsReporter); ok {
+			mergeObjectStats(res, reporter.objectStats(sampleSize))
+		}
+	}
+	return res
+}
+
+var (
+	cacheObjectsDesc = prometheus.NewDesc(
+		"controller_runtime_cache_objects",
+		"Number of objects held by the cache per group, version and kind",
+		[]string{"group", "version", "kind"}, nil,
+	)
+	cacheApproximateBytesDesc = prometheus.NewDesc(
+		"controller_runtime_cache_approximate_bytes",
+		"Approximate serialized size in bytes of the objects held by the cache per group, version and kind",
+		[]string{"group", "version", "kind"}, nil,
+	)
+)
+
+// NewStatsCollector returns a prometheus.Collector exposing the Stats of c.
+// The stats are computed on every scrape, see Stats for the meaning of sampleSize.
+// The collector can be registered with the controller-runtime metrics.Registry.
+func NewStatsCollector(c Cache, sampleSize int) prometheus.Collector {
+	return &statsCollector{cache: c, sampleSize: sampleSize}
+}
+
+type statsCollector struct {
+	cache      Cache
+	sampleSize int
+}
+
+func (sc *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheObjectsDesc
+	ch <- cacheApproximateBytesDesc
+}
+
+func (sc *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := Stats(sc.cache, sc.sampleSize)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(cacheObjectsDesc, err)
+		return
+	}
+	for _, s := range stats {
+		gvk := s.GroupVersionKind
+		ch <- prometheus.MustNewConstMetric(cacheObjectsDesc, prometheus.GaugeValue, float64(s.Objects), gvk.Group, gvk.Version, gvk.Kind)
+		ch <- prometheus.MustNewConstMetric(cacheApproximateBytesDesc, prometheus.GaugeValue, float64(s.ApproximateBytes), gvk.Group, gvk.Version, gvk.Kind)
+	}
+}
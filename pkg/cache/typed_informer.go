@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TypedInformer wraps an Informer and allows adding event handlers that
+// receive objects of type object rather than interface{}.
+type TypedInformer[object client.Object] struct {
+	Informer
+}
+
+// TypedResourceEventHandlerFuncs is an adaptor that lets typed funcs be
+// used as a toolscache.ResourceEventHandler. Any nil func is ignored, as
+// are objects that are not of type object.
+type TypedResourceEventHandlerFuncs[object client.Object] struct {
+	// AddFunc is called when an object is added.
+	AddFunc func(obj object)
+
+	// UpdateFunc is called when an object is modified, and on every resync.
+	UpdateFunc func(oldObj, newObj object)
+
+	// DeleteFunc is called when an object is deleted. If the deletion was
+	// missed, it is called with the last known state of the object.
+	DeleteFunc func(obj object)
+}
+
+var _ toolscache.ResourceEventHandler = TypedResourceEventHandlerFuncs[client.Object]{}
+
+// OnAdd implements toolscache.ResourceEventHandler.
+func (h TypedResourceEventHandlerFuncs[object]) OnAdd(obj interface{}, _ bool) {
+	if h.AddFunc == nil {
+		return
+	}
+	if o, ok := obj.(object); ok {
+		h.AddFunc(o)
+	}
+}
+
+// OnUpdate implements toolscache.ResourceEventHandler.
+func (h TypedResourceEventHandlerFuncs[object]) OnUpdate(oldObj, newObj interface{}) {
+	if h.UpdateFunc == nil {
+		return
+	}
+	oldO, ok := oldObj.(object)
+	if !ok {
+		return
+	}
+	newO, ok := newObj.(object)
+	if !ok {
+		return
+	}
+	h.UpdateFunc(oldO, newO)
+}
+
+// OnDelete implements toolscache.ResourceEventHandler.
+func (h TypedResourceEventHandlerFuncs[object]) OnDelete(obj interface{}) {
+	if h.DeleteFunc == nil {
+		return
+	}
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if o, ok := obj.(object); ok {
+		h.DeleteFunc(o)
+	}
+}
+
+// InformerFor fetches or constructs an informer for the type of obj, like
+// Informers.GetInformer, and returns it as a TypedInformer.
+func InformerFor[object client.Object](ctx context.Context, informers Informers, obj object, opts ...InformerGetOption) (*TypedInformer[object], error) {
+	informer, err := informers.GetInformer(ctx, obj, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedInformer[object]{Informer: informer}, nil
+}
+
+// AddTypedEventHandler adds a typed event handler to the informer using the
+// shared informer's resync period.
+func (i *TypedInformer[object]) AddTypedEventHandler(handler TypedResourceEventHandlerFuncs[object]) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandler(handler)
+}
+
+// AddTypedEventHandlerWithResyncPeriod adds a typed event handler to the
+// informer using the specified resync period.
+func (i *TypedInformer[object]) AddTypedEventHandlerWithResyncPeriod(handler TypedResourceEventHandlerFuncs[object], resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+var _ = Describe("InformerFor", func() {
+	It("should deliver typed objects to the event handler", func() {
+		ctx := context.Background()
+		informers := &informertest.FakeInformers{}
+
+		informer, err := cache.InformerFor(ctx, informers, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var added, updated, deleted []string
+		_, err = informer.AddTypedEventHandler(cache.TypedResourceEventHandlerFuncs[*corev1.Pod]{
+			AddFunc: func(pod *corev1.Pod) {
+				added = append(added, pod.Name)
+			},
+			UpdateFunc: func(oldPod, newPod *corev1.Pod) {
+				updated = append(updated, oldPod.Name+"->"+newPod.Name)
+			},
+			DeleteFunc: func(pod *corev1.Pod) {
+				deleted = append(deleted, pod.Name)
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeInformer := informer.Informer.(*controllertest.FakeInformer)
+		first := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "first"}}
+		second := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "second"}}
+		fakeInformer.Add(first)
+		fakeInformer.Update(first, second)
+		fakeInformer.Delete(second)
+		fakeInformer.Delete(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ignored"}})
+
+		Expect(added).To(Equal([]string{"first"}))
+		Expect(updated).To(Equal([]string{"first->second"}))
+		Expect(deleted).To(Equal([]string{"second"}))
+	})
+
+	It("should unwrap tombstones on delete", func() {
+		var deleted []string
+		handler := cache.TypedResourceEventHandlerFuncs[*corev1.Pod]{
+			DeleteFunc: func(pod *corev1.Pod) {
+				deleted = append(deleted, pod.Name)
+			},
+		}
+		handler.OnDelete(toolscache.DeletedFinalStateUnknown{
+			Key: "default/gone",
+			Obj: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "gone"}},
+		})
+		Expect(deleted).To(Equal([]string{"gone"}))
+	})
+})
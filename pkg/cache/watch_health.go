@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"golang.org/x/exp/maps"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// WatchHealth describes the health of the list and watch requests of an
+// informer. Informers retry failed requests with a backoff, e.g. while the
+// CRD of their kind is deleted, and recover once a request succeeds.
+type WatchHealth struct {
+	// ConsecutiveFailures is the number of requests that failed since the
+	// last successful one.
+	ConsecutiveFailures int
+
+	// LastError is the error of the last failed request, or nil if the last
+	// request succeeded.
+	LastError error
+}
+
+// watchHealthReporter is implemented by the caches returned from New.
+type watchHealthReporter interface {
+	watchHealth(obj client.Object) (WatchHealth, bool)
+}
+
+// GetWatchHealth returns the WatchHealth of the informer of obj in c. It
+// returns false if c has no informer for obj, or was not created by New.
+// The health of caches restricted to multiple namespaces is the one of
+// their least healthy informer for obj.
+func GetWatchHealth(c Informers, obj client.Object) (WatchHealth, bool) {
+	reporter, ok := c.(watchHealthReporter)
+	if !ok {
+		return WatchHealth{}, false
+	}
+	return reporter.watchHealth(obj)
+}
+
+func (ic *informerCache) watchHealth(obj client.Object) (WatchHealth, bool) {
+	gvk, err := apiutil.GVKForObject(obj, ic.scheme)
+	if err != nil {
+		return WatchHealth{}, false
+	}
+	var lookup client.Object = obj
+	if pom, ok := ic.asMetadata(gvk, obj); ok {
+		lookup = pom
+	}
+	entry, _, ok := ic.Informers.Peek(gvk, lookup)
+	if !ok {
+		return WatchHealth{}, false
+	}
+	failures, lastErr := entry.WatchHealth()
+	return WatchHealth{ConsecutiveFailures: failures, LastError: lastErr}, true
+}
+
+func (c *multiNamespaceCache) watchHealth(obj client.Object) (WatchHealth, bool) {
+	caches := append([]Cache{c.clusterCache}, maps.Values(c.caches())...)
+	var (
+		res   WatchHealth
+		found bool
+	)
+	for _, nsCache := range caches {
+		reporter, ok := nsCache.(watchHealthReporter)
+		if !ok {
+			continue
+		}
+		if health, ok := reporter.watchHealth(obj); ok {
+			found = true
+			if health.ConsecutiveFailures > res.ConsecutiveFailures {
+				res = health
+			}
+		}
+	}
+	return res, found
+}
+
+func (dbt *delegatingByGVKCache) watchHealth(obj client.Object) (WatchHealth, bool) {
+	c, err := dbt.cacheForObject(obj)
+	if err != nil {
+		return WatchHealth{}, false
+	}
+	return GetWatchHealth(c, obj)
+}
+
+func (c *namespaceEvictingCache) watchHealth(obj client.Object) (WatchHealth, bool) {
+	return GetWatchHealth(c.Cache, obj)
+}
+
+func (c *namespaceDiscoveringCache) watchHealth(obj client.Object) (WatchHealth, bool) {
+	return GetWatchHealth(c.Cache, obj)
+}
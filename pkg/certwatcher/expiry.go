@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certwatcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher/metrics"
+)
+
+// ExpiryMonitor monitors the expiry of the certificate served by a server.
+// It exports the expiry time in the
+// certwatcher_certificate_expiration_timestamp_seconds metric, and provides
+// a health check failing when the certificate is about to expire, so that
+// a certificate that was not renewed doesn't silently break the server.
+type ExpiryMonitor struct {
+	name           string
+	threshold      time.Duration
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	notAfter time.Time
+}
+
+// NewExpiryMonitor returns an ExpiryMonitor of the certificates returned by
+// getCertificate. The name identifies the server in the metric. The health
+// check fails when the certificate expires within threshold, or has expired
+// if threshold is zero.
+func NewExpiryMonitor(name string, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error), threshold time.Duration) *ExpiryMonitor {
+	return &ExpiryMonitor{
+		name:           name,
+		threshold:      threshold,
+		getCertificate: getCertificate,
+	}
+}
+
+// GetCertificate returns the certificate to serve, recording its expiry. It
+// can be used as tls.Config.GetCertificate, to keep the metric up to date as
+// the certificate is served.
+func (m *ExpiryMonitor) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := m.getCertificate(hello)
+	if err == nil && cert != nil {
+		_, _ = m.observe(cert)
+	}
+	return cert, err
+}
+
+// NotAfter returns the expiry time of the certificate currently served.
+func (m *ExpiryMonitor) NotAfter() (time.Time, error) {
+	cert, err := m.getCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get certificate: %w", err)
+	}
+	if cert == nil {
+		return time.Time{}, errors.New("no certificate is served")
+	}
+	return m.observe(cert)
+}
+
+// Check is a health check failing when the certificate currently served
+// expires within the threshold of the monitor. Its signature is the one of
+// healthz.Checker.
+func (m *ExpiryMonitor) Check(_ *http.Request) error {
+	notAfter, err := m.NotAfter()
+	if err != nil {
+		return err
+	}
+	if remaining := time.Until(notAfter); remaining <= m.threshold {
+		if remaining <= 0 {
+			return fmt.Errorf("certificate expired at %s", notAfter.Format(time.RFC3339))
+		}
+		return fmt.Errorf("certificate expires at %s, in less than %s", notAfter.Format(time.RFC3339), m.threshold)
+	}
+	return nil
+}
+
+// observe returns the expiry time of cert, and records it in the metric. The
+// expiry time is only parsed when the certificate changes.
+func (m *ExpiryMonitor) observe(cert *tls.Certificate) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cert == m.cert {
+		return m.notAfter, nil
+	}
+
+	leaf := cert.Leaf
+	if leaf == nil {
+		if len(cert.Certificate) == 0 {
+			return time.Time{}, errors.New("certificate holds no certificate data")
+		}
+		var err error
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+	}
+	m.cert = cert
+	m.notAfter = leaf.NotAfter
+	metrics.CertificateExpirationTimestamp.WithLabelValues(m.name).Set(float64(leaf.NotAfter.Unix()))
+	return m.notAfter, nil
+}
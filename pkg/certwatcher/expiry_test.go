@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certwatcher_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher/metrics"
+)
+
+var _ = Describe("ExpiryMonitor", func() {
+	var cert *tls.Certificate
+
+	BeforeEach(func() {
+		Expect(writeCerts(certPath, keyPath, "127.0.0.1")).To(Succeed())
+		keyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
+		Expect(err).NotTo(HaveOccurred())
+		cert = &keyPair
+	})
+
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return cert, nil
+	}
+
+	It("should report the expiry time of the certificate in the metric", func() {
+		monitor := certwatcher.NewExpiryMonitor("test", getCertificate, 0)
+		served, err := monitor.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(served).To(BeIdenticalTo(cert))
+
+		notAfter, err := monitor.NotAfter()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		Expect(testutil.ToFloat64(metrics.CertificateExpirationTimestamp.WithLabelValues("test"))).To(BeEquivalentTo(notAfter.Unix()))
+	})
+
+	It("should fail the check when the certificate expires within the threshold", func() {
+		Expect(certwatcher.NewExpiryMonitor("test", getCertificate, 30*time.Minute).Check(nil)).To(Succeed())
+		Expect(certwatcher.NewExpiryMonitor("test", getCertificate, 2*time.Hour).Check(nil)).
+			To(MatchError(ContainSubstring("in less than 2h0m0s")))
+	})
+
+	It("should fail the check when no certificate is served", func() {
+		monitor := certwatcher.NewExpiryMonitor("test", func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return nil, nil
+		}, 0)
+		Expect(monitor.Check(nil)).To(MatchError("no certificate is served"))
+
+		monitor = certwatcher.NewExpiryMonitor("test", func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return nil, errors.New("unavailable")
+		}, 0)
+		Expect(monitor.Check(nil)).To(MatchError(ContainSubstring("unavailable")))
+	})
+})
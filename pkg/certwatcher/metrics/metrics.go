@@ -35,11 +35,20 @@ var (
 		Name: "certwatcher_read_certificate_errors_total",
 		Help: "Total number of certificate read errors",
 	})
+
+	// CertificateExpirationTimestamp is a prometheus gauge metrics which
+	// holds the expiry time of the certificate served by a server, in
+	// seconds since the epoch.
+	CertificateExpirationTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "certwatcher_certificate_expiration_timestamp_seconds",
+		Help: "Expiry time of the certificate served by a server, in seconds since the epoch",
+	}, []string{"name"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		ReadCertificateTotal,
 		ReadCertificateErrors,
+		CertificateExpirationTimestamp,
 	)
 }
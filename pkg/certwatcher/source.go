@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certwatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Source provides the serving certificate of a server and keeps it up to
+// date, e.g. by watching files, polling an issuer or receiving updates from
+// a SPIFFE Workload API.
+type Source interface {
+	// GetCertificate returns the current certificate. It can be used as
+	// tls.Config.GetCertificate.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	// RegisterCallback registers a callback to be invoked when the
+	// certificate changes.
+	RegisterCallback(callback func(tls.Certificate))
+
+	// Start keeps the certificate up to date until ctx is done.
+	Start(ctx context.Context) error
+}
+
+var _ Source = &CertWatcher{}
+
+// FetchFunc returns the latest certificate from a certificate issuer.
+type FetchFunc func(ctx context.Context) (*tls.Certificate, error)
+
+// PollingSource is a Source that periodically fetches the certificate using
+// a FetchFunc. It allows serving certificates that are not mounted as files,
+// such as certificates issued by a cloud provider or an SVID.
+type PollingSource struct {
+	sync.RWMutex
+
+	fetch    FetchFunc
+	interval time.Duration
+
+	currentCert *tls.Certificate
+	callback    func(tls.Certificate)
+}
+
+var _ Source = &PollingSource{}
+
+// NewPollingSource returns a new PollingSource that fetches the certificate
+// every interval. The certificate is fetched once before returning, so that
+// it is available as soon as the server starts.
+func NewPollingSource(ctx context.Context, fetch FetchFunc, interval time.Duration) (*PollingSource, error) {
+	ps := &PollingSource{
+		fetch:    fetch,
+		interval: interval,
+	}
+	if err := ps.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// RegisterCallback registers a callback to be invoked when the certificate changes.
+func (ps *PollingSource) RegisterCallback(callback func(tls.Certificate)) {
+	ps.Lock()
+	defer ps.Unlock()
+	// If the current certificate is not nil, invoke the callback immediately.
+	if ps.currentCert != nil {
+		callback(*ps.currentCert)
+	}
+	ps.callback = callback
+}
+
+// GetCertificate fetches the currently loaded certificate, which may be nil.
+func (ps *PollingSource) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	ps.RLock()
+	defer ps.RUnlock()
+	return ps.currentCert, nil
+}
+
+// Start polls the certificate until ctx is done. Failures to fetch the
+// certificate are logged and the last fetched certificate keeps being served.
+func (ps *PollingSource) Start(ctx context.Context) error {
+	log.Info("Starting certificate poller")
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := ps.refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Error(err, "error fetching certificate")
+		}
+	}, ps.interval)
+	return nil
+}
+
+func (ps *PollingSource) refresh(ctx context.Context) error {
+	cert, err := ps.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch certificate: %w", err)
+	}
+	if cert == nil {
+		return fmt.Errorf("failed to fetch certificate: no certificate returned")
+	}
+
+	ps.Lock()
+	defer ps.Unlock()
+	if ps.currentCert != nil && sameCertificate(ps.currentCert, cert) {
+		return nil
+	}
+	ps.currentCert = cert
+
+	log.Info("Updated current TLS certificate")
+
+	// If a callback is registered, invoke it with the new certificate.
+	if ps.callback != nil {
+		callback := ps.callback
+		go func() {
+			callback(*cert)
+		}()
+	}
+	return nil
+}
+
+func sameCertificate(a, b *tls.Certificate) bool {
+	if len(a.Certificate) != len(b.Certificate) {
+		return false
+	}
+	for i := range a.Certificate {
+		if !bytes.Equal(a.Certificate[i], b.Certificate[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certwatcher_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+)
+
+var _ = Describe("PollingSource", func() {
+	var (
+		ctx       context.Context
+		ctxCancel context.CancelFunc
+		fetchErr  atomic.Pointer[error]
+	)
+
+	BeforeEach(func() {
+		ctx, ctxCancel = context.WithCancel(context.Background())
+		fetchErr.Store(nil)
+		Expect(writeCerts(certPath, keyPath, "127.0.0.1")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		ctxCancel()
+	})
+
+	fetch := func(context.Context) (*tls.Certificate, error) {
+		if err := fetchErr.Load(); err != nil {
+			return nil, *err
+		}
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		return &cert, err
+	}
+
+	It("should fetch the certificate on creation", func() {
+		source, err := certwatcher.NewPollingSource(ctx, fetch, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert, err := source.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert).NotTo(BeNil())
+	})
+
+	It("should error if the certificate can't be fetched on creation", func() {
+		err := errors.New("unavailable")
+		fetchErr.Store(&err)
+		_, err = certwatcher.NewPollingSource(ctx, fetch, time.Hour)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should invoke the callback when the certificate rotates", func() {
+		source, err := certwatcher.NewPollingSource(ctx, fetch, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		first, err := source.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		var calls atomic.Int64
+		source.RegisterCallback(func(tls.Certificate) {
+			calls.Add(1)
+		})
+		Expect(calls.Load()).To(BeEquivalentTo(1))
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(source.Start(ctx)).To(Succeed())
+		}()
+		Consistently(calls.Load, 100*time.Millisecond).Should(BeEquivalentTo(1))
+
+		Expect(writeCerts(certPath, keyPath, "192.168.0.1")).To(Succeed())
+		Eventually(calls.Load).Should(BeEquivalentTo(2))
+
+		second, err := source.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Certificate).NotTo(Equal(first.Certificate))
+	})
+
+	It("should keep serving the last certificate when fetching fails", func() {
+		source, err := certwatcher.NewPollingSource(ctx, fetch, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = errors.New("unavailable")
+		fetchErr.Store(&err)
+		go func() {
+			defer GinkgoRecover()
+			Expect(source.Start(ctx)).To(Succeed())
+		}()
+
+		Consistently(func() (*tls.Certificate, error) {
+			return source.GetCertificate(nil)
+		}, 100*time.Millisecond).ShouldNot(BeNil())
+	})
+})
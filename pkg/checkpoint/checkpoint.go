@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkpoint persists a small progress checkpoint per object, so that
+// reconcilers driving multi-step workflows can resume where they left off
+// after a restart instead of starting over.
+//
+// A reconciler loads the checkpoint of its object at the start of a
+// reconcile, saves it after each completed step and clears it once the
+// workflow is done:
+//
+//	var progress Progress
+//	found, err := checkpoint.Load(ctx, store, obj, &progress)
+//	...
+//	progress.Step = "volumes-attached"
+//	if err := checkpoint.Save(ctx, store, obj, progress); err != nil { ... }
+//	...
+//	if err := checkpoint.Clear(ctx, store, obj); err != nil { ... }
+//
+// Checkpoints are stored by a Store, either in an annotation of the object
+// or in a ConfigMap owned by it, and are limited in size.
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrTooLarge is returned when saving a checkpoint larger than the maximum
+// size of its store.
+var ErrTooLarge = errors.New("checkpoint exceeds the maximum size of its store")
+
+// Store persists the checkpoints of objects.
+type Store interface {
+	// Load returns the checkpoint of obj, or nil if none was saved.
+	Load(ctx context.Context, obj client.Object) ([]byte, error)
+
+	// Save replaces the checkpoint of obj with data, or clears it if data is
+	// nil. It returns an error wrapping ErrTooLarge if data exceeds the
+	// maximum size of the store.
+	Save(ctx context.Context, obj client.Object, data []byte) error
+}
+
+// Load decodes the checkpoint of obj saved in store into into, which must be
+// a pointer. It returns false if no checkpoint was saved.
+func Load(ctx context.Context, store Store, obj client.Object, into any) (bool, error) {
+	data, err := store.Load(ctx, obj)
+	if err != nil || data == nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return false, fmt.Errorf("failed to decode checkpoint of %s: %w", obj.GetName(), err)
+	}
+	return true, nil
+}
+
+// Save encodes checkpoint as JSON and saves it in store for obj.
+func Save(ctx context.Context, store Store, obj client.Object, checkpoint any) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint of %s: %w", obj.GetName(), err)
+	}
+	return store.Save(ctx, obj, data)
+}
+
+// Clear removes the checkpoint of obj from store.
+func Clear(ctx context.Context, store Store, obj client.Object) error {
+	return store.Save(ctx, obj, nil)
+}
+
+func checkSize(obj client.Object, data []byte, maxSize int) error {
+	if len(data) > maxSize {
+		return fmt.Errorf("checkpoint of %s is %d bytes, more than %d: %w", obj.GetName(), len(data), maxSize, ErrTooLarge)
+	}
+	return nil
+}
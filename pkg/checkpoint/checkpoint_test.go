@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/checkpoint"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckpoint(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Checkpoint Suite")
+}
+
+type progress struct {
+	Step     string `json:"step"`
+	Attempts int    `json:"attempts"`
+}
+
+var _ = Describe("Checkpoint", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		obj *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "obj", UID: "obj-uid"}}
+		c = fake.NewClientBuilder().WithObjects(obj).Build()
+	})
+
+	roundTrip := func(store checkpoint.Store) {
+		var p progress
+		found, err := checkpoint.Load(ctx, store, obj, &p)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		Expect(checkpoint.Save(ctx, store, obj, progress{Step: "attach", Attempts: 2})).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		found, err = checkpoint.Load(ctx, store, obj, &p)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(p).To(Equal(progress{Step: "attach", Attempts: 2}))
+
+		Expect(checkpoint.Clear(ctx, store, obj)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		found, err = checkpoint.Load(ctx, store, obj, &p)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	}
+
+	Describe("AnnotationStore", func() {
+		It("should save, load and clear checkpoints", func() {
+			store := &checkpoint.AnnotationStore{Client: c}
+			roundTrip(store)
+			Expect(obj.Annotations).NotTo(HaveKey(checkpoint.AnnotationKey))
+		})
+
+		It("should refuse checkpoints larger than its maximum size", func() {
+			store := &checkpoint.AnnotationStore{Client: c, MaxSize: 16}
+			err := checkpoint.Save(ctx, store, obj, progress{Step: strings.Repeat("a", 16)})
+			Expect(err).To(MatchError(checkpoint.ErrTooLarge))
+		})
+	})
+
+	Describe("ConfigMapStore", func() {
+		It("should save, load and clear checkpoints", func() {
+			store := &checkpoint.ConfigMapStore{Client: c}
+			Expect(checkpoint.Save(ctx, store, obj, progress{Step: "attach"})).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "obj-deployment.apps-checkpoint"}, cm)).To(Succeed())
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+			Expect(cm.OwnerReferences[0].UID).To(Equal(obj.UID))
+
+			Expect(checkpoint.Clear(ctx, store, obj)).To(Succeed())
+			err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "obj-deployment.apps-checkpoint"}, cm)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			roundTrip(store)
+		})
+
+		It("should ignore the checkpoint of a previous object of the same name", func() {
+			store := &checkpoint.ConfigMapStore{Client: c}
+			Expect(checkpoint.Save(ctx, store, obj, progress{Step: "attach"})).To(Succeed())
+
+			recreated := obj.DeepCopy()
+			recreated.UID = "recreated-uid"
+			var p progress
+			found, err := checkpoint.Load(ctx, store, recreated, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("should neither overwrite nor delete the checkpoint of another object", func() {
+			store := &checkpoint.ConfigMapStore{Client: c}
+			Expect(checkpoint.Save(ctx, store, obj, progress{Step: "attach"})).To(Succeed())
+
+			recreated := obj.DeepCopy()
+			recreated.UID = "recreated-uid"
+			err := checkpoint.Save(ctx, store, recreated, progress{Step: "detach"})
+			Expect(err).To(MatchError(ContainSubstring("is not owned by")))
+			Expect(checkpoint.Clear(ctx, store, recreated)).To(Succeed())
+
+			var p progress
+			found, err := checkpoint.Load(ctx, store, obj, &p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(p).To(Equal(progress{Step: "attach"}))
+		})
+
+		It("should keep the owner reference of an existing ConfigMap", func() {
+			store := &checkpoint.ConfigMapStore{Client: c}
+			Expect(checkpoint.Save(ctx, store, obj, progress{Step: "attach"})).To(Succeed())
+			Expect(checkpoint.Save(ctx, store, obj, progress{Step: "detach"})).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "obj-deployment.apps-checkpoint"}, cm)).To(Succeed())
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+			Expect(*cm.OwnerReferences[0].Controller).To(BeTrue())
+		})
+
+		It("should shorten the name of the ConfigMap of objects with long names", func() {
+			obj = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: strings.Repeat("a", 253), UID: "long-uid"}}
+			Expect(c.Create(ctx, obj)).To(Succeed())
+			store := &checkpoint.ConfigMapStore{Client: c}
+			Expect(checkpoint.Save(ctx, store, obj, progress{Step: "attach"})).To(Succeed())
+
+			cms := &corev1.ConfigMapList{}
+			Expect(c.List(ctx, cms, client.InNamespace("default"))).To(Succeed())
+			Expect(cms.Items).To(HaveLen(1))
+			Expect(len(cms.Items[0].Name)).To(BeNumerically("<=", 253))
+
+			Expect(checkpoint.Clear(ctx, store, obj)).To(Succeed())
+			roundTrip(store)
+		})
+
+		It("should refuse checkpoints larger than its maximum size", func() {
+			store := &checkpoint.ConfigMapStore{Client: c, MaxSize: 16}
+			err := checkpoint.Save(ctx, store, obj, progress{Step: strings.Repeat("a", 16)})
+			Expect(err).To(MatchError(checkpoint.ErrTooLarge))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/internal/ownedstore"
+)
+
+// AnnotationKey is the annotation used by AnnotationStore to record the
+// checkpoint of an object, base64 encoded.
+const AnnotationKey = "checkpoint.controller-runtime.sigs.k8s.io/data"
+
+// ConfigMapKey is the key of the checkpoint in the binary data of the
+// ConfigMaps written by ConfigMapStore.
+const ConfigMapKey = "checkpoint"
+
+const (
+	// DefaultAnnotationMaxSize is the default maximum size of the
+	// checkpoints of an AnnotationStore, before encoding.
+	DefaultAnnotationMaxSize = 4 * 1024
+
+	// DefaultConfigMapMaxSize is the default maximum size of the
+	// checkpoints of a ConfigMapStore.
+	DefaultConfigMapMaxSize = 256 * 1024
+)
+
+// AnnotationStore is a Store that records the checkpoint base64 encoded in
+// an annotation of the object. Saving a checkpoint updates the object, so
+// it is only suitable for checkpoints saved rarely and small enough not to
+// weigh on the size limit of the object.
+type AnnotationStore struct {
+	// Client is used to patch the object.
+	Client client.Client
+
+	// MaxSize is the maximum size of a checkpoint in bytes. Defaults to
+	// DefaultAnnotationMaxSize.
+	MaxSize int
+}
+
+var _ Store = &AnnotationStore{}
+
+// Load implements Store.
+func (s *AnnotationStore) Load(_ context.Context, obj client.Object) ([]byte, error) {
+	value, ok := obj.GetAnnotations()[AnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint of %s: %w", obj.GetName(), err)
+	}
+	return data, nil
+}
+
+// Save implements Store. The object is patched in place.
+func (s *AnnotationStore) Save(ctx context.Context, obj client.Object, data []byte) error {
+	maxSize := s.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultAnnotationMaxSize
+	}
+	if err := checkSize(obj, data, maxSize); err != nil {
+		return err
+	}
+
+	var value *string
+	if data != nil {
+		value = ptr.To(base64.StdEncoding.EncodeToString(data))
+	}
+	return ownedstore.SetAnnotation(ctx, s.Client, obj, AnnotationKey, value)
+}
+
+// ConfigMapStore is a Store that records the checkpoint of each object in
+// the binary data of a ConfigMap controlled by it, so that saving a
+// checkpoint doesn't update the object nor trigger its watches. The
+// ConfigMap is garbage collected with the object, and deleted when its
+// checkpoint is cleared.
+//
+// The ConfigMap is named after the name, kind and group of the object. The
+// checkpoint of a deleted object of the same name, whose ConfigMap wasn't
+// garbage collected yet, is ignored by Load and Clear, and Save fails until
+// the ConfigMap is gone.
+type ConfigMapStore struct {
+	// Client is used to read and write the ConfigMaps.
+	Client client.Client
+
+	// Namespace is the namespace of the ConfigMaps. Defaults to the
+	// namespace of the object, and is required for cluster-scoped objects.
+	Namespace string
+
+	// NameSuffix is appended to the name, kind and group of the object to
+	// name its ConfigMap, e.g. "web-deployment.apps-checkpoint".
+	// Defaults to "-checkpoint".
+	NameSuffix string
+
+	// MaxSize is the maximum size of a checkpoint in bytes. Defaults to
+	// DefaultConfigMapMaxSize.
+	MaxSize int
+}
+
+var _ Store = &ConfigMapStore{}
+
+// Load implements Store.
+func (s *ConfigMapStore) Load(ctx context.Context, obj client.Object) ([]byte, error) {
+	cm, err := s.configMaps().Get(ctx, obj)
+	if err != nil || cm == nil {
+		return nil, err
+	}
+	return cm.BinaryData[ConfigMapKey], nil
+}
+
+// Save implements Store.
+func (s *ConfigMapStore) Save(ctx context.Context, obj client.Object, data []byte) error {
+	maxSize := s.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultConfigMapMaxSize
+	}
+	if err := checkSize(obj, data, maxSize); err != nil {
+		return err
+	}
+
+	if data == nil {
+		return s.configMaps().Delete(ctx, obj)
+	}
+	return s.configMaps().Update(ctx, obj, func(cm *corev1.ConfigMap) {
+		cm.BinaryData = map[string][]byte{ConfigMapKey: data}
+	})
+}
+
+func (s *ConfigMapStore) configMaps() *ownedstore.ConfigMaps {
+	suffix := s.NameSuffix
+	if suffix == "" {
+		suffix = "-checkpoint"
+	}
+	return &ownedstore.ConfigMaps{Client: s.Client, Namespace: s.Namespace, NameSuffix: suffix}
+}
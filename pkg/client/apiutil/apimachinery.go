@@ -52,6 +52,13 @@ func init() {
 
 // AddToProtobufScheme add the given SchemeBuilder into protobufScheme, which should
 // be additional types that do support protobuf.
+//
+// Clients and informers of typed objects use protobuf to talk to the API server
+// for the types of protobufScheme, which holds the built-in types by default, and
+// JSON for the other types, e.g. custom resources and unstructured objects.
+// Protobuf is much cheaper to encode and decode, for both the API server and the
+// controller, which matters for large lists and watches. Setting the ContentType
+// of the rest.Config the clients are created with overrides it for all the types.
 func AddToProtobufScheme(addToScheme func(*runtime.Scheme) error) error {
 	protobufSchemeLock.Lock()
 	defer protobufSchemeLock.Unlock()
@@ -187,6 +194,11 @@ func createRestConfig(gvk schema.GroupVersionKind, isUnstructured bool, baseConf
 		protobufSchemeLock.RLock()
 		if protobufScheme.Recognizes(gvk) {
 			cfg.ContentType = runtime.ContentTypeProtobuf
+			// Accept JSON as well, for servers of the type, e.g. aggregated
+			// API servers, that don't support protobuf.
+			if cfg.AcceptContentTypes == "" {
+				cfg.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+			}
 		}
 		protobufSchemeLock.RUnlock()
 	}
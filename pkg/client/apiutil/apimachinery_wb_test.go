@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiutil
+
+import (
+	"testing"
+
+	gmg "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+func TestCreateRestConfigContentType(t *testing.T) {
+	pod := corev1.SchemeGroupVersion.WithKind("Pod")
+	crd := apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition")
+
+	t.Run("uses protobuf with a JSON fallback for built-in types", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+		cfg := createRestConfig(pod, false, &rest.Config{}, scheme.Codecs)
+		g.Expect(cfg.ContentType).To(gmg.Equal(runtime.ContentTypeProtobuf))
+		g.Expect(cfg.AcceptContentTypes).To(gmg.Equal(runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON))
+	})
+
+	t.Run("uses JSON for other types", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+		cfg := createRestConfig(crd, false, &rest.Config{}, scheme.Codecs)
+		g.Expect(cfg.ContentType).To(gmg.BeEmpty())
+		g.Expect(cfg.AcceptContentTypes).To(gmg.BeEmpty())
+	})
+
+	t.Run("uses JSON for unstructured objects", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+		cfg := createRestConfig(pod, true, &rest.Config{}, scheme.Codecs)
+		g.Expect(cfg.ContentType).NotTo(gmg.Equal(runtime.ContentTypeProtobuf))
+	})
+
+	t.Run("keeps the content type of the base config", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+		cfg := createRestConfig(pod, false, &rest.Config{ContentConfig: rest.ContentConfig{ContentType: runtime.ContentTypeJSON}}, scheme.Codecs)
+		g.Expect(cfg.ContentType).To(gmg.Equal(runtime.ContentTypeJSON))
+		g.Expect(cfg.AcceptContentTypes).To(gmg.BeEmpty())
+	})
+}
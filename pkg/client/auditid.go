@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"k8s.io/client-go/rest"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AuditIDHeader is the header holding the ID under which the kube-apiserver
+// records a request in its audit log. The kube-apiserver returns it in its
+// responses, and uses the one of a request if it is set.
+const AuditIDHeader = "Audit-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a copy of ctx that carries the given correlation
+// ID. Requests made with the returned context by a client whose config has
+// been passed to EnableAuditIDs send it as their audit ID, so that the audit
+// events of the requests can be found from the ID, e.g. the ID of a
+// reconcile logged by the controller.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok
+}
+
+// AuditIDRecorder records the audit IDs returned by the kube-apiserver for
+// the requests made with a context returned by WithAuditIDRecorder. It is
+// safe for concurrent use.
+type AuditIDRecorder struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+// AuditIDs returns the audit IDs recorded, in the order the responses were
+// received.
+func (r *AuditIDRecorder) AuditIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids...)
+}
+
+// Last returns the last audit ID recorded, or an empty string.
+func (r *AuditIDRecorder) Last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.ids) == 0 {
+		return ""
+	}
+	return r.ids[len(r.ids)-1]
+}
+
+func (r *AuditIDRecorder) record(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = append(r.ids, id)
+}
+
+type auditIDRecorderKey struct{}
+
+// WithAuditIDRecorder returns a copy of ctx, and an AuditIDRecorder that
+// records the audit IDs of the requests made with it by a client whose config
+// has been passed to EnableAuditIDs, e.g. to report them with an error.
+func WithAuditIDRecorder(ctx context.Context) (context.Context, *AuditIDRecorder) {
+	recorder := &AuditIDRecorder{}
+	return context.WithValue(ctx, auditIDRecorderKey{}, recorder), recorder
+}
+
+// EnableAuditIDs wraps the transport of config to link the requests it makes
+// with the audit events of the kube-apiserver:
+//
+//   - requests made with a context carrying a correlation ID send it as their
+//     audit ID,
+//   - the audit IDs of responses are recorded by the AuditIDRecorder of the
+//     context of the request, if any,
+//   - requests are logged at V(5) with their audit ID by the logger of their
+//     context, e.g. the logger of the reconcile making them.
+//
+// It must be called before clients or caches are created from config.
+func EnableAuditIDs(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &auditIDRoundTripper{delegate: rt}
+	})
+}
+
+type auditIDRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *auditIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if id, ok := CorrelationIDFromContext(ctx); ok && id != "" {
+		req = req.Clone(ctx)
+		req.Header.Set(AuditIDHeader, id)
+	}
+
+	resp, err := rt.delegate.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	auditID := resp.Header.Get(AuditIDHeader)
+	if recorder, ok := ctx.Value(auditIDRecorderKey{}).(*AuditIDRecorder); ok && auditID != "" {
+		recorder.record(auditID)
+	}
+	logf.FromContext(ctx).V(5).Info("API request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "auditID", auditID)
+	return resp, nil
+}
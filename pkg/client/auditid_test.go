@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestEnableAuditIDs(t *testing.T) {
+	var requestAuditID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestAuditID = req.Header.Get(client.AuditIDHeader)
+		auditID := requestAuditID
+		if auditID == "" {
+			auditID = "generated"
+		}
+		w.Header().Set(client.AuditIDHeader, auditID)
+	}))
+	defer srv.Close()
+
+	cfg := &rest.Config{Host: srv.URL}
+	client.EnableAuditIDs(cfg)
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	get := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	ctx, recorder := client.WithAuditIDRecorder(context.Background())
+	if last := recorder.Last(); last != "" {
+		t.Fatalf("expected no audit ID before any request, got %q", last)
+	}
+	get(ctx)
+	if requestAuditID != "" {
+		t.Fatalf("expected no audit ID to be sent without a correlation ID, got %q", requestAuditID)
+	}
+
+	get(client.WithCorrelationID(ctx, "reconcile-1234"))
+	if requestAuditID != "reconcile-1234" {
+		t.Fatalf("expected the correlation ID to be sent as audit ID, got %q", requestAuditID)
+	}
+	if ids := recorder.AuditIDs(); !reflect.DeepEqual(ids, []string{"generated", "reconcile-1234"}) {
+		t.Fatalf("unexpected audit IDs recorded: %v", ids)
+	}
+	if last := recorder.Last(); last != "reconcile-1234" {
+		t.Fatalf("unexpected last audit ID: %q", last)
+	}
+}
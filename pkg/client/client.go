@@ -52,6 +52,16 @@ type Options struct {
 
 	// DryRun instructs the client to only perform dry run requests.
 	DryRun *bool
+
+	// ReuseListBuffers makes the List calls of typed objects that are not
+	// served from the cache read responses into pooled buffers, and decode
+	// them into the items of the list they are given, reusing its capacity,
+	// instead of allocating both for every call. It reduces allocations for
+	// controllers that frequently list large collections from the API
+	// server, if they pass the same list to consecutive calls; the items of
+	// a list passed to List must then not be retained across calls, since
+	// they are overwritten.
+	ReuseListBuffers bool
 }
 
 // CacheOptions are options for creating a cache-backed client.
@@ -177,6 +187,9 @@ func newClient(config *rest.Config, options Options) (*client, error) {
 		scheme: options.Scheme,
 		mapper: options.Mapper,
 	}
+	if options.ReuseListBuffers {
+		c.typedClient.listBuffers = newListBufferPool()
+	}
 	if options.Cache == nil || options.Cache.Reader == nil {
 		return c, nil
 	}
@@ -270,6 +283,7 @@ func (c *client) RESTMapper() meta.RESTMapper {
 
 // Create implements client.Client.
 func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	switch obj.(type) {
 	case runtime.Unstructured:
 		return c.unstructuredClient.Create(ctx, obj, opts...)
@@ -282,6 +296,7 @@ func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) e
 
 // Update implements client.Client.
 func (c *client) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer c.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -319,6 +334,7 @@ func (c *client) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllO
 
 // Patch implements client.Client.
 func (c *client) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer c.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -332,13 +348,18 @@ func (c *client) Patch(ctx context.Context, obj Object, patch Patch, opts ...Pat
 
 // Get implements client.Client.
 func (c *client) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	getOpts := (&GetOptions{}).ApplyOptions(opts)
 	if isUncached, err := c.shouldBypassCache(obj); err != nil {
 		return err
-	} else if !isUncached {
+	} else if !isUncached && !getOpts.requiresLiveRead() {
 		// Attempt to get from the cache.
 		return c.cache.Get(ctx, key, obj, opts...)
 	}
 
+	if getOpts.ResourceVersionMatch == metav1.ResourceVersionMatchExact {
+		return c.getExact(ctx, key, obj, getOpts)
+	}
+
 	// Perform a live lookup.
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -548,6 +569,7 @@ func (sc *subResourceClient) Get(ctx context.Context, obj Object, subResource Ob
 
 // Create implements client.SubResourceClient
 func (sc *subResourceClient) Create(ctx context.Context, obj Object, subResource Object, opts ...SubResourceCreateOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	defer sc.client.resetGroupVersionKind(subResource, subResource.GetObjectKind().GroupVersionKind())
 
@@ -563,6 +585,7 @@ func (sc *subResourceClient) Create(ctx context.Context, obj Object, subResource
 
 // Update implements client.SubResourceClient
 func (sc *subResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -576,6 +599,7 @@ func (sc *subResourceClient) Update(ctx context.Context, obj Object, opts ...Sub
 
 // Patch implements client.SubResourceWriter.
 func (sc *subResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
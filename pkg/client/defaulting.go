@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Defaulter mutates an object before it is written, e.g. to set standard
+// labels or owner references.
+type Defaulter func(ctx context.Context, obj Object) error
+
+// Defaulting configures the defaulters applied by a client returned from
+// [WithDefaulting].
+type Defaulting struct {
+	// Defaulters are applied to objects of every kind, in order.
+	Defaulters []Defaulter
+
+	// ByKind holds the defaulters applied to the objects of specific kinds,
+	// in order, after Defaulters.
+	ByKind map[schema.GroupVersionKind][]Defaulter
+}
+
+// WithDefaulting wraps a Client and applies defaulters to objects before
+// creating, updating or patching them, so that conventions such as standard
+// labels or owner references are enforced in one place instead of in every
+// reconciler. If a defaulter fails, the object is not written and the error
+// is returned.
+//
+// Defaulters mutate the object passed to the client. They only affect the
+// patches computed from the object, such as those created with MergeFrom
+// and Apply patches, not raw patches. Writes to subresources, such as the
+// status, are not defaulted.
+func WithDefaulting(c Client, defaulting Defaulting) Client {
+	return &clientWithDefaulting{
+		Client:     c,
+		defaulting: defaulting,
+	}
+}
+
+type clientWithDefaulting struct {
+	Client
+	defaulting Defaulting
+}
+
+func (c *clientWithDefaulting) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := c.applyDefaulters(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *clientWithDefaulting) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := c.applyDefaulters(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *clientWithDefaulting) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	if err := c.applyDefaulters(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *clientWithDefaulting) applyDefaulters(ctx context.Context, obj Object) error {
+	defaulters := c.defaulting.Defaulters
+	if len(c.defaulting.ByKind) > 0 {
+		gvk, err := c.Client.GroupVersionKindFor(obj)
+		if err != nil {
+			return err
+		}
+		defaulters = append(defaulters[:len(defaulters):len(defaulters)], c.defaulting.ByKind[gvk]...)
+	}
+	for _, defaulter := range defaulters {
+		if err := defaulter(ctx, obj); err != nil {
+			return fmt.Errorf("failed to default %T %s: %w", obj, ObjectKeyFromObject(obj), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithDefaulting(t *testing.T) {
+	setLabel := func(key, value string) client.Defaulter {
+		return func(_ context.Context, obj client.Object) error {
+			labels := obj.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[key] = value
+			obj.SetLabels(labels)
+			return nil
+		}
+	}
+	c := client.WithDefaulting(fake.NewClientBuilder().Build(), client.Defaulting{
+		Defaulters: []client.Defaulter{setLabel("app.kubernetes.io/managed-by", "test")},
+		ByKind: map[schema.GroupVersionKind][]client.Defaulter{
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"): {setLabel("kind", "configmap")},
+		},
+	})
+	ctx := context.Background()
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	if err := c.Create(ctx, cm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Labels["app.kubernetes.io/managed-by"] != "test" || created.Labels["kind"] != "configmap" {
+		t.Fatalf("expected the ConfigMap to be defaulted, got labels %v", created.Labels)
+	}
+
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"}}
+	if err := c.Create(ctx, deploy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := deploy.Labels["kind"]; ok || deploy.Labels["app.kubernetes.io/managed-by"] != "test" {
+		t.Fatalf("expected the Deployment to only get the defaults of all kinds, got labels %v", deploy.Labels)
+	}
+
+	base := created.DeepCopy()
+	created.Labels = nil
+	if err := c.Patch(ctx, created, client.MergeFrom(base)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Labels["kind"] != "configmap" {
+		t.Fatalf("expected the patched ConfigMap to be defaulted, got labels %v", created.Labels)
+	}
+
+	failing := client.WithDefaulting(fake.NewClientBuilder().Build(), client.Defaulting{
+		Defaulters: []client.Defaulter{func(context.Context, client.Object) error { return errors.New("boom") }},
+	})
+	if err := failing.Update(ctx, cm); err == nil {
+		t.Fatal("expected the update to fail")
+	}
+}
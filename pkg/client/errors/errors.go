@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors classifies the errors returned by clients and maps them to
+// the behavior recommended for controllers, replacing scattered checks with
+// the helpers of k8s.io/apimachinery/pkg/api/errors.
+//
+// A reconciler would typically use it as follows:
+//
+//	if err := r.Client.Update(ctx, obj); err != nil {
+//		if delay, requeue := clienterrors.ShouldRequeue(err); requeue {
+//			return reconcile.Result{RequeueAfter: delay}, nil
+//		}
+//		return reconcile.Result{}, reconcile.TerminalError(err)
+//	}
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// Class is the class of an error, which determines how a controller should
+// react to it.
+type Class string
+
+const (
+	// ClassNone is the class of nil errors.
+	ClassNone Class = "None"
+
+	// ClassNotFound is the class of errors indicating that the object
+	// doesn't exist (anymore). They usually mean there is nothing to do.
+	ClassNotFound Class = "NotFound"
+
+	// ClassConflict is the class of errors indicating that the object was
+	// modified concurrently or already exists. The request should be retried
+	// after reading the latest version of the object.
+	ClassConflict Class = "Conflict"
+
+	// ClassThrottled is the class of errors indicating that the API server
+	// asked the client to slow down. The request should be retried after the
+	// suggested delay.
+	ClassThrottled Class = "Throttled"
+
+	// ClassTransient is the class of errors that are likely to go away when
+	// retrying, such as timeouts, network errors and internal server errors.
+	ClassTransient Class = "Transient"
+
+	// ClassPermanent is the class of errors that won't go away by retrying
+	// the same request, such as invalid objects or missing permissions.
+	ClassPermanent Class = "Permanent"
+
+	// ClassUnknown is the class of errors that can't be classified.
+	ClassUnknown Class = "Unknown"
+)
+
+// Classify returns the class of err.
+func Classify(err error) Class {
+	switch {
+	case err == nil:
+		return ClassNone
+	case IsNotFoundOrGone(err):
+		return ClassNotFound
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return ClassConflict
+	case apierrors.IsTooManyRequests(err):
+		return ClassThrottled
+	case apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsInternalError(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsUnexpectedServerError(err),
+		isNetworkError(err):
+		return ClassTransient
+	case apierrors.IsInvalid(err),
+		apierrors.IsBadRequest(err),
+		apierrors.IsForbidden(err),
+		apierrors.IsUnauthorized(err),
+		apierrors.IsMethodNotSupported(err),
+		apierrors.IsNotAcceptable(err),
+		apierrors.IsUnsupportedMediaType(err),
+		apierrors.IsRequestEntityTooLargeError(err):
+		return ClassPermanent
+	default:
+		return ClassUnknown
+	}
+}
+
+// IsNotFoundOrGone returns true if err indicates that the object doesn't
+// exist, or that the requested resource is gone.
+func IsNotFoundOrGone(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsGone(err) || apierrors.IsResourceExpired(err)
+}
+
+// IsRetryable returns true if retrying the request that failed with err may
+// succeed, possibly after reading the latest version of the object.
+func IsRetryable(err error) bool {
+	switch Classify(err) {
+	case ClassConflict, ClassThrottled, ClassTransient:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPermanent returns true if retrying the request that failed with err
+// is not expected to succeed.
+func IsPermanent(err error) bool {
+	return Classify(err) == ClassPermanent
+}
+
+// ShouldRequeue returns whether a reconcile that failed with err should be
+// requeued and, if so, after which delay. A zero delay means the request
+// should be requeued with the controller's rate limiting.
+//
+// Errors that can't be classified are requeued, as returning them from a
+// reconciler would. Permanent errors are not requeued; they should usually
+// be surfaced as a condition on the object and returned as a
+// reconcile.TerminalError.
+func ShouldRequeue(err error) (time.Duration, bool) {
+	switch Classify(err) {
+	case ClassNone, ClassNotFound, ClassPermanent:
+		return 0, false
+	case ClassThrottled:
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+			return time.Duration(seconds) * time.Second, true
+		}
+		return 0, true
+	default:
+		return 0, true
+	}
+}
+
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err)
+}
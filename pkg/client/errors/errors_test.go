@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	clienterrors "sigs.k8s.io/controller-runtime/pkg/client/errors"
+)
+
+func TestClassify(t *testing.T) {
+	gr := schema.GroupResource{Resource: "configmaps"}
+
+	for _, tc := range []struct {
+		name          string
+		err           error
+		expected      clienterrors.Class
+		expectRequeue bool
+		expectDelay   time.Duration
+	}{
+		{name: "nil", err: nil, expected: clienterrors.ClassNone},
+		{name: "not found", err: apierrors.NewNotFound(gr, "foo"), expected: clienterrors.ClassNotFound},
+		{name: "gone", err: apierrors.NewGone("gone"), expected: clienterrors.ClassNotFound},
+		{name: "conflict", err: apierrors.NewConflict(gr, "foo", errors.New("modified")), expected: clienterrors.ClassConflict, expectRequeue: true},
+		{name: "already exists", err: apierrors.NewAlreadyExists(gr, "foo"), expected: clienterrors.ClassConflict, expectRequeue: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 3), expected: clienterrors.ClassThrottled, expectRequeue: true, expectDelay: 3 * time.Second},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("boom")), expected: clienterrors.ClassTransient, expectRequeue: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), expected: clienterrors.ClassTransient, expectRequeue: true},
+		{name: "deadline exceeded", err: fmt.Errorf("get: %w", context.DeadlineExceeded), expected: clienterrors.ClassTransient, expectRequeue: true},
+		{name: "eof", err: fmt.Errorf("get: %w", io.EOF), expected: clienterrors.ClassTransient, expectRequeue: true},
+		{name: "invalid", err: apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "foo", nil), expected: clienterrors.ClassPermanent},
+		{name: "forbidden", err: apierrors.NewForbidden(gr, "foo", errors.New("denied")), expected: clienterrors.ClassPermanent},
+		{name: "bad request", err: apierrors.NewBadRequest("bad"), expected: clienterrors.ClassPermanent},
+		{name: "unknown", err: errors.New("something"), expected: clienterrors.ClassUnknown, expectRequeue: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if class := clienterrors.Classify(tc.err); class != tc.expected {
+				t.Errorf("unexpected class: expected=%s; got=%s", tc.expected, class)
+			}
+			delay, requeue := clienterrors.ShouldRequeue(tc.err)
+			if requeue != tc.expectRequeue || delay != tc.expectDelay {
+				t.Errorf("unexpected requeue: expected=(%s, %t); got=(%s, %t)", tc.expectDelay, tc.expectRequeue, delay, requeue)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	gr := schema.GroupResource{Resource: "configmaps"}
+
+	if !clienterrors.IsRetryable(apierrors.NewConflict(gr, "foo", errors.New("modified"))) {
+		t.Error("expected conflicts to be retryable")
+	}
+	if clienterrors.IsRetryable(apierrors.NewForbidden(gr, "foo", errors.New("denied"))) {
+		t.Error("expected forbidden errors not to be retryable")
+	}
+	if !clienterrors.IsPermanent(apierrors.NewForbidden(gr, "foo", errors.New("denied"))) {
+		t.Error("expected forbidden errors to be permanent")
+	}
+	if !clienterrors.IsNotFoundOrGone(fmt.Errorf("wrapped: %w", apierrors.NewNotFound(gr, "foo"))) {
+		t.Error("expected wrapped not found errors to be detected")
+	}
+}
@@ -54,6 +54,7 @@ import (
 	"k8s.io/client-go/testing"
 	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -111,6 +112,7 @@ type ClientBuilder struct {
 	withStatusSubresource []client.Object
 	objectTracker         testing.ObjectTracker
 	interceptorFuncs      *interceptor.Funcs
+	informers             cache.Informers
 
 	// indexes maps each GroupVersionKind (GVK) to the indexes registered for that GVK.
 	// The inner map maps from index name to IndexerFunc.
@@ -208,6 +210,18 @@ func (f *ClientBuilder) WithInterceptorFuncs(interceptorFuncs interceptor.Funcs)
 	return f
 }
 
+// WithInformers makes the client deliver the objects it creates, updates and
+// deletes as events to the fake informers of informers, typically an
+// informertest.FakeInformers, so that the event handlers, predicates and
+// reconcilers of a controller watching them can be exercised together in a
+// unit test. Informers whose events can't be injected, i.e. that are not a
+// controllertest.FakeInformer, are ignored, and so are the objects the
+// client is built with.
+func (f *ClientBuilder) WithInformers(informers cache.Informers) *ClientBuilder {
+	f.informers = informers
+	return f
+}
+
 // Build builds and returns a new fake client.
 func (f *ClientBuilder) Build() client.WithWatch {
 	if f.scheme == nil {
@@ -228,11 +242,14 @@ func (f *ClientBuilder) Build() client.WithWatch {
 		withStatusSubResource.Insert(gvk)
 	}
 
-	if f.objectTracker == nil {
-		tracker = versionedTracker{ObjectTracker: testing.NewObjectTracker(f.scheme, scheme.Codecs.UniversalDecoder()), scheme: f.scheme, withStatusSubresource: withStatusSubResource}
-	} else {
-		tracker = versionedTracker{ObjectTracker: f.objectTracker, scheme: f.scheme, withStatusSubresource: withStatusSubResource}
+	objectTracker := f.objectTracker
+	if objectTracker == nil {
+		objectTracker = testing.NewObjectTracker(f.scheme, scheme.Codecs.UniversalDecoder())
+	}
+	if f.informers != nil {
+		objectTracker = notifyingTracker{ObjectTracker: objectTracker, scheme: f.scheme, informers: f.informers}
 	}
+	tracker = versionedTracker{ObjectTracker: objectTracker, scheme: f.scheme, withStatusSubresource: withStatusSubResource}
 
 	for _, obj := range f.initObject {
 		if err := tracker.Add(obj); err != nil {
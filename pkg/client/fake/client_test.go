@@ -43,11 +43,18 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -2273,4 +2280,50 @@ var _ = Describe("Fake client builder", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(called).To(BeTrue())
 	})
+
+	It("should deliver its writes to the informers passed to WithInformers", func() {
+		ctx := context.Background()
+		informers := &informertest.FakeInformers{}
+		cli := NewClientBuilder().WithInformers(informers).Build()
+
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer queue.ShutDown()
+		src := source.Kind(informers, &appsv1.Deployment{}, &handler.TypedEnqueueRequestForObject[*appsv1.Deployment]{},
+			predicate.TypedGenerationChangedPredicate[*appsv1.Deployment]{})
+		Expect(src.Start(ctx, queue)).To(Succeed())
+		Expect(src.WaitForSync(ctx)).To(Succeed())
+
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "dep", Finalizers: []string{"test"}}}
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dep)}
+		Expect(cli.Create(ctx, dep)).To(Succeed())
+		Eventually(queue.Len).Should(Equal(1))
+		item, _ := queue.Get()
+		Expect(item).To(Equal(req))
+		queue.Done(item)
+
+		By("filtering updates with the predicates of the source")
+		dep.Labels = map[string]string{"foo": "bar"}
+		Expect(cli.Update(ctx, dep)).To(Succeed())
+		dep.Generation++
+		Expect(cli.Patch(ctx, dep, client.MergeFrom(&appsv1.Deployment{}))).To(Succeed())
+		Eventually(queue.Len).Should(Equal(1))
+		item, _ = queue.Get()
+		Expect(item).To(Equal(req))
+		queue.Done(item)
+
+		By("delivering deletions once the finalizers are removed")
+		var deleted []string
+		informer, err := informers.FakeInformerFor(ctx, &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj any) { deleted = append(deleted, obj.(*appsv1.Deployment).Name) },
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cli.Delete(ctx, dep)).To(Succeed())
+		Expect(deleted).To(BeEmpty())
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(dep), dep)).To(Succeed())
+		dep.Finalizers = nil
+		Expect(cli.Update(ctx, dep)).To(Succeed())
+		Expect(deleted).To(Equal([]string{"dep"}))
+	})
 })
@@ -24,6 +24,10 @@ You can create a fake client with optional objects.
 
 You can invoke the methods defined in the Client interface.
 
+The writes of the client can be delivered as events to fake informers, e.g.
+those of an informertest.FakeInformers passed to WithInformers, to exercise
+the event handlers, predicates and reconcilers of a controller together.
+
 When in doubt, it's almost always better not to use this package and instead use
 envtest.Environment with a real client and API server.
 
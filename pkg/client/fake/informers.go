@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// eventInjector is implemented by fake informers whose events can be
+// injected, such as controllertest.FakeInformer.
+type eventInjector interface {
+	Add(obj metav1.Object)
+	Update(oldObj, newObj metav1.Object)
+	Delete(obj metav1.Object)
+}
+
+// notifyingTracker delivers the objects written to its tracker as events to
+// the fake informers of informers.
+type notifyingTracker struct {
+	testing.ObjectTracker
+	scheme    *runtime.Scheme
+	informers cache.Informers
+}
+
+func (t notifyingTracker) Create(gvr schema.GroupVersionResource, obj runtime.Object, ns string, opts ...metav1.CreateOptions) error {
+	if err := t.ObjectTracker.Create(gvr, obj, ns, opts...); err != nil {
+		return err
+	}
+	t.notify(gvr, ns, obj, nil)
+	return nil
+}
+
+func (t notifyingTracker) Update(gvr schema.GroupVersionResource, obj runtime.Object, ns string, opts ...metav1.UpdateOptions) error {
+	old := t.get(gvr, ns, obj)
+	if err := t.ObjectTracker.Update(gvr, obj, ns, opts...); err != nil {
+		return err
+	}
+	t.notify(gvr, ns, obj, old)
+	return nil
+}
+
+func (t notifyingTracker) Patch(gvr schema.GroupVersionResource, obj runtime.Object, ns string, opts ...metav1.PatchOptions) error {
+	old := t.get(gvr, ns, obj)
+	if err := t.ObjectTracker.Patch(gvr, obj, ns, opts...); err != nil {
+		return err
+	}
+	t.notify(gvr, ns, obj, old)
+	return nil
+}
+
+func (t notifyingTracker) Apply(gvr schema.GroupVersionResource, applyConfiguration runtime.Object, ns string, opts ...metav1.PatchOptions) error {
+	old := t.get(gvr, ns, applyConfiguration)
+	if err := t.ObjectTracker.Apply(gvr, applyConfiguration, ns, opts...); err != nil {
+		return err
+	}
+	t.notify(gvr, ns, applyConfiguration, old)
+	return nil
+}
+
+func (t notifyingTracker) Delete(gvr schema.GroupVersionResource, ns, name string, opts ...metav1.DeleteOptions) error {
+	old, err := t.ObjectTracker.Get(gvr, ns, name)
+	if err != nil {
+		old = nil
+	}
+	if err := t.ObjectTracker.Delete(gvr, ns, name, opts...); err != nil {
+		return err
+	}
+	if injector, oldObj := t.injectorFor(old); injector != nil {
+		injector.Delete(oldObj)
+	}
+	return nil
+}
+
+// get returns the stored version of obj, or nil if there is none.
+func (t notifyingTracker) get(gvr schema.GroupVersionResource, ns string, obj runtime.Object) runtime.Object {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	old, err := t.ObjectTracker.Get(gvr, ns, accessor.GetName())
+	if err != nil {
+		return nil
+	}
+	return old
+}
+
+// notify delivers the stored version of obj to its informer, as an update
+// of old if it is set, or as an add otherwise.
+func (t notifyingTracker) notify(gvr schema.GroupVersionResource, ns string, obj, old runtime.Object) {
+	stored := t.get(gvr, ns, obj)
+	injector, newObj := t.injectorFor(stored)
+	if injector == nil {
+		return
+	}
+	if old == nil {
+		injector.Add(newObj)
+		return
+	}
+	oldObj, err := meta.Accessor(old)
+	if err != nil {
+		return
+	}
+	injector.Update(oldObj, newObj)
+}
+
+// injectorFor returns the informer of obj if its events can be injected.
+func (t notifyingTracker) injectorFor(obj runtime.Object) (eventInjector, metav1.Object) {
+	if obj == nil {
+		return nil, nil
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, nil
+	}
+	gvk, err := apiutil.GVKForObject(obj, t.scheme)
+	if err != nil {
+		return nil, nil
+	}
+	informer, err := t.informers.GetInformerForKind(context.Background(), gvk)
+	if err != nil {
+		return nil, nil
+	}
+	injector, ok := informer.(eventInjector)
+	if !ok {
+		return nil, nil
+	}
+	return injector, accessor
+}
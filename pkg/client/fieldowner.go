@@ -104,3 +104,43 @@ func (f *subresourceClientWithFieldOwner) Update(ctx context.Context, obj Object
 func (f *subresourceClientWithFieldOwner) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
 	return f.subresourceWriter.Patch(ctx, obj, patch, append([]SubResourcePatchOption{FieldOwner(f.owner)}, opts...)...)
 }
+
+type fieldManagerKey struct{}
+
+// WithContextFieldManager returns a copy of ctx in which the write requests
+// made by clients created with New default to the given field manager, so
+// that the managedFields of the objects they write attribute their changes
+// to it. Controllers call it before every reconcile if their FieldManager
+// option is set. A [FieldOwner] option, or a client wrapped with
+// WithFieldOwner, takes precedence.
+func WithContextFieldManager(ctx context.Context, fieldManager string) context.Context {
+	return context.WithValue(ctx, fieldManagerKey{}, fieldManager)
+}
+
+// FieldManagerFromContext returns the field manager carried by ctx, if any.
+func FieldManagerFromContext(ctx context.Context) (string, bool) {
+	fieldManager, ok := ctx.Value(fieldManagerKey{}).(string)
+	return fieldManager, ok && fieldManager != ""
+}
+
+// FieldOwner implements the options of all the writes that default to the
+// field manager carried by their context.
+var (
+	_ CreateOption            = FieldOwner("")
+	_ UpdateOption            = FieldOwner("")
+	_ PatchOption             = FieldOwner("")
+	_ SubResourceCreateOption = FieldOwner("")
+	_ SubResourceUpdateOption = FieldOwner("")
+	_ SubResourcePatchOption  = FieldOwner("")
+)
+
+// withContextFieldOwner prepends the field manager carried by ctx, if any,
+// to opts, so that the options set by the caller override it. option must
+// be one of the option types implemented by FieldOwner above.
+func withContextFieldOwner[option any](ctx context.Context, opts []option) []option {
+	fieldManager, ok := FieldManagerFromContext(ctx)
+	if !ok {
+		return opts
+	}
+	return append([]option{any(FieldOwner(fieldManager)).(option)}, opts...)
+}
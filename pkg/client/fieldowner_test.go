@@ -18,9 +18,16 @@ package client_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -73,6 +80,56 @@ func TestWithFieldOwnerOverridden(t *testing.T) {
 	}
 }
 
+func TestWithContextFieldManager(t *testing.T) {
+	var mu sync.Mutex
+	var fieldManagers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fieldManagers = append(fieldManagers, r.URL.Query().Get("fieldManager"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"foo"}}`))
+	}))
+	defer srv.Close()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	c, err := client.New(&rest.Config{Host: srv.URL}, client.Options{Mapper: mapper})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	ctx := client.WithContextFieldManager(context.Background(), "my-controller")
+	if err := c.Create(ctx, ns); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if err := c.Status().Update(ctx, ns); err != nil {
+		t.Fatalf("status update failed: %v", err)
+	}
+	if err := c.Update(ctx, ns, client.FieldOwner("explicit")); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := client.WithFieldOwner(c, "wrapper").Update(ctx, ns); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := c.Update(context.Background(), ns); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	expected := []string{"my-controller", "my-controller", "explicit", "wrapper", ""}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(fieldManagers) != len(expected) {
+		t.Fatalf("wrong number of requests: expected=%d; got=%d", len(expected), len(fieldManagers))
+	}
+	for i := range expected {
+		if fieldManagers[i] != expected[i] {
+			t.Fatalf("wrong field manager of request %d: expected=%q; got=%q", i, expected[i], fieldManagers[i])
+		}
+	}
+}
+
 // testClient is a helper function that checks if calls have the expected field manager,
 // and calls the callback function on each intercepted call.
 func testClient(t *testing.T, expectedFieldManager string, callback func()) client.Client {
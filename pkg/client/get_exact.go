@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// getExact gets obj at exactly the resourceVersion of opts. The API server
+// only supports exact resourceVersion matches for lists, so the object is
+// listed with a field selector on its name.
+func (c *client) getExact(ctx context.Context, key ObjectKey, obj Object, opts *GetOptions) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+
+	listOpts := &ListOptions{
+		Namespace:     key.Namespace,
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", key.Name),
+		Raw: &metav1.ListOptions{
+			ResourceVersion:      opts.AsGetOptions().ResourceVersion,
+			ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+		},
+	}
+	var list ObjectList
+	switch obj.(type) {
+	case runtime.Unstructured:
+		ul := &unstructured.UnstructuredList{}
+		ul.SetGroupVersionKind(listGVK)
+		list = ul
+		err = c.unstructuredClient.List(ctx, list, listOpts)
+	case *metav1.PartialObjectMetadata:
+		pl := &metav1.PartialObjectMetadataList{}
+		pl.SetGroupVersionKind(listGVK)
+		list = pl
+		err = c.metadataClient.List(ctx, list, listOpts)
+	default:
+		ro, newErr := c.scheme.New(listGVK)
+		if newErr != nil {
+			return newErr
+		}
+		var ok bool
+		if list, ok = ro.(ObjectList); !ok {
+			return fmt.Errorf("%s is not an ObjectList", listGVK)
+		}
+		err = c.typedClient.List(ctx, list, listOpts)
+	}
+	if err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+		return apierrors.NewNotFound(mapping.Resource.GroupResource(), key.Name)
+	}
+
+	switch out := obj.(type) {
+	case runtime.Unstructured:
+		item, ok := items[0].(runtime.Unstructured)
+		if !ok {
+			return fmt.Errorf("list of %s contained %T", gvk, items[0])
+		}
+		out.SetUnstructuredContent(item.UnstructuredContent())
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	default:
+		outVal, itemVal := reflect.ValueOf(obj), reflect.ValueOf(items[0])
+		if itemVal.Type() != outVal.Type() {
+			return fmt.Errorf("list of %s contained %T, expected %T", gvk, items[0], obj)
+		}
+		outVal.Elem().Set(itemVal.Elem())
+		if _, isMetadata := obj.(*metav1.PartialObjectMetadata); isMetadata {
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countingReader is a cache reader counting the gets it serves.
+type countingReader struct {
+	client.Reader
+	gets int
+}
+
+func (r *countingReader) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	r.gets++
+	return nil
+}
+
+func TestGetResourceVersionAndConsistency(t *testing.T) {
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		cm := corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", ResourceVersion: "5"},
+			Data:       map[string]string{"key": "value"},
+		}
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/configmaps/a":
+			_ = json.NewEncoder(w).Encode(cm)
+		case "/api/v1/namespaces/default/configmaps":
+			list := corev1.ConfigMapList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"}}
+			if req.URL.Query().Get("fieldSelector") == "metadata.name=a" {
+				list.Items = append(list.Items, cm)
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	cache := &countingReader{}
+	c, err := client.New(&rest.Config{Host: srv.URL}, client.Options{
+		Mapper: mapper,
+		Cache:  &client.CacheOptions{Reader: cache, Unstructured: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	key := client.ObjectKey{Namespace: "default", Name: "a"}
+
+	if err := c.Get(ctx, key, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cache.gets != 1 || len(queries) != 0 {
+		t.Fatalf("expected the get to be served by the cache, got %d cache gets and %d requests", cache.gets, len(queries))
+	}
+
+	if err := c.Get(ctx, key, &corev1.ConfigMap{}, client.ConsistentRead{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 1 || queries[0].Has("resourceVersion") {
+		t.Fatalf("expected a consistent read from the API server, got queries %v", queries)
+	}
+
+	notOlderThan := client.MatchingResourceVersion{ResourceVersion: "4", Match: metav1.ResourceVersionMatchNotOlderThan}
+	if err := c.Get(ctx, key, &corev1.ConfigMap{}, notOlderThan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 2 || queries[1].Get("resourceVersion") != "4" {
+		t.Fatalf("expected a read not older than 4 from the API server, got queries %v", queries)
+	}
+
+	exact := client.MatchingResourceVersion{ResourceVersion: "5", Match: metav1.ResourceVersionMatchExact}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm, exact); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 3 || queries[2].Get("resourceVersion") != "5" || queries[2].Get("resourceVersionMatch") != "Exact" {
+		t.Fatalf("expected an exact list from the API server, got queries %v", queries)
+	}
+	if cm.Name != "a" || cm.ResourceVersion != "5" || cm.Data["key"] != "value" {
+		t.Fatalf("unexpected object: %+v", cm)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	if err := c.Get(ctx, key, u, exact); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.GetName() != "a" || u.GetKind() != "ConfigMap" {
+		t.Fatalf("unexpected unstructured object: %v", u.Object)
+	}
+
+	err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "b"}, &corev1.ConfigMap{}, exact)
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected a NotFound error, got %v", err)
+	}
+	if cache.gets != 1 {
+		t.Fatalf("expected reads with options to bypass the cache, got %d cache gets", cache.gets)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// OversizedObjects is a prometheus counter which holds the number of
+	// objects seen by a size-guarded client whose serialized size exceeded
+	// the configured limit.
+	OversizedObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_client_oversized_objects_total",
+		Help: "Total number of objects exceeding the configured size limit per group, kind and verb",
+	}, []string{"group", "kind", "verb"})
+
+	// OversizedLists is a prometheus counter which holds the number of
+	// list responses seen by a size-guarded client whose serialized size
+	// exceeded the configured memory budget.
+	OversizedLists = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_client_oversized_lists_total",
+		Help: "Total number of list responses exceeding the configured memory budget per group and kind",
+	}, []string{"group", "kind"})
+
+	// ThrottledSeconds is a prometheus counter which holds the time spent
+	// by a retrying client waiting for the delay suggested by the API
+	// server in a Retry-After response.
+	ThrottledSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_client_throttled_seconds_total",
+		Help: "Total number of seconds spent waiting for the API server suggested Retry-After delay per verb",
+	}, []string{"verb"})
+
+	// UncachedReads is a prometheus counter which holds the number of reads
+	// made through a budgeted uncached reader.
+	UncachedReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_client_uncached_reads_total",
+		Help: "Total number of reads bypassing the cache per controller and verb",
+	}, []string{"controller", "verb"})
+
+	// UncachedReadBudgetExceeded is a prometheus counter which holds the
+	// number of uncached reads exceeding the budget of a reconcile.
+	UncachedReadBudgetExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_client_uncached_read_budget_exceeded_total",
+		Help: "Total number of reads bypassing the cache beyond the per-reconcile budget per controller",
+	}, []string{"controller"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		OversizedObjects,
+		OversizedLists,
+		ThrottledSeconds,
+		UncachedReads,
+		UncachedReadBudgetExceeded,
+	)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ManagedByLabel is the standard label naming the tool managing an
+	// object.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+
+	// PartOfLabel is the standard label naming the higher level application
+	// an object is part of.
+	PartOfLabel = "app.kubernetes.io/part-of"
+)
+
+// LabelPolicy configures the labels stamped on objects by a client returned
+// from [WithLabelPolicy].
+type LabelPolicy struct {
+	// ManagedBy is the value of the app.kubernetes.io/managed-by label.
+	// The label is not set if empty.
+	ManagedBy string
+
+	// PartOf is the value of the app.kubernetes.io/part-of label. The label
+	// is not set if empty.
+	PartOf string
+
+	// ControllerLabel, if set, is the key of a label set to the name of the
+	// controller that creates the object, for objects created while
+	// reconciling by the controllers of a manager whose Client is the one
+	// returned by WithLabelPolicy. See WithReadScope.
+	ControllerLabel string
+
+	// Labels are additional labels set on objects.
+	Labels map[string]string
+
+	// ExcludeKinds lists the kinds of objects that are not labeled, e.g.
+	// Events.
+	ExcludeKinds []schema.GroupKind
+
+	// Exclude, if set, is called for every object, and the objects it
+	// returns true for are not labeled.
+	Exclude func(gvk schema.GroupVersionKind, obj Object) bool
+}
+
+// WithLabelPolicy wraps a Client and stamps the labels of policy on the
+// objects created through it, so that every object an operator creates can
+// be attributed to it, e.g. with
+//
+//	kubectl get all -l app.kubernetes.io/managed-by=my-operator
+//
+// Labels already set on an object are not overwritten. Objects are only
+// labeled when they are created; updates and patches are left as is.
+func WithLabelPolicy(c Client, policy LabelPolicy) Client {
+	return &clientWithLabelPolicy{
+		Client: c,
+		policy: policy,
+	}
+}
+
+type clientWithLabelPolicy struct {
+	Client
+	policy LabelPolicy
+}
+
+func (c *clientWithLabelPolicy) usesReadScope() bool {
+	return c.policy.ControllerLabel != ""
+}
+
+func (c *clientWithLabelPolicy) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := c.stamp(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *clientWithLabelPolicy) stamp(ctx context.Context, obj Object) error {
+	if len(c.policy.ExcludeKinds) > 0 || c.policy.Exclude != nil {
+		gvk, err := c.Client.GroupVersionKindFor(obj)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(c.policy.ExcludeKinds, gvk.GroupKind()) {
+			return nil
+		}
+		if c.policy.Exclude != nil && c.policy.Exclude(gvk, obj) {
+			return nil
+		}
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	setDefault := func(key, value string) {
+		if _, ok := labels[key]; !ok && value != "" {
+			labels[key] = value
+		}
+	}
+	setDefault(ManagedByLabel, c.policy.ManagedBy)
+	setDefault(PartOfLabel, c.policy.PartOf)
+	if scope, ok := ctx.Value(readScopeKey{}).(*readScope); ok && c.policy.ControllerLabel != "" {
+		setDefault(c.policy.ControllerLabel, scope.controller)
+	}
+	for key, value := range c.policy.Labels {
+		setDefault(key, value)
+	}
+	if len(labels) > 0 {
+		obj.SetLabels(labels)
+	}
+	return nil
+}
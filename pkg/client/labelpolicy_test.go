@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithLabelPolicy(t *testing.T) {
+	c := client.WithLabelPolicy(fake.NewClientBuilder().Build(), client.LabelPolicy{
+		ManagedBy:       "my-operator",
+		PartOf:          "my-app",
+		ControllerLabel: "example.com/controller",
+		Labels:          map[string]string{"team": "a"},
+		ExcludeKinds:    []schema.GroupKind{{Kind: "Event"}},
+		Exclude: func(_ schema.GroupVersionKind, obj client.Object) bool {
+			return obj.GetName() == "excluded"
+		},
+	})
+	ctx := client.WithReadScope(context.Background(), "configmap-controller")
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "cm",
+		Namespace: "default",
+		Labels:    map[string]string{"team": "b"},
+	}}
+	if err := c.Create(ctx, cm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		client.ManagedByLabel:    "my-operator",
+		client.PartOfLabel:       "my-app",
+		"example.com/controller": "configmap-controller",
+		"team":                   "b",
+	}
+	for key, value := range expected {
+		if cm.Labels[key] != value {
+			t.Fatalf("expected label %s=%s, got labels %v", key, value, cm.Labels)
+		}
+	}
+
+	cm.Labels = nil
+	if err := c.Update(ctx, cm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cm.Labels) != 0 {
+		t.Fatalf("expected updates not to be labeled, got labels %v", cm.Labels)
+	}
+
+	for _, obj := range []client.Object{
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "event", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "excluded", Namespace: "default"}},
+	} {
+		if err := c.Create(ctx, obj); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(obj.GetLabels()) != 0 {
+			t.Fatalf("expected %s not to be labeled, got labels %v", obj.GetName(), obj.GetLabels())
+		}
+	}
+}
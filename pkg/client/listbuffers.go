@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+// listBufferPool pools the buffers that the responses of list requests are
+// read into.
+type listBufferPool struct {
+	pool sync.Pool
+}
+
+func newListBufferPool() *listBufferPool {
+	return &listBufferPool{pool: sync.Pool{New: func() any { return &bytes.Buffer{} }}}
+}
+
+// listInto performs the list request req, reading its response into a pooled
+// buffer and decoding it into the items of list, whose capacity is reused.
+func (p *listBufferPool) listInto(ctx context.Context, req *rest.Request, decoder runtime.Decoder, list ObjectList) error {
+	body, err := req.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	buf := p.pool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		p.pool.Put(buf)
+	}()
+	if _, err := buf.ReadFrom(body); err != nil {
+		return err
+	}
+
+	if err := resetListItems(list); err != nil {
+		return err
+	}
+	return runtime.DecodeInto(decoder, buf.Bytes(), list)
+}
+
+// resetListItems empties the items of list, keeping their capacity. The items
+// are zeroed, since decoding reuses the existing items of a slice and would
+// otherwise keep the fields of the previous items that a new item doesn't
+// set.
+func resetListItems(list ObjectList) error {
+	itemsPtr, err := meta.GetItemsPtr(list)
+	if err != nil {
+		return err
+	}
+	items := reflect.ValueOf(itemsPtr).Elem()
+	if items.Kind() != reflect.Slice {
+		return nil
+	}
+	items.Slice(0, items.Cap()).Clear()
+	items.SetLen(0)
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReuseListBuffers(t *testing.T) {
+	responses := []*corev1.PodList{
+		{Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"app": "a"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		}},
+		{Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+		}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp := responses[0]
+		responses = responses[1:]
+		resp.APIVersion, resp.Kind = "v1", "PodList"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	c, err := client.New(&rest.Config{Host: srv.URL}, client.Options{Mapper: mapper, ReuseListBuffers: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list := &corev1.PodList{}
+	if err := c.List(context.Background(), list, client.InNamespace("default")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Items) != 2 || list.Items[0].Name != "a" || list.Items[0].Labels["app"] != "a" || list.Items[1].Name != "b" {
+		t.Fatalf("unexpected items: %+v", list.Items)
+	}
+	first := &list.Items[0]
+
+	if err := c.List(context.Background(), list, client.InNamespace("default")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "c" {
+		t.Fatalf("unexpected items: %+v", list.Items)
+	}
+	if list.Items[0].Labels != nil {
+		t.Fatalf("expected the fields of previous items to be reset, got labels %v", list.Items[0].Labels)
+	}
+	if &list.Items[0] != first {
+		t.Fatal("expected the items of the list to be reused")
+	}
+}
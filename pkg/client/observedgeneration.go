@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WithObservedGeneration wraps a Client and sets status.observedGeneration
+// to metadata.generation on the objects written through its status
+// subresource, following the convention of most CRDs to report the
+// generation their status reflects. See SetObservedGeneration for the
+// objects it applies to.
+//
+// The field is set on the object before the write, so that updates, merge
+// patches computed with MergeFrom and apply patches include it.
+func WithObservedGeneration(c Client) Client {
+	return &clientWithObservedGeneration{Client: c}
+}
+
+type clientWithObservedGeneration struct {
+	Client
+}
+
+func (c *clientWithObservedGeneration) Status() SubResourceWriter {
+	return &statusWriterWithObservedGeneration{SubResourceWriter: c.Client.Status()}
+}
+
+func (c *clientWithObservedGeneration) SubResource(subResource string) SubResourceClient {
+	sc := c.Client.SubResource(subResource)
+	if subResource != "status" {
+		return sc
+	}
+	return &subResourceClientWithObservedGeneration{
+		SubResourceReader: sc,
+		SubResourceWriter: &statusWriterWithObservedGeneration{SubResourceWriter: sc},
+	}
+}
+
+type subResourceClientWithObservedGeneration struct {
+	SubResourceReader
+	SubResourceWriter
+}
+
+type statusWriterWithObservedGeneration struct {
+	SubResourceWriter
+}
+
+func (s *statusWriterWithObservedGeneration) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	SetObservedGeneration(obj)
+	return s.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (s *statusWriterWithObservedGeneration) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	SetObservedGeneration(obj)
+	return s.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// SetObservedGeneration sets status.observedGeneration of obj to its
+// metadata.generation, and returns whether obj has that field: typed objects
+// whose Status struct has an int64 ObservedGeneration field, and
+// unstructured objects whose status already holds observedGeneration. The
+// schema of unstructured objects is unknown, so the field is not added to
+// them.
+func SetObservedGeneration(obj Object) bool {
+	generation := obj.GetGeneration()
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if _, found, err := unstructured.NestedFieldNoCopy(u.Object, "status", "observedGeneration"); err != nil || !found {
+			return false
+		}
+		return unstructured.SetNestedField(u.Object, generation, "status", "observedGeneration") == nil
+	}
+
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	status := v.Elem().FieldByName("Status")
+	if status.Kind() == reflect.Ptr {
+		if status.IsNil() {
+			return false
+		}
+		status = status.Elem()
+	}
+	if status.Kind() != reflect.Struct {
+		return false
+	}
+	observedGeneration := status.FieldByName("ObservedGeneration")
+	if observedGeneration.Kind() != reflect.Int64 || !observedGeneration.CanSet() {
+		return false
+	}
+	observedGeneration.SetInt(generation)
+	return true
+}
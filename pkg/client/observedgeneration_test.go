@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithObservedGeneration(t *testing.T) {
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default", Generation: 3}}
+	c := client.WithObservedGeneration(fake.NewClientBuilder().
+		WithObjects(deploy).
+		WithStatusSubresource(deploy).
+		Build())
+	ctx := context.Background()
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deploy), deploy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deploy.Status.Replicas = 1
+	if err := c.Status().Update(ctx, deploy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deploy), updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Status.ObservedGeneration != 3 {
+		t.Fatalf("expected observedGeneration to be set on update, got %d", updated.Status.ObservedGeneration)
+	}
+
+	updated.Generation = 4
+	base := updated.DeepCopy()
+	updated.Status.Replicas = 2
+	if err := c.SubResource("status").Patch(ctx, updated, client.MergeFrom(base)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	patched := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deploy), patched); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patched.Status.ObservedGeneration != 4 || patched.Status.Replicas != 2 {
+		t.Fatalf("expected observedGeneration to be set on patch, got %+v", patched.Status)
+	}
+}
+
+func TestSetObservedGeneration(t *testing.T) {
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	if !client.SetObservedGeneration(deploy) || deploy.Status.ObservedGeneration != 2 {
+		t.Fatalf("expected observedGeneration to be set on a typed object, got %d", deploy.Status.ObservedGeneration)
+	}
+
+	if client.SetObservedGeneration(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Generation: 2}}) {
+		t.Fatal("expected objects without a status to be ignored")
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{}}}
+	u.SetGeneration(5)
+	if client.SetObservedGeneration(u) {
+		t.Fatal("expected unstructured objects without observedGeneration to be ignored")
+	}
+	if err := unstructured.SetNestedField(u.Object, int64(1), "status", "observedGeneration"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !client.SetObservedGeneration(u) {
+		t.Fatal("expected observedGeneration to be set on an unstructured object")
+	}
+	if got, _, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); got != 5 {
+		t.Fatalf("unexpected observedGeneration %d", got)
+	}
+}
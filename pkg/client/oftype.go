@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// TypedClient is a facade over a Client for a single object type. Its methods
+// accept and return T directly, so callers neither cast objects nor risk
+// passing an object of the wrong type. T must be a pointer to a typed object
+// registered with the scheme of the underlying client.
+type TypedClient[T Object] struct {
+	client Client
+}
+
+// OfType returns a TypedClient for T backed by c.
+//
+//	pods := client.OfType[*corev1.Pod](c)
+//	pod, err := pods.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"})
+func OfType[T Object](c Client) *TypedClient[T] {
+	return &TypedClient[T]{client: c}
+}
+
+// New returns a new, empty T.
+func (t *TypedClient[T]) New() T {
+	return reflect.New(reflect.TypeOf(*new(T)).Elem()).Interface().(T)
+}
+
+// Get retrieves the object for the given key.
+func (t *TypedClient[T]) Get(ctx context.Context, key ObjectKey, opts ...GetOption) (T, error) {
+	obj := t.New()
+	if err := t.client.Get(ctx, key, obj, opts...); err != nil {
+		return *new(T), err
+	}
+	return obj, nil
+}
+
+// List retrieves the objects matching the given options.
+func (t *TypedClient[T]) List(ctx context.Context, opts ...ListOption) ([]T, error) {
+	list, err := t.newList()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.client.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]T, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(T)
+		if !ok {
+			return nil, fmt.Errorf("expected list item of type %T, got %T", *new(T), item)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Create saves obj in the Kubernetes cluster.
+func (t *TypedClient[T]) Create(ctx context.Context, obj T, opts ...CreateOption) error {
+	return t.client.Create(ctx, obj, opts...)
+}
+
+// Update updates obj in the Kubernetes cluster.
+func (t *TypedClient[T]) Update(ctx context.Context, obj T, opts ...UpdateOption) error {
+	return t.client.Update(ctx, obj, opts...)
+}
+
+// Patch patches obj in the Kubernetes cluster.
+func (t *TypedClient[T]) Patch(ctx context.Context, obj T, patch Patch, opts ...PatchOption) error {
+	return t.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete deletes obj from the Kubernetes cluster.
+func (t *TypedClient[T]) Delete(ctx context.Context, obj T, opts ...DeleteOption) error {
+	return t.client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf deletes all objects of type T matching the given options.
+func (t *TypedClient[T]) DeleteAllOf(ctx context.Context, opts ...DeleteAllOfOption) error {
+	return t.client.DeleteAllOf(ctx, t.New(), opts...)
+}
+
+// Status returns a client for the status subresource of T.
+func (t *TypedClient[T]) Status() *TypedSubResourceClient[T] {
+	return t.SubResource("status")
+}
+
+// SubResource returns a client for the named subresource of T.
+func (t *TypedClient[T]) SubResource(subResource string) *TypedSubResourceClient[T] {
+	return &TypedSubResourceClient[T]{client: t.client.SubResource(subResource)}
+}
+
+// newList returns an empty list for T, looked up in the client's scheme.
+func (t *TypedClient[T]) newList() (ObjectList, error) {
+	gvk, err := t.client.GroupVersionKindFor(t.New())
+	if err != nil {
+		return nil, err
+	}
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	ro, err := t.client.Scheme().New(listGVK)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := ro.(ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ObjectList", listGVK)
+	}
+	return list, nil
+}
+
+// TypedSubResourceClient is a facade over a SubResourceClient for a single
+// object type.
+type TypedSubResourceClient[T Object] struct {
+	client SubResourceClient
+}
+
+// Get retrieves the subresource of obj into subResource.
+func (t *TypedSubResourceClient[T]) Get(ctx context.Context, obj T, subResource Object, opts ...SubResourceGetOption) error {
+	return t.client.Get(ctx, obj, subResource, opts...)
+}
+
+// Create creates the subresource of obj.
+func (t *TypedSubResourceClient[T]) Create(ctx context.Context, obj T, subResource Object, opts ...SubResourceCreateOption) error {
+	return t.client.Create(ctx, obj, subResource, opts...)
+}
+
+// Update updates the subresource of obj.
+func (t *TypedSubResourceClient[T]) Update(ctx context.Context, obj T, opts ...SubResourceUpdateOption) error {
+	return t.client.Update(ctx, obj, opts...)
+}
+
+// Patch patches the subresource of obj.
+func (t *TypedSubResourceClient[T]) Patch(ctx context.Context, obj T, patch Patch, opts ...SubResourcePatchOption) error {
+	return t.client.Patch(ctx, obj, patch, opts...)
+}
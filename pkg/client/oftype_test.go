@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOfType(t *testing.T) {
+	ctx := context.Background()
+	pods := client.OfType[*corev1.Pod](fake.NewClientBuilder().WithStatusSubresource(&corev1.Pod{}).Build())
+
+	for _, name := range []string{"a", "b"} {
+		pod := pods.New()
+		pod.Namespace = "default"
+		pod.Name = name
+		if err := pods.Create(ctx, pod); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	pod, err := pods.Get(ctx, client.ObjectKey{Namespace: "default", Name: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Name != "a" {
+		t.Fatalf("expected pod a, got %s", pod.Name)
+	}
+
+	pod.Status.Phase = corev1.PodRunning
+	if err := pods.Status().Update(ctx, pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod, err = pods.Get(ctx, client.ObjectKeyFromObject(pod)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		t.Fatalf("expected status to be updated, got phase %q", pod.Status.Phase)
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	pod.Labels = map[string]string{"app": "foo"}
+	if err := pods.Patch(ctx, pod, patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, err := pods.List(ctx, client.InNamespace("default"), client.MatchingLabels{"app": "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "a" {
+		t.Fatalf("expected to list pod a, got %v", list)
+	}
+
+	if err := pods.Delete(ctx, pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := pods.Get(ctx, client.ObjectKey{Namespace: "default", Name: "a"}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	if list, err = pods.List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "b" {
+		t.Fatalf("expected to list pod b, got %v", list)
+	}
+}
@@ -426,11 +426,22 @@ func (p PropagationPolicy) ApplyToDeleteAllOf(opts *DeleteAllOfOptions) {
 // {{{ Get Options
 
 // GetOptions contains options for get operation.
-// Now it only has a Raw field, with support for specific resourceVersion.
+// It has a Raw field, with support for specific resourceVersion, and
+// options selecting how fresh the returned object must be.
 type GetOptions struct {
 	// Raw represents raw GetOptions, as passed to the API server.  Note
 	// that these may not be respected by all implementations of interface.
 	Raw *metav1.GetOptions
+
+	// ResourceVersionMatch determines how the resourceVersion of Raw is
+	// matched, see MatchingResourceVersion. Gets with a ResourceVersionMatch
+	// bypass the cache of the client.
+	ResourceVersionMatch metav1.ResourceVersionMatch
+
+	// Consistent requests the latest state of the object with a quorum
+	// read, see ConsistentRead. Consistent gets bypass the cache of the
+	// client.
+	Consistent bool
 }
 
 var _ GetOption = &GetOptions{}
@@ -440,6 +451,17 @@ func (o *GetOptions) ApplyToGet(lo *GetOptions) {
 	if o.Raw != nil {
 		lo.Raw = o.Raw
 	}
+	if o.ResourceVersionMatch != "" {
+		lo.ResourceVersionMatch = o.ResourceVersionMatch
+	}
+	if o.Consistent {
+		lo.Consistent = true
+	}
+}
+
+// requiresLiveRead returns whether the options can't be served by a cache.
+func (o *GetOptions) requiresLiveRead() bool {
+	return o.Consistent || o.ResourceVersionMatch != ""
 }
 
 // AsGetOptions returns these options as a flattened metav1.GetOptions.
@@ -460,6 +482,52 @@ func (o *GetOptions) ApplyOptions(opts []GetOption) *GetOptions {
 	return o
 }
 
+// ConsistentRead makes Get return the latest state of the object, read
+// from etcd with a quorum read, bypassing the cache of the client. It is
+// meant for critical decision points of reconcilers, where acting on a stale
+// object from the cache is not acceptable, and costs a request to the API
+// server.
+type ConsistentRead struct{}
+
+// ApplyToGet applies this configuration to the given get options.
+func (ConsistentRead) ApplyToGet(opts *GetOptions) {
+	opts.Consistent = true
+	opts.ResourceVersionMatch = ""
+	if opts.Raw != nil {
+		raw := *opts.Raw
+		raw.ResourceVersion = ""
+		opts.Raw = &raw
+	}
+}
+
+// MatchingResourceVersion makes Get return the object at a resourceVersion
+// matching ResourceVersion, according to Match, bypassing the cache of the
+// client:
+//
+//   - ResourceVersionMatchNotOlderThan returns the object at
+//     ResourceVersion or newer, e.g. to read an object at least as recent
+//     as the one a previous write returned,
+//   - ResourceVersionMatchExact returns the object at exactly
+//     ResourceVersion, or an Expired error if that version has been
+//     compacted. The API server only supports exact matches for lists, so
+//     the object is listed with a field selector on its name.
+type MatchingResourceVersion struct {
+	ResourceVersion string
+	Match           metav1.ResourceVersionMatch
+}
+
+// ApplyToGet applies this configuration to the given get options.
+func (m MatchingResourceVersion) ApplyToGet(opts *GetOptions) {
+	raw := metav1.GetOptions{}
+	if opts.Raw != nil {
+		raw = *opts.Raw
+	}
+	raw.ResourceVersion = m.ResourceVersion
+	opts.Raw = &raw
+	opts.ResourceVersionMatch = m.Match
+	opts.Consistent = false
+}
+
 // }}}
 
 // {{{ List Options
@@ -81,6 +81,21 @@ var _ = Describe("GetOptions", func() {
 		o.ApplyToGet(newGetOpts)
 		Expect(newGetOpts).To(Equal(o))
 	})
+	It("Should set ResourceVersionMatch", func() {
+		newGetOpts := &client.GetOptions{}
+		client.MatchingResourceVersion{ResourceVersion: "RV0", Match: metav1.ResourceVersionMatchExact}.ApplyToGet(newGetOpts)
+		Expect(newGetOpts).To(Equal(&client.GetOptions{
+			Raw:                  &metav1.GetOptions{ResourceVersion: "RV0"},
+			ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+		}))
+	})
+	It("Should clear the resourceVersion of consistent reads", func() {
+		raw := &metav1.GetOptions{ResourceVersion: "RV0"}
+		newGetOpts := &client.GetOptions{Raw: raw}
+		client.ConsistentRead{}.ApplyToGet(newGetOpts)
+		Expect(newGetOpts).To(Equal(&client.GetOptions{Raw: &metav1.GetOptions{}, Consistent: true}))
+		Expect(raw.ResourceVersion).To(Equal("RV0"))
+	})
 })
 
 var _ = Describe("CreateOptions", func() {
@@ -20,9 +20,12 @@ import (
 	"fmt"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
 )
 
 var (
@@ -70,6 +73,19 @@ func (m MergeFromWithOptimisticLock) ApplyToMergeFrom(in *MergeFromOptions) {
 	in.OptimisticLock = true
 }
 
+// MergeFromWithResourceVersionFallback is like MergeFromWithOptimisticLock, but
+// tolerates objects without a resource version: the resource version of the
+// modified object is used if the original object has none, and the patch is
+// sent without optimistic locking if neither has one, e.g. when patching an
+// object built from scratch rather than read from the API server.
+type MergeFromWithResourceVersionFallback struct{}
+
+// ApplyToMergeFrom applies this configuration to the given patch options.
+func (m MergeFromWithResourceVersionFallback) ApplyToMergeFrom(in *MergeFromOptions) {
+	in.OptimisticLock = true
+	in.ResourceVersionFallback = true
+}
+
 // MergeFromOption is some configuration that modifies options for a merge-from patch data.
 type MergeFromOption interface {
 	// ApplyToMergeFrom applies this configuration to the given patch options.
@@ -82,6 +98,11 @@ type MergeFromOptions struct {
 	// patch data. If the `resourceVersion` field doesn't match what's stored,
 	// the operation results in a conflict and clients will need to try again.
 	OptimisticLock bool
+
+	// ResourceVersionFallback, when true, makes OptimisticLock fall back to the
+	// resource version of the modified object, and then to no locking at all,
+	// instead of failing when the original object has no resource version.
+	ResourceVersionFallback bool
 }
 
 type mergeFromPatch struct {
@@ -103,7 +124,10 @@ func (s *mergeFromPatch) Data(obj Object) ([]byte, error) {
 
 	if s.opts.OptimisticLock {
 		version := original.GetResourceVersion()
-		if len(version) == 0 {
+		if len(version) == 0 && s.opts.ResourceVersionFallback {
+			// An empty version removes the resource version from the patch.
+			version = modified.GetResourceVersion()
+		} else if len(version) == 0 {
 			return nil, fmt.Errorf("cannot use OptimisticLock, object %q does not have any resource version we can use", original)
 		}
 
@@ -178,6 +202,29 @@ func StrategicMergeFrom(obj Object, opts ...MergeFromOption) Patch {
 	return &mergeFromPatch{patchType: types.StrategicMergePatchType, createPatch: createStrategicMergePatch, from: obj, opts: *options}
 }
 
+// AutoMergeFrom creates a Patch with the given object as base, using the
+// strategic-merge-patch strategy for built-in Kubernetes types and the
+// merge-patch strategy for all other types, such as custom resources, which
+// don't support strategic-merge-patch. Built-in types are the typed objects
+// known to the client-go scheme; unstructured objects always use merge-patch.
+// See MergeFrom and StrategicMergeFrom for more details.
+func AutoMergeFrom(obj Object, opts ...MergeFromOption) Patch {
+	if supportsStrategicMergePatch(obj) {
+		return StrategicMergeFrom(obj, opts...)
+	}
+	return MergeFromWithOptions(obj, opts...)
+}
+
+// supportsStrategicMergePatch returns true if obj is a typed built-in object.
+func supportsStrategicMergePatch(obj Object) bool {
+	switch obj.(type) {
+	case runtime.Unstructured, *metav1.PartialObjectMetadata:
+		return false
+	}
+	_, _, err := scheme.Scheme.ObjectKinds(obj)
+	return err == nil
+}
+
 // mergePatch uses a raw merge strategy to patch the object.
 type mergePatch struct{}
 
@@ -26,6 +26,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func BenchmarkMergeFrom(b *testing.B) {
@@ -120,3 +124,75 @@ var _ = Describe("MergeFrom", func() {
 		Expect(data).To(Equal([]byte(`{"spec":{"activeDeadlineSeconds":9223372036854775800}}`)))
 	})
 })
+
+func TestMergeFromWithResourceVersionFallback(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		originalVersion  string
+		modifiedVersion  string
+		expectedPatchRaw string
+	}{
+		{
+			name:             "original resource version",
+			originalVersion:  "1",
+			modifiedVersion:  "2",
+			expectedPatchRaw: `{"data":{"key":"value"},"metadata":{"resourceVersion":"1"}}`,
+		},
+		{
+			name:             "modified resource version",
+			modifiedVersion:  "2",
+			expectedPatchRaw: `{"data":{"key":"value"},"metadata":{"resourceVersion":"2"}}`,
+		},
+		{
+			name:             "no resource version",
+			expectedPatchRaw: `{"data":{"key":"value"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			original := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ResourceVersion: tc.originalVersion}}
+			modified := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{ResourceVersion: tc.modifiedVersion},
+				Data:       map[string]string{"key": "value"},
+			}
+
+			data, err := MergeFromWithOptions(original, MergeFromWithResourceVersionFallback{}).Data(modified)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != tc.expectedPatchRaw {
+				t.Fatalf("unexpected patch: expected=%s; got=%s", tc.expectedPatchRaw, data)
+			}
+		})
+	}
+}
+
+func TestAutoMergeFrom(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+
+	for _, tc := range []struct {
+		name     string
+		obj      Object
+		expected types.PatchType
+	}{
+		{name: "built-in type", obj: &appsv1.Deployment{}, expected: types.StrategicMergePatchType},
+		{name: "unstructured", obj: crd, expected: types.MergePatchType},
+		{name: "partial object metadata", obj: &metav1.PartialObjectMetadata{}, expected: types.MergePatchType},
+		{name: "unknown type", obj: &unknownType{}, expected: types.MergePatchType},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if patchType := AutoMergeFrom(tc.obj).Type(); patchType != tc.expected {
+				t.Fatalf("unexpected patch type: expected=%s; got=%s", tc.expected, patchType)
+			}
+		})
+	}
+}
+
+type unknownType struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func (u *unknownType) DeepCopyObject() runtime.Object {
+	return &unknownType{TypeMeta: u.TypeMeta, ObjectMeta: *u.ObjectMeta.DeepCopy()}
+}
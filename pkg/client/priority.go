@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// PriorityClass names a class of requests that the kube-apiserver's API
+// Priority and Fairness (APF) should classify separately, e.g. background
+// resyncs versus interactive reconciles. FlowSchemas match requests by the
+// identity making them, so each PriorityClass is sent with its own
+// credentials, see EnablePriorityClasses.
+type PriorityClass string
+
+type priorityClassKey struct{}
+
+// WithPriorityClass returns a copy of ctx that carries the given
+// PriorityClass. Requests made with the returned context by a client whose
+// config has been passed to EnablePriorityClasses are sent with the
+// credentials configured for the class.
+func WithPriorityClass(ctx context.Context, class PriorityClass) context.Context {
+	return context.WithValue(ctx, priorityClassKey{}, class)
+}
+
+// PriorityClassFromContext returns the PriorityClass carried by ctx, if any.
+func PriorityClassFromContext(ctx context.Context) (PriorityClass, bool) {
+	class, ok := ctx.Value(priorityClassKey{}).(PriorityClass)
+	return class, ok && class != ""
+}
+
+// EnablePriorityClasses wraps the transport of config so that requests made
+// with a context carrying one of the given classes are sent through a
+// transport built from the class's config instead. The class configs are
+// expected to authenticate as dedicated identities, e.g. ServiceAccounts,
+// that FlowSchemas map to the desired priority levels. Unlike impersonation,
+// this doesn't require any additional permissions, and the requests are
+// authorized as the class's identity.
+//
+// Only the transport related fields of the class configs, i.e. their
+// credentials and TLS settings, are used; requests keep the host and path of
+// config. Requests without a class, or with a class not in classes, are left
+// unchanged. It must be called before clients or caches are created from
+// config.
+func EnablePriorityClasses(config *rest.Config, classes map[PriorityClass]*rest.Config) error {
+	transports := make(map[PriorityClass]http.RoundTripper, len(classes))
+	for class, classConfig := range classes {
+		rt, err := rest.TransportFor(classConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create transport for priority class %q: %w", class, err)
+		}
+		transports[class] = rt
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &priorityClassRoundTripper{delegate: rt, classes: transports}
+	})
+	return nil
+}
+
+type priorityClassRoundTripper struct {
+	delegate http.RoundTripper
+	classes  map[PriorityClass]http.RoundTripper
+}
+
+func (rt *priorityClassRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	class, ok := PriorityClassFromContext(req.Context())
+	if !ok {
+		return rt.delegate.RoundTrip(req)
+	}
+	classRT, ok := rt.classes[class]
+	if !ok {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	// The credentials of config have already been set by the wrapping
+	// transports, drop them so that the class transport sets its own.
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	for key := range req.Header {
+		if strings.HasPrefix(key, "Impersonate-") {
+			req.Header.Del(key)
+		}
+	}
+	return classRT.RoundTrip(req)
+}
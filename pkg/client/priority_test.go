@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestEnablePriorityClasses(t *testing.T) {
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		headers = req.Header.Clone()
+	}))
+	defer srv.Close()
+
+	cfg := &rest.Config{
+		Host:        srv.URL,
+		BearerToken: "controller",
+		Impersonate: rest.ImpersonationConfig{UserName: "tenant"},
+	}
+	if err := client.EnablePriorityClasses(cfg, map[client.PriorityClass]*rest.Config{
+		"resync": {BearerToken: "resync"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	get := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	get(context.Background())
+	if auth := headers.Get("Authorization"); auth != "Bearer controller" {
+		t.Fatalf("expected the credentials of the config without a class, got %q", auth)
+	}
+	if user := headers.Get(authenticationv1.ImpersonateUserHeader); user != "tenant" {
+		t.Fatalf("expected the impersonation of the config without a class, got %q", user)
+	}
+
+	get(client.WithPriorityClass(context.Background(), "unknown"))
+	if auth := headers.Get("Authorization"); auth != "Bearer controller" {
+		t.Fatalf("expected the credentials of the config for an unknown class, got %q", auth)
+	}
+
+	get(client.WithPriorityClass(context.Background(), "resync"))
+	if auth := headers.Get("Authorization"); auth != "Bearer resync" {
+		t.Fatalf("expected the credentials of the class, got %q", auth)
+	}
+	if user := headers.Get(authenticationv1.ImpersonateUserHeader); user != "" {
+		t.Fatalf("expected no impersonation for a class, got %q", user)
+	}
+}
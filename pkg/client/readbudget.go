@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/internal/metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrReadBudgetExceeded is returned by a reader created with WithReadBudget
+// when a reconcile exceeds its budget of uncached reads and the budget is
+// enforced.
+var ErrReadBudgetExceeded = errors.New("uncached read budget exceeded")
+
+// ReadBudget configures the accounting performed by a reader returned from
+// [WithReadBudget].
+type ReadBudget struct {
+	// MaxReadsPerReconcile is the number of reads a single reconcile may make
+	// through the reader. Zero disables the budget; reads are still counted.
+	MaxReadsPerReconcile int
+
+	// Enforce makes reads beyond the budget fail with ErrReadBudgetExceeded.
+	// Otherwise, they are logged.
+	Enforce bool
+}
+
+type readScopeKey struct{}
+
+type readScope struct {
+	controller string
+	reads      atomic.Int64
+}
+
+// WithReadScope returns a copy of ctx in which reads made through readers
+// created with WithReadBudget are accounted to the given controller and
+// counted against the budget of a single reconcile. The controllers of a
+// manager call it before every reconcile if the APIReader or the Client of
+// the manager uses it, see UsesReadScope.
+func WithReadScope(ctx context.Context, controller string) context.Context {
+	return context.WithValue(ctx, readScopeKey{}, &readScope{controller: controller})
+}
+
+// WithReadBudget wraps a Reader that bypasses the cache, typically the
+// manager's APIReader, and counts the reads made through it per controller
+// in the controller_runtime_client_uncached_reads_total metric. This helps
+// finding reconcilers that accidentally bypass the cache and put load on the
+// API server.
+//
+// Reads made within a reconcile are counted against budget. Reads beyond it
+// are recorded in the controller_runtime_client_uncached_read_budget_exceeded_total
+// metric, and either logged or failed depending on budget.Enforce. Reads made
+// outside of a reconcile are counted but never limited.
+func WithReadBudget(r Reader, budget ReadBudget) Reader {
+	return &readerWithBudget{
+		reader: r,
+		budget: budget,
+	}
+}
+
+// readScopeUser is implemented by the readers and clients that use the
+// scope set with WithReadScope.
+type readScopeUser interface {
+	usesReadScope() bool
+}
+
+// UsesReadScope returns whether r uses the scope set with WithReadScope,
+// i.e. whether it was created with WithReadBudget, or with WithLabelPolicy
+// and a ControllerLabel.
+func UsesReadScope(r Reader) bool {
+	user, ok := r.(readScopeUser)
+	return ok && user.usesReadScope()
+}
+
+type readerWithBudget struct {
+	reader Reader
+	budget ReadBudget
+}
+
+func (r *readerWithBudget) usesReadScope() bool {
+	return true
+}
+
+func (r *readerWithBudget) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	if err := r.account(ctx, "get"); err != nil {
+		return err
+	}
+	return r.reader.Get(ctx, key, obj, opts...)
+}
+
+func (r *readerWithBudget) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	if err := r.account(ctx, "list"); err != nil {
+		return err
+	}
+	return r.reader.List(ctx, list, opts...)
+}
+
+func (r *readerWithBudget) account(ctx context.Context, verb string) error {
+	scope, ok := ctx.Value(readScopeKey{}).(*readScope)
+	if !ok {
+		metrics.UncachedReads.WithLabelValues("", verb).Inc()
+		return nil
+	}
+	metrics.UncachedReads.WithLabelValues(scope.controller, verb).Inc()
+
+	reads := scope.reads.Add(1)
+	if r.budget.MaxReadsPerReconcile <= 0 || reads <= int64(r.budget.MaxReadsPerReconcile) {
+		return nil
+	}
+	metrics.UncachedReadBudgetExceeded.WithLabelValues(scope.controller).Inc()
+	if r.budget.Enforce {
+		return fmt.Errorf("%w: %d reads made by controller %q, budget is %d", ErrReadBudgetExceeded, reads, scope.controller, r.budget.MaxReadsPerReconcile)
+	}
+	logf.FromContext(ctx).Info("Uncached read budget exceeded, consider reading from the cache",
+		"verb", verb, "reads", reads, "budget", r.budget.MaxReadsPerReconcile)
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithReadBudget(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	reader := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	t.Run("enforced budget", func(t *testing.T) {
+		r := client.WithReadBudget(reader, client.ReadBudget{MaxReadsPerReconcile: 2, Enforce: true})
+		ctx := client.WithReadScope(context.Background(), "test")
+
+		for i := 0; i < 2; i++ {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if err := r.List(ctx, &corev1.ConfigMapList{}); !errors.Is(err, client.ErrReadBudgetExceeded) {
+			t.Fatalf("expected budget exceeded error, got: %v", err)
+		}
+
+		ctx = client.WithReadScope(context.Background(), "test")
+		if err := r.List(ctx, &corev1.ConfigMapList{}); err != nil {
+			t.Fatalf("expected new reconcile to have a fresh budget, got: %v", err)
+		}
+	})
+
+	t.Run("unenforced budget", func(t *testing.T) {
+		r := client.WithReadBudget(reader, client.ReadBudget{MaxReadsPerReconcile: 1})
+		ctx := client.WithReadScope(context.Background(), "test")
+
+		for i := 0; i < 3; i++ {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+
+	t.Run("outside of a reconcile", func(t *testing.T) {
+		r := client.WithReadBudget(reader, client.ReadBudget{MaxReadsPerReconcile: 1, Enforce: true})
+
+		for i := 0; i < 3; i++ {
+			if err := r.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+}
+
+func TestUsesReadScope(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+
+	if client.UsesReadScope(c) {
+		t.Fatal("expected a plain client not to use the read scope")
+	}
+	if !client.UsesReadScope(client.WithReadBudget(c, client.ReadBudget{})) {
+		t.Fatal("expected a reader with a budget to use the read scope")
+	}
+	if client.UsesReadScope(client.WithLabelPolicy(c, client.LabelPolicy{ManagedBy: "my-operator"})) {
+		t.Fatal("expected a client with a label policy without ControllerLabel not to use the read scope")
+	}
+	if !client.UsesReadScope(client.WithLabelPolicy(c, client.LabelPolicy{ControllerLabel: "example.com/controller"})) {
+		t.Fatal("expected a client with a label policy with ControllerLabel to use the read scope")
+	}
+}
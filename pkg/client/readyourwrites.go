@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// cacheObservationInterval is the interval at which WaitForCacheObservation
+// reads from the cache.
+const cacheObservationInterval = 10 * time.Millisecond
+
+// WaitForCacheObservation blocks until cache, typically the cache of a
+// manager, returns obj at the resourceVersion it has after a write, or at a
+// later one, or until ctx is done. Caches are updated asynchronously from
+// the writes of a client, so a reconciler that creates an object and then
+// lists the objects from the cache, e.g. in its next reconcile, may not see
+// it and create it again. Waiting for the cache to observe the write after
+// it returns removes that race:
+//
+//	if err := c.Create(ctx, obj); err != nil {
+//		return err
+//	}
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	if err := client.WaitForCacheObservation(ctx, mgr.GetCache(), obj); err != nil {
+//		return err
+//	}
+//
+// ResourceVersions are compared as integers, as assigned by the
+// kube-apiserver; objects with other resourceVersions are only observed when
+// the cache returns the exact resourceVersion of obj.
+func WaitForCacheObservation(ctx context.Context, cache Reader, obj Object) error {
+	want := obj.GetResourceVersion()
+	if want == "" {
+		return errors.New("object has no resourceVersion to wait for")
+	}
+	key := ObjectKeyFromObject(obj)
+	observed := obj.DeepCopyObject().(Object)
+
+	var lastErr error
+	err := wait.PollUntilContextCancel(ctx, cacheObservationInterval, true, func(ctx context.Context) (bool, error) {
+		lastErr = cache.Get(ctx, key, observed)
+		switch {
+		case apierrors.IsNotFound(lastErr):
+			return false, nil
+		case lastErr != nil:
+			return false, lastErr
+		}
+		return resourceVersionAtLeast(observed.GetResourceVersion(), want), nil
+	})
+	if err != nil && ctx.Err() != nil {
+		if lastErr != nil {
+			return fmt.Errorf("cache did not observe resourceVersion %s of %s: %w: %w", want, key, ctx.Err(), lastErr)
+		}
+		return fmt.Errorf("cache did not observe resourceVersion %s of %s, last observed %q: %w", want, key, observed.GetResourceVersion(), ctx.Err())
+	}
+	return err
+}
+
+// resourceVersionAtLeast returns true if the resourceVersion observed is the
+// one wanted, or a later one.
+func resourceVersionAtLeast(observed, want string) bool {
+	if observed == want {
+		return true
+	}
+	observedInt, err := strconv.ParseUint(observed, 10, 64)
+	if err != nil {
+		return false
+	}
+	wantInt, err := strconv.ParseUint(want, 10, 64)
+	if err != nil {
+		return false
+	}
+	return observedInt >= wantInt
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWaitForCacheObservation(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", ResourceVersion: "10"}}
+
+	// The cache returns the pod only after a few reads, first at an older
+	// resourceVersion.
+	reads := 0
+	cache := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			reads++
+			switch reads {
+			case 1:
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, key.Name)
+			case 2:
+				obj.SetResourceVersion("9")
+			default:
+				obj.SetResourceVersion("11")
+			}
+			return nil
+		},
+	}).Build()
+
+	if err := client.WaitForCacheObservation(ctx, cache, pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reads != 3 {
+		t.Fatalf("expected the cache to be read until it returned a later resourceVersion, got %d reads", reads)
+	}
+	if pod.ResourceVersion != "10" {
+		t.Fatalf("expected the object not to be modified, got resourceVersion %q", pod.ResourceVersion)
+	}
+}
+
+func TestWaitForCacheObservationTimeout(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", ResourceVersion: "10"}}
+	cache := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			obj.SetResourceVersion("9")
+			return nil
+		},
+	}).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := client.WaitForCacheObservation(ctx, cache, pod)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cache did not observe resourceVersion 10 of default/pod") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWaitForCacheObservationErrors(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}}
+	if err := client.WaitForCacheObservation(ctx, fake.NewClientBuilder().Build(), pod); err == nil {
+		t.Fatal("expected an error for an object without a resourceVersion")
+	}
+
+	pod.ResourceVersion = "10"
+	failure := errors.New("cache failure")
+	cache := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return failure
+		},
+	}).Build()
+	if err := client.WaitForCacheObservation(ctx, cache, pod); !errors.Is(err, failure) {
+		t.Fatalf("expected the error of the cache, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/internal/metrics"
+)
+
+// RetryAfterOptions configures the retries performed by a client returned
+// from [WithRetryAfter].
+type RetryAfterOptions struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	// Defaults to 5.
+	MaxRetries int
+
+	// MaxDelay caps the delay waited before a single retry, regardless of
+	// the delay suggested by the API server. Defaults to 30 seconds.
+	MaxDelay time.Duration
+}
+
+// WithRetryAfter wraps a Client and transparently retries requests that
+// failed with an error suggesting a client delay, such as a 429 Too Many
+// Requests response carrying a Retry-After header. Before every retry, the
+// client waits for the suggested delay or until the context is done,
+// whichever happens first. The time spent waiting is recorded in the
+// controller_runtime_client_throttled_seconds_total metric. Creates and
+// DeleteAllOf calls are only retried on 429 Too Many Requests responses, as
+// they may have been applied despite other errors, e.g. server timeouts.
+//
+// The REST client used by the default Client already retries a few times
+// on its own; WithRetryAfter keeps retrying beyond that so reconcilers see
+// the error only once MaxRetries is exhausted or the context is done. In
+// that case, the last error returned by the API server is returned.
+func WithRetryAfter(c Client, options RetryAfterOptions) Client {
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = 5
+	}
+	if options.MaxDelay <= 0 {
+		options.MaxDelay = 30 * time.Second
+	}
+	return &clientWithRetryAfter{
+		Client:  c,
+		options: options,
+	}
+}
+
+type clientWithRetryAfter struct {
+	Client
+	options RetryAfterOptions
+}
+
+func (c *clientWithRetryAfter) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	return c.options.retry(ctx, "get", true, func() error {
+		return c.Client.Get(ctx, key, obj, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	return c.options.retry(ctx, "list", true, func() error {
+		return c.Client.List(ctx, list, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	return c.options.retry(ctx, "create", false, func() error {
+		return c.Client.Create(ctx, obj, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	return c.options.retry(ctx, "update", true, func() error {
+		return c.Client.Update(ctx, obj, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	return c.options.retry(ctx, "patch", true, func() error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	return c.options.retry(ctx, "delete", true, func() error {
+		return c.Client.Delete(ctx, obj, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	return c.options.retry(ctx, "deletecollection", false, func() error {
+		return c.Client.DeleteAllOf(ctx, obj, opts...)
+	})
+}
+
+func (c *clientWithRetryAfter) Status() SubResourceWriter {
+	return &subResourceClientWithRetryAfter{
+		writer:  c.Client.Status(),
+		options: c.options,
+	}
+}
+
+func (c *clientWithRetryAfter) SubResource(subResource string) SubResourceClient {
+	sc := c.Client.SubResource(subResource)
+	return &subResourceClientWithRetryAfter{
+		reader:  sc,
+		writer:  sc,
+		options: c.options,
+	}
+}
+
+type subResourceClientWithRetryAfter struct {
+	reader  SubResourceReader
+	writer  SubResourceWriter
+	options RetryAfterOptions
+}
+
+func (c *subResourceClientWithRetryAfter) Get(ctx context.Context, obj Object, subResource Object, opts ...SubResourceGetOption) error {
+	return c.options.retry(ctx, "get", true, func() error {
+		return c.reader.Get(ctx, obj, subResource, opts...)
+	})
+}
+
+func (c *subResourceClientWithRetryAfter) Create(ctx context.Context, obj Object, subResource Object, opts ...SubResourceCreateOption) error {
+	return c.options.retry(ctx, "create", false, func() error {
+		return c.writer.Create(ctx, obj, subResource, opts...)
+	})
+}
+
+func (c *subResourceClientWithRetryAfter) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	return c.options.retry(ctx, "update", true, func() error {
+		return c.writer.Update(ctx, obj, opts...)
+	})
+}
+
+func (c *subResourceClientWithRetryAfter) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	return c.options.retry(ctx, "patch", true, func() error {
+		return c.writer.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+// retry calls fn until it succeeds, fails with an error that doesn't
+// suggest a client delay, MaxRetries is exhausted or ctx is done.
+//
+// Errors suggesting a client delay include server timeouts, after which a
+// request may still have been applied. Requests that aren't idempotent,
+// i.e. creates, which may generate a name, and deletecollections, are thus
+// only retried if they were rejected with a 429 Too Many Requests.
+func (o RetryAfterOptions) retry(ctx context.Context, verb string, idempotent bool, fn func() error) error {
+	err := fn()
+	for attempt := 0; attempt < o.MaxRetries; attempt++ {
+		seconds, ok := apierrors.SuggestsClientDelay(err)
+		if !ok || (!idempotent && !apierrors.IsTooManyRequests(err)) {
+			return err
+		}
+		delay := time.Duration(seconds) * time.Second
+		if delay > o.MaxDelay {
+			delay = o.MaxDelay
+		}
+
+		start := time.Now()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			metrics.ThrottledSeconds.WithLabelValues(verb).Add(time.Since(start).Seconds())
+			return err
+		case <-timer.C:
+		}
+		metrics.ThrottledSeconds.WithLabelValues(verb).Add(time.Since(start).Seconds())
+
+		err = fn()
+	}
+	return err
+}
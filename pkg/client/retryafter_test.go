@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWithRetryAfter(t *testing.T) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+
+	throttled := 2
+	calls := 0
+	wrapped := fake.NewClientBuilder().WithObjects(cm).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			calls++
+			if calls <= throttled {
+				return apierrors.NewTooManyRequests("slow down", 1)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+
+	c := client.WithRetryAfter(wrapped, client.RetryAfterOptions{MaxRetries: 3, MaxDelay: time.Millisecond})
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := throttled + 1; calls != expected {
+		t.Fatalf("wrong number of calls: expected=%d; got=%d", expected, calls)
+	}
+
+	calls, throttled = 0, 10
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+	if !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected too many requests error, got: %v", err)
+	}
+	if expected := 4; calls != expected {
+		t.Fatalf("wrong number of calls: expected=%d; got=%d", expected, calls)
+	}
+
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c = client.WithRetryAfter(wrapped, client.RetryAfterOptions{})
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}); !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected too many requests error, got: %v", err)
+	}
+	if expected := 1; calls != expected {
+		t.Fatalf("wrong number of calls: expected=%d; got=%d", expected, calls)
+	}
+}
+
+func TestWithRetryAfterOnlyRetriesThrottledCreates(t *testing.T) {
+	var createErr error
+	calls := 0
+	wrapped := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			calls++
+			if calls == 1 {
+				return createErr
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	c := client.WithRetryAfter(wrapped, client.RetryAfterOptions{MaxDelay: time.Millisecond})
+
+	newConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{GenerateName: "cm-", Namespace: "default"}}
+	}
+
+	// A server timeout may have been applied, so retrying the create could
+	// create a second object.
+	createErr = apierrors.NewServerTimeout(corev1.Resource("configmaps"), "create", 1)
+	if err := c.Create(context.Background(), newConfigMap()); !apierrors.IsServerTimeout(err) {
+		t.Fatalf("expected server timeout error, got: %v", err)
+	}
+	if expected := 1; calls != expected {
+		t.Fatalf("wrong number of calls: expected=%d; got=%d", expected, calls)
+	}
+
+	calls = 0
+	createErr = apierrors.NewTooManyRequests("slow down", 1)
+	if err := c.Create(context.Background(), newConfigMap()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := 2; calls != expected {
+		t.Fatalf("wrong number of calls: expected=%d; got=%d", expected, calls)
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi3"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// SchemaValidation configures the validation performed by a client returned
+// from [WithSchemaValidation].
+type SchemaValidation struct {
+	// OpenAPI serves the OpenAPI v3 schemas of the API server, typically the
+	// OpenAPIV3 client of a discovery client. It is required.
+	OpenAPI openapi.Client
+
+	// RejectUnknownFields makes objects with fields that are not part of
+	// their schema fail validation. By default, unknown fields are pruned
+	// from the object before it is sent, as the API server would do.
+	RejectUnknownFields bool
+}
+
+// WithSchemaValidation wraps a Client and validates unstructured objects
+// against the OpenAPI schemas published by the API server before creating
+// or updating them. This gives fully dynamic controllers, which build their
+// objects as a GroupVersionKind and a map[string]interface{}, the early
+// error detection that typed objects get from the compiler:
+//
+//   - objects missing required fields, or with fields of the wrong type,
+//     fail with an Invalid error listing every problem, without a round
+//     trip to the API server,
+//   - fields that are not part of the schema are pruned, or rejected if
+//     RejectUnknownFields is set.
+//
+// Only Create and Update are validated, since patches carry partial
+// objects. Typed objects, and objects of kinds the API server publishes no
+// schema for, are passed through unchanged. Schemas are fetched once per
+// GroupVersion and cached for the lifetime of the client.
+func WithSchemaValidation(c Client, validation SchemaValidation) (Client, error) {
+	if validation.OpenAPI == nil {
+		return nil, errors.New("must provide an OpenAPI client")
+	}
+	return &clientWithSchemaValidation{
+		Client: c,
+		validator: &schemaValidator{
+			root:                openapi3.NewRoot(validation.OpenAPI),
+			rejectUnknownFields: validation.RejectUnknownFields,
+			specs:               map[schema.GroupVersion]*spec3.OpenAPI{},
+		},
+	}, nil
+}
+
+type clientWithSchemaValidation struct {
+	Client
+	validator *schemaValidator
+}
+
+func (c *clientWithSchemaValidation) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := c.validate(obj); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *clientWithSchemaValidation) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := c.validate(obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *clientWithSchemaValidation) validate(obj Object) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	return c.validator.validate(u)
+}
+
+// schemaValidator validates unstructured objects against OpenAPI v3 schemas.
+type schemaValidator struct {
+	root                openapi3.Root
+	rejectUnknownFields bool
+
+	mu    sync.Mutex
+	specs map[schema.GroupVersion]*spec3.OpenAPI
+}
+
+func (v *schemaValidator) validate(u *unstructured.Unstructured) error {
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return nil
+	}
+	spec, kindSchema, err := v.schemaFor(gvk)
+	if err != nil {
+		return err
+	}
+	if kindSchema == nil {
+		return nil
+	}
+
+	w := &schemaWalker{spec: spec, rejectUnknownFields: v.rejectUnknownFields}
+	w.walk(u.Object, kindSchema, nil)
+	if len(w.errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(gvk.GroupKind(), u.GetName(), w.errs)
+}
+
+// schemaFor returns the spec of the GroupVersion of gvk and the schema of
+// gvk in it, or a nil schema if the API server publishes none. Specs are
+// refetched when they don't hold gvk, which may be the kind of a CRD that
+// was created after they were fetched.
+func (v *schemaValidator) schemaFor(gvk schema.GroupVersionKind) (*spec3.OpenAPI, *spec.Schema, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	gv := gvk.GroupVersion()
+	if cached, ok := v.specs[gv]; ok {
+		if s := kindSchema(cached, gvk); s != nil {
+			return cached, s, nil
+		}
+	}
+	fetched, err := v.root.GVSpec(gv)
+	if err != nil {
+		var notFound *openapi3.GroupVersionNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get OpenAPI schema of %s: %w", gv, err)
+	}
+	v.specs[gv] = fetched
+	return fetched, kindSchema(fetched, gvk), nil
+}
+
+// kindSchema returns the schema of gvk in s, which is the one tagged with
+// the x-kubernetes-group-version-kind extension.
+func kindSchema(s *spec3.OpenAPI, gvk schema.GroupVersionKind) *spec.Schema {
+	if s.Components == nil {
+		return nil
+	}
+	for _, candidate := range s.Components.Schemas {
+		gvks, ok := candidate.Extensions["x-kubernetes-group-version-kind"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, entry := range gvks {
+			m, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+// schemaWalker walks an object alongside its schema, recording validation
+// errors and pruning unknown fields.
+type schemaWalker struct {
+	spec                *spec3.OpenAPI
+	rejectUnknownFields bool
+	errs                field.ErrorList
+}
+
+func (w *schemaWalker) walk(value interface{}, s *spec.Schema, path *field.Path) {
+	s = w.resolve(s)
+	if s == nil || value == nil {
+		return
+	}
+	for i := range s.AllOf {
+		w.walk(value, &s.AllOf[i], path)
+	}
+	if isIntOrString(s) {
+		switch value.(type) {
+		case string, int64, float64:
+		default:
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be an integer or a string"))
+		}
+		return
+	}
+
+	switch {
+	case s.Type.Contains("object") || len(s.Properties) > 0:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be an object"))
+			return
+		}
+		w.walkObject(obj, s, path)
+	case s.Type.Contains("array"):
+		items, ok := value.([]interface{})
+		if !ok {
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be an array"))
+			return
+		}
+		if s.Items != nil && s.Items.Schema != nil {
+			for i, item := range items {
+				w.walk(item, s.Items.Schema, path.Index(i))
+			}
+		}
+	case s.Type.Contains("string"):
+		if _, ok := value.(string); !ok {
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be a string"))
+		}
+	case s.Type.Contains("integer"):
+		switch n := value.(type) {
+		case int64, int32, int:
+		case float64:
+			if n != float64(int64(n)) {
+				w.errs = append(w.errs, field.TypeInvalid(path, value, "must be an integer"))
+			}
+		default:
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be an integer"))
+		}
+	case s.Type.Contains("number"):
+		switch value.(type) {
+		case int64, int32, int, float64:
+		default:
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be a number"))
+		}
+	case s.Type.Contains("boolean"):
+		if _, ok := value.(bool); !ok {
+			w.errs = append(w.errs, field.TypeInvalid(path, value, "must be a boolean"))
+		}
+	}
+}
+
+func (w *schemaWalker) walkObject(obj map[string]interface{}, s *spec.Schema, path *field.Path) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			w.errs = append(w.errs, field.Required(path.Child(name), ""))
+		}
+	}
+
+	preserveUnknownFields, _ := s.Extensions.GetBool("x-kubernetes-preserve-unknown-fields")
+	embeddedResource, _ := s.Extensions.GetBool("x-kubernetes-embedded-resource")
+	// Iterate in a stable order, so that errors are reported consistently.
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := s.Properties[name]; ok {
+			w.walk(obj[name], &property, path.Child(name))
+			continue
+		}
+		switch {
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+			w.walk(obj[name], s.AdditionalProperties.Schema, path.Key(name))
+		case s.AdditionalProperties != nil && s.AdditionalProperties.Allows:
+		case preserveUnknownFields:
+		case embeddedResource && (name == "apiVersion" || name == "kind" || name == "metadata"):
+		case len(s.Properties) == 0 && s.AdditionalProperties == nil && len(s.AllOf) > 0:
+			// The properties are declared by the schemas of AllOf.
+		case w.rejectUnknownFields:
+			w.errs = append(w.errs, field.NotSupported(path.Child(name), obj[name], propertyNames(s)))
+		default:
+			delete(obj, name)
+		}
+	}
+}
+
+// resolve follows the reference of s, if any, to the schema it points to
+// in the components of the spec.
+func (w *schemaWalker) resolve(s *spec.Schema) *spec.Schema {
+	for s != nil {
+		ref := s.Ref.String()
+		if ref == "" {
+			return s
+		}
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		if !ok || w.spec.Components == nil {
+			return nil
+		}
+		s = w.spec.Components.Schemas[name]
+	}
+	return nil
+}
+
+func isIntOrString(s *spec.Schema) bool {
+	intOrString, _ := s.Extensions.GetBool("x-kubernetes-int-or-string")
+	return intOrString || s.Format == "int-or-string"
+}
+
+func propertyNames(s *spec.Schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/openapi/openapitest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newDeployment(containers ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "app"}},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "app"}},
+				"spec":     map[string]interface{}{"containers": containers},
+			},
+		},
+	}}
+}
+
+func TestWithSchemaValidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes valid objects and prunes unknown fields", func(t *testing.T) {
+		c, err := client.WithSchemaValidation(fake.NewClientBuilder().Build(), client.SchemaValidation{
+			OpenAPI: openapitest.NewEmbeddedFileClient(),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		obj := newDeployment(map[string]interface{}{"name": "app", "image": "app:v1", "unknown": true})
+		if err := unstructured.SetNestedField(obj.Object, "value", "spec", "unknown"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.Create(ctx, obj); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "unknown"); found {
+			t.Errorf("expected spec.unknown to be pruned")
+		}
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		if _, found := containers[0].(map[string]interface{})["unknown"]; found {
+			t.Errorf("expected unknown container field to be pruned")
+		}
+		if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "selector", "matchLabels"); !found {
+			t.Errorf("expected map fields to be kept")
+		}
+	})
+
+	t.Run("rejects objects missing required fields or with invalid types", func(t *testing.T) {
+		c, err := client.WithSchemaValidation(fake.NewClientBuilder().Build(), client.SchemaValidation{
+			OpenAPI: openapitest.NewEmbeddedFileClient(),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		obj := newDeployment(map[string]interface{}{"image": "app:v1"})
+		if err := unstructured.SetNestedField(obj.Object, "one", "spec", "replicas"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = c.Create(ctx, obj)
+		if !apierrors.IsInvalid(err) {
+			t.Fatalf("expected an Invalid error, got %v", err)
+		}
+		for _, expected := range []string{"spec.replicas", "spec.template.spec.containers[0].name"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected error to mention %s, got %v", expected, err)
+			}
+		}
+	})
+
+	t.Run("rejects unknown fields if configured", func(t *testing.T) {
+		c, err := client.WithSchemaValidation(fake.NewClientBuilder().Build(), client.SchemaValidation{
+			OpenAPI:             openapitest.NewEmbeddedFileClient(),
+			RejectUnknownFields: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		obj := newDeployment(map[string]interface{}{"name": "app", "image": "app:v1"})
+		if err := unstructured.SetNestedField(obj.Object, "value", "spec", "unknown"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.Update(ctx, obj); !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "spec.unknown") {
+			t.Fatalf("expected an Invalid error for spec.unknown, got %v", err)
+		}
+	})
+
+	t.Run("passes objects without schema", func(t *testing.T) {
+		c, err := client.WithSchemaValidation(fake.NewClientBuilder().Build(), client.SchemaValidation{
+			OpenAPI: openapitest.NewEmbeddedFileClient(),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "widget", "namespace": "default"},
+			"spec":       map[string]interface{}{"anything": "goes"},
+		}}
+		if err := c.Create(ctx, obj); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/lru"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/internal/metrics"
+)
+
+// measuredObjects is how many object sizes are remembered by a size
+// guarded client or cache, so that the versions of objects that were
+// already measured aren't serialized again.
+const measuredObjects = 4096
+
+// SizeGuardrails configures the size checks performed by a client returned
+// from [WithSizeGuardrails], or by a cache configured with them.
+type SizeGuardrails struct {
+	// MaxObjectBytes is the serialized size in bytes above which an object
+	// read or written through the client is reported as oversized.
+	// Zero disables the per-object check.
+	MaxObjectBytes int
+
+	// MaxListBytes is the serialized size in bytes above which a list
+	// response is reported as exceeding its memory budget.
+	// Zero disables the per-list check.
+	MaxListBytes int
+
+	// Recorder, if set, is used to emit a Warning event on objects that
+	// exceed MaxObjectBytes, once per version of the object.
+	Recorder record.EventRecorder
+}
+
+// WithSizeGuardrails wraps a Client and reports objects and list responses
+// whose serialized size exceeds the configured limits. Objects are checked
+// after they are read or written, and the items of list responses one by
+// one. Violations are recorded in the
+// controller_runtime_client_oversized_objects_total and
+// controller_runtime_client_oversized_lists_total metrics and, if a
+// Recorder is configured, as Warning events on the offending object.
+//
+// The checks never fail a request; they are intended to help diagnose
+// memory pressure caused by unexpectedly large objects. Measuring an object
+// requires serializing it, so the sizes of the recently seen versions of
+// objects are remembered to avoid measuring them again.
+func WithSizeGuardrails(c Client, guardrails SizeGuardrails) Client {
+	return &clientWithSizeGuardrails{
+		Client:  c,
+		checker: newSizeChecker(guardrails, c.GroupVersionKindFor),
+	}
+}
+
+// SizeGuardrailsTransform returns a cache transform function that checks
+// the size of the objects committed to the cache, after applying the given
+// transform if it is not nil. Violations are reported as by
+// [WithSizeGuardrails], with the "cache" verb.
+func SizeGuardrailsTransform(guardrails SizeGuardrails, scheme *runtime.Scheme, transform func(any) (any, error)) func(any) (any, error) {
+	checker := newSizeChecker(guardrails, func(obj runtime.Object) (schema.GroupVersionKind, error) {
+		return apiutil.GVKForObject(obj, scheme)
+	})
+	return func(in any) (any, error) {
+		if transform != nil {
+			var err error
+			if in, err = transform(in); err != nil {
+				return nil, err
+			}
+		}
+		if obj, ok := in.(Object); ok {
+			checker.checkObject(obj, "cache")
+		}
+		return in, nil
+	}
+}
+
+type clientWithSizeGuardrails struct {
+	Client
+	checker *sizeChecker
+}
+
+func (c *clientWithSizeGuardrails) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	c.checker.checkObject(obj, "get")
+	return nil
+}
+
+func (c *clientWithSizeGuardrails) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	c.checker.checkList(list)
+	return nil
+}
+
+func (c *clientWithSizeGuardrails) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	c.checker.checkObject(obj, "create")
+	return err
+}
+
+func (c *clientWithSizeGuardrails) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	c.checker.checkObject(obj, "update")
+	return err
+}
+
+func (c *clientWithSizeGuardrails) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.checker.checkObject(obj, "patch")
+	return err
+}
+
+// sizeChecker checks the sizes of objects against SizeGuardrails.
+type sizeChecker struct {
+	guardrails SizeGuardrails
+	gvkFor     func(runtime.Object) (schema.GroupVersionKind, error)
+
+	// sizes holds the measuredSize of objects per UID.
+	sizes *lru.Cache
+	// mu serializes the measurements of objects, so that an oversized
+	// version is only reported once.
+	mu sync.Mutex
+}
+
+type measuredSize struct {
+	resourceVersion string
+	size            int
+}
+
+func newSizeChecker(guardrails SizeGuardrails, gvkFor func(runtime.Object) (schema.GroupVersionKind, error)) *sizeChecker {
+	return &sizeChecker{
+		guardrails: guardrails,
+		gvkFor:     gvkFor,
+		sizes:      lru.New(measuredObjects),
+	}
+}
+
+func (c *sizeChecker) checkObject(obj Object, verb string) {
+	if c.guardrails.MaxObjectBytes <= 0 {
+		return
+	}
+	size, measured := c.sizeOf(obj)
+	c.reportObject(obj, verb, size, measured)
+}
+
+func (c *sizeChecker) checkList(list ObjectList) {
+	if c.guardrails.MaxObjectBytes <= 0 && c.guardrails.MaxListBytes <= 0 {
+		return
+	}
+	total := 0
+	_ = meta.EachListItem(list, func(item runtime.Object) error {
+		obj, ok := item.(Object)
+		if !ok {
+			return nil
+		}
+		size, measured := c.sizeOf(obj)
+		if c.guardrails.MaxObjectBytes > 0 {
+			c.reportObject(obj, "list", size, measured)
+		}
+		total += size
+		return nil
+	})
+	if c.guardrails.MaxListBytes <= 0 || total <= c.guardrails.MaxListBytes {
+		return
+	}
+	gvk := c.kindFor(list)
+	metrics.OversizedLists.WithLabelValues(gvk.Group, strings.TrimSuffix(gvk.Kind, "List")).Inc()
+}
+
+func (c *sizeChecker) reportObject(obj Object, verb string, size int, measured bool) {
+	if size <= c.guardrails.MaxObjectBytes {
+		return
+	}
+	gvk := c.kindFor(obj)
+	metrics.OversizedObjects.WithLabelValues(gvk.Group, gvk.Kind, verb).Inc()
+	// Objects that weren't created can't be referenced by events.
+	if c.guardrails.Recorder != nil && measured && obj.GetUID() != "" {
+		c.guardrails.Recorder.Eventf(obj, corev1.EventTypeWarning, "ObjectSizeExceeded",
+			"Object is %d bytes, exceeding the configured limit of %d bytes", size, c.guardrails.MaxObjectBytes)
+	}
+}
+
+// sizeOf returns the size of obj once serialized to JSON, which is a
+// reasonable proxy for the size an object takes in the API server and,
+// roughly, in memory. It returns whether the object was measured, rather
+// than its size remembered from a previous measurement of its version.
+func (c *sizeChecker) sizeOf(obj Object) (int, bool) {
+	uid, resourceVersion := obj.GetUID(), obj.GetResourceVersion()
+	if uid == "" || resourceVersion == "" {
+		return serializedSize(obj), true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.sizes.Get(uid); ok && cached.(measuredSize).resourceVersion == resourceVersion {
+		return cached.(measuredSize).size, false
+	}
+	size := serializedSize(obj)
+	c.sizes.Add(uid, measuredSize{resourceVersion: resourceVersion, size: size})
+	return size, true
+}
+
+func (c *sizeChecker) kindFor(obj runtime.Object) schema.GroupVersionKind {
+	gvk, err := c.gvkFor(obj)
+	if err != nil {
+		return schema.GroupVersionKind{Kind: "unknown"}
+	}
+	return gvk
+}
+
+func serializedSize(obj any) int {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
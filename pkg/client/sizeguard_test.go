@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/internal/metrics"
+)
+
+func TestWithSizeGuardrails(t *testing.T) {
+	large := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "large", Namespace: "default", UID: "large-uid"},
+		Data:       map[string]string{"key": strings.Repeat("x", 1024)},
+	}
+	small := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "default", UID: "small-uid"},
+	}
+	uncreated := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "uncreated", Namespace: "default"},
+		Data:       map[string]string{"key": strings.Repeat("x", 1024)},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	c := client.WithSizeGuardrails(fake.NewClientBuilder().Build(), client.SizeGuardrails{
+		MaxObjectBytes: 512,
+		MaxListBytes:   1536,
+		Recorder:       recorder,
+	})
+	oversized := func(verb string) float64 {
+		return testutil.ToFloat64(metrics.OversizedObjects.WithLabelValues("", "ConfigMap", verb))
+	}
+
+	ctx := context.Background()
+	if err := c.Create(ctx, small); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected no events for small object, got %d", len(recorder.Events))
+	}
+
+	creates := oversized("create")
+	if err := c.Create(ctx, large); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Create(ctx, uncreated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := creates + 2; oversized("create") != expected {
+		t.Fatalf("wrong number of oversized creates: expected=%v; got=%v", expected, oversized("create"))
+	}
+
+	gets := oversized("get")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(large), &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := gets + 1; oversized("get") != expected {
+		t.Fatalf("wrong number of oversized gets: expected=%v; got=%v", expected, oversized("get"))
+	}
+	if expected := 1; len(recorder.Events) != expected {
+		t.Fatalf("expected a single event per version of the object, and none for objects without UID: expected=%d; got=%d", expected, len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "ObjectSizeExceeded") {
+		t.Fatalf("unexpected event: %q", event)
+	}
+
+	patches := oversized("patch")
+	patch := client.MergeFrom(large.DeepCopy())
+	large.Labels = map[string]string{"patched": "true"}
+	if err := c.Patch(ctx, large, patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := patches + 1; oversized("patch") != expected {
+		t.Fatalf("wrong number of oversized patches: expected=%v; got=%v", expected, oversized("patch"))
+	}
+	if expected := 1; len(recorder.Events) != expected {
+		t.Fatalf("expected an event for the patched version: expected=%d; got=%d", expected, len(recorder.Events))
+	}
+	<-recorder.Events
+
+	lists, listed := testutil.ToFloat64(metrics.OversizedLists.WithLabelValues("", "ConfigMap")), oversized("list")
+	if err := c.List(ctx, &corev1.ConfigMapList{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := listed + 2; oversized("list") != expected {
+		t.Fatalf("wrong number of oversized list items: expected=%v; got=%v", expected, oversized("list"))
+	}
+	if expected := lists + 1; testutil.ToFloat64(metrics.OversizedLists.WithLabelValues("", "ConfigMap")) != expected {
+		t.Fatalf("expected the list to exceed its budget")
+	}
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected no events for versions already reported, got %d", len(recorder.Events))
+	}
+}
+
+func TestSizeGuardrailsTransform(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	transform := client.SizeGuardrailsTransform(client.SizeGuardrails{
+		MaxObjectBytes: 512,
+		Recorder:       recorder,
+	}, scheme.Scheme, func(in any) (any, error) {
+		in.(*corev1.ConfigMap).Data = nil
+		return in, nil
+	})
+
+	cached := func(uid types.UID, data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: string(uid), Namespace: "default", UID: uid, ResourceVersion: "1"},
+			Data:       map[string]string{"key": data},
+			BinaryData: map[string][]byte{"key": []byte(data)},
+		}
+	}
+	before := testutil.ToFloat64(metrics.OversizedObjects.WithLabelValues("", "ConfigMap", "cache"))
+	for _, obj := range []*corev1.ConfigMap{cached("small", "x"), cached("large", strings.Repeat("x", 1024))} {
+		out, err := transform(obj)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.(*corev1.ConfigMap).Data != nil {
+			t.Fatalf("expected the wrapped transform to run")
+		}
+	}
+	if expected := before + 1; testutil.ToFloat64(metrics.OversizedObjects.WithLabelValues("", "ConfigMap", "cache")) != expected {
+		t.Fatalf("expected the large object to be reported after its transform ran")
+	}
+	if expected := 1; len(recorder.Events) != expected {
+		t.Fatalf("wrong number of events: expected=%d; got=%d", expected, len(recorder.Events))
+	}
+}
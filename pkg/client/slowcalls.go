@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SlowCallLogging configures the logging performed by a client returned
+// from [WithSlowCallLogging].
+type SlowCallLogging struct {
+	// Threshold is the duration above which a call is logged. Defaults to
+	// one second.
+	Threshold time.Duration
+
+	// IncludeStack adds the stack of the goroutine that made the call to
+	// the log entries, which points at the code of the reconcile
+	// responsible for it.
+	IncludeStack bool
+}
+
+// WithSlowCallLogging wraps a Client and logs a warning for every call that
+// takes longer than the threshold, to help identifying the calls
+// responsible for long reconciles. The entries are logged with the logger
+// of the context of the call, so that calls made within a reconcile are
+// logged with its controller, object and reconcile ID, and carry the verb,
+// GroupVersionKind, subresource and duration of the call.
+func WithSlowCallLogging(c Client, logging SlowCallLogging) Client {
+	if logging.Threshold <= 0 {
+		logging.Threshold = time.Second
+	}
+	return &clientWithSlowCallLogging{
+		Client:  c,
+		logging: logging,
+	}
+}
+
+type clientWithSlowCallLogging struct {
+	Client
+	logging SlowCallLogging
+}
+
+func (c *clientWithSlowCallLogging) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	defer c.observe(ctx, time.Now(), "get", obj, "")
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *clientWithSlowCallLogging) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	defer c.observe(ctx, time.Now(), "list", list, "")
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *clientWithSlowCallLogging) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	defer c.observe(ctx, time.Now(), "create", obj, "")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *clientWithSlowCallLogging) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	defer c.observe(ctx, time.Now(), "update", obj, "")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *clientWithSlowCallLogging) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	defer c.observe(ctx, time.Now(), "patch", obj, "")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *clientWithSlowCallLogging) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	defer c.observe(ctx, time.Now(), "delete", obj, "")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *clientWithSlowCallLogging) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	defer c.observe(ctx, time.Now(), "deletecollection", obj, "")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *clientWithSlowCallLogging) Status() SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *clientWithSlowCallLogging) SubResource(subResource string) SubResourceClient {
+	return &subResourceClientWithSlowCallLogging{
+		client:      c,
+		subResource: subResource,
+		wrapped:     c.Client.SubResource(subResource),
+	}
+}
+
+// observe logs the call if it took longer than the threshold.
+func (c *clientWithSlowCallLogging) observe(ctx context.Context, start time.Time, verb string, obj runtime.Object, subResource string) {
+	duration := time.Since(start)
+	if duration <= c.logging.Threshold {
+		return
+	}
+	keysAndValues := []interface{}{"verb", verb, "duration", duration, "threshold", c.logging.Threshold}
+	if gvk, err := c.Client.GroupVersionKindFor(obj); err == nil {
+		keysAndValues = append(keysAndValues, "groupVersionKind", gvk.String())
+	}
+	if subResource != "" {
+		keysAndValues = append(keysAndValues, "subResource", subResource)
+	}
+	if c.logging.IncludeStack {
+		keysAndValues = append(keysAndValues, "stack", string(debug.Stack()))
+	}
+	logf.FromContext(ctx).Info("Warning: slow client call", keysAndValues...)
+}
+
+type subResourceClientWithSlowCallLogging struct {
+	client      *clientWithSlowCallLogging
+	subResource string
+	wrapped     SubResourceClient
+}
+
+func (c *subResourceClientWithSlowCallLogging) Get(ctx context.Context, obj Object, subResource Object, opts ...SubResourceGetOption) error {
+	defer c.client.observe(ctx, time.Now(), "get", obj, c.subResource)
+	return c.wrapped.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *subResourceClientWithSlowCallLogging) Create(ctx context.Context, obj Object, subResource Object, opts ...SubResourceCreateOption) error {
+	defer c.client.observe(ctx, time.Now(), "create", obj, c.subResource)
+	return c.wrapped.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *subResourceClientWithSlowCallLogging) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	defer c.client.observe(ctx, time.Now(), "update", obj, c.subResource)
+	return c.wrapped.Update(ctx, obj, opts...)
+}
+
+func (c *subResourceClientWithSlowCallLogging) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	defer c.client.observe(ctx, time.Now(), "patch", obj, c.subResource)
+	return c.wrapped.Patch(ctx, obj, patch, opts...)
+}
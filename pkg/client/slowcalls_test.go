@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestWithSlowCallLogging(t *testing.T) {
+	var logs []string
+	ctx := logf.IntoContext(context.Background(), funcr.New(func(prefix, args string) {
+		logs = append(logs, args)
+	}, funcr.Options{}))
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}}
+	c := client.WithSlowCallLogging(fake.NewClientBuilder().WithObjects(pod).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			time.Sleep(20 * time.Millisecond)
+			return c.Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			time.Sleep(20 * time.Millisecond)
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		},
+	}).Build(), client.SlowCallLogging{Threshold: 10 * time.Millisecond, IncludeStack: true})
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 0 {
+		t.Fatalf("expected fast calls not to be logged, got %v", logs)
+	}
+
+	if err := c.Update(ctx, pod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("expected the slow call to be logged, got %v", logs)
+	}
+	for _, expected := range []string{`"verb"="update"`, `"groupVersionKind"="/v1, Kind=Pod"`, "TestWithSlowCallLogging"} {
+		if !strings.Contains(logs[0], expected) {
+			t.Errorf("expected log entry to contain %s, got %s", expected, logs[0])
+		}
+	}
+
+	if err := c.Status().Patch(ctx, pod, client.MergeFrom(pod.DeepCopy())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logs) != 2 || !strings.Contains(logs[1], `"subResource"="status"`) {
+		t.Fatalf("expected the slow subresource call to be logged, got %v", logs)
+	}
+}
@@ -28,6 +28,10 @@ var _ Writer = &typedClient{}
 type typedClient struct {
 	resources  *clientRestResources
 	paramCodec runtime.ParameterCodec
+
+	// listBuffers, if set, pools the buffers the responses of list requests
+	// are read into.
+	listBuffers *listBufferPool
 }
 
 // Create implements client.Client.
@@ -157,12 +161,14 @@ func (c *typedClient) List(ctx context.Context, obj ObjectList, opts ...ListOpti
 	listOpts := ListOptions{}
 	listOpts.ApplyOptions(opts)
 
-	return r.Get().
+	req := r.Get().
 		NamespaceIfScoped(listOpts.Namespace, r.isNamespaced()).
 		Resource(r.resource()).
-		VersionedParams(listOpts.AsListOptions(), c.paramCodec).
-		Do(ctx).
-		Into(obj)
+		VersionedParams(listOpts.AsListOptions(), c.paramCodec)
+	if c.listBuffers != nil {
+		return c.listBuffers.listInto(ctx, req, c.resources.codecs.UniversalDeserializer(), obj)
+	}
+	return req.Do(ctx).Into(obj)
 }
 
 func (c *typedClient) GetSubResource(ctx context.Context, obj, subResourceObj Object, subResource string, opts ...SubResourceGetOption) error {
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+)
+
+// Usage is the resource usage of the requests made with a context returned
+// by WithUsageRecorder.
+type Usage struct {
+	// Requests is the number of requests made to the API server by clients
+	// whose config has been passed to EnableUsageAccounting.
+	Requests int64
+
+	// ObjectsRead is the number of objects returned by the Get and List
+	// calls of clients wrapped with WithUsageAccounting, whether they were
+	// read from the API server or from a cache.
+	ObjectsRead int64
+
+	// BytesWritten is the number of bytes of the bodies of the requests
+	// made to the API server by clients whose config has been passed to
+	// EnableUsageAccounting.
+	BytesWritten int64
+}
+
+// UsageRecorder records the resource usage of the requests made with a
+// context returned by WithUsageRecorder. It is safe for concurrent use.
+type UsageRecorder struct {
+	requests     atomic.Int64
+	objectsRead  atomic.Int64
+	bytesWritten atomic.Int64
+}
+
+// Usage returns the usage recorded so far.
+func (r *UsageRecorder) Usage() Usage {
+	return Usage{
+		Requests:     r.requests.Load(),
+		ObjectsRead:  r.objectsRead.Load(),
+		BytesWritten: r.bytesWritten.Load(),
+	}
+}
+
+type usageRecorderKey struct{}
+
+// WithUsageRecorder returns a copy of ctx, and a UsageRecorder that records
+// the resource usage of the requests made with it. Controllers with the
+// AccountUsage option do it for every reconcile.
+func WithUsageRecorder(ctx context.Context) (context.Context, *UsageRecorder) {
+	recorder := &UsageRecorder{}
+	return context.WithValue(ctx, usageRecorderKey{}, recorder), recorder
+}
+
+func usageRecorderFrom(ctx context.Context) *UsageRecorder {
+	recorder, _ := ctx.Value(usageRecorderKey{}).(*UsageRecorder)
+	return recorder
+}
+
+// EnableUsageAccounting wraps the transport of config to record the
+// requests it makes, and the bytes they write, in the UsageRecorder of
+// their context, if any. It must be called before clients are created from
+// config.
+func EnableUsageAccounting(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &usageRoundTripper{delegate: rt}
+	})
+}
+
+type usageRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *usageRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := usageRecorderFrom(req.Context())
+	if recorder == nil {
+		return rt.delegate.RoundTrip(req)
+	}
+	recorder.requests.Add(1)
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+	case req.ContentLength > 0:
+		recorder.bytesWritten.Add(req.ContentLength)
+	default:
+		req = req.Clone(req.Context())
+		req.Body = &countingReadCloser{ReadCloser: req.Body, count: &recorder.bytesWritten}
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+// countingReadCloser counts the bytes read from a request body of unknown
+// length.
+type countingReadCloser struct {
+	io.ReadCloser
+	count *atomic.Int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.count.Add(int64(n))
+	return n, err
+}
+
+// WithUsageAccounting wraps a Client to record the objects read by its Get
+// and List calls in the UsageRecorder of their context, if any. Combined
+// with EnableUsageAccounting, it accounts for the resource usage of each
+// reconcile of a controller with the AccountUsage option.
+func WithUsageAccounting(c Client) Client {
+	return &usageAccountingClient{Client: c}
+}
+
+type usageAccountingClient struct {
+	Client
+}
+
+func (c *usageAccountingClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	if recorder := usageRecorderFrom(ctx); recorder != nil {
+		recorder.objectsRead.Add(1)
+	}
+	return nil
+}
+
+func (c *usageAccountingClient) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if recorder := usageRecorderFrom(ctx); recorder != nil {
+		recorder.objectsRead.Add(int64(meta.LenList(list)))
+	}
+	return nil
+}
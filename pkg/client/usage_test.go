@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnableUsageAccounting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(io.Discard, req.Body)
+	}))
+	defer srv.Close()
+
+	cfg := &rest.Config{Host: srv.URL}
+	client.EnableUsageAccounting(cfg)
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	do := func(ctx context.Context, body io.Reader) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// Requests made without a recorder are not accounted for.
+	do(context.Background(), strings.NewReader("ignored"))
+
+	ctx, recorder := client.WithUsageRecorder(context.Background())
+	do(ctx, nil)
+	do(ctx, strings.NewReader("12345"))
+	// The length of a body of unknown length is counted while it is sent.
+	do(ctx, io.MultiReader(strings.NewReader("123"), strings.NewReader("45678")))
+
+	usage := recorder.Usage()
+	if usage.Requests != 3 {
+		t.Errorf("expected 3 requests, got %d", usage.Requests)
+	}
+	if usage.BytesWritten != 13 {
+		t.Errorf("expected 13 bytes written, got %d", usage.BytesWritten)
+	}
+}
+
+func TestWithUsageAccounting(t *testing.T) {
+	c := client.WithUsageAccounting(fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}},
+	).Build())
+
+	ctx, recorder := client.WithUsageRecorder(context.Background())
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "a"}, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "missing"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error getting a missing object")
+	}
+	if err := c.List(ctx, &corev1.ConfigMapList{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.List(context.Background(), &corev1.ConfigMapList{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if read := recorder.Usage().ObjectsRead; read != 3 {
+		t.Errorf("expected 3 objects read, got %d", read)
+	}
+}
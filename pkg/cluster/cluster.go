@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 )
 
 // Cluster provides various methods to interact with a cluster.
@@ -103,6 +104,26 @@ type Options struct {
 	// to create the http client.
 	HTTPClient *http.Client
 
+	// Endpoints are the URLs of other API servers of the cluster, e.g.
+	// "https://10.0.0.2:6443", which are used when the host of the Config
+	// can't be reached. This provides client-side failover for clusters
+	// without a load balancer in front of their API servers. Endpoints must
+	// serve the API at the same path as the host of the Config, and present
+	// certificates valid for their address or for the ServerName of the
+	// Config.
+	//
+	// Requests are sent to the first reachable endpoint, the host of the
+	// Config being preferred. Reads are retried on the next endpoint whatever
+	// the error, writes only if the connection could not be established.
+	// Unreachable endpoints are probed every EndpointProbeInterval while the
+	// Cluster is running, and used again once their readyz endpoint
+	// succeeds. The Config and HTTP client of the Cluster fail over as well.
+	Endpoints []string
+
+	// EndpointProbeInterval is the interval at which the readiness of the
+	// Endpoints is probed. Defaults to 10 seconds.
+	EndpointProbeInterval time.Duration
+
 	// Cache is the cache.Options that will be used to create the default Cache.
 	// By default, the cache will watch and list requested objects in all namespaces.
 	Cache cache.Options
@@ -139,6 +160,36 @@ type Options struct {
 	// is shorter than the lifetime of your process.
 	EventBroadcaster record.EventBroadcaster
 
+	// EventsV1 makes the event recorders emit events through the
+	// events.k8s.io/v1 API rather than the core v1 one, if set. Repeated
+	// events are then deduplicated into event series, which are updated
+	// periodically rather than on every occurrence, and the events of each
+	// object are rate limited. This greatly reduces the load on the API
+	// server of controllers emitting many events. EventBroadcaster is not
+	// used when EventsV1 is set.
+	EventsV1 *EventsV1Options
+
+	// TenantClients, if set, makes the GetTenantClient method of the
+	// Cluster, see TenantClientProvider, create clients for tenant
+	// namespaces from the kubeconfig Secrets provided by the tenants in
+	// their namespace, so that multi-tenant operators act with the
+	// credentials of each tenant. Clients are cached, and recreated when the
+	// Secret changes.
+	TenantClients *TenantClientsOptions
+
+	// APIReaderBudget, if set, makes the APIReader of the cluster count the
+	// reads made through it per controller, and limit the reads made by a
+	// single reconcile, see client.WithReadBudget. This helps finding
+	// reconcilers that accidentally bypass the cache. Defaults to nil,
+	// which doesn't count the reads.
+	APIReaderBudget *client.ReadBudget
+
+	// EventSinks receive a copy of every event recorded by the event
+	// recorders of the cluster, in addition to its emission to the API
+	// server, e.g. to mirror events to structured logs with
+	// recorder.NewLogSink, or to an exporter.
+	EventSinks []recorder.Sink
+
 	// makeBroadcaster allows deferring the creation of the broadcaster to
 	// avoid leaking goroutines if we never call Start on this manager.  It also
 	// returns whether or not this is a "owned" broadcaster, and as such should be
@@ -149,6 +200,18 @@ type Options struct {
 	newRecorderProvider func(config *rest.Config, httpClient *http.Client, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster intrec.EventBroadcasterProducer) (*intrec.Provider, error)
 }
 
+// EventsV1Options configures the emission of events through the
+// events.k8s.io/v1 API.
+type EventsV1Options struct {
+	// QPS is the sustained rate of the events emitted per object. Defaults
+	// to one every five minutes.
+	QPS float32
+
+	// Burst is the number of events that can be emitted per object before
+	// QPS applies. Defaults to 25.
+	Burst int
+}
+
 // Option can be used to manipulate Options.
 type Option func(*Options)
 
@@ -175,6 +238,17 @@ func New(config *rest.Config, opts ...Option) (Cluster, error) {
 		return nil, err
 	}
 
+	var failover *endpointFailover
+	if len(options.Endpoints) > 0 {
+		failover, err = newEndpointFailover(config, options.Endpoints, options.EndpointProbeInterval, options.Logger)
+		if err != nil {
+			return nil, err
+		}
+		options.HTTPClient = failover.wrapHTTPClient(options.HTTPClient)
+		config = failover.wrapConfig(config)
+		originalConfig = failover.wrapConfig(originalConfig)
+	}
+
 	// Create the mapper provider
 	mapper, err := options.MapperProvider(config, options.HTTPClient)
 	if err != nil {
@@ -231,13 +305,18 @@ func New(config *rest.Config, opts ...Option) (Cluster, error) {
 
 	// Create the API Reader, a client with no cache.
 	clientReader, err := client.New(config, client.Options{
-		HTTPClient: options.HTTPClient,
-		Scheme:     options.Scheme,
-		Mapper:     mapper,
+		HTTPClient:       options.HTTPClient,
+		Scheme:           options.Scheme,
+		Mapper:           mapper,
+		ReuseListBuffers: options.Client.ReuseListBuffers,
 	})
 	if err != nil {
 		return nil, err
 	}
+	var apiReader client.Reader = clientReader
+	if options.APIReaderBudget != nil {
+		apiReader = client.WithReadBudget(clientReader, *options.APIReaderBudget)
+	}
 
 	// Create the recorder provider to inject event recorders for the components.
 	// TODO(directxman12): the log for the event provider should have a context (name, tags, etc) specific
@@ -246,19 +325,27 @@ func New(config *rest.Config, opts ...Option) (Cluster, error) {
 	if err != nil {
 		return nil, err
 	}
+	recorderProvider.SetSinks(options.EventSinks)
 
-	return &cluster{
+	c := &cluster{
 		config:           originalConfig,
 		httpClient:       options.HTTPClient,
 		scheme:           options.Scheme,
 		cache:            cache,
 		fieldIndexes:     cache,
 		client:           clientWriter,
-		apiReader:        clientReader,
+		apiReader:        apiReader,
 		recorderProvider: recorderProvider,
 		mapper:           mapper,
 		logger:           options.Logger,
-	}, nil
+		failover:         failover,
+	}
+	if options.TenantClients != nil {
+		if c.tenants, err = newTenantClients(c, *options.TenantClients); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
 // setOptionsDefaults set default values for Options fields.
@@ -292,7 +379,7 @@ func setOptionsDefaults(options Options, config *rest.Config) (Options, error) {
 
 	// Allow newRecorderProvider to be mocked
 	if options.newRecorderProvider == nil {
-		options.newRecorderProvider = intrec.NewProvider
+		options.newRecorderProvider = intrec.NewProviderFunc((*intrec.EventsV1Options)(options.EventsV1))
 	}
 
 	// This is duplicated with pkg/manager, we need it here to provide
@@ -20,7 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
@@ -109,6 +112,77 @@ var _ = Describe("cluster.Cluster", func() {
 		})
 	})
 
+	Describe("Endpoints", func() {
+		var primary, secondary *httptest.Server
+		var newCluster func(opts ...Option) (Cluster, error)
+
+		BeforeEach(func() {
+			primary = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("primary"))
+			}))
+			secondary = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("secondary"))
+			}))
+			DeferCleanup(secondary.Close)
+			newCluster = func(opts ...Option) (Cluster, error) {
+				return New(&rest.Config{Host: primary.URL}, append([]Option{func(o *Options) {
+					o.Endpoints = []string{secondary.URL}
+					o.MapperProvider = func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+						return meta.NewDefaultRESTMapper(nil), nil
+					}
+				}}, opts...)...)
+			}
+		})
+
+		get := func(c Cluster, method string) string {
+			req, err := http.NewRequest(method, c.GetConfig().Host+"/version", strings.NewReader("{}"))
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := c.GetHTTPClient().Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			return string(body)
+		}
+
+		It("should prefer the host of the Config", func() {
+			defer primary.Close()
+			c, err := newCluster()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get(c, http.MethodGet)).To(Equal("primary"))
+		})
+
+		It("should fail over to the next endpoint when the host can't be reached", func() {
+			primary.Close()
+			c, err := newCluster()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(get(c, http.MethodGet)).To(Equal("secondary"))
+			Expect(get(c, http.MethodPost)).To(Equal("secondary"))
+		})
+
+		It("should fail over the clients created from the Config", func() {
+			primary.Close()
+			c, err := newCluster()
+			Expect(err).NotTo(HaveOccurred())
+			httpClient, err := rest.HTTPClientFor(c.GetConfig())
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := httpClient.Get(c.GetConfig().Host + "/version")
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(Equal("secondary"))
+		})
+
+		It("should return an error if an endpoint is invalid", func() {
+			defer primary.Close()
+			_, err := newCluster(func(o *Options) {
+				o.Endpoints = []string{"10.0.0.2"}
+			})
+			Expect(err).To(MatchError(ContainSubstring("invalid endpoint")))
+		})
+	})
+
 	It("should not leak goroutines when stopped", func() {
 		currentGRs := goleak.IgnoreCurrent()
 
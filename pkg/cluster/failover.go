@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+)
+
+// defaultEndpointProbeInterval is the default interval at which the
+// endpoints of a cluster are probed.
+const defaultEndpointProbeInterval = 10 * time.Second
+
+// endpointFailover sends requests to the first healthy endpoint of a
+// cluster, in order of preference, and fails over to the next ones when an
+// endpoint can't be reached. Endpoints that failed are probed in the
+// background and used again once they are ready.
+type endpointFailover struct {
+	endpoints     []*url.URL
+	probeInterval time.Duration
+	logger        logr.Logger
+
+	// probe sends the readiness probes. It is set to the transport of the
+	// HTTP client of the cluster, which sends requests to the endpoints
+	// they are addressed to.
+	probe http.RoundTripper
+
+	mu      sync.RWMutex
+	healthy []bool
+}
+
+func newEndpointFailover(config *rest.Config, endpoints []string, probeInterval time.Duration, logger logr.Logger) (*endpointFailover, error) {
+	primary, _, err := rest.DefaultServerUrlFor(config)
+	if err != nil {
+		return nil, err
+	}
+	f := &endpointFailover{
+		endpoints:     []*url.URL{primary},
+		probeInterval: probeInterval,
+		logger:        logger.WithName("failover"),
+	}
+	if f.probeInterval <= 0 {
+		f.probeInterval = defaultEndpointProbeInterval
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: must be a URL with a scheme and a host", endpoint)
+		}
+		f.endpoints = append(f.endpoints, u)
+	}
+	f.healthy = make([]bool, len(f.endpoints))
+	for i := range f.healthy {
+		f.healthy[i] = true
+	}
+	return f, nil
+}
+
+// wrapHTTPClient returns a copy of c that fails over between the endpoints,
+// and uses the transport of c to probe them.
+func (f *endpointFailover) wrapHTTPClient(c *http.Client) *http.Client {
+	wrapped := *c
+	f.probe = c.Transport
+	if f.probe == nil {
+		f.probe = http.DefaultTransport
+	}
+	wrapped.Transport = f.wrap(f.probe)
+	return &wrapped
+}
+
+// wrapConfig returns a copy of config whose clients fail over between the
+// endpoints.
+func (f *endpointFailover) wrapConfig(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Wrap(f.wrap)
+	return config
+}
+
+func (f *endpointFailover) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &failoverRoundTripper{failover: f, delegate: rt}
+}
+
+// candidates returns the indexes of the endpoints to try, healthy ones
+// first, in order of preference.
+func (f *endpointFailover) candidates() []int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	healthy := make([]int, 0, len(f.endpoints))
+	var unhealthy []int
+	for i, ok := range f.healthy {
+		if ok {
+			healthy = append(healthy, i)
+		} else {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (f *endpointFailover) setHealthy(i int, healthy bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.healthy[i] == healthy {
+		return
+	}
+	f.healthy[i] = healthy
+	if healthy {
+		f.logger.Info("API server endpoint is ready", "endpoint", f.endpoints[i].Host)
+	} else {
+		f.logger.Info("API server endpoint is unreachable, failing over", "endpoint", f.endpoints[i].Host)
+	}
+}
+
+// Start probes the readiness of the endpoints until ctx is done.
+func (f *endpointFailover) Start(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for i := range f.endpoints {
+			f.setHealthy(i, f.ready(ctx, i))
+		}
+	}, f.probeInterval)
+}
+
+// ready reports whether the readyz endpoint of the i-th endpoint answers
+// successfully. Endpoints denying anonymous access to it are considered
+// ready, since they can be reached.
+func (f *endpointFailover) ready(ctx context.Context, i int) bool {
+	ctx, cancel := context.WithTimeout(ctx, f.probeInterval)
+	defer cancel()
+	u := *f.endpoints[i]
+	u.Path = "/readyz"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := f.probe.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	default:
+		return false
+	}
+}
+
+type failoverRoundTripper struct {
+	failover *endpointFailover
+	delegate http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. It sends req to the first
+// endpoint that can be reached, retrying requests on the next endpoint when
+// it is safe to do so: reads whatever the error, and writes only if the
+// connection could not be established.
+func (rt *failoverRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	for attempt, i := range rt.failover.candidates() {
+		endpointReq, err := rt.requestFor(req, i, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := rt.delegate.RoundTrip(endpointReq)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+		if req.Context().Err() != nil || !retriable(req, err) {
+			return nil, err
+		}
+		rt.failover.setHealthy(i, false)
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The body of the request was consumed and can't be replayed.
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// requestFor returns a copy of req addressed to the i-th endpoint.
+func (rt *failoverRoundTripper) requestFor(req *http.Request, i, attempt int) (*http.Request, error) {
+	endpoint := rt.failover.endpoints[i]
+	out := req.Clone(req.Context())
+	out.URL.Scheme = endpoint.Scheme
+	out.URL.Host = endpoint.Host
+	out.Host = ""
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		out.Body = body
+	}
+	return out, nil
+}
+
+// retriable reports whether req can be sent again after failing with err.
+func retriable(req *http.Request, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
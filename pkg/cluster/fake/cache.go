@@ -0,0 +1,305 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Cache is a cache.Cache backed by a fake client. Reads are served by the
+// client directly, so they always observe its latest writes. Informers list
+// and watch the client, so their event handlers are called when objects are
+// created, updated or deleted through it.
+//
+// Informers for metadata-only objects are not supported.
+type Cache struct {
+	client client.WithWatch
+
+	mu        sync.Mutex
+	ctx       context.Context
+	informers map[informerKey]*informerEntry
+	indexers  map[schema.GroupVersionKind]map[string]client.IndexerFunc
+}
+
+var _ cache.Cache = &Cache{}
+
+type informerKey struct {
+	gvk          schema.GroupVersionKind
+	unstructured bool
+}
+
+type informerEntry struct {
+	informer toolscache.SharedIndexInformer
+	cancel   context.CancelFunc
+}
+
+// NewCache returns a Cache backed by c, typically built with
+// fake.NewClientBuilder.
+func NewCache(c client.WithWatch) *Cache {
+	return &Cache{
+		client:    c,
+		informers: map[informerKey]*informerEntry{},
+		indexers:  map[schema.GroupVersionKind]map[string]client.IndexerFunc{},
+	}
+}
+
+// Get implements client.Reader.
+func (c *Cache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.client.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Reader. Field selectors on fields indexed with
+// IndexField are evaluated by the Cache, others by the client.
+func (c *Cache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.FieldSelector == nil || listOpts.FieldSelector.Empty() {
+		return c.client.List(ctx, list, opts...)
+	}
+	field, value, found := requiresExactMatch(listOpts.FieldSelector)
+	if !found {
+		return c.client.List(ctx, list, opts...)
+	}
+	gvk, err := apiutil.GVKForObject(list, c.client.Scheme())
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	c.mu.Lock()
+	extractValue, indexed := c.indexers[gvk][field]
+	c.mu.Unlock()
+	if !indexed {
+		return c.client.List(ctx, list, opts...)
+	}
+
+	listOpts.FieldSelector = nil
+	if err := c.client.List(ctx, list, &listOpts); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	var matching []runtime.Object
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		for _, v := range extractValue(obj) {
+			if v == value {
+				matching = append(matching, item)
+				break
+			}
+		}
+	}
+	return meta.SetList(list, matching)
+}
+
+// requiresExactMatch returns the field and value of a selector that
+// requires a single field to equal a value.
+func requiresExactMatch(sel fields.Selector) (field, value string, found bool) {
+	reqs := sel.Requirements()
+	if len(reqs) != 1 {
+		return "", "", false
+	}
+	req := reqs[0]
+	if req.Operator != "=" && req.Operator != "==" {
+		return "", "", false
+	}
+	return req.Field, req.Value, true
+}
+
+// GetInformer implements cache.Informers.
+func (c *Cache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.client.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	_, isUnstructured := obj.(runtime.Unstructured)
+	return c.informerFor(ctx, informerKey{gvk: gvk, unstructured: isUnstructured}, obj, opts...)
+}
+
+// GetInformerForKind implements cache.Informers.
+func (c *Cache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	obj, err := c.client.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	cObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a client.Object", gvk)
+	}
+	return c.informerFor(ctx, informerKey{gvk: gvk}, cObj, opts...)
+}
+
+func (c *Cache) informerFor(ctx context.Context, key informerKey, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	if _, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		return nil, errors.New("informers for metadata-only objects are not supported by the fake cache")
+	}
+	getOpts := cache.InformerGetOptions{}
+	for _, opt := range opts {
+		opt(&getOpts)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.informers[key]
+	if !ok {
+		entry = &informerEntry{informer: c.newInformer(key, obj)}
+		c.informers[key] = entry
+		if c.ctx != nil {
+			c.run(entry)
+		}
+	}
+	started := c.ctx != nil
+	c.mu.Unlock()
+
+	if started && (getOpts.BlockUntilSynced == nil || *getOpts.BlockUntilSynced) {
+		if !toolscache.WaitForCacheSync(ctx.Done(), entry.informer.HasSynced) {
+			return nil, fmt.Errorf("failed waiting for %s informer to sync: %w", key.gvk, ctx.Err())
+		}
+	}
+	return entry.informer, nil
+}
+
+func (c *Cache) newInformer(key informerKey, obj client.Object) toolscache.SharedIndexInformer {
+	newList := func() (client.ObjectList, error) {
+		listGVK := key.gvk.GroupVersion().WithKind(key.gvk.Kind + "List")
+		if key.unstructured {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(listGVK)
+			return list, nil
+		}
+		list, err := c.client.Scheme().New(listGVK)
+		if err != nil {
+			return nil, err
+		}
+		return list.(client.ObjectList), nil
+	}
+	lw := &toolscache.ListWatch{
+		ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+			list, err := newList()
+			if err != nil {
+				return nil, err
+			}
+			return list, c.client.List(context.Background(), list)
+		},
+		WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+			list, err := newList()
+			if err != nil {
+				return nil, err
+			}
+			return c.client.Watch(context.Background(), list)
+		},
+	}
+	return toolscache.NewSharedIndexInformer(lw, obj.DeepCopyObject(), 0, toolscache.Indexers{
+		toolscache.NamespaceIndex: toolscache.MetaNamespaceIndexFunc,
+	})
+}
+
+// run starts the informer of entry. It must be called with mu held, once
+// the cache was started.
+func (c *Cache) run(entry *informerEntry) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	entry.cancel = cancel
+	go entry.informer.Run(ctx.Done())
+}
+
+// RemoveInformer implements cache.Informers.
+func (c *Cache) RemoveInformer(_ context.Context, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.client.Scheme())
+	if err != nil {
+		return err
+	}
+	_, isUnstructured := obj.(runtime.Unstructured)
+	key := informerKey{gvk: gvk, unstructured: isUnstructured}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.informers[key]; ok {
+		if entry.cancel != nil {
+			entry.cancel()
+		}
+		delete(c.informers, key)
+	}
+	return nil
+}
+
+// Start implements cache.Informers. It runs the informers until ctx is
+// done.
+func (c *Cache) Start(ctx context.Context) error {
+	c.mu.Lock()
+	if c.ctx != nil {
+		c.mu.Unlock()
+		return errors.New("fake cache was started more than once")
+	}
+	c.ctx = ctx
+	for _, entry := range c.informers {
+		c.run(entry)
+	}
+	c.mu.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+// WaitForCacheSync implements cache.Informers.
+func (c *Cache) WaitForCacheSync(ctx context.Context) bool {
+	c.mu.Lock()
+	var synced []toolscache.InformerSynced
+	for _, entry := range c.informers {
+		synced = append(synced, entry.informer.HasSynced)
+	}
+	c.mu.Unlock()
+	return toolscache.WaitForCacheSync(ctx.Done(), synced...)
+}
+
+// IndexField implements client.FieldIndexer. Indexed fields can be used in
+// the field selectors of List.
+func (c *Cache) IndexField(_ context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	gvk, err := apiutil.GVKForObject(obj, c.client.Scheme())
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.indexers[gvk] == nil {
+		c.indexers[gvk] = map[string]client.IndexerFunc{}
+	}
+	if _, ok := c.indexers[gvk][field]; ok {
+		return fmt.Errorf("field %q of %s is already indexed", field, gvk)
+	}
+	c.indexers[gvk][field] = extractValue
+	return nil
+}
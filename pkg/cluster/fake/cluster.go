@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package fake provides a cluster.Cluster backed by a fake client, to unit
+test the wiring of controllers and managers without an API server.
+
+Objects written through the client of the Cluster are delivered as events to
+the informers of its cache, so that event handlers, predicates and
+reconcilers can be exercised together:
+
+	c := fake.NewCluster(clientfake.NewClientBuilder().WithObjects(objs...).Build())
+	mgr, err := manager.New(&rest.Config{}, c.ManagerOptions(manager.Options{}))
+	...
+	err = builder.ControllerManagedBy(mgr).For(&appsv1.Deployment{}).Complete(r)
+	...
+	go mgr.Start(ctx)
+	err = c.GetClient().Create(ctx, deployment) // triggers a reconcile
+*/
+package fake
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Cluster is a cluster.Cluster backed by a fake client. Its client and API
+// reader are the fake client, its cache a Cache backed by it, and its
+// RESTMapper the one of the fake client.
+type Cluster struct {
+	client client.WithWatch
+	cache  *Cache
+
+	// Recorder records the events of all the recorders returned by
+	// GetEventRecorderFor. It buffers 1024 events, after which recording
+	// blocks until they are read.
+	Recorder *record.FakeRecorder
+}
+
+var (
+	_ cluster.Cluster              = &Cluster{}
+	_ cluster.TenantClientProvider = &Cluster{}
+)
+
+// NewCluster returns a Cluster backed by c, typically built with
+// fake.NewClientBuilder. Set the RESTMapper of c if the objects it serves
+// are passed to APIs that check whether they are namespaced, such as owner
+// references.
+func NewCluster(c client.WithWatch) *Cluster {
+	return &Cluster{
+		client:   c,
+		cache:    NewCache(c),
+		Recorder: record.NewFakeRecorder(1024),
+	}
+}
+
+// GetHTTPClient implements cluster.Cluster. The client can't reach any API
+// server.
+func (c *Cluster) GetHTTPClient() *http.Client {
+	return &http.Client{}
+}
+
+// GetTenantClient implements cluster.TenantClientProvider. It returns the
+// client of the cluster for every namespace.
+func (c *Cluster) GetTenantClient(_ context.Context, _ string) (client.Client, error) {
+	return c.client, nil
+}
+
+// GetConfig implements cluster.Cluster. The config doesn't point to any
+// API server.
+func (c *Cluster) GetConfig() *rest.Config {
+	return &rest.Config{}
+}
+
+// GetCache implements cluster.Cluster.
+func (c *Cluster) GetCache() cache.Cache {
+	return c.cache
+}
+
+// GetScheme implements cluster.Cluster.
+func (c *Cluster) GetScheme() *runtime.Scheme {
+	return c.client.Scheme()
+}
+
+// GetClient implements cluster.Cluster.
+func (c *Cluster) GetClient() client.Client {
+	return c.client
+}
+
+// GetFieldIndexer implements cluster.Cluster.
+func (c *Cluster) GetFieldIndexer() client.FieldIndexer {
+	return c.cache
+}
+
+// GetEventRecorderFor implements cluster.Cluster.
+func (c *Cluster) GetEventRecorderFor(string) record.EventRecorder {
+	return c.Recorder
+}
+
+// GetRESTMapper implements cluster.Cluster.
+func (c *Cluster) GetRESTMapper() meta.RESTMapper {
+	return c.client.RESTMapper()
+}
+
+// GetAPIReader implements cluster.Cluster.
+func (c *Cluster) GetAPIReader() client.Reader {
+	return c.client
+}
+
+// Start implements cluster.Cluster. It runs the informers of the cache
+// until ctx is done.
+func (c *Cluster) Start(ctx context.Context) error {
+	return c.cache.Start(ctx)
+}
+
+// ManagerOptions returns opts set up for manager.New to use the scheme,
+// RESTMapper, cache and client of the Cluster, and not to serve metrics.
+// The API reader and the event recorders of the manager are not backed by
+// the fake client, and leader election must not be enabled.
+func (c *Cluster) ManagerOptions(opts manager.Options) manager.Options {
+	opts.Scheme = c.client.Scheme()
+	opts.MapperProvider = func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+		return c.client.RESTMapper(), nil
+	}
+	opts.NewCache = func(*rest.Config, cache.Options) (cache.Cache, error) {
+		return c.cache, nil
+	}
+	opts.NewClient = func(*rest.Config, client.Options) (client.Client, error) {
+		return c.client, nil
+	}
+	if opts.Metrics.BindAddress == "" {
+		opts.Metrics.BindAddress = "0"
+	}
+	return opts
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestFake(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fake Cluster Suite")
+}
+
+var _ = Describe("Cluster", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		c      *fake.Cluster
+		pod    *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(func() { cancel() })
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"},
+			Spec:       corev1.PodSpec{NodeName: "node-a"},
+		}
+		c = fake.NewCluster(clientfake.NewClientBuilder().WithObjects(pod).Build())
+	})
+
+	It("should deliver the writes of its client to the informers of its cache", func() {
+		informer, err := c.GetCache().GetInformer(ctx, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		events := make(chan string, 10)
+		_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj any) { events <- "add " + obj.(*corev1.Pod).Name },
+			UpdateFunc: func(_, obj any) { events <- "update " + obj.(*corev1.Pod).Name },
+			DeleteFunc: func(obj any) { events <- "delete " + obj.(*corev1.Pod).Name },
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Expect(c.GetCache().WaitForCacheSync(ctx)).To(BeTrue())
+		Eventually(events).Should(Receive(Equal("add existing")))
+
+		created := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "created"}}
+		Expect(c.GetClient().Create(ctx, created)).To(Succeed())
+		Eventually(events).Should(Receive(Equal("add created")))
+
+		created.Labels = map[string]string{"updated": "true"}
+		Expect(c.GetClient().Update(ctx, created)).To(Succeed())
+		Eventually(events).Should(Receive(Equal("update created")))
+
+		Expect(c.GetClient().Delete(ctx, created)).To(Succeed())
+		Eventually(events).Should(Receive(Equal("delete created")))
+	})
+
+	It("should list by the fields indexed through its field indexer", func() {
+		Expect(c.GetFieldIndexer().IndexField(ctx, &corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		})).To(Succeed())
+		Expect(c.GetClient().Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+			Spec:       corev1.PodSpec{NodeName: "node-b"},
+		})).To(Succeed())
+
+		pods := &corev1.PodList{}
+		Expect(c.GetCache().List(ctx, pods, client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector("spec.nodeName", "node-a"),
+		})).To(Succeed())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Name).To(Equal("existing"))
+	})
+
+	It("should run the controllers of a manager", func() {
+		mgr, err := manager.New(&rest.Config{}, c.ManagerOptions(manager.Options{}))
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciled := make(chan reconcile.Request, 10)
+		Expect(builder.ControllerManagedBy(mgr).
+			For(&corev1.Pod{}).
+			Complete(reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				reconciled <- req
+				return reconcile.Result{}, nil
+			}))).To(Succeed())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(ctx)).To(Succeed())
+		}()
+		Eventually(reconciled).Should(Receive(Equal(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)})))
+
+		created := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "created"}}
+		Expect(c.GetClient().Create(ctx, created)).To(Succeed())
+		Eventually(reconciled).Should(Receive(Equal(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(created)})))
+	})
+})
@@ -57,6 +57,12 @@ type cluster struct {
 	// Logger is the logger that should be used by this manager.
 	// If none is set, it defaults to log.Log global logger.
 	logger logr.Logger
+
+	// failover probes the endpoints of the cluster, if several are set.
+	failover *endpointFailover
+
+	// tenants creates the clients of tenant namespaces, if configured.
+	tenants *tenantClients
 }
 
 func (c *cluster) GetConfig() *rest.Config {
@@ -95,11 +101,21 @@ func (c *cluster) GetAPIReader() client.Reader {
 	return c.apiReader
 }
 
+func (c *cluster) GetTenantClient(ctx context.Context, namespace string) (client.Client, error) {
+	if c.tenants == nil {
+		return nil, errTenantClientsNotConfigured
+	}
+	return c.tenants.clientFor(ctx, namespace)
+}
+
 func (c *cluster) GetLogger() logr.Logger {
 	return c.logger
 }
 
 func (c *cluster) Start(ctx context.Context) error {
 	defer c.recorderProvider.Stop(ctx)
+	if c.failover != nil {
+		go c.failover.Start(ctx)
+	}
 	return c.cache.Start(ctx)
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ErrClusterNotFound is returned by Providers for clusters they don't know.
+var ErrClusterNotFound = errors.New("cluster not found")
+
+// Provider looks clusters up by name, e.g. to route the requests of a
+// controller reconciling the objects of several clusters.
+type Provider interface {
+	// GetCluster returns the cluster named name. It returns an error
+	// wrapping ErrClusterNotFound if there is no such cluster.
+	GetCluster(ctx context.Context, name string) (Cluster, error)
+}
+
+// Reconciler reconciles the objects of a cluster.
+type Reconciler interface {
+	Reconcile(ctx context.Context, cl Cluster, req reconcile.Request) (reconcile.Result, error)
+}
+
+// ReconcilerFunc is a function implementing Reconciler.
+type ReconcilerFunc func(ctx context.Context, cl Cluster, req reconcile.Request) (reconcile.Result, error)
+
+// Reconcile implements Reconciler.
+func (f ReconcilerFunc) Reconcile(ctx context.Context, cl Cluster, req reconcile.Request) (reconcile.Result, error) {
+	return f(ctx, cl, req)
+}
+
+// ForClusters returns a reconciler of ClusterRequests looking up the
+// cluster of each request in provider and passing it to r, so that r reads
+// and writes the objects of the right cluster. Requests of the clusters
+// provider doesn't know, e.g. because they were removed, are dropped.
+func ForClusters(provider Provider, r Reconciler) reconcile.TypedReconciler[reconcile.ClusterRequest] {
+	return reconcile.TypedFunc[reconcile.ClusterRequest](func(ctx context.Context, req reconcile.ClusterRequest) (reconcile.Result, error) {
+		cl, err := provider.GetCluster(ctx, req.ClusterName)
+		if err != nil {
+			if errors.Is(err, ErrClusterNotFound) {
+				logf.FromContext(ctx).V(1).Info("Dropping request of unknown cluster", "cluster", req.ClusterName)
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{}, fmt.Errorf("failed to get cluster %q: %w", req.ClusterName, err)
+		}
+		return r.Reconcile(ctx, cl, req.Request)
+	})
+}
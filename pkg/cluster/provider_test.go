@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type mapProvider map[string]Cluster
+
+func (p mapProvider) GetCluster(_ context.Context, name string) (Cluster, error) {
+	cl, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrClusterNotFound, name)
+	}
+	return cl, nil
+}
+
+type namedCluster struct {
+	Cluster
+	name string
+}
+
+var _ = Describe("ForClusters", func() {
+	var (
+		reconciled []string
+		r          reconcile.TypedReconciler[reconcile.ClusterRequest]
+	)
+
+	BeforeEach(func() {
+		reconciled = nil
+		provider := mapProvider{
+			"east": &namedCluster{name: "east"},
+			"west": &namedCluster{name: "west"},
+		}
+		r = ForClusters(provider, ReconcilerFunc(func(_ context.Context, cl Cluster, req reconcile.Request) (reconcile.Result, error) {
+			reconciled = append(reconciled, cl.(*namedCluster).name+"/"+req.Name)
+			if req.Name == "fail" {
+				return reconcile.Result{}, errors.New("boom")
+			}
+			return reconcile.Result{}, nil
+		}))
+	})
+
+	request := func(clusterName, name string) reconcile.ClusterRequest {
+		return reconcile.ClusterRequest{
+			ClusterName: clusterName,
+			Request:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}},
+		}
+	}
+
+	It("should route requests to their cluster", func() {
+		_, err := r.Reconcile(context.Background(), request("east", "foo"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = r.Reconcile(context.Background(), request("west", "fail"))
+		Expect(err).To(MatchError("boom"))
+		Expect(reconciled).To(Equal([]string{"east/foo", "west/fail"}))
+	})
+
+	It("should drop the requests of unknown clusters", func() {
+		_, err := r.Reconcile(context.Background(), request("north", "foo"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciled).To(BeEmpty())
+	})
+})
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceAccountClientsOptions are the options for creating ServiceAccountClients.
+type ServiceAccountClientsOptions struct {
+	// Audiences are the intended audiences of the tokens. Defaults to the
+	// audiences of the API server.
+	Audiences []string
+
+	// TokenExpiration is the requested lifetime of the tokens. The API
+	// server may issue tokens with a different lifetime, and doesn't issue
+	// tokens valid less than 10 minutes. Defaults to 1 hour.
+	TokenExpiration time.Duration
+
+	// RefreshBefore is how long before their expiration tokens are renewed.
+	// Defaults to a fifth of their lifetime.
+	RefreshBefore time.Duration
+
+	// TokenClient is used to request the tokens with the TokenRequest API.
+	// It requires the permission to create serviceaccounts/token. Defaults
+	// to the Cluster's client.
+	TokenClient client.SubResourceClientConstructor
+
+	// IdleTimeout is how long the client of a ServiceAccount is kept
+	// while it isn't used. Defaults to TokenExpiration.
+	IdleTimeout time.Duration
+
+	// NewClient is used to create the clients. It is passed an HTTP client
+	// in the options that authenticates the requests with the token of the
+	// ServiceAccount.
+	// Defaults to client.New with the Cluster's scheme and REST mapper.
+	NewClient client.NewClientFunc
+}
+
+// ServiceAccountClients creates clients acting as ServiceAccounts, for
+// reconciles that must run with the reduced permissions of a tenant rather
+// than those of the controller. Clients use short-lived tokens requested
+// with the TokenRequest API. There is a single client per ServiceAccount,
+// which renews its token shortly before it expires; concurrent renewals of
+// the token of a ServiceAccount share a single TokenRequest. Clients that
+// haven't been used for IdleTimeout are dropped.
+type ServiceAccountClients struct {
+	cluster Cluster
+	options ServiceAccountClientsOptions
+	now     func() time.Time
+
+	// group deduplicates the concurrent creations of the client, and
+	// renewals of the token, of a ServiceAccount.
+	group singleflight.Group
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]*serviceAccountClient
+}
+
+type serviceAccountClient struct {
+	client client.Client
+	token  *serviceAccountToken
+}
+
+// NewServiceAccountClients returns a ServiceAccountClients for the given Cluster.
+func NewServiceAccountClients(cluster Cluster, options ServiceAccountClientsOptions) (*ServiceAccountClients, error) {
+	if options.TokenExpiration == 0 {
+		options.TokenExpiration = time.Hour
+	}
+	if options.TokenExpiration < 10*time.Minute {
+		return nil, fmt.Errorf("TokenExpiration must be at least 10 minutes, got %s", options.TokenExpiration)
+	}
+	if options.RefreshBefore < 0 || options.RefreshBefore >= options.TokenExpiration {
+		return nil, fmt.Errorf("RefreshBefore must be shorter than TokenExpiration, got %s", options.RefreshBefore)
+	}
+	if options.IdleTimeout == 0 {
+		options.IdleTimeout = options.TokenExpiration
+	}
+	if options.TokenClient == nil {
+		options.TokenClient = cluster.GetClient()
+	}
+	if options.NewClient == nil {
+		options.NewClient = client.New
+	}
+
+	return &ServiceAccountClients{
+		cluster: cluster,
+		options: options,
+		now:     time.Now,
+		clients: map[types.NamespacedName]*serviceAccountClient{},
+	}, nil
+}
+
+// ClientFor returns a client acting as the given ServiceAccount. The token
+// of the ServiceAccount is requested, or renewed if needed, before it
+// returns, so that failing TokenRequests are reported here.
+func (s *ServiceAccountClients) ClientFor(ctx context.Context, serviceAccount types.NamespacedName) (client.Client, error) {
+	s.mu.Lock()
+	cached, ok := s.clients[serviceAccount]
+	s.mu.Unlock()
+	if !ok {
+		created, err, _ := s.group.Do("client/"+serviceAccount.String(), func() (interface{}, error) {
+			return s.newClient(serviceAccount)
+		})
+		if err != nil {
+			return nil, err
+		}
+		cached = created.(*serviceAccountClient)
+	}
+
+	if _, err := cached.token.get(ctx); err != nil {
+		return nil, err
+	}
+	return cached.client, nil
+}
+
+// newClient creates and caches the client of the given ServiceAccount, and
+// drops the idle ones.
+func (s *ServiceAccountClients) newClient(serviceAccount types.NamespacedName) (*serviceAccountClient, error) {
+	s.mu.Lock()
+	if cached, ok := s.clients[serviceAccount]; ok {
+		s.mu.Unlock()
+		return cached, nil
+	}
+	s.mu.Unlock()
+
+	token := &serviceAccountToken{clients: s, serviceAccount: serviceAccount, lastUsed: s.now()}
+	config := rest.AnonymousClientConfig(s.cluster.GetConfig())
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &serviceAccountRoundTripper{token: token, delegate: rt}
+	})
+	// The transports are cached by client-go for the TLS configuration,
+	// so the clients of all the ServiceAccounts share the same one.
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create HTTP client for service account %s: %w", serviceAccount, err)
+	}
+	c, err := s.options.NewClient(config, client.Options{HTTPClient: httpClient, Scheme: s.cluster.GetScheme(), Mapper: s.cluster.GetRESTMapper()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client for service account %s: %w", serviceAccount, err)
+	}
+	created := &serviceAccountClient{client: c, token: token}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for name, cached := range s.clients {
+		if cached.token.idleSince(now) > s.options.IdleTimeout {
+			delete(s.clients, name)
+		}
+	}
+	s.clients[serviceAccount] = created
+	return created, nil
+}
+
+// requestToken requests a token for the given ServiceAccount.
+func (s *ServiceAccountClients) requestToken(ctx context.Context, serviceAccount types.NamespacedName) (*authenticationv1.TokenRequestStatus, error) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: serviceAccount.Namespace, Name: serviceAccount.Name}}
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         s.options.Audiences,
+			ExpirationSeconds: ptr.To(int64(s.options.TokenExpiration / time.Second)),
+		},
+	}
+	if err := s.options.TokenClient.SubResource("token").Create(ctx, sa, request); err != nil {
+		return nil, fmt.Errorf("unable to request token for service account %s: %w", serviceAccount, err)
+	}
+	if request.Status.Token == "" {
+		return nil, fmt.Errorf("no token issued for service account %s", serviceAccount)
+	}
+	return &request.Status, nil
+}
+
+// Invalidate makes the client of the given ServiceAccount renew its token
+// before its next request, e.g. after its token was rejected.
+func (s *ServiceAccountClients) Invalidate(serviceAccount types.NamespacedName) {
+	s.mu.Lock()
+	cached, ok := s.clients[serviceAccount]
+	s.mu.Unlock()
+	if ok {
+		cached.token.invalidate()
+	}
+}
+
+// serviceAccountToken is the token of a ServiceAccount, renewed shortly
+// before it expires.
+type serviceAccountToken struct {
+	clients        *ServiceAccountClients
+	serviceAccount types.NamespacedName
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+	lastUsed  time.Time
+}
+
+// get returns the token, renewing it if needed.
+func (t *serviceAccountToken) get(ctx context.Context) (string, error) {
+	now := t.clients.now()
+	t.mu.Lock()
+	t.lastUsed = now
+	token, valid := t.token, t.token != "" && now.Before(t.refreshAt)
+	t.mu.Unlock()
+	if valid {
+		return token, nil
+	}
+
+	renewed, err, _ := t.clients.group.Do("token/"+t.serviceAccount.String(), func() (interface{}, error) {
+		status, err := t.clients.requestToken(ctx, t.serviceAccount)
+		if err != nil {
+			return nil, err
+		}
+		refreshBefore := t.clients.options.RefreshBefore
+		if refreshBefore == 0 {
+			refreshBefore = status.ExpirationTimestamp.Sub(t.clients.now()) / 5
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.token = status.Token
+		t.refreshAt = status.ExpirationTimestamp.Add(-refreshBefore)
+		return status.Token, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return renewed.(string), nil
+}
+
+func (t *serviceAccountToken) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = ""
+}
+
+func (t *serviceAccountToken) idleSince(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return now.Sub(t.lastUsed)
+}
+
+// serviceAccountRoundTripper authenticates requests with the current token
+// of a ServiceAccount.
+type serviceAccountRoundTripper struct {
+	token    *serviceAccountToken
+	delegate http.RoundTripper
+}
+
+func (rt *serviceAccountRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := rt.token.get(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return rt.delegate.RoundTrip(req)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("ServiceAccountClients", func() {
+	It("should create one client per service account that renews its token", func() {
+		ctx := context.Background()
+		c, err := New(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Now()
+		var mu sync.Mutex
+		var requests []*authenticationv1.TokenRequest
+		tokens := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(_ context.Context, _ client.Client, subResource string, obj client.Object, sub client.Object, _ ...client.SubResourceCreateOption) error {
+				Expect(subResource).To(Equal("token"))
+				mu.Lock()
+				defer mu.Unlock()
+				request := sub.(*authenticationv1.TokenRequest)
+				requests = append(requests, request)
+				request.Status.Token = fmt.Sprintf("%s-%d", obj.GetName(), len(requests))
+				request.Status.ExpirationTimestamp = metav1.NewTime(now.Add(time.Hour))
+				return nil
+			},
+		}).Build()
+
+		authorizations := make(chan string, 10)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations <- r.Header.Get("Authorization")
+		}))
+		defer server.Close()
+
+		var httpClients []*http.Client
+		clients, err := NewServiceAccountClients(c, ServiceAccountClientsOptions{
+			Audiences:   []string{"tenant"},
+			TokenClient: tokens,
+			NewClient: func(config *rest.Config, options client.Options) (client.Client, error) {
+				Expect(config.Username).To(BeEmpty())
+				Expect(config.CertData).To(BeEmpty())
+				Expect(config.BearerToken).To(BeEmpty())
+				httpClients = append(httpClients, options.HTTPClient)
+				return fake.NewClientBuilder().Build(), nil
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		clients.now = func() time.Time { return now }
+
+		sa := types.NamespacedName{Namespace: "tenant-a", Name: "reconciler"}
+		first, err := clients.ClientFor(ctx, sa)
+		Expect(err).NotTo(HaveOccurred())
+		again, err := clients.ClientFor(ctx, sa)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(first))
+		Expect(httpClients).To(HaveLen(1))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Spec.Audiences).To(Equal([]string{"tenant"}))
+		Expect(*requests[0].Spec.ExpirationSeconds).To(Equal(int64(3600)))
+
+		_, err = httpClients[0].Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-authorizations).To(Equal("Bearer reconciler-1"))
+
+		By("renewing the token shortly before it expires")
+		clients.now = func() time.Time { return now.Add(50 * time.Minute) }
+		_, err = httpClients[0].Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-authorizations).To(Equal("Bearer reconciler-2"))
+		renewed, err := clients.ClientFor(ctx, sa)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renewed).To(BeIdenticalTo(first))
+		Expect(requests).To(HaveLen(2))
+
+		By("renewing an invalidated token")
+		clients.Invalidate(sa)
+		_, err = httpClients[0].Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-authorizations).To(Equal("Bearer reconciler-3"))
+
+		By("dropping idle clients")
+		clients.now = func() time.Time { return now.Add(3 * time.Hour) }
+		_, err = clients.ClientFor(ctx, types.NamespacedName{Namespace: "tenant-b", Name: "reconciler"})
+		Expect(err).NotTo(HaveOccurred())
+		clients.mu.Lock()
+		Expect(clients.clients).To(HaveLen(1))
+		clients.mu.Unlock()
+	})
+
+	It("should share a single token request between concurrent callers", func() {
+		c, err := New(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		var requests atomic.Int32
+		release := make(chan struct{})
+		clients, err := NewServiceAccountClients(c, ServiceAccountClientsOptions{
+			TokenClient: fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(_ context.Context, _ client.Client, _ string, _ client.Object, sub client.Object, _ ...client.SubResourceCreateOption) error {
+					requests.Add(1)
+					<-release
+					request := sub.(*authenticationv1.TokenRequest)
+					request.Status.Token = "token"
+					request.Status.ExpirationTimestamp = metav1.NewTime(time.Now().Add(time.Hour))
+					return nil
+				},
+			}).Build(),
+			NewClient: func(*rest.Config, client.Options) (client.Client, error) {
+				return fake.NewClientBuilder().Build(), nil
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		sa := types.NamespacedName{Namespace: "tenant-a", Name: "reconciler"}
+		results := make(chan client.Client, 5)
+		for range 5 {
+			go func() {
+				defer GinkgoRecover()
+				c, err := clients.ClientFor(context.Background(), sa)
+				Expect(err).NotTo(HaveOccurred())
+				results <- c
+			}()
+		}
+		Eventually(requests.Load).Should(BeEquivalentTo(1))
+		Consistently(requests.Load, 100*time.Millisecond).Should(BeEquivalentTo(1))
+		close(release)
+
+		first := <-results
+		for range 4 {
+			Expect(<-results).To(BeIdenticalTo(first))
+		}
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should fail when no token is issued", func() {
+		c, err := New(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		clients, err := NewServiceAccountClients(c, ServiceAccountClientsOptions{
+			TokenClient: fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceCreate: func(context.Context, client.Client, string, client.Object, client.Object, ...client.SubResourceCreateOption) error {
+					return nil
+				},
+			}).Build(),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = clients.ClientFor(context.Background(), types.NamespacedName{Namespace: "tenant-a", Name: "reconciler"})
+		Expect(err).To(MatchError("no token issued for service account tenant-a/reconciler"))
+	})
+
+	It("should reject token expirations the API server doesn't issue", func() {
+		c, err := New(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = NewServiceAccountClients(c, ServiceAccountClientsOptions{TokenExpiration: time.Minute})
+		Expect(err).To(MatchError(ContainSubstring("TokenExpiration must be at least 10 minutes")))
+	})
+})
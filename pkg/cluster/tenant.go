@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errTenantClientsNotConfigured is returned by GetTenantClient when the
+// Cluster has no TenantClients options.
+var errTenantClientsNotConfigured = errors.New("tenant clients are not configured, set Options.TenantClients")
+
+// TenantClientProvider is implemented by the Clusters and Managers that
+// create clients with the credentials of tenants, like the ones created
+// by New with Options.TenantClients.
+type TenantClientProvider interface {
+	// GetTenantClient returns a client using the credentials provided by
+	// the tenant owning the given namespace, as configured by
+	// Options.TenantClients. It fails if TenantClients is not set.
+	GetTenantClient(ctx context.Context, namespace string) (client.Client, error)
+}
+
+var _ TenantClientProvider = &cluster{}
+
+// TenantClientsOptions configures the clients returned by
+// TenantClientProvider.GetTenantClient.
+//
+// The kubeconfig Secrets are controlled by the tenants, so they may only
+// carry inline credentials and may only point the clients at the API
+// server of the Cluster, or at AllowedServers: kubeconfigs using exec or
+// auth provider plugins, referring to local files such as token,
+// certificate or key files, using a proxy, skipping TLS verification or
+// pointing at another server are rejected, as they would run commands in
+// or read files from the process, or send its requests to a server chosen
+// by the tenant.
+type TenantClientsOptions struct {
+	// SecretName is the name of the Secret holding the kubeconfig of a
+	// tenant. It is looked up in the tenant's namespace. Required.
+	SecretName string
+
+	// SecretKey is the key of the kubeconfig in the Secret.
+	// Defaults to "kubeconfig".
+	SecretKey string
+
+	// SecretReader is used to read the Secrets, on every call of
+	// GetTenantClient to detect their changes. Defaults to the Cluster's
+	// API reader, so that tenant Secrets are neither cached nor require
+	// permissions to list and watch all Secrets. Pass the Cluster's client
+	// to read them from the cache instead.
+	SecretReader client.Reader
+
+	// AllowedServers are the API servers, as URLs, that the kubeconfigs of
+	// tenants may point at in addition to the host of the Cluster's config.
+	AllowedServers []string
+
+	// NewClient is used to create the tenant clients.
+	// Defaults to client.New with the Cluster's scheme.
+	NewClient client.NewClientFunc
+}
+
+// tenantClients creates clients for tenant namespaces using credentials
+// provided by the tenants in a kubeconfig Secret in their namespace. Clients
+// are cached and recreated when the Secret changes.
+type tenantClients struct {
+	cluster        Cluster
+	options        TenantClientsOptions
+	allowedServers sets.Set[string]
+
+	mu      sync.Mutex
+	clients map[string]tenantClient
+}
+
+type tenantClient struct {
+	client          client.Client
+	resourceVersion string
+}
+
+// newTenantClients returns the tenantClients of the given Cluster.
+func newTenantClients(cluster Cluster, options TenantClientsOptions) (*tenantClients, error) {
+	if options.SecretName == "" {
+		return nil, fmt.Errorf("must specify SecretName for TenantClients")
+	}
+	if options.SecretKey == "" {
+		options.SecretKey = "kubeconfig"
+	}
+	if options.SecretReader == nil {
+		options.SecretReader = cluster.GetAPIReader()
+	}
+	if options.NewClient == nil {
+		options.NewClient = client.New
+	}
+
+	allowedServers := sets.New[string]()
+	for _, server := range append([]string{cluster.GetConfig().Host}, options.AllowedServers...) {
+		normalized, err := normalizeServer(server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed server %q for TenantClients: %w", server, err)
+		}
+		allowedServers.Insert(normalized)
+	}
+
+	return &tenantClients{
+		cluster:        cluster,
+		options:        options,
+		allowedServers: allowedServers,
+		clients:        map[string]tenantClient{},
+	}, nil
+}
+
+// clientFor returns a client using the credentials of the tenant owning the
+// given namespace.
+func (t *tenantClients) clientFor(ctx context.Context, namespace string) (client.Client, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: t.options.SecretName}
+	if err := t.options.SecretReader.Get(ctx, key, secret); err != nil {
+		t.invalidate(namespace)
+		return nil, fmt.Errorf("unable to get tenant credentials %s: %w", key, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cached, ok := t.clients[namespace]; ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.client, nil
+	}
+	delete(t.clients, namespace)
+
+	kubeconfig, ok := secret.Data[t.options.SecretKey]
+	if !ok {
+		return nil, fmt.Errorf("tenant credentials %s have no key %q", key, t.options.SecretKey)
+	}
+	config, err := restConfigFromTenantKubeconfig(kubeconfig, t.allowedServers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubeconfig from tenant credentials %s: %w", key, err)
+	}
+	c, err := t.options.NewClient(config, client.Options{Scheme: t.cluster.GetScheme()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client from tenant credentials %s: %w", key, err)
+	}
+
+	t.clients[namespace] = tenantClient{client: c, resourceVersion: secret.ResourceVersion}
+	return c, nil
+}
+
+// invalidate drops the cached client of the tenant owning the given namespace.
+func (t *tenantClients) invalidate(namespace string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.clients, namespace)
+}
+
+// restConfigFromTenantKubeconfig returns the config of the current context
+// of a kubeconfig provided by a tenant, rejecting the kubeconfigs that don't
+// only carry inline data or point at a server not in allowedServers.
+func restConfigFromTenantKubeconfig(data []byte, allowedServers sets.Set[string]) (*rest.Config, error) {
+	kubeconfig, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTenantKubeconfig(kubeconfig, allowedServers); err != nil {
+		return nil, err
+	}
+	return clientcmd.NewNonInteractiveClientConfig(*kubeconfig, kubeconfig.CurrentContext, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+}
+
+// validateTenantKubeconfig rejects plugins, references to local files,
+// proxies, insecure TLS and servers not in allowedServers, in every cluster
+// and user of kubeconfig.
+func validateTenantKubeconfig(kubeconfig *clientcmdapi.Config, allowedServers sets.Set[string]) error {
+	for name, cluster := range kubeconfig.Clusters {
+		switch {
+		case cluster.CertificateAuthority != "":
+			return fmt.Errorf("cluster %q: certificate-authority files are not allowed, use certificate-authority-data", name)
+		case cluster.ProxyURL != "":
+			return fmt.Errorf("cluster %q: proxies are not allowed", name)
+		case cluster.InsecureSkipTLSVerify:
+			return fmt.Errorf("cluster %q: insecure-skip-tls-verify is not allowed", name)
+		}
+		server, err := normalizeServer(cluster.Server)
+		if err != nil {
+			return fmt.Errorf("cluster %q: invalid server: %w", name, err)
+		}
+		if !allowedServers.Has(server) {
+			return fmt.Errorf("cluster %q: server %q is not allowed", name, cluster.Server)
+		}
+	}
+	for name, user := range kubeconfig.AuthInfos {
+		switch {
+		case user.Exec != nil:
+			return fmt.Errorf("user %q: exec plugins are not allowed", name)
+		case user.AuthProvider != nil:
+			return fmt.Errorf("user %q: auth providers are not allowed", name)
+		case user.TokenFile != "":
+			return fmt.Errorf("user %q: token files are not allowed, use token", name)
+		case user.ClientCertificate != "":
+			return fmt.Errorf("user %q: client-certificate files are not allowed, use client-certificate-data", name)
+		case user.ClientKey != "":
+			return fmt.Errorf("user %q: client-key files are not allowed, use client-key-data", name)
+		}
+	}
+	return nil
+}
+
+// normalizeServer returns the scheme, host and port of the API server at
+// server, defaulting them as rest.Config does, so that equivalent URLs of
+// the same server compare equal.
+func normalizeServer(server string) (string, error) {
+	u, _, err := rest.DefaultServerURL(server, "", schema.GroupVersion{}, true)
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return u.Scheme + "://" + net.JoinHostPort(strings.ToLower(u.Hostname()), port), nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const tenantKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: tenant
+  cluster:
+    server: https://%s.example.com
+contexts:
+- name: tenant
+  context:
+    cluster: tenant
+    user: tenant
+current-context: tenant
+users:
+- name: tenant
+  user:
+    token: secret-token
+`
+
+var tenantServers = sets.New("https://tenant.example.com:443")
+
+var _ = Describe("TenantClients", func() {
+	It("should create and cache clients from tenant kubeconfig Secrets", func() {
+		ctx := context.Background()
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-credentials", Namespace: "tenant-a"},
+			Data:       map[string][]byte{"kubeconfig": []byte(fmt.Sprintf(tenantKubeconfig, "first"))},
+		}
+		secrets := fake.NewClientBuilder().WithObjects(secret).Build()
+
+		var hosts []string
+		c, err := New(cfg, func(o *Options) {
+			o.TenantClients = &TenantClientsOptions{
+				SecretName:     "tenant-credentials",
+				SecretReader:   secrets,
+				AllowedServers: []string{"https://first.example.com", "second.example.com:443"},
+				NewClient: func(config *rest.Config, options client.Options) (client.Client, error) {
+					hosts = append(hosts, config.Host)
+					return fake.NewClientBuilder().Build(), nil
+				},
+			}
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		provider := c.(TenantClientProvider)
+		first, err := provider.GetTenantClient(ctx, "tenant-a")
+		Expect(err).NotTo(HaveOccurred())
+		again, err := provider.GetTenantClient(ctx, "tenant-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(first))
+		Expect(hosts).To(Equal([]string{"https://first.example.com"}))
+
+		By("recreating the client when the Secret changes")
+		secret.Data["kubeconfig"] = []byte(fmt.Sprintf(tenantKubeconfig, "second"))
+		Expect(secrets.Update(ctx, secret)).To(Succeed())
+		updated, err := provider.GetTenantClient(ctx, "tenant-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).NotTo(BeIdenticalTo(first))
+		Expect(hosts).To(Equal([]string{"https://first.example.com", "https://second.example.com"}))
+
+		By("failing for tenants without credentials")
+		_, err = provider.GetTenantClient(ctx, "tenant-b")
+		Expect(err).To(MatchError(ContainSubstring("unable to get tenant credentials tenant-b/tenant-credentials")))
+
+		By("failing for tenants pointing at another server")
+		secret.Data["kubeconfig"] = []byte(fmt.Sprintf(tenantKubeconfig, "attacker"))
+		Expect(secrets.Update(ctx, secret)).To(Succeed())
+		_, err = provider.GetTenantClient(ctx, "tenant-a")
+		Expect(err).To(MatchError(ContainSubstring(`server "https://attacker.example.com" is not allowed`)))
+	})
+
+	It("should read the tenant Secrets with the API reader by default", func() {
+		c, err := New(cfg, func(o *Options) {
+			o.TenantClients = &TenantClientsOptions{SecretName: "tenant-credentials"}
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.(*cluster).tenants.options.SecretReader).To(BeIdenticalTo(c.GetAPIReader()))
+	})
+
+	It("should allow the server of the cluster", func() {
+		c, err := New(cfg, func(o *Options) {
+			o.TenantClients = &TenantClientsOptions{SecretName: "tenant-credentials"}
+		})
+		Expect(err).NotTo(HaveOccurred())
+		server, err := normalizeServer(cfg.Host)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.(*cluster).tenants.allowedServers.UnsortedList()).To(ConsistOf(server))
+	})
+
+	It("should fail if tenant clients are not configured", func() {
+		c, err := New(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.(TenantClientProvider).GetTenantClient(context.Background(), "tenant-a")
+		Expect(err).To(MatchError(errTenantClientsNotConfigured))
+	})
+
+	DescribeTable("should reject kubeconfigs not carrying only inline data",
+		func(user, message string) {
+			kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: tenant
+  cluster:
+    server: https://tenant.example.com
+contexts:
+- name: tenant
+  context:
+    cluster: tenant
+    user: tenant
+current-context: tenant
+users:
+- name: tenant
+  user:
+%s
+`, user)
+			_, err := restConfigFromTenantKubeconfig([]byte(kubeconfig), tenantServers)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("exec plugin", "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh", "exec plugins are not allowed"),
+		Entry("auth provider", "    auth-provider:\n      name: oidc", "auth providers are not allowed"),
+		Entry("token file", "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", "token files are not allowed"),
+		Entry("client certificate file", "    client-certificate: /etc/tls/tls.crt", "client-certificate files are not allowed"),
+		Entry("client key file", "    client-key: /etc/tls/tls.key", "client-key files are not allowed"),
+	)
+
+	DescribeTable("should reject kubeconfigs sending requests elsewhere than to the allowed servers",
+		func(cluster, message string) {
+			kubeconfig := strings.Replace(fmt.Sprintf(tenantKubeconfig, "tenant"), "    server: https://tenant.example.com\n", cluster, 1)
+			_, err := restConfigFromTenantKubeconfig([]byte(kubeconfig), tenantServers)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("other server", "    server: https://tenant.example.com.attacker.io\n", "is not allowed"),
+		Entry("other port", "    server: https://tenant.example.com:8443\n", "is not allowed"),
+		Entry("plain HTTP", "    server: http://tenant.example.com\n", "is not allowed"),
+		Entry("proxy", "    server: https://tenant.example.com\n    proxy-url: http://attacker.io\n", "proxies are not allowed"),
+		Entry("insecure TLS", "    server: https://tenant.example.com\n    insecure-skip-tls-verify: true\n", "insecure-skip-tls-verify is not allowed"),
+	)
+
+	It("should reject certificate authority files", func() {
+		kubeconfig := strings.Replace(fmt.Sprintf(tenantKubeconfig, "tenant"), "    server:", "    certificate-authority: /etc/ca.crt\n    server:", 1)
+		_, err := restConfigFromTenantKubeconfig([]byte(kubeconfig), tenantServers)
+		Expect(err).To(MatchError(ContainSubstring("certificate-authority files are not allowed")))
+	})
+
+	It("should accept kubeconfigs carrying only inline data", func() {
+		config, err := restConfigFromTenantKubeconfig([]byte(fmt.Sprintf(tenantKubeconfig, "tenant")), tenantServers)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Host).To(Equal("https://tenant.example.com"))
+		Expect(config.BearerToken).To(Equal("secret-token"))
+	})
+})
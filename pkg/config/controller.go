@@ -16,7 +16,11 @@ limitations under the License.
 
 package config
 
-import "time"
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 // Controller contains configuration options for a controller.
 type Controller struct {
@@ -26,6 +30,28 @@ type Controller struct {
 	// Defaults to false if SkipNameValidation setting on controller and Manager are unset.
 	SkipNameValidation *bool
 
+	// SuffixDuplicateNames makes controllers append a numeric suffix to their name,
+	// e.g. "pod_2", if the name is already used by another controller, instead of
+	// failing to be created.
+	// Can be overwritten for a controller via the SuffixDuplicateNames setting on the controller.
+	// Defaults to false if SuffixDuplicateNames setting on controller and Manager are unset.
+	SuffixDuplicateNames *bool
+
+	// UseNameAsFieldManager makes controllers default their FieldManager to
+	// their name, so that the managedFields of objects attribute the changes
+	// made within reconciliations to the controller that made them.
+	// Can be overwritten for a controller via the FieldManager setting on the controller.
+	// Defaults to false, which leaves the field manager of the requests
+	// unchanged, as changing the field manager of an existing controller
+	// changes the ownership of the fields it manages.
+	UseNameAsFieldManager *bool
+
+	// NameGenerator generates the name of controllers created by the builder
+	// that were not given a name via Named(). It is passed the GroupVersionKind
+	// of the object passed to For().
+	// Defaults to the lowercase version of the kind.
+	NameGenerator func(gvk schema.GroupVersionKind) string
+
 	// GroupKindConcurrency is a map from a Kind to the number of concurrent reconciliation
 	// allowed for that controller.
 	//
@@ -53,4 +79,22 @@ type Controller struct {
 	// NeedLeaderElection indicates whether the controller needs to use leader election.
 	// Defaults to true, which means the controller will use leader election.
 	NeedLeaderElection *bool
+
+	// PauseAnnotation, if set, makes the controllers built with the builder
+	// drop the events of the For objects that have this annotation, with any
+	// value but "false". See predicate.NotPaused. The builder.Pausable
+	// option of a controller takes precedence.
+	PauseAnnotation string
+
+	// FeatureGates sets the feature gates of the manager, e.g. from a
+	// configuration file. The gates must be declared in the FeatureGates of
+	// the manager options. Gates set explicitly, e.g. from the
+	// --feature-gates flag, take precedence.
+	FeatureGates map[string]bool
+
+	// Components enables or disables the components of the manager
+	// registered with a manager.Components, by name. Components enabled or
+	// disabled explicitly, e.g. from the --enable-components and
+	// --disable-components flags, take precedence.
+	Components map[string]bool
 }
@@ -19,14 +19,26 @@ package controller
 import (
 	"context"
 	"fmt"
+	runtimemetrics "runtime/metrics"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/internal/controller"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -42,6 +54,13 @@ type TypedOptions[request comparable] struct {
 	// Defaults to false if Controller.SkipNameValidation setting from the Manager is also unset.
 	SkipNameValidation *bool
 
+	// SuffixDuplicateNames makes the controller append a numeric suffix to its name,
+	// e.g. "pod_2", if the name is already used by another controller, instead of
+	// returning an error. It has no effect if SkipNameValidation is set.
+	// Defaults to the Controller.SuffixDuplicateNames setting from the Manager if unset.
+	// Defaults to false if Controller.SuffixDuplicateNames setting from the Manager is also unset.
+	SuffixDuplicateNames *bool
+
 	// MaxConcurrentReconciles is the maximum number of concurrent Reconciles which can be run. Defaults to 1.
 	MaxConcurrentReconciles int
 
@@ -66,6 +85,22 @@ type TypedOptions[request comparable] struct {
 	// The overall is a token bucket and the per-item is exponential.
 	RateLimiter workqueue.TypedRateLimiter[request]
 
+	// RequeueRateLimiter, if set, limits how frequently requests are
+	// requeued when the Reconciler asks for it with Result.Requeue or
+	// Result.RequeueAfter, independently of RateLimiter, which then only
+	// applies to the retries of failed reconciles. A request asking for a
+	// requeue is queued again after the delay RequeueRateLimiter returns for
+	// it, or after its RequeueAfter if longer. The backoff of
+	// RequeueRateLimiter grows with consecutive Result.Requeue, and is reset
+	// by Result.RequeueAfter, so that periodic requeues are not delayed
+	// further every time. The backoff of RateLimiter is only reset once the
+	// request is reconciled successfully without asking for a requeue, so
+	// requeues in between failed reconciles don't reset the backoff of their
+	// retries. Defaults to
+	// nil, which requeues with RateLimiter for Result.Requeue, and without
+	// limit for Result.RequeueAfter.
+	RequeueRateLimiter workqueue.TypedRateLimiter[request]
+
 	// NewQueue constructs the queue for this controller once the controller is ready to start.
 	// With NewQueue a custom queue implementation can be used, e.g. a priority queue to prioritize with which
 	// priority/order objects are reconciled (e.g. to reconcile objects with changes first).
@@ -81,6 +116,328 @@ type TypedOptions[request comparable] struct {
 	// LogConstructor is used to construct a logger used for this controller and passed
 	// to each reconciliation via the context field.
 	LogConstructor func(request *request) logr.Logger
+
+	// PriorityClass, if set, is passed to each reconciliation via the context field,
+	// so that requests made by clients with priority classes enabled are sent with
+	// the credentials of the class and classified accordingly by the kube-apiserver's
+	// API Priority and Fairness. See client.EnablePriorityClasses.
+	PriorityClass client.PriorityClass
+
+	// ResyncPriorityClass, if set, is used instead of PriorityClass for
+	// reconciliations that were only triggered by periodic resyncs of informers,
+	// so that background traffic can be routed to a lower priority level than
+	// reconciliations of actual changes. Setting it implies RecordTriggers.
+	ResyncPriorityClass client.PriorityClass
+
+	// FieldManager, if set, is the field manager that the write requests
+	// made within a reconciliation by clients created with client.New default
+	// to, see client.WithContextFieldManager. It attributes the changes made
+	// by the controller in the managedFields of objects to it.
+	// Defaults to the name of the controller if the
+	// Controller.UseNameAsFieldManager setting from the Manager is set.
+	// Defaults to unset otherwise, which leaves the field manager of the
+	// requests unchanged.
+	FieldManager string
+
+	// StartWhen, if set, delays processing of requests until it returns.
+	// Watches are started and their caches synced before StartWhen is called,
+	// so events are queued while the controller waits. If StartWhen returns
+	// an error, the controller fails to start.
+	// See WhenClosed and WhenTrue.
+	StartWhen StartCondition
+
+	// RecordTriggers makes the controller record the events that caused
+	// requests to be enqueued, so that reconcilers can retrieve them with
+	// reconcile.TriggersFromContext and optimize based on why they were
+	// called. Recording triggers retains the event objects until the
+	// request is processed. Defaults to false.
+	RecordTriggers bool
+
+	// AccountUsage makes the controller record the resource usage of each
+	// reconcile: the API requests it makes, the objects it reads and the
+	// bytes it writes. The usage is logged at V(5) and exported in the
+	// controller_runtime_reconcile_api_requests,
+	// controller_runtime_reconcile_objects_read and
+	// controller_runtime_reconcile_bytes_written metrics, to identify
+	// pathological reconciles. Only the requests of clients whose config
+	// has been passed to client.EnableUsageAccounting, and the reads of
+	// clients wrapped with client.WithUsageAccounting, are accounted for.
+	// Defaults to false.
+	AccountUsage bool
+
+	// MetricsLabel is the value of the controller label of the metrics of
+	// the controller, and the name of its workqueue in the workqueue
+	// metrics. Defaults to the name of the controller. Managers creating
+	// many controllers dynamically, e.g. one per kind of a set of CRDs, can
+	// share a label among similar controllers to aggregate their metrics and
+	// bound the number of series. See also DeleteMetrics.
+	MetricsLabel string
+
+	// ReconcileExemplars makes the controller attach exemplars to the
+	// observations of the controller_runtime_reconcile_time_seconds
+	// histogram, so that latency spikes can be linked to the traces of the
+	// corresponding reconciles. The exemplar of a reconcile is set by calling
+	// SetReconcileExemplar with its context, typically from the tracing
+	// instrumentation of the Reconciler:
+	//
+	//	span := trace.SpanFromContext(ctx)
+	//	controller.SetReconcileExemplar(ctx, prometheus.Labels{"trace_id": span.SpanContext().TraceID().String()})
+	//
+	// Exemplars are only exposed in the OpenMetrics format, see the
+	// EnableOpenMetrics option of the metrics server. Defaults to false.
+	ReconcileExemplars bool
+
+	// ObjectUID, if set, returns the UID of the object of a request, or an
+	// empty UID if it is unknown, e.g. because the object has been deleted.
+	// The UID is added as the "uid" value of the logger of the
+	// reconciliation, and is the default "object_uid" exemplar of its
+	// duration metric, so that an object can be tracked across delete and
+	// recreate cycles. Events already carry the UID of their object. See
+	// builder.CorrelateByUID.
+	ObjectUID func(ctx context.Context, req request) types.UID
+
+	// Debounce delays the reconciliation of requests triggered by events
+	// until the events of an object quiet down, collapsing bursts of events,
+	// e.g. during the rollout of a Deployment, into a single reconcile.
+	// Requeues requested by the Reconciler are not delayed.
+	Debounce *DebounceOptions
+
+	// SourceQuarantine quarantines the Kind sources of the controller whose
+	// informer keeps failing to list or watch, e.g. because the CRD of their
+	// kind has been deleted, so that a single broken watch neither floods
+	// the logs nor destabilizes the controller. The events of a quarantined
+	// source are dropped until its informer recovers, after which it relists
+	// and the dropped changes are delivered as updates. Quarantined sources
+	// are logged, reported in ControllerInfo.QuarantinedSources and counted
+	// in the controller_runtime_quarantined_sources metric. Defaults to nil,
+	// which never quarantines sources.
+	SourceQuarantine *SourceQuarantineOptions
+
+	// MemoryPressure holds back the requeues the Reconciler requests with
+	// RequeueAfter and the requests triggered by periodic resyncs of
+	// informers while the process is under memory pressure, so that an
+	// event storm doesn't get the process OOM killed. Each held request is
+	// kept once, and they are all queued once the pressure subsides.
+	// Requests triggered by changes and retries of failed reconciles are
+	// not held back. The number of held requests is
+	// exported in the controller_runtime_shed_requests metric. Defaults to
+	// nil, which never holds requests back.
+	MemoryPressure *MemoryPressureOptions
+
+	// RequeueTimerResolution enables reconcile.RequeueUnlessChanged, and is
+	// the resolution of the timers the Reconciler registers with it: timers
+	// fire up to one resolution late. Defaults to 0, which disables
+	// RequeueUnlessChanged and runs no timers.
+	RequeueTimerResolution time.Duration
+
+	// WarmUp configures a warm-up pass that reconciles all the objects of
+	// the controller once when it starts, separately from the requests
+	// triggered by changes. See TypedWarmUpOptions.
+	WarmUp *TypedWarmUpOptions[request]
+
+	// Shutdown configures how the controller waits for its in-flight
+	// reconciles when it stops, and reports the requests it did not
+	// complete, to help triage after a restart. Defaults to nil, which waits
+	// for in-flight reconciles until the GracefulShutdownTimeout of the
+	// manager elapses, without reporting.
+	Shutdown *TypedShutdownOptions[request]
+
+	// Permissions are the permissions the controller requires, e.g. to
+	// watch its sources and read and write the objects it manages. They are
+	// reported by the manager's GetControllers and checked when the manager
+	// starts if its PermissionCheck option is set. The builder fills them in
+	// from the watched objects.
+	Permissions []manager.Permission
+}
+
+// DebounceOptions configures the debouncing of the requests of a controller.
+type DebounceOptions struct {
+	// QuietPeriod is how long a request is held after the last event that
+	// triggered it. It is required.
+	QuietPeriod time.Duration
+
+	// MaxDelay bounds how long a request is held after the first event that
+	// triggered it, so that objects with a steady stream of events are still
+	// reconciled. Defaults to ten times QuietPeriod.
+	MaxDelay time.Duration
+}
+
+// SourceQuarantineOptions configures the quarantine of the sources of a
+// controller.
+type SourceQuarantineOptions struct {
+	// FailureThreshold is the number of consecutive list and watch failures
+	// of the informer of a source after which it is quarantined. Defaults
+	// to 5.
+	FailureThreshold int
+
+	// CheckInterval is the interval at which the health of the informers is
+	// checked. Defaults to 10 seconds.
+	CheckInterval time.Duration
+}
+
+// MemoryPressureOptions configures how a controller detects memory
+// pressure.
+type MemoryPressureOptions struct {
+	// UnderPressure reports whether the process is under memory pressure,
+	// e.g. based on the memory usage of its cgroup or a signal of the user.
+	// Defaults to reporting whether the heap in use exceeds HeapLimit.
+	UnderPressure func() bool
+
+	// HeapLimit is the size of the heap in use, in bytes, above which the
+	// process is under memory pressure. It is required if UnderPressure is
+	// not set, and ignored otherwise.
+	HeapLimit uint64
+
+	// CheckInterval is the interval at which the memory pressure is
+	// checked. Defaults to 5 seconds.
+	CheckInterval time.Duration
+}
+
+// heapAbove returns a function reporting whether the heap in use exceeds
+// limit bytes.
+func heapAbove(limit uint64) func() bool {
+	return func() bool {
+		sample := []runtimemetrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		runtimemetrics.Read(sample)
+		return sample[0].Value.Kind() == runtimemetrics.KindUint64 && sample[0].Value.Uint64() > limit
+	}
+}
+
+// ShutdownOptions configures the shutdown of a controller.
+type ShutdownOptions = TypedShutdownOptions[reconcile.Request]
+
+// TypedShutdownOptions configures the shutdown of a controller. When it
+// stops, the controller no longer reconciles the requests left in its
+// queue, waits for its in-flight reconciles for at most Timeout, and logs a
+// report of the requests it did not complete.
+type TypedShutdownOptions[request comparable] struct {
+	// Timeout bounds how long the controller waits for its in-flight
+	// reconciles. Defaults to 0, which waits until the
+	// GracefulShutdownTimeout of the manager elapses.
+	Timeout time.Duration
+
+	// OnReport, if set, is called with the report once the controller
+	// stopped.
+	OnReport func(report TypedShutdownReport[request])
+
+	// Recorder, if set, records a warning event for each request of the
+	// report on the object returned by EventObject, which is required for
+	// controllers not built with the builder. The builder defaults it to
+	// the For object of the request.
+	Recorder    record.EventRecorder
+	EventObject func(req request) runtime.Object
+}
+
+// ShutdownReport lists the requests a controller did not complete when it
+// stopped.
+type ShutdownReport = TypedShutdownReport[reconcile.Request]
+
+// TypedShutdownReport lists the requests a controller did not complete when
+// it stopped.
+type TypedShutdownReport[request comparable] struct {
+	// Controller is the name of the controller.
+	Controller string
+
+	// InFlight are the requests still being reconciled when the wait for
+	// them timed out, with the time their reconcile started.
+	InFlight map[request]time.Time
+
+	// Queued are the requests left in the queue, which were not reconciled.
+	Queued []request
+
+	// TimedOut is whether the wait for the in-flight reconciles timed out.
+	TimedOut bool
+}
+
+// reportShutdown returns a function logging, recording and passing the
+// reports of the controller named name to the callback of options.
+func reportShutdown[request comparable](name string, log func(*request) logr.Logger, options *TypedShutdownOptions[request]) func(controller.ShutdownReport[request]) {
+	return func(r controller.ShutdownReport[request]) {
+		report := TypedShutdownReport[request]{
+			Controller: name,
+			InFlight:   r.InFlight,
+			Queued:     r.Queued,
+			TimedOut:   r.TimedOut,
+		}
+		if len(report.InFlight) > 0 || len(report.Queued) > 0 {
+			inFlight := make([]string, 0, len(report.InFlight))
+			for req, started := range report.InFlight {
+				inFlight = append(inFlight, fmt.Sprintf("%v (started %s)", req, started.Format(time.RFC3339)))
+			}
+			queued := make([]string, 0, len(report.Queued))
+			for _, req := range report.Queued {
+				queued = append(queued, fmt.Sprint(req))
+			}
+			sort.Strings(inFlight)
+			log(nil).Info("Requests not completed at shutdown", "inFlight", inFlight, "queued", queued, "timedOut", report.TimedOut)
+		}
+
+		if options.Recorder != nil && options.EventObject != nil {
+			for req := range report.InFlight {
+				options.Recorder.Eventf(options.EventObject(req), corev1.EventTypeWarning, "ReconcileInterrupted",
+					"Controller %s stopped while reconciling the object", name)
+			}
+			for _, req := range report.Queued {
+				options.Recorder.Eventf(options.EventObject(req), corev1.EventTypeWarning, "ReconcileInterrupted",
+					"Controller %s stopped before reconciling the object", name)
+			}
+		}
+		if options.OnReport != nil {
+			options.OnReport(report)
+		}
+	}
+}
+
+// WarmUpOptions configures the warm-up pass of a controller.
+type WarmUpOptions = TypedWarmUpOptions[reconcile.Request]
+
+// TypedWarmUpOptions configures the warm-up pass of a controller. Once its
+// caches are synced, the controller reconciles the requests returned by
+// List at a lower pace than the requests triggered by changes, so that a
+// restart of a controller managing many objects doesn't starve them. The
+// number of requests of the pass not reconciled yet is exported in the
+// controller_runtime_warmup_pending_requests metric, and the duration of
+// the pass in the controller_runtime_warmup_duration_seconds metric once it
+// completed.
+type TypedWarmUpOptions[request comparable] struct {
+	// List returns the requests of the pass. It is required, except for
+	// controllers built with the builder, which list the objects of their
+	// For kind from the cache and skip the events of the initial list of
+	// those objects, since the pass reconciles them.
+	List func(ctx context.Context) ([]request, error)
+
+	// MaxConcurrentReconciles is the maximum number of requests of the pass
+	// queued or reconciled at the same time. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// QPS is the maximum rate at which requests of the pass are queued.
+	// Defaults to 10.
+	QPS float64
+}
+
+// StartCondition blocks until a precondition for a controller to start
+// processing requests is met, or ctx is done.
+type StartCondition func(ctx context.Context) error
+
+// WhenClosed returns a StartCondition that is met when ch is closed.
+func WhenClosed(ch <-chan struct{}) StartCondition {
+	return func(ctx context.Context) error {
+		select {
+		case <-ch:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WhenTrue returns a StartCondition that is met when condition returns true.
+// The condition is checked immediately and then every interval. If the
+// condition returns an error, the StartCondition fails.
+func WhenTrue(interval time.Duration, condition func(ctx context.Context) (bool, error)) StartCondition {
+	return func(ctx context.Context) error {
+		return wait.PollUntilContextCancel(ctx, interval, true, condition)
+	}
 }
 
 // Controller implements a Kubernetes API.  A Controller manages a work queue fed reconcile.Requests
@@ -146,12 +503,36 @@ func NewTypedUnmanaged[request comparable](name string, mgr manager.Manager, opt
 		return nil, fmt.Errorf("must specify Name for Controller")
 	}
 
+	if options.Debounce != nil && options.Debounce.QuietPeriod <= 0 {
+		return nil, fmt.Errorf("must specify a positive Debounce.QuietPeriod")
+	}
+
+	if options.MemoryPressure != nil && options.MemoryPressure.UnderPressure == nil && options.MemoryPressure.HeapLimit == 0 {
+		return nil, fmt.Errorf("must specify MemoryPressure.UnderPressure or MemoryPressure.HeapLimit")
+	}
+
+	if options.Shutdown != nil && options.Shutdown.Recorder != nil && options.Shutdown.EventObject == nil {
+		return nil, fmt.Errorf("must specify Shutdown.EventObject with Shutdown.Recorder")
+	}
+
+	if options.WarmUp != nil && options.WarmUp.List == nil {
+		return nil, fmt.Errorf("must specify WarmUp.List")
+	}
+
+	// The name is reserved once the options are validated, so that it isn't
+	// taken by a controller that failed to be created.
 	if options.SkipNameValidation == nil {
 		options.SkipNameValidation = mgr.GetControllerOptions().SkipNameValidation
 	}
 
+	if options.SuffixDuplicateNames == nil {
+		options.SuffixDuplicateNames = mgr.GetControllerOptions().SuffixDuplicateNames
+	}
+
 	if options.SkipNameValidation == nil || !*options.SkipNameValidation {
-		if err := checkName(name); err != nil {
+		suffix := options.SuffixDuplicateNames != nil && *options.SuffixDuplicateNames
+		var err error
+		if name, err = controller.ReserveName(name, suffix); err != nil {
 			return nil, err
 		}
 	}
@@ -192,10 +573,14 @@ func NewTypedUnmanaged[request comparable](name string, mgr manager.Manager, opt
 		options.RateLimiter = workqueue.DefaultTypedControllerRateLimiter[request]()
 	}
 
+	if options.MetricsLabel == "" {
+		options.MetricsLabel = name
+	}
+
 	if options.NewQueue == nil {
-		options.NewQueue = func(controllerName string, rateLimiter workqueue.TypedRateLimiter[request]) workqueue.TypedRateLimitingInterface[request] {
+		options.NewQueue = func(_ string, rateLimiter workqueue.TypedRateLimiter[request]) workqueue.TypedRateLimitingInterface[request] {
 			return workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[request]{
-				Name: controllerName,
+				Name: options.MetricsLabel,
 			})
 		}
 	}
@@ -208,10 +593,69 @@ func NewTypedUnmanaged[request comparable](name string, mgr manager.Manager, opt
 		options.NeedLeaderElection = mgr.GetControllerOptions().NeedLeaderElection
 	}
 
+	if options.FieldManager == "" && ptr.Deref(mgr.GetControllerOptions().UseNameAsFieldManager, false) {
+		options.FieldManager = name
+	}
+
+	var debounceQuietPeriod, debounceMaxDelay time.Duration
+	if options.Debounce != nil {
+		debounceQuietPeriod = options.Debounce.QuietPeriod
+		debounceMaxDelay = options.Debounce.MaxDelay
+		if debounceMaxDelay <= 0 {
+			debounceMaxDelay = 10 * debounceQuietPeriod
+		}
+	}
+
+	var quarantineThreshold int
+	var quarantineCheckInterval time.Duration
+	if options.SourceQuarantine != nil {
+		quarantineThreshold = options.SourceQuarantine.FailureThreshold
+		if quarantineThreshold <= 0 {
+			quarantineThreshold = 5
+		}
+		quarantineCheckInterval = options.SourceQuarantine.CheckInterval
+		if quarantineCheckInterval <= 0 {
+			quarantineCheckInterval = 10 * time.Second
+		}
+	}
+
+	var memoryPressure func() bool
+	var memoryPressureCheckInterval time.Duration
+	if options.MemoryPressure != nil {
+		memoryPressure = options.MemoryPressure.UnderPressure
+		if memoryPressure == nil {
+			memoryPressure = heapAbove(options.MemoryPressure.HeapLimit)
+		}
+		memoryPressureCheckInterval = options.MemoryPressure.CheckInterval
+		if memoryPressureCheckInterval <= 0 {
+			memoryPressureCheckInterval = 5 * time.Second
+		}
+	}
+
+	var shutdownTimeout time.Duration
+	var shutdownReporter func(controller.ShutdownReport[request])
+	if options.Shutdown != nil {
+		shutdownTimeout = options.Shutdown.Timeout
+		shutdownReporter = reportShutdown(name, options.LogConstructor, options.Shutdown)
+	}
+
+	var warmUp *controller.WarmUp[request]
+	if options.WarmUp != nil {
+		warmUp = &controller.WarmUp[request]{
+			List:                    options.WarmUp.List,
+			MaxConcurrentReconciles: max(options.WarmUp.MaxConcurrentReconciles, 1),
+			QPS:                     options.WarmUp.QPS,
+		}
+		if warmUp.QPS <= 0 {
+			warmUp.QPS = 10
+		}
+	}
+
 	// Create controller with dependencies set
 	return &controller.Controller[request]{
 		Do:                      options.Reconciler,
 		RateLimiter:             options.RateLimiter,
+		RequeueRateLimiter:      options.RequeueRateLimiter,
 		NewQueue:                options.NewQueue,
 		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
 		CacheSyncTimeout:        options.CacheSyncTimeout,
@@ -219,8 +663,63 @@ func NewTypedUnmanaged[request comparable](name string, mgr manager.Manager, opt
 		LogConstructor:          options.LogConstructor,
 		RecoverPanic:            options.RecoverPanic,
 		LeaderElected:           options.NeedLeaderElection,
+		PriorityClass:           options.PriorityClass,
+		ResyncPriorityClass:     options.ResyncPriorityClass,
+		FieldManager:            options.FieldManager,
+		ScopeReads:              client.UsesReadScope(mgr.GetAPIReader()) || client.UsesReadScope(mgr.GetClient()),
+		StartWhen:               options.StartWhen,
+		RecordTriggers:          options.RecordTriggers || options.ResyncPriorityClass != "",
+		AccountUsage:            options.AccountUsage,
+		ReconcileExemplars:      options.ReconcileExemplars,
+		ObjectUID:               options.ObjectUID,
+		MetricsLabel:            options.MetricsLabel,
+		Permissions:             options.Permissions,
+		RequeueTimerResolution:  options.RequeueTimerResolution,
+		WarmUp:                  warmUp,
+		DebounceQuietPeriod:     debounceQuietPeriod,
+		DebounceMaxDelay:        debounceMaxDelay,
+		SkipRequest:             requestInDeletedNamespace[request](mgr.GetCache()),
+
+		SourceQuarantineThreshold:     quarantineThreshold,
+		SourceQuarantineCheckInterval: quarantineCheckInterval,
+
+		MemoryPressure:              memoryPressure,
+		MemoryPressureCheckInterval: memoryPressureCheckInterval,
+
+		ShutdownTimeout: shutdownTimeout,
+		ReportShutdown:  shutdownReporter,
 	}, nil
 }
 
+// requestInDeletedNamespace returns a function reporting whether a request
+// is for an object of a namespace that was deleted, if the cache tracks
+// deleted namespaces. Such requests are dropped, since their objects are
+// gone.
+func requestInDeletedNamespace[request comparable](c cache.Cache) func(request) bool {
+	deleted, ok := c.(cache.DeletedNamespaces)
+	if !ok {
+		return nil
+	}
+	return func(req request) bool {
+		r, ok := any(req).(reconcile.Request)
+		return ok && r.Namespace != "" && deleted.IsNamespaceDeleted(r.Namespace)
+	}
+}
+
 // ReconcileIDFromContext gets the reconcileID from the current context.
 var ReconcileIDFromContext = controller.ReconcileIDFromContext
+
+// DeleteMetrics deletes the series of the controller and workqueue metrics
+// of the controller with the given name, or MetricsLabel. Managers that stop
+// controllers at runtime call it once a controller has stopped, so that the
+// series of deleted controllers don't accumulate. The series of running
+// controllers sharing the label are recreated as they are updated.
+func DeleteMetrics(name string) {
+	ctrlmetrics.DeleteController(name)
+	metrics.DeleteWorkqueueMetrics(name)
+}
+
+// SetReconcileExemplar sets the exemplar attached to the duration metric of
+// the current reconciliation, typically the trace ID of its span. It is a
+// no-op unless the controller has ReconcileExemplars enabled.
+var SetReconcileExemplar = controller.SetReconcileExemplar
@@ -22,21 +22,37 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/goleak"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	internalcontroller "sigs.k8s.io/controller-runtime/pkg/internal/controller"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+var _ = Describe("controller.DeleteMetrics", func() {
+	It("should delete the series of the controller", func() {
+		ctrlmetrics.ReconcileTotal.WithLabelValues("deleted", "success").Inc()
+		ctrlmetrics.ReconcileTotal.WithLabelValues("kept", "success").Inc()
+		before := testutil.CollectAndCount(ctrlmetrics.ReconcileTotal)
+
+		controller.DeleteMetrics("deleted")
+		Expect(testutil.CollectAndCount(ctrlmetrics.ReconcileTotal)).To(Equal(before - 1))
+		Expect(testutil.ToFloat64(ctrlmetrics.ReconcileTotal.WithLabelValues("kept", "success"))).To(BeNumerically(">=", 1))
+	})
+})
+
 var _ = Describe("controller.Controller", func() {
 	rec := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
 		return reconcile.Result{}, nil
@@ -74,6 +90,22 @@ var _ = Describe("controller.Controller", func() {
 			Expect(c2).To(BeNil())
 		})
 
+		It("should not reserve the name of a controller with invalid options", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("invalid-options", m, controller.Options{
+				Reconciler: rec,
+				WarmUp:     &controller.WarmUpOptions{},
+			})
+			Expect(err).To(MatchError(ContainSubstring("must specify WarmUp.List")))
+			Expect(c).To(BeNil())
+
+			c, err = controller.New("invalid-options", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+		})
+
 		It("should return an error if two controllers are registered with the same name and SkipNameValidation is set to false on the manager", func() {
 			m, err := manager.New(cfg, manager.Options{
 				Controller: config.Controller{
@@ -212,6 +244,82 @@ var _ = Describe("controller.Controller", func() {
 			Expect(ctrl.NewQueue).NotTo(BeNil())
 		})
 
+		It("should default MetricsLabel to the name of the controller", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("metrics-label-default", m, controller.Options{
+				Reconciler: reconcile.Func(nil),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).MetricsLabel).To(Equal("metrics-label-default"))
+
+			c, err = controller.New("metrics-label-shared", m, controller.Options{
+				Reconciler:   reconcile.Func(nil),
+				MetricsLabel: "shared",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).MetricsLabel).To(Equal("shared"))
+		})
+
+		It("should only drop requests of deleted namespaces if the cache evicts them", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("skip-request-default", m, controller.Options{
+				Reconciler: reconcile.Func(nil),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).SkipRequest).To(BeNil())
+
+			m, err = manager.New(cfg, manager.Options{Cache: cache.Options{EvictDeletedNamespaces: true}})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err = controller.New("skip-request-evicting", m, controller.Options{
+				Reconciler: reconcile.Func(nil),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).SkipRequest).NotTo(BeNil())
+		})
+
+		It("should only scope reads if the manager has an APIReaderBudget", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("scope-reads-default", m, controller.Options{
+				Reconciler: reconcile.Func(nil),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).ScopeReads).To(BeFalse())
+
+			m, err = manager.New(cfg, manager.Options{APIReaderBudget: &client.ReadBudget{MaxReadsPerReconcile: 10}})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err = controller.New("scope-reads-budget", m, controller.Options{
+				Reconciler: reconcile.Func(nil),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).ScopeReads).To(BeTrue())
+		})
+
+		It("should not enable requeue timers unless RequeueTimerResolution is set", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("requeue-timers-default", m, controller.Options{
+				Reconciler: reconcile.Func(nil),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).RequeueTimerResolution).To(BeZero())
+
+			c, err = controller.New("requeue-timers-enabled", m, controller.Options{
+				Reconciler:             reconcile.Func(nil),
+				RequeueTimerResolution: time.Minute,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).RequeueTimerResolution).To(Equal(time.Minute))
+		})
+
 		It("should not override RateLimiter and NewQueue if specified", func() {
 			m, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
@@ -238,6 +346,27 @@ var _ = Describe("controller.Controller", func() {
 			Expect(customNewQueueCalled).To(BeTrue(), "Expected customNewQueue to be called")
 		})
 
+		It("should default FieldManager to the name of the controller if configured on the manager", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			c, err := controller.New("field-manager-unset", m, controller.Options{Reconciler: reconcile.Func(nil)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).FieldManager).To(BeEmpty())
+
+			m, err = manager.New(cfg, manager.Options{Controller: config.Controller{UseNameAsFieldManager: ptr.To(true)}})
+			Expect(err).NotTo(HaveOccurred())
+			c, err = controller.New("field-manager-default", m, controller.Options{Reconciler: reconcile.Func(nil)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).FieldManager).To(Equal("field-manager-default"))
+
+			c, err = controller.New("field-manager-override", m, controller.Options{
+				Reconciler:   reconcile.Func(nil),
+				FieldManager: "custom",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.(*internalcontroller.Controller[reconcile.Request]).FieldManager).To(Equal("custom"))
+		})
+
 		It("should default RecoverPanic from the manager", func() {
 			m, err := manager.New(cfg, manager.Options{Controller: config.Controller{RecoverPanic: ptr.To(true)}})
 			Expect(err).NotTo(HaveOccurred())
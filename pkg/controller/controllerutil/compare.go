@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CompareOption configures how NeedsUpdate and UpdateIfChanged compare
+// objects.
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	ignored  map[string]bool
+	listKeys map[string][]string
+}
+
+// IgnoreFields makes the comparison ignore the fields at the given paths.
+// Paths are dot-separated field names, e.g. "spec.replicas", and go through
+// lists, e.g. "spec.template.spec.containers.image" is the image of every
+// container.
+func IgnoreFields(paths ...string) CompareOption {
+	return func(o *compareOptions) {
+		for _, path := range paths {
+			o.ignored[path] = true
+		}
+	}
+}
+
+// ListMapKeys makes the comparison match the elements of the list at path
+// by the values of the given keys rather than by their position, so that
+// the order of the list doesn't matter, e.g.
+//
+//	ListMapKeys("spec.template.spec.containers", "name")
+func ListMapKeys(path string, keys ...string) CompareOption {
+	return func(o *compareOptions) {
+		o.listKeys[path] = keys
+	}
+}
+
+// NeedsUpdate returns whether updating current with desired would change
+// it. The comparison is semantic:
+//
+//   - only the fields set in desired are compared, so that the fields
+//     defaulted by the API server are not considered changes. Fields left
+//     to their zero value in typed objects are not set, unless their JSON
+//     tag lacks omitempty. Note that an update does unset the fields that
+//     are not set in desired, such as labels added by others, unless the
+//     API server defaults them again,
+//   - the status and the metadata maintained by the API server are ignored,
+//   - quantities and numbers are compared by value,
+//   - the lists configured with ListMapKeys are compared regardless of
+//     their order.
+func NeedsUpdate(current, desired client.Object, opts ...CompareOption) (bool, error) {
+	o := &compareOptions{
+		ignored: map[string]bool{
+			"status":                     true,
+			"metadata.resourceVersion":   true,
+			"metadata.generation":        true,
+			"metadata.uid":               true,
+			"metadata.creationTimestamp": true,
+			"metadata.managedFields":     true,
+			"metadata.selfLink":          true,
+		},
+		listKeys: map[string][]string{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	currentContent, err := toUnstructured(current)
+	if err != nil {
+		return false, err
+	}
+	desiredContent, err := toUnstructured(desired)
+	if err != nil {
+		return false, err
+	}
+	return !o.contains(currentContent, desiredContent, ""), nil
+}
+
+// UpdateIfChanged updates current with desired, unless NeedsUpdate reports
+// that it wouldn't change it, so that reconcilers don't issue no-op updates,
+// which trigger watch events and thus event storms when several controllers
+// watch the same objects. current is typically the object read from the
+// cache, and desired the object built by the reconciler. The resource
+// version of current is used for the update unless desired sets one.
+//
+// It returns OperationResultUpdated if the object was updated, and
+// OperationResultNone otherwise.
+func UpdateIfChanged(ctx context.Context, c client.Client, current, desired client.Object, opts ...CompareOption) (OperationResult, error) {
+	changed, err := NeedsUpdate(current, desired, opts...)
+	if err != nil || !changed {
+		return OperationResultNone, err
+	}
+	if desired.GetResourceVersion() == "" {
+		desired.SetResourceVersion(current.GetResourceVersion())
+	}
+	if err := c.Update(ctx, desired); err != nil {
+		return OperationResultNone, err
+	}
+	return OperationResultUpdated, nil
+}
+
+func toUnstructured(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %T: %w", obj, err)
+	}
+	return content, nil
+}
+
+// contains returns whether the fields set in desired, at the given path,
+// have the same values in current.
+func (o *compareOptions) contains(current, desired interface{}, path string) bool {
+	if o.ignored[path] {
+		return true
+	}
+	switch desired := desired.(type) {
+	case map[string]interface{}:
+		current, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range desired {
+			if !o.contains(current[key], value, join(path, key)) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		current, ok := current.([]interface{})
+		if !ok || len(current) != len(desired) {
+			return false
+		}
+		if keys, ok := o.listKeys[path]; ok {
+			return o.containsListMap(current, desired, path, keys)
+		}
+		for i := range desired {
+			if !o.contains(current[i], desired[i], path) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		// Null fields are unset.
+		return true
+	default:
+		return scalarEqual(current, desired)
+	}
+}
+
+// containsListMap matches the elements of two lists by the values of keys.
+func (o *compareOptions) containsListMap(current, desired []interface{}, path string, keys []string) bool {
+	byKey := make(map[string]interface{}, len(current))
+	for _, element := range current {
+		byKey[listMapKey(element, keys)] = element
+	}
+	for _, element := range desired {
+		match, ok := byKey[listMapKey(element, keys)]
+		if !ok || !o.contains(match, element, path) {
+			return false
+		}
+	}
+	return true
+}
+
+func listMapKey(element interface{}, keys []string) string {
+	m, _ := element.(map[string]interface{})
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = fmt.Sprintf("%v", m[key])
+	}
+	return strings.Join(values, "\x00")
+}
+
+// scalarEqual compares JSON scalars, numbers by value whatever their type,
+// and strings holding quantities by the quantity they hold.
+func scalarEqual(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	if sa, ok := a.(string); ok {
+		sb, ok := b.(string)
+		return ok && (sa == sb || quantityEqual(sa, sb))
+	}
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+func quantityEqual(a, b string) bool {
+	qa, err := resource.ParseQuantity(a)
+	if err != nil {
+		return false
+	}
+	qb, err := resource.ParseQuantity(b)
+	if err != nil {
+		return false
+	}
+	return qa.Cmp(qb) == 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Comparing objects", func() {
+	var current, desired *appsv1.Deployment
+
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app", Labels: map[string]string{"app": "web"}},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](2),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{
+						{Name: "web", Image: "nginx", Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+						}},
+						{Name: "sidecar", Image: "envoy"},
+					}},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		desired = newDeployment()
+		// The current object has been defaulted and updated by the API server.
+		current = newDeployment()
+		current.ResourceVersion = "42"
+		current.UID = "uid"
+		current.Generation = 3
+		current.Spec.Strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+		current.Spec.Template.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+		current.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("0.5")
+		current.Status.Replicas = 2
+	})
+
+	It("should ignore defaulted fields, metadata maintained by the server and the status", func() {
+		Expect(controllerutil.NeedsUpdate(current, desired)).To(BeFalse())
+	})
+
+	It("should detect changed fields", func() {
+		desired.Spec.Template.Spec.Containers[1].Image = "envoy:v2"
+		Expect(controllerutil.NeedsUpdate(current, desired)).To(BeTrue())
+	})
+
+	It("should detect removed list elements", func() {
+		desired.Spec.Template.Spec.Containers = desired.Spec.Template.Spec.Containers[:1]
+		Expect(controllerutil.NeedsUpdate(current, desired)).To(BeTrue())
+	})
+
+	It("should ignore the order of lists with keys", func() {
+		containers := desired.Spec.Template.Spec.Containers
+		containers[0], containers[1] = containers[1], containers[0]
+		Expect(controllerutil.NeedsUpdate(current, desired)).To(BeTrue())
+		Expect(controllerutil.NeedsUpdate(current, desired, controllerutil.ListMapKeys("spec.template.spec.containers", "name"))).To(BeFalse())
+	})
+
+	It("should ignore the given fields", func() {
+		desired.Spec.Replicas = ptr.To[int32](5)
+		desired.Spec.Template.Spec.Containers[0].Image = "nginx:latest"
+		Expect(controllerutil.NeedsUpdate(current, desired, controllerutil.IgnoreFields("spec.replicas"))).To(BeTrue())
+		Expect(controllerutil.NeedsUpdate(current, desired, controllerutil.IgnoreFields("spec.replicas", "spec.template.spec.containers.image"))).To(BeFalse())
+	})
+
+	It("should compare typed and unstructured objects", func() {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+		Expect(err).NotTo(HaveOccurred())
+		u := &unstructured.Unstructured{Object: content}
+		Expect(controllerutil.NeedsUpdate(u, desired)).To(BeFalse())
+		desired.Spec.Replicas = ptr.To[int32](3)
+		Expect(controllerutil.NeedsUpdate(u, desired)).To(BeTrue())
+	})
+
+	Describe("UpdateIfChanged", func() {
+		var (
+			cl      client.Client
+			updates int
+		)
+
+		BeforeEach(func() {
+			updates = 0
+			cl = fake.NewClientBuilder().WithObjects(current).WithInterceptorFuncs(interceptor.Funcs{
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					updates++
+					return c.Update(ctx, obj, opts...)
+				},
+			}).Build()
+			Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(current), current)).To(Succeed())
+		})
+
+		It("should not update unchanged objects", func() {
+			Expect(controllerutil.UpdateIfChanged(context.Background(), cl, current, desired)).To(Equal(controllerutil.OperationResultNone))
+			Expect(updates).To(BeZero())
+		})
+
+		It("should update changed objects", func() {
+			desired.Spec.Replicas = ptr.To[int32](3)
+			Expect(controllerutil.UpdateIfChanged(context.Background(), cl, current, desired)).To(Equal(controllerutil.OperationResultUpdated))
+			Expect(updates).To(Equal(1))
+
+			updated := &appsv1.Deployment{}
+			Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(desired), updated)).To(Succeed())
+			Expect(updated.Spec.Replicas).To(Equal(ptr.To[int32](3)))
+		})
+	})
+})
@@ -492,3 +492,44 @@ func ContainsFinalizer(o client.Object, finalizer string) bool {
 	}
 	return false
 }
+
+// DeleteInOrder deletes the given stages of objects one after the other:
+// all objects of a stage are deleted, and the next stage is only started
+// once every object of the previous stage is gone. This is useful when
+// finalizing an owner whose dependents must be torn down in a specific
+// order, e.g. an application before the database it uses.
+//
+// DeleteInOrder doesn't block. It returns false while objects are still
+// being deleted, in which case the caller should requeue and call it
+// again, and true once all objects of all stages are gone. Only the names
+// and namespaces of the given objects are used. Using a client that reads
+// from the API server rather than the cache avoids needless requeues.
+func DeleteInOrder(ctx context.Context, c client.Client, stages [][]client.Object, opts ...client.DeleteOption) (done bool, err error) {
+	for _, stage := range stages {
+		remaining := false
+		for _, obj := range stage {
+			current, ok := obj.DeepCopyObject().(client.Object)
+			if !ok {
+				return false, fmt.Errorf("unable to copy object %T", obj)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return false, err
+			}
+
+			remaining = true
+			if current.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := c.Delete(ctx, current, opts...); client.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+		}
+		if remaining {
+			return false, nil
+		}
+	}
+	return true, nil
+}
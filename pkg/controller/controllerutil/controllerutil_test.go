@@ -26,12 +26,14 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -856,6 +858,46 @@ var _ = Describe("Controllerutil", func() {
 		})
 	})
 
+	Describe("DeleteInOrder", func() {
+		It("should only delete a stage once the previous stage is gone", func() {
+			ctx := context.Background()
+			app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "app", Namespace: "default", Finalizers: []string{"example.com/finalizer"},
+			}}
+			db := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+			fakeClient := fake.NewClientBuilder().WithObjects(app, db).Build()
+			stages := [][]client.Object{{app}, {db}}
+
+			By("deleting the first stage")
+			done, err := controllerutil.DeleteInOrder(ctx, fakeClient, stages)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(app), app)).To(Succeed())
+			Expect(app.DeletionTimestamp).NotTo(BeNil())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(db), db)).To(Succeed())
+			Expect(db.DeletionTimestamp).To(BeNil())
+
+			By("waiting for the first stage to be gone")
+			done, err = controllerutil.DeleteInOrder(ctx, fakeClient, stages)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(db), db)).To(Succeed())
+
+			By("deleting the second stage once the first one is gone")
+			app.Finalizers = nil
+			Expect(fakeClient.Update(ctx, app)).To(Succeed())
+			done, err = controllerutil.DeleteInOrder(ctx, fakeClient, stages)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(db), db))).To(BeTrue())
+
+			By("reporting completion once all stages are gone")
+			done, err = controllerutil.DeleteInOrder(ctx, fakeClient, stages)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeTrue())
+		})
+	})
+
 	Describe("Finalizers", func() {
 		var deploy *appsv1.Deployment
 
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// HashOption configures ComputeHash.
+type HashOption func(*hashOptions)
+
+type hashOptions struct {
+	pruned         map[string]bool
+	collisionCount *int32
+}
+
+// PruneFields excludes the fields at the given paths from the hash. Paths
+// are dot-separated field names, e.g. "metadata.annotations", and go through
+// lists, e.g. "containers.image" is the image of every container.
+func PruneFields(paths ...string) HashOption {
+	return func(o *hashOptions) {
+		for _, path := range paths {
+			o.pruned[path] = true
+		}
+	}
+}
+
+// WithCollisionCount adds count, if set, to the hash, so that a new hash
+// can be computed when a name built from the hash collides with an existing
+// object, like the collisionCount of the status of Deployments.
+func WithCollisionCount(count *int32) HashOption {
+	return func(o *hashOptions) {
+		o.collisionCount = count
+	}
+}
+
+// ComputeHash returns a short hash of obj, typically the spec or pod
+// template of an object, that is safe to use in names and label values,
+// e.g. to name child objects like the pod-template-hash of ReplicaSets, or
+// to detect changes. obj may be a typed or unstructured object, or any
+// value that can be converted to JSON.
+//
+// The hash is deterministic across processes and releases: it is computed
+// from the JSON representation of obj, with sorted keys, and with null
+// fields and empty maps and lists removed, so that unset and empty fields
+// hash the same. Objects that differ only by pruned fields hash the same.
+func ComputeHash(obj interface{}, opts ...HashOption) (string, error) {
+	o := &hashOptions{pruned: map[string]bool{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	content, err := toJSONValue(obj)
+	if err != nil {
+		return "", err
+	}
+	normalized, _ := o.normalize(content, "")
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %T: %w", obj, err)
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	if o.collisionCount != nil {
+		_, _ = hasher.Write([]byte(strconv.FormatInt(int64(*o.collisionCount), 10)))
+	}
+	return rand.SafeEncodeString(strconv.FormatUint(uint64(hasher.Sum32()), 10)), nil
+}
+
+// toJSONValue returns the JSON representation of obj as generic values.
+func toJSONValue(obj interface{}) (interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash %T: %w", obj, err)
+	}
+	// Keep numbers as they are written, so that large integers don't lose
+	// precision.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to hash %T: %w", obj, err)
+	}
+	return value, nil
+}
+
+// normalize removes the pruned fields, null fields and empty maps and lists
+// from value, and returns false if value itself is to be removed.
+func (o *hashOptions) normalize(value interface{}, path string) (interface{}, bool) {
+	if path != "" && o.pruned[path] {
+		return nil, false
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			if field, ok := o.normalize(field, join(path, key)); ok {
+				normalized[key] = field
+			}
+		}
+		return normalized, len(normalized) > 0
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(value))
+		for _, element := range value {
+			// Keep empty elements, which matter to the position of others.
+			element, _ := o.normalize(element, path)
+			normalized = append(normalized, element)
+		}
+		return normalized, len(normalized) > 0
+	case nil:
+		return nil, false
+	default:
+		return value, true
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Hashing objects", func() {
+	var template *corev1.PodTemplateSpec
+
+	BeforeEach(func() {
+		template = &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"app": "web"},
+				Annotations: map[string]string{"restartedAt": "now"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx"}}},
+		}
+	})
+
+	It("should compute a stable hash usable as a label value", func() {
+		hash, err := controllerutil.ComputeHash(template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validation.IsValidLabelValue(hash)).To(BeEmpty())
+		Expect(controllerutil.ComputeHash(template.DeepCopy())).To(Equal(hash))
+
+		template.Spec.Containers[0].Image = "nginx:latest"
+		Expect(controllerutil.ComputeHash(template)).NotTo(Equal(hash))
+	})
+
+	It("should hash unset and empty fields the same", func() {
+		hash, err := controllerutil.ComputeHash(template)
+		Expect(err).NotTo(HaveOccurred())
+		template.Spec.NodeSelector = map[string]string{}
+		template.Spec.Volumes = []corev1.Volume{}
+		Expect(controllerutil.ComputeHash(template)).To(Equal(hash))
+	})
+
+	It("should hash typed and unstructured objects the same", func() {
+		template.Spec.TerminationGracePeriodSeconds = ptr.To[int64](30)
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+		Expect(err).NotTo(HaveOccurred())
+		hash, err := controllerutil.ComputeHash(template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ComputeHash(&unstructured.Unstructured{Object: content})).To(Equal(hash))
+	})
+
+	It("should ignore pruned fields", func() {
+		hash, err := controllerutil.ComputeHash(template, controllerutil.PruneFields("metadata.annotations", "spec.containers.image"))
+		Expect(err).NotTo(HaveOccurred())
+		template.Annotations["restartedAt"] = "later"
+		template.Spec.Containers[0].Image = "nginx:latest"
+		Expect(controllerutil.ComputeHash(template, controllerutil.PruneFields("metadata.annotations", "spec.containers.image"))).To(Equal(hash))
+		template.Spec.Containers[0].Name = "nginx"
+		Expect(controllerutil.ComputeHash(template, controllerutil.PruneFields("metadata.annotations", "spec.containers.image"))).NotTo(Equal(hash))
+	})
+
+	It("should change with the collision count", func() {
+		hash, err := controllerutil.ComputeHash(template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllerutil.ComputeHash(template, controllerutil.WithCollisionCount(nil))).To(Equal(hash))
+		Expect(controllerutil.ComputeHash(template, controllerutil.WithCollisionCount(ptr.To[int32](1)))).NotTo(Equal(hash))
+	})
+})
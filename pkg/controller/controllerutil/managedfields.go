@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// FieldOwnershipConflict is a field owned by several field managers.
+type FieldOwnershipConflict struct {
+	// Path is the path of the field, e.g. ".spec.replicas", or
+	// ".spec.containers[name=\"nginx\"].image" for the field of an item of
+	// an associative list.
+	Path string
+
+	// Managers are the other managers owning the field, sorted.
+	Managers []string
+}
+
+// FieldManagers returns the sorted names of the field managers owning the
+// field at path in the managedFields of obj, or a field below it. The path is
+// a list of field names, e.g. FieldManagers(obj, "spec", "replicas"); an
+// empty path returns all the managers owning fields of obj.
+func FieldManagers(obj metav1.Object, path ...string) ([]string, error) {
+	managedSets, err := managedFieldSets(obj)
+	if err != nil {
+		return nil, err
+	}
+	fieldPath := makeFieldPath(path)
+	var managers []string
+	for manager, set := range managedSets {
+		if ownsFieldPath(set, fieldPath) {
+			managers = append(managers, manager)
+		}
+	}
+	sort.Strings(managers)
+	return managers, nil
+}
+
+// OwnsField returns true if the field manager named manager owns the field
+// at path in the managedFields of obj, or a field below it. It tells whether
+// a value was set by the manager, e.g. to only correct the drift of fields
+// the manager is responsible for.
+func OwnsField(obj metav1.Object, manager string, path ...string) (bool, error) {
+	managedSets, err := managedFieldSets(obj)
+	if err != nil {
+		return false, err
+	}
+	set, ok := managedSets[manager]
+	return ok && ownsFieldPath(set, makeFieldPath(path)), nil
+}
+
+// FieldOwnershipConflicts returns the fields owned by the field manager
+// named manager that other managers own too, sorted by path. Co-owned fields
+// are fields that several managers applied with the same value, or that
+// another manager took over with a forced apply or an update: changing them
+// with an apply that is not forced fails with a conflict.
+func FieldOwnershipConflicts(obj metav1.Object, manager string) ([]FieldOwnershipConflict, error) {
+	managedSets, err := managedFieldSets(obj)
+	if err != nil {
+		return nil, err
+	}
+	owned, ok := managedSets[manager]
+	if !ok {
+		return nil, nil
+	}
+
+	coOwners := map[string]sets.Set[string]{}
+	for other, set := range managedSets {
+		if other == manager {
+			continue
+		}
+		owned.Intersection(set).Leaves().Iterate(func(p fieldpath.Path) {
+			key := p.String()
+			if coOwners[key] == nil {
+				coOwners[key] = sets.New[string]()
+			}
+			coOwners[key].Insert(other)
+		})
+	}
+
+	conflicts := make([]FieldOwnershipConflict, 0, len(coOwners))
+	for path, managers := range coOwners {
+		conflicts = append(conflicts, FieldOwnershipConflict{Path: path, Managers: sets.List(managers)})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	return conflicts, nil
+}
+
+// managedFieldSets returns the fields owned by each manager of obj. The
+// entries of the operations of a manager are merged, since a manager owning
+// a field through an update conflicts with its applies as little as when it
+// owns it through an apply.
+func managedFieldSets(obj metav1.Object) (map[string]*fieldpath.Set, error) {
+	managed := map[string]*fieldpath.Set{}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("failed to decode the fields managed by %q: %w", entry.Manager, err)
+		}
+		if existing, ok := managed[entry.Manager]; ok {
+			set = existing.Union(set)
+		}
+		managed[entry.Manager] = set
+	}
+	return managed, nil
+}
+
+func makeFieldPath(names []string) fieldpath.Path {
+	path := make(fieldpath.Path, 0, len(names))
+	for i := range names {
+		path = append(path, fieldpath.PathElement{FieldName: &names[i]})
+	}
+	return path
+}
+
+// ownsFieldPath returns true if set holds path, or a path below it.
+func ownsFieldPath(set *fieldpath.Set, path fieldpath.Path) bool {
+	if set.Has(path) {
+		return true
+	}
+	for _, element := range path {
+		set = set.WithPrefix(element)
+	}
+	return !set.Empty()
+}
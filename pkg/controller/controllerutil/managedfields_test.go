@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Managed fields", func() {
+	managedFields := func(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  operation,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+		}
+	}
+
+	var deploy *appsv1.Deployment
+	BeforeEach(func() {
+		deploy = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				managedFields("controller", metav1.ManagedFieldsOperationApply,
+					`{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"nginx\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`),
+				managedFields("controller", metav1.ManagedFieldsOperationUpdate,
+					`{"f:status":{"f:replicas":{}}}`),
+				managedFields("kubectl", metav1.ManagedFieldsOperationUpdate,
+					`{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"nginx\"}":{"f:image":{}}}}}}}`),
+				managedFields("hpa", metav1.ManagedFieldsOperationUpdate,
+					`{"f:spec":{"f:replicas":{}}}`),
+			},
+		}}
+	})
+
+	Describe("FieldManagers", func() {
+		It("should return the managers owning a field", func() {
+			Expect(controllerutil.FieldManagers(deploy, "spec", "replicas")).To(Equal([]string{"controller", "hpa"}))
+			Expect(controllerutil.FieldManagers(deploy, "status", "replicas")).To(Equal([]string{"controller"}))
+			Expect(controllerutil.FieldManagers(deploy, "spec", "paused")).To(BeEmpty())
+		})
+
+		It("should return the managers owning fields below a path", func() {
+			Expect(controllerutil.FieldManagers(deploy, "spec", "template")).To(Equal([]string{"controller", "kubectl"}))
+			Expect(controllerutil.FieldManagers(deploy)).To(Equal([]string{"controller", "hpa", "kubectl"}))
+		})
+
+		It("should fail for invalid managed fields", func() {
+			deploy.ManagedFields = append(deploy.ManagedFields, managedFields("broken", metav1.ManagedFieldsOperationUpdate, "{"))
+			_, err := controllerutil.FieldManagers(deploy, "spec")
+			Expect(err).To(MatchError(ContainSubstring(`failed to decode the fields managed by "broken"`)))
+		})
+	})
+
+	Describe("OwnsField", func() {
+		It("should tell whether a manager owns a field", func() {
+			Expect(controllerutil.OwnsField(deploy, "controller", "status", "replicas")).To(BeTrue())
+			Expect(controllerutil.OwnsField(deploy, "hpa", "spec")).To(BeTrue())
+			Expect(controllerutil.OwnsField(deploy, "hpa", "status")).To(BeFalse())
+			Expect(controllerutil.OwnsField(deploy, "unknown", "spec")).To(BeFalse())
+		})
+	})
+
+	Describe("FieldOwnershipConflicts", func() {
+		It("should return the fields co-owned by other managers", func() {
+			Expect(controllerutil.FieldOwnershipConflicts(deploy, "controller")).To(Equal([]controllerutil.FieldOwnershipConflict{
+				{Path: ".spec.replicas", Managers: []string{"hpa"}},
+				{Path: `.spec.template.spec.containers[name="nginx"].image`, Managers: []string{"kubectl"}},
+			}))
+		})
+
+		It("should return no conflicts for managers owning no fields", func() {
+			Expect(controllerutil.FieldOwnershipConflicts(deploy, "unknown")).To(BeEmpty())
+		})
+	})
+})
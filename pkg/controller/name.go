@@ -17,27 +17,9 @@ limitations under the License.
 package controller
 
 import (
-	"fmt"
-	"sync"
-
-	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/internal/controller"
 )
 
-var nameLock sync.Mutex
-var usedNames sets.Set[string]
-
-func checkName(name string) error {
-	nameLock.Lock()
-	defer nameLock.Unlock()
-	if usedNames == nil {
-		usedNames = sets.Set[string]{}
-	}
-
-	if usedNames.Has(name) {
-		return fmt.Errorf("controller with name %s already exists. Controller names must be unique to avoid multiple controllers reporting to the same metric", name)
-	}
-
-	usedNames.Insert(name)
-
-	return nil
-}
+// ErrNameAlreadyExists is returned, wrapped, when a controller is created
+// with a name that is already used by another controller.
+var ErrNameAlreadyExists = controller.ErrNameAlreadyExists
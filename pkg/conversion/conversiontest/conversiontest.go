@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversiontest provides helpers to test the conversions of types
+// implementing conversion.Convertible, by fuzzing objects and checking that
+// they survive a round trip through another version unchanged:
+//
+//	func TestConversion(t *testing.T) {
+//		conversiontest.FuzzRoundTripHub(t, &v2.CronJob{}, &v1.CronJob{}, conversiontest.Options{})
+//		conversiontest.FuzzRoundTripSpoke(t, &v1.CronJob{}, &v2.CronJob{}, conversiontest.Options{})
+//	}
+//
+// Fields that can't be represented in all versions must be preserved, e.g.
+// in annotations, for the round trips to succeed, as required by the API
+// server for conversion webhooks.
+package conversiontest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// Options configures the fuzzing performed by FuzzRoundTripHub and
+// FuzzRoundTripSpoke.
+type Options struct {
+	// Iterations is the number of fuzzed objects to round trip. Defaults
+	// to 100.
+	Iterations int
+
+	// Seed seeds the fuzzer. Defaults to a random seed, which is logged so
+	// that failures can be reproduced.
+	Seed int64
+
+	// FuzzerFuncs are custom fuzzer functions, e.g. to constrain fields to
+	// the values a conversion supports. They are merged with, and take
+	// precedence over, the fuzzer functions of the metav1 types.
+	FuzzerFuncs fuzzer.FuzzerFuncs
+}
+
+// FuzzRoundTripHub fuzzes hub objects, converts them to the spoke version
+// and back, and reports an error on t for every object that changed. hub
+// and spoke are only used for their types.
+func FuzzRoundTripHub(t testing.TB, hub conversion.Hub, spoke conversion.Convertible, opts Options) {
+	t.Helper()
+	fuzzRoundTrip(t, hub, opts, func(obj runtime.Object) (runtime.Object, error) {
+		return RoundTripHub(obj.(conversion.Hub), spoke)
+	})
+}
+
+// FuzzRoundTripSpoke fuzzes spoke objects, converts them to the hub version
+// and back, and reports an error on t for every object that changed. spoke
+// and hub are only used for their types.
+func FuzzRoundTripSpoke(t testing.TB, spoke conversion.Convertible, hub conversion.Hub, opts Options) {
+	t.Helper()
+	fuzzRoundTrip(t, spoke, opts, func(obj runtime.Object) (runtime.Object, error) {
+		return RoundTripSpoke(obj.(conversion.Convertible), hub)
+	})
+}
+
+func fuzzRoundTrip(t testing.TB, obj runtime.Object, opts Options, roundTrip func(runtime.Object) (runtime.Object, error)) {
+	t.Helper()
+	if opts.Iterations <= 0 {
+		opts.Iterations = 100
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	f := fuzzer.FuzzerFor(
+		fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, opts.FuzzerFuncs),
+		rand.NewSource(opts.Seed),
+		serializer.NewCodecFactory(runtime.NewScheme()),
+	)
+	t.Logf("Fuzzing %T with seed %d", obj, opts.Seed)
+
+	for i := range opts.Iterations {
+		original := newObject(obj)
+		f.Fuzz(original)
+		fuzzed := original.DeepCopyObject()
+
+		result, err := roundTrip(fuzzed)
+		if err != nil {
+			t.Errorf("iteration %d: failed to round trip %T: %v", i, obj, err)
+			continue
+		}
+		if diff := Diff(original, result); diff != "" {
+			t.Errorf("iteration %d: %T changed after a round trip (-original +result):\n%s", i, obj, diff)
+		}
+	}
+}
+
+// RoundTripHub converts hub to the version of spoke and back, and returns
+// the resulting hub object. Neither hub nor spoke are modified.
+func RoundTripHub(hub conversion.Hub, spoke conversion.Convertible) (conversion.Hub, error) {
+	converted := newObject(spoke).(conversion.Convertible)
+	if err := converted.ConvertFrom(hub.DeepCopyObject().(conversion.Hub)); err != nil {
+		return nil, fmt.Errorf("failed to convert %T to %T: %w", hub, spoke, err)
+	}
+	result := newObject(hub).(conversion.Hub)
+	if err := converted.ConvertTo(result); err != nil {
+		return nil, fmt.Errorf("failed to convert %T to %T: %w", spoke, hub, err)
+	}
+	return result, nil
+}
+
+// RoundTripSpoke converts spoke to the version of hub and back, and returns
+// the resulting spoke object. Neither spoke nor hub are modified.
+func RoundTripSpoke(spoke conversion.Convertible, hub conversion.Hub) (conversion.Convertible, error) {
+	converted := newObject(hub).(conversion.Hub)
+	if err := spoke.DeepCopyObject().(conversion.Convertible).ConvertTo(converted); err != nil {
+		return nil, fmt.Errorf("failed to convert %T to %T: %w", spoke, hub, err)
+	}
+	result := newObject(spoke).(conversion.Convertible)
+	if err := result.ConvertFrom(converted); err != nil {
+		return nil, fmt.Errorf("failed to convert %T to %T: %w", hub, spoke, err)
+	}
+	return result, nil
+}
+
+// Diff returns a human readable diff of two objects, or an empty string if
+// they are semantically equal. Their TypeMeta is ignored, since
+// conversions are not expected to set it.
+func Diff(expected, actual runtime.Object) string {
+	expected, actual = withoutTypeMeta(expected), withoutTypeMeta(actual)
+	if equality.Semantic.DeepEqual(expected, actual) {
+		return ""
+	}
+	return cmp.Diff(expected, actual)
+}
+
+func withoutTypeMeta(obj runtime.Object) runtime.Object {
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	return obj
+}
+
+// newObject returns a new, empty object of the type of obj, which must be
+// a pointer to a struct.
+func newObject(obj runtime.Object) runtime.Object {
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversiontest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion/conversiontest"
+	jobsv1 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v1"
+	jobsv2 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v2"
+)
+
+// lossyJob drops the labels of the objects it is converted from.
+type lossyJob struct {
+	jobsv1.ExternalJob
+}
+
+func (j *lossyJob) ConvertFrom(src conversion.Hub) error {
+	if err := j.ExternalJob.ConvertFrom(src); err != nil {
+		return err
+	}
+	j.Labels = nil
+	return nil
+}
+
+// recordingTB records the errors reported by the helpers.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper()                     {}
+func (r *recordingTB) Logf(string, ...interface{}) {}
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestFuzzRoundTrip(t *testing.T) {
+	conversiontest.FuzzRoundTripHub(t, &jobsv2.ExternalJob{}, &jobsv1.ExternalJob{}, conversiontest.Options{})
+	conversiontest.FuzzRoundTripSpoke(t, &jobsv1.ExternalJob{}, &jobsv2.ExternalJob{}, conversiontest.Options{})
+}
+
+func TestFuzzRoundTripReportsLossyConversions(t *testing.T) {
+	tb := &recordingTB{}
+	conversiontest.FuzzRoundTripHub(tb, &jobsv2.ExternalJob{}, &lossyJob{}, conversiontest.Options{Iterations: 20, Seed: 1})
+	if len(tb.errors) == 0 {
+		t.Fatalf("expected lossy conversions to be reported")
+	}
+	if !strings.Contains(tb.errors[0], "Labels") {
+		t.Errorf("expected the diff to mention the lost labels, got %s", tb.errors[0])
+	}
+}
+
+func TestDiff(t *testing.T) {
+	job := &jobsv1.ExternalJob{Spec: jobsv1.ExternalJobSpec{RunAt: "now"}}
+	same := job.DeepCopy()
+	same.Kind = "ExternalJob"
+	if diff := conversiontest.Diff(job, same); diff != "" {
+		t.Errorf("expected objects differing by their TypeMeta to be equal, got %s", diff)
+	}
+	other := job.DeepCopy()
+	other.Spec.RunAt = "later"
+	if diff := conversiontest.Diff(job, other); !strings.Contains(diff, "later") {
+		t.Errorf("expected a diff, got %q", diff)
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultBinaryAssetsIndexURL is the index of the releases of the
+// control-plane binaries also used by setup-envtest.
+const DefaultBinaryAssetsIndexURL = "https://raw.githubusercontent.com/kubernetes-sigs/controller-tools/HEAD/envtest-releases.yaml"
+
+// binaryAssets are the binaries of a release.
+var binaryAssets = []string{"kube-apiserver", "etcd", "kubectl"}
+
+// BinaryAssetsOptions configures DownloadBinaryAssets.
+type BinaryAssetsOptions struct {
+	// Version is the Kubernetes version of the binaries, e.g. "1.30.0". A
+	// version without patch, e.g. "1.30", selects its latest patch release.
+	// Defaults to the latest release.
+	Version string
+
+	// IndexURL is the URL of the index of the releases. Defaults to
+	// DefaultBinaryAssetsIndexURL.
+	IndexURL string
+
+	// Directory is the directory the binaries are cached in, in a
+	// subdirectory per release and platform laid out like the store of
+	// setup-envtest, e.g. "k8s/1.30.0-linux-amd64". Defaults to
+	// "kubebuilder-envtest" in the user cache directory.
+	Directory string
+
+	// OS and Arch are the platform of the binaries. They default to the
+	// platform of the running program.
+	OS   string
+	Arch string
+
+	// HTTPClient is used to download the index and the binaries. Defaults
+	// to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// binaryAssetsIndex is the index of the releases of the binaries.
+type binaryAssetsIndex struct {
+	// Releases maps versions, e.g. "v1.30.0", to their archives by name,
+	// e.g. "envtest-v1.30.0-linux-amd64.tar.gz".
+	Releases map[string]map[string]binaryAssetsArchive `json:"releases"`
+}
+
+type binaryAssetsArchive struct {
+	// Hash is the hex-encoded SHA-512 checksum of the archive.
+	Hash     string `json:"hash"`
+	SelfLink string `json:"selfLink"`
+}
+
+// DownloadBinaryAssets returns the directory holding the kube-apiserver,
+// etcd and kubectl binaries of the release selected by opts, downloading
+// them if they are not cached yet. Downloaded archives are verified against
+// the checksums of the index. Selecting a release requires downloading the
+// index, unless Version is a full version whose binaries are cached.
+func DownloadBinaryAssets(ctx context.Context, opts BinaryAssetsOptions) (string, error) {
+	if err := opts.setDefaults(); err != nil {
+		return "", err
+	}
+
+	if v, err := version.ParseSemantic(strings.TrimPrefix(opts.Version, "v")); err == nil {
+		if dir := opts.releaseDirectory(v); hasBinaryAssets(dir) {
+			return dir, nil
+		}
+	}
+
+	index, err := opts.fetchIndex(ctx)
+	if err != nil {
+		return "", err
+	}
+	v, archive, err := opts.selectRelease(index)
+	if err != nil {
+		return "", err
+	}
+	dir := opts.releaseDirectory(v)
+	if hasBinaryAssets(dir) {
+		return dir, nil
+	}
+	if err := opts.download(ctx, archive, dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func (o *BinaryAssetsOptions) setDefaults() error {
+	if o.IndexURL == "" {
+		o.IndexURL = DefaultBinaryAssetsIndexURL
+	}
+	if o.Directory == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("failed to find the directory to cache binary assets in: %w", err)
+		}
+		o.Directory = filepath.Join(cacheDir, "kubebuilder-envtest")
+	}
+	if o.OS == "" {
+		o.OS = runtime.GOOS
+	}
+	if o.Arch == "" {
+		o.Arch = runtime.GOARCH
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+	return nil
+}
+
+func (o *BinaryAssetsOptions) releaseDirectory(v *version.Version) string {
+	return filepath.Join(o.Directory, "k8s", fmt.Sprintf("%s-%s-%s", v, o.OS, o.Arch))
+}
+
+func hasBinaryAssets(dir string) bool {
+	for _, name := range binaryAssets {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *BinaryAssetsOptions) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (o *BinaryAssetsOptions) fetchIndex(ctx context.Context) (*binaryAssetsIndex, error) {
+	body, err := o.get(ctx, o.IndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the index of binary assets from %s: %w", o.IndexURL, err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the index of binary assets from %s: %w", o.IndexURL, err)
+	}
+	index := &binaryAssetsIndex{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to decode the index of binary assets from %s: %w", o.IndexURL, err)
+	}
+	return index, nil
+}
+
+// selectRelease returns the latest release of the index matching the
+// requested version, with an archive for the requested platform.
+func (o *BinaryAssetsOptions) selectRelease(index *binaryAssetsIndex) (*version.Version, binaryAssetsArchive, error) {
+	var (
+		selected *version.Version
+		archive  binaryAssetsArchive
+	)
+	for name, archives := range index.Releases {
+		v, err := version.ParseSemantic(strings.TrimPrefix(name, "v"))
+		if err != nil || !o.matches(v) {
+			continue
+		}
+		a, ok := archives[fmt.Sprintf("envtest-v%s-%s-%s.tar.gz", v, o.OS, o.Arch)]
+		if !ok {
+			continue
+		}
+		if selected == nil || v.GreaterThan(selected) {
+			selected, archive = v, a
+		}
+	}
+	if selected == nil {
+		return nil, archive, fmt.Errorf("no release of binary assets matches version %q for %s/%s", o.Version, o.OS, o.Arch)
+	}
+	return selected, archive, nil
+}
+
+// matches returns true if v is the requested version, or one of its patch
+// releases if it has no patch number. Pre-releases only match exactly.
+func (o *BinaryAssetsOptions) matches(v *version.Version) bool {
+	requested := strings.TrimPrefix(o.Version, "v")
+	if requested == "" {
+		return v.PreRelease() == ""
+	}
+	if v.String() == requested {
+		return true
+	}
+	return v.PreRelease() == "" && strings.HasPrefix(v.String(), requested+".") && strings.Count(requested, ".") == 1
+}
+
+// download downloads and verifies archive, and extracts its binaries into
+// dir. The binaries are extracted into a temporary directory first, so
+// that dir only exists once it is complete.
+func (o *BinaryAssetsOptions) download(ctx context.Context, archive binaryAssetsArchive, dir string) error {
+	body, err := o.get(ctx, archive.SelfLink)
+	if err != nil {
+		return fmt.Errorf("failed to download binary assets from %s: %w", archive.SelfLink, err)
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(dir), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	hash := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hash), body); err != nil {
+		return fmt.Errorf("failed to download binary assets from %s: %w", archive.SelfLink, err)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, archive.Hash) {
+		return fmt.Errorf("checksum mismatch for binary assets from %s: expected %s, got %s", archive.SelfLink, archive.Hash, sum)
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), ".extract-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := extractBinaryAssets(tmpFile, tmpDir); err != nil {
+		return fmt.Errorf("failed to extract binary assets from %s: %w", archive.SelfLink, err)
+	}
+	if !hasBinaryAssets(tmpDir) {
+		return fmt.Errorf("binary assets from %s miss some of %s", archive.SelfLink, strings.Join(binaryAssets, ", "))
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		// Another process may have downloaded the binaries concurrently.
+		if hasBinaryAssets(dir) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// extractBinaryAssets extracts the binaries of a gzipped tarball into dir,
+// flattening their paths.
+func extractBinaryAssets(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Base(hdr.Name)
+		if !slices.Contains(binaryAssets, name) {
+			continue
+		}
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil { //nolint:gosec // The archive was verified against its checksum.
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DownloadBinaryAssets", func() {
+	var (
+		server    *httptest.Server
+		downloads map[string]int
+		archives  map[string][]byte
+		hashes    map[string]string
+		opts      BinaryAssetsOptions
+	)
+
+	newArchive := func(version string) []byte {
+		buf := &bytes.Buffer{}
+		gz := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gz)
+		for _, name := range []string{"kube-apiserver", "etcd", "kubectl"} {
+			content := []byte(name + " " + version)
+			Expect(tw.WriteHeader(&tar.Header{Name: "controller-tools/envtest/" + name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write(content)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		Expect(gz.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	BeforeEach(func() {
+		downloads = map[string]int{}
+		archives = map[string][]byte{}
+		hashes = map[string]string{}
+		for _, v := range []string{"1.29.3", "1.30.0", "1.30.2"} {
+			name := fmt.Sprintf("envtest-v%s-linux-amd64.tar.gz", v)
+			archives[name] = newArchive(v)
+			sum := sha512.Sum512(archives[name])
+			hashes[name] = hex.EncodeToString(sum[:])
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := filepath.Base(r.URL.Path)
+			downloads[name]++
+			if name == "index.yaml" {
+				fmt.Fprintln(w, "releases:")
+				for _, v := range []string{"1.29.3", "1.30.0", "1.30.2"} {
+					archive := fmt.Sprintf("envtest-v%s-linux-amd64.tar.gz", v)
+					fmt.Fprintf(w, "  v%s:\n    %s:\n      hash: %s\n      selfLink: %s/%s\n", v, archive, hashes[archive], server.URL, archive)
+				}
+				return
+			}
+			_, _ = w.Write(archives[name])
+		}))
+		DeferCleanup(server.Close)
+		opts = BinaryAssetsOptions{
+			IndexURL:  server.URL + "/index.yaml",
+			Directory: GinkgoT().TempDir(),
+			OS:        "linux",
+			Arch:      "amd64",
+		}
+	})
+
+	It("should download the latest release and cache it", func() {
+		dir, err := DownloadBinaryAssets(context.Background(), opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dir).To(Equal(filepath.Join(opts.Directory, "k8s", "1.30.2-linux-amd64")))
+		content, err := os.ReadFile(filepath.Join(dir, "kube-apiserver"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("kube-apiserver 1.30.2"))
+
+		opts.Version = "1.30.2"
+		_, err = DownloadBinaryAssets(context.Background(), opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloads["index.yaml"]).To(Equal(1))
+		Expect(downloads["envtest-v1.30.2-linux-amd64.tar.gz"]).To(Equal(1))
+	})
+
+	It("should select the latest patch release of a minor version", func() {
+		opts.Version = "1.29"
+		dir, err := DownloadBinaryAssets(context.Background(), opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dir).To(Equal(filepath.Join(opts.Directory, "k8s", "1.29.3-linux-amd64")))
+	})
+
+	It("should fail when no release matches", func() {
+		opts.Version = "1.31.0"
+		_, err := DownloadBinaryAssets(context.Background(), opts)
+		Expect(err).To(MatchError(ContainSubstring("no release of binary assets matches")))
+	})
+
+	It("should reject archives that don't match their checksum", func() {
+		archives["envtest-v1.30.2-linux-amd64.tar.gz"] = newArchive("tampered")
+		_, err := DownloadBinaryAssets(context.Background(), opts)
+		Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+		_, err = os.Stat(filepath.Join(opts.Directory, "k8s", "1.30.2-linux-amd64"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
@@ -65,6 +65,11 @@ type CRDInstallOptions struct {
 	// ErrorIfPathMissing will cause an error if a Path does not exist
 	ErrorIfPathMissing bool
 
+	// Generate, if set, generates CRDs from Go type definitions before
+	// installing them, so that tests never run against stale CRD manifests.
+	// The generated CRDs are installed in addition to CRDs and Paths.
+	Generate *CRDGenerateOptions
+
 	// MaxTime is the max time to wait
 	MaxTime time.Duration
 
@@ -116,7 +121,25 @@ func InstallCRDs(config *rest.Config, options CRDInstallOptions) ([]*apiextensio
 }
 
 // readCRDFiles reads the directories of CRDs in options.Paths and adds the CRD structs to options.CRDs.
+// If options.Generate is set, the generated CRDs are added as well.
 func readCRDFiles(options *CRDInstallOptions) error {
+	if options.Generate != nil {
+		dir, err := os.MkdirTemp("", "envtest-crds-")
+		if err != nil {
+			return fmt.Errorf("unable to create directory for generated CRDs: %w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := generateCRDs(*options.Generate, dir); err != nil {
+			return err
+		}
+		generated, err := renderCRDs(&CRDInstallOptions{Paths: []string{dir}, ErrorIfPathMissing: true})
+		if err != nil {
+			return fmt.Errorf("unable to read generated CRDs: %w", err)
+		}
+		options.CRDs = append(options.CRDs, generated...)
+	}
+
 	if len(options.Paths) > 0 {
 		crdList, err := renderCRDs(options)
 		if err != nil {
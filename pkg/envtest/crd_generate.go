@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/internal/testing/process"
+)
+
+// CRDGenerateOptions are the options for generating CRDs from Go type
+// definitions using controller-gen.
+type CRDGenerateOptions struct {
+	// Paths are the Go packages to generate CRDs for, in the form accepted
+	// by controller-gen, e.g. "./api/...". Relative paths are resolved
+	// against Dir.
+	Paths []string
+
+	// Dir is the directory controller-gen is run in. It must be inside the
+	// Go module containing Paths. Defaults to the current working directory,
+	// which is the package directory when running go test.
+	Dir string
+
+	// Generator is the controller-gen generator and its arguments, e.g.
+	// "crd:allowDangerousTypes=true". Defaults to "crd".
+	Generator string
+
+	// ControllerGenPath is the path to the controller-gen binary. Defaults to
+	// the TEST_ASSET_CONTROLLER_GEN environment variable, then to a
+	// controller-gen binary in KUBEBUILDER_ASSETS or /usr/local/kubebuilder/bin,
+	// then to controller-gen on the PATH.
+	ControllerGenPath string
+}
+
+// generateCRDs runs controller-gen to write the CRDs for options.Paths to dir.
+func generateCRDs(options CRDGenerateOptions, dir string) error {
+	if len(options.Paths) == 0 {
+		return fmt.Errorf("must specify Paths to generate CRDs for")
+	}
+
+	binary, err := controllerGenPath(options)
+	if err != nil {
+		return err
+	}
+
+	generator := options.Generator
+	if generator == "" {
+		generator = "crd"
+	}
+
+	args := []string{generator}
+	for _, path := range options.Paths {
+		args = append(args, "paths="+path)
+	}
+	args = append(args, "output:crd:dir="+dir)
+
+	log.V(1).Info("generating CRDs", "controller-gen", binary, "args", args)
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = options.Dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unable to generate CRDs with %s %s: %w: %s", binary, strings.Join(args, " "), err, output.String())
+	}
+	return nil
+}
+
+func controllerGenPath(options CRDGenerateOptions) (string, error) {
+	if options.ControllerGenPath != "" {
+		return options.ControllerGenPath, nil
+	}
+	if path := process.BinPathFinder("controller-gen", ""); fileExists(path) {
+		return path, nil
+	}
+	path, err := exec.LookPath("controller-gen")
+	if err != nil {
+		return "", fmt.Errorf("unable to find controller-gen, set ControllerGenPath or TEST_ASSET_CONTROLLER_GEN: %w", err)
+	}
+	return path, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
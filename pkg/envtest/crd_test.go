@@ -17,6 +17,9 @@ limitations under the License.
 package envtest
 
 import (
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -48,4 +51,40 @@ var _ = Describe("Test", func() {
 			Expect(expectedCRDs).To(Equal(foundCRDs))
 		})
 	})
+
+	Describe("readCRDFiles with Generate", func() {
+		It("should install the CRDs written by controller-gen", func() {
+			dir := GinkgoT().TempDir()
+			fakeControllerGen := filepath.Join(dir, "controller-gen")
+			script := `#!/bin/sh
+for arg in "$@"; do
+	case "$arg" in
+		output:crd:dir=*) out="${arg#output:crd:dir=}" ;;
+	esac
+done
+cp testdata/crds/examplecrd3.yaml "$out/"
+`
+			Expect(os.WriteFile(fakeControllerGen, []byte(script), 0o755)).To(Succeed())
+
+			opt := CRDInstallOptions{
+				Generate: &CRDGenerateOptions{
+					Paths:             []string{"./testdata/..."},
+					ControllerGenPath: fakeControllerGen,
+				},
+			}
+			Expect(readCRDFiles(&opt)).To(Succeed())
+			Expect(opt.CRDs).To(HaveLen(1))
+			Expect(opt.CRDs[0].Name).To(Equal("configs.foo.example.com"))
+		})
+
+		It("should return the controller-gen output on failure", func() {
+			opt := CRDInstallOptions{
+				Generate: &CRDGenerateOptions{
+					Paths:             []string{"./testdata/..."},
+					ControllerGenPath: "false",
+				},
+			}
+			Expect(readCRDFiles(&opt)).To(MatchError(ContainSubstring("unable to generate CRDs")))
+		})
+	})
 })
@@ -19,7 +19,9 @@ limitations under the License.
 // Control plane binaries (etcd and kube-apiserver) are loaded by default from
 // /usr/local/kubebuilder/bin.  This can be overridden by setting the
 // KUBEBUILDER_ASSETS environment variable, or by directly creating a
-// ControlPlane for the Environment to use.
+// ControlPlane for the Environment to use. Setting DownloadBinaryAssets
+// makes the Environment download and cache them instead, see
+// DownloadBinaryAssets.
 //
 // Environment can also be configured to work with an existing cluster, and
 // simply load CRDs and provide client configuration.
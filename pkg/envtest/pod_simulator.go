@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodPhasePolicy decides the phase a simulated pod should be in.
+type PodPhasePolicy func(pod *corev1.Pod) corev1.PodPhase
+
+// AlwaysPhase returns a PodPhasePolicy that puts every pod in the given phase.
+func AlwaysPhase(phase corev1.PodPhase) PodPhasePolicy {
+	return func(*corev1.Pod) corev1.PodPhase {
+		return phase
+	}
+}
+
+// SucceedAfter returns a PodPhasePolicy that keeps pods Running for the
+// given duration after they started, and then marks them Succeeded.
+func SucceedAfter(d time.Duration) PodPhasePolicy {
+	return func(pod *corev1.Pod) corev1.PodPhase {
+		if pod.Status.StartTime != nil && time.Since(pod.Status.StartTime.Time) >= d {
+			return corev1.PodSucceeded
+		}
+		return corev1.PodRunning
+	}
+}
+
+// PodSimulator simulates a node and its kubelet on a test control plane,
+// which doesn't run a scheduler or any kubelet. It binds unscheduled pods
+// to its node, moves them through their lifecycle according to Policy, and
+// finalizes the deletion of terminating pods. It allows integration testing
+// controllers that wait for pods to reach a phase.
+//
+// No containers are actually run.
+type PodSimulator struct {
+	// Client is used to read and write pods and nodes. Required.
+	Client client.Client
+
+	// NodeName is the name of the simulated node, which is created if it
+	// doesn't exist. Defaults to "envtest-node".
+	NodeName string
+
+	// Namespace restricts the simulator to pods in the given namespace.
+	// Defaults to all namespaces.
+	Namespace string
+
+	// Policy decides the phase of the pods bound to the simulated node.
+	// Defaults to AlwaysPhase(corev1.PodRunning).
+	Policy PodPhasePolicy
+
+	// Interval is the interval at which pods are simulated.
+	// Defaults to 100 milliseconds.
+	Interval time.Duration
+}
+
+// Start runs the simulator until ctx is done.
+func (s *PodSimulator) Start(ctx context.Context) error {
+	if s.Client == nil {
+		return fmt.Errorf("must specify Client for PodSimulator")
+	}
+	if s.NodeName == "" {
+		s.NodeName = "envtest-node"
+	}
+	if s.Policy == nil {
+		s.Policy = AlwaysPhase(corev1.PodRunning)
+	}
+	if s.Interval == 0 {
+		s.Interval = 100 * time.Millisecond
+	}
+
+	if err := s.ensureNode(ctx); err != nil {
+		return err
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.simulate(ctx); err != nil && ctx.Err() == nil {
+			log.Error(err, "failed to simulate pods", "node", s.NodeName)
+		}
+	}, s.Interval)
+	return nil
+}
+
+func (s *PodSimulator) ensureNode(ctx context.Context) error {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: s.NodeName}}
+	if err := s.Client.Create(ctx, node); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create simulated node %s: %w", s.NodeName, err)
+	}
+	if err := s.Client.Get(ctx, client.ObjectKeyFromObject(node), node); err != nil {
+		return fmt.Errorf("unable to get simulated node %s: %w", s.NodeName, err)
+	}
+
+	node.Status.Conditions = []corev1.NodeCondition{{
+		Type:               corev1.NodeReady,
+		Status:             corev1.ConditionTrue,
+		Reason:             "KubeletReady",
+		LastHeartbeatTime:  metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+	}}
+	if err := s.Client.Status().Update(ctx, node); err != nil {
+		return fmt.Errorf("unable to mark simulated node %s ready: %w", s.NodeName, err)
+	}
+	return nil
+}
+
+func (s *PodSimulator) simulate(ctx context.Context) error {
+	pods := &corev1.PodList{}
+	if err := s.Client.List(ctx, pods, client.InNamespace(s.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		var err error
+		switch {
+		case pod.DeletionTimestamp != nil:
+			if pod.Spec.NodeName == s.NodeName || pod.Spec.NodeName == "" {
+				err = client.IgnoreNotFound(s.Client.Delete(ctx, pod, client.GracePeriodSeconds(0)))
+			}
+		case pod.Spec.NodeName == "":
+			err = s.bind(ctx, pod)
+		case pod.Spec.NodeName == s.NodeName:
+			err = s.updatePhase(ctx, pod)
+		}
+		if err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to simulate pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *PodSimulator) bind(ctx context.Context, pod *corev1.Pod) error {
+	binding := &corev1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		Target:     corev1.ObjectReference{Kind: "Node", Name: s.NodeName},
+	}
+	return s.Client.SubResource("binding").Create(ctx, pod, binding)
+}
+
+func (s *PodSimulator) updatePhase(ctx context.Context, pod *corev1.Pod) error {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil
+	}
+
+	now := metav1.Now()
+	if pod.Status.StartTime == nil {
+		pod.Status.StartTime = &now
+	}
+	phase := s.Policy(pod)
+	if phase == pod.Status.Phase {
+		return nil
+	}
+
+	pod.Status.Phase = phase
+	ready := corev1.ConditionFalse
+	if phase == corev1.PodRunning {
+		ready = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		{Type: corev1.ContainersReady, Status: ready, LastTransitionTime: now},
+		{Type: corev1.PodReady, Status: ready, LastTransitionTime: now},
+	}
+
+	pod.Status.ContainerStatuses = nil
+	for _, container := range pod.Spec.Containers {
+		status := corev1.ContainerStatus{
+			Name:  container.Name,
+			Image: container.Image,
+			Ready: phase == corev1.PodRunning,
+		}
+		switch phase {
+		case corev1.PodRunning:
+			status.State.Running = &corev1.ContainerStateRunning{StartedAt: *pod.Status.StartTime}
+		case corev1.PodSucceeded, corev1.PodFailed:
+			exitCode := int32(0)
+			if phase == corev1.PodFailed {
+				exitCode = 1
+			}
+			status.State.Terminated = &corev1.ContainerStateTerminated{
+				ExitCode:   exitCode,
+				StartedAt:  *pod.Status.StartTime,
+				FinishedAt: now,
+			}
+		default:
+			status.State.Waiting = &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}
+		}
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
+	}
+
+	return s.Client.Status().Update(ctx, pod)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PodSimulator", func() {
+	It("should schedule pods and move them through their lifecycle", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c, err := client.New(env.Config, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		sim := &PodSimulator{
+			Client:    c,
+			Namespace: "default",
+			Policy:    SucceedAfter(time.Second),
+		}
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(sim.Start(ctx)).To(Succeed())
+		}()
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "simulated", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "busybox"}},
+			},
+		}
+		Expect(c.Create(ctx, pod)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+			g.Expect(pod.Spec.NodeName).To(Equal("envtest-node"))
+			g.Expect(pod.Status.Phase).To(Equal(corev1.PodRunning))
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+			g.Expect(pod.Status.Phase).To(Equal(corev1.PodSucceeded))
+		}).Should(Succeed())
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+})
@@ -0,0 +1,471 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/internal/flock"
+	"sigs.k8s.io/controller-runtime/pkg/internal/testing/controlplane"
+)
+
+// leaseIdentity is the name of the ServiceAccount, Role and RoleBinding
+// giving a Lease access to its namespace.
+const leaseIdentity = "envtest"
+
+// Pool shares a single Environment between tests that run in parallel,
+// isolating them from each other by giving every test its own namespace
+// and an identity that can only access it. This avoids paying the control
+// plane startup cost once per test.
+//
+// Within a process, the Environment is started when the first Lease is
+// acquired and stopped when the last Lease is released. Processes, e.g. the
+// test binaries of different packages or parallel Ginkgo processes, share
+// it too when their Pools use the same Dir.
+type Pool struct {
+	// Environment is the environment shared by the pool.
+	Environment *Environment
+
+	// NamespacePrefix is the prefix of the namespaces created for leases.
+	// Defaults to "envtest-".
+	NamespacePrefix string
+
+	// Dir is the directory through which processes share the Environment.
+	// The first process acquiring a Lease starts the Environment, and
+	// others use it until they released all their Leases. The process that
+	// started the Environment waits for them in its last Release before
+	// stopping it. Processes that exited without releasing their Leases are
+	// ignored.
+	//
+	// Defaults to sharing the Environment within the process only. Sharing
+	// between processes is only supported on unix systems.
+	Dir string
+
+	// PollInterval is how often the process that started the Environment
+	// checks whether other processes still use it. Defaults to 1 second.
+	PollInterval time.Duration
+
+	mu     sync.Mutex
+	leases int
+	config *rest.Config
+	client client.Client
+
+	// started is whether this process started the Environment.
+	started bool
+	// holder is the file locked by this process while it uses the
+	// Environment shared through Dir.
+	holder        string
+	releaseHolder func() error
+}
+
+// Lease is a namespace-scoped share of a pooled Environment.
+type Lease struct {
+	// Config can be used to talk to the apiserver as an identity that has
+	// full access to the lease's namespace, and nothing else.
+	Config *rest.Config
+
+	// AdminConfig can be used to talk to the apiserver with full access,
+	// e.g. to install CRDs. Objects created with it outside of the lease's
+	// namespace are not cleaned up.
+	AdminConfig *rest.Config
+
+	// Namespace is the namespace created for this lease. Tests using the
+	// lease should only create namespaced objects in it.
+	Namespace string
+
+	pool     *Pool
+	released bool
+}
+
+// Acquire returns a new Lease on the pool's Environment, starting the
+// Environment if no other Lease is held.
+func (p *Pool) Acquire(ctx context.Context) (*Lease, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Environment == nil {
+		return nil, fmt.Errorf("must specify Environment for Pool")
+	}
+
+	if p.leases == 0 {
+		if err := p.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	lease, err := p.newLease(ctx)
+	if err != nil {
+		if p.leases == 0 {
+			if stopErr := p.stop(ctx); stopErr != nil {
+				log.Error(stopErr, "unable to stop pooled environment")
+			}
+		}
+		return nil, err
+	}
+	p.leases++
+	return lease, nil
+}
+
+// Release deletes the objects in the lease's namespace and the namespace
+// itself, and stops the pool's Environment if this was the last Lease held.
+// Releasing a Lease more than once is a no-op.
+func (l *Lease) Release(ctx context.Context) error {
+	p := l.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l.released {
+		return nil
+	}
+	l.released = true
+	p.leases--
+
+	err := p.clean(ctx, l.Namespace)
+	if p.leases == 0 {
+		err = errors.Join(err, p.stop(ctx))
+	}
+	return err
+}
+
+// newLease creates a namespace and an identity for a new Lease.
+func (p *Pool) newLease(ctx context.Context) (*Lease, error) {
+	prefix := p.NamespacePrefix
+	if prefix == "" {
+		prefix = "envtest-"
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: prefix}}
+	if err := p.client.Create(ctx, ns); err != nil {
+		return nil, fmt.Errorf("unable to create namespace for lease: %w", err)
+	}
+
+	config, err := p.identity(ctx, ns.Name)
+	if err != nil {
+		if cleanErr := p.clean(ctx, ns.Name); cleanErr != nil {
+			log.Error(cleanErr, "unable to clean up namespace", "namespace", ns.Name)
+		}
+		return nil, fmt.Errorf("unable to create identity for lease: %w", err)
+	}
+
+	return &Lease{
+		Config:      config,
+		AdminConfig: rest.CopyConfig(p.config),
+		Namespace:   ns.Name,
+		pool:        p,
+	}, nil
+}
+
+// identity creates a ServiceAccount with full access to the given namespace,
+// and returns a config authenticating as it.
+func (p *Pool) identity(ctx context.Context, namespace string) (*rest.Config, error) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: leaseIdentity}}
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: leaseIdentity},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: leaseIdentity},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: leaseIdentity},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: namespace, Name: leaseIdentity}},
+	}
+	for _, obj := range []client.Object{sa, role, binding} {
+		if err := p.client.Create(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To(int64((24 * time.Hour) / time.Second))},
+	}
+	if err := p.client.SubResource("token").Create(ctx, sa, request); err != nil {
+		return nil, err
+	}
+
+	config := rest.AnonymousClientConfig(p.config)
+	config.BearerToken = request.Status.Token
+	return config, nil
+}
+
+// clean deletes the objects in the given namespace and the namespace
+// itself. The test control plane runs neither the namespace controller nor
+// the garbage collector, so objects are deleted explicitly and their
+// finalizers removed.
+func (p *Pool) clean(ctx context.Context, namespace string) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(p.config)
+	if err != nil {
+		return err
+	}
+	resources, err := discoveryClient.ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return fmt.Errorf("unable to discover resources to clean up in namespace %s: %w", namespace, err)
+	}
+
+	var errs []error
+	for _, list := range resources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if !slices.Contains(resource.Verbs, "deletecollection") || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			if err := p.deleteAll(ctx, namespace, gv.WithKind(resource.Kind)); err != nil {
+				errs = append(errs, fmt.Errorf("unable to delete %s in namespace %s: %w", resource.Name, namespace, err))
+			}
+		}
+	}
+
+	ns := &corev1.Namespace{}
+	if err := p.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+	if err := p.client.Delete(ctx, ns); err != nil && !apierrors.IsNotFound(err) {
+		errs = append(errs, fmt.Errorf("unable to delete namespace %s: %w", namespace, err))
+	}
+	// Finalize the namespace, as the namespace controller would once it is
+	// empty.
+	ns.Spec.Finalizers = nil
+	if err := p.client.SubResource("finalize").Update(ctx, ns); err != nil && !apierrors.IsNotFound(err) {
+		errs = append(errs, fmt.Errorf("unable to finalize namespace %s: %w", namespace, err))
+	}
+	return errors.Join(errs...)
+}
+
+// deleteAll deletes all the objects of the given kind in the given
+// namespace, removing the finalizers that would keep them around.
+func (p *Pool) deleteAll(ctx context.Context, namespace string, gvk schema.GroupVersionKind) error {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	if err := p.client.DeleteAllOf(ctx, obj, client.InNamespace(namespace), client.GracePeriodSeconds(0)); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	remaining := &metav1.PartialObjectMetadataList{}
+	remaining.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := p.client.List(ctx, remaining, client.InNamespace(namespace)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	for i := range remaining.Items {
+		item := &remaining.Items[i]
+		if len(item.Finalizers) == 0 {
+			continue
+		}
+		patch := client.MergeFrom(item.DeepCopy())
+		item.Finalizers = nil
+		if err := p.client.Patch(ctx, item, patch); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// start starts the Environment, or joins the one shared through Dir.
+func (p *Pool) start() error {
+	if p.Dir == "" {
+		config, err := p.Environment.Start()
+		if err != nil {
+			return fmt.Errorf("unable to start pooled environment: %w", err)
+		}
+		p.config, p.started = config, true
+	} else if err := p.join(); err != nil {
+		return err
+	}
+
+	// The pool's own client uses the default scheme, which knows the types
+	// it manages whatever the Environment's scheme.
+	var err error
+	p.client, err = client.New(p.config, client.Options{})
+	if err != nil {
+		return errors.Join(fmt.Errorf("unable to create client for pooled environment: %w", err), p.stop(context.Background()))
+	}
+	return nil
+}
+
+// join starts the Environment shared through Dir if no other process runs
+// it, or uses the one that does, and registers this process as using it.
+func (p *Pool) join() error {
+	holders := filepath.Join(p.Dir, "holders")
+	if err := os.MkdirAll(holders, 0o750); err != nil {
+		return fmt.Errorf("unable to create pool directory: %w", err)
+	}
+	unlock, err := flock.Lock(filepath.Join(p.Dir, "lock"), true)
+	if err != nil {
+		return fmt.Errorf("unable to lock pool directory: %w", err)
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			log.Error(err, "unable to unlock pool directory")
+		}
+	}()
+
+	others, err := p.otherHolders()
+	if err != nil {
+		return err
+	}
+	kubeconfig := filepath.Join(p.Dir, "kubeconfig")
+	if others > 0 {
+		contents, err := os.ReadFile(kubeconfig)
+		if err != nil {
+			return fmt.Errorf("unable to read kubeconfig of shared environment: %w", err)
+		}
+		if p.config, err = clientcmd.RESTConfigFromKubeConfig(contents); err != nil {
+			return fmt.Errorf("unable to load kubeconfig of shared environment: %w", err)
+		}
+	} else {
+		config, err := p.Environment.Start()
+		if err != nil {
+			return fmt.Errorf("unable to start pooled environment: %w", err)
+		}
+		p.config, p.started = config, true
+		contents, err := controlplane.KubeConfigFromREST(config)
+		if err == nil {
+			err = os.WriteFile(kubeconfig, contents, 0o600)
+		}
+		if err != nil {
+			return errors.Join(fmt.Errorf("unable to write kubeconfig of shared environment: %w", err), p.Environment.Stop())
+		}
+	}
+
+	holder, err := os.CreateTemp(holders, "holder-")
+	if err == nil {
+		err = holder.Close()
+	}
+	if err == nil {
+		p.holder = holder.Name()
+		p.releaseHolder, err = flock.Lock(p.holder, false)
+	}
+	if err != nil {
+		err = fmt.Errorf("unable to register as holder of shared environment: %w", err)
+		if p.started {
+			err = errors.Join(err, os.Remove(kubeconfig), p.Environment.Stop())
+		}
+		return err
+	}
+	return nil
+}
+
+// otherHolders returns how many other processes use the Environment shared
+// through Dir, removing the holders of processes that exited. Holders are
+// locked by the processes using the Environment, so the lock of a holder
+// that can be acquired was released when its process exited. It must be
+// called with the pool directory locked.
+func (p *Pool) otherHolders() (int, error) {
+	holders := filepath.Join(p.Dir, "holders")
+	entries, err := os.ReadDir(holders)
+	if err != nil {
+		return 0, fmt.Errorf("unable to list holders of shared environment: %w", err)
+	}
+
+	others := 0
+	for _, entry := range entries {
+		path := filepath.Join(holders, entry.Name())
+		if path == p.holder {
+			continue
+		}
+		release, err := flock.Lock(path, false)
+		if errors.Is(err, flock.ErrAlreadyLocked) {
+			others++
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err := errors.Join(os.Remove(path), release()); err != nil {
+			return 0, err
+		}
+	}
+	return others, nil
+}
+
+// stop stops the Environment if this process started it, waiting for the
+// other processes using it to leave, and unregisters this process.
+func (p *Pool) stop(ctx context.Context) error {
+	if p.Dir == "" {
+		return p.Environment.Stop()
+	}
+	if !p.started {
+		return p.leave()
+	}
+
+	interval := p.PollInterval
+	if interval == 0 {
+		interval = time.Second
+	}
+	unlocked := func() (bool, error) {
+		unlock, err := flock.Lock(filepath.Join(p.Dir, "lock"), true)
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			if err := unlock(); err != nil {
+				log.Error(err, "unable to unlock pool directory")
+			}
+		}()
+
+		if others, err := p.otherHolders(); err != nil || others > 0 {
+			return false, err
+		}
+		// Processes joining from now on start their own Environment.
+		return true, errors.Join(os.Remove(filepath.Join(p.Dir, "kubeconfig")), p.leave())
+	}
+	err := wait.PollUntilContextCancel(ctx, interval, true, func(context.Context) (bool, error) {
+		return unlocked()
+	})
+	if err != nil {
+		err = errors.Join(
+			fmt.Errorf("stopped pooled environment while other processes may still use it: %w", err),
+			os.Remove(filepath.Join(p.Dir, "kubeconfig")),
+			p.leave(),
+		)
+	}
+	p.started = false
+	return errors.Join(err, p.Environment.Stop())
+}
+
+// leave unregisters this process as using the Environment shared through Dir.
+func (p *Pool) leave() error {
+	if p.holder == "" {
+		return nil
+	}
+	err := errors.Join(os.Remove(p.holder), p.releaseHolder())
+	p.holder, p.releaseHolder = "", nil
+	return err
+}
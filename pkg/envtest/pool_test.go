@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Pool", func() {
+	newPool := func(dir string) *Pool {
+		return &Pool{
+			Environment: &Environment{
+				UseExistingCluster: ptr.To(true),
+				Config:             env.Config,
+			},
+			NamespacePrefix: "pool-test-",
+			Dir:             dir,
+			PollInterval:    10 * time.Millisecond,
+		}
+	}
+
+	It("should hand out leases with distinct namespaces on a shared environment", func() {
+		ctx := context.Background()
+		pool := newPool("")
+
+		first, err := pool.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := pool.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Namespace).To(HavePrefix("pool-test-"))
+		Expect(second.Namespace).NotTo(Equal(first.Namespace))
+
+		admin, err := client.New(first.AdminConfig, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(admin.Get(ctx, client.ObjectKey{Name: second.Namespace}, &corev1.Namespace{})).To(Succeed())
+
+		Expect(first.Release(ctx)).To(Succeed())
+		Expect(first.Release(ctx)).To(Succeed())
+		Expect(second.Release(ctx)).To(Succeed())
+	})
+
+	It("should restrict leases to their namespace and clean it up on release", func() {
+		ctx := context.Background()
+		pool := newPool("")
+
+		first, err := pool.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := pool.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			Expect(second.Release(ctx)).To(Succeed())
+		}()
+
+		c, err := client.New(first.Config, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace:  first.Namespace,
+			Name:       "finalized",
+			Finalizers: []string{"example.com/finalizer"},
+		}}
+		Expect(c.Create(ctx, cm)).To(Succeed())
+		err = c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: second.Namespace, Name: "other"}})
+		Expect(apierrors.IsForbidden(err)).To(BeTrue(), "expected forbidden error, got %v", err)
+
+		Expect(first.Release(ctx)).To(Succeed())
+
+		admin, err := client.New(second.AdminConfig, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		err = admin.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found error, got %v", err)
+		err = admin.Get(ctx, client.ObjectKey{Name: first.Namespace}, &corev1.Namespace{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found error, got %v", err)
+	})
+
+	It("should share the environment between pools using the same directory", func() {
+		ctx := context.Background()
+		dir := GinkgoT().TempDir()
+		starter, joiner := newPool(dir), newPool(dir)
+
+		first, err := starter.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(starter.started).To(BeTrue())
+		second, err := joiner.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joiner.started).To(BeFalse())
+		Expect(second.AdminConfig.Host).To(Equal(first.AdminConfig.Host))
+
+		released := make(chan error)
+		go func() {
+			released <- first.Release(ctx)
+		}()
+		Consistently(released, 100*time.Millisecond).ShouldNot(Receive())
+
+		Expect(second.Release(ctx)).To(Succeed())
+		Eventually(released).Should(Receive(BeNil()))
+		Expect(filepath.Join(dir, "kubeconfig")).NotTo(BeAnExistingFile())
+
+		By("starting the environment again once it was stopped")
+		third, err := joiner.Acquire(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joiner.started).To(BeTrue())
+		Expect(third.Release(ctx)).To(Succeed())
+	})
+})
@@ -151,6 +151,22 @@ type Environment struct {
 	// located in the local environment. This field can be overridden by setting KUBEBUILDER_ASSETS.
 	BinaryAssetsDirectory string
 
+	// DownloadBinaryAssets makes Start download the binaries of
+	// DownloadBinaryAssetsVersion from DownloadBinaryAssetsIndexURL if they
+	// are not cached in BinaryAssetsDirectory yet, instead of expecting them
+	// to be installed, e.g. by setup-envtest. BinaryAssetsDirectory is then
+	// the cache directory, see BinaryAssetsOptions.Directory.
+	DownloadBinaryAssets bool
+
+	// DownloadBinaryAssetsVersion is the Kubernetes version of the binaries
+	// downloaded, see BinaryAssetsOptions.Version. Defaults to the latest
+	// release.
+	DownloadBinaryAssetsVersion string
+
+	// DownloadBinaryAssetsIndexURL is the index of the releases of the
+	// binaries. Defaults to DefaultBinaryAssetsIndexURL.
+	DownloadBinaryAssetsIndexURL string
+
 	// UseExistingCluster indicates that this environments should use an
 	// existing kubeconfig, instead of trying to stand up a new control plane.
 	// This is useful in cases that need aggregated API servers and the like.
@@ -233,9 +249,22 @@ func (te *Environment) Start() (*rest.Config, error) {
 			}
 		}
 
-		apiServer.Path = process.BinPathFinder("kube-apiserver", te.BinaryAssetsDirectory)
-		te.ControlPlane.Etcd.Path = process.BinPathFinder("etcd", te.BinaryAssetsDirectory)
-		te.ControlPlane.KubectlPath = process.BinPathFinder("kubectl", te.BinaryAssetsDirectory)
+		binaryAssetsDirectory := te.BinaryAssetsDirectory
+		if te.DownloadBinaryAssets {
+			dir, err := DownloadBinaryAssets(context.TODO(), BinaryAssetsOptions{
+				Version:   te.DownloadBinaryAssetsVersion,
+				IndexURL:  te.DownloadBinaryAssetsIndexURL,
+				Directory: te.BinaryAssetsDirectory,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to download binary assets: %w", err)
+			}
+			binaryAssetsDirectory = dir
+		}
+
+		apiServer.Path = process.BinPathFinder("kube-apiserver", binaryAssetsDirectory)
+		te.ControlPlane.Etcd.Path = process.BinPathFinder("etcd", binaryAssetsDirectory)
+		te.ControlPlane.KubectlPath = process.BinPathFinder("kubectl", binaryAssetsDirectory)
 
 		if err := te.defaultTimeouts(); err != nil {
 			return nil, fmt.Errorf("failed to default controlplane timeouts: %w", err)
@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package featuregate lets controllers declare feature gates that toggle
+// their behaviors per deployment, using the --feature-gates flag syntax of
+// Kubernetes components:
+//
+//	const SmartRollouts featuregate.Feature = "SmartRollouts"
+//
+//	gates := featuregate.New()
+//	if err := gates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
+//		SmartRollouts: {Default: false, Stage: featuregate.Alpha},
+//	}); err != nil { ... }
+//	gates.AddFlag(flag.CommandLine)
+//	flag.Parse()
+//
+//	mgr, err := manager.New(cfg, manager.Options{FeatureGates: gates})
+//	...
+//	if gates.Enabled(SmartRollouts) { ... }
+//
+// The state of the gates is exported in the controller_runtime_feature_enabled
+// metric, and served as JSON by the metrics server of a Manager configured
+// with them.
+package featuregate
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// FlagName is the name of the flag registered by AddFlag.
+const FlagName = "feature-gates"
+
+// Feature is the name of a feature gate.
+type Feature string
+
+// Stage is the maturity of a feature.
+type Stage string
+
+const (
+	// Alpha features are experimental and disabled by default.
+	Alpha = Stage("ALPHA")
+	// Beta features are well tested and usually enabled by default.
+	Beta = Stage("BETA")
+	// GA features are generally available, and their gate will be removed.
+	GA = Stage("GA")
+	// Deprecated features will be removed.
+	Deprecated = Stage("DEPRECATED")
+)
+
+// FeatureSpec describes a feature gate.
+type FeatureSpec struct {
+	// Default is whether the feature is enabled when its gate isn't set.
+	Default bool
+
+	// Stage is the maturity of the feature. Defaults to Alpha.
+	Stage Stage
+
+	// LockToDefault prevents the gate from being set to another value than
+	// Default, typically for GA features.
+	LockToDefault bool
+
+	// Description is shown in the help of the flag and the introspection
+	// endpoint.
+	Description string
+}
+
+var enabledGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "controller_runtime_feature_enabled",
+	Help: "Whether a feature gate is enabled (1) or disabled (0)",
+}, []string{"name", "stage"})
+
+func init() {
+	metrics.Registry.MustRegister(enabledGauge)
+}
+
+// Gates is a set of feature gates. It is safe for concurrent use, and
+// implements flag.Value so that it can be set from a flag, and http.Handler
+// to serve the state of the gates as JSON.
+type Gates struct {
+	mu      sync.RWMutex
+	known   map[Feature]FeatureSpec
+	enabled map[Feature]bool
+}
+
+// New returns an empty set of feature gates.
+func New() *Gates {
+	return &Gates{
+		known:   map[Feature]FeatureSpec{},
+		enabled: map[Feature]bool{},
+	}
+}
+
+// Add declares features. Declaring a feature again with the same spec is a
+// no-op, while declaring it with a different spec is an error.
+func (g *Gates) Add(features map[Feature]FeatureSpec) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for name, spec := range features {
+		if spec.Stage == "" {
+			spec.Stage = Alpha
+		}
+		if existing, ok := g.known[name]; ok {
+			if existing != spec {
+				return fmt.Errorf("feature gate %q already declared with a different spec", name)
+			}
+			continue
+		}
+		g.known[name] = spec
+	}
+	for name := range features {
+		g.updateMetric(name)
+	}
+	return nil
+}
+
+// Enabled returns whether the feature is enabled. Undeclared features are
+// disabled.
+func (g *Gates) Enabled(f Feature) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.enabledLocked(f)
+}
+
+func (g *Gates) enabledLocked(f Feature) bool {
+	if enabled, ok := g.enabled[f]; ok {
+		return enabled
+	}
+	return g.known[f].Default
+}
+
+// IsSet returns whether the gate of the feature has been set explicitly,
+// rather than being at its default.
+func (g *Gates) IsSet(f Feature) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.enabled[f]
+	return ok
+}
+
+// Set sets gates from a comma-separated list of Feature=bool pairs, e.g.
+// "A=true,B=false", as passed to the --feature-gates flag. It implements
+// flag.Value.
+func (g *Gates) Set(value string) error {
+	m := map[string]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("missing bool value for feature gate %q", pair)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %s=%s: %w", name, raw, err)
+		}
+		m[strings.TrimSpace(name)] = enabled
+	}
+	return g.SetFromMap(m)
+}
+
+// SetFromMap sets gates from a map of feature names to values, e.g. read
+// from a configuration file. Unknown features, and features locked to their
+// default, can't be set. No gate is set if any of them is invalid.
+func (g *Gates) SetFromMap(m map[string]bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for name, enabled := range m {
+		spec, ok := g.known[Feature(name)]
+		if !ok {
+			return fmt.Errorf("unrecognized feature gate %q", name)
+		}
+		if spec.LockToDefault && enabled != spec.Default {
+			return fmt.Errorf("feature gate %q is locked to %t", name, spec.Default)
+		}
+	}
+	for name, enabled := range m {
+		g.enabled[Feature(name)] = enabled
+		g.updateMetric(Feature(name))
+	}
+	return nil
+}
+
+// String returns the gates that have been set, in the format accepted by
+// Set. It implements flag.Value.
+func (g *Gates) String() string {
+	if g == nil {
+		return ""
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	pairs := make([]string, 0, len(g.enabled))
+	for name, enabled := range g.enabled {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Type returns the type of the flag value, for compatibility with
+// github.com/spf13/pflag.
+func (g *Gates) Type() string {
+	return "mapStringBool"
+}
+
+// AddFlag registers the --feature-gates flag on fs. Features must be
+// declared before, so that they are listed in the help of the flag.
+func (g *Gates) AddFlag(fs *flag.FlagSet) {
+	fs.Var(g, FlagName, "A set of key=value pairs that describe feature gates for alpha/experimental features. Options are:\n"+strings.Join(g.knownFeatures(), "\n"))
+}
+
+func (g *Gates) knownFeatures() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	known := make([]string, 0, len(g.known))
+	for name, spec := range g.known {
+		line := fmt.Sprintf("%s=true|false (%s - default=%t)", name, spec.Stage, spec.Default)
+		if spec.Description != "" {
+			line += ": " + spec.Description
+		}
+		known = append(known, line)
+	}
+	sort.Strings(known)
+	return known
+}
+
+// FeatureStatus is the state of a feature gate.
+type FeatureStatus struct {
+	Name        Feature `json:"name"`
+	Enabled     bool    `json:"enabled"`
+	Default     bool    `json:"default"`
+	Stage       Stage   `json:"stage"`
+	Locked      bool    `json:"locked,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// Status returns the state of all the declared features, sorted by name.
+func (g *Gates) Status() []FeatureStatus {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	status := make([]FeatureStatus, 0, len(g.known))
+	for name, spec := range g.known {
+		status = append(status, FeatureStatus{
+			Name:        name,
+			Enabled:     g.enabledLocked(name),
+			Default:     spec.Default,
+			Stage:       spec.Stage,
+			Locked:      spec.LockToDefault,
+			Description: spec.Description,
+		})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Name < status[j].Name })
+	return status
+}
+
+// ServeHTTP serves the status of the gates as JSON. It implements
+// http.Handler.
+func (g *Gates) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(g.Status()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (g *Gates) updateMetric(name Feature) {
+	value := 0.0
+	if g.enabledLocked(name) {
+		value = 1
+	}
+	enabledGauge.WithLabelValues(string(name), string(g.known[name].Stage)).Set(value)
+}
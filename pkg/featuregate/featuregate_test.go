@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregate
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const (
+	alphaFeature Feature = "AlphaFeature"
+	betaFeature  Feature = "BetaFeature"
+	gaFeature    Feature = "GAFeature"
+)
+
+func newTestGates(t *testing.T) *Gates {
+	t.Helper()
+	g := New()
+	if err := g.Add(map[Feature]FeatureSpec{
+		alphaFeature: {Default: false},
+		betaFeature:  {Default: true, Stage: Beta, Description: "A beta feature"},
+		gaFeature:    {Default: true, Stage: GA, LockToDefault: true},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return g
+}
+
+func TestGatesDefaults(t *testing.T) {
+	g := newTestGates(t)
+	if g.Enabled(alphaFeature) || !g.Enabled(betaFeature) || !g.Enabled(gaFeature) {
+		t.Errorf("expected gates to be at their defaults, got %+v", g.Status())
+	}
+	if g.Enabled("Unknown") {
+		t.Errorf("expected unknown features to be disabled")
+	}
+	if err := g.Add(map[Feature]FeatureSpec{alphaFeature: {Default: true}}); err == nil {
+		t.Errorf("expected redeclaring a feature with a different spec to fail")
+	}
+	if err := g.Add(map[Feature]FeatureSpec{alphaFeature: {Default: false}}); err != nil {
+		t.Errorf("unexpected error redeclaring a feature with the same spec: %v", err)
+	}
+}
+
+func TestGatesFlag(t *testing.T) {
+	g := newTestGates(t)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	g.AddFlag(fs)
+	if err := fs.Parse([]string{"--feature-gates=AlphaFeature=true, BetaFeature=false"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !g.Enabled(alphaFeature) || g.Enabled(betaFeature) {
+		t.Errorf("expected flag to toggle gates, got %+v", g.Status())
+	}
+	if !g.IsSet(alphaFeature) || g.IsSet(gaFeature) {
+		t.Errorf("expected only flagged gates to be set")
+	}
+	if got, want := g.String(), "AlphaFeature=true,BetaFeature=false"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	for _, value := range []string{"Unknown=true", "AlphaFeature", "AlphaFeature=maybe", "GAFeature=false"} {
+		if err := g.Set(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+	if err := g.Set("BetaFeature=true,GAFeature=false"); err == nil {
+		t.Errorf("expected setting a locked gate to fail")
+	}
+	if g.Enabled(betaFeature) {
+		t.Errorf("expected invalid values to leave all gates unchanged")
+	}
+}
+
+func TestGatesIntrospection(t *testing.T) {
+	g := newTestGates(t)
+	if err := g.Set("AlphaFeature=true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, httptest.NewRequest("GET", "/featuregates", nil))
+	var status []FeatureStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status) != 3 || status[0].Name != alphaFeature || !status[0].Enabled || status[0].Stage != Alpha {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if status[1].Description != "A beta feature" || !status[2].Locked {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if v := testutil.ToFloat64(enabledGauge.WithLabelValues(string(alphaFeature), string(Alpha))); v != 1 {
+		t.Errorf("expected metric of enabled feature to be 1, got %v", v)
+	}
+	if v := testutil.ToFloat64(enabledGauge.WithLabelValues(string(gaFeature), string(GA))); v != 1 {
+		t.Errorf("expected metric of default feature to be 1, got %v", v)
+	}
+}
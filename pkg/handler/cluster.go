@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ForCluster returns a handler enqueuing the requests of h as ClusterRequests
+// for the cluster named clusterName, for controllers reconciling the objects
+// of several clusters. Each source of such a controller watches a single
+// cluster, and is given a handler for its cluster:
+//
+//	src := source.Kind(mgr.GetCache(), &corev1.Pod{},
+//		handler.ForCluster[*corev1.Pod](name, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{}))
+func ForCluster[object any](clusterName string, h TypedEventHandler[object, reconcile.Request]) TypedEventHandler[object, reconcile.ClusterRequest] {
+	return &clusterHandler[object]{clusterName: clusterName, handler: h}
+}
+
+type clusterHandler[object any] struct {
+	clusterName string
+	handler     TypedEventHandler[object, reconcile.Request]
+}
+
+// Create implements TypedEventHandler.
+func (h *clusterHandler[object]) Create(ctx context.Context, e event.TypedCreateEvent[object], q workqueue.TypedRateLimitingInterface[reconcile.ClusterRequest]) {
+	h.handler.Create(ctx, e, h.queue(q))
+}
+
+// Update implements TypedEventHandler.
+func (h *clusterHandler[object]) Update(ctx context.Context, e event.TypedUpdateEvent[object], q workqueue.TypedRateLimitingInterface[reconcile.ClusterRequest]) {
+	h.handler.Update(ctx, e, h.queue(q))
+}
+
+// Delete implements TypedEventHandler.
+func (h *clusterHandler[object]) Delete(ctx context.Context, e event.TypedDeleteEvent[object], q workqueue.TypedRateLimitingInterface[reconcile.ClusterRequest]) {
+	h.handler.Delete(ctx, e, h.queue(q))
+}
+
+// Generic implements TypedEventHandler.
+func (h *clusterHandler[object]) Generic(ctx context.Context, e event.TypedGenericEvent[object], q workqueue.TypedRateLimitingInterface[reconcile.ClusterRequest]) {
+	h.handler.Generic(ctx, e, h.queue(q))
+}
+
+func (h *clusterHandler[object]) queue(q workqueue.TypedRateLimitingInterface[reconcile.ClusterRequest]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return &clusterQueue{clusterName: h.clusterName, queue: q}
+}
+
+// clusterQueue adds the requests added to it to the queue of ClusterRequests
+// it wraps, for the cluster named clusterName. Handlers only add requests,
+// so reading from it returns the requests of the wrapped queue regardless of
+// their cluster.
+type clusterQueue struct {
+	clusterName string
+	queue       workqueue.TypedRateLimitingInterface[reconcile.ClusterRequest]
+}
+
+func (q *clusterQueue) request(req reconcile.Request) reconcile.ClusterRequest {
+	return reconcile.ClusterRequest{ClusterName: q.clusterName, Request: req}
+}
+
+func (q *clusterQueue) Add(req reconcile.Request) { q.queue.Add(q.request(req)) }
+
+func (q *clusterQueue) AddAfter(req reconcile.Request, duration time.Duration) {
+	q.queue.AddAfter(q.request(req), duration)
+}
+
+func (q *clusterQueue) AddRateLimited(req reconcile.Request) { q.queue.AddRateLimited(q.request(req)) }
+
+func (q *clusterQueue) Forget(req reconcile.Request) { q.queue.Forget(q.request(req)) }
+
+func (q *clusterQueue) NumRequeues(req reconcile.Request) int {
+	return q.queue.NumRequeues(q.request(req))
+}
+
+func (q *clusterQueue) Done(req reconcile.Request) { q.queue.Done(q.request(req)) }
+
+func (q *clusterQueue) Get() (reconcile.Request, bool) {
+	req, shutdown := q.queue.Get()
+	return req.Request, shutdown
+}
+
+func (q *clusterQueue) Len() int { return q.queue.Len() }
+
+func (q *clusterQueue) ShutDown() { q.queue.ShutDown() }
+
+func (q *clusterQueue) ShutDownWithDrain() { q.queue.ShutDownWithDrain() }
+
+func (q *clusterQueue) ShuttingDown() bool { return q.queue.ShuttingDown() }
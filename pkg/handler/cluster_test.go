@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("ForCluster", func() {
+	It("should enqueue the requests of its handler for its cluster", func() {
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.ClusterRequest]())
+		defer q.ShutDown()
+		h := handler.ForCluster[*corev1.Pod]("east", &handler.TypedEnqueueRequestForObject[*corev1.Pod]{})
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+		h.Create(context.Background(), event.TypedCreateEvent[*corev1.Pod]{Object: pod}, q)
+
+		Expect(q.Len()).To(Equal(1))
+		req, _ := q.Get()
+		Expect(req).To(Equal(reconcile.ClusterRequest{
+			ClusterName: "east",
+			Request:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}},
+		}))
+		Expect(req.String()).To(Equal("east/default/foo"))
+	})
+})
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/lru"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultMapFuncCacheSize is the default number of objects whose requests
+// are memoized by a MapFuncCache.
+const DefaultMapFuncCacheSize = 1024
+
+// MapFuncCacheOptions configures a MapFuncCache.
+type MapFuncCacheOptions struct {
+	// Size is the maximum number of objects whose requests are memoized.
+	// The least recently used entries are evicted beyond it. Defaults to
+	// DefaultMapFuncCacheSize.
+	Size int
+
+	// TTL bounds how long the requests of an object are memoized. It should
+	// be set when the result of the map function depends on other objects
+	// than the one it is given, and Invalidate can't be called when those
+	// change. Defaults to zero, which memoizes results until the object
+	// changes.
+	TTL time.Duration
+}
+
+// MapFuncCache memoizes the results of a MapFunc.
+type MapFuncCache = TypedMapFuncCache[client.Object, reconcile.Request]
+
+// TypedMapFuncCache memoizes the results of a TypedMapFunc, keyed by the UID
+// and resourceVersion of the objects it is called with. Expensive map
+// functions, e.g. ones that list objects from the API server, are then only
+// called again when an object changes, rather than for every event of busy
+// resources. Objects without a UID or resourceVersion are always mapped.
+//
+// The result of a map function that depends on other objects than the one it
+// is given may become stale when those change; Invalidate and InvalidateAll
+// drop memoized results so that they are computed again.
+//
+// TypedMapFuncCache is experimental and subject to future change.
+type TypedMapFuncCache[object client.Object, request comparable] struct {
+	fn      TypedMapFunc[object, request]
+	ttl     time.Duration
+	entries *lru.Cache
+}
+
+type mapFuncCacheEntry[request comparable] struct {
+	resourceVersion string
+	requests        []request
+	expires         time.Time
+}
+
+// NewMapFuncCache returns a MapFuncCache memoizing the results of fn.
+func NewMapFuncCache(fn MapFunc, opts MapFuncCacheOptions) *MapFuncCache {
+	return NewTypedMapFuncCache(fn, opts)
+}
+
+// NewTypedMapFuncCache returns a TypedMapFuncCache memoizing the results of fn.
+//
+// NewTypedMapFuncCache is experimental and subject to future change.
+func NewTypedMapFuncCache[object client.Object, request comparable](fn TypedMapFunc[object, request], opts MapFuncCacheOptions) *TypedMapFuncCache[object, request] {
+	if opts.Size <= 0 {
+		opts.Size = DefaultMapFuncCacheSize
+	}
+	return &TypedMapFuncCache[object, request]{
+		fn:      fn,
+		ttl:     opts.TTL,
+		entries: lru.New(opts.Size),
+	}
+}
+
+// MapFunc returns the memoizing map function, to be passed to
+// EnqueueRequestsFromMapFunc or TypedEnqueueRequestsFromMapFunc.
+func (c *TypedMapFuncCache[object, request]) MapFunc() TypedMapFunc[object, request] {
+	return c.mapFunc
+}
+
+func (c *TypedMapFuncCache[object, request]) mapFunc(ctx context.Context, obj object) []request {
+	uid, resourceVersion := obj.GetUID(), obj.GetResourceVersion()
+	if uid == "" || resourceVersion == "" {
+		return c.fn(ctx, obj)
+	}
+
+	if cached, ok := c.entries.Get(uid); ok {
+		entry := cached.(*mapFuncCacheEntry[request])
+		if entry.resourceVersion == resourceVersion && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+			return slices.Clone(entry.requests)
+		}
+	}
+
+	reqs := c.fn(ctx, obj)
+	entry := &mapFuncCacheEntry[request]{
+		resourceVersion: resourceVersion,
+		requests:        slices.Clone(reqs),
+	}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+	c.entries.Add(uid, entry)
+	return reqs
+}
+
+// Invalidate drops the memoized requests of the object with the given UID.
+func (c *TypedMapFuncCache[object, request]) Invalidate(uid types.UID) {
+	c.entries.Remove(uid)
+}
+
+// InvalidateAll drops all the memoized requests, e.g. when an object the map
+// function depends on has changed.
+func (c *TypedMapFuncCache[object, request]) InvalidateAll() {
+	c.entries.Clear()
+}
+
+// Len returns the number of objects whose requests are memoized.
+func (c *TypedMapFuncCache[object, request]) Len() int {
+	return c.entries.Len()
+}
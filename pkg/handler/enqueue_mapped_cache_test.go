@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("MapFuncCache", func() {
+	var ctx = context.Background()
+	var calls int
+	var mapFunc handler.MapFunc
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		calls = 0
+		mapFunc = func(_ context.Context, obj client.Object) []reconcile.Request {
+			calls++
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName() + "-" + obj.GetResourceVersion(),
+			}}}
+		}
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "biz", Name: "baz", UID: "uid", ResourceVersion: "1",
+		}}
+	})
+
+	It("should memoize the requests of an object until its resourceVersion changes", func() {
+		cache := handler.NewMapFuncCache(mapFunc, handler.MapFuncCacheOptions{})
+		fn := cache.MapFunc()
+
+		expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz-1"}}}
+		Expect(fn(ctx, pod)).To(Equal(expected))
+		Expect(fn(ctx, pod)).To(Equal(expected))
+		Expect(calls).To(Equal(1))
+		Expect(cache.Len()).To(Equal(1))
+
+		pod.ResourceVersion = "2"
+		Expect(fn(ctx, pod)).To(Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz-2"}}}))
+		Expect(calls).To(Equal(2))
+		Expect(cache.Len()).To(Equal(1))
+	})
+
+	It("should not memoize the requests of objects without a UID or resourceVersion", func() {
+		fn := handler.NewMapFuncCache(mapFunc, handler.MapFuncCacheOptions{}).MapFunc()
+		pod.UID = ""
+		fn(ctx, pod)
+		fn(ctx, pod)
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should map objects again once invalidated", func() {
+		cache := handler.NewMapFuncCache(mapFunc, handler.MapFuncCacheOptions{})
+		fn := cache.MapFunc()
+
+		fn(ctx, pod)
+		cache.Invalidate("other")
+		fn(ctx, pod)
+		Expect(calls).To(Equal(1))
+
+		cache.Invalidate(pod.UID)
+		fn(ctx, pod)
+		Expect(calls).To(Equal(2))
+
+		cache.InvalidateAll()
+		Expect(cache.Len()).To(BeZero())
+		fn(ctx, pod)
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should map objects again once their requests expired", func() {
+		fn := handler.NewMapFuncCache(mapFunc, handler.MapFuncCacheOptions{TTL: 10 * time.Millisecond}).MapFunc()
+		fn(ctx, pod)
+		fn(ctx, pod)
+		Expect(calls).To(Equal(1))
+		Eventually(func() int {
+			fn(ctx, pod)
+			return calls
+		}).Should(BeNumerically(">", 1))
+	})
+
+	It("should evict the least recently used objects beyond its size", func() {
+		cache := handler.NewMapFuncCache(mapFunc, handler.MapFuncCacheOptions{Size: 1})
+		fn := cache.MapFunc()
+		other := pod.DeepCopy()
+		other.UID = "other"
+
+		fn(ctx, pod)
+		fn(ctx, other)
+		Expect(cache.Len()).To(Equal(1))
+		fn(ctx, pod)
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should not let callers modify the memoized requests", func() {
+		fn := handler.NewMapFuncCache(mapFunc, handler.MapFuncCacheOptions{}).MapFunc()
+		fn(ctx, pod)[0].Name = "modified"
+		Expect(fn(ctx, pod)[0].Name).To(Equal("baz-1"))
+	})
+
+})
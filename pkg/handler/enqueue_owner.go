@@ -100,6 +100,11 @@ type enqueueRequestForOwner[object client.Object] struct {
 	mapper meta.RESTMapper
 }
 
+// String describes the handler with the kind of the owners it enqueues.
+func (e *enqueueRequestForOwner[object]) String() string {
+	return fmt.Sprintf("EnqueueRequestForOwner(%s)", e.groupKind)
+}
+
 func (e *enqueueRequestForOwner[object]) setIsController(isController bool) {
 	e.isController = isController
 }
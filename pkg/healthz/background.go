@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithTimeout returns a checker failing if check doesn't return within the
+// given timeout. The context of the request passed to check is canceled
+// once the timeout expires; a check ignoring it keeps running in the
+// background, but no longer blocks the probe.
+func WithTimeout(check Checker, timeout time.Duration) Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		return runWithContext(ctx, check, req.WithContext(ctx))
+	}
+}
+
+// runWithContext runs check, returning early if ctx is done.
+func runWithContext(ctx context.Context, check Checker, req *http.Request) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(req)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.New("check timed out")
+		}
+		return ctx.Err()
+	}
+}
+
+// BackgroundOptions configures a BackgroundChecker.
+type BackgroundOptions struct {
+	// Interval is the interval between the runs of the check. Defaults to
+	// 10 seconds.
+	Interval time.Duration
+
+	// Timeout is the timeout of a run of the check. Defaults to the
+	// Interval.
+	Timeout time.Duration
+
+	// MaxAge is the age after which the cached result is considered stale
+	// and the checker fails, e.g. because a run of the check doesn't
+	// return. Defaults to three times the Interval plus the Timeout.
+	MaxAge time.Duration
+}
+
+// BackgroundChecker runs a check on an interval in the background, and
+// serves its cached result, so that slow checks, e.g. pinging the API
+// server or an external dependency, don't block the probes of the kubelet.
+// It must be started, e.g. by adding it to the manager, and its Check
+// registered, e.g. with AddHealthzCheck:
+//
+//	checker := healthz.NewBackgroundChecker(check, healthz.BackgroundOptions{})
+//	if err := mgr.Add(checker); err != nil { ... }
+//	if err := mgr.AddHealthzCheck("apiserver", checker.Check); err != nil { ... }
+//
+// Check fails until the first run of the check completes.
+type BackgroundChecker struct {
+	check Checker
+	opts  BackgroundOptions
+
+	mu        sync.Mutex
+	err       error
+	checkedAt time.Time
+
+	// now is the clock of the checker, for testing.
+	now func() time.Time
+}
+
+// NewBackgroundChecker returns a BackgroundChecker of the given check.
+// The requests passed to check carry the context of the run, and no
+// other information.
+func NewBackgroundChecker(check Checker, opts BackgroundOptions) *BackgroundChecker {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = opts.Interval
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 3*opts.Interval + opts.Timeout
+	}
+	return &BackgroundChecker{
+		check: check,
+		opts:  opts,
+		err:   errors.New("check has not completed yet"),
+		now:   time.Now,
+	}
+}
+
+// Start runs the check on the interval until ctx is done.
+func (c *BackgroundChecker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	for {
+		c.run(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, the check
+// runs on every replica.
+func (c *BackgroundChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// Check returns the cached result of the check, failing if it is stale.
+// It is a Checker.
+func (c *BackgroundChecker) Check(_ *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkedAt.IsZero() {
+		if age := c.now().Sub(c.checkedAt); age > c.opts.MaxAge {
+			return fmt.Errorf("last result of the check is stale, completed %s ago", age.Round(time.Second))
+		}
+	}
+	return c.err
+}
+
+func (c *BackgroundChecker) run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if err == nil {
+		err = runWithContext(ctx, c.check, req)
+	}
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		// The manager is stopping, keep the last result.
+		return
+	}
+	if err != nil {
+		log.V(1).Info("background healthz check failed", "error", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+	c.checkedAt = c.now()
+}
@@ -22,7 +22,8 @@ limitations under the License.
 // but has some changes to bring it in line with controller-runtime's style.
 //
 // The main entrypoint is the Handler -- this serves both aggregated health status
-// and individual health check endpoints.
+// and individual health check endpoints. Slow checks can be bounded with
+// WithTimeout, or run in the background with a BackgroundChecker.
 package healthz
 
 import (
@@ -17,9 +17,12 @@ limitations under the License.
 package healthz_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -201,3 +204,67 @@ var _ = Describe("Healthz Handler", func() {
 		})
 	})
 })
+
+var _ = Describe("WithTimeout", func() {
+	It("should fail if the check doesn't return in time", func() {
+		check := healthz.WithTimeout(func(req *http.Request) error {
+			<-req.Context().Done()
+			return req.Context().Err()
+		}, 10*time.Millisecond)
+
+		resp := requestTo(&healthz.Handler{Checks: map[string]healthz.Checker{"slow": check}}, "/slow")
+		Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		Expect(resp.Body.String()).To(ContainSubstring("check timed out"))
+	})
+
+	It("should return the result of the check", func() {
+		check := healthz.WithTimeout(healthz.Ping, time.Second)
+		Expect(check(httptest.NewRequest(http.MethodGet, "/", nil))).To(Succeed())
+	})
+})
+
+var _ = Describe("BackgroundChecker", func() {
+	It("should serve the cached result of the check", func(specCtx SpecContext) {
+		var fail atomic.Bool
+		var runs atomic.Int32
+		checker := healthz.NewBackgroundChecker(func(req *http.Request) error {
+			runs.Add(1)
+			if fail.Load() {
+				return errors.New("blech")
+			}
+			return nil
+		}, healthz.BackgroundOptions{Interval: 10 * time.Millisecond})
+		handler := &healthz.Handler{Checks: map[string]healthz.Checker{"bg": checker.Check}}
+
+		By("failing before the first run completes")
+		Expect(requestTo(handler, "/bg").Code).To(Equal(http.StatusInternalServerError))
+
+		ctx, cancel := context.WithCancel(specCtx)
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(checker.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(func() int { return requestTo(handler, "/bg").Code }).Should(Equal(http.StatusOK))
+		fail.Store(true)
+		Eventually(func() int { return requestTo(handler, "/bg").Code }).Should(Equal(http.StatusInternalServerError))
+		Expect(runs.Load()).To(BeNumerically(">", 1))
+	})
+
+	It("should not block probes on a slow check", func(specCtx SpecContext) {
+		checker := healthz.NewBackgroundChecker(func(req *http.Request) error {
+			<-req.Context().Done()
+			return req.Context().Err()
+		}, healthz.BackgroundOptions{Interval: 10 * time.Millisecond, Timeout: 5 * time.Millisecond})
+
+		ctx, cancel := context.WithCancel(specCtx)
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(checker.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(func() error { return checker.Check(nil) }).Should(MatchError("check timed out"))
+	})
+})
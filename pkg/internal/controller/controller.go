@@ -24,12 +24,16 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/util/workqueue"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/internal/controllerinfo"
+	internalsource "sigs.k8s.io/controller-runtime/pkg/internal/source"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -51,6 +55,12 @@ type Controller[request comparable] struct {
 	// RateLimiter is used to limit how frequently requests may be queued into the work queue.
 	RateLimiter workqueue.TypedRateLimiter[request]
 
+	// RequeueRateLimiter, if set, is used instead of RateLimiter to delay
+	// the requeues the Reconciler asks for, whether with Result.Requeue or
+	// Result.RequeueAfter. Its backoff grows with consecutive
+	// Result.Requeue, and is reset by Result.RequeueAfter.
+	RequeueRateLimiter workqueue.TypedRateLimiter[request]
+
 	// NewQueue constructs the queue for this controller once the controller is ready to start.
 	// This is a func because the standard Kubernetes work queues start themselves immediately, which
 	// leads to goroutine leaks if something calls controller.New repeatedly.
@@ -80,6 +90,25 @@ type Controller[request comparable] struct {
 	// startWatches maintains a list of sources, handlers, and predicates to start when the controller is started.
 	startWatches []source.TypedSource[request]
 
+	// sourcesMu guards sources, watches and queueLen. It is distinct from mu because
+	// mu is held while waiting for caches to sync.
+	sourcesMu sync.Mutex
+
+	// sources holds a description of every source passed to Watch, used to
+	// describe the controller to the manager.
+	sources []string
+
+	// watches holds the detailed description of every source passed to
+	// Watch.
+	watches []controllerinfo.WatchInfo
+
+	// queueLen returns the length of the queue once the controller has been
+	// started, used to describe the controller to the manager.
+	queueLen func() int
+
+	// quarantined holds the sources currently quarantined.
+	quarantined map[string]struct{}
+
 	// LogConstructor is used to construct a logger to then log messages to users during reconciliation,
 	// or for example when a watch is started.
 	// Note: LogConstructor has to be able to handle nil requests as we are also using it
@@ -92,13 +121,128 @@ type Controller[request comparable] struct {
 
 	// LeaderElected indicates whether the controller is leader elected or always running.
 	LeaderElected *bool
+
+	// PriorityClass is added to the context of each reconciliation if set.
+	PriorityClass client.PriorityClass
+
+	// ResyncPriorityClass is added to the context of reconciliations that
+	// were only triggered by resyncs instead of PriorityClass if set. It
+	// requires RecordTriggers.
+	ResyncPriorityClass client.PriorityClass
+
+	// FieldManager is added to the context of each reconciliation if set.
+	FieldManager string
+
+	// ScopeReads adds the scope of the controller to the context of each
+	// reconciliation, see client.WithReadScope.
+	ScopeReads bool
+
+	// StartWhen, if set, is called after the sources have been started and
+	// synced, and blocks the workers from processing requests until it returns.
+	StartWhen func(ctx context.Context) error
+
+	// RecordTriggers makes the controller record the events that caused
+	// requests to be enqueued and pass them to each reconciliation via the
+	// context.
+	RecordTriggers bool
+
+	// Permissions are the permissions the controller requires, reported by
+	// DescribeController.
+	Permissions []controllerinfo.Permission
+
+	// AccountUsage makes the controller record the resource usage of each
+	// reconciliation, and report it in logs and metrics.
+	AccountUsage bool
+
+	// MetricsLabel is the value of the controller label of the metrics of
+	// the controller. Defaults to Name.
+	MetricsLabel string
+
+	// ReconcileExemplars makes the controller attach the exemplar set with
+	// SetReconcileExemplar during a reconciliation to its duration metric.
+	ReconcileExemplars bool
+
+	// ObjectUID, if set, returns the UID of the object of a request, or an
+	// empty UID if it is unknown. The UID is added to the logger of the
+	// reconciliation and is its default exemplar.
+	ObjectUID func(ctx context.Context, req request) types.UID
+
+	// SourceQuarantineThreshold, if positive, quarantines the sources whose
+	// informer failed to list or watch that many consecutive times: their
+	// events are dropped until the informer recovers. The health of the
+	// informers is checked every SourceQuarantineCheckInterval.
+	SourceQuarantineThreshold     int
+	SourceQuarantineCheckInterval time.Duration
+
+	// SkipRequest, if set, is called before reconciling each request.
+	// Requests it returns true for are dropped without being reconciled.
+	SkipRequest func(req request) bool
+
+	// DebounceQuietPeriod, if set, holds the requests added by sources
+	// until no further request for the same key was added during the
+	// period, or DebounceMaxDelay elapsed.
+	DebounceQuietPeriod time.Duration
+	DebounceMaxDelay    time.Duration
+
+	// MemoryPressure, if set, reports whether the process is under memory
+	// pressure. It is called every MemoryPressureCheckInterval, and the
+	// requeues requested with RequeueAfter are held back while it returns
+	// true.
+	MemoryPressure              func() bool
+	MemoryPressureCheckInterval time.Duration
+
+	// RequeueTimerResolution, if positive, is the resolution of the timers
+	// registered with reconcile.RequeueUnlessChanged.
+	RequeueTimerResolution time.Duration
+
+	// requeueTimers holds the timers registered with
+	// reconcile.RequeueUnlessChanged once the controller started.
+	requeueTimers *requeueTimers[request]
+
+	// WarmUp configures an initial pass reconciling the requests it lists
+	// when the controller starts, if set.
+	WarmUp *WarmUp[request]
+
+	// ShutdownTimeout, if positive, bounds how long the controller waits
+	// for its in-flight reconciles when it stops.
+	ShutdownTimeout time.Duration
+
+	// ReportShutdown, if set, is called once the controller stopped with
+	// the requests it did not complete. See ShutdownReport.
+	ReportShutdown func(report ShutdownReport[request])
+
+	// shutdownMu guards the fields below, which track the requests not
+	// completed at shutdown if ReportShutdown is set.
+	shutdownMu sync.Mutex
+	inFlight   map[request]time.Time
+	drained    []request
+
+	// warmUpMu guards warmUpState, which tracks the warm-up pass in progress.
+	warmUpMu    sync.Mutex
+	warmUpState *warmUpState[request]
+
+	// workersMu guards MaxConcurrentReconciles once the controller has been
+	// created, and the fields below.
+	workersMu sync.Mutex
+
+	// workers is the number of running workers.
+	workers int
+
+	// workerCtx and workerGroup are set once the workers have been
+	// launched, and used to launch more of them.
+	workerCtx   context.Context
+	workerGroup *sync.WaitGroup
+
+	// workersStopped is set once the controller waits for its workers to
+	// finish, after which no worker can be launched.
+	workersStopped bool
 }
 
 // Reconcile implements reconcile.Reconciler.
 func (c *Controller[request]) Reconcile(ctx context.Context, req request) (_ reconcile.Result, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			ctrlmetrics.ReconcilePanics.WithLabelValues(c.Name).Inc()
+			ctrlmetrics.ReconcilePanics.WithLabelValues(c.metricsLabel()).Inc()
 
 			if c.RecoverPanic == nil || *c.RecoverPanic {
 				for _, fn := range utilruntime.PanicHandlers {
@@ -121,6 +265,22 @@ func (c *Controller[request]) Watch(src source.TypedSource[request]) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.sourcesMu.Lock()
+	c.sources = append(c.sources, sourceName(src))
+	c.watches = append(c.watches, describeWatch(src))
+	c.sourcesMu.Unlock()
+
+	if q, ok := src.(quarantinable); ok && c.SourceQuarantineThreshold > 0 {
+		name := sourceName(src)
+		q.SetQuarantine(internalsource.Quarantine{
+			Threshold:     c.SourceQuarantineThreshold,
+			CheckInterval: c.SourceQuarantineCheckInterval,
+			OnChange: func(quarantined bool, err error) {
+				c.setQuarantined(name, quarantined, err)
+			},
+		})
+	}
+
 	// Controller hasn't started yet, store the watches locally and return.
 	//
 	// These watches are going to be held on the controller struct until the manager or user calls Start(...).
@@ -141,6 +301,83 @@ func (c *Controller[request]) NeedLeaderElection() bool {
 	return *c.LeaderElected
 }
 
+// DescribeController implements the manager.ControllerDescriber interface.
+func (c *Controller[request]) DescribeController() controllerinfo.ControllerInfo {
+	c.sourcesMu.Lock()
+	defer c.sourcesMu.Unlock()
+
+	var queueDepth int
+	if c.queueLen != nil {
+		queueDepth = c.queueLen()
+	}
+	c.workersMu.Lock()
+	maxConcurrentReconciles := c.MaxConcurrentReconciles
+	c.workersMu.Unlock()
+	var quarantined []string
+	for _, name := range c.sources {
+		if _, ok := c.quarantined[name]; ok {
+			quarantined = append(quarantined, name)
+		}
+	}
+	return controllerinfo.ControllerInfo{
+		Name:                    c.Name,
+		QuarantinedSources:      quarantined,
+		Sources:                 append([]string(nil), c.sources...),
+		Watches:                 append([]controllerinfo.WatchInfo(nil), c.watches...),
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		NeedLeaderElection:      c.NeedLeaderElection(),
+		Permissions:             append([]controllerinfo.Permission(nil), c.Permissions...),
+		QueueDepth:              queueDepth,
+	}
+}
+
+// quarantinable is implemented by sources that can be quarantined while
+// their informer keeps failing.
+type quarantinable interface {
+	SetQuarantine(q internalsource.Quarantine)
+}
+
+// setQuarantined records that the source named name entered or left
+// quarantine.
+func (c *Controller[request]) setQuarantined(name string, quarantined bool, err error) {
+	c.sourcesMu.Lock()
+	defer c.sourcesMu.Unlock()
+
+	if quarantined {
+		if c.quarantined == nil {
+			c.quarantined = map[string]struct{}{}
+		}
+		c.quarantined[name] = struct{}{}
+		c.LogConstructor(nil).Error(err, "Quarantining source, its events are dropped until its informer recovers", "source", name)
+	} else {
+		delete(c.quarantined, name)
+		c.LogConstructor(nil).Info("Source recovered from quarantine", "source", name)
+	}
+	ctrlmetrics.QuarantinedSources.WithLabelValues(c.metricsLabel()).Set(float64(len(c.quarantined)))
+}
+
+// watchDescriber is implemented by sources that describe their watch in
+// more detail than their string representation.
+type watchDescriber interface {
+	DescribeWatch() controllerinfo.WatchInfo
+}
+
+func describeWatch(src any) controllerinfo.WatchInfo {
+	if d, ok := src.(watchDescriber); ok {
+		return d.DescribeWatch()
+	}
+	return controllerinfo.WatchInfo{Source: sourceName(src)}
+}
+
+// sourceName describes src by its string representation, or by its type if
+// it has none.
+func sourceName(src any) string {
+	if s, ok := src.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", src)
+}
+
 // Start implements controller.Controller.
 func (c *Controller[request]) Start(ctx context.Context) error {
 	// use an IIFE to get proper lock handling
@@ -156,6 +393,24 @@ func (c *Controller[request]) Start(ctx context.Context) error {
 	c.ctx = ctx
 
 	c.Queue = c.NewQueue(c.Name, c.RateLimiter)
+	if c.DebounceQuietPeriod > 0 {
+		c.Queue = newDebounceQueue(c.Queue, c.DebounceQuietPeriod, c.DebounceMaxDelay)
+	}
+	if c.MemoryPressure != nil {
+		shedding := newSheddingQueue(c.Queue, c.metricsLabel())
+		go shedding.monitor(ctx, c.LogConstructor(nil), c.MemoryPressure, c.MemoryPressureCheckInterval)
+		c.Queue = shedding
+	}
+	if c.RecordTriggers {
+		c.Queue = newTriggerQueue(c.Queue)
+	}
+	if c.RequeueTimerResolution > 0 {
+		c.requeueTimers = newRequeueTimers[request](c.RequeueTimerResolution)
+		go c.requeueTimers.run(ctx, c.Queue)
+	}
+	c.sourcesMu.Lock()
+	c.queueLen = c.Queue.Len
+	c.sourcesMu.Unlock()
 	go func() {
 		<-ctx.Done()
 		c.Queue.ShutDown()
@@ -213,19 +468,6 @@ func (c *Controller[request]) Start(ctx context.Context) error {
 		// which won't be garbage collected if we hold a reference to it.
 		c.startWatches = nil
 
-		// Launch workers to process resources
-		c.LogConstructor(nil).Info("Starting workers", "worker count", c.MaxConcurrentReconciles)
-		wg.Add(c.MaxConcurrentReconciles)
-		for i := 0; i < c.MaxConcurrentReconciles; i++ {
-			go func() {
-				defer wg.Done()
-				// Run a worker thread that just dequeues items, processes them, and marks them done.
-				// It enforces that the reconcileHandler is never invoked concurrently with the same object.
-				for c.processNextWorkItem(ctx) {
-				}
-			}()
-		}
-
 		c.Started = true
 		return nil
 	}()
@@ -233,13 +475,93 @@ func (c *Controller[request]) Start(ctx context.Context) error {
 		return err
 	}
 
+	// Wait for the start condition without holding the lock, so that
+	// watches can still be added while the controller waits.
+	if c.StartWhen != nil {
+		c.LogConstructor(nil).Info("Waiting for start condition")
+		if err := c.StartWhen(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to wait for %s start condition: %w", c.Name, err)
+		}
+	}
+
+	// Launch workers to process resources
+	c.workersMu.Lock()
+	c.LogConstructor(nil).Info("Starting workers", "worker count", c.MaxConcurrentReconciles)
+	c.workerCtx = ctx
+	c.workerGroup = wg
+	c.launchWorkersLocked()
+	c.workersMu.Unlock()
+	if c.WarmUp != nil {
+		go c.warmUp(ctx)
+	}
+
 	<-ctx.Done()
 	c.LogConstructor(nil).Info("Shutdown signal received, waiting for all workers to finish")
-	wg.Wait()
-	c.LogConstructor(nil).Info("All workers finished")
+	c.workersMu.Lock()
+	c.workersStopped = true
+	c.workersMu.Unlock()
+	timedOut := c.waitForWorkers(wg)
+	if !timedOut {
+		c.LogConstructor(nil).Info("All workers finished")
+	}
+	if c.ReportShutdown != nil {
+		c.reportShutdown(timedOut)
+	}
 	return nil
 }
 
+// SetMaxConcurrentReconciles changes the maximum number of concurrent
+// Reconciles of the controller, including while it runs. Workers are
+// launched right away when it grows, and stop once they are done with their
+// current request when it shrinks.
+func (c *Controller[request]) SetMaxConcurrentReconciles(n int) {
+	if n <= 0 {
+		n = 1
+	}
+	c.workersMu.Lock()
+	defer c.workersMu.Unlock()
+	if n == c.MaxConcurrentReconciles {
+		return
+	}
+	c.LogConstructor(nil).Info("Changing worker count", "worker count", n)
+	c.MaxConcurrentReconciles = n
+	ctrlmetrics.WorkerCount.WithLabelValues(c.metricsLabel()).Set(float64(n))
+	c.launchWorkersLocked()
+}
+
+// launchWorkersLocked launches workers until there are
+// MaxConcurrentReconciles of them, if the workers have been started.
+func (c *Controller[request]) launchWorkersLocked() {
+	if c.workerGroup == nil || c.workersStopped {
+		return
+	}
+	for ; c.workers < c.MaxConcurrentReconciles; c.workers++ {
+		c.workerGroup.Add(1)
+		go func() {
+			defer c.workerGroup.Done()
+			// Run a worker thread that just dequeues items, processes them, and marks them done.
+			// It enforces that the reconcileHandler is never invoked concurrently with the same object.
+			for c.keepWorking() && c.processNextWorkItem(c.workerCtx) {
+			}
+		}()
+	}
+}
+
+// keepWorking returns false, and accounts for the worker stopping, if there
+// are more workers than MaxConcurrentReconciles.
+func (c *Controller[request]) keepWorking() bool {
+	c.workersMu.Lock()
+	defer c.workersMu.Unlock()
+	if c.workers > c.MaxConcurrentReconciles {
+		c.workers--
+		return false
+	}
+	return true
+}
+
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the reconcileHandler.
 func (c *Controller[request]) processNextWorkItem(ctx context.Context) bool {
@@ -257,10 +579,20 @@ func (c *Controller[request]) processNextWorkItem(ctx context.Context) bool {
 	// period.
 	defer c.Queue.Done(obj)
 
-	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Add(1)
-	defer ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Add(-1)
+	if c.ReportShutdown != nil {
+		if c.drain(ctx, obj) {
+			return true
+		}
+		defer c.untrack(obj)
+	}
+
+	ctrlmetrics.ActiveWorkers.WithLabelValues(c.metricsLabel()).Add(1)
+	defer ctrlmetrics.ActiveWorkers.WithLabelValues(c.metricsLabel()).Add(-1)
 
 	c.reconcileHandler(ctx, obj)
+	if c.WarmUp != nil {
+		c.warmedUp(obj)
+	}
 	return true
 }
 
@@ -272,30 +604,86 @@ const (
 )
 
 func (c *Controller[request]) initMetrics() {
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelError).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeueAfter).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeue).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelSuccess).Add(0)
-	ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Add(0)
-	ctrlmetrics.TerminalReconcileErrors.WithLabelValues(c.Name).Add(0)
-	ctrlmetrics.ReconcilePanics.WithLabelValues(c.Name).Add(0)
-	ctrlmetrics.WorkerCount.WithLabelValues(c.Name).Set(float64(c.MaxConcurrentReconciles))
-	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Set(0)
+	ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelError).Add(0)
+	ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelRequeueAfter).Add(0)
+	ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelRequeue).Add(0)
+	ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelSuccess).Add(0)
+	ctrlmetrics.ReconcileErrors.WithLabelValues(c.metricsLabel()).Add(0)
+	ctrlmetrics.TerminalReconcileErrors.WithLabelValues(c.metricsLabel()).Add(0)
+	ctrlmetrics.ReconcilePanics.WithLabelValues(c.metricsLabel()).Add(0)
+	c.workersMu.Lock()
+	ctrlmetrics.WorkerCount.WithLabelValues(c.metricsLabel()).Set(float64(c.MaxConcurrentReconciles))
+	c.workersMu.Unlock()
+	ctrlmetrics.ActiveWorkers.WithLabelValues(c.metricsLabel()).Set(0)
+	ctrlmetrics.QuarantinedSources.WithLabelValues(c.metricsLabel()).Set(0)
+}
+
+// reportUsage logs the resource usage of a reconciliation and updates the
+// usage metrics.
+func (c *Controller[request]) reportUsage(log logr.Logger, recorder *client.UsageRecorder) {
+	usage := recorder.Usage()
+	ctrlmetrics.ReconcileAPIRequests.WithLabelValues(c.metricsLabel()).Observe(float64(usage.Requests))
+	ctrlmetrics.ReconcileObjectsRead.WithLabelValues(c.metricsLabel()).Observe(float64(usage.ObjectsRead))
+	ctrlmetrics.ReconcileBytesWritten.WithLabelValues(c.metricsLabel()).Observe(float64(usage.BytesWritten))
+	log.V(5).Info("Reconcile usage", "apiRequests", usage.Requests, "objectsRead", usage.ObjectsRead, "bytesWritten", usage.BytesWritten)
 }
 
 func (c *Controller[request]) reconcileHandler(ctx context.Context, req request) {
+	if c.SkipRequest != nil && c.SkipRequest(req) {
+		c.LogConstructor(&req).V(5).Info("Dropping request")
+		c.Queue.Forget(req)
+		return
+	}
+
+	var exemplar *exemplarHolder
+	if c.ReconcileExemplars {
+		ctx, exemplar = withExemplarHolder(ctx)
+	}
+
 	// Update metrics after processing each item
 	reconcileStartTS := time.Now()
 	defer func() {
-		c.updateMetrics(time.Since(reconcileStartTS))
+		c.updateMetrics(time.Since(reconcileStartTS), exemplar)
 	}()
 
 	log := c.LogConstructor(&req)
+	if c.ObjectUID != nil {
+		if uid := c.ObjectUID(ctx, req); uid != "" {
+			log = log.WithValues("uid", uid)
+			exemplar.setDefault(prometheus.Labels{"object_uid": string(uid)})
+		}
+	}
 	reconcileID := uuid.NewUUID()
 
 	log = log.WithValues("reconcileID", reconcileID)
 	ctx = logf.IntoContext(ctx, log)
 	ctx = addReconcileID(ctx, reconcileID)
+	if c.ScopeReads {
+		ctx = client.WithReadScope(ctx, c.Name)
+	}
+	if c.FieldManager != "" {
+		ctx = client.WithContextFieldManager(ctx, c.FieldManager)
+	}
+	priorityClass := c.PriorityClass
+	if triggers, ok := c.Queue.(*triggerQueue[request]); ok {
+		popped := triggers.popTriggers(req)
+		ctx = reconcile.WithTriggers(ctx, popped)
+		if c.ResyncPriorityClass != "" && onlyResyncs(popped) {
+			priorityClass = c.ResyncPriorityClass
+		}
+	}
+	if priorityClass != "" {
+		ctx = client.WithPriorityClass(ctx, priorityClass)
+	}
+	if timers := c.requeueTimers; timers != nil {
+		timers.remove(req)
+		ctx = reconcile.WithRequeueTimer(ctx, func(after time.Duration) { timers.add(req, after) })
+	}
+	if c.AccountUsage {
+		var usage *client.UsageRecorder
+		ctx, usage = client.WithUsageRecorder(ctx)
+		defer c.reportUsage(log, usage)
+	}
 
 	// RunInformersAndControllers the syncHandler, passing it the Namespace/Name string of the
 	// resource to be synced.
@@ -304,12 +692,12 @@ func (c *Controller[request]) reconcileHandler(ctx context.Context, req request)
 	switch {
 	case err != nil:
 		if errors.Is(err, reconcile.TerminalError(nil)) {
-			ctrlmetrics.TerminalReconcileErrors.WithLabelValues(c.Name).Inc()
+			ctrlmetrics.TerminalReconcileErrors.WithLabelValues(c.metricsLabel()).Inc()
 		} else {
 			c.Queue.AddRateLimited(req)
 		}
-		ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Inc()
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelError).Inc()
+		ctrlmetrics.ReconcileErrors.WithLabelValues(c.metricsLabel()).Inc()
+		ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelError).Inc()
 		if !result.IsZero() {
 			log.Info("Warning: Reconciler returned both a non-zero result and a non-nil error. The result will always be ignored if the error is non-nil and the non-nil error causes reqeueuing with exponential backoff. For more details, see: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/reconcile#Reconciler")
 		}
@@ -320,19 +708,38 @@ func (c *Controller[request]) reconcileHandler(ctx context.Context, req request)
 		// along with a non-nil error. But this is intended as
 		// We need to drive to stable reconcile loops before queuing due
 		// to result.RequestAfter
-		c.Queue.Forget(req)
-		c.Queue.AddAfter(req, result.RequeueAfter)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeueAfter).Inc()
+		after := result.RequeueAfter
+		if c.RequeueRateLimiter != nil {
+			// The backoff of the retries of failed reconciles is owned by
+			// the rate limiter of the queue, and only reset once the
+			// request is reconciled successfully. The RequeueRateLimiter
+			// only bounds the rate of requeues after a delay: its backoff
+			// is reset so that it doesn't grow with every periodic
+			// requeue.
+			after = max(after, c.RequeueRateLimiter.When(req))
+			c.RequeueRateLimiter.Forget(req)
+		} else {
+			c.Queue.Forget(req)
+		}
+		c.Queue.AddAfter(req, after)
+		ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelRequeueAfter).Inc()
 	case result.Requeue:
 		log.V(5).Info("Reconcile done, requeueing")
-		c.Queue.AddRateLimited(req)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeue).Inc()
+		if c.RequeueRateLimiter != nil {
+			c.Queue.AddAfter(req, c.RequeueRateLimiter.When(req))
+		} else {
+			c.Queue.AddRateLimited(req)
+		}
+		ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelRequeue).Inc()
 	default:
 		log.V(5).Info("Reconcile successful")
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
 		c.Queue.Forget(req)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelSuccess).Inc()
+		if c.RequeueRateLimiter != nil {
+			c.RequeueRateLimiter.Forget(req)
+		}
+		ctrlmetrics.ReconcileTotal.WithLabelValues(c.metricsLabel(), labelSuccess).Inc()
 	}
 }
 
@@ -342,8 +749,17 @@ func (c *Controller[request]) GetLogger() logr.Logger {
 }
 
 // updateMetrics updates prometheus metrics within the controller.
-func (c *Controller[request]) updateMetrics(reconcileTime time.Duration) {
-	ctrlmetrics.ReconcileTime.WithLabelValues(c.Name).Observe(reconcileTime.Seconds())
+func (c *Controller[request]) updateMetrics(reconcileTime time.Duration, exemplar *exemplarHolder) {
+	exemplar.observe(ctrlmetrics.ReconcileTime.WithLabelValues(c.metricsLabel()), reconcileTime.Seconds())
+}
+
+// metricsLabel returns the value of the controller label of the metrics of
+// the controller.
+func (c *Controller[request]) metricsLabel() string {
+	if c.MetricsLabel != "" {
+		return c.MetricsLabel
+	}
+	return c.Name
 }
 
 // ReconcileIDFromContext gets the reconcileID from the current context.
@@ -20,28 +20,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/internal/log"
+	internalsource "sigs.k8s.io/controller-runtime/pkg/internal/source"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -143,6 +151,26 @@ var _ = Describe("controller", func() {
 		})
 	})
 
+	Describe("Watch", func() {
+		It("should report the quarantined sources", func() {
+			ctrl.SourceQuarantineThreshold = 3
+			src := source.Kind(&informertest.FakeInformers{}, &corev1.Pod{}, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{})
+			Expect(ctrl.Watch(src)).To(Succeed())
+			Expect(ctrl.DescribeController().QuarantinedSources).To(BeEmpty())
+
+			ctrl.setQuarantined("kind source: *v1.Pod", true, errors.New("not found"))
+			Expect(ctrl.DescribeController().QuarantinedSources).To(ConsistOf("kind source: *v1.Pod"))
+
+			ctrl.setQuarantined("kind source: *v1.Pod", false, nil)
+			Expect(ctrl.DescribeController().QuarantinedSources).To(BeEmpty())
+		})
+
+		It("should describe sources without string representation by their type", func() {
+			Expect(ctrl.Watch(&unnamedSource{})).To(Succeed())
+			Expect(ctrl.DescribeController().Sources).To(ConsistOf("*controller.unnamedSource"))
+		})
+	})
+
 	Describe("Start", func() {
 		It("should return an error if there is an error waiting for the informers", func() {
 			f := false
@@ -268,6 +296,104 @@ var _ = Describe("controller", func() {
 			<-processed
 		})
 
+		DescribeTable("should only pass a field manager to the reconciler if set",
+			func(fieldManager string) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				fieldManagers := make(chan string, 1)
+				ctrl.FieldManager = fieldManager
+				ctrl.Do = reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					fieldManager, _ := client.FieldManagerFromContext(ctx)
+					fieldManagers <- fieldManager
+					return reconcile.Result{}, nil
+				})
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).To(Succeed())
+				}()
+
+				queue.Add(request)
+				Eventually(fieldManagers).Should(Receive(Equal(fieldManager)))
+			},
+			Entry("unset", ""),
+			Entry("set", "my-controller"),
+		)
+
+		It("should pass the triggers of a request to the reconciler", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch := make(chan event.GenericEvent, 1)
+			p := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			}
+			ch <- event.GenericEvent{Object: p}
+
+			triggers := make(chan []reconcile.Trigger, 1)
+			ctrl.RecordTriggers = true
+			ctrl.Do = reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				triggers <- reconcile.TriggersFromContext(ctx)
+				return reconcile.Result{}, nil
+			})
+			ctrl.startWatches = []source.TypedSource[reconcile.Request]{
+				source.Channel(ch, &handler.EnqueueRequestForObject{}),
+			}
+
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(triggers).Should(Receive(Equal([]reconcile.Trigger{
+				{EventType: reconcile.EventGeneric, Object: p},
+			})))
+		})
+
+		It("should use the resync priority class for reconciles only triggered by resyncs", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			resynced := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "resynced", Namespace: "bar", ResourceVersion: "1"}}
+			changed := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "changed", Namespace: "bar", ResourceVersion: "2"}}
+			changedOld := changed.DeepCopy()
+			changedOld.ResourceVersion = "1"
+
+			var mu sync.Mutex
+			classes := map[string]client.PriorityClass{}
+			ctrl.RecordTriggers = true
+			ctrl.PriorityClass = "interactive"
+			ctrl.ResyncPriorityClass = "background"
+			ctrl.Do = reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				class, _ := client.PriorityClassFromContext(ctx)
+				mu.Lock()
+				defer mu.Unlock()
+				classes[req.Name] = class
+				return reconcile.Result{}, nil
+			})
+			ctrl.startWatches = []source.TypedSource[reconcile.Request]{
+				source.Func(func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+					internalsource.WithUpdateTrigger(q, resynced, resynced).Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(resynced)})
+					internalsource.WithUpdateTrigger(q, changedOld, changed).Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(changed)})
+					return nil
+				}),
+			}
+
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(func() map[string]client.PriorityClass {
+				mu.Lock()
+				defer mu.Unlock()
+				return maps.Clone(classes)
+			}).Should(Equal(map[string]client.PriorityClass{
+				"resynced": "background",
+				"changed":  "interactive",
+			}))
+		})
+
 		It("should error when channel source is not specified", func() {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -323,6 +449,45 @@ var _ = Describe("controller", func() {
 			Expect(err.Error()).To(Equal("controller was started more than once. This is likely to be caused by being added to a manager multiple times"))
 		})
 
+		It("should not process items until the start condition is met", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ready := make(chan struct{})
+			ctrl.StartWhen = func(ctx context.Context) error {
+				select {
+				case <-ready:
+				case <-ctx.Done():
+				}
+				return nil
+			}
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			Eventually(func() bool {
+				ctrl.mu.Lock()
+				defer ctrl.mu.Unlock()
+				return ctrl.Started
+			}).Should(BeTrue())
+			queue.Add(request)
+
+			Consistently(reconciled).ShouldNot(Receive())
+			Expect(queue.Len()).To(Equal(1))
+
+			close(ready)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+		})
+
+		It("should return an error if the start condition fails", func() {
+			ctrl.StartWhen = func(context.Context) error {
+				return errors.New("condition failed")
+			}
+			err := ctrl.Start(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("condition failed"))
+		})
 	})
 
 	Describe("Processing queue items from a Controller", func() {
@@ -344,6 +509,64 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return queue.NumRequeues(request) }).Should(Equal(0))
 		})
 
+		It("should drop requests that SkipRequest returns true for", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			dropped := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "deleted", Name: "bar"}}
+			ctrl.SkipRequest = func(req reconcile.Request) bool {
+				return req.Namespace == "deleted"
+			}
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			queue.Add(dropped)
+			queue.Add(request)
+
+			By("Invoking Reconciler only for the request that is not dropped")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(queue.Len).Should(Equal(0))
+			Expect(queue.NumRequeues(dropped)).To(Equal(0))
+		})
+
+		It("should report the requests not completed at shutdown", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			started := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				close(started)
+				<-release
+				return reconcile.Result{}, nil
+			})
+			ctrl.ShutdownTimeout = 50 * time.Millisecond
+			reports := make(chan ShutdownReport[reconcile.Request], 1)
+			ctrl.ReportShutdown = func(report ShutdownReport[reconcile.Request]) {
+				reports <- report
+			}
+			stopped := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(stopped)
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			queued := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "queued"}}
+			queue.Add(request)
+			<-started
+			queue.Add(queued)
+
+			By("Stopping the controller while a request is in flight")
+			cancel()
+			Eventually(stopped).Should(BeClosed())
+			var report ShutdownReport[reconcile.Request]
+			Expect(reports).To(Receive(&report))
+			Expect(report.TimedOut).To(BeTrue())
+			Expect(report.InFlight).To(HaveKey(request))
+			Expect(report.Queued).To(ConsistOf(queued))
+		})
+
 		PIt("should forget an item if it is not a Request and continue processing items", func() {
 			// TODO(community): write this test
 		})
@@ -499,6 +722,86 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(0))
 		})
 
+		It("should requeue with the RequeueRateLimiter if set", func() {
+			dq := &DelegatingQueue{TypedRateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+			ctrl.NewQueue = func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+				return dq
+			}
+			limiter := &recordingRateLimiter{delay: 10 * time.Millisecond}
+			ctrl.RequeueRateLimiter = limiter
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			dq.Add(request)
+			Expect(dq.getCounts()).To(Equal(countInfo{Trying: 1}))
+
+			By("Invoking Reconciler which will ask for requeue")
+			fakeReconcile.AddResult(reconcile.Result{Requeue: true}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 1, AddAfter: 1}))
+			Expect(limiter.getWhen()).To(Equal(1))
+
+			By("Invoking Reconciler which will ask for a requeue after a shorter delay than the limiter's")
+			fakeReconcile.AddResult(reconcile.Result{RequeueAfter: time.Millisecond}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 1, AddAfter: 2}))
+			Expect(limiter.getWhen()).To(Equal(2))
+			Eventually(limiter.getForgotten).Should(Equal(1))
+
+			By("Invoking Reconciler a third time without asking for requeue")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(limiter.getForgotten).Should(Equal(2))
+			Expect(dq.getCounts().AddRateLimited).To(Equal(0))
+		})
+
+		It("should not reset the error backoff on requeues with the RequeueRateLimiter", func() {
+			dq := &DelegatingQueue{TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueue(
+				workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](time.Millisecond, time.Second),
+			)}
+			ctrl.NewQueue = func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+				return dq
+			}
+			limiter := &recordingRateLimiter{delay: time.Millisecond}
+			ctrl.RequeueRateLimiter = limiter
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			dq.Add(request)
+
+			By("Invoking Reconciler which returns an error")
+			fakeReconcile.AddResult(reconcile.Result{}, fmt.Errorf("expected error: reconcile"))
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(1))
+
+			By("Invoking Reconciler which will ask for requeue")
+			fakeReconcile.AddResult(reconcile.Result{Requeue: true}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(limiter.getWhen).Should(Equal(1))
+			Expect(dq.NumRequeues(request)).To(Equal(1))
+
+			By("Invoking Reconciler which returns an error again")
+			fakeReconcile.AddResult(reconcile.Result{}, fmt.Errorf("expected error: reconcile"))
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(2))
+
+			By("Invoking Reconciler which succeeds")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(0))
+			Eventually(limiter.getForgotten).Should(Equal(1))
+		})
+
 		It("should perform error behavior if error is not nil, regardless of RequeueAfter", func() {
 			dq := &DelegatingQueue{TypedRateLimitingInterface: ctrl.NewQueue("controller1", nil)}
 			ctrl.NewQueue = func(string, workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
@@ -542,6 +845,90 @@ var _ = Describe("controller", func() {
 			// TODO(community): write this test
 		})
 
+		It("should change the number of workers while running", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			started := make(chan reconcile.Request)
+			release := make(chan struct{})
+			ctrl.Do = reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				started <- req
+				<-release
+				return reconcile.Result{}, nil
+			})
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+
+			for _, name := range []string{"a", "b", "c"} {
+				queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+			}
+			Eventually(started).Should(Receive())
+			Consistently(started, 100*time.Millisecond).ShouldNot(Receive())
+
+			By("adding a worker")
+			ctrl.SetMaxConcurrentReconciles(2)
+			Expect(ctrl.DescribeController().MaxConcurrentReconciles).To(Equal(2))
+			Eventually(started).Should(Receive())
+
+			By("removing a worker")
+			ctrl.SetMaxConcurrentReconciles(1)
+			release <- struct{}{}
+			release <- struct{}{}
+			Eventually(started).Should(Receive())
+			Consistently(started, 100*time.Millisecond).ShouldNot(Receive())
+			close(release)
+		})
+
+		It("should reconcile the requests of the warm-up pass with bounded parallelism", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ctrl.Name = "warm-up"
+			ctrl.MaxConcurrentReconciles = 3
+			var requests []reconcile.Request
+			for _, name := range []string{"a", "b", "c", "d", "a"} {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+			}
+			ctrl.WarmUp = &WarmUp[reconcile.Request]{
+				List: func(context.Context) ([]reconcile.Request, error) {
+					return requests, nil
+				},
+				MaxConcurrentReconciles: 2,
+				QPS:                     1000,
+			}
+
+			var mu sync.Mutex
+			inFlight, maxInFlight := 0, 0
+			reconciled := map[string]int{}
+			ctrl.Do = reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				reconciled[req.Name]++
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return reconcile.Result{}, nil
+			})
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(func() float64 {
+				return testutil.ToFloat64(ctrlmetrics.WarmUpDuration.WithLabelValues("warm-up"))
+			}).Should(BeNumerically(">", 0))
+			Expect(testutil.ToFloat64(ctrlmetrics.WarmUpPending.WithLabelValues("warm-up"))).To(BeZero())
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(reconciled).To(Equal(map[string]int{"a": 1, "b": 1, "c": 1, "d": 1}))
+			Expect(maxInFlight).To(BeNumerically("<=", 2))
+		})
+
 		Context("prometheus metric reconcile_total", func() {
 			var reconcileTotal dto.Metric
 
@@ -748,10 +1135,264 @@ var _ = Describe("controller", func() {
 					return nil
 				}, 2.0).Should(Succeed())
 			})
+
+			It("should record the usage of reconciliations accounting for it", func() {
+				ctrlmetrics.ReconcileObjectsRead.Reset()
+				ctrl.AccountUsage = true
+				c := client.WithUsageAccounting(fake.NewClientBuilder().WithObjects(
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}},
+				).Build())
+				ctrl.Do = reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					return reconcile.Result{}, c.List(ctx, &corev1.ConfigMapList{})
+				})
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				queue.Add(request)
+
+				Eventually(func(g Gomega) {
+					var objectsRead dto.Metric
+					hist := ctrlmetrics.ReconcileObjectsRead.WithLabelValues(ctrl.Name).(prometheus.Histogram)
+					g.Expect(hist.Write(&objectsRead)).To(Succeed())
+					g.Expect(objectsRead.GetHistogram().GetSampleCount()).To(BeEquivalentTo(1))
+					g.Expect(objectsRead.GetHistogram().GetSampleSum()).To(BeEquivalentTo(2))
+				}).Should(Succeed())
+			})
+
+			It("should attach the exemplar of reconciliations to the reconcile time histogram", func() {
+				ctrlmetrics.ReconcileTime.Reset()
+				ctrl.ReconcileExemplars = true
+				ctrl.Do = reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					SetReconcileExemplar(ctx, prometheus.Labels{"trace_id": "0af7651916cd43dd8448eb211c80319c"})
+					return reconcile.Result{}, nil
+				})
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				queue.Add(request)
+
+				Eventually(func(g Gomega) {
+					var reconcileTime dto.Metric
+					hist := ctrlmetrics.ReconcileTime.WithLabelValues(ctrl.Name).(prometheus.Histogram)
+					g.Expect(hist.Write(&reconcileTime)).To(Succeed())
+					var traceIDs []string
+					for _, bucket := range reconcileTime.GetHistogram().GetBucket() {
+						for _, label := range bucket.GetExemplar().GetLabel() {
+							traceIDs = append(traceIDs, label.GetValue())
+						}
+					}
+					g.Expect(traceIDs).To(ConsistOf("0af7651916cd43dd8448eb211c80319c"))
+				}).Should(Succeed())
+			})
+
+			It("should add the UID of the object to the logger and exemplar of reconciliations", func() {
+				ctrlmetrics.ReconcileTime.Reset()
+				ctrl.ReconcileExemplars = true
+				ctrl.ObjectUID = func(_ context.Context, req reconcile.Request) types.UID {
+					return types.UID("uid-of-" + req.Name)
+				}
+				lines := make(chan string, 1)
+				ctrl.LogConstructor = func(_ *reconcile.Request) logr.Logger {
+					return funcr.New(func(_, args string) { lines <- args }, funcr.Options{})
+				}
+				ctrl.Do = reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					logf.FromContext(ctx).Info("reconciling")
+					return reconcile.Result{}, nil
+				})
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				queue.Add(request)
+
+				Eventually(lines).Should(Receive(ContainSubstring(`"uid"="uid-of-` + request.Name + `"`)))
+				Eventually(func(g Gomega) {
+					var reconcileTime dto.Metric
+					hist := ctrlmetrics.ReconcileTime.WithLabelValues(ctrl.Name).(prometheus.Histogram)
+					g.Expect(hist.Write(&reconcileTime)).To(Succeed())
+					exemplars := map[string]string{}
+					for _, bucket := range reconcileTime.GetHistogram().GetBucket() {
+						for _, label := range bucket.GetExemplar().GetLabel() {
+							exemplars[label.GetName()] = label.GetValue()
+						}
+					}
+					g.Expect(exemplars).To(Equal(map[string]string{"object_uid": "uid-of-" + request.Name}))
+				}).Should(Succeed())
+			})
 		})
 	})
 })
 
+var _ = Describe("debounceQueue", func() {
+	var queue workqueue.TypedRateLimitingInterface[string]
+	var clk *testingclock.FakeClock
+
+	BeforeEach(func() {
+		queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+		DeferCleanup(queue.ShutDown)
+		clk = testingclock.NewFakeClock(time.Now())
+	})
+
+	newQueue := func(quietPeriod, maxDelay time.Duration) *debounceQueue[string] {
+		q := newDebounceQueue(queue, quietPeriod, maxDelay)
+		q.clock = clk
+		return q
+	}
+
+	// step advances the clock, and waits for the timer firing at the new
+	// time, if any, to be handled.
+	step := func(q *debounceQueue[string], d time.Duration) {
+		clk.Step(d)
+		Eventually(func() bool { return clk.HasWaiters() || q.Len() > 0 }).Should(BeTrue())
+	}
+
+	It("should collapse the requests added during the quiet period", func() {
+		q := newQueue(50*time.Millisecond, time.Minute)
+		for i := 0; i < 5; i++ {
+			if i > 0 {
+				clk.Step(10 * time.Millisecond)
+			}
+			q.Add("a")
+		}
+
+		By("extending the quiet period with every request")
+		step(q, 10*time.Millisecond)
+		Expect(q.Len()).To(BeZero())
+
+		step(q, 40*time.Millisecond)
+		Expect(q.Len()).To(Equal(1))
+		Expect(clk.HasWaiters()).To(BeFalse())
+	})
+
+	It("should release requests once the maximum delay elapsed", func() {
+		q := newQueue(50*time.Millisecond, 120*time.Millisecond)
+		q.Add("a")
+		// The timer fires at 50ms, 90ms and 120ms, while a request is added
+		// 10ms before each.
+		for i, d := range []time.Duration{40 * time.Millisecond, 30 * time.Millisecond, 20 * time.Millisecond} {
+			clk.Step(d)
+			q.Add("a")
+			step(q, 10*time.Millisecond)
+			if i < 2 {
+				Expect(q.Len()).To(BeZero())
+			}
+		}
+		Expect(q.Len()).To(Equal(1))
+	})
+
+	It("should not delay requeues", func() {
+		q := newQueue(time.Minute, time.Minute)
+		q.AddAfter("a", 0)
+		Expect(q.Len()).To(Equal(1))
+	})
+
+	It("should drop the requests held on shutdown", func() {
+		q := newQueue(10*time.Millisecond, time.Minute)
+		q.Add("a")
+		q.ShutDown()
+		q.Add("b")
+		Expect(clk.HasWaiters()).To(BeFalse())
+		clk.Step(time.Minute)
+		Expect(q.Len()).To(BeZero())
+	})
+})
+
+var _ = Describe("sheddingQueue", func() {
+	var queue workqueue.TypedRateLimitingInterface[string]
+
+	BeforeEach(func() {
+		queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+		DeferCleanup(queue.ShutDown)
+	})
+
+	It("should hold back requeues under pressure and release them once it subsides", func() {
+		q := newSheddingQueue(queue, "shedding-test")
+		var pressure atomic.Bool
+		pressure.Store(true)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go q.monitor(ctx, logr.Discard(), pressure.Load, 10*time.Millisecond)
+		Eventually(q.isShedding).Should(BeTrue())
+
+		q.AddAfter("a", 0)
+		q.AddAfter("a", 0)
+		q.Add("b")
+		Expect(q.Len()).To(Equal(1))
+		Expect(testutil.ToFloat64(ctrlmetrics.ShedRequests.WithLabelValues("shedding-test"))).To(Equal(1.0))
+
+		pressure.Store(false)
+		Eventually(q.Len).Should(Equal(2))
+		Expect(testutil.ToFloat64(ctrlmetrics.ShedRequests.WithLabelValues("shedding-test"))).To(Equal(0.0))
+
+		q.AddAfter("c", 0)
+		Expect(q.Len()).To(Equal(3))
+	})
+
+	It("should hold back requests triggered by resyncs under pressure", func() {
+		q := newSheddingQueue(queue, "shedding-resync-test")
+		q.setShedding(true)
+
+		resynced := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", ResourceVersion: "1"}}
+		changed := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", ResourceVersion: "2"}}
+		internalsource.WithUpdateTrigger[string](q, resynced, resynced).Add("a")
+		internalsource.WithUpdateTrigger[string](q, resynced, changed).Add("b")
+		Expect(q.Len()).To(Equal(1))
+
+		q.setShedding(false)
+		Expect(q.Len()).To(Equal(2))
+	})
+})
+
+var _ = Describe("requeueTimers", func() {
+	It("should expire the requests once their tick passed", func() {
+		t := newRequeueTimers[string](time.Second)
+		t.add("a", 2*time.Second)
+		t.add("b", 5*time.Second)
+
+		Expect(t.expire(t.origin.Add(time.Second))).To(BeEmpty())
+		Expect(t.expire(t.origin.Add(3 * time.Second))).To(ConsistOf("a"))
+		Expect(t.expire(t.origin.Add(10 * time.Second))).To(ConsistOf("b"))
+		Expect(t.expire(t.origin.Add(20 * time.Second))).To(BeEmpty())
+	})
+
+	It("should replace and cancel timers", func() {
+		t := newRequeueTimers[string](time.Second)
+		t.add("a", 2*time.Second)
+		t.add("a", 5*time.Second)
+		t.add("b", 2*time.Second)
+		t.remove("b")
+
+		Expect(t.expire(t.origin.Add(3 * time.Second))).To(BeEmpty())
+		Expect(t.expire(t.origin.Add(10 * time.Second))).To(ConsistOf("a"))
+		Expect(t.buckets).To(BeEmpty())
+		Expect(t.ticks).To(BeEmpty())
+	})
+
+	It("should queue the expired requests", func() {
+		queue := workqueue.NewTyped[string]()
+		DeferCleanup(queue.ShutDown)
+		t := newRequeueTimers[string](10 * time.Millisecond)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go t.run(ctx, queue)
+
+		t.add("a", 20*time.Millisecond)
+		Eventually(queue.Len).Should(Equal(1))
+	})
+})
+
 var _ = Describe("ReconcileIDFromContext function", func() {
 	It("should return an empty string if there is nothing in the context", func() {
 		ctx := context.Background()
@@ -769,6 +1410,45 @@ var _ = Describe("ReconcileIDFromContext function", func() {
 	})
 })
 
+type recordingRateLimiter struct {
+	delay time.Duration
+
+	mu        sync.Mutex
+	when      int
+	forgotten int
+}
+
+func (r *recordingRateLimiter) When(reconcile.Request) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.when++
+	return r.delay
+}
+
+func (r *recordingRateLimiter) Forget(reconcile.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forgotten++
+}
+
+func (r *recordingRateLimiter) NumRequeues(reconcile.Request) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.when
+}
+
+func (r *recordingRateLimiter) getWhen() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.when
+}
+
+func (r *recordingRateLimiter) getForgotten() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.forgotten
+}
+
 type DelegatingQueue struct {
 	workqueue.TypedRateLimitingInterface[reconcile.Request]
 	mu sync.Mutex
@@ -847,6 +1527,12 @@ func (f *fakeReconciler) Reconcile(_ context.Context, r reconcile.Request) (reco
 	return res.Result, res.Err
 }
 
+type unnamedSource struct{}
+
+func (s *unnamedSource) Start(context.Context, workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	return nil
+}
+
 type singnallingSourceWrapper struct {
 	cacheSyncDone chan struct{}
 	source.SyncingSource
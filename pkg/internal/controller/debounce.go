@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+)
+
+// debounceQueue wraps the queue of a controller and holds the requests
+// added to it until no further request for the same key was added during
+// the quiet period, or the maximum delay elapsed since the first one.
+// Requeues of the controller, which use AddRateLimited and AddAfter, are
+// not delayed.
+type debounceQueue[request comparable] struct {
+	workqueue.TypedRateLimitingInterface[request]
+
+	quietPeriod time.Duration
+	maxDelay    time.Duration
+	clock       clock.WithDelayedExecution
+
+	mu       sync.Mutex
+	pending  map[request]*pendingRequest
+	shutdown bool
+}
+
+// pendingRequest is a request held by a debounceQueue.
+type pendingRequest struct {
+	first time.Time
+	last  time.Time
+	timer clock.Timer
+}
+
+func newDebounceQueue[request comparable](queue workqueue.TypedRateLimitingInterface[request], quietPeriod, maxDelay time.Duration) *debounceQueue[request] {
+	return &debounceQueue[request]{
+		TypedRateLimitingInterface: queue,
+		quietPeriod:                quietPeriod,
+		maxDelay:                   maxDelay,
+		clock:                      clock.RealClock{},
+		pending:                    map[request]*pendingRequest{},
+	}
+}
+
+// Add holds req until its quiet period elapsed.
+func (q *debounceQueue[request]) Add(req request) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shutdown {
+		return
+	}
+
+	now := q.clock.Now()
+	if p, ok := q.pending[req]; ok {
+		p.last = now
+		return
+	}
+	p := &pendingRequest{first: now, last: now}
+	q.schedule(req, p, q.quietPeriod)
+	q.pending[req] = p
+}
+
+// schedule releases req after wait. release runs in its own goroutine, and
+// every wait uses a new timer, since fake clocks run the functions of their
+// timers while holding their lock, and can't fire a timer twice.
+func (q *debounceQueue[request]) schedule(req request, p *pendingRequest, wait time.Duration) {
+	p.timer = q.clock.AfterFunc(wait, func() { go q.release(req, p) })
+}
+
+// release adds req to the queue if its quiet period, or its maximum delay,
+// elapsed, and waits for the rest of its quiet period otherwise.
+func (q *debounceQueue[request]) release(req request, p *pendingRequest) {
+	q.mu.Lock()
+	if q.pending[req] != p {
+		q.mu.Unlock()
+		return
+	}
+	now := q.clock.Now()
+	deadline := p.last.Add(q.quietPeriod)
+	if maxDeadline := p.first.Add(q.maxDelay); maxDeadline.Before(deadline) {
+		deadline = maxDeadline
+	}
+	if wait := deadline.Sub(now); wait > 0 {
+		q.schedule(req, p, wait)
+		q.mu.Unlock()
+		return
+	}
+	delete(q.pending, req)
+	q.mu.Unlock()
+
+	q.TypedRateLimitingInterface.Add(req)
+}
+
+// ShutDown drops the requests held and shuts the queue down.
+func (q *debounceQueue[request]) ShutDown() {
+	q.mu.Lock()
+	q.shutdown = true
+	for req, p := range q.pending {
+		p.timer.Stop()
+		delete(q.pending, req)
+	}
+	q.mu.Unlock()
+	q.TypedRateLimitingInterface.ShutDown()
+}
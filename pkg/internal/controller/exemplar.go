@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exemplarKey is a context.Context Value key. Its associated value should
+// be a *exemplarHolder.
+type exemplarKey struct{}
+
+// exemplarHolder holds the exemplar set during a reconciliation.
+type exemplarHolder struct {
+	mu     sync.Mutex
+	labels prometheus.Labels
+}
+
+// SetReconcileExemplar sets the exemplar attached to the metrics of the
+// current reconciliation, typically the trace ID of its span. It is a no-op
+// if ctx is not the context of a reconciliation of a controller with
+// exemplars enabled.
+func SetReconcileExemplar(ctx context.Context, labels prometheus.Labels) {
+	h, ok := ctx.Value(exemplarKey{}).(*exemplarHolder)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.labels = labels
+}
+
+func withExemplarHolder(ctx context.Context) (context.Context, *exemplarHolder) {
+	h := &exemplarHolder{}
+	return context.WithValue(ctx, exemplarKey{}, h), h
+}
+
+// setDefault sets the exemplar of the reconciliation, unless it has already
+// been set.
+func (h *exemplarHolder) setDefault(labels prometheus.Labels) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.labels == nil {
+		h.labels = labels
+	}
+}
+
+func (h *exemplarHolder) get() prometheus.Labels {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.labels
+}
+
+// observe records value in observer, with the exemplar of h if any.
+func (h *exemplarHolder) observe(observer prometheus.Observer, value float64) {
+	labels := h.get()
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok && len(labels) > 0 {
+		eo.ObserveWithExemplar(value, labels)
+		return
+	}
+	observer.Observe(value)
+}
@@ -75,6 +75,78 @@ var (
 		Name: "controller_runtime_active_workers",
 		Help: "Number of currently used workers per controller",
 	}, []string{"controller"})
+
+	// ReconcileAPIRequests is a prometheus metric which keeps track of the
+	// number of API requests made by reconciliations of controllers that
+	// account for their usage.
+	ReconcileAPIRequests = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_runtime_reconcile_api_requests",
+		Help:    "Number of API requests per reconciliation per controller",
+		Buckets: []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500},
+	}, []string{"controller"})
+
+	// ReconcileObjectsRead is a prometheus metric which keeps track of the
+	// number of objects read by reconciliations of controllers that account
+	// for their usage.
+	ReconcileObjectsRead = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_runtime_reconcile_objects_read",
+		Help:    "Number of objects read per reconciliation per controller",
+		Buckets: []float64{0, 1, 2, 5, 10, 50, 100, 500, 1000, 5000, 10000},
+	}, []string{"controller"})
+
+	// ReconcileBytesWritten is a prometheus metric which keeps track of the
+	// number of bytes sent to the API server by reconciliations of
+	// controllers that account for their usage.
+	ReconcileBytesWritten = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_runtime_reconcile_bytes_written",
+		Help:    "Number of bytes written to the API server per reconciliation per controller",
+		Buckets: prometheus.ExponentialBuckets(256, 4, 8),
+	}, []string{"controller"})
+
+	// SuspendedObjects is a prometheus metric which holds the number of
+	// objects whose reconciliation is suspended per controller.
+	SuspendedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_suspended_objects",
+		Help: "Number of objects whose reconciliation is suspended per controller",
+	}, []string{"controller"})
+
+	// WarmUpPending is a prometheus gauge metric which holds the number of
+	// requests of the warm-up pass of a controller that are not reconciled
+	// yet.
+	WarmUpPending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_warmup_pending_requests",
+		Help: "Number of requests of the warm-up pass not reconciled yet per controller",
+	}, []string{"controller"})
+
+	// WarmUpDuration is a prometheus gauge metric which is set to the
+	// duration of the warm-up pass of a controller once it completed.
+	WarmUpDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_warmup_duration_seconds",
+		Help: "Duration of the completed warm-up pass per controller",
+	}, []string{"controller"})
+
+	// QuarantinedSources is a prometheus gauge metric which holds the number
+	// of sources of a controller quarantined because their informer keeps
+	// failing.
+	QuarantinedSources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_quarantined_sources",
+		Help: "Number of sources quarantined because their informer keeps failing per controller",
+	}, []string{"controller"})
+
+	// ShedRequests is a prometheus gauge metric which holds the number of
+	// requeues of a controller held back while the process is under memory
+	// pressure.
+	ShedRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_shed_requests",
+		Help: "Number of requeues held back under memory pressure per controller",
+	}, []string{"controller"})
+
+	// PausedEvents is a prometheus counter metric which holds the number of
+	// events of paused objects that were not enqueued.
+	PausedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_paused_events_total",
+		Help: "Total number of events of paused objects not enqueued per controller",
+	}, []string{"controller"})
 )
 
 func init() {
@@ -86,9 +158,46 @@ func init() {
 		ReconcileTime,
 		WorkerCount,
 		ActiveWorkers,
+		ReconcileAPIRequests,
+		ReconcileObjectsRead,
+		ReconcileBytesWritten,
+		SuspendedObjects,
+		WarmUpPending,
+		WarmUpDuration,
 		// expose process metrics like CPU, Memory, file descriptor usage etc.
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 		// expose Go runtime metrics like GC stats, memory stats etc.
 		collectors.NewGoCollector(),
+		QuarantinedSources,
+		ShedRequests,
+		PausedEvents,
 	)
 }
+
+// DeleteController deletes the series of the metrics of the controller with
+// the given controller label.
+func DeleteController(controller string) {
+	labels := prometheus.Labels{"controller": controller}
+	for _, vec := range []interface {
+		DeletePartialMatch(labels prometheus.Labels) int
+	}{
+		ReconcileTotal,
+		ReconcileErrors,
+		TerminalReconcileErrors,
+		ReconcilePanics,
+		ReconcileTime,
+		WorkerCount,
+		ActiveWorkers,
+		ReconcileAPIRequests,
+		ReconcileObjectsRead,
+		ReconcileBytesWritten,
+		SuspendedObjects,
+		WarmUpPending,
+		WarmUpDuration,
+		QuarantinedSources,
+		ShedRequests,
+		PausedEvents,
+	} {
+		vec.DeletePartialMatch(labels)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ErrNameAlreadyExists is returned, wrapped, when a controller is created
+// with a name that is already used by another controller.
+var ErrNameAlreadyExists = errors.New("controller name already exists")
+
+var nameLock sync.Mutex
+var usedNames sets.Set[string]
+
+// ReserveName reserves name for a controller and returns it. If name is
+// already in use, ReserveName returns an error wrapping ErrNameAlreadyExists,
+// or, if suffix is set, reserves and returns the first free suffixed name,
+// e.g. name_2.
+func ReserveName(name string, suffix bool) (string, error) {
+	if !suffix {
+		return name, reserveName(name)
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxSuffixAttempts; attempt++ {
+		candidate := suffixedName(name, attempt)
+		if err = reserveName(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", err
+}
+
+// ReleaseName releases a name reserved with ReserveName, for a controller
+// that failed to be created.
+func ReleaseName(name string) {
+	nameLock.Lock()
+	defer nameLock.Unlock()
+	usedNames.Delete(name)
+}
+
+func reserveName(name string) error {
+	nameLock.Lock()
+	defer nameLock.Unlock()
+	if usedNames == nil {
+		usedNames = sets.Set[string]{}
+	}
+
+	if usedNames.Has(name) {
+		return fmt.Errorf("controller with name %s already exists. Controller names must be unique to avoid multiple controllers reporting to the same metric. "+
+			"Set a different name, or set SuffixDuplicateNames to append a numeric suffix: %w", name, ErrNameAlreadyExists)
+	}
+
+	usedNames.Insert(name)
+
+	return nil
+}
+
+// suffixedName returns the name to try for the attempt-th controller that
+// wants to use name, e.g. name, name_2, name_3 and so on.
+func suffixedName(name string, attempt int) string {
+	if attempt <= 1 {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", name, attempt)
+}
+
+// maxSuffixAttempts bounds the number of suffixed names tried before giving up.
+const maxSuffixAttempts = 100
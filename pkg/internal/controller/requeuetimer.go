@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// requeueTimers is a timer wheel holding the requests to reconcile again
+// after a delay unless they are reconciled before. Deadlines are rounded up
+// to the next tick of the resolution, and the requests of a tick are kept
+// in a single bucket, so that registering and cancelling a timer is cheap
+// and a single goroutine fires them all.
+type requeueTimers[request comparable] struct {
+	resolution time.Duration
+	origin     time.Time
+
+	mu sync.Mutex
+	// buckets holds the requests to queue per tick.
+	buckets map[int64]map[request]struct{}
+	// ticks holds the tick of each request in buckets.
+	ticks map[request]int64
+	// fired is the last tick whose bucket was queued.
+	fired int64
+}
+
+func newRequeueTimers[request comparable](resolution time.Duration) *requeueTimers[request] {
+	return &requeueTimers[request]{
+		resolution: resolution,
+		origin:     time.Now(),
+		buckets:    map[int64]map[request]struct{}{},
+		ticks:      map[request]int64{},
+	}
+}
+
+// tickOf returns the first tick at or after the given time.
+func (t *requeueTimers[request]) tickOf(at time.Time) int64 {
+	elapsed := at.Sub(t.origin)
+	tick := int64(elapsed / t.resolution)
+	if elapsed%t.resolution != 0 {
+		tick++
+	}
+	return tick
+}
+
+// add registers req to be queued after the given duration, replacing any
+// timer it has.
+func (t *requeueTimers[request]) add(req request, after time.Duration) {
+	tick := t.tickOf(time.Now().Add(after))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(req)
+	tick = max(tick, t.fired+1)
+	bucket, ok := t.buckets[tick]
+	if !ok {
+		bucket = map[request]struct{}{}
+		t.buckets[tick] = bucket
+	}
+	bucket[req] = struct{}{}
+	t.ticks[req] = tick
+}
+
+// remove cancels the timer of req, if any.
+func (t *requeueTimers[request]) remove(req request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(req)
+}
+
+func (t *requeueTimers[request]) removeLocked(req request) {
+	tick, ok := t.ticks[req]
+	if !ok {
+		return
+	}
+	delete(t.ticks, req)
+	delete(t.buckets[tick], req)
+	if len(t.buckets[tick]) == 0 {
+		delete(t.buckets, tick)
+	}
+}
+
+// expire removes and returns the requests whose tick has passed at now.
+func (t *requeueTimers[request]) expire(now time.Time) []request {
+	current := int64(now.Sub(t.origin) / t.resolution)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var expired []request
+	for ; t.fired < current; t.fired++ {
+		for req := range t.buckets[t.fired+1] {
+			expired = append(expired, req)
+			delete(t.ticks, req)
+		}
+		delete(t.buckets, t.fired+1)
+	}
+	return expired
+}
+
+// run queues the expired requests every resolution until ctx is done.
+func (t *requeueTimers[request]) run(ctx context.Context, queue workqueue.TypedInterface[request]) {
+	ticker := time.NewTicker(t.resolution)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, req := range t.expire(now) {
+				queue.Add(req)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	internal "sigs.k8s.io/controller-runtime/pkg/internal/source"
+)
+
+// sheddingQueue wraps the queue of a controller and holds back the requests
+// added with AddAfter, i.e. the requeues requested with RequeueAfter, and the
+// requests triggered by periodic resyncs of informers, while the process is
+// under memory pressure. Each held request is kept once, however many times
+// it is requeued, and the held requests are added to the queue once the
+// pressure subsides. Requests triggered by changes and retries of failed
+// reconciles are not held.
+type sheddingQueue[request comparable] struct {
+	workqueue.TypedRateLimitingInterface[request]
+
+	metricsLabel string
+
+	mu       sync.Mutex
+	shedding bool
+	held     map[request]struct{}
+	shutdown bool
+}
+
+var _ internal.ResyncHolder[string] = &sheddingQueue[string]{}
+
+func newSheddingQueue[request comparable](queue workqueue.TypedRateLimitingInterface[request], metricsLabel string) *sheddingQueue[request] {
+	return &sheddingQueue[request]{
+		TypedRateLimitingInterface: queue,
+		metricsLabel:               metricsLabel,
+		held:                       map[request]struct{}{},
+	}
+}
+
+// AddAfter holds req while under pressure, and adds it after duration
+// otherwise.
+func (q *sheddingQueue[request]) AddAfter(req request, duration time.Duration) {
+	if q.hold(req) {
+		return
+	}
+	q.TypedRateLimitingInterface.AddAfter(req, duration)
+}
+
+// HoldResync implements source.ResyncHolder.
+func (q *sheddingQueue[request]) HoldResync(req request) bool {
+	return q.hold(req)
+}
+
+// hold holds req and returns true while under pressure. The pressure is
+// checked under the lock, so that requests can't be held after setShedding
+// released the held requests.
+func (q *sheddingQueue[request]) hold(req request) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shutdown {
+		return true
+	}
+	if !q.shedding {
+		return false
+	}
+	q.held[req] = struct{}{}
+	ctrlmetrics.ShedRequests.WithLabelValues(q.metricsLabel).Set(float64(len(q.held)))
+	return true
+}
+
+// isShedding returns whether requests are being held.
+func (q *sheddingQueue[request]) isShedding() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shedding
+}
+
+// setShedding starts or stops holding requests. When it stops, the held
+// requests are added to the queue.
+func (q *sheddingQueue[request]) setShedding(shedding bool) {
+	q.mu.Lock()
+	q.shedding = shedding
+	if shedding {
+		q.mu.Unlock()
+		return
+	}
+	held := q.held
+	q.held = map[request]struct{}{}
+	q.mu.Unlock()
+	ctrlmetrics.ShedRequests.WithLabelValues(q.metricsLabel).Set(0)
+
+	for req := range held {
+		q.TypedRateLimitingInterface.Add(req)
+	}
+}
+
+// monitor checks whether the process is under pressure every interval
+// until ctx is done, and starts or stops holding requests accordingly.
+func (q *sheddingQueue[request]) monitor(ctx context.Context, log logr.Logger, underPressure func() bool, interval time.Duration) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		pressure := underPressure()
+		if pressure == q.isShedding() {
+			return
+		}
+		if pressure {
+			log.Info("Memory pressure detected, holding back requeues and resyncs")
+		} else {
+			q.mu.Lock()
+			held := len(q.held)
+			q.mu.Unlock()
+			log.Info("Memory pressure subsided, releasing held requests", "requests", held)
+		}
+		q.setShedding(pressure)
+	}, interval)
+}
+
+// ShutDown drops the requests held and shuts the queue down.
+func (q *sheddingQueue[request]) ShutDown() {
+	q.mu.Lock()
+	q.shutdown = true
+	q.held = map[request]struct{}{}
+	q.mu.Unlock()
+	q.TypedRateLimitingInterface.ShutDown()
+}
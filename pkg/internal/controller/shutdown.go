@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ShutdownReport lists the requests a controller did not complete when it
+// stopped.
+type ShutdownReport[request comparable] struct {
+	// InFlight are the requests still being reconciled when the controller
+	// stopped waiting for its workers, with the time their reconcile
+	// started. It is only set if the wait timed out.
+	InFlight map[request]time.Time
+
+	// Queued are the requests that were queued when the controller stopped,
+	// which were not reconciled.
+	Queued []request
+
+	// TimedOut is whether the wait for the in-flight reconciles timed out.
+	TimedOut bool
+}
+
+// waitForWorkers waits for the workers in wg to finish, for at most
+// ShutdownTimeout if it is positive, and returns whether the wait timed out.
+func (c *Controller[request]) waitForWorkers(wg *sync.WaitGroup) bool {
+	if c.ShutdownTimeout <= 0 {
+		wg.Wait()
+		return false
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return false
+	case <-time.After(c.ShutdownTimeout):
+		c.LogConstructor(nil).Info("Timed out waiting for workers to finish", "timeout", c.ShutdownTimeout)
+		return true
+	}
+}
+
+// drain records req as queued at shutdown rather than reconciling it, and
+// returns true, once ctx is done.
+func (c *Controller[request]) drain(ctx context.Context, req request) bool {
+	c.shutdownMu.Lock()
+	defer c.shutdownMu.Unlock()
+	if ctx.Err() != nil {
+		c.drained = append(c.drained, req)
+		return true
+	}
+	if c.inFlight == nil {
+		c.inFlight = map[request]time.Time{}
+	}
+	c.inFlight[req] = time.Now()
+	return false
+}
+
+// untrack records that the reconcile of req completed.
+func (c *Controller[request]) untrack(req request) {
+	c.shutdownMu.Lock()
+	defer c.shutdownMu.Unlock()
+	delete(c.inFlight, req)
+}
+
+// reportShutdown drains the requests left in the queue, e.g. because the
+// workers are stuck, and reports the requests that were not completed.
+func (c *Controller[request]) reportShutdown(timedOut bool) {
+	if timedOut {
+		for {
+			req, shutdown := c.Queue.Get()
+			if shutdown {
+				break
+			}
+			c.Queue.Done(req)
+			c.shutdownMu.Lock()
+			c.drained = append(c.drained, req)
+			c.shutdownMu.Unlock()
+		}
+	}
+
+	c.shutdownMu.Lock()
+	report := ShutdownReport[request]{
+		Queued:   c.drained,
+		TimedOut: timedOut,
+	}
+	if timedOut && len(c.inFlight) > 0 {
+		report.InFlight = make(map[request]time.Time, len(c.inFlight))
+		for req, started := range c.inFlight {
+			report.InFlight[req] = started
+		}
+	}
+	c.drained = nil
+	c.shutdownMu.Unlock()
+
+	c.ReportShutdown(report)
+}
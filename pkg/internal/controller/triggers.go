@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+
+	internal "sigs.k8s.io/controller-runtime/pkg/internal/source"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// maxTriggersPerRequest bounds the number of triggers kept for a single
+// request, dropping the oldest ones, so that a hot object doesn't make
+// the triggers of a request grow while it waits in the queue.
+const maxTriggersPerRequest = 16
+
+// triggerQueue wraps the queue of a controller and records the triggers of
+// the requests added to it by sources.
+type triggerQueue[request comparable] struct {
+	workqueue.TypedRateLimitingInterface[request]
+
+	mu       sync.Mutex
+	triggers map[request][]reconcile.Trigger
+}
+
+func newTriggerQueue[request comparable](queue workqueue.TypedRateLimitingInterface[request]) *triggerQueue[request] {
+	return &triggerQueue[request]{
+		TypedRateLimitingInterface: queue,
+		triggers:                   map[request][]reconcile.Trigger{},
+	}
+}
+
+// RecordTrigger implements source.TriggerRecorder.
+func (q *triggerQueue[request]) RecordTrigger(req request, trigger reconcile.Trigger) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	triggers := append(q.triggers[req], trigger)
+	if len(triggers) > maxTriggersPerRequest {
+		triggers = triggers[len(triggers)-maxTriggersPerRequest:]
+	}
+	q.triggers[req] = triggers
+}
+
+// HoldResync implements source.ResyncHolder, delegating to the wrapped
+// queue.
+func (q *triggerQueue[request]) HoldResync(req request) bool {
+	if holder, ok := q.TypedRateLimitingInterface.(internal.ResyncHolder[request]); ok {
+		return holder.HoldResync(req)
+	}
+	return false
+}
+
+// popTriggers returns and forgets the triggers recorded for req.
+func (q *triggerQueue[request]) popTriggers(req request) []reconcile.Trigger {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	triggers := q.triggers[req]
+	delete(q.triggers, req)
+	return triggers
+}
+
+// onlyResyncs returns whether all triggers are resyncs. It returns false
+// for requests without triggers, e.g. requeues.
+func onlyResyncs(triggers []reconcile.Trigger) bool {
+	for _, trigger := range triggers {
+		if !trigger.Resync {
+			return false
+		}
+	}
+	return len(triggers) > 0
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+)
+
+// WarmUp configures the warm-up pass of a controller.
+type WarmUp[request comparable] struct {
+	// List returns the requests to reconcile.
+	List func(ctx context.Context) ([]request, error)
+
+	// MaxConcurrentReconciles is the maximum number of requests of the
+	// pass queued or reconciled at the same time.
+	MaxConcurrentReconciles int
+
+	// QPS is the rate at which requests of the pass are queued.
+	QPS float64
+}
+
+// warmUpState tracks the requests of the warm-up pass that are queued or
+// being reconciled.
+type warmUpState[request comparable] struct {
+	mu       sync.Mutex
+	inFlight map[request]struct{}
+	slots    chan struct{}
+}
+
+// warmUp queues the requests listed by the WarmUp of the controller, at
+// most WarmUp.MaxConcurrentReconciles at a time and at WarmUp.QPS, so that
+// the initial reconciliation of all objects doesn't starve requests
+// triggered by changes.
+func (c *Controller[request]) warmUp(ctx context.Context) {
+	log := c.LogConstructor(nil)
+	start := time.Now()
+	requests, err := c.WarmUp.List(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error(err, "Failed to list the requests of the warm-up pass")
+		}
+		return
+	}
+	requests = unique(requests)
+	log.Info("Starting warm-up pass", "requests", len(requests))
+	ctrlmetrics.WarmUpPending.WithLabelValues(c.metricsLabel()).Set(float64(len(requests)))
+
+	state := &warmUpState[request]{
+		inFlight: make(map[request]struct{}, c.WarmUp.MaxConcurrentReconciles),
+		slots:    make(chan struct{}, c.WarmUp.MaxConcurrentReconciles),
+	}
+	c.warmUpMu.Lock()
+	c.warmUpState = state
+	c.warmUpMu.Unlock()
+	defer func() {
+		c.warmUpMu.Lock()
+		c.warmUpState = nil
+		c.warmUpMu.Unlock()
+	}()
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / c.WarmUp.QPS))
+	defer ticker.Stop()
+	for i, req := range requests {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case state.slots <- struct{}{}:
+		}
+
+		state.mu.Lock()
+		state.inFlight[req] = struct{}{}
+		state.mu.Unlock()
+		c.Queue.Add(req)
+	}
+
+	// Wait for the last requests to be reconciled.
+	for i := 0; i < cap(state.slots); i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case state.slots <- struct{}{}:
+		}
+	}
+	duration := time.Since(start)
+	ctrlmetrics.WarmUpDuration.WithLabelValues(c.metricsLabel()).Set(duration.Seconds())
+	log.Info("Warm-up pass completed", "duration", duration)
+}
+
+// warmedUp accounts for req being reconciled, if it is part of the warm-up
+// pass in progress.
+func (c *Controller[request]) warmedUp(req request) {
+	c.warmUpMu.Lock()
+	state := c.warmUpState
+	c.warmUpMu.Unlock()
+	if state == nil {
+		return
+	}
+
+	state.mu.Lock()
+	_, ok := state.inFlight[req]
+	delete(state.inFlight, req)
+	state.mu.Unlock()
+	if ok {
+		ctrlmetrics.WarmUpPending.WithLabelValues(c.metricsLabel()).Dec()
+		<-state.slots
+	}
+}
+
+// unique returns requests without duplicates, in order.
+func unique[request comparable](requests []request) []request {
+	seen := make(map[request]struct{}, len(requests))
+	out := make([]request, 0, len(requests))
+	for _, req := range requests {
+		if _, ok := seen[req]; ok {
+			continue
+		}
+		seen[req] = struct{}{}
+		out = append(out, req)
+	}
+	return out
+}
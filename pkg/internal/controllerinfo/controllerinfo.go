@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllerinfo holds the types describing controllers, shared by
+// the controllers and the manager without either importing the other.
+package controllerinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ControllerInfo describes a controller, as reported to the Manager.
+type ControllerInfo struct {
+	// Name is the unique name of the controller, as used in logs and metrics.
+	Name string
+
+	// Sources describes the sources the controller watches, e.g.
+	// "kind source: *v1.Pod".
+	Sources []string
+
+	// Watches describes the sources the controller watches in more detail,
+	// in the same order as Sources.
+	Watches []WatchInfo
+
+	// MaxConcurrentReconciles is the maximum number of concurrent Reconciles
+	// the controller runs.
+	MaxConcurrentReconciles int
+
+	// NeedLeaderElection is true if the controller only runs while the
+	// manager holds the leader lease.
+	NeedLeaderElection bool
+
+	// Permissions are the permissions the controller requires, e.g. to watch
+	// its sources. See manager.RequiredPermissions and manager.CheckPermissions.
+	Permissions []Permission
+
+	// QueueDepth is the number of requests waiting to be reconciled. A deep
+	// queue means the controller lags behind the events it watches. It is
+	// zero until the controller has started.
+	QueueDepth int
+
+	// QuarantinedSources are the sources, among Sources, whose events are
+	// dropped because their informer keeps failing. See
+	// manager.SourceQuarantineChecker.
+	QuarantinedSources []string
+}
+
+// WatchInfo describes a source watched by a controller.
+type WatchInfo struct {
+	// Source describes the source, e.g. "kind source: *v1.Pod".
+	Source string `json:"source"`
+
+	// Type is the Go type of the objects watched by the source, e.g.
+	// "*v1.Pod", if it watches objects of a single type.
+	Type string `json:"type,omitempty"`
+
+	// Handler describes the event handler of the source, e.g.
+	// "EnqueueRequestForOwner(ReplicaSet.apps)", if known.
+	Handler string `json:"handler,omitempty"`
+
+	// Predicates describes the predicates filtering the events of the
+	// source, e.g. "GenerationChangedPredicate".
+	Predicates []string `json:"predicates,omitempty"`
+}
+
+// Permission is a set of verbs allowed on a kind of object.
+type Permission struct {
+	schema.GroupVersionKind
+
+	// Namespace is the namespace the permission applies to. Empty means
+	// all namespaces for namespaced kinds.
+	Namespace string
+
+	// Verbs are the verbs allowed on the kind, e.g. "get", "list", "watch".
+	Verbs []string
+}
+
+// String returns a human readable representation of the permission.
+func (p Permission) String() string {
+	gk := p.GroupVersionKind.GroupKind().String()
+	if p.Namespace != "" {
+		return fmt.Sprintf("%s in namespace %s: %s", gk, p.Namespace, strings.Join(p.Verbs, ","))
+	}
+	return fmt.Sprintf("%s: %s", gk, strings.Join(p.Verbs, ","))
+}
@@ -46,3 +46,26 @@ func Acquire(path string) error {
 	}
 	return err
 }
+
+// Lock acquires a lock on a file until the returned function is called,
+// waiting for it to be released by its holder if wait is set. Locks are
+// held by open files, so a process can't lock a file twice.
+func Lock(path string, wait bool) (func() error, error) {
+	fd, err := unix.Open(path, unix.O_CREAT|unix.O_RDWR|unix.O_CLOEXEC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	how := unix.LOCK_EX
+	if !wait {
+		how |= unix.LOCK_NB
+	}
+	if err := unix.Flock(fd, how); err != nil {
+		_ = unix.Close(fd)
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			return nil, fmt.Errorf("cannot lock file %q: %w", path, ErrAlreadyLocked)
+		}
+		return nil, err
+	}
+	return func() error { return unix.Close(fd) }, nil
+}
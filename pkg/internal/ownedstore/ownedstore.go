@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ownedstore implements the storage of small amounts of state per
+// object, either as an annotation on the object or in a ConfigMap
+// controlled by it, shared by the stores of the inventory and checkpoint
+// packages and the AnnotationStore of the objectlock package.
+package ownedstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// OwnerUIDAnnotation records the UID of the owner of a ConfigMap in another
+// namespace than the owner, which can't have an owner reference to it.
+const OwnerUIDAnnotation = "controller-runtime.sigs.k8s.io/owner-uid"
+
+// SetAnnotation sets the annotation key of obj to value, or removes it if
+// value is nil, and patches obj in place. It does nothing if the annotation
+// is already up to date.
+func SetAnnotation(ctx context.Context, c client.Client, obj client.Object, key string, value *string, opts ...client.MergeFromOption) error {
+	current, found := obj.GetAnnotations()[key]
+	if (value == nil && !found) || (value != nil && found && current == *value) {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), opts...)
+	annotations := obj.GetAnnotations()
+	if value == nil {
+		delete(annotations, key)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = *value
+	}
+	obj.SetAnnotations(annotations)
+	return c.Patch(ctx, obj, patch)
+}
+
+// ConfigMaps reads and writes the ConfigMaps controlled by owners.
+type ConfigMaps struct {
+	// Client is used to read and write the ConfigMaps.
+	Client client.Client
+
+	// Namespace is the namespace of the ConfigMaps. Defaults to the
+	// namespace of the owner.
+	Namespace string
+
+	// NameSuffix is appended to the name, kind and group of the owner to
+	// name its ConfigMap.
+	NameSuffix string
+}
+
+// Key returns the key of the ConfigMap of owner. The name is shortened and
+// completed with a hash if it would exceed the maximum length of a name.
+func (s *ConfigMaps) Key(owner client.Object) (client.ObjectKey, error) {
+	gvk, err := apiutil.GVKForObject(owner, s.Client.Scheme())
+	if err != nil {
+		return client.ObjectKey{}, err
+	}
+	namespace := s.Namespace
+	if namespace == "" {
+		namespace = owner.GetNamespace()
+	}
+	name := owner.GetName() + "-" + strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "." + gvk.Group
+	}
+	if len(name)+len(s.NameSuffix) > validation.DNS1123SubdomainMaxLength {
+		sum := sha256.Sum256([]byte(name))
+		hash := hex.EncodeToString(sum[:])[:8]
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(s.NameSuffix)-len(hash)-1], ".-")
+		name += "-" + hash
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name + s.NameSuffix}, nil
+}
+
+// Get returns the ConfigMap of owner, or nil if it doesn't exist or isn't
+// controlled by owner, e.g. because it belongs to a deleted owner of the
+// same name that wasn't garbage collected yet.
+func (s *ConfigMaps) Get(ctx context.Context, owner client.Object) (*corev1.ConfigMap, error) {
+	cm, err := s.get(ctx, owner)
+	if err != nil || cm == nil || !ownedBy(cm, owner) {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// Update creates or updates the ConfigMap of owner, setting its content
+// with mutate. It fails if the ConfigMap exists but isn't controlled by
+// owner.
+func (s *ConfigMaps) Update(ctx context.Context, owner client.Object, mutate func(cm *corev1.ConfigMap)) error {
+	cm, err := s.get(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if cm == nil {
+		key, err := s.Key(owner)
+		if err != nil {
+			return err
+		}
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		if err := s.setOwner(cm, owner); err != nil {
+			return err
+		}
+		mutate(cm)
+		return s.Client.Create(ctx, cm)
+	}
+
+	if !ownedBy(cm, owner) {
+		return fmt.Errorf("ConfigMap %s is not owned by %s", client.ObjectKeyFromObject(cm), client.ObjectKeyFromObject(owner))
+	}
+	original := cm.DeepCopy()
+	mutate(cm)
+	if equality.Semantic.DeepEqual(original, cm) {
+		return nil
+	}
+	return s.Client.Update(ctx, cm)
+}
+
+// Delete deletes the ConfigMap of owner. A ConfigMap that isn't controlled
+// by owner is left alone.
+func (s *ConfigMaps) Delete(ctx context.Context, owner client.Object) error {
+	cm, err := s.Get(ctx, owner)
+	if err != nil || cm == nil {
+		return err
+	}
+	return client.IgnoreNotFound(s.Client.Delete(ctx, cm, client.Preconditions{UID: &cm.UID}))
+}
+
+func (s *ConfigMaps) get(ctx context.Context, owner client.Object) (*corev1.ConfigMap, error) {
+	key, err := s.Key(owner)
+	if err != nil {
+		return nil, err
+	}
+	if key.Namespace == "" {
+		return nil, fmt.Errorf("must specify a namespace for the ConfigMap of cluster-scoped %s", owner.GetName())
+	}
+	cm := &corev1.ConfigMap{}
+	if err := s.Client.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cm, nil
+}
+
+// setOwner makes owner the controller of cm, or records its UID if cm can't
+// have an owner reference to it.
+func (s *ConfigMaps) setOwner(cm *corev1.ConfigMap, owner client.Object) error {
+	// Owner references can't cross namespaces.
+	if owner.GetNamespace() == "" || owner.GetNamespace() == cm.Namespace {
+		return controllerutil.SetControllerReference(owner, cm, s.Client.Scheme())
+	}
+	cm.Annotations = map[string]string{OwnerUIDAnnotation: string(owner.GetUID())}
+	return nil
+}
+
+// ownedBy reports whether cm belongs to owner rather than to another
+// object, e.g. a deleted owner of the same name.
+func ownedBy(cm *corev1.ConfigMap, owner client.Object) bool {
+	if owner.GetUID() == "" {
+		return true
+	}
+	if owner.GetNamespace() == "" || owner.GetNamespace() == cm.Namespace {
+		ref := metav1.GetControllerOf(cm)
+		return ref != nil && ref.UID == owner.GetUID()
+	}
+	return cm.Annotations[OwnerUIDAnnotation] == string(owner.GetUID())
+}
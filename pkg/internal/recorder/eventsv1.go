@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventsv1client "k8s.io/client-go/kubernetes/typed/events/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/utils/lru"
+)
+
+const (
+	// defaultEventsV1QPS and defaultEventsV1Burst are the default rate
+	// limit of the events of an object, the same as the spam filter of core
+	// v1 events.
+	defaultEventsV1QPS   = 1. / 300.
+	defaultEventsV1Burst = 25
+
+	// maxRateLimitedObjects bounds the number of objects whose rate limit
+	// is tracked, the least recently used ones being forgotten.
+	maxRateLimitedObjects = 4096
+)
+
+// EventsV1Options configures a Provider emitting events through the
+// events.k8s.io/v1 API.
+type EventsV1Options struct {
+	// QPS is the sustained rate of the events emitted per object. Defaults
+	// to one every five minutes.
+	QPS float32
+
+	// Burst is the number of events that can be emitted per object before
+	// QPS applies. Defaults to 25.
+	Burst int
+}
+
+// NewEventsV1Provider creates a Provider whose recorders emit events through
+// the events.k8s.io/v1 API. Repeated events are deduplicated into event
+// series, and the events of each object are rate limited.
+func NewEventsV1Provider(config *rest.Config, httpClient *http.Client, scheme *runtime.Scheme, logger logr.Logger, opts EventsV1Options) (*Provider, error) {
+	if httpClient == nil {
+		panic("httpClient must not be nil")
+	}
+
+	eventsClient, err := eventsv1client.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init client: %w", err)
+	}
+	return newEventsV1Provider(&events.EventSinkImpl{Interface: eventsClient}, scheme, logger, opts), nil
+}
+
+// NewProviderFunc returns NewProvider, or a function creating Providers
+// emitting events through the events.k8s.io/v1 API with the given options
+// if they are set.
+func NewProviderFunc(eventsV1 *EventsV1Options) func(*rest.Config, *http.Client, *runtime.Scheme, logr.Logger, EventBroadcasterProducer) (*Provider, error) {
+	if eventsV1 == nil {
+		return NewProvider
+	}
+	opts := *eventsV1
+	return func(config *rest.Config, httpClient *http.Client, scheme *runtime.Scheme, logger logr.Logger, _ EventBroadcasterProducer) (*Provider, error) {
+		return NewEventsV1Provider(config, httpClient, scheme, logger, opts)
+	}
+}
+
+func newEventsV1Provider(sink events.EventSink, scheme *runtime.Scheme, logger logr.Logger, opts EventsV1Options) *Provider {
+	if opts.QPS <= 0 {
+		opts.QPS = defaultEventsV1QPS
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = defaultEventsV1Burst
+	}
+	return &Provider{
+		scheme: scheme,
+		logger: logger,
+		eventsV1: &eventsV1{
+			sink:     sink,
+			opts:     opts,
+			limiters: lru.New(maxRateLimitedObjects),
+			stop:     make(chan struct{}),
+		},
+	}
+}
+
+// eventsV1 holds the state of a Provider emitting events through the
+// events.k8s.io/v1 API.
+type eventsV1 struct {
+	sink     events.EventSink
+	opts     EventsV1Options
+	limiters *lru.Cache
+
+	broadcaster events.EventBroadcaster
+	stop        chan struct{}
+}
+
+// getEventsV1Broadcaster ensures that the events.k8s.io/v1 broadcaster of
+// the provider is started, and returns it.
+func (p *Provider) getEventsV1Broadcaster() events.EventBroadcaster {
+	p.broadcasterOnce.Do(func() {
+		broadcaster := events.NewBroadcaster(p.eventsV1.sink)
+		broadcaster.StartRecordingToSink(p.eventsV1.stop)
+		_, _ = broadcaster.StartEventWatcher(func(obj runtime.Object) {
+			if e, ok := obj.(*eventsv1.Event); ok {
+				p.logger.V(1).Info(e.Note, "type", e.Type, "object", e.Regarding, "reason", e.Reason)
+			}
+		})
+		p.eventsV1.broadcaster = broadcaster
+		p.stopBroadcaster = true
+	})
+	return p.eventsV1.broadcaster
+}
+
+// allow reports whether an event can be emitted for obj under the rate
+// limit of its events.
+func (e *eventsV1) allow(obj runtime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return true
+	}
+	key := string(accessor.GetUID())
+	if key == "" {
+		key = fmt.Sprintf("%T/%s/%s", obj, accessor.GetNamespace(), accessor.GetName())
+	}
+	// The cache is safe for concurrent use, but concurrent misses for the
+	// same object may each create a limiter, which only lets a few more
+	// events through.
+	limiter, ok := e.limiters.Get(key)
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketPassiveRateLimiter(e.opts.QPS, e.opts.Burst)
+		e.limiters.Add(key, limiter)
+	}
+	return limiter.(flowcontrol.PassiveRateLimiter).TryAccept()
+}
+
+// eventsV1Recorder adapts an events.k8s.io/v1 recorder to
+// record.EventRecorder. The reason of events is also used as their action,
+// and annotations are dropped since the API doesn't support them.
+type eventsV1Recorder struct {
+	state *eventsV1
+	rec   events.EventRecorder
+}
+
+var _ record.EventRecorder = &eventsV1Recorder{}
+
+func (r *eventsV1Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.Eventf(object, eventtype, reason, "%s", message)
+}
+
+func (r *eventsV1Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !r.state.allow(object) {
+		return
+	}
+	r.rec.Eventf(object, nil, eventtype, reason, reason, messageFmt, args...)
+}
+
+func (r *eventsV1Recorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
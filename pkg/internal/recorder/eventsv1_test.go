@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
+)
+
+// fakeEventSink records the events created through it.
+type fakeEventSink struct {
+	mu     sync.Mutex
+	events []*eventsv1.Event
+}
+
+func (s *fakeEventSink) Create(_ context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return event, nil
+}
+
+func (s *fakeEventSink) Update(_ context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return event, nil
+}
+
+func (s *fakeEventSink) Patch(_ context.Context, event *eventsv1.Event, _ []byte) (*eventsv1.Event, error) {
+	return event, nil
+}
+
+func (s *fakeEventSink) reasons() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var reasons []string
+	for _, e := range s.events {
+		reasons = append(reasons, e.Reason)
+	}
+	return reasons
+}
+
+var _ = Describe("events.k8s.io/v1 Provider", func() {
+	It("should emit rate limited events through the events.k8s.io/v1 API", func() {
+		sink := &fakeEventSink{}
+		provider := newEventsV1Provider(sink, scheme.Scheme, logr.Discard(), EventsV1Options{QPS: 0.001, Burst: 2})
+		defer provider.Stop(context.Background())
+		recorder := provider.GetEventRecorderFor("test-controller")
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "uid"}}
+		other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other", UID: "other-uid"}}
+		recorder.Event(pod, corev1.EventTypeNormal, "First", "first")
+		recorder.Eventf(pod, corev1.EventTypeWarning, "Second", "%s", "second")
+		recorder.Event(pod, corev1.EventTypeNormal, "Dropped", "dropped")
+		recorder.Event(other, corev1.EventTypeNormal, "Other", "other")
+
+		Eventually(sink.reasons).Should(ConsistOf("First", "Second", "Other"))
+		Consistently(sink.reasons, "100ms").Should(HaveLen(3))
+
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		e := sink.events[0]
+		Expect(e.ReportingController).To(Equal("test-controller"))
+		Expect(e.Action).To(Equal(e.Reason))
+		Expect(e.Regarding.Kind).To(Equal("Pod"))
+	})
+})
+
+var _ = Describe("Provider sinks", func() {
+	It("should pass a copy of every event to the sinks", func() {
+		provider := newEventsV1Provider(&fakeEventSink{}, scheme.Scheme, logr.Discard(), EventsV1Options{})
+		defer provider.Stop(context.Background())
+
+		var mu sync.Mutex
+		var events []recorder.Event
+		provider.SetSinks([]recorder.Sink{recorder.SinkFunc(func(e recorder.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		})})
+		rec := provider.GetEventRecorderFor("test-controller")
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "uid"}}
+		rec.Event(pod, corev1.EventTypeNormal, "First", "first")
+		rec.AnnotatedEventf(pod, map[string]string{"foo": "bar"}, corev1.EventTypeWarning, "Second", "%s event", "second")
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Component).To(Equal("test-controller"))
+		Expect(events[0].Regarding.Kind).To(Equal("Pod"))
+		Expect(events[0].Regarding.Name).To(Equal("pod"))
+		Expect(events[0].Message).To(Equal("first"))
+		Expect(events[1].Type).To(Equal(corev1.EventTypeWarning))
+		Expect(events[1].Message).To(Equal("second event"))
+		Expect(events[1].Annotations).To(HaveKeyWithValue("foo", "bar"))
+	})
+})
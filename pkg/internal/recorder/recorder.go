@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,9 @@ import (
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 )
 
 // EventBroadcasterProducer makes an event broadcaster, returning
@@ -51,6 +55,43 @@ type Provider struct {
 	broadcasterOnce sync.Once
 	broadcaster     record.EventBroadcaster
 	stopBroadcaster bool
+
+	// eventsV1 is set if events are emitted through the events.k8s.io/v1
+	// API instead of the core v1 one.
+	eventsV1 *eventsV1
+
+	// sinks receive a copy of every recorded event.
+	sinks []recorder.Sink
+}
+
+// SetSinks sets the sinks receiving a copy of every event recorded by the
+// recorders of the provider. It must be called before recording events.
+func (p *Provider) SetSinks(sinks []recorder.Sink) {
+	p.sinks = sinks
+}
+
+// mirror passes the event to the sinks of the provider.
+func (p *Provider) mirror(object runtime.Object, annotations map[string]string, component, eventtype, reason, message string) {
+	if len(p.sinks) == 0 {
+		return
+	}
+	e := recorder.Event{
+		Object:      object,
+		Component:   component,
+		Type:        eventtype,
+		Reason:      reason,
+		Message:     message,
+		Annotations: annotations,
+		Timestamp:   time.Now(),
+	}
+	if ref, err := reference.GetReference(p.scheme, object); err == nil {
+		e.Regarding = *ref
+	} else {
+		p.logger.V(1).Info("Could not build the reference of the object of an event", "error", err)
+	}
+	for _, sink := range p.sinks {
+		sink.Record(e)
+	}
 }
 
 // NB(directxman12): this manually implements Stop instead of Being a runnable because we need to
@@ -67,6 +108,19 @@ func (p *Provider) Stop(shutdownCtx context.Context) {
 	doneCh := make(chan struct{})
 
 	go func() {
+		if p.eventsV1 != nil {
+			broadcaster := p.getEventsV1Broadcaster()
+			p.lock.Lock()
+			if !p.stopped {
+				close(p.eventsV1.stop)
+				broadcaster.Shutdown()
+				p.stopped = true
+			}
+			p.lock.Unlock()
+			close(doneCh)
+			return
+		}
+
 		// technically, this could start the broadcaster, but practically, it's
 		// almost certainly already been started (e.g. by leader election).  We
 		// need to invoke this to ensure that we don't inadvertently race with
@@ -147,6 +201,13 @@ type lazyRecorder struct {
 // ensureRecording ensures that a concrete recorder is populated for this recorder.
 func (l *lazyRecorder) ensureRecording() {
 	l.recOnce.Do(func() {
+		if l.prov.eventsV1 != nil {
+			l.rec = &eventsV1Recorder{
+				state: l.prov.eventsV1,
+				rec:   l.prov.getEventsV1Broadcaster().NewRecorder(l.prov.scheme, l.name),
+			}
+			return
+		}
 		broadcaster := l.prov.getBroadcaster()
 		l.rec = broadcaster.NewRecorder(l.prov.scheme, corev1.EventSource{Component: l.name})
 	})
@@ -158,6 +219,7 @@ func (l *lazyRecorder) Event(object runtime.Object, eventtype, reason, message s
 	l.prov.lock.RLock()
 	if !l.prov.stopped {
 		l.rec.Event(object, eventtype, reason, message)
+		l.prov.mirror(object, nil, l.name, eventtype, reason, message)
 	}
 	l.prov.lock.RUnlock()
 }
@@ -167,6 +229,7 @@ func (l *lazyRecorder) Eventf(object runtime.Object, eventtype, reason, messageF
 	l.prov.lock.RLock()
 	if !l.prov.stopped {
 		l.rec.Eventf(object, eventtype, reason, messageFmt, args...)
+		l.prov.mirror(object, nil, l.name, eventtype, reason, fmt.Sprintf(messageFmt, args...))
 	}
 	l.prov.lock.RUnlock()
 }
@@ -176,6 +239,7 @@ func (l *lazyRecorder) AnnotatedEventf(object runtime.Object, annotations map[st
 	l.prov.lock.RLock()
 	if !l.prov.stopped {
 		l.rec.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+		l.prov.mirror(object, annotations, l.name, eventtype, reason, fmt.Sprintf(messageFmt, args...))
 	}
 	l.prov.lock.RUnlock()
 }
@@ -28,6 +28,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var log = logf.RuntimeLog.WithName("source").WithName("EventHandler")
@@ -57,10 +58,9 @@ type EventHandler[object client.Object, request comparable] struct {
 	predicates []predicate.TypedPredicate[object]
 }
 
-// HandlerFuncs converts EventHandler to a ResourceEventHandlerFuncs
-// TODO: switch to ResourceEventHandlerDetailedFuncs with client-go 1.27
-func (e *EventHandler[object, request]) HandlerFuncs() cache.ResourceEventHandlerFuncs {
-	return cache.ResourceEventHandlerFuncs{
+// HandlerFuncs converts EventHandler to a ResourceEventHandlerDetailedFuncs.
+func (e *EventHandler[object, request]) HandlerFuncs() cache.ResourceEventHandlerDetailedFuncs {
+	return cache.ResourceEventHandlerDetailedFuncs{
 		AddFunc:    e.OnAdd,
 		UpdateFunc: e.OnUpdate,
 		DeleteFunc: e.OnDelete,
@@ -68,8 +68,10 @@ func (e *EventHandler[object, request]) HandlerFuncs() cache.ResourceEventHandle
 }
 
 // OnAdd creates CreateEvent and calls Create on EventHandler.
-func (e *EventHandler[object, request]) OnAdd(obj interface{}) {
-	c := event.TypedCreateEvent[object]{}
+func (e *EventHandler[object, request]) OnAdd(obj interface{}, isInInitialList bool) {
+	c := event.TypedCreateEvent[object]{
+		IsInInitialList: isInInitialList,
+	}
 
 	// Pull Object out of the object
 	if o, ok := obj.(object); ok {
@@ -89,7 +91,7 @@ func (e *EventHandler[object, request]) OnAdd(obj interface{}) {
 	// Invoke create handler
 	ctx, cancel := context.WithCancel(e.ctx)
 	defer cancel()
-	e.handler.Create(ctx, c, e.queue)
+	e.handler.Create(ctx, c, WithTrigger(e.queue, reconcile.EventCreate, c.Object))
 }
 
 // OnUpdate creates UpdateEvent and calls Update on EventHandler.
@@ -122,7 +124,7 @@ func (e *EventHandler[object, request]) OnUpdate(oldObj, newObj interface{}) {
 	// Invoke update handler
 	ctx, cancel := context.WithCancel(e.ctx)
 	defer cancel()
-	e.handler.Update(ctx, u, e.queue)
+	e.handler.Update(ctx, u, WithUpdateTrigger(e.queue, u.ObjectOld, u.ObjectNew))
 }
 
 // OnDelete creates DeleteEvent and calls Delete on EventHandler.
@@ -170,5 +172,5 @@ func (e *EventHandler[object, request]) OnDelete(obj interface{}) {
 	// Invoke delete handler
 	ctx, cancel := context.WithCancel(e.ctx)
 	defer cancel()
-	e.handler.Delete(ctx, d, e.queue)
+	e.handler.Delete(ctx, d, WithTrigger(e.queue, reconcile.EventDelete, d.Object))
 }
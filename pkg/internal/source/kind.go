@@ -5,16 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/internal/controllerinfo"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -26,10 +30,24 @@ type Kind[object client.Object, request comparable] struct {
 	// Cache used to watch APIs
 	Cache cache.Cache
 
+	// Informer, if set, is the informer the source registers its handler
+	// with, instead of getting one from Cache.
+	Informer cache.Informer
+
+	// GetOptions are passed to Cache when getting the informer.
+	GetOptions []cache.InformerGetOption
+
+	// Lazy makes WaitForSync return as soon as the handler is registered,
+	// without waiting for the informer to sync.
+	Lazy bool
+
 	Handler handler.TypedEventHandler[object, request]
 
 	Predicates []predicate.TypedPredicate[object]
 
+	// quarantine is set by SetQuarantine.
+	quarantine *Quarantine
+
 	// startedErr may contain an error if one was encountered during startup. If its closed and does not
 	// contain an error, startup and syncing finished.
 	startedErr  chan error
@@ -42,7 +60,7 @@ func (ks *Kind[object, request]) Start(ctx context.Context, queue workqueue.Type
 	if isNil(ks.Type) {
 		return fmt.Errorf("must create Kind with a non-nil object")
 	}
-	if isNil(ks.Cache) {
+	if isNil(ks.Cache) && isNil(ks.Informer) {
 		return fmt.Errorf("must create Kind with a non-nil cache")
 	}
 	if isNil(ks.Handler) {
@@ -55,15 +73,23 @@ func (ks *Kind[object, request]) Start(ctx context.Context, queue workqueue.Type
 	ks.startedErr = make(chan error)
 	go func() {
 		var (
-			i       cache.Informer
+			i       = ks.Informer
 			lastErr error
 		)
 
+		getOpts := ks.GetOptions
+		if ks.Lazy {
+			getOpts = append(slices.Clip(getOpts), cache.BlockUntilSynced(false))
+		}
+
 		// Tries to get an informer until it returns true,
 		// an error or the specified context is cancelled or expired.
 		if err := wait.PollUntilContextCancel(ctx, 10*time.Second, true, func(ctx context.Context) (bool, error) {
+			if !isNil(i) {
+				return true, nil
+			}
 			// Lookup the Informer from the Cache and add an EventHandler which populates the Queue
-			i, lastErr = ks.Cache.GetInformer(ctx, ks.Type)
+			i, lastErr = ks.Cache.GetInformer(ctx, ks.Type, getOpts...)
 			if lastErr != nil {
 				kindMatchErr := &meta.NoKindMatchError{}
 				switch {
@@ -87,12 +113,22 @@ func (ks *Kind[object, request]) Start(ctx context.Context, queue workqueue.Type
 			return
 		}
 
-		_, err := i.AddEventHandler(NewEventHandler(ctx, queue, ks.Handler, ks.Predicates).HandlerFuncs())
+		h := ks.Handler
+		var gate *quarantineGate[object, request]
+		if ks.quarantine != nil {
+			gate = &quarantineGate[object, request]{handler: h}
+			h = gate
+		}
+		_, err := i.AddEventHandler(NewEventHandler(ctx, queue, h, ks.Predicates).HandlerFuncs())
 		if err != nil {
 			ks.startedErr <- err
 			return
 		}
-		if !ks.Cache.WaitForCacheSync(ctx) {
+		if gate != nil && !isNil(ks.Cache) {
+			health := func() (cache.WatchHealth, bool) { return cache.GetWatchHealth(ks.Cache, ks.Type) }
+			go gate.monitor(ctx, health, *ks.quarantine)
+		}
+		if !ks.Lazy && !ks.waitForSync(ctx, i) {
 			// Would be great to return something more informative here
 			ks.startedErr <- errors.New("cache did not sync")
 		}
@@ -102,6 +138,15 @@ func (ks *Kind[object, request]) Start(ctx context.Context, queue workqueue.Type
 	return nil
 }
 
+// waitForSync waits for the cache to sync or, if the source was given its
+// informer, for that informer to sync.
+func (ks *Kind[object, request]) waitForSync(ctx context.Context, i cache.Informer) bool {
+	if isNil(ks.Informer) {
+		return ks.Cache.WaitForCacheSync(ctx)
+	}
+	return toolscache.WaitForCacheSync(ctx.Done(), i.HasSynced)
+}
+
 func (ks *Kind[object, request]) String() string {
 	if !isNil(ks.Type) {
 		return fmt.Sprintf("kind source: %T", ks.Type)
@@ -109,6 +154,37 @@ func (ks *Kind[object, request]) String() string {
 	return "kind source: unknown type"
 }
 
+// DescribeWatch describes the watch of the source to the manager.
+func (ks *Kind[object, request]) DescribeWatch() controllerinfo.WatchInfo {
+	info := controllerinfo.WatchInfo{Source: ks.String()}
+	if !isNil(ks.Type) {
+		info.Type = fmt.Sprintf("%T", ks.Type)
+	}
+	if !isNil(ks.Handler) {
+		info.Handler = describe(ks.Handler)
+	}
+	for _, p := range ks.Predicates {
+		info.Predicates = append(info.Predicates, describe(p))
+	}
+	return info
+}
+
+// describe returns the string representation of v if it has one, or the
+// name of its type without package path nor type arguments, e.g.
+// "enqueueRequestsFromMapFunc".
+func describe(v any) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	name := reflect.TypeOf(v).String()
+	name, _, _ = strings.Cut(name, "[")
+	name = strings.TrimLeft(name, "*")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
 // WaitForSync implements SyncingSource to allow controllers to wait with starting
 // workers until the cache is synced.
 func (ks *Kind[object, request]) WaitForSync(ctx context.Context) error {
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// Quarantine configures the quarantine of a Kind source whose informer
+// keeps failing to list or watch, e.g. because the CRD of its kind has been
+// deleted. The events of a quarantined source are dropped until its
+// informer recovers.
+type Quarantine struct {
+	// Threshold is the number of consecutive list and watch failures after
+	// which the source is quarantined.
+	Threshold int
+
+	// CheckInterval is the interval at which the health of the informer is
+	// checked.
+	CheckInterval time.Duration
+
+	// OnChange is called when the source enters or leaves quarantine, with
+	// the last error of its informer when it enters it.
+	OnChange func(quarantined bool, err error)
+}
+
+// SetQuarantine makes the source quarantine itself according to q. It must
+// be called before Start.
+func (ks *Kind[object, request]) SetQuarantine(q Quarantine) {
+	ks.quarantine = &q
+}
+
+// quarantineGate drops the events of a source while it is quarantined.
+type quarantineGate[object any, request comparable] struct {
+	handler     handler.TypedEventHandler[object, request]
+	quarantined atomic.Bool
+}
+
+func (g *quarantineGate[object, request]) Create(ctx context.Context, evt event.TypedCreateEvent[object], q workqueue.TypedRateLimitingInterface[request]) {
+	if !g.quarantined.Load() {
+		g.handler.Create(ctx, evt, q)
+	}
+}
+
+func (g *quarantineGate[object, request]) Update(ctx context.Context, evt event.TypedUpdateEvent[object], q workqueue.TypedRateLimitingInterface[request]) {
+	if !g.quarantined.Load() {
+		g.handler.Update(ctx, evt, q)
+	}
+}
+
+func (g *quarantineGate[object, request]) Delete(ctx context.Context, evt event.TypedDeleteEvent[object], q workqueue.TypedRateLimitingInterface[request]) {
+	if !g.quarantined.Load() {
+		g.handler.Delete(ctx, evt, q)
+	}
+}
+
+func (g *quarantineGate[object, request]) Generic(ctx context.Context, evt event.TypedGenericEvent[object], q workqueue.TypedRateLimitingInterface[request]) {
+	if !g.quarantined.Load() {
+		g.handler.Generic(ctx, evt, q)
+	}
+}
+
+// monitor checks the health of the informer of the source, as returned by
+// health, until ctx is done, and quarantines the source while it keeps
+// failing. Informers relist when they recover, so the changes missed
+// during the quarantine are delivered once it ends.
+func (g *quarantineGate[object, request]) monitor(ctx context.Context, health func() (cache.WatchHealth, bool), q Quarantine) {
+	threshold := q.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+	interval := q.CheckInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		h, ok := health()
+		if !ok {
+			return
+		}
+		quarantined := g.quarantined.Load()
+		switch {
+		case !quarantined && h.ConsecutiveFailures >= threshold:
+			g.quarantined.Store(true)
+			if q.OnChange != nil {
+				q.OnChange(true, h.LastError)
+			}
+		case quarantined && h.ConsecutiveFailures == 0:
+			g.quarantined.Store(false)
+			if q.OnChange != nil {
+				q.OnChange(false, nil)
+			}
+		}
+	}, interval)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("quarantineGate", func() {
+	It("should drop events while the informer keeps failing", func() {
+		var created atomic.Int32
+		gate := &quarantineGate[*corev1.Pod, reconcile.Request]{handler: handler.TypedFuncs[*corev1.Pod, reconcile.Request]{
+			CreateFunc: func(context.Context, event.TypedCreateEvent[*corev1.Pod], workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				created.Add(1)
+			},
+		}}
+
+		var mu sync.Mutex
+		health := cache.WatchHealth{}
+		setHealth := func(h cache.WatchHealth) {
+			mu.Lock()
+			defer mu.Unlock()
+			health = h
+		}
+		changes := make(chan bool, 2)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go gate.monitor(ctx, func() (cache.WatchHealth, bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			return health, true
+		}, Quarantine{
+			Threshold:     3,
+			CheckInterval: 10 * time.Millisecond,
+			OnChange:      func(quarantined bool, _ error) { changes <- quarantined },
+		})
+
+		evt := event.TypedCreateEvent[*corev1.Pod]{Object: &corev1.Pod{}}
+		gate.Create(ctx, evt, nil)
+		Expect(created.Load()).To(BeEquivalentTo(1))
+
+		setHealth(cache.WatchHealth{ConsecutiveFailures: 2, LastError: errors.New("not found")})
+		Consistently(changes, 50*time.Millisecond).ShouldNot(Receive())
+
+		setHealth(cache.WatchHealth{ConsecutiveFailures: 3, LastError: errors.New("not found")})
+		Eventually(changes).Should(Receive(BeTrue()))
+		gate.Create(ctx, evt, nil)
+		Expect(created.Load()).To(BeEquivalentTo(1))
+
+		setHealth(cache.WatchHealth{})
+		Eventually(changes).Should(Receive(BeFalse()))
+		gate.Create(ctx, evt, nil)
+		Expect(created.Load()).To(BeEquivalentTo(2))
+	})
+})
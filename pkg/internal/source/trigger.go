@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TriggerRecorder is implemented by controller queues that record the
+// events that caused requests to be enqueued.
+type TriggerRecorder[request comparable] interface {
+	RecordTrigger(req request, trigger reconcile.Trigger)
+}
+
+// ResyncHolder is implemented by controller queues that may hold back the
+// requests triggered by periodic resyncs of informers.
+type ResyncHolder[request comparable] interface {
+	// HoldResync returns true if req, triggered by a resync, was held
+	// back and must not be added to the queue.
+	HoldResync(req request) bool
+}
+
+// WithTrigger returns a queue that records the given trigger for every
+// request added to it, if queue is a TriggerRecorder. Otherwise, queue is
+// returned unchanged.
+func WithTrigger[request comparable](queue workqueue.TypedRateLimitingInterface[request], eventType reconcile.EventType, obj any) workqueue.TypedRateLimitingInterface[request] {
+	trigger := reconcile.Trigger{EventType: eventType}
+	if o, ok := obj.(client.Object); ok {
+		trigger.Object = o
+	}
+	return withTrigger(queue, trigger)
+}
+
+// WithUpdateTrigger is like WithTrigger for an Update event from oldObj to
+// newObj. The trigger is marked as a resync if both objects have the same
+// resourceVersion, i.e. the event was emitted by a periodic resync of the
+// informer rather than by a change of the object. Requests added for
+// resyncs are offered to queue first if it is a ResyncHolder.
+func WithUpdateTrigger[request comparable](queue workqueue.TypedRateLimitingInterface[request], oldObj, newObj any) workqueue.TypedRateLimitingInterface[request] {
+	trigger := reconcile.Trigger{EventType: reconcile.EventUpdate}
+	if o, ok := newObj.(client.Object); ok {
+		trigger.Object = o
+		if old, ok := oldObj.(client.Object); ok {
+			trigger.Resync = old.GetResourceVersion() != "" && old.GetResourceVersion() == o.GetResourceVersion()
+		}
+	}
+	triggering := withTrigger(queue, trigger)
+	if holder, ok := queue.(ResyncHolder[request]); ok && trigger.Resync {
+		return &resyncQueue[request]{TypedRateLimitingInterface: triggering, holder: holder}
+	}
+	return triggering
+}
+
+func withTrigger[request comparable](queue workqueue.TypedRateLimitingInterface[request], trigger reconcile.Trigger) workqueue.TypedRateLimitingInterface[request] {
+	recorder, ok := queue.(TriggerRecorder[request])
+	if !ok {
+		return queue
+	}
+	return &triggeringQueue[request]{
+		TypedRateLimitingInterface: queue,
+		recorder:                   recorder,
+		trigger:                    trigger,
+	}
+}
+
+type triggeringQueue[request comparable] struct {
+	workqueue.TypedRateLimitingInterface[request]
+	recorder TriggerRecorder[request]
+	trigger  reconcile.Trigger
+}
+
+// The trigger is recorded before the request is added, so that it is
+// available as soon as a worker picks the request up.
+
+func (q *triggeringQueue[request]) Add(item request) {
+	q.recorder.RecordTrigger(item, q.trigger)
+	q.TypedRateLimitingInterface.Add(item)
+}
+
+func (q *triggeringQueue[request]) AddAfter(item request, duration time.Duration) {
+	q.recorder.RecordTrigger(item, q.trigger)
+	q.TypedRateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *triggeringQueue[request]) AddRateLimited(item request) {
+	q.recorder.RecordTrigger(item, q.trigger)
+	q.TypedRateLimitingInterface.AddRateLimited(item)
+}
+
+// resyncQueue adds the requests triggered by a resync unless its holder
+// holds them back.
+type resyncQueue[request comparable] struct {
+	workqueue.TypedRateLimitingInterface[request]
+	holder ResyncHolder[request]
+}
+
+func (q *resyncQueue[request]) Add(item request) {
+	if !q.holder.HoldResync(item) {
+		q.TypedRateLimitingInterface.Add(item)
+	}
+}
+
+func (q *resyncQueue[request]) AddAfter(item request, duration time.Duration) {
+	if !q.holder.HoldResync(item) {
+		q.TypedRateLimitingInterface.AddAfter(item, duration)
+	}
+}
+
+func (q *resyncQueue[request]) AddRateLimited(item request) {
+	if !q.holder.HoldResync(item) {
+		q.TypedRateLimitingInterface.AddRateLimited(item)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory records the objects created or updated on behalf of an
+// owner, so that objects which are no longer desired can be pruned and all
+// of them deleted when the owner is uninstalled, similar to the inventory
+// kept by kubectl apply --prune.
+//
+// A reconciler typically creates an Inventory at the start of a reconcile,
+// records every object it applies, as returned by the API server, prunes
+// the stale objects and commits the inventory:
+//
+//	inv, err := inventory.New(ctx, r.Client, store, owner)
+//	...
+//	// apply objects, calling inv.Record(obj) for each of them
+//	...
+//	if err := inv.Prune(ctx); err != nil { ... }
+//	if err := inv.Commit(ctx); err != nil { ... }
+//
+// Objects are only pruned if they are still the objects that were recorded,
+// or are owned by the owner, so that objects recreated by someone else with
+// the same name are left alone. Drifted reports the objects that were
+// modified since they were recorded, e.g. by users editing them.
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ObjectReference identifies an object in an inventory.
+type ObjectReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// UID is the UID of the recorded object, if it had been created.
+	UID types.UID `json:"uid,omitempty"`
+
+	// Hash is the hash of the content of the recorded object, without its
+	// metadata and status, to detect drift.
+	Hash string `json:"hash,omitempty"`
+}
+
+// String returns a human readable representation of the reference.
+func (r ObjectReference) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s/%s", r.APIVersion, r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s/%s", r.APIVersion, r.Kind, r.Namespace, r.Name)
+}
+
+// GroupKind returns the GroupKind of the referenced object.
+func (r ObjectReference) GroupKind() schema.GroupKind {
+	return schema.FromAPIVersionAndKind(r.APIVersion, r.Kind).GroupKind()
+}
+
+// sameObject compares references ignoring the version, so that an object
+// recorded with a different version of its API is not considered stale.
+func (r ObjectReference) sameObject(other ObjectReference) bool {
+	return r.GroupKind() == other.GroupKind() && r.Namespace == other.Namespace && r.Name == other.Name
+}
+
+// Inventory tracks the objects recorded during a reconcile against the
+// inventory saved by the previous one. It is not safe for concurrent use.
+type Inventory struct {
+	client client.Client
+	store  Store
+	owner  client.Object
+
+	previous []ObjectReference
+	recorded []ObjectReference
+	pruned   []ObjectReference
+}
+
+// New loads the inventory of owner from store and returns an Inventory to
+// record the objects of the current reconcile.
+func New(ctx context.Context, c client.Client, store Store, owner client.Object) (*Inventory, error) {
+	previous, err := store.Load(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load inventory of %s: %w", client.ObjectKeyFromObject(owner), err)
+	}
+	return &Inventory{
+		client:   c,
+		store:    store,
+		owner:    owner,
+		previous: previous,
+	}, nil
+}
+
+// Record adds objs to the objects desired by the current reconcile.
+func (i *Inventory) Record(objs ...client.Object) error {
+	for _, obj := range objs {
+		ref, err := i.referenceFor(obj)
+		if err != nil {
+			return err
+		}
+		if idx := slices.IndexFunc(i.recorded, ref.sameObject); idx >= 0 {
+			i.recorded[idx] = ref
+		} else {
+			i.recorded = append(i.recorded, ref)
+		}
+	}
+	return nil
+}
+
+// Previous returns the inventory saved by the previous reconcile.
+func (i *Inventory) Previous() []ObjectReference {
+	return slices.Clone(i.previous)
+}
+
+// Stale returns the objects of the previous inventory that have not been
+// recorded by the current reconcile, and have not been pruned yet.
+func (i *Inventory) Stale() []ObjectReference {
+	var stale []ObjectReference
+	for _, ref := range i.previous {
+		if !contains(i.recorded, ref) && !contains(i.pruned, ref) {
+			stale = append(stale, ref)
+		}
+	}
+	return stale
+}
+
+// Prune deletes the stale objects. Objects that are already gone are
+// considered pruned. Objects that are not owned anymore, because they have
+// a different UID than the recorded one or, if no UID was recorded, no
+// owner reference to the owner, are dropped from the inventory without
+// being deleted. Objects that failed to be deleted are kept in the
+// inventory so that pruning them is retried by the next reconcile.
+func (i *Inventory) Prune(ctx context.Context, opts ...client.DeleteOption) error {
+	var errs []error
+	for _, ref := range i.Stale() {
+		if err := i.prune(ctx, ref, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune %s: %w", ref, err))
+			continue
+		}
+		i.pruned = append(i.pruned, ref)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// Drifted returns the objects of the previous inventory that were deleted,
+// replaced by another object with the same name, or whose content changed
+// since they were recorded. Objects recorded without a hash are only
+// checked for deletion and replacement.
+func (i *Inventory) Drifted(ctx context.Context) ([]ObjectReference, error) {
+	var drifted []ObjectReference
+	for _, ref := range i.previous {
+		obj, err := getObject(ctx, i.client, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check drift of %s: %w", ref, err)
+		}
+		if obj == nil || (ref.UID != "" && obj.GetUID() != ref.UID) {
+			drifted = append(drifted, ref)
+			continue
+		}
+		if ref.Hash == "" {
+			continue
+		}
+		hash, err := hashOf(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check drift of %s: %w", ref, err)
+		}
+		if hash != ref.Hash {
+			drifted = append(drifted, ref)
+		}
+	}
+	return drifted, nil
+}
+
+// Commit saves the inventory of the current reconcile, which is made of the
+// recorded objects and the stale objects that have not been pruned.
+func (i *Inventory) Commit(ctx context.Context) error {
+	refs := append(slices.Clone(i.recorded), i.Stale()...)
+	sortReferences(refs)
+	if err := i.store.Save(ctx, i.owner, refs); err != nil {
+		return fmt.Errorf("failed to save inventory of %s: %w", client.ObjectKeyFromObject(i.owner), err)
+	}
+	i.previous = refs
+	i.recorded = nil
+	i.pruned = nil
+	return nil
+}
+
+// Uninstall deletes all the objects in the inventory of owner and saves an
+// empty inventory. It is typically called when finalizing the owner.
+func Uninstall(ctx context.Context, c client.Client, store Store, owner client.Object, opts ...client.DeleteOption) error {
+	inv, err := New(ctx, c, store, owner)
+	if err != nil {
+		return err
+	}
+	if err := inv.Prune(ctx, opts...); err != nil {
+		return err
+	}
+	return inv.Commit(ctx)
+}
+
+func (i *Inventory) referenceFor(obj client.Object) (ObjectReference, error) {
+	gvk, err := i.client.GroupVersionKindFor(obj)
+	if err != nil {
+		return ObjectReference{}, fmt.Errorf("failed to record %s in inventory: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	hash, err := hashOf(obj)
+	if err != nil {
+		return ObjectReference{}, fmt.Errorf("failed to record %s in inventory: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+		Hash:       hash,
+	}, nil
+}
+
+// prune deletes the object of ref if it is still owned.
+func (i *Inventory) prune(ctx context.Context, ref ObjectReference, opts ...client.DeleteOption) error {
+	obj, err := getObject(ctx, i.client, ref)
+	if err != nil || obj == nil {
+		return err
+	}
+	if !i.owns(ref, obj) {
+		log.FromContext(ctx).Info("Not pruning object that isn't owned anymore", "object", ref.String())
+		return nil
+	}
+
+	// The precondition guarantees that an object replaced in the meantime
+	// isn't deleted.
+	uid := obj.GetUID()
+	opts = append(slices.Clone(opts), client.Preconditions{UID: &uid})
+	if err := i.client.Delete(ctx, obj, opts...); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// owns returns whether obj is the object that was recorded as ref or, if no
+// UID was recorded, is owned by the owner.
+func (i *Inventory) owns(ref ObjectReference, obj client.Object) bool {
+	if ref.UID != "" {
+		return obj.GetUID() == ref.UID
+	}
+	return slices.ContainsFunc(obj.GetOwnerReferences(), func(owner metav1.OwnerReference) bool {
+		return owner.UID == i.owner.GetUID()
+	})
+}
+
+// getObject returns the object of ref, or nil if it doesn't exist.
+func getObject(ctx context.Context, c client.Client, ref ObjectReference) (client.Object, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+// hashOf returns the hash of the content of obj, without its metadata and
+// status which change independently of what was applied.
+func hashOf(obj client.Object) (string, error) {
+	return controllerutil.ComputeHash(obj, controllerutil.PruneFields("apiVersion", "kind", "metadata", "status"))
+}
+
+func contains(refs []ObjectReference, ref ObjectReference) bool {
+	return slices.ContainsFunc(refs, ref.sameObject)
+}
+
+func sortReferences(refs []ObjectReference) {
+	slices.SortFunc(refs, func(a, b ObjectReference) int {
+		return strings.Compare(a.String(), b.String())
+	})
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/inventory"
+)
+
+func TestInventory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Inventory Suite")
+}
+
+var _ = Describe("Inventory", func() {
+	var (
+		ctx   context.Context
+		c     client.Client
+		owner *appsv1.Deployment
+		cm1   *corev1.ConfigMap
+		cm2   *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		owner = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "owner", UID: "owner-uid"}}
+		cm1 = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm1", UID: "cm1-uid"}}
+		cm2 = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm2", UID: "cm2-uid"}}
+		c = fake.NewClientBuilder().WithObjects(owner, cm1, cm2).Build()
+	})
+
+	reconcile := func(store inventory.Store, objs ...client.Object) *inventory.Inventory {
+		inv, err := inventory.New(ctx, c, store, owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Record(objs...)).To(Succeed())
+		Expect(inv.Prune(ctx)).To(Succeed())
+		Expect(inv.Commit(ctx)).To(Succeed())
+		return inv
+	}
+
+	names := func(refs []inventory.ObjectReference) []string {
+		var names []string
+		for _, ref := range refs {
+			names = append(names, ref.String())
+		}
+		return names
+	}
+
+	exists := func(obj client.Object) bool {
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	for _, tc := range []struct {
+		name  string
+		store func() inventory.Store
+	}{
+		{name: "AnnotationStore", store: func() inventory.Store { return &inventory.AnnotationStore{Client: c} }},
+		{name: "ConfigMapStore", store: func() inventory.Store { return &inventory.ConfigMapStore{Client: c} }},
+	} {
+		Context("with "+tc.name, func() {
+			It("should record the objects and prune the stale ones", func() {
+				store := tc.store()
+
+				reconcile(store, cm1, cm2)
+				refs, err := store.Load(ctx, owner)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(names(refs)).To(Equal([]string{"v1 ConfigMap/default/cm1", "v1 ConfigMap/default/cm2"}))
+				Expect(refs[0].UID).To(BeEquivalentTo("cm1-uid"))
+				Expect(refs[0].Hash).NotTo(BeEmpty())
+
+				inv := reconcile(store, cm1)
+				Expect(inv.Previous()).To(HaveLen(1))
+				Expect(exists(cm1)).To(BeTrue())
+				Expect(exists(cm2)).To(BeFalse())
+			})
+
+			It("should delete all the objects on uninstall", func() {
+				store := tc.store()
+				reconcile(store, cm1, cm2)
+
+				Expect(inventory.Uninstall(ctx, c, store, owner)).To(Succeed())
+				Expect(exists(cm1)).To(BeFalse())
+				Expect(exists(cm2)).To(BeFalse())
+
+				refs, err := store.Load(ctx, owner)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(refs).To(BeEmpty())
+			})
+		})
+	}
+
+	It("should report stale objects before they are pruned", func() {
+		store := &inventory.AnnotationStore{Client: c}
+		reconcile(store, cm1, cm2)
+
+		inv, err := inventory.New(ctx, c, store, owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Record(cm2)).To(Succeed())
+		Expect(names(inv.Stale())).To(Equal([]string{"v1 ConfigMap/default/cm1"}))
+	})
+
+	It("should not prune objects that were replaced", func() {
+		store := &inventory.AnnotationStore{Client: c}
+		reconcile(store, cm1, cm2)
+
+		Expect(c.Delete(ctx, cm2)).To(Succeed())
+		replaced := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm2", UID: "other-uid"}}
+		Expect(c.Create(ctx, replaced)).To(Succeed())
+
+		inv := reconcile(store, cm1)
+		Expect(inv.Previous()).To(HaveLen(1))
+		Expect(exists(replaced)).To(BeTrue())
+		Expect(replaced.UID).To(BeEquivalentTo("other-uid"))
+	})
+
+	It("should only prune objects recorded without UID if they are owned", func() {
+		owned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "owned",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "owner", UID: owner.UID}},
+		}}
+		unowned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unowned"}}
+		Expect(c.Create(ctx, owned)).To(Succeed())
+		Expect(c.Create(ctx, unowned)).To(Succeed())
+		store := &inventory.AnnotationStore{Client: c}
+		Expect(store.Save(ctx, owner, []inventory.ObjectReference{
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "owned"},
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "unowned"},
+		})).To(Succeed())
+
+		inv := reconcile(store)
+		Expect(inv.Previous()).To(BeEmpty())
+		Expect(exists(owned)).To(BeFalse())
+		Expect(exists(unowned)).To(BeTrue())
+	})
+
+	It("should report the objects that drifted", func() {
+		cm1.Data = map[string]string{"key": "value"}
+		Expect(c.Update(ctx, cm1)).To(Succeed())
+		cm3 := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm3", UID: "cm3-uid"}}
+		Expect(c.Create(ctx, cm3)).To(Succeed())
+		store := &inventory.AnnotationStore{Client: c}
+		reconcile(store, cm1, cm2, cm3)
+
+		inv, err := inventory.New(ctx, c, store, owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Drifted(ctx)).To(BeEmpty())
+
+		By("modifying, replacing and deleting objects")
+		cm1.Data["key"] = "edited"
+		Expect(c.Update(ctx, cm1)).To(Succeed())
+		cm2.Labels = map[string]string{"only": "metadata"}
+		Expect(c.Update(ctx, cm2)).To(Succeed())
+		Expect(c.Delete(ctx, cm3)).To(Succeed())
+
+		drifted, err := inv.Drifted(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(drifted)).To(Equal([]string{"v1 ConfigMap/default/cm1", "v1 ConfigMap/default/cm3"}))
+	})
+
+	It("should own the inventory ConfigMap", func() {
+		reconcile(&inventory.ConfigMapStore{Client: c}, cm1)
+
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "owner-deployment.apps-inventory"}, cm)).To(Succeed())
+		Expect(cm.OwnerReferences).To(HaveLen(1))
+		Expect(cm.OwnerReferences[0].UID).To(Equal(owner.UID))
+		Expect(*cm.OwnerReferences[0].Controller).To(BeTrue())
+	})
+
+	It("should not share the inventory ConfigMap between owners of different kinds", func() {
+		store := &inventory.ConfigMapStore{Client: c}
+		reconcile(store, cm1, cm2)
+
+		other := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "owner", UID: "other-owner-uid"}}
+		Expect(c.Create(ctx, other)).To(Succeed())
+		inv, err := inventory.New(ctx, c, store, other)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Previous()).To(BeEmpty())
+		Expect(inv.Prune(ctx)).To(Succeed())
+		Expect(inv.Commit(ctx)).To(Succeed())
+		Expect(exists(cm1)).To(BeTrue())
+		Expect(exists(cm2)).To(BeTrue())
+	})
+
+	It("should neither prune nor overwrite the inventory of a previous owner of the same name", func() {
+		store := &inventory.ConfigMapStore{Client: c}
+		reconcile(store, cm1, cm2)
+
+		recreated := owner.DeepCopy()
+		recreated.UID = "recreated-uid"
+		inv, err := inventory.New(ctx, c, store, recreated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inv.Previous()).To(BeEmpty())
+		Expect(inv.Prune(ctx)).To(Succeed())
+		Expect(exists(cm1)).To(BeTrue())
+		Expect(exists(cm2)).To(BeTrue())
+		Expect(inv.Commit(ctx)).To(MatchError(ContainSubstring("is not owned by")))
+
+		refs, err := store.Load(ctx, owner)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(refs)).To(Equal([]string{"v1 ConfigMap/default/cm1", "v1 ConfigMap/default/cm2"}))
+	})
+
+	It("should shorten the name of the inventory ConfigMap of owners with long names", func() {
+		owner = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: strings.Repeat("a", 253), UID: "long-uid"}}
+		Expect(c.Create(ctx, owner)).To(Succeed())
+		reconcile(&inventory.ConfigMapStore{Client: c}, cm1)
+
+		cms := &corev1.ConfigMapList{}
+		Expect(c.List(ctx, cms, client.InNamespace("default"))).To(Succeed())
+		var inventories []string
+		for _, cm := range cms.Items {
+			if strings.HasSuffix(cm.Name, "-inventory") {
+				inventories = append(inventories, cm.Name)
+			}
+		}
+		Expect(inventories).To(HaveLen(1))
+		Expect(len(inventories[0])).To(BeNumerically("<=", 253))
+	})
+})
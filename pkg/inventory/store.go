@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/internal/ownedstore"
+)
+
+// AnnotationKey is the annotation used by AnnotationStore to record the
+// inventory of an owner.
+const AnnotationKey = "inventory.controller-runtime.sigs.k8s.io/objects"
+
+// ConfigMapKey is the key of the inventory in the ConfigMaps written by
+// ConfigMapStore.
+const ConfigMapKey = "objects"
+
+// Store persists inventories.
+type Store interface {
+	// Load returns the inventory of owner. It returns an empty inventory
+	// if none was saved yet.
+	Load(ctx context.Context, owner client.Object) ([]ObjectReference, error)
+
+	// Save replaces the inventory of owner with refs.
+	Save(ctx context.Context, owner client.Object, refs []ObjectReference) error
+}
+
+// AnnotationStore is a Store that records the inventory as an annotation on
+// the owner. It is suitable for owners of a small number of objects, as
+// annotations count towards the size limit of the owner.
+type AnnotationStore struct {
+	// Client is used to patch the owner.
+	Client client.Client
+}
+
+var _ Store = &AnnotationStore{}
+
+// Load implements Store.
+func (s *AnnotationStore) Load(_ context.Context, owner client.Object) ([]ObjectReference, error) {
+	return decode(owner.GetAnnotations()[AnnotationKey])
+}
+
+// Save implements Store. The owner is patched in place.
+func (s *AnnotationStore) Save(ctx context.Context, owner client.Object, refs []ObjectReference) error {
+	data, err := encode(refs)
+	if err != nil {
+		return err
+	}
+	return ownedstore.SetAnnotation(ctx, s.Client, owner, AnnotationKey, &data)
+}
+
+// ConfigMapStore is a Store that records the inventory of each owner in a
+// ConfigMap controlled by it, for owners of too many objects to record them
+// in an annotation. The ConfigMap is garbage collected with its owner.
+//
+// The ConfigMap is named after the name, kind and group of the owner, so
+// that owners of different kinds don't share an inventory. The inventory of
+// a deleted owner of the same name, whose ConfigMap wasn't garbage
+// collected yet, is neither loaded, so that its objects aren't pruned, nor
+// overwritten: Save fails until the ConfigMap is gone.
+type ConfigMapStore struct {
+	// Client is used to read and write the ConfigMaps.
+	Client client.Client
+
+	// Namespace is the namespace of the ConfigMaps. Defaults to the
+	// namespace of the owner, and is required for cluster-scoped owners.
+	Namespace string
+
+	// NameSuffix is appended to the name, kind and group of the owner to
+	// name its ConfigMap, e.g. "web-deployment.apps-inventory".
+	// Defaults to "-inventory".
+	NameSuffix string
+}
+
+var _ Store = &ConfigMapStore{}
+
+// Load implements Store.
+func (s *ConfigMapStore) Load(ctx context.Context, owner client.Object) ([]ObjectReference, error) {
+	cm, err := s.configMaps().Get(ctx, owner)
+	if err != nil || cm == nil {
+		return nil, err
+	}
+	return decode(cm.Data[ConfigMapKey])
+}
+
+// Save implements Store.
+func (s *ConfigMapStore) Save(ctx context.Context, owner client.Object, refs []ObjectReference) error {
+	data, err := encode(refs)
+	if err != nil {
+		return err
+	}
+	return s.configMaps().Update(ctx, owner, func(cm *corev1.ConfigMap) {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[ConfigMapKey] = data
+	})
+}
+
+func (s *ConfigMapStore) configMaps() *ownedstore.ConfigMaps {
+	suffix := s.NameSuffix
+	if suffix == "" {
+		suffix = "-inventory"
+	}
+	return &ownedstore.ConfigMaps{Client: s.Client, Namespace: s.Namespace, NameSuffix: suffix}
+}
+
+func encode(refs []ObjectReference) (string, error) {
+	if refs == nil {
+		refs = []ObjectReference{}
+	}
+	data, err := json.Marshal(refs)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	return string(data), nil
+}
+
+func decode(data string) ([]ObjectReference, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var refs []ObjectReference
+	if err := json.Unmarshal([]byte(data), &refs); err != nil {
+		return nil, fmt.Errorf("failed to decode inventory: %w", err)
+	}
+	return refs, nil
+}
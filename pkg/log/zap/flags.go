@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -166,3 +167,101 @@ func (ev *timeEncodingFlag) Set(flagValue string) error {
 	ev.value = flagValue
 	return nil
 }
+
+type presetFlag struct {
+	setFunc func(Preset)
+	value   string
+}
+
+var _ flag.Value = &presetFlag{}
+
+func (ev *presetFlag) String() string {
+	return ev.value
+}
+
+func (ev *presetFlag) Type() string {
+	return "preset"
+}
+
+func (ev *presetFlag) Set(flagValue string) error {
+	preset := Preset(strings.ToLower(flagValue))
+	switch preset {
+	case DevelopmentPreset, ProductionPreset, HighVolumePreset:
+		ev.setFunc(preset)
+	default:
+		return fmt.Errorf("invalid preset value \"%s\"", flagValue)
+	}
+	ev.value = flagValue
+	return nil
+}
+
+type samplingFlag struct {
+	setFunc func(*SamplingOptions)
+	value   string
+}
+
+var _ flag.Value = &samplingFlag{}
+
+func (ev *samplingFlag) String() string {
+	return ev.value
+}
+
+func (ev *samplingFlag) Type() string {
+	return "sampling"
+}
+
+func (ev *samplingFlag) Set(flagValue string) error {
+	if strings.ToLower(flagValue) == "off" {
+		ev.setFunc(&SamplingOptions{})
+		ev.value = flagValue
+		return nil
+	}
+	initial, thereafter, ok := strings.Cut(flagValue, ":")
+	if !ok {
+		return fmt.Errorf("invalid sampling value \"%s\"", flagValue)
+	}
+	initialVal, err := strconv.Atoi(initial)
+	if err != nil || initialVal <= 0 {
+		return fmt.Errorf("invalid sampling value \"%s\"", flagValue)
+	}
+	thereafterVal, err := strconv.Atoi(thereafter)
+	if err != nil || thereafterVal < 0 {
+		return fmt.Errorf("invalid sampling value \"%s\"", flagValue)
+	}
+	ev.setFunc(&SamplingOptions{Tick: time.Second, Initial: initialVal, Thereafter: thereafterVal})
+	ev.value = flagValue
+	return nil
+}
+
+type errorRateLimitFlag struct {
+	setFunc func(*ErrorRateLimitOptions)
+	value   string
+}
+
+var _ flag.Value = &errorRateLimitFlag{}
+
+func (ev *errorRateLimitFlag) String() string {
+	return ev.value
+}
+
+func (ev *errorRateLimitFlag) Type() string {
+	return "rate-limit"
+}
+
+func (ev *errorRateLimitFlag) Set(flagValue string) error {
+	burst, interval, ok := strings.Cut(flagValue, "/")
+	if !ok {
+		return fmt.Errorf("invalid error rate limit value \"%s\"", flagValue)
+	}
+	burstVal, err := strconv.Atoi(burst)
+	if err != nil || burstVal <= 0 {
+		return fmt.Errorf("invalid error rate limit value \"%s\"", flagValue)
+	}
+	intervalVal, err := time.ParseDuration(interval)
+	if err != nil || intervalVal <= 0 {
+		return fmt.Errorf("invalid error rate limit value \"%s\"", flagValue)
+	}
+	ev.setFunc(&ErrorRateLimitOptions{Interval: intervalVal, Burst: burstVal})
+	ev.value = flagValue
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zap
+
+import (
+	"flag"
+	"time"
+)
+
+// Preset names a set of defaults of Options suited to an environment.
+type Preset string
+
+const (
+	// DevelopmentPreset enables development mode: console output, Debug
+	// level, stacktraces on warnings and no sampling.
+	DevelopmentPreset Preset = "development"
+
+	// ProductionPreset keeps the production defaults and logs the same error
+	// at most 10 times per minute.
+	ProductionPreset Preset = "production"
+
+	// HighVolumePreset is suited to controllers reconciling many objects:
+	// it logs the first 10 entries with the same message per second, then
+	// every 100th, and the same error at most 5 times per minute.
+	HighVolumePreset Preset = "high-volume"
+)
+
+// UsePreset sets Options.Preset.
+func UsePreset(preset Preset) Opts {
+	return func(o *Options) {
+		o.Preset = preset
+	}
+}
+
+// SamplingOptions configures the sampling of log entries: within each Tick,
+// the first Initial entries with the same level and message are logged, then
+// every Thereafter-th. See zapcore.NewSamplerWithOptions.
+type SamplingOptions struct {
+	Tick       time.Duration
+	Initial    int
+	Thereafter int
+}
+
+// Sampling sets Options.Sampling. Use an empty SamplingOptions to disable
+// sampling.
+func Sampling(sampling SamplingOptions) Opts {
+	return func(o *Options) {
+		o.Sampling = &sampling
+	}
+}
+
+// ErrorRateLimitOptions limits how often the same error is logged: entries
+// at Error level or above with the same logger name and message are logged
+// at most Burst times per Interval, and dropped otherwise.
+type ErrorRateLimitOptions struct {
+	Interval time.Duration
+	Burst    int
+}
+
+// ErrorRateLimit sets Options.ErrorRateLimit.
+func ErrorRateLimit(limit ErrorRateLimitOptions) Opts {
+	return func(o *Options) {
+		o.ErrorRateLimit = &limit
+	}
+}
+
+// applyPreset sets the fields of o that are defaulted by its preset.
+func (o *Options) applyPreset() {
+	switch o.Preset {
+	case DevelopmentPreset:
+		o.Development = true
+	case ProductionPreset:
+		if o.ErrorRateLimit == nil {
+			o.ErrorRateLimit = &ErrorRateLimitOptions{Interval: time.Minute, Burst: 10}
+		}
+	case HighVolumePreset:
+		if o.Sampling == nil {
+			o.Sampling = &SamplingOptions{Tick: time.Second, Initial: 10, Thereafter: 100}
+		}
+		if o.ErrorRateLimit == nil {
+			o.ErrorRateLimit = &ErrorRateLimitOptions{Interval: time.Minute, Burst: 5}
+		}
+	}
+}
+
+// Config is the serializable form of the logging settings, to embed in the
+// configuration file of a component. Its values have the syntax of the
+// corresponding zap flags, see Options.BindFlags, and empty values are
+// ignored.
+type Config struct {
+	Preset          Preset `json:"preset,omitempty"`
+	Development     *bool  `json:"development,omitempty"`
+	Encoder         string `json:"encoder,omitempty"`
+	Level           string `json:"level,omitempty"`
+	StacktraceLevel string `json:"stacktraceLevel,omitempty"`
+	TimeEncoding    string `json:"timeEncoding,omitempty"`
+	Sampling        string `json:"sampling,omitempty"`
+	ErrorRateLimit  string `json:"errorRateLimit,omitempty"`
+}
+
+// Opts returns the Opts applying c, or an error if a value of c is invalid.
+//
+//	opts, err := cfg.Logging.Opts()
+//	if err != nil {
+//		...
+//	}
+//	log := zap.New(opts)
+func (c Config) Opts() (Opts, error) {
+	if err := c.applyTo(&Options{}); err != nil {
+		return nil, err
+	}
+	return func(o *Options) {
+		// The values have been validated above.
+		_ = c.applyTo(o)
+	}, nil
+}
+
+func (c Config) applyTo(o *Options) error {
+	fs := flag.NewFlagSet("zap", flag.ContinueOnError)
+	o.BindFlags(fs)
+	values := []struct{ name, value string }{
+		{"zap-preset", string(c.Preset)},
+		{"zap-encoder", c.Encoder},
+		{"zap-log-level", c.Level},
+		{"zap-stacktrace-level", c.StacktraceLevel},
+		{"zap-time-encoding", c.TimeEncoding},
+		{"zap-sampling", c.Sampling},
+		{"zap-error-rate-limit", c.ErrorRateLimit},
+	}
+	for _, v := range values {
+		if v.value == "" {
+			continue
+		}
+		if err := fs.Set(v.name, v.value); err != nil {
+			return err
+		}
+	}
+	if c.Development != nil {
+		o.Development = *c.Development
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zap
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// maxErrorRateLimitKeys is the number of distinct errors past which expired
+// rate limiting windows are pruned.
+const maxErrorRateLimitKeys = 4096
+
+// errorRateLimitCore drops the entries at Error level or above with the same
+// logger name and message that exceed the burst of their interval.
+type errorRateLimitCore struct {
+	zapcore.Core
+	limit ErrorRateLimitOptions
+	state *errorRateLimitState
+}
+
+// errorRateLimitState is shared by the cores derived with With, so that
+// errors are limited regardless of the fields of the logger.
+type errorRateLimitState struct {
+	mu      sync.Mutex
+	windows map[string]*errorWindow
+}
+
+type errorWindow struct {
+	start time.Time
+	count int
+}
+
+func newErrorRateLimitCore(core zapcore.Core, limit ErrorRateLimitOptions) zapcore.Core {
+	return &errorRateLimitCore{
+		Core:  core,
+		limit: limit,
+		state: &errorRateLimitState{windows: map[string]*errorWindow{}},
+	}
+}
+
+// With implements zapcore.Core.
+func (c *errorRateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &errorRateLimitCore{Core: c.Core.With(fields), limit: c.limit, state: c.state}
+}
+
+// Check implements zapcore.Core.
+func (c *errorRateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.ErrorLevel && !c.state.allow(ent.LoggerName+"\x00"+ent.Message, ent.Time, c.limit) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (s *errorRateLimitState) allow(key string, now time.Time, limit ErrorRateLimitOptions) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.windows[key]
+	if !ok || now.Sub(w.start) >= limit.Interval {
+		if !ok && len(s.windows) >= maxErrorRateLimitKeys {
+			s.prune(now, limit.Interval)
+		}
+		w = &errorWindow{start: now}
+		s.windows[key] = w
+	}
+	w.count++
+	return w.count <= limit.Burst
+}
+
+// prune removes the expired windows.
+func (s *errorRateLimitState) prune(now time.Time, interval time.Duration) {
+	for key, w := range s.windows {
+		if now.Sub(w.start) >= interval {
+			delete(s.windows, key)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zap
+
+import (
+	"io"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink creates a zapcore.Core writing log entries to an additional
+// destination, e.g. a rotated file or an OTLP exporter. The encoder is the
+// one of the logger and is not shared with other sinks; the level is the
+// one of the logger.
+type Sink func(encoder zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core
+
+// WriterSink returns a Sink writing to w with the encoder of the logger,
+// e.g. to a file rotated by a rotating io.Writer.
+func WriterSink(w io.Writer) Sink {
+	return func(encoder zapcore.Encoder, level zapcore.LevelEnabler) zapcore.Core {
+		return zapcore.NewCore(encoder, zapcore.AddSync(w), level)
+	}
+}
+
+// AddSinks appends sinks to Options.Sinks.
+func AddSinks(sinks ...Sink) Opts {
+	return func(o *Options) {
+		o.Sinks = append(o.Sinks, sinks...)
+	}
+}
@@ -165,10 +165,28 @@ type Options struct {
 	// TimeEncoder specifies the encoder for the timestamps in log messages.
 	// Defaults to RFC3339TimeEncoder.
 	TimeEncoder zapcore.TimeEncoder
+	// Preset selects defaults suited to an environment for the sampling,
+	// rate limiting and development mode settings. Fields that are set take
+	// precedence over the preset.
+	Preset Preset
+	// Sampling configures the sampling of log entries. Defaults to sampling
+	// the first 100 entries with the same message per second, then every
+	// 100th, in production mode, and to no sampling in development mode.
+	// An empty SamplingOptions disables sampling.
+	Sampling *SamplingOptions
+	// ErrorRateLimit limits how often the same error is logged. Defaults to
+	// no limit.
+	ErrorRateLimit *ErrorRateLimitOptions
+	// Sinks are additional destinations of the log output, e.g. rotated
+	// files or an OTLP exporter. Sampling and rate limiting apply to them
+	// like to DestWriter.
+	Sinks []Sink
 }
 
 // addDefaults adds defaults to the Options.
 func (o *Options) addDefaults() {
+	o.applyPreset()
+
 	if o.DestWriter == nil {
 		o.DestWriter = os.Stderr
 	}
@@ -198,16 +216,28 @@ func (o *Options) addDefaults() {
 			lvl := zap.NewAtomicLevelAt(zap.ErrorLevel)
 			o.StacktraceLevel = &lvl
 		}
-		// Disable sampling for increased Debug levels. Otherwise, this will
-		// cause index out of bounds errors in the sampling code.
-		if !o.Level.Enabled(zapcore.Level(-2)) {
-			o.ZapOpts = append(o.ZapOpts,
-				zap.WrapCore(func(core zapcore.Core) zapcore.Core {
-					return zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
-				}))
+		if o.Sampling == nil {
+			o.Sampling = &SamplingOptions{Tick: time.Second, Initial: 100, Thereafter: 100}
 		}
 	}
 
+	// Disable sampling for increased Debug levels. Otherwise, this will
+	// cause index out of bounds errors in the sampling code.
+	if o.Sampling != nil && o.Sampling.Tick > 0 && !o.Level.Enabled(zapcore.Level(-2)) {
+		sampling := *o.Sampling
+		o.ZapOpts = append(o.ZapOpts,
+			zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewSamplerWithOptions(core, sampling.Tick, sampling.Initial, sampling.Thereafter)
+			}))
+	}
+	if o.ErrorRateLimit != nil && o.ErrorRateLimit.Interval > 0 {
+		limit := *o.ErrorRateLimit
+		o.ZapOpts = append(o.ZapOpts,
+			zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+				return newErrorRateLimitCore(core, limit)
+			}))
+	}
+
 	if o.TimeEncoder == nil {
 		o.TimeEncoder = zapcore.RFC3339TimeEncoder
 	}
@@ -237,7 +267,15 @@ func NewRaw(opts ...Opts) *zap.Logger {
 	sink := zapcore.AddSync(o.DestWriter)
 
 	o.ZapOpts = append(o.ZapOpts, zap.ErrorOutput(sink))
-	log := zap.New(zapcore.NewCore(&KubeAwareEncoder{Encoder: o.Encoder, Verbose: o.Development}, sink, o.Level))
+	core := zapcore.NewCore(&KubeAwareEncoder{Encoder: o.Encoder, Verbose: o.Development}, sink, o.Level)
+	if len(o.Sinks) > 0 {
+		cores := []zapcore.Core{core}
+		for _, s := range o.Sinks {
+			cores = append(cores, s(&KubeAwareEncoder{Encoder: o.Encoder.Clone(), Verbose: o.Development}, o.Level))
+		}
+		core = zapcore.NewTee(cores...)
+	}
+	log := zap.New(core)
 	log = log.WithOptions(o.ZapOpts...)
 	return log
 }
@@ -252,6 +290,9 @@ func NewRaw(opts ...Opts) *zap.Logger {
 //   - zap-stacktrace-level: Zap Level at and above which stacktraces are captured (one of 'info', 'error' or 'panic')
 //   - zap-time-encoding: Zap time encoding (one of 'epoch', 'millis', 'nano', 'iso8601', 'rfc3339' or 'rfc3339nano'),
 //     Defaults to 'epoch'.
+//   - zap-preset: Zap preset (one of 'development', 'production' or 'high-volume'), see Preset.
+//   - zap-sampling: Zap sampling per second, as '<initial>:<thereafter>', or 'off'.
+//   - zap-error-rate-limit: Zap rate limit of repeated errors, as '<burst>/<interval>' e.g. '10/1m'.
 func (o *Options) BindFlags(fs *flag.FlagSet) {
 	// Set Development mode value
 	fs.BoolVar(&o.Development, "zap-devel", o.Development,
@@ -288,6 +329,30 @@ func (o *Options) BindFlags(fs *flag.FlagSet) {
 		o.TimeEncoder = fromFlag
 	}
 	fs.Var(&timeEncoderVal, "zap-time-encoding", "Zap time encoding (one of 'epoch', 'millis', 'nano', 'iso8601', 'rfc3339' or 'rfc3339nano'). Defaults to 'epoch'.")
+
+	// Set the preset
+	var presetVal presetFlag
+	presetVal.setFunc = func(fromFlag Preset) {
+		o.Preset = fromFlag
+	}
+	fs.Var(&presetVal, "zap-preset", "Zap preset (one of 'development', 'production' or 'high-volume').")
+
+	// Set the sampling
+	var samplingVal samplingFlag
+	samplingVal.setFunc = func(fromFlag *SamplingOptions) {
+		o.Sampling = fromFlag
+	}
+	fs.Var(&samplingVal, "zap-sampling",
+		"Zap sampling per second, as '<initial>:<thereafter>' to log the first <initial> entries with the same message, "+
+			"then every <thereafter>th, or 'off'.")
+
+	// Set the error rate limit
+	var errorRateLimitVal errorRateLimitFlag
+	errorRateLimitVal.setFunc = func(fromFlag *ErrorRateLimitOptions) {
+		o.ErrorRateLimit = fromFlag
+	}
+	fs.Var(&errorRateLimitVal, "zap-error-rate-limit",
+		"Zap rate limit of repeated errors, as '<burst>/<interval>' e.g. '10/1m' to log the same error at most 10 times per minute.")
 }
 
 // UseFlagOptions configures the logger to use the Options set by parsing zap option flags from the CLI.
@@ -19,9 +19,13 @@ package zap
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
+	"io"
 	"os"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
@@ -589,3 +593,101 @@ var _ = Describe("Zap log level flag options setup", func() {
 		})
 	})
 })
+
+var _ = Describe("Zap presets, sampling and sinks", func() {
+	var logOut *bytes.Buffer
+
+	BeforeEach(func() {
+		logOut = new(bytes.Buffer)
+	})
+
+	It("should rate limit repeated errors", func() {
+		logger := New(WriteTo(logOut), ErrorRateLimit(ErrorRateLimitOptions{Interval: time.Hour, Burst: 2}))
+		for range 5 {
+			logger.Error(errors.New("boom"), "failed to reconcile")
+		}
+		logger.Error(errors.New("boom"), "failed to update status")
+		logger.Info("reconciled")
+		logger.Info("reconciled")
+		logger.Info("reconciled")
+
+		Expect(strings.Count(logOut.String(), "failed to reconcile")).To(Equal(2))
+		Expect(strings.Count(logOut.String(), "failed to update status")).To(Equal(1))
+		Expect(strings.Count(logOut.String(), "reconciled")).To(Equal(3))
+	})
+
+	It("should sample entries as configured", func() {
+		logger := New(WriteTo(logOut), Sampling(SamplingOptions{Tick: time.Hour, Initial: 2, Thereafter: 0}))
+		for range 5 {
+			logger.Info("reconciled")
+		}
+		Expect(strings.Count(logOut.String(), "reconciled")).To(Equal(2))
+	})
+
+	It("should disable sampling with empty SamplingOptions", func() {
+		logger := New(WriteTo(logOut), Sampling(SamplingOptions{}))
+		for range 150 {
+			logger.Info("reconciled")
+		}
+		Expect(strings.Count(logOut.String(), "reconciled")).To(Equal(150))
+	})
+
+	It("should apply the defaults of the high-volume preset", func() {
+		logger := New(WriteTo(logOut), UsePreset(HighVolumePreset))
+		for range 20 {
+			logger.Info("reconciled")
+			logger.Error(errors.New("boom"), "failed to reconcile")
+		}
+		Expect(strings.Count(logOut.String(), "reconciled")).To(Equal(10))
+		Expect(strings.Count(logOut.String(), "failed to reconcile")).To(Equal(5))
+	})
+
+	It("should let set fields take precedence over the preset", func() {
+		opts := &Options{Preset: HighVolumePreset, Sampling: &SamplingOptions{}}
+		opts.addDefaults()
+		Expect(*opts.Sampling).To(Equal(SamplingOptions{}))
+		Expect(*opts.ErrorRateLimit).To(Equal(ErrorRateLimitOptions{Interval: time.Minute, Burst: 5}))
+	})
+
+	It("should write to additional sinks", func() {
+		sinkOut := new(bytes.Buffer)
+		logger := New(WriteTo(logOut), AddSinks(WriterSink(sinkOut)))
+		logger.WithValues("controller", "pods").Info("reconciled")
+		Expect(logOut.String()).To(ContainSubstring(`"msg":"reconciled","controller":"pods"`))
+		Expect(sinkOut.String()).To(Equal(logOut.String()))
+	})
+
+	It("should parse the preset, sampling and rate limit flags", func() {
+		var fromFlags Options
+		fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		fromFlags.BindFlags(fs)
+		Expect(fs.Parse([]string{"--zap-preset=production", "--zap-sampling=5:50", "--zap-error-rate-limit=3/30s"})).To(Succeed())
+		Expect(fromFlags.Preset).To(Equal(ProductionPreset))
+		Expect(*fromFlags.Sampling).To(Equal(SamplingOptions{Tick: time.Second, Initial: 5, Thereafter: 50}))
+		Expect(*fromFlags.ErrorRateLimit).To(Equal(ErrorRateLimitOptions{Interval: 30 * time.Second, Burst: 3}))
+
+		fs = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fromFlags.BindFlags(fs)
+		Expect(fs.Parse([]string{"--zap-sampling=off"})).To(Succeed())
+		Expect(*fromFlags.Sampling).To(Equal(SamplingOptions{}))
+		Expect(fs.Parse([]string{"--zap-error-rate-limit=3"})).NotTo(Succeed())
+		Expect(fs.Parse([]string{"--zap-preset=verbose"})).NotTo(Succeed())
+	})
+
+	It("should apply a Config", func() {
+		var cfg Config
+		Expect(json.Unmarshal([]byte(`{"preset":"development","level":"error","sampling":"1:0"}`), &cfg)).To(Succeed())
+		opts, err := cfg.Opts()
+		Expect(err).NotTo(HaveOccurred())
+
+		o := &Options{}
+		opts(o)
+		Expect(o.Preset).To(Equal(DevelopmentPreset))
+		Expect(o.Level.Enabled(zapcore.InfoLevel)).To(BeFalse())
+		Expect(*o.Sampling).To(Equal(SamplingOptions{Tick: time.Second, Initial: 1, Thereafter: 0}))
+
+		_, err = Config{ErrorRateLimit: "often"}.Opts()
+		Expect(err).To(MatchError(ContainSubstring("invalid error rate limit")))
+	})
+})
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// QueueBackpressure returns a check for admission.WithBackpressure that
+// reports pressure while the queue of one of the given controllers of mgr,
+// or of any of its controllers if none are given, holds more than maxDepth
+// requests. A deep queue means a controller lags behind the events it
+// watches, and so does the cache it shares with webhooks.
+//
+// Controllers that need leader election are not started on replicas that
+// are not the leader, so their queues are always empty there.
+func QueueBackpressure(mgr Manager, maxDepth int, controllers ...string) admission.BackpressureCheck {
+	return func(context.Context) string {
+		for _, info := range mgr.GetControllers() {
+			if len(controllers) > 0 && !slices.Contains(controllers, info.Name) {
+				continue
+			}
+			if info.QueueDepth > maxDepth {
+				return fmt.Sprintf("controller %s has %d requests queued, more than %d", info.Name, info.QueueDepth, maxDepth)
+			}
+		}
+		return ""
+	}
+}
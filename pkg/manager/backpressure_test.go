@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QueueBackpressure", func() {
+	It("should report pressure from the controllers with deep queues", func() {
+		cm := &controllerManager{controllers: []ControllerDescriber{
+			&describedController{info: ControllerInfo{Name: "a", QueueDepth: 5}},
+			&describedController{info: ControllerInfo{Name: "b", QueueDepth: 50}},
+		}}
+
+		Expect(QueueBackpressure(cm, 10)(context.Background())).NotTo(BeEmpty(), "expected pressure from controller b")
+		Expect(QueueBackpressure(cm, 10, "a")(context.Background())).To(BeEmpty())
+		Expect(QueueBackpressure(cm, 100)(context.Background())).To(BeEmpty())
+	})
+})
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnableComponentsFlagName is the name of the flag enabling components
+	// registered by Components.AddFlags.
+	EnableComponentsFlagName = "enable-components"
+
+	// DisableComponentsFlagName is the name of the flag disabling
+	// components registered by Components.AddFlags.
+	DisableComponentsFlagName = "disable-components"
+)
+
+// Component is a named part of a manager, typically a controller or a
+// webhook, that can be enabled or disabled per deployment.
+type Component struct {
+	// Name identifies the component in flags and configuration. It is
+	// required.
+	Name string
+
+	// DependsOn are the names of the components this component requires,
+	// e.g. the controller creating the objects it reconciles. Enabling a
+	// component whose dependencies are disabled is an error.
+	DependsOn []string
+
+	// DisabledByDefault disables the component unless it is enabled
+	// explicitly, e.g. for experimental controllers.
+	DisabledByDefault bool
+
+	// Setup adds the component to the manager, e.g. by building its
+	// controller. It is required.
+	Setup func(mgr Manager) error
+}
+
+// Components is a registry of the components of a manager, which are set
+// up on the manager unless they are disabled by flags or configuration:
+//
+//	components := manager.NewComponents()
+//	if err := components.Register(
+//		manager.Component{Name: "pods", Setup: (&PodReconciler{}).SetupWithManager},
+//		manager.Component{Name: "pod-webhook", DependsOn: []string{"pods"}, Setup: setupPodWebhook},
+//	); err != nil { ... }
+//	components.AddFlags(flag.CommandLine)
+//	flag.Parse()
+//	...
+//	if err := components.SetupWithManager(mgr); err != nil { ... }
+//
+// Components enabled or disabled explicitly take precedence over the
+// Components of the controller configuration of the manager.
+type Components struct {
+	mu         sync.Mutex
+	components []Component
+	byName     map[string]Component
+	explicit   map[string]bool
+}
+
+// NewComponents returns an empty registry.
+func NewComponents() *Components {
+	return &Components{
+		byName:   map[string]Component{},
+		explicit: map[string]bool{},
+	}
+}
+
+// Register adds components to the registry. They are set up in the order
+// they are registered.
+func (c *Components) Register(components ...Component) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, component := range components {
+		if component.Name == "" {
+			return fmt.Errorf("must specify Name for components")
+		}
+		if component.Setup == nil {
+			return fmt.Errorf("must specify Setup for component %q", component.Name)
+		}
+		if _, ok := c.byName[component.Name]; ok {
+			return fmt.Errorf("component %q already registered", component.Name)
+		}
+		c.byName[component.Name] = component
+		c.components = append(c.components, component)
+	}
+	return nil
+}
+
+// Enable enables the named components explicitly.
+func (c *Components) Enable(names ...string) {
+	c.set(names, true)
+}
+
+// Disable disables the named components explicitly.
+func (c *Components) Disable(names ...string) {
+	c.set(names, false)
+}
+
+func (c *Components) set(names []string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		c.explicit[name] = enabled
+	}
+}
+
+// AddFlags registers the --enable-components and --disable-components
+// flags on fs, each taking a comma-separated list of component names.
+// Components must be registered before, so that they are listed in the help
+// of the flags.
+func (c *Components) AddFlags(fs *flag.FlagSet) {
+	names := strings.Join(c.names(), ", ")
+	fs.Var(&componentsFlag{components: c, enabled: true}, EnableComponentsFlagName,
+		"A comma-separated list of components to enable. Components: "+names)
+	fs.Var(&componentsFlag{components: c, enabled: false}, DisableComponentsFlagName,
+		"A comma-separated list of components to disable. Components: "+names)
+}
+
+func (c *Components) names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.components))
+	for _, component := range c.components {
+		names = append(names, component.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled returns whether the named component is enabled, given the
+// components enabled or disabled in config, e.g. the Components of the
+// controller configuration of a manager.
+func (c *Components) Enabled(name string, config map[string]bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enabledLocked(name, config)
+}
+
+func (c *Components) enabledLocked(name string, config map[string]bool) bool {
+	if enabled, ok := c.explicit[name]; ok {
+		return enabled
+	}
+	if enabled, ok := config[name]; ok {
+		return enabled
+	}
+	component, ok := c.byName[name]
+	return ok && !component.DisabledByDefault
+}
+
+// SetupWithManager sets up the enabled components on mgr, in the order they
+// were registered. It returns an error, without setting up any component,
+// if an unknown component is enabled or disabled, or if an enabled
+// component depends on a disabled or unknown one.
+func (c *Components) SetupWithManager(mgr Manager) error {
+	config := mgr.GetControllerOptions().Components
+
+	c.mu.Lock()
+	var unknown []string
+	for name := range c.explicit {
+		if _, ok := c.byName[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	for name := range config {
+		if _, ok := c.byName[name]; !ok && !slices.Contains(unknown, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		c.mu.Unlock()
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown components %s", strings.Join(unknown, ", "))
+	}
+
+	var enabled []Component
+	for _, component := range c.components {
+		if !c.enabledLocked(component.Name, config) {
+			continue
+		}
+		for _, dependency := range component.DependsOn {
+			if !c.enabledLocked(dependency, config) {
+				c.mu.Unlock()
+				return fmt.Errorf("component %q depends on %q, which is disabled or unknown", component.Name, dependency)
+			}
+		}
+		enabled = append(enabled, component)
+	}
+	c.mu.Unlock()
+
+	log := mgr.GetLogger()
+	for _, component := range c.components {
+		if !slices.ContainsFunc(enabled, func(e Component) bool { return e.Name == component.Name }) {
+			log.Info("Component disabled", "component", component.Name)
+		}
+	}
+	for _, component := range enabled {
+		if err := component.Setup(mgr); err != nil {
+			return fmt.Errorf("failed to set up component %q: %w", component.Name, err)
+		}
+	}
+	return nil
+}
+
+// componentsFlag is a flag.Value enabling or disabling the components it
+// is set to.
+type componentsFlag struct {
+	components *Components
+	enabled    bool
+	value      []string
+}
+
+// Set implements flag.Value.
+func (f *componentsFlag) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			f.value = append(f.value, name)
+			f.components.set([]string{name}, f.enabled)
+		}
+	}
+	return nil
+}
+
+// String implements flag.Value.
+func (f *componentsFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.value, ",")
+}
+
+// Type returns the type of the flag value, for compatibility with
+// github.com/spf13/pflag.
+func (f *componentsFlag) Type() string {
+	return "strings"
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"flag"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/config"
+)
+
+var _ = Describe("Components", func() {
+	var setUp []string
+	component := func(name string, disabledByDefault bool, dependsOn ...string) Component {
+		return Component{
+			Name:              name,
+			DependsOn:         dependsOn,
+			DisabledByDefault: disabledByDefault,
+			Setup: func(Manager) error {
+				setUp = append(setUp, name)
+				return nil
+			},
+		}
+	}
+	newManager := func(components map[string]bool) Manager {
+		return &controllerManager{controllerConfig: config.Controller{Components: components}, logger: logr.Discard()}
+	}
+
+	BeforeEach(func() {
+		setUp = nil
+	})
+
+	DescribeTable("should set up the enabled components",
+		func(args []string, components map[string]bool, expected []string, expectedErr string) {
+			c := NewComponents()
+			Expect(c.Register(
+				component("pods", false),
+				component("pod-webhook", false, "pods"),
+				component("nodes", false),
+				component("experimental", true),
+			)).To(Succeed())
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			c.AddFlags(fs)
+			Expect(fs.Parse(args)).To(Succeed())
+
+			err := c.SetupWithManager(newManager(components))
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				Expect(setUp).To(BeEmpty(), "no component must be set up")
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(setUp).To(Equal(expected))
+		},
+		Entry("by default", nil, nil, []string{"pods", "pod-webhook", "nodes"}, ""),
+		Entry("with flags",
+			[]string{"--disable-components=nodes", "--enable-components=experimental"}, nil,
+			[]string{"pods", "pod-webhook", "experimental"}, ""),
+		Entry("with the configuration",
+			nil, map[string]bool{"nodes": false, "experimental": true},
+			[]string{"pods", "pod-webhook", "experimental"}, ""),
+		Entry("with flags taking precedence over the configuration",
+			[]string{"--enable-components=nodes"}, map[string]bool{"nodes": false},
+			[]string{"pods", "pod-webhook", "nodes"}, ""),
+		Entry("failing with a disabled dependency",
+			[]string{"--disable-components=pods"}, nil,
+			nil, `component "pod-webhook" depends on "pods", which is disabled or unknown`),
+		Entry("with a disabled dependency and dependent",
+			[]string{"--disable-components=pods,pod-webhook"}, nil,
+			[]string{"nodes"}, ""),
+		Entry("failing with unknown components",
+			[]string{"--disable-components=foo"}, map[string]bool{"bar": true},
+			nil, "unknown components bar, foo"),
+	)
+
+	It("should fail to register a component twice", func() {
+		Expect(NewComponents().Register(component("a", false), component("a", false))).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/featuregate"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// setupFeatureGates applies the feature gates of the controller
+// configuration to the gates of the options, and serves their state on the
+// metrics server.
+func setupFeatureGates(options Options, metricsServer metricsserver.Server) (*featuregate.Gates, error) {
+	if options.FeatureGates == nil {
+		if len(options.Controller.FeatureGates) > 0 {
+			return nil, errors.New("feature gates configured without FeatureGates in the manager options")
+		}
+		return featuregate.New(), nil
+	}
+
+	// Gates set explicitly, e.g. from flags, take precedence.
+	fromConfig := map[string]bool{}
+	for name, enabled := range options.Controller.FeatureGates {
+		if !options.FeatureGates.IsSet(featuregate.Feature(name)) {
+			fromConfig[name] = enabled
+		}
+	}
+	if err := options.FeatureGates.SetFromMap(fromConfig); err != nil {
+		return nil, fmt.Errorf("invalid feature gates configuration: %w", err)
+	}
+
+	if metricsServer != nil {
+		if err := metricsServer.AddExtraHandler("/featuregates", options.FeatureGates); err != nil {
+			return nil, err
+		}
+	}
+	return options.FeatureGates, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/featuregate"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+type fakeMetricsServer struct {
+	metricsserver.Server
+	handlers map[string]http.Handler
+}
+
+func (s *fakeMetricsServer) AddExtraHandler(path string, handler http.Handler) error {
+	s.handlers[path] = handler
+	return nil
+}
+
+var _ = Describe("setupFeatureGates", func() {
+	It("should apply the configured gates unless set by flags and serve them", func() {
+		gates := featuregate.New()
+		Expect(gates.Add(map[featuregate.Feature]featuregate.FeatureSpec{
+			"FromFlag":   {},
+			"FromConfig": {},
+		})).To(Succeed())
+		Expect(gates.Set("FromFlag=false")).To(Succeed())
+
+		server := &fakeMetricsServer{handlers: map[string]http.Handler{}}
+		got, err := setupFeatureGates(Options{
+			FeatureGates: gates,
+			Controller: config.Controller{
+				FeatureGates: map[string]bool{"FromFlag": true, "FromConfig": true},
+			},
+		}, server)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(BeIdenticalTo(gates))
+		Expect(gates.Enabled("FromFlag")).To(BeFalse(), "flags must take precedence over the configuration")
+		Expect(gates.Enabled("FromConfig")).To(BeTrue())
+		Expect(server.handlers).To(HaveKeyWithValue("/featuregates", gates))
+	})
+
+	It("should fail on unknown gates in the configuration", func() {
+		gates := featuregate.New()
+		_, err := setupFeatureGates(Options{
+			FeatureGates: gates,
+			Controller:   config.Controller{FeatureGates: map[string]bool{"Unknown": true}},
+		}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail on configured gates without FeatureGates", func() {
+		_, err := setupFeatureGates(Options{
+			Controller: config.Controller{FeatureGates: map[string]bool{"FromConfig": true}},
+		}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should default to empty gates", func() {
+		got, err := setupFeatureGates(Options{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ControllerGraph is the topology of the controllers of a Manager: the
+// objects each controller watches, and how their events are filtered and
+// mapped to requests. It can be exported as JSON or in the DOT language of
+// Graphviz, e.g. to document or debug operators made of many controllers.
+type ControllerGraph struct {
+	Controllers []ControllerNode `json:"controllers"`
+}
+
+// ControllerNode is a controller of a ControllerGraph.
+type ControllerNode struct {
+	Name                    string      `json:"name"`
+	NeedLeaderElection      bool        `json:"needLeaderElection"`
+	MaxConcurrentReconciles int         `json:"maxConcurrentReconciles"`
+	Watches                 []WatchInfo `json:"watches"`
+}
+
+// NewControllerGraph returns the graph of the given controllers, typically
+// those returned by Manager.GetControllers, sorted by name.
+func NewControllerGraph(controllers []ControllerInfo) ControllerGraph {
+	g := ControllerGraph{Controllers: make([]ControllerNode, 0, len(controllers))}
+	for _, c := range controllers {
+		watches := c.Watches
+		if watches == nil {
+			// Controllers that don't describe their watches in detail.
+			for _, src := range c.Sources {
+				watches = append(watches, WatchInfo{Source: src})
+			}
+		}
+		g.Controllers = append(g.Controllers, ControllerNode{
+			Name:                    c.Name,
+			NeedLeaderElection:      c.NeedLeaderElection,
+			MaxConcurrentReconciles: c.MaxConcurrentReconciles,
+			Watches:                 append([]WatchInfo{}, watches...),
+		})
+	}
+	sort.Slice(g.Controllers, func(i, j int) bool {
+		return g.Controllers[i].Name < g.Controllers[j].Name
+	})
+	return g
+}
+
+// WriteJSON writes g to w as JSON.
+func (g ControllerGraph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes g to w in the DOT language. Controllers are boxes, and
+// the types they watch are ellipses with an edge to each controller
+// watching them, labeled with the handler and predicates of the watch.
+// Sources that don't watch a single type are notes.
+func (g ControllerGraph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph controllers {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	sourceNodes := map[string]bool{}
+	for _, c := range g.Controllers {
+		fmt.Fprintf(bw, "\t%s [shape=box];\n", strconv.Quote("controller/"+c.Name))
+	}
+	for _, c := range g.Controllers {
+		for _, watch := range c.Watches {
+			node, shape := watch.Type, "ellipse"
+			if node == "" {
+				node, shape = watch.Source, "note"
+			}
+			if !sourceNodes[node] {
+				sourceNodes[node] = true
+				fmt.Fprintf(bw, "\t%s [shape=%s];\n", strconv.Quote(node), shape)
+			}
+			label := strings.Join(append([]string{watch.Handler}, watch.Predicates...), "\n")
+			fmt.Fprintf(bw, "\t%s -> %s [label=%s];\n",
+				strconv.Quote(node), strconv.Quote("controller/"+c.Name), strconv.Quote(strings.TrimPrefix(label, "\n")))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// ControllerGraphHandler returns a handler serving the ControllerGraph of
+// the controllers of mgr as JSON, or in the DOT language with the
+// "format=dot" query parameter. It can be added to the metrics server with
+// AddMetricsServerExtraHandler, e.g.
+//
+//	mgr.AddMetricsServerExtraHandler("/debug/controllers", manager.ControllerGraphHandler(mgr))
+func ControllerGraphHandler(mgr Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		g := NewControllerGraph(mgr.GetControllers())
+		if req.URL.Query().Get("format") == "dot" {
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+			_ = g.WriteDOT(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = g.WriteJSON(w)
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ControllerGraph", func() {
+	It("should describe the controllers and their watches as JSON and DOT", func() {
+		g := NewControllerGraph([]ControllerInfo{
+			{
+				Name:                    "replicaset",
+				NeedLeaderElection:      true,
+				MaxConcurrentReconciles: 2,
+				Sources:                 []string{"kind source: *v1.ReplicaSet", "kind source: *v1.Pod"},
+				Watches: []WatchInfo{
+					{Source: "kind source: *v1.ReplicaSet", Type: "*v1.ReplicaSet", Handler: "TypedEnqueueRequestForObject", Predicates: []string{"TypedGenerationChangedPredicate"}},
+					{Source: "kind source: *v1.Pod", Type: "*v1.Pod", Handler: "EnqueueRequestForOwner(ReplicaSet.apps)"},
+				},
+			},
+			{
+				Name:    "external",
+				Sources: []string{"channel source: 0xc000123456"},
+			},
+		})
+
+		Expect(g.Controllers).To(HaveLen(2))
+		Expect(g.Controllers[0].Name).To(Equal("external"), "controllers must be sorted by name")
+		Expect(g.Controllers[1].Name).To(Equal("replicaset"))
+		Expect(g.Controllers[0].Watches).To(Equal([]WatchInfo{{Source: "channel source: 0xc000123456"}}), "watches must default to the sources")
+
+		var out bytes.Buffer
+		Expect(g.WriteJSON(&out)).To(Succeed())
+		var decoded ControllerGraph
+		Expect(json.Unmarshal(out.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(g), "the JSON must round trip")
+
+		out.Reset()
+		Expect(g.WriteDOT(&out)).To(Succeed())
+		for _, line := range []string{
+			"\t\"controller/replicaset\" [shape=box];",
+			"\t\"*v1.Pod\" [shape=ellipse];",
+			"\t\"channel source: 0xc000123456\" [shape=note];",
+			"\t\"*v1.ReplicaSet\" -> \"controller/replicaset\" [label=\"TypedEnqueueRequestForObject\\nTypedGenerationChangedPredicate\"];",
+			"\t\"*v1.Pod\" -> \"controller/replicaset\" [label=\"EnqueueRequestForOwner(ReplicaSet.apps)\"];",
+			"\t\"channel source: 0xc000123456\" -> \"controller/external\" [label=\"\"];",
+		} {
+			Expect(out.String()).To(ContainSubstring(line + "\n"))
+		}
+	})
+})
+
+var _ = Describe("ControllerGraphHandler", func() {
+	It("should serve the graph as JSON, or as DOT on request", func() {
+		mgr := &controllerManager{controllers: []ControllerDescriber{
+			&describedController{info: ControllerInfo{Name: "replicaset", Sources: []string{"kind source: *v1.ReplicaSet"}}},
+		}}
+
+		rec := httptest.NewRecorder()
+		ControllerGraphHandler(mgr).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/controllers", nil))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(rec.Body.String()).To(ContainSubstring(`"name": "replicaset"`))
+
+		rec = httptest.NewRecorder()
+		ControllerGraphHandler(mgr).ServeHTTP(rec, httptest.NewRequest("GET", "/debug/controllers?format=dot", nil))
+		Expect(rec.Body.String()).To(HavePrefix("digraph controllers {"))
+	})
+})
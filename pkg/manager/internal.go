@@ -23,6 +23,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/featuregate"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
@@ -56,9 +58,22 @@ const (
 
 	defaultReadinessEndpoint = "/readyz"
 	defaultLivenessEndpoint  = "/healthz"
+
+	// leaderElectionReadyzCheck is the name of the readyz check added when
+	// readiness requires leadership.
+	leaderElectionReadyzCheck = "leader-election"
+
+	// metricsCertificateReadyzCheck and webhookCertificateReadyzCheck are
+	// the names of the readyz checks added when readiness requires valid
+	// certificates.
+	metricsCertificateReadyzCheck = "metrics-certificate"
+	webhookCertificateReadyzCheck = "webhook-certificate"
 )
 
-var _ Runnable = &controllerManager{}
+var (
+	_ Runnable                     = &controllerManager{}
+	_ cluster.TenantClientProvider = &controllerManager{}
+)
 
 type controllerManager struct {
 	sync.Mutex
@@ -68,6 +83,13 @@ type controllerManager struct {
 	errChan              chan error
 	runnables            *runnables
 
+	// shutdownRequested is closed when Shutdown is called.
+	shutdownRequested chan struct{}
+	shutdownOnce      sync.Once
+
+	// controllers are the runnables added to the manager that describe themselves as controllers.
+	controllers []ControllerDescriber
+
 	// cluster holds a variety of methods to interact with a cluster. Required.
 	cluster cluster.Cluster
 
@@ -100,6 +122,10 @@ type controllerManager struct {
 	// Healthz probe handler
 	healthzHandler *healthz.Handler
 
+	// webhookCertificateCheck adds a readyz check of the certificate of
+	// the webhook server once it is used.
+	webhookCertificateCheck bool
+
 	// pprofListener is used to serve pprof
 	pprofListener net.Listener
 
@@ -124,6 +150,9 @@ type controllerManager struct {
 	// election was configured.
 	elected chan struct{}
 
+	// leadership tracks the current leadership of the manager.
+	leadership leadership
+
 	webhookServer webhook.Server
 	// webhookServerOnce will be called in GetWebhookServer() to optionally initialize
 	// webhookServer if unset, and Add() it to controllerManager.
@@ -146,10 +175,24 @@ type controllerManager struct {
 	// before the manager actually returns on stop.
 	gracefulShutdownTimeout time.Duration
 
+	// permissionCheck configures the check of the permissions required by
+	// the controllers when starting.
+	permissionCheck PermissionCheck
+
+	// featureGates are the feature gates of the manager.
+	featureGates *featuregate.Gates
+
 	// onStoppedLeading is callled when the leader election lease is lost.
 	// It can be overridden for tests.
 	onStoppedLeading func()
 
+	// stopHooks are run when the manager stops, after all runnables have
+	// stopped. They are guarded by stopHooksMu rather than the manager lock,
+	// which can be held while stopping.
+	stopHooksMu  sync.Mutex
+	stopHooks    []StopHook
+	stopHooksRun bool
+
 	// shutdownCtx is the context that can be used during shutdown. It will be cancelled
 	// after the gracefulShutdownTimeout ended. It must not be accessed before internalStop
 	// is closed because it will be nil.
@@ -176,7 +219,13 @@ func (cm *controllerManager) Add(r Runnable) error {
 }
 
 func (cm *controllerManager) add(r Runnable) error {
-	return cm.runnables.Add(r)
+	if err := cm.runnables.Add(r); err != nil {
+		return err
+	}
+	if d, ok := r.(ControllerDescriber); ok {
+		cm.controllers = append(cm.controllers, d)
+	}
+	return nil
 }
 
 // AddMetricsServerExtraHandler adds extra handler served on path to the http server that serves metrics.
@@ -231,10 +280,30 @@ func (cm *controllerManager) AddReadyzCheck(name string, check healthz.Checker)
 	return nil
 }
 
+// leadershipChecker is the readyz check added when readiness requires
+// leadership. It fails until the manager is elected.
+func (cm *controllerManager) leadershipChecker(_ *http.Request) error {
+	select {
+	case <-cm.elected:
+		return nil
+	default:
+		return errors.New("leadership not acquired")
+	}
+}
+
 func (cm *controllerManager) GetHTTPClient() *http.Client {
 	return cm.cluster.GetHTTPClient()
 }
 
+// GetTenantClient implements cluster.TenantClientProvider.
+func (cm *controllerManager) GetTenantClient(ctx context.Context, namespace string) (client.Client, error) {
+	provider, ok := cm.cluster.(cluster.TenantClientProvider)
+	if !ok {
+		return nil, fmt.Errorf("cluster %T doesn't provide tenant clients", cm.cluster)
+	}
+	return provider.GetTenantClient(ctx, namespace)
+}
+
 func (cm *controllerManager) GetConfig() *rest.Config {
 	return cm.cluster.GetConfig()
 }
@@ -275,6 +344,11 @@ func (cm *controllerManager) GetWebhookServer() webhook.Server {
 		if err := cm.Add(cm.webhookServer); err != nil {
 			panic(fmt.Sprintf("unable to add webhook server to the controller manager: %s", err))
 		}
+		if provider, ok := cm.webhookServer.(webhook.CertificateExpiryCheckerProvider); ok && cm.webhookCertificateCheck {
+			if err := cm.AddReadyzCheck(webhookCertificateReadyzCheck, provider.CertificateExpiryChecker()); err != nil {
+				panic(fmt.Sprintf("unable to add webhook certificate readyz check to the controller manager: %s", err))
+			}
+		}
 	})
 	return cm.webhookServer
 }
@@ -287,6 +361,43 @@ func (cm *controllerManager) GetControllerOptions() config.Controller {
 	return cm.controllerConfig
 }
 
+func (cm *controllerManager) GetFeatureGates() *featuregate.Gates {
+	return cm.featureGates
+}
+
+func (cm *controllerManager) GetControllers() []ControllerInfo {
+	controllers := cm.getControllerDescribers()
+	infos := make([]ControllerInfo, 0, len(controllers))
+	for _, d := range controllers {
+		infos = append(infos, d.DescribeController())
+	}
+	return infos
+}
+
+// getControllerDescribers returns the controllers added to the manager, so
+// that they can be described without holding the lock of the manager while
+// their own locks are taken.
+func (cm *controllerManager) getControllerDescribers() []ControllerDescriber {
+	cm.Lock()
+	defer cm.Unlock()
+	return slices.Clone(cm.controllers)
+}
+
+func (cm *controllerManager) SetMaxConcurrentReconciles(controllerName string, n int) error {
+	for _, d := range cm.getControllerDescribers() {
+		if d.DescribeController().Name != controllerName {
+			continue
+		}
+		setter, ok := d.(ControllerConcurrencySetter)
+		if !ok {
+			return fmt.Errorf("the concurrency of controller %q can't be changed", controllerName)
+		}
+		setter.SetMaxConcurrentReconciles(n)
+		return nil
+	}
+	return fmt.Errorf("no controller named %q", controllerName)
+}
+
 func (cm *controllerManager) addHealthProbeServer() error {
 	mux := http.NewServeMux()
 	srv := httpserver.New(mux)
@@ -380,6 +491,12 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		}
 	}()
 
+	// Check the permissions required by the controllers before starting
+	// anything, so that missing permissions fail fast.
+	if err := cm.checkPermissions(cm.internalCtx); err != nil {
+		return err
+	}
+
 	// Add the cluster runnable.
 	if err := cm.add(cm.cluster); err != nil {
 		return fmt.Errorf("failed to add cluster to runnables: %w", err)
@@ -457,6 +574,7 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 					cm.errChan <- err
 				}
 				close(cm.elected)
+				cm.leadership.set(true)
 			}()
 		}
 	}
@@ -467,12 +585,22 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 	case <-ctx.Done():
 		// We are done
 		return nil
+	case <-cm.shutdownRequested:
+		// Shutdown was requested programmatically
+		return nil
 	case err := <-cm.errChan:
 		// Error starting or running a runnable
 		return err
 	}
 }
 
+func (cm *controllerManager) Shutdown(reason string) {
+	cm.shutdownOnce.Do(func() {
+		cm.logger.Info("Shutdown requested", "reason", reason)
+		close(cm.shutdownRequested)
+	})
+}
+
 // engageStopProcedure signals all runnables to stop, reads potential errors
 // from the errChan and waits for them to end. It must not be called more than once.
 func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) error {
@@ -485,11 +613,13 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 	//
 	// The shutdown context immediately expires if the gracefulShutdownTimeout is not set.
 	var shutdownCancel context.CancelFunc
+	var shutdownDeadline time.Time
 	if cm.gracefulShutdownTimeout < 0 {
 		// We want to wait forever for the runnables to stop.
 		cm.shutdownCtx, shutdownCancel = context.WithCancel(context.Background())
 	} else {
-		cm.shutdownCtx, shutdownCancel = context.WithTimeout(context.Background(), cm.gracefulShutdownTimeout)
+		shutdownDeadline = time.Now().Add(cm.gracefulShutdownTimeout)
+		cm.shutdownCtx, shutdownCancel = context.WithDeadline(context.Background(), shutdownDeadline)
 	}
 	defer shutdownCancel()
 
@@ -543,6 +673,7 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 		// Prevent leader election when shutting down a non-elected manager
 		cm.runnables.LeaderElection.startOnce.Do(func() {})
 		cm.runnables.LeaderElection.StopAndWait(cm.shutdownCtx)
+		cm.leadership.set(false)
 
 		// Stop the caches before the leader election runnables, this is an important
 		// step to make sure that we don't race with the reconcilers by receiving more events
@@ -563,18 +694,24 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 	}()
 
 	<-cm.shutdownCtx.Done()
+	var shutdownErr error
 	if err := cm.shutdownCtx.Err(); err != nil && !errors.Is(err, context.Canceled) {
 		if errors.Is(err, context.DeadlineExceeded) {
 			if cm.gracefulShutdownTimeout > 0 {
-				return fmt.Errorf("failed waiting for all runnables to end within grace period of %s: %w", cm.gracefulShutdownTimeout, err)
+				shutdownErr = fmt.Errorf("failed waiting for all runnables to end within grace period of %s: %w", cm.gracefulShutdownTimeout, err)
 			}
-			return nil
+		} else {
+			// For any other error, return the error.
+			shutdownErr = err
 		}
-		// For any other error, return the error.
-		return err
 	}
 
-	return nil
+	// Run the stop hooks once nothing uses what they release anymore, or
+	// the grace period expired, but before the deferred functions release
+	// the leader election lock.
+	stopHooksCtx, stopHooksCancel := stopHooksContext(shutdownDeadline)
+	defer stopHooksCancel()
+	return errors.Join(shutdownErr, cm.runStopHooks(stopHooksCtx))
 }
 
 func (cm *controllerManager) initLeaderElector() (*leaderelection.LeaderElector, error) {
@@ -590,8 +727,10 @@ func (cm *controllerManager) initLeaderElector() (*leaderelection.LeaderElector,
 					return
 				}
 				close(cm.elected)
+				cm.leadership.set(true)
 			},
 			OnStoppedLeading: func() {
+				cm.leadership.set(false)
 				if cm.onStoppedLeading != nil {
 					cm.onStoppedLeading()
 				}
@@ -621,3 +760,11 @@ func (cm *controllerManager) startLeaderElectionRunnables() error {
 func (cm *controllerManager) Elected() <-chan struct{} {
 	return cm.elected
 }
+
+func (cm *controllerManager) IsLeader() bool {
+	return cm.leadership.isLeader()
+}
+
+func (cm *controllerManager) WatchLeadership(ctx context.Context) <-chan LeadershipEvent {
+	return cm.leadership.watch(ctx)
+}
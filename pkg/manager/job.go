@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// JobFailurePolicy determines what happens when a Job fails.
+type JobFailurePolicy string
+
+const (
+	// JobFailurePolicyFatal stops the manager with the error of the job.
+	JobFailurePolicyFatal JobFailurePolicy = "Fatal"
+
+	// JobFailurePolicyRetry retries the job with an exponential backoff,
+	// and stops the manager once the retries are exhausted.
+	JobFailurePolicyRetry JobFailurePolicy = "Retry"
+)
+
+// JobFunc is the function run by a Job. The job has completed once it
+// returns nil.
+type JobFunc func(ctx context.Context) error
+
+// JobOptions configures a Job added with AddJob.
+type JobOptions struct {
+	// FailurePolicy determines what happens when the job fails. Defaults
+	// to JobFailurePolicyFatal.
+	FailurePolicy JobFailurePolicy
+
+	// MaxRetries is the number of times a job is retried with
+	// JobFailurePolicyRetry. Zero retries until the job succeeds or the
+	// manager stops.
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry, doubled on every
+	// retry up to MaxBackoff. Defaults to one second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries. Defaults to one
+	// minute.
+	MaxBackoff time.Duration
+
+	// RunOnAllReplicas runs the job on every replica as soon as the manager
+	// starts. By default, the job only runs on the leader, like a
+	// controller.
+	RunOnAllReplicas bool
+
+	// BlockReadiness adds a readiness check that fails until the job has
+	// completed. Since jobs that don't run on all replicas only run on the
+	// leader, the other replicas would never become ready; BlockReadiness
+	// is typically combined with RunOnAllReplicas.
+	BlockReadiness bool
+}
+
+// JobStatus describes the progress of a Job.
+type JobStatus struct {
+	// Attempts is the number of times the job has been run.
+	Attempts int
+
+	// Completed is set once the job has succeeded.
+	Completed bool
+
+	// LastError is the error returned by the last failed attempt, if any.
+	LastError error
+}
+
+// Job is a run-to-completion task added to a Manager with AddJob, such as a
+// migration or an initialization task. Unlike a Runnable, a Job returns
+// once it has completed, without stopping the manager.
+type Job struct {
+	name string
+	fn   JobFunc
+	opts JobOptions
+
+	mu     sync.Mutex
+	status JobStatus
+	done   chan struct{}
+}
+
+// AddJob adds a Job running fn to mgr. The job starts with the other
+// Runnables of the manager, and the manager keeps running once it has
+// completed. The returned Job lets other components wait for its
+// completion, e.g. controllers that depend on a migration.
+func AddJob(mgr Manager, name string, fn JobFunc, opts JobOptions) (*Job, error) {
+	if name == "" {
+		return nil, errors.New("must provide a job name")
+	}
+	if fn == nil {
+		return nil, errors.New("must provide a job function")
+	}
+	if opts.FailurePolicy == "" {
+		opts.FailurePolicy = JobFailurePolicyFatal
+	}
+	if opts.FailurePolicy != JobFailurePolicyFatal && opts.FailurePolicy != JobFailurePolicyRetry {
+		return nil, fmt.Errorf("unknown job failure policy %q", opts.FailurePolicy)
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+
+	j := &Job{
+		name: name,
+		fn:   fn,
+		opts: opts,
+		done: make(chan struct{}),
+	}
+	if opts.BlockReadiness {
+		if err := mgr.AddReadyzCheck("job-"+name, j.readyzCheck); err != nil {
+			return nil, err
+		}
+	}
+	if err := mgr.Add(j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Name returns the name of the job.
+func (j *Job) Name() string {
+	return j.name
+}
+
+// Done returns a channel that is closed once the job has completed.
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// Wait blocks until the job has completed or ctx is done.
+func (j *Job) Wait(ctx context.Context) error {
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Status returns the progress of the job.
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable.
+func (j *Job) NeedLeaderElection() bool {
+	return !j.opts.RunOnAllReplicas
+}
+
+// Start implements Runnable. It runs the job until it completes, and
+// returns an error if the job fails according to its failure policy.
+func (j *Job) Start(ctx context.Context) error {
+	log := crlog.FromContext(ctx).WithValues("job", j.name)
+	backoff := j.opts.InitialBackoff
+	for {
+		err := j.fn(ctx)
+
+		j.mu.Lock()
+		j.status.Attempts++
+		j.status.LastError = err
+		attempts := j.status.Attempts
+		j.status.Completed = err == nil
+		j.mu.Unlock()
+
+		if err == nil {
+			log.Info("Job completed", "attempts", attempts)
+			close(j.done)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if j.opts.FailurePolicy == JobFailurePolicyFatal ||
+			(j.opts.MaxRetries > 0 && attempts > j.opts.MaxRetries) {
+			return fmt.Errorf("job %q failed after %d attempts: %w", j.name, attempts, err)
+		}
+
+		log.Error(err, "Job failed, retrying", "attempts", attempts, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, j.opts.MaxBackoff)
+	}
+}
+
+func (j *Job) readyzCheck(_ *http.Request) error {
+	status := j.Status()
+	if status.Completed {
+		return nil
+	}
+	if status.LastError != nil {
+		return fmt.Errorf("job %q has not completed: %w", j.name, status.LastError)
+	}
+	return fmt.Errorf("job %q has not completed", j.name)
+}
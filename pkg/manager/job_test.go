@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Job", func() {
+	newManager := func() *controllerManager {
+		return &controllerManager{
+			runnables: newRunnables(func() context.Context { return context.Background() }, make(chan error, 1)),
+		}
+	}
+
+	It("should complete and unblock readiness", func() {
+		cm := newManager()
+		release := make(chan struct{})
+		job, err := AddJob(cm, "migrate", func(ctx context.Context) error {
+			<-release
+			return nil
+		}, JobOptions{RunOnAllReplicas: true, BlockReadiness: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.NeedLeaderElection()).To(BeFalse(), "the job must run on all replicas")
+		check := cm.readyzHandler.Checks["job-migrate"]
+		Expect(check).NotTo(BeNil())
+		Expect(check(nil)).To(HaveOccurred(), "the readiness check must fail until the job completes")
+
+		done := make(chan error)
+		go func() {
+			defer GinkgoRecover()
+			done <- job.Start(context.Background())
+		}()
+		close(release)
+		Expect(job.Wait(context.Background())).To(Succeed())
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(check(nil)).To(Succeed(), "the readiness check must pass once the job completed")
+		status := job.Status()
+		Expect(status.Completed).To(BeTrue())
+		Expect(status.Attempts).To(Equal(1))
+	})
+
+	It("should fail fatally by default", func() {
+		job, err := AddJob(newManager(), "fatal", func(ctx context.Context) error {
+			return errors.New("boom")
+		}, JobOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.NeedLeaderElection()).To(BeTrue(), "jobs must need leader election by default")
+		Expect(job.Start(context.Background())).NotTo(Succeed())
+		Consistently(job.Done(), 10*time.Millisecond).ShouldNot(BeClosed(), "failed jobs must not be done")
+	})
+
+	It("should retry until it succeeds", func() {
+		attempts := 0
+		job, err := AddJob(newManager(), "retry", func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		}, JobOptions{FailurePolicy: JobFailurePolicyRetry, InitialBackoff: time.Millisecond})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Start(context.Background())).To(Succeed())
+		status := job.Status()
+		Expect(status.Completed).To(BeTrue())
+		Expect(status.Attempts).To(Equal(3))
+		Expect(status.LastError).NotTo(HaveOccurred())
+	})
+
+	It("should stop once retries are exhausted", func() {
+		job, err := AddJob(newManager(), "exhausted", func(ctx context.Context) error {
+			return errors.New("boom")
+		}, JobOptions{FailurePolicy: JobFailurePolicyRetry, MaxRetries: 2, InitialBackoff: time.Millisecond})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Start(context.Background())).NotTo(Succeed())
+		status := job.Status()
+		Expect(status.Completed).To(BeFalse())
+		Expect(status.Attempts).To(Equal(3))
+	})
+
+	It("should reject unknown failure policies", func() {
+		_, err := AddJob(newManager(), "invalid", func(ctx context.Context) error { return nil }, JobOptions{FailurePolicy: "Sometimes"})
+		Expect(err).To(HaveOccurred())
+	})
+})
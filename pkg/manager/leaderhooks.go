@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+)
+
+// LeaderHooks are callbacks invoked on the leadership transitions of a
+// Manager. All of them are optional.
+type LeaderHooks struct {
+	// OnStandby is called when the manager starts. Its context is cancelled
+	// when the manager is elected, or stops, which lets components pre-warm
+	// their state while waiting for leadership so that they take over
+	// quickly. OnStandby may return early; leadership is only taken over
+	// once it has returned.
+	OnStandby func(ctx context.Context) error
+
+	// OnStartedLeading is called when the manager is elected, before the
+	// Runnable is started. Its context is cancelled when the manager stops,
+	// including when it loses leadership.
+	OnStartedLeading func(ctx context.Context) error
+
+	// OnStoppedLeading is called after the Runnable has returned when the
+	// manager stops after having been elected, typically to flush state.
+	// Its context is not cancelled. When the manager stops because it lost
+	// leadership, it doesn't wait for Runnables to return, so
+	// OnStoppedLeading may not complete.
+	OnStoppedLeading func(ctx context.Context) error
+}
+
+// AddWithLeaderHooks adds r to mgr so that it only runs while mgr is the
+// leader, like a Runnable that needs leader election, and calls hooks on the
+// leadership transitions of mgr. If leader election is disabled, the manager
+// is elected as soon as it starts. r may be nil to only run the hooks.
+func AddWithLeaderHooks(mgr Manager, r Runnable, hooks LeaderHooks) error {
+	return mgr.Add(&leaderHooksRunnable{
+		elected:  mgr.Elected(),
+		runnable: r,
+		hooks:    hooks,
+	})
+}
+
+// leaderHooksRunnable runs on all replicas, and starts the wrapped Runnable
+// once elected.
+type leaderHooksRunnable struct {
+	elected  <-chan struct{}
+	runnable Runnable
+	hooks    LeaderHooks
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable. The runnable must
+// run while on standby.
+func (l *leaderHooksRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements Runnable.
+func (l *leaderHooksRunnable) Start(ctx context.Context) error {
+	if err := l.standby(ctx); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-l.elected:
+	}
+
+	if l.hooks.OnStartedLeading != nil {
+		if err := l.hooks.OnStartedLeading(ctx); err != nil {
+			return fmt.Errorf("failed to start leading: %w", err)
+		}
+	}
+	var err error
+	if l.runnable != nil {
+		err = l.runnable.Start(ctx)
+	} else {
+		<-ctx.Done()
+	}
+
+	if l.hooks.OnStoppedLeading != nil {
+		if stopErr := l.hooks.OnStoppedLeading(context.WithoutCancel(ctx)); stopErr != nil && err == nil {
+			err = fmt.Errorf("failed to stop leading: %w", stopErr)
+		}
+	}
+	return err
+}
+
+// standby runs the OnStandby hook until it returns, or until the manager is
+// elected or stops.
+func (l *leaderHooksRunnable) standby(ctx context.Context) error {
+	if l.hooks.OnStandby == nil {
+		return nil
+	}
+	standbyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-l.elected:
+			cancel()
+		case <-standbyCtx.Done():
+		}
+	}()
+	if err := l.hooks.OnStandby(standbyCtx); err != nil && standbyCtx.Err() == nil {
+		return fmt.Errorf("failed to stand by: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("leaderHooksRunnable", func() {
+	It("should run the hooks around the runnable when elected", func() {
+		var (
+			mu    sync.Mutex
+			calls []string
+		)
+		called := func(name string) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+		}
+
+		elected := make(chan struct{})
+		standingBy := make(chan struct{})
+		running := make(chan struct{})
+		r := &leaderHooksRunnable{
+			elected: elected,
+			runnable: RunnableFunc(func(ctx context.Context) error {
+				called("run")
+				close(running)
+				<-ctx.Done()
+				return nil
+			}),
+			hooks: LeaderHooks{
+				OnStandby: func(ctx context.Context) error {
+					called("standby")
+					close(standingBy)
+					<-ctx.Done()
+					return ctx.Err()
+				},
+				OnStartedLeading: func(ctx context.Context) error {
+					called("started")
+					return nil
+				},
+				OnStoppedLeading: func(ctx context.Context) error {
+					Expect(ctx.Err()).NotTo(HaveOccurred(), "the context of OnStoppedLeading must not be cancelled")
+					called("stopped")
+					return nil
+				},
+			},
+		}
+		Expect(r.NeedLeaderElection()).To(BeFalse(), "the runnable must run while on standby")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			defer GinkgoRecover()
+			done <- r.Start(ctx)
+		}()
+
+		Eventually(standingBy).Should(BeClosed())
+		close(elected)
+		Eventually(running, 5*time.Second).Should(BeClosed())
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(calls).To(Equal([]string{"standby", "started", "run", "stopped"}))
+	})
+
+	It("should not call the leading hooks when never elected", func() {
+		stopped := false
+		r := &leaderHooksRunnable{
+			elected: make(chan struct{}),
+			hooks: LeaderHooks{
+				OnStandby: func(ctx context.Context) error {
+					return nil
+				},
+				OnStartedLeading: func(ctx context.Context) error {
+					Fail("unexpected call to OnStartedLeading")
+					return nil
+				},
+				OnStoppedLeading: func(ctx context.Context) error {
+					stopped = true
+					return nil
+				},
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(r.Start(ctx)).To(Succeed())
+		Expect(stopped).To(BeFalse(), "OnStoppedLeading must not be called when never elected")
+	})
+
+	It("should return the error of OnStandby", func() {
+		r := &leaderHooksRunnable{
+			elected: make(chan struct{}),
+			hooks: LeaderHooks{
+				OnStandby: func(ctx context.Context) error {
+					return errors.New("boom")
+				},
+			},
+		}
+		Expect(r.Start(context.Background())).To(MatchError(ContainSubstring("boom")))
+	})
+})
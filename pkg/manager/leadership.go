@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LeadershipEvent is a change of the leadership of a Manager.
+type LeadershipEvent struct {
+	// Leader is true when the manager became the leader, and false when it
+	// lost leadership or stopped while leading.
+	Leader bool
+
+	// Time is the time of the change.
+	Time time.Time
+}
+
+// leadership tracks whether a manager is the leader, and notifies the
+// subscribers of its changes. Its zero value is a non-leader without
+// subscribers.
+type leadership struct {
+	mu          sync.Mutex
+	leader      bool
+	since       time.Time
+	subscribers map[chan LeadershipEvent]struct{}
+}
+
+// set records a change of leadership, and notifies the subscribers if it
+// changed. Since a manager is elected at most once, and then only loses
+// leadership, the channels of subscribers never hold more than two events
+// and sending to them never blocks.
+func (l *leadership) set(leader bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.leader == leader {
+		return
+	}
+	l.leader = leader
+	l.since = time.Now()
+	for ch := range l.subscribers {
+		select {
+		case ch <- LeadershipEvent{Leader: leader, Time: l.since}:
+		default:
+		}
+	}
+}
+
+func (l *leadership) isLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leader
+}
+
+// watch returns a channel receiving the current leadership state, then its
+// changes, until ctx is done.
+func (l *leadership) watch(ctx context.Context) <-chan LeadershipEvent {
+	// The current state, election and loss of leadership.
+	ch := make(chan LeadershipEvent, 3)
+
+	l.mu.Lock()
+	if l.subscribers == nil {
+		l.subscribers = map[chan LeadershipEvent]struct{}{}
+	}
+	l.subscribers[ch] = struct{}{}
+	ch <- LeadershipEvent{Leader: l.leader, Time: l.since}
+	l.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subscribers, ch)
+		close(ch)
+	}()
+	return ch
+}
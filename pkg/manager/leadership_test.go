@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("leadership", func() {
+	It("should report changes of leadership to its watchers", func() {
+		var l leadership
+		Expect(l.isLeader()).To(BeFalse(), "a new manager must not be the leader")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events := l.watch(ctx)
+		var e LeadershipEvent
+		Eventually(events).Should(Receive(&e))
+		Expect(e.Leader).To(BeFalse(), "the initial event must report no leadership")
+
+		l.set(true)
+		l.set(true)
+		Expect(l.isLeader()).To(BeTrue(), "the manager must be the leader once elected")
+		Eventually(events).Should(Receive(&e))
+		Expect(e.Leader).To(BeTrue())
+		Expect(e.Time).NotTo(BeZero())
+
+		late := l.watch(context.Background())
+		Eventually(late).Should(Receive(&e))
+		Expect(e.Leader).To(BeTrue(), "late watchers must receive the current leadership")
+
+		l.set(false)
+		Expect(l.isLeader()).To(BeFalse(), "the manager must not be the leader once leadership is lost")
+		Eventually(events).Should(Receive(&e))
+		Expect(e.Leader).To(BeFalse())
+
+		cancel()
+		Eventually(events).Should(BeClosed(), "no more events must be sent once the watch is cancelled")
+	})
+})
@@ -39,7 +39,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/featuregate"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/internal/controllerinfo"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -64,6 +66,17 @@ type Manager interface {
 	// election was configured.
 	Elected() <-chan struct{}
 
+	// IsLeader reports whether this manager currently is the leader. Unlike
+	// Elected, it turns false again when leadership is lost, or when the
+	// manager stops while leading.
+	IsLeader() bool
+
+	// WatchLeadership returns a channel that receives the current leadership
+	// state of this manager, then each of its changes, so that components
+	// that are not Runnables, e.g. external schedulers, can coordinate with
+	// it. The channel is closed when ctx is done.
+	WatchLeadership(ctx context.Context) <-chan LeadershipEvent
+
 	// AddMetricsServerExtraHandler adds an extra handler served on path to the http server that serves metrics.
 	// Might be useful to register some diagnostic endpoints e.g. pprof.
 	//
@@ -73,6 +86,15 @@ type Manager interface {
 	// a new http server/listener should be added as Runnable to the manager via Add method.
 	AddMetricsServerExtraHandler(path string, handler http.Handler) error
 
+	// AddStopHook adds a hook run when the manager stops, after all its
+	// runnables have stopped, and before it releases its leader election
+	// lock. If the runnables don't stop within the graceful shutdown
+	// period, the hooks run once it expires. The context of the hook carries
+	// the remaining graceful shutdown period, but at least 5 seconds. Hooks
+	// run in the order they were added, and their errors are returned by
+	// Start.
+	AddStopHook(hook StopHook) error
+
 	// AddHealthzCheck allows you to add Healthz checker
 	AddHealthzCheck(name string, check healthz.Checker) error
 
@@ -95,6 +117,27 @@ type Manager interface {
 
 	// GetControllerOptions returns controller global configuration options.
 	GetControllerOptions() config.Controller
+
+	// GetControllers returns a description of every controller that has been
+	// added to the manager, in the order they were added.
+	GetControllers() []ControllerInfo
+
+	// SetMaxConcurrentReconciles changes the maximum number of concurrent
+	// Reconciles of the named controller, including while it runs. It fails
+	// if no such controller has been added to the manager, or if the
+	// controller doesn't implement ControllerConcurrencySetter.
+	SetMaxConcurrentReconciles(controllerName string, n int) error
+
+	// GetFeatureGates returns the feature gates of the manager, which are
+	// empty unless set in its options.
+	GetFeatureGates() *featuregate.Gates
+
+	// Shutdown makes Start return, cleanly stopping all runnables, as if its
+	// context was cancelled. The reason is logged. It can be used to stop the
+	// manager from business logic, e.g. when a fatal condition is detected.
+	// If the manager has not been started yet, Start returns as soon as it
+	// has started. Calling Shutdown more than once has no effect.
+	Shutdown(reason string)
 }
 
 // Options are the arguments for creating a new Manager.
@@ -112,6 +155,19 @@ type Options struct {
 	// used by the Client and Cache.
 	MapperProvider func(c *rest.Config, httpClient *http.Client) (meta.RESTMapper, error)
 
+	// HTTPClient is the HTTP client used to talk to the API server. Defaults
+	// to a client created by rest.HTTPClientFor from the Config.
+	HTTPClient *http.Client
+
+	// Endpoints are the URLs of other API servers of the cluster, used when
+	// the host of the Config can't be reached, including for leader election.
+	// See cluster.Options.Endpoints.
+	Endpoints []string
+
+	// EndpointProbeInterval is the interval at which the readiness of the
+	// Endpoints is probed. Defaults to 10 seconds.
+	EndpointProbeInterval time.Duration
+
 	// Cache is the cache.Options that will be used to create the default Cache.
 	// By default, the cache will watch and list requested objects in all namespaces.
 	Cache cache.Options
@@ -231,6 +287,23 @@ type Options struct {
 	// Readiness probe endpoint name, defaults to "readyz"
 	ReadinessEndpointName string
 
+	// ReadinessRequiresLeadership makes the readiness probe fail until the
+	// manager has acquired leadership, by adding a "leader-election" readyz
+	// check. It is ignored if LeaderElection is disabled.
+	//
+	// Leave it unset, the default, when all replicas serve traffic, e.g.
+	// webhooks, and set it when a Service should only route to the leader.
+	ReadinessRequiresLeadership bool
+
+	// ReadinessRequiresValidCertificates makes the readiness probe fail when
+	// the certificate served by the metrics or webhook server expires within
+	// the CertificateExpiryThreshold of its options, by adding the
+	// "metrics-certificate" and "webhook-certificate" readyz checks. The
+	// webhook check is only added once the webhook server is used, and
+	// servers that don't implement CertificateExpiryCheckerProvider are
+	// ignored.
+	ReadinessRequiresValidCertificates bool
+
 	// Liveness probe endpoint name, defaults to "healthz"
 	LivenessEndpointName string
 
@@ -269,6 +342,37 @@ type Options struct {
 	// +optional
 	Controller config.Controller
 
+	// PermissionCheck configures a check, performed when the manager starts,
+	// that the manager is allowed the permissions required by its controllers.
+	// Defaults to no check.
+	PermissionCheck PermissionCheck
+
+	// FeatureGates are the feature gates toggling the behaviors of the
+	// controllers of this manager, typically set from the --feature-gates
+	// flag. Gates of Controller.FeatureGates that are not set explicitly are
+	// set from it. If set, their state is served as JSON on the
+	// /featuregates path of the metrics server.
+	FeatureGates *featuregate.Gates
+
+	// EventsV1 makes the event recorders emit events through the
+	// events.k8s.io/v1 API rather than the core v1 one, if set. See
+	// cluster.Options.EventsV1.
+	EventsV1 *cluster.EventsV1Options
+
+	// TenantClients, if set, makes the GetTenantClient method of the
+	// manager, see cluster.TenantClientProvider, create clients with the
+	// credentials of tenants. See cluster.Options.TenantClients.
+	TenantClients *cluster.TenantClientsOptions
+
+	// APIReaderBudget, if set, makes the APIReader of the manager count the
+	// uncached reads made by each controller, and limit the reads made by a
+	// single reconcile. See cluster.Options.APIReaderBudget.
+	APIReaderBudget *client.ReadBudget
+
+	// EventSinks receive a copy of every event recorded by the event
+	// recorders of the manager. See cluster.Options.EventSinks.
+	EventSinks []recorder.Sink
+
 	// makeBroadcaster allows deferring the creation of the broadcaster to
 	// avoid leaking goroutines if we never call Start on this manager.  It also
 	// returns whether or not this is a "owned" broadcaster, and as such should be
@@ -314,6 +418,27 @@ type LeaderElectionRunnable interface {
 	NeedLeaderElection() bool
 }
 
+// ControllerInfo describes a controller that has been added to a Manager.
+type ControllerInfo = controllerinfo.ControllerInfo
+
+// WatchInfo describes a source watched by a controller.
+type WatchInfo = controllerinfo.WatchInfo
+
+// ControllerDescriber is implemented by Runnables that are controllers. The
+// Manager uses it to report the controllers it manages via GetControllers.
+type ControllerDescriber interface {
+	// DescribeController returns a description of the controller.
+	DescribeController() ControllerInfo
+}
+
+// ControllerConcurrencySetter is implemented by controllers whose maximum
+// number of concurrent Reconciles can be changed while they run.
+type ControllerConcurrencySetter interface {
+	// SetMaxConcurrentReconciles changes the maximum number of concurrent
+	// Reconciles of the controller.
+	SetMaxConcurrentReconciles(n int)
+}
+
 // New returns a new Manager for creating Controllers.
 // Note that if ContentType in the given config is not set, "application/vnd.kubernetes.protobuf"
 // will be used for all built-in resources of Kubernetes, and "application/json" is for other types
@@ -328,18 +453,26 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	cluster, err := cluster.New(config, func(clusterOptions *cluster.Options) {
 		clusterOptions.Scheme = options.Scheme
 		clusterOptions.MapperProvider = options.MapperProvider
+		clusterOptions.HTTPClient = options.HTTPClient
 		clusterOptions.Logger = options.Logger
 		clusterOptions.NewCache = options.NewCache
 		clusterOptions.NewClient = options.NewClient
 		clusterOptions.Cache = options.Cache
 		clusterOptions.Client = options.Client
+		clusterOptions.Endpoints = options.Endpoints
+		clusterOptions.EndpointProbeInterval = options.EndpointProbeInterval
 		clusterOptions.EventBroadcaster = options.EventBroadcaster //nolint:staticcheck
+		clusterOptions.EventsV1 = options.EventsV1
+		clusterOptions.EventSinks = options.EventSinks
+		clusterOptions.TenantClients = options.TenantClients
+		clusterOptions.APIReaderBudget = options.APIReaderBudget
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	config = rest.CopyConfig(config)
+	// The config of the cluster fails over between its Endpoints.
+	config = rest.CopyConfig(cluster.GetConfig())
 	if config.UserAgent == "" {
 		config.UserAgent = rest.DefaultKubernetesUserAgent()
 	}
@@ -351,6 +484,7 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	if err != nil {
 		return nil, err
 	}
+	recorderProvider.SetSinks(options.EventSinks)
 
 	// Create the resource lock to enable leader election)
 	var leaderConfig *rest.Config
@@ -378,6 +512,7 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		if err != nil {
 			return nil, err
 		}
+		leaderRecorderProvider.SetSinks(options.EventSinks)
 	}
 
 	var resourceLock resourcelock.Interface
@@ -401,6 +536,11 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		return nil, err
 	}
 
+	featureGates, err := setupFeatureGates(options, metricsServer)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create health probes listener. This will throw an error if the bind
 	// address is invalid or already in use.
 	healthProbeListener, err := options.newHealthProbeListener(options.HealthProbeBindAddress)
@@ -417,7 +557,7 @@ func New(config *rest.Config, options Options) (Manager, error) {
 
 	errChan := make(chan error, 1)
 	runnables := newRunnables(options.BaseContext, errChan)
-	return &controllerManager{
+	cm := &controllerManager{
 		stopProcedureEngaged:          ptr.To(int64(0)),
 		cluster:                       cluster,
 		runnables:                     runnables,
@@ -428,6 +568,9 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		controllerConfig:              options.Controller,
 		logger:                        options.Logger,
 		elected:                       make(chan struct{}),
+		shutdownRequested:             make(chan struct{}),
+		permissionCheck:               options.PermissionCheck,
+		featureGates:                  featureGates,
 		webhookServer:                 options.WebhookServer,
 		leaderElectionID:              options.LeaderElectionID,
 		leaseDuration:                 *options.LeaseDuration,
@@ -441,7 +584,21 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		internalProceduresStop:        make(chan struct{}),
 		leaderElectionStopped:         make(chan struct{}),
 		leaderElectionReleaseOnCancel: options.LeaderElectionReleaseOnCancel,
-	}, nil
+		webhookCertificateCheck:       options.ReadinessRequiresValidCertificates,
+	}
+	if options.LeaderElection && options.ReadinessRequiresLeadership {
+		if err := cm.AddReadyzCheck(leaderElectionReadyzCheck, cm.leadershipChecker); err != nil {
+			return nil, err
+		}
+	}
+	if options.ReadinessRequiresValidCertificates {
+		if provider, ok := metricsServer.(metricsserver.CertificateExpiryCheckerProvider); ok {
+			if err := cm.AddReadyzCheck(metricsCertificateReadyzCheck, provider.CertificateExpiryChecker()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cm, nil
 }
 
 // defaultHealthProbeListener creates the default health probes listener bound to the given address.
@@ -485,7 +642,7 @@ func setOptionsDefaults(options Options) Options {
 
 	// Allow newRecorderProvider to be mocked
 	if options.newRecorderProvider == nil {
-		options.newRecorderProvider = intrec.NewProvider
+		options.newRecorderProvider = intrec.NewProviderFunc((*intrec.EventsV1Options)(options.EventsV1))
 	}
 
 	// This is duplicated with pkg/cluster, we need it here
@@ -117,6 +117,20 @@ var _ = Describe("manger.Manager", func() {
 			Expect(err.Error()).To(ContainSubstring("expected error"))
 		})
 
+		It("should report the controllers that have been added", func() {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Add(RunnableFunc(func(context.Context) error { return nil }))).To(Succeed())
+			Expect(m.Add(&describedController{info: ControllerInfo{Name: "first"}})).To(Succeed())
+			Expect(m.Add(&describedController{info: ControllerInfo{Name: "second", NeedLeaderElection: true}})).To(Succeed())
+
+			Expect(m.GetControllers()).To(Equal([]ControllerInfo{
+				{Name: "first"},
+				{Name: "second", NeedLeaderElection: true},
+			}))
+		})
+
 		It("should lazily initialize a webhook server if needed", func() {
 			By("creating a manager with options")
 			m, err := New(cfg, Options{WebhookServer: webhook.NewServer(webhook.Options{Port: 9440, Host: "foo.com"})})
@@ -159,7 +173,81 @@ var _ = Describe("manger.Manager", func() {
 			Expect(isCustomWebhook).To(BeTrue())
 		})
 
+		It("should check the served certificates for readiness if configured", func() {
+			m, err := New(cfg, Options{
+				ReadinessRequiresValidCertificates: true,
+				WebhookServer:                      webhook.NewServer(webhook.Options{}),
+				HealthProbeBindAddress:             "0",
+				Metrics:                            metricsserver.Options{BindAddress: ":0"},
+				PprofBindAddress:                   "0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := m.(*controllerManager)
+			check, ok := cm.readyzHandler.Checks[metricsCertificateReadyzCheck]
+			Expect(ok).To(BeTrue())
+			// The metrics server doesn't serve over TLS.
+			Expect(check(nil)).To(Succeed())
+			Expect(cm.readyzHandler.Checks).NotTo(HaveKey(webhookCertificateReadyzCheck))
+
+			m.GetWebhookServer()
+			check, ok = cm.readyzHandler.Checks[webhookCertificateReadyzCheck]
+			Expect(ok).To(BeTrue())
+			Expect(check(nil)).To(MatchError(ContainSubstring("not been started")))
+		})
+
+		It("should ignore webhook servers that don't check their certificate", func() {
+			type customWebhook struct {
+				webhook.Server
+			}
+			m, err := New(cfg, Options{
+				ReadinessRequiresValidCertificates: true,
+				WebhookServer:                      customWebhook{},
+				HealthProbeBindAddress:             "0",
+				Metrics:                            metricsserver.Options{BindAddress: "0"},
+				PprofBindAddress:                   "0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			m.GetWebhookServer()
+			Expect(m.(*controllerManager).readyzHandler).To(BeNil())
+		})
+
 		Context("with leader election enabled", func() {
+			It("should only report ready once elected if readiness requires leadership", func() {
+				m, err := New(cfg, Options{
+					LeaderElection:              true,
+					LeaderElectionNamespace:     "default",
+					LeaderElectionID:            "test-leader-election-id-readyz",
+					ReadinessRequiresLeadership: true,
+					HealthProbeBindAddress:      "0",
+					Metrics:                     metricsserver.Options{BindAddress: "0"},
+					PprofBindAddress:            "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				cm := m.(*controllerManager)
+				check, ok := cm.readyzHandler.Checks[leaderElectionReadyzCheck]
+				Expect(ok).To(BeTrue())
+				Expect(check(nil)).NotTo(Succeed())
+
+				close(cm.elected)
+				Expect(check(nil)).To(Succeed())
+			})
+
+			It("should not require leadership for readiness by default", func() {
+				m, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionID:        "test-leader-election-id-readyz",
+					HealthProbeBindAddress:  "0",
+					Metrics:                 metricsserver.Options{BindAddress: "0"},
+					PprofBindAddress:        "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.(*controllerManager).readyzHandler).To(BeNil())
+			})
+
 			It("should only cancel the leader election after all runnables are done", func() {
 				m, err := New(cfg, Options{
 					LeaderElection:          true,
@@ -701,6 +789,65 @@ var _ = Describe("manger.Manager", func() {
 				Expect(m.Start(ctx)).NotTo(HaveOccurred())
 			})
 
+			It("should stop when shutdown is requested", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				done := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					defer close(done)
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				<-m.Elected()
+
+				m.Shutdown("fatal condition detected")
+				m.Shutdown("second call is a no-op")
+				Eventually(done).Should(BeClosed())
+			})
+
+			It("should run the stop hooks after the runnables have stopped", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+
+				var calls []string
+				runnableStopped := make(chan struct{})
+				Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					close(runnableStopped)
+					return nil
+				}))).To(Succeed())
+				Expect(m.AddStopHook(func(ctx context.Context) error {
+					Expect(runnableStopped).To(BeClosed())
+					_, hasDeadline := ctx.Deadline()
+					Expect(hasDeadline).To(BeTrue())
+					calls = append(calls, "first")
+					return errors.New("expected error")
+				})).To(Succeed())
+				Expect(m.AddStopHook(func(ctx context.Context) error {
+					calls = append(calls, "second")
+					return nil
+				})).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				go func() {
+					<-m.Elected()
+					cancel()
+				}()
+				Expect(m.Start(ctx)).To(MatchError(ContainSubstring("expected error")))
+				Expect(calls).To(Equal([]string{"first", "second"}))
+
+				Expect(m.AddStopHook(func(context.Context) error { return nil })).NotTo(Succeed())
+			})
+
 			It("should return an error if it can't start the cache", func() {
 				m, err := New(cfg, options)
 				Expect(err).NotTo(HaveOccurred())
@@ -1153,6 +1300,30 @@ var _ = Describe("manger.Manager", func() {
 				<-runnableStopped
 			})
 
+			It("should run the stop hooks with a minimum budget if gracefulShutdownTimeout is 0", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+				m.(*controllerManager).gracefulShutdownTimeout = time.Duration(0)
+
+				Expect(m.AddStopHook(func(ctx context.Context) error {
+					Expect(ctx.Err()).NotTo(HaveOccurred())
+					deadline, hasDeadline := ctx.Deadline()
+					Expect(hasDeadline).To(BeTrue())
+					Expect(time.Until(deadline)).To(BeNumerically(">", minStopHooksTimeout/2))
+					return errors.New("expected error")
+				})).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				go func() {
+					<-m.Elected()
+					cancel()
+				}()
+				Expect(m.Start(ctx)).To(MatchError(ContainSubstring("expected error")))
+			})
+
 			It("should wait forever for runnables if gracefulShutdownTimeout is <0 (-1)", func() {
 				m, err := New(cfg, options)
 				Expect(err).NotTo(HaveOccurred())
@@ -1943,3 +2114,15 @@ func (n *needElection) Start(_ context.Context) error {
 func (n *needElection) NeedLeaderElection() bool {
 	return true
 }
+
+type describedController struct {
+	info ControllerInfo
+}
+
+func (d *describedController) Start(_ context.Context) error {
+	return nil
+}
+
+func (d *describedController) DescribeController() ControllerInfo {
+	return d.info
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/internal/controllerinfo"
+)
+
+// Permission is a set of verbs allowed on a kind of object.
+type Permission = controllerinfo.Permission
+
+// PermissionCheckPolicy decides what a Manager does when it lacks some of
+// the permissions required by its controllers.
+type PermissionCheckPolicy string
+
+const (
+	// PermissionCheckWarn logs the missing permissions.
+	PermissionCheckWarn PermissionCheckPolicy = "Warn"
+
+	// PermissionCheckFail makes Start fail if any permission is missing.
+	PermissionCheckFail PermissionCheckPolicy = "Fail"
+)
+
+// PermissionCheck configures the check of the permissions required by the
+// controllers of a Manager, which is performed when the Manager starts.
+type PermissionCheck struct {
+	// Policy decides what to do when permissions are missing. Empty
+	// disables the check.
+	Policy PermissionCheckPolicy
+
+	// Namespaces are the namespaces in which permissions on namespaced
+	// kinds are checked. Defaults to checking for permissions in all
+	// namespaces, which is what a cache watching all namespaces requires.
+	Namespaces []string
+}
+
+// RequiredPermissions returns the permissions required by the given
+// controllers, merged by kind and sorted.
+func RequiredPermissions(controllers []ControllerInfo) []Permission {
+	verbs := map[schema.GroupVersionKind][]string{}
+	for _, info := range controllers {
+		for _, p := range info.Permissions {
+			for _, verb := range p.Verbs {
+				if !slices.Contains(verbs[p.GroupVersionKind], verb) {
+					verbs[p.GroupVersionKind] = append(verbs[p.GroupVersionKind], verb)
+				}
+			}
+		}
+	}
+
+	perms := make([]Permission, 0, len(verbs))
+	for gvk, v := range verbs {
+		slices.Sort(v)
+		perms = append(perms, Permission{GroupVersionKind: gvk, Verbs: v})
+	}
+	slices.SortFunc(perms, func(a, b Permission) int {
+		return strings.Compare(a.GroupVersionKind.String(), b.GroupVersionKind.String())
+	})
+	return perms
+}
+
+// CheckPermissions checks the given permissions with SelfSubjectAccessReviews
+// created with c, and returns those that are not allowed. Permissions on
+// namespaced kinds are checked in each of namespaces, or in all namespaces
+// if none are given.
+func CheckPermissions(ctx context.Context, c client.Client, perms []Permission, namespaces ...string) ([]Permission, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	var missing []Permission
+	for _, p := range perms {
+		mapping, err := c.RESTMapper().RESTMapping(p.GroupKind(), p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get REST mapping for %s: %w", p.GroupVersionKind, err)
+		}
+
+		checkNamespaces := []string{""}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			checkNamespaces = namespaces
+		}
+		for _, ns := range checkNamespaces {
+			denied := Permission{GroupVersionKind: p.GroupVersionKind, Namespace: ns}
+			for _, verb := range p.Verbs {
+				review := &authorizationv1.SelfSubjectAccessReview{
+					Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+						ResourceAttributes: &authorizationv1.ResourceAttributes{
+							Namespace: ns,
+							Verb:      verb,
+							Group:     mapping.Resource.Group,
+							Version:   mapping.Resource.Version,
+							Resource:  mapping.Resource.Resource,
+						},
+					},
+				}
+				if err := c.Create(ctx, review); err != nil {
+					return nil, fmt.Errorf("failed to review permission to %s %s: %w", verb, mapping.Resource, err)
+				}
+				if !review.Status.Allowed {
+					denied.Verbs = append(denied.Verbs, verb)
+				}
+			}
+			if len(denied.Verbs) > 0 {
+				missing = append(missing, denied)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// checkPermissions performs the permission check configured on the
+// manager, if any.
+func (cm *controllerManager) checkPermissions(ctx context.Context) error {
+	if cm.permissionCheck.Policy == "" {
+		return nil
+	}
+
+	perms := make([]ControllerInfo, 0, len(cm.controllers))
+	for _, d := range cm.controllers {
+		perms = append(perms, d.DescribeController())
+	}
+	missing, err := CheckPermissions(ctx, cm.cluster.GetClient(), RequiredPermissions(perms), cm.permissionCheck.Namespaces...)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, 0, len(missing))
+	for _, p := range missing {
+		descriptions = append(descriptions, p.String())
+	}
+	if cm.permissionCheck.Policy == PermissionCheckFail {
+		return fmt.Errorf("missing permissions required by controllers: %s", strings.Join(descriptions, "; "))
+	}
+	cm.logger.Info("Missing permissions required by controllers, check the RBAC rules of the manager", "missing", descriptions)
+	return nil
+}
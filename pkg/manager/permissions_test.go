@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var (
+	podGVK  = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	nodeGVK = schema.GroupVersionKind{Version: "v1", Kind: "Node"}
+)
+
+var _ = Describe("RequiredPermissions", func() {
+	It("should merge the permissions of the controllers by kind", func() {
+		perms := RequiredPermissions([]ControllerInfo{
+			{Name: "a", Permissions: []Permission{
+				{GroupVersionKind: podGVK, Verbs: []string{"watch", "list", "get"}},
+			}},
+			{Name: "b", Permissions: []Permission{
+				{GroupVersionKind: podGVK, Verbs: []string{"get", "update"}},
+				{GroupVersionKind: nodeGVK, Verbs: []string{"get"}},
+			}},
+		})
+
+		Expect(perms).To(Equal([]Permission{
+			{GroupVersionKind: nodeGVK, Verbs: []string{"get"}},
+			{GroupVersionKind: podGVK, Verbs: []string{"get", "list", "update", "watch"}},
+		}))
+	})
+})
+
+var _ = Describe("CheckPermissions", func() {
+	It("should return the permissions that are not allowed", func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+		mapper.Add(nodeGVK, meta.RESTScopeRoot)
+
+		var reviewed []authorizationv1.ResourceAttributes
+		c := fake.NewClientBuilder().WithRESTMapper(mapper).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review := obj.(*authorizationv1.SelfSubjectAccessReview)
+				attrs := review.Spec.ResourceAttributes
+				reviewed = append(reviewed, *attrs)
+				// Deny updating pods in namespace "b" and watching nodes.
+				review.Status.Allowed = !(attrs.Resource == "pods" && attrs.Namespace == "b" && attrs.Verb == "update") &&
+					!(attrs.Resource == "nodes" && attrs.Verb == "watch")
+				return nil
+			},
+		}).Build()
+
+		missing, err := CheckPermissions(context.Background(), c, []Permission{
+			{GroupVersionKind: nodeGVK, Verbs: []string{"get", "watch"}},
+			{GroupVersionKind: podGVK, Verbs: []string{"get", "update"}},
+		}, "a", "b")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(missing).To(Equal([]Permission{
+			{GroupVersionKind: nodeGVK, Verbs: []string{"watch"}},
+			{GroupVersionKind: podGVK, Namespace: "b", Verbs: []string{"update"}},
+		}))
+		Expect(reviewed).To(HaveLen(6))
+		Expect(reviewed[0].Namespace).To(BeEmpty(), "cluster-scoped kinds must be reviewed without namespace")
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// SourceQuarantineChecker returns a health check failing while sources of
+// the controllers of mgr are quarantined, see the SourceQuarantine option of
+// controllers. Add it as a readiness check to mark a manager whose watches
+// are broken as degraded without restarting it:
+//
+//	mgr.AddReadyzCheck("sources", manager.SourceQuarantineChecker(mgr))
+func SourceQuarantineChecker(mgr Manager) healthz.Checker {
+	return func(*http.Request) error {
+		var quarantined []string
+		for _, info := range mgr.GetControllers() {
+			for _, src := range info.QuarantinedSources {
+				quarantined = append(quarantined, fmt.Sprintf("%s (controller %s)", src, info.Name))
+			}
+		}
+		if len(quarantined) > 0 {
+			return fmt.Errorf("sources quarantined: %s", strings.Join(quarantined, ", "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SourceQuarantineChecker", func() {
+	It("should fail while sources are quarantined", func() {
+		healthy := &describedController{info: ControllerInfo{Name: "a", Sources: []string{"kind source: *v1.Pod"}}}
+		cm := &controllerManager{controllers: []ControllerDescriber{healthy}}
+		Expect(SourceQuarantineChecker(cm)(nil)).To(Succeed())
+
+		cm.controllers = append(cm.controllers, &describedController{info: ControllerInfo{
+			Name:               "b",
+			Sources:            []string{"kind source: *v1.Foo"},
+			QuarantinedSources: []string{"kind source: *v1.Foo"},
+		}})
+		Expect(SourceQuarantineChecker(cm)(nil)).To(MatchError("sources quarantined: kind source: *v1.Foo (controller b)"))
+	})
+})
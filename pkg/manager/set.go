@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+var _ cluster.Provider = &Set{}
+
+// SetOptions configures a Set.
+type SetOptions struct {
+	// Scheme is the scheme of all the managers of the set. Defaults to the
+	// kubernetes/client-go scheme.Scheme.
+	Scheme *runtime.Scheme
+
+	// HTTPClient, if set, is the HTTP client of all the managers of the set
+	// whose Options don't specify one. It can only be shared by managers
+	// that talk to API servers with the same TLS and authentication
+	// settings.
+	HTTPClient *http.Client
+
+	// Metrics configures the metrics server of the set, which serves the
+	// metrics of all its managers from the shared metrics.Registry. The
+	// managers of the set don't serve metrics themselves. The config and
+	// HTTP client of the first manager added are used by its
+	// FilterProvider, if any.
+	Metrics metricsserver.Options
+}
+
+// Set runs several managers in a single process, e.g. one per cluster, with
+// shared dependencies and a coordinated lifecycle: they are started
+// together, and all of them are stopped when one of them fails. Managers
+// can be added and removed while the set runs, e.g. as clusters join and
+// leave a fleet.
+//
+// Controllers are named and report metrics globally, so the controllers of
+// different managers of a set must have distinct names or MetricsLabel
+// options, e.g. suffixed with the name of their manager.
+type Set struct {
+	options SetOptions
+
+	mu       sync.Mutex
+	managers map[string]*setMember
+	names    []string
+
+	// ctx is set once the set has been started, and errs receives the
+	// first error of its managers.
+	ctx  context.Context
+	errs chan error
+	wg   sync.WaitGroup
+}
+
+type setMember struct {
+	mgr    Manager
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSet returns a new Set.
+func NewSet(options SetOptions) *Set {
+	if options.Scheme == nil {
+		options.Scheme = scheme.Scheme
+	}
+	return &Set{
+		options:  options,
+		managers: map[string]*setMember{},
+	}
+}
+
+// Add creates a manager named name for config with options, with the
+// dependencies shared by the set overriding those of options. The manager
+// is started right away if the set is running.
+func (s *Set) Add(name string, config *rest.Config, options Options) (Manager, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.managers[name]; ok {
+		return nil, fmt.Errorf("manager %q already exists in the set", name)
+	}
+	options.Scheme = s.options.Scheme
+	if options.HTTPClient == nil {
+		options.HTTPClient = s.options.HTTPClient
+	}
+	options.Metrics = metricsserver.Options{BindAddress: "0"}
+	mgr, err := New(config, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manager %q: %w", name, err)
+	}
+
+	member := &setMember{mgr: mgr, done: make(chan struct{})}
+	s.managers[name] = member
+	s.names = append(s.names, name)
+	if s.ctx != nil {
+		s.start(name, member)
+	}
+	return mgr, nil
+}
+
+// Get returns the manager named name.
+func (s *Set) Get(name string) (Manager, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	member, ok := s.managers[name]
+	if !ok {
+		return nil, false
+	}
+	return member.mgr, true
+}
+
+// GetCluster implements cluster.Provider, returning the manager named name,
+// so that controllers reconciling ClusterRequests can route their requests
+// to the managers of the set with cluster.ForClusters.
+func (s *Set) GetCluster(_ context.Context, name string) (cluster.Cluster, error) {
+	mgr, ok := s.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", cluster.ErrClusterNotFound, name)
+	}
+	return mgr, nil
+}
+
+// Names returns the names of the managers of the set, in the order they
+// were added.
+func (s *Set) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.names)
+}
+
+// Remove removes the manager named name from the set, stopping it and
+// waiting for it to stop if the set is running. Stopping a removed manager
+// doesn't stop the set.
+func (s *Set) Remove(name string) {
+	s.mu.Lock()
+	member, ok := s.managers[name]
+	if ok {
+		delete(s.managers, name)
+		s.names = slices.DeleteFunc(s.names, func(n string) bool { return n == name })
+	}
+	s.mu.Unlock()
+
+	if ok && member.cancel != nil {
+		member.cancel()
+		<-member.done
+	}
+}
+
+// Start starts all the managers of the set and the metrics server, and
+// blocks until ctx is done or one of them fails, after which it stops all
+// of them and waits for them to stop. It returns the first error of the
+// managers.
+func (s *Set) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	if s.ctx != nil {
+		s.mu.Unlock()
+		return errors.New("manager set was started more than once")
+	}
+	s.ctx = ctx
+	s.errs = make(chan error, 1)
+
+	var config *rest.Config
+	var httpClient *http.Client
+	if len(s.names) > 0 {
+		first := s.managers[s.names[0]].mgr
+		config, httpClient = first.GetConfig(), first.GetHTTPClient()
+	}
+	metricsServer, err := metricsserver.NewServer(s.options.Metrics, config, httpClient)
+	if err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to create metrics server: %w", err)
+	}
+	if metricsServer != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := metricsServer.Start(ctx); err != nil {
+				s.fail(fmt.Errorf("metrics server: %w", err))
+			}
+		}()
+	}
+	for _, name := range s.names {
+		s.start(name, s.managers[name])
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+	case err = <-s.errs:
+	}
+	cancel()
+	s.wg.Wait()
+	return err
+}
+
+// start starts the manager of member. It must be called with mu held, once
+// the set has been started.
+func (s *Set) start(name string, member *setMember) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	member.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(member.done)
+		if err := member.mgr.Start(ctx); err != nil {
+			s.fail(fmt.Errorf("manager %q: %w", name, err))
+		}
+	}()
+}
+
+// fail reports err unless an error was already reported.
+func (s *Set) fail(err error) {
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+var _ = Describe("Set", func() {
+	It("should run its managers together, including the ones added while running", func() {
+		config := &rest.Config{Host: "http://127.0.0.1:1"}
+		scheme := runtime.NewScheme()
+		set := NewSet(SetOptions{Scheme: scheme, Metrics: metricsserver.Options{BindAddress: "0"}})
+
+		a, err := set.Add("a", config, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		b, err := set.Add("b", config, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = set.Add("a", config, Options{})
+		Expect(err).To(HaveOccurred(), "adding a manager twice must fail")
+		Expect(a.GetScheme()).To(BeIdenticalTo(scheme), "the managers must share the scheme of the set")
+		Expect(b.GetScheme()).To(BeIdenticalTo(scheme))
+		Expect(set.Names()).To(Equal([]string{"a", "b"}))
+		cl, err := set.GetCluster(context.Background(), "a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cl).To(BeIdenticalTo(a))
+		_, err = set.GetCluster(context.Background(), "c")
+		Expect(err).To(MatchError(cluster.ErrClusterNotFound))
+
+		aStopped := make(chan struct{})
+		Expect(a.Add(RunnableFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			close(aStopped)
+			return nil
+		}))).To(Succeed())
+		failB := make(chan struct{})
+		Expect(b.Add(RunnableFunc(func(ctx context.Context) error {
+			select {
+			case <-failB:
+				return errors.New("boom")
+			case <-ctx.Done():
+				return nil
+			}
+		}))).To(Succeed())
+
+		done := make(chan error)
+		go func() {
+			defer GinkgoRecover()
+			done <- set.Start(context.Background())
+		}()
+
+		By("starting the managers added to the running set")
+		cStarted, cStopped := make(chan struct{}), make(chan struct{})
+		c, err := set.Add("c", config, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Add(RunnableFunc(func(ctx context.Context) error {
+			close(cStarted)
+			<-ctx.Done()
+			close(cStopped)
+			return nil
+		}))).To(Succeed())
+		Eventually(cStarted, 10*time.Second).Should(BeClosed())
+
+		By("stopping the removed managers")
+		set.Remove("c")
+		Eventually(cStopped, 10*time.Second).Should(BeClosed())
+		_, ok := set.Get("c")
+		Expect(ok).To(BeFalse())
+
+		By("stopping all the managers when one fails")
+		close(failB)
+		var startErr error
+		Eventually(done, 10*time.Second).Should(Receive(&startErr))
+		Expect(startErr).To(MatchError(ContainSubstring(`manager "b"`)))
+		Expect(aStopped).To(BeClosed())
+	})
+})
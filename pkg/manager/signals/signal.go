@@ -24,22 +24,89 @@ import (
 
 var onlyOneSignalHandler = make(chan struct{})
 
+// Options configures the signal handler set up by SetupSignalHandlerWithOptions.
+type Options struct {
+	// ShutdownSignals are the signals that cancel the returned context.
+	// Defaults to SIGTERM and SIGINT.
+	ShutdownSignals []os.Signal
+
+	// ReloadSignals are the signals that trigger OnReload.
+	// Defaults to SIGHUP on platforms that support it.
+	ReloadSignals []os.Signal
+
+	// OnReload, if set, is called every time a reload signal is caught,
+	// e.g. to reload configuration files. It is called sequentially from
+	// a single goroutine.
+	OnReload func()
+}
+
 // SetupSignalHandler registers for SIGTERM and SIGINT. A context is returned
 // which is canceled on one of these signals. If a second signal is caught, the program
 // is terminated with exit code 1.
 func SetupSignalHandler() context.Context {
+	return SetupSignalHandlerWithOptions(Options{})
+}
+
+// SetupSignalHandlerWithOptions is like SetupSignalHandler, but allows
+// customizing the shutdown signals and registering a callback to be called
+// on reload signals. Only one of SetupSignalHandler and
+// SetupSignalHandlerWithOptions may be called.
+func SetupSignalHandlerWithOptions(opts Options) context.Context {
+	ctx, _ := setupSignalHandler(opts)
+	return ctx
+}
+
+// setupSignalHandler sets up the signal handler, and returns a function
+// removing it, for testing.
+func setupSignalHandler(opts Options) (context.Context, func()) {
 	close(onlyOneSignalHandler) // panics when called twice
 
+	if len(opts.ShutdownSignals) == 0 {
+		opts.ShutdownSignals = shutdownSignals
+	}
+	if len(opts.ReloadSignals) == 0 {
+		opts.ReloadSignals = reloadSignals
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
 
 	c := make(chan os.Signal, 2)
-	signal.Notify(c, shutdownSignals...)
+	signal.Notify(c, opts.ShutdownSignals...)
 	go func() {
-		<-c
+		select {
+		case <-c:
+		case <-stopped:
+			return
+		}
 		cancel()
-		<-c
+		select {
+		case <-c:
+		case <-stopped:
+			return
+		}
 		os.Exit(1) // second signal. Exit directly.
 	}()
 
-	return ctx
+	if opts.OnReload != nil && len(opts.ReloadSignals) > 0 {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, opts.ReloadSignals...)
+		go func() {
+			defer signal.Stop(reload)
+			for {
+				select {
+				case <-reload:
+					opts.OnReload()
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	return ctx, func() {
+		signal.Stop(c)
+		close(stopped)
+		cancel()
+	}
 }
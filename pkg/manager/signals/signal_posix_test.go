@@ -0,0 +1,51 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signals
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("runtime signal with options", func() {
+	It("should call OnReload on reload signals and cancel on custom shutdown signals", func() {
+		var reloads atomic.Int32
+		ctx, stop := setupSignalHandler(Options{
+			ShutdownSignals: []os.Signal{syscall.SIGUSR1},
+			ReloadSignals:   []os.Signal{syscall.SIGUSR2},
+			OnReload: func() {
+				reloads.Add(1)
+			},
+		})
+		// Don't leave a handler exiting on a second SIGUSR1 installed.
+		DeferCleanup(stop)
+
+		Expect(syscall.Kill(os.Getpid(), syscall.SIGUSR2)).To(Succeed())
+		Eventually(reloads.Load).Should(BeEquivalentTo(1))
+		Consistently(ctx.Done()).ShouldNot(BeClosed())
+
+		Expect(syscall.Kill(os.Getpid(), syscall.SIGUSR1)).To(Succeed())
+		Eventually(ctx.Done()).Should(BeClosed())
+	})
+})
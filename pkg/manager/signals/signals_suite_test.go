@@ -32,3 +32,12 @@ func TestSource(t *testing.T) {
 var _ = BeforeSuite(func() {
 	signal.Reset()
 })
+
+var _ = BeforeEach(func() {
+	// Allow every spec to set up a signal handler.
+	previous := onlyOneSignalHandler
+	onlyOneSignalHandler = make(chan struct{})
+	DeferCleanup(func() {
+		onlyOneSignalHandler = previous
+	})
+})
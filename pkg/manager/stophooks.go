@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// minStopHooksTimeout is the minimum time the stop hooks are given to run,
+// even if the graceful shutdown period is shorter or has been used up by
+// the runnables.
+const minStopHooksTimeout = 5 * time.Second
+
+// StopHook is a function run when the manager stops, e.g. to flush buffers,
+// deregister from external systems or release locks. Its context expires at
+// the end of the graceful shutdown period of the manager, but no sooner than
+// 5 seconds after the hooks started running.
+type StopHook func(ctx context.Context) error
+
+// AddStopHook adds a hook run when the manager stops.
+func (cm *controllerManager) AddStopHook(hook StopHook) error {
+	cm.stopHooksMu.Lock()
+	defer cm.stopHooksMu.Unlock()
+
+	if cm.stopHooksRun {
+		return errors.New("unable to add stop hook because the manager is already stopping")
+	}
+	cm.stopHooks = append(cm.stopHooks, hook)
+	return nil
+}
+
+// stopHooksContext returns the context to run the stop hooks with, which
+// expires at shutdownDeadline but no sooner than minStopHooksTimeout from
+// now, or never if shutdownDeadline is zero.
+func stopHooksContext(shutdownDeadline time.Time) (context.Context, context.CancelFunc) {
+	if shutdownDeadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	if minDeadline := time.Now().Add(minStopHooksTimeout); shutdownDeadline.Before(minDeadline) {
+		shutdownDeadline = minDeadline
+	}
+	return context.WithDeadline(context.Background(), shutdownDeadline)
+}
+
+// runStopHooks runs the stop hooks in the order they were added, and
+// returns their errors. A hook failing doesn't prevent the next ones from
+// running.
+func (cm *controllerManager) runStopHooks(ctx context.Context) error {
+	cm.stopHooksMu.Lock()
+	cm.stopHooksRun = true
+	hooks := cm.stopHooks
+	cm.stopHooksMu.Unlock()
+
+	if len(hooks) == 0 {
+		return nil
+	}
+	cm.logger.Info("Running stop hooks")
+	var errs []error
+	for i, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			cm.logger.Error(err, "Stop hook failed", "hook", i)
+			errs = append(errs, fmt.Errorf("stop hook %d failed: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
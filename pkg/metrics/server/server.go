@@ -33,6 +33,7 @@ import (
 	certutil "k8s.io/client-go/util/cert"
 
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -58,6 +59,19 @@ type Server interface {
 	Start(ctx context.Context) error
 }
 
+// CertificateExpiryCheckerProvider is implemented by the Servers that
+// monitor the expiry of the certificate they serve, like the Server
+// returned by NewServer.
+type CertificateExpiryCheckerProvider interface {
+	// CertificateExpiryChecker returns an healthz.Checker which fails when
+	// the certificate served by the server expires within the
+	// CertificateExpiryThreshold of its options, or before the server has
+	// been started. It never fails if the server doesn't serve over TLS.
+	CertificateExpiryChecker() healthz.Checker
+}
+
+var _ CertificateExpiryCheckerProvider = &defaultServer{}
+
 // Options are all available options for the metrics.Server
 type Options struct {
 	// SecureServing enables serving metrics via https.
@@ -105,8 +119,28 @@ type Options struct {
 	// This also allows providing a certificate via GetCertificate.
 	TLSOpts []func(*tls.Config)
 
+	// CertificateSource provides the serving certificate, e.g. from a SPIFFE
+	// Workload API or a cloud certificate issuer, instead of reading it from
+	// CertDir. The server starts the source and stops it on shutdown.
+	//
+	// Note: This option is only used when TLSOpts does not set GetCertificate.
+	CertificateSource certwatcher.Source
+
+	// CertificateExpiryThreshold is how long before the served certificate
+	// expires the checker returned by CertificateExpiryChecker starts
+	// failing. Defaults to zero, which only fails once it has expired. The
+	// expiry time of the certificate is exported in the
+	// certwatcher_certificate_expiration_timestamp_seconds metric with the
+	// name "metrics".
+	CertificateExpiryThreshold time.Duration
+
 	// ListenConfig contains options for listening to an address on the metric server.
 	ListenConfig net.ListenConfig
+
+	// EnableOpenMetrics serves metrics in the OpenMetrics format to scrapers
+	// that request it. It is required to expose exemplars, such as those of
+	// controllers with ReconcileExemplars enabled.
+	EnableOpenMetrics bool
 }
 
 // Filter is a func that is added around metrics and extra handlers on the metrics server.
@@ -152,12 +186,16 @@ type defaultServer struct {
 	// the metrics and the extra handlers on the metrics server.
 	metricsFilter Filter
 
-	// mu protects access to the bindAddr field.
+	// mu protects access to the bindAddr and expiryMonitor fields.
 	mu sync.RWMutex
 
 	// bindAddr is used to store the bindAddr after the listener has been created.
 	// This is used during testing to figure out the port that has been chosen randomly.
 	bindAddr string
+
+	// expiryMonitor monitors the expiry of the served certificate once the
+	// listener has been created, when serving over TLS.
+	expiryMonitor *certwatcher.ExpiryMonitor
 }
 
 // setDefaults does defaulting for the Server.
@@ -219,7 +257,8 @@ func (s *defaultServer) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 
 	handler := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
-		ErrorHandling: promhttp.HTTPErrorOnError,
+		ErrorHandling:     promhttp.HTTPErrorOnError,
+		EnableOpenMetrics: s.options.EnableOpenMetrics,
 	})
 	if s.metricsFilter != nil {
 		log := log.WithValues("path", defaultMetricsEndpoint)
@@ -283,6 +322,16 @@ func (s *defaultServer) createListener(ctx context.Context, log logr.Logger) (ne
 		op(cfg)
 	}
 
+	if cfg.GetCertificate == nil && s.options.CertificateSource != nil {
+		cfg.GetCertificate = s.options.CertificateSource.GetCertificate
+
+		go func() {
+			if err := s.options.CertificateSource.Start(ctx); err != nil {
+				log.Error(err, "certificate source error")
+			}
+		}()
+	}
+
 	if cfg.GetCertificate == nil {
 		certPath := filepath.Join(s.options.CertDir, s.options.CertName)
 		keyPath := filepath.Join(s.options.CertDir, s.options.KeyName)
@@ -323,7 +372,15 @@ func (s *defaultServer) createListener(ctx context.Context, log logr.Logger) (ne
 			return nil, fmt.Errorf("failed to create self-signed key pair for metrics server: %w", err)
 		}
 		cfg.Certificates = []tls.Certificate{keyPair}
+		cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &keyPair, nil
+		}
 	}
+	expiryMonitor := certwatcher.NewExpiryMonitor("metrics", cfg.GetCertificate, s.options.CertificateExpiryThreshold)
+	cfg.GetCertificate = expiryMonitor.GetCertificate
+	s.mu.Lock()
+	s.expiryMonitor = expiryMonitor
+	s.mu.Unlock()
 
 	l, err := s.options.ListenConfig.Listen(ctx, "tcp", s.options.BindAddress)
 	if err != nil {
@@ -333,6 +390,28 @@ func (s *defaultServer) createListener(ctx context.Context, log logr.Logger) (ne
 	return tls.NewListener(l, cfg), nil
 }
 
+// CertificateExpiryChecker returns an healthz.Checker which fails when the
+// served certificate expires within the CertificateExpiryThreshold of the
+// options of the server, or before the server has been started.
+func (s *defaultServer) CertificateExpiryChecker() healthz.Checker {
+	return func(req *http.Request) error {
+		if !s.options.SecureServing {
+			return nil
+		}
+		s.mu.RLock()
+		expiryMonitor := s.expiryMonitor
+		s.mu.RUnlock()
+
+		if expiryMonitor == nil {
+			return fmt.Errorf("metrics server has not been started yet")
+		}
+		if err := expiryMonitor.Check(req); err != nil {
+			return fmt.Errorf("metrics server certificate: %w", err)
+		}
+		return nil
+	}
+}
+
 func (s *defaultServer) GetBindAddr() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -99,6 +99,19 @@ func init() {
 	workqueue.SetProvider(workqueueMetricsProvider{})
 }
 
+// DeleteWorkqueueMetrics deletes the series of the metrics of the workqueue
+// with the given name, e.g. once the controller using it has been stopped.
+func DeleteWorkqueueMetrics(name string) {
+	labels := prometheus.Labels{"name": name}
+	depth.DeletePartialMatch(labels)
+	adds.DeletePartialMatch(labels)
+	latency.DeletePartialMatch(labels)
+	workDuration.DeletePartialMatch(labels)
+	unfinished.DeletePartialMatch(labels)
+	longestRunningProcessor.DeletePartialMatch(labels)
+	retries.DeletePartialMatch(labels)
+}
+
 type workqueueMetricsProvider struct{}
 
 func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
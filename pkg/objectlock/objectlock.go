@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objectlock lets instances of a controller that run without
+// leader election partition their work by locking the objects they
+// reconcile. An instance only reconciles the objects it holds the lock of;
+// the lock of an object expires when its holder stops renewing it, after
+// which another instance may take it over according to a StealPolicy.
+//
+// Locks are persisted by a Store, either as an annotation on the object
+// itself or as a Lease per object:
+//
+//	locker := &objectlock.Locker{
+//		Store:    &objectlock.LeaseStore{Client: mgr.GetClient()},
+//		Identity: os.Getenv("POD_NAME"),
+//	}
+//	err := builder.ControllerManagedBy(mgr).
+//		For(&appsv1.Deployment{}).
+//		Complete(objectlock.Reconciler(mgr.GetClient(), func() client.Object { return &appsv1.Deployment{} }, locker, r))
+package objectlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultLeaseDuration is the duration of the locks of a Locker that does
+// not specify one.
+const DefaultLeaseDuration = time.Minute
+
+// Record describes the lock of an object.
+type Record struct {
+	// Holder is the identity of the instance holding the lock.
+	Holder string `json:"holder"`
+
+	// AcquireTime is when the holder acquired the lock.
+	AcquireTime metav1.Time `json:"acquireTime"`
+
+	// RenewTime is when the holder last renewed the lock.
+	RenewTime metav1.Time `json:"renewTime"`
+
+	// LeaseDuration is how long the lock is held after it was renewed.
+	LeaseDuration metav1.Duration `json:"leaseDuration"`
+
+	// Transitions is the number of times the lock changed holders.
+	Transitions int32 `json:"transitions,omitempty"`
+
+	// resourceVersion is the resource version the record was loaded at,
+	// used by stores that persist records in their own objects.
+	resourceVersion string
+}
+
+// Expired returns true if the lock was not renewed within its lease
+// duration before now.
+func (r *Record) Expired(now time.Time) bool {
+	return !now.Before(r.RenewTime.Add(r.LeaseDuration.Duration))
+}
+
+// StealPolicy decides whether an instance may take over the lock described
+// by record, held by another instance.
+type StealPolicy func(record Record, now time.Time) bool
+
+// StealExpired takes over locks that expired. It is the default policy.
+func StealExpired(record Record, now time.Time) bool {
+	return record.Expired(now)
+}
+
+// NeverSteal never takes over locks, which are then only released by their
+// holder. It guarantees that an object is never reconciled by two instances
+// concurrently, at the price of objects staying unreconciled while their
+// holder is gone.
+func NeverSteal(Record, time.Time) bool {
+	return false
+}
+
+// StealAfter takes over locks that have been expired for longer than grace,
+// to tolerate holders that are slow to renew their locks.
+func StealAfter(grace time.Duration) StealPolicy {
+	return func(record Record, now time.Time) bool {
+		return record.Expired(now.Add(-grace))
+	}
+}
+
+// Locker acquires and releases the locks of objects on behalf of an
+// instance. It is safe for concurrent use by different reconciles, as long
+// as they lock different objects.
+type Locker struct {
+	// Store persists the locks.
+	Store Store
+
+	// Identity identifies the instance, typically the name of its Pod. It
+	// is required.
+	Identity string
+
+	// LeaseDuration is how long a lock is held after it was acquired or
+	// renewed. Defaults to DefaultLeaseDuration.
+	LeaseDuration time.Duration
+
+	// Steal decides whether the lock of another instance may be taken over.
+	// Defaults to StealExpired.
+	Steal StealPolicy
+}
+
+// Acquire acquires or renews the lock of obj, and returns false if it is
+// held by another instance. Renewals are only written once half of the
+// lease duration elapsed. Losing a race against another instance is not an
+// error.
+func (l *Locker) Acquire(ctx context.Context, obj client.Object) (bool, error) {
+	if l.Identity == "" {
+		return false, errors.New("must specify the Identity of the Locker")
+	}
+	record, err := l.Store.Load(ctx, obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to load the lock of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+
+	now := metav1.Now()
+	leaseDuration := l.leaseDuration()
+	switch {
+	case record == nil:
+		record = &Record{AcquireTime: now}
+	case record.Holder == "":
+		record.AcquireTime = now
+	case record.Holder == l.Identity:
+		if now.Sub(record.RenewTime.Time) < leaseDuration/2 && record.LeaseDuration.Duration == leaseDuration {
+			return true, nil
+		}
+	case l.steal(*record, now.Time):
+		record.AcquireTime = now
+		record.Transitions++
+	default:
+		return false, nil
+	}
+	record.Holder = l.Identity
+	record.RenewTime = now
+	record.LeaseDuration = metav1.Duration{Duration: leaseDuration}
+
+	if err := l.Store.Save(ctx, obj, record); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to save the lock of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	return true, nil
+}
+
+// Release releases the lock of obj if the instance holds it.
+func (l *Locker) Release(ctx context.Context, obj client.Object) error {
+	record, err := l.Store.Load(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("failed to load the lock of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	if record == nil || record.Holder != l.Identity {
+		return nil
+	}
+	record.Holder = ""
+	if err := l.Store.Save(ctx, obj, record); err != nil && !apierrors.IsConflict(err) {
+		return fmt.Errorf("failed to release the lock of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+func (l *Locker) leaseDuration() time.Duration {
+	if l.LeaseDuration <= 0 {
+		return DefaultLeaseDuration
+	}
+	return l.LeaseDuration
+}
+
+func (l *Locker) steal(record Record, now time.Time) bool {
+	if l.Steal == nil {
+		return StealExpired(record, now)
+	}
+	return l.Steal(record, now)
+}
+
+// Reconciler wraps r so that it only reconciles the objects whose lock l
+// acquires. Requests for objects locked by another instance are requeued
+// after the lease duration, so that they are picked up if their holder goes
+// away. Requests for objects that no longer exist are passed to r
+// unconditionally. newObject returns an empty object of the type
+// reconciled.
+func Reconciler(c client.Reader, newObject func() client.Object, l *Locker, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		obj := newObject()
+		if err := c.Get(ctx, req.NamespacedName, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return r.Reconcile(ctx, req)
+			}
+			return reconcile.Result{}, err
+		}
+		acquired, err := l.Acquire(ctx, obj)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !acquired {
+			return reconcile.Result{RequeueAfter: l.leaseDuration()}, nil
+		}
+		return r.Reconcile(ctx, req)
+	})
+}
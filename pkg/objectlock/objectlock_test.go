@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectlock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/objectlock"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestObjectLock(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Object Lock Suite")
+}
+
+var _ = Describe("Locker", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		pod *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "1234"}}
+		c = fake.NewClientBuilder().WithObjects(pod).Build()
+	})
+
+	get := func() *corev1.Pod {
+		obj := &corev1.Pod{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(pod), obj)).To(Succeed())
+		return obj
+	}
+
+	expire := func(store objectlock.Store, holder string) {
+		obj := get()
+		record, err := store.Load(ctx, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.Holder).To(Equal(holder))
+		record.RenewTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		Expect(store.Save(ctx, obj, record)).To(Succeed())
+	}
+
+	for _, tc := range []struct {
+		name     string
+		newStore func() objectlock.Store
+	}{
+		{"AnnotationStore", func() objectlock.Store { return &objectlock.AnnotationStore{Client: c} }},
+		{"LeaseStore", func() objectlock.Store { return &objectlock.LeaseStore{Client: c} }},
+	} {
+		Context("with a "+tc.name, func() {
+			var a, b *objectlock.Locker
+
+			BeforeEach(func() {
+				store := tc.newStore()
+				a = &objectlock.Locker{Store: store, Identity: "a"}
+				b = &objectlock.Locker{Store: store, Identity: "b"}
+			})
+
+			It("should only let one instance hold the lock", func() {
+				Expect(a.Acquire(ctx, get())).To(BeTrue())
+				Expect(b.Acquire(ctx, get())).To(BeFalse())
+				Expect(a.Acquire(ctx, get())).To(BeTrue())
+			})
+
+			It("should steal expired locks", func() {
+				Expect(a.Acquire(ctx, get())).To(BeTrue())
+				expire(a.Store, "a")
+
+				Expect(b.Acquire(ctx, get())).To(BeTrue())
+				Expect(a.Acquire(ctx, get())).To(BeFalse())
+				record, err := b.Store.Load(ctx, get())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(record.Holder).To(Equal("b"))
+				Expect(record.Transitions).To(BeEquivalentTo(1))
+			})
+
+			It("should not steal locks with NeverSteal", func() {
+				b.Steal = objectlock.NeverSteal
+				Expect(a.Acquire(ctx, get())).To(BeTrue())
+				expire(a.Store, "a")
+
+				Expect(b.Acquire(ctx, get())).To(BeFalse())
+				Expect(a.Acquire(ctx, get())).To(BeTrue())
+			})
+
+			It("should release the lock", func() {
+				Expect(a.Acquire(ctx, get())).To(BeTrue())
+				Expect(b.Release(ctx, get())).To(Succeed())
+				Expect(b.Acquire(ctx, get())).To(BeFalse())
+
+				Expect(a.Release(ctx, get())).To(Succeed())
+				Expect(b.Acquire(ctx, get())).To(BeTrue())
+			})
+		})
+	}
+
+	It("should lose the race when the object changed since it was read", func() {
+		locker := &objectlock.Locker{Store: &objectlock.AnnotationStore{Client: c}, Identity: "a"}
+		stale := get()
+		Expect(locker.Acquire(ctx, get())).To(BeTrue())
+		Expect((&objectlock.Locker{Store: locker.Store, Identity: "b"}).Acquire(ctx, stale)).To(BeFalse())
+	})
+
+	It("should record the lock in a Lease owned by the object", func() {
+		locker := &objectlock.Locker{Store: &objectlock.LeaseStore{Client: c}, Identity: "a", LeaseDuration: 30 * time.Second}
+		Expect(locker.Acquire(ctx, get())).To(BeTrue())
+
+		lease := &coordinationv1.Lease{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "lock-1234"}, lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("a"))
+		Expect(*lease.Spec.LeaseDurationSeconds).To(BeEquivalentTo(30))
+		Expect(lease.OwnerReferences).To(HaveLen(1))
+		Expect(lease.OwnerReferences[0].UID).To(Equal(types.UID("1234")))
+
+		Expect(locker.Release(ctx, get())).To(Succeed())
+		err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "lock-1234"}, lease)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should only reconcile the objects whose lock it acquires", func() {
+		store := &objectlock.AnnotationStore{Client: c}
+		Expect((&objectlock.Locker{Store: store, Identity: "a"}).Acquire(ctx, get())).To(BeTrue())
+
+		var reconciled int
+		r := objectlock.Reconciler(c, func() client.Object { return &corev1.Pod{} },
+			&objectlock.Locker{Store: store, Identity: "b", LeaseDuration: 10 * time.Second},
+			reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				reconciled++
+				return reconcile.Result{}, nil
+			}))
+
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(10 * time.Second))
+		Expect(reconciled).To(BeZero())
+
+		expire(store, "a")
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciled).To(Equal(1))
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "gone"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciled).To(Equal(2))
+	})
+})
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectlock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/internal/ownedstore"
+)
+
+// AnnotationKey is the annotation used by AnnotationStore to record the
+// lock of an object.
+const AnnotationKey = "objectlock.controller-runtime.sigs.k8s.io/lock"
+
+// Store persists the locks of objects. Save must fail with a conflict or
+// already exists error if the lock changed since it was loaded.
+type Store interface {
+	// Load returns the lock of obj, or nil if it is not locked. The
+	// Holder of the lock may also be empty if it is not locked.
+	Load(ctx context.Context, obj client.Object) (*Record, error)
+
+	// Save replaces the lock of obj with record, loaded by Load or new. A
+	// record without Holder releases the lock.
+	Save(ctx context.Context, obj client.Object, record *Record) error
+}
+
+// AnnotationStore is a Store that records the lock as an annotation on the
+// object itself. Renewing a lock updates the object, which triggers the
+// watches of the object.
+type AnnotationStore struct {
+	// Client is used to patch the objects.
+	Client client.Client
+}
+
+var _ Store = &AnnotationStore{}
+
+// Load implements Store.
+func (s *AnnotationStore) Load(_ context.Context, obj client.Object) (*Record, error) {
+	data, ok := obj.GetAnnotations()[AnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	record := &Record{}
+	if err := json.Unmarshal([]byte(data), record); err != nil {
+		return nil, fmt.Errorf("failed to decode lock: %w", err)
+	}
+	return record, nil
+}
+
+// Save implements Store. The object is patched in place, and the patch
+// fails with a conflict if the object changed since it was read.
+func (s *AnnotationStore) Save(ctx context.Context, obj client.Object, record *Record) error {
+	var value *string
+	if record.Holder != "" {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode lock: %w", err)
+		}
+		value = ptr.To(string(data))
+	}
+	return ownedstore.SetAnnotation(ctx, s.Client, obj, AnnotationKey, value, client.MergeFromWithOptimisticLock{})
+}
+
+// LeaseStore is a Store that records the lock of each object in a Lease
+// named after the UID of the object, so that renewing a lock doesn't update
+// the object nor trigger its watches. The Lease has an owner reference to
+// the object, unless they are in different namespaces, so that it is
+// garbage collected with it. Conflicts are detected with the
+// resourceVersion of the Lease.
+type LeaseStore struct {
+	// Client is used to read and write the Leases.
+	Client client.Client
+
+	// Namespace is the namespace of the Leases. Defaults to the namespace of
+	// the object, and is required for cluster-scoped objects.
+	Namespace string
+
+	// NamePrefix is prepended to the UID of the object to name its Lease.
+	// Defaults to "lock-".
+	NamePrefix string
+}
+
+var _ Store = &LeaseStore{}
+
+// Load implements Store.
+func (s *LeaseStore) Load(ctx context.Context, obj client.Object) (*Record, error) {
+	lease := &coordinationv1.Lease{}
+	if err := s.Client.Get(ctx, s.keyFor(obj), lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	record := &Record{
+		Holder:          ptr.Deref(lease.Spec.HolderIdentity, ""),
+		Transitions:     ptr.Deref(lease.Spec.LeaseTransitions, 0),
+		resourceVersion: lease.ResourceVersion,
+	}
+	if lease.Spec.AcquireTime != nil {
+		record.AcquireTime = metav1.NewTime(lease.Spec.AcquireTime.Time)
+	}
+	if lease.Spec.RenewTime != nil {
+		record.RenewTime = metav1.NewTime(lease.Spec.RenewTime.Time)
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		record.LeaseDuration = metav1.Duration{Duration: time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second}
+	}
+	return record, nil
+}
+
+// Save implements Store. Released locks are deleted.
+func (s *LeaseStore) Save(ctx context.Context, obj client.Object, record *Record) error {
+	key := s.keyFor(obj)
+	if key.Namespace == "" {
+		return fmt.Errorf("must specify Namespace for LeaseStore to save the lock of cluster-scoped %s", obj.GetName())
+	}
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+	if record.Holder == "" {
+		return client.IgnoreNotFound(s.Client.Delete(ctx, lease, client.Preconditions{ResourceVersion: ptr.To(record.resourceVersion)}))
+	}
+
+	lease.Spec = coordinationv1.LeaseSpec{
+		HolderIdentity:       ptr.To(record.Holder),
+		LeaseDurationSeconds: ptr.To(int32(record.LeaseDuration.Seconds())),
+		AcquireTime:          &metav1.MicroTime{Time: record.AcquireTime.Time},
+		RenewTime:            &metav1.MicroTime{Time: record.RenewTime.Time},
+		LeaseTransitions:     ptr.To(record.Transitions),
+	}
+	// Owner references can't cross namespaces.
+	if obj.GetNamespace() == "" || obj.GetNamespace() == lease.Namespace {
+		if err := controllerutil.SetOwnerReference(obj, lease, s.Client.Scheme()); err != nil {
+			return err
+		}
+	}
+	if record.resourceVersion == "" {
+		return s.Client.Create(ctx, lease)
+	}
+	lease.ResourceVersion = record.resourceVersion
+	return s.Client.Update(ctx, lease)
+}
+
+func (s *LeaseStore) keyFor(obj client.Object) client.ObjectKey {
+	namespace := s.Namespace
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	prefix := s.NamePrefix
+	if prefix == "" {
+		prefix = "lock-"
+	}
+	return client.ObjectKey{Namespace: namespace, Name: prefix + string(obj.GetUID())}
+}
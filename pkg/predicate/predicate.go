@@ -19,9 +19,12 @@ package predicate
 import (
 	"maps"
 	"reflect"
+	"strconv"
+	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
@@ -167,6 +170,77 @@ func (TypedResourceVersionChangedPredicate[T]) Update(e event.TypedUpdateEvent[T
 	return e.ObjectNew.GetResourceVersion() != e.ObjectOld.GetResourceVersion()
 }
 
+// NewResourceVersionMonotonicPredicate returns a predicate that drops the events of
+// objects whose resource version is not newer than the last one seen for the same
+// object, e.g. duplicate or out-of-order events delivered after a watch restarts.
+//
+// Resource versions are meant to be opaque. This predicate relies on the API server
+// using integers that grow with each write, as it does when backed by etcd: the
+// events of objects whose resource version is not an integer are never dropped.
+//
+// Note that the update events of the periodic resyncs have the same resource
+// version as the previous event of the object, and are dropped as well.
+func NewResourceVersionMonotonicPredicate() Predicate {
+	return NewTypedResourceVersionMonotonicPredicate[client.Object]()
+}
+
+// NewTypedResourceVersionMonotonicPredicate returns a predicate that drops the events
+// of objects whose resource version is not newer than the last one seen for the same
+// object. See NewResourceVersionMonotonicPredicate.
+func NewTypedResourceVersionMonotonicPredicate[object metav1.Object]() TypedPredicate[object] {
+	return &resourceVersionMonotonic[object]{seen: map[types.UID]uint64{}}
+}
+
+type resourceVersionMonotonic[object metav1.Object] struct {
+	mu sync.Mutex
+	// seen is the last resource version seen per object.
+	seen map[types.UID]uint64
+}
+
+// observe records the resource version of obj and returns whether it is newer than
+// the last one seen.
+func (p *resourceVersionMonotonic[object]) observe(obj object) bool {
+	if isNil(obj) {
+		return true
+	}
+	rv, err := strconv.ParseUint(obj.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if last, ok := p.seen[obj.GetUID()]; ok && rv <= last {
+		return false
+	}
+	p.seen[obj.GetUID()] = rv
+	return true
+}
+
+// Create implements Predicate.
+func (p *resourceVersionMonotonic[object]) Create(e event.TypedCreateEvent[object]) bool {
+	return p.observe(e.Object)
+}
+
+// Update implements Predicate.
+func (p *resourceVersionMonotonic[object]) Update(e event.TypedUpdateEvent[object]) bool {
+	return p.observe(e.ObjectNew)
+}
+
+// Delete implements Predicate. It forgets the object.
+func (p *resourceVersionMonotonic[object]) Delete(e event.TypedDeleteEvent[object]) bool {
+	if !isNil(e.Object) {
+		p.mu.Lock()
+		delete(p.seen, e.Object.GetUID())
+		p.mu.Unlock()
+	}
+	return true
+}
+
+// Generic implements Predicate.
+func (p *resourceVersionMonotonic[object]) Generic(event.TypedGenericEvent[object]) bool {
+	return true
+}
+
 // GenerationChangedPredicate implements a default update predicate function on Generation change.
 //
 // This predicate will skip update events that have no change in the object's metadata.generation field.
@@ -286,6 +360,25 @@ func (TypedLabelChangedPredicate[object]) Update(e event.TypedUpdateEvent[object
 	return !maps.Equal(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels())
 }
 
+// SkipInitialListPredicate implements a default create predicate function that skips
+// the Create events triggered by the initial list performed when an informer starts.
+//
+// This is useful for controllers that only care about changes made after they started.
+// Note that objects that were created while the controller was not running will not be
+// reconciled until they are updated.
+type SkipInitialListPredicate = TypedSkipInitialListPredicate[client.Object]
+
+// TypedSkipInitialListPredicate implements a default create predicate function that skips
+// the Create events triggered by the initial list performed when an informer starts.
+type TypedSkipInitialListPredicate[object any] struct {
+	TypedFuncs[object]
+}
+
+// Create implements default CreateEvent filter for skipping initial list events.
+func (TypedSkipInitialListPredicate[object]) Create(e event.TypedCreateEvent[object]) bool {
+	return !e.IsInInitialList
+}
+
 // And returns a composite predicate that implements a logical AND of the predicates passed to it.
 func And[object any](predicates ...TypedPredicate[object]) TypedPredicate[object] {
 	return and[object]{predicates}
@@ -806,6 +806,99 @@ var _ = Describe("Predicate", func() {
 		})
 	})
 
+	Describe("When checking a SkipInitialListPredicate", func() {
+		instance := predicate.SkipInitialListPredicate{}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "biz"}}
+
+		It("should return false for initial list events", func() {
+			Expect(instance.Create(event.CreateEvent{Object: pod, IsInInitialList: true})).To(BeFalse())
+		})
+
+		It("should return true for other events", func() {
+			Expect(instance.Create(event.CreateEvent{Object: pod})).To(BeTrue())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: pod})).To(BeTrue())
+			Expect(instance.Delete(event.DeleteEvent{Object: pod})).To(BeTrue())
+			Expect(instance.Generic(event.GenericEvent{Object: pod})).To(BeTrue())
+		})
+	})
+
+	Describe("When checking a NotPaused predicate", func() {
+		It("should drop the events of paused objects", func() {
+			var paused []client.Object
+			instance := predicate.NotPaused(predicate.PauseOptions[client.Object]{
+				Controller: "pause-test",
+				OnPaused:   func(obj client.Object) { paused = append(paused, obj) },
+			})
+			pod.Annotations = map[string]string{predicate.DefaultPauseAnnotation: "true"}
+
+			Expect(instance.Create(event.CreateEvent{Object: pod})).To(BeFalse())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: pod})).To(BeFalse())
+			Expect(instance.Delete(event.DeleteEvent{Object: pod})).To(BeFalse())
+			Expect(instance.Generic(event.GenericEvent{Object: pod})).To(BeFalse())
+			Expect(paused).To(HaveLen(4))
+		})
+
+		It("should pass the events of objects not paused", func() {
+			instance := predicate.NotPaused(predicate.PauseOptions[client.Object]{Annotation: "example.com/paused"})
+			Expect(instance.Create(event.CreateEvent{Object: pod})).To(BeTrue())
+
+			pod.Annotations = map[string]string{"example.com/paused": "false"}
+			Expect(instance.Create(event.CreateEvent{Object: pod})).To(BeTrue())
+
+			pod.Annotations = map[string]string{predicate.DefaultPauseAnnotation: "true"}
+			Expect(instance.Create(event.CreateEvent{Object: pod})).To(BeTrue())
+		})
+
+		It("should resume with the update removing the annotation", func() {
+			instance := predicate.NotPaused(predicate.PauseOptions[client.Object]{})
+			old := pod.DeepCopy()
+			old.Annotations = map[string]string{predicate.DefaultPauseAnnotation: ""}
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: pod})).To(BeTrue())
+		})
+	})
+
+	Describe("When checking a ResourceVersionMonotonicPredicate", func() {
+		var instance predicate.Predicate
+		withRV := func(rv string) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "biz", UID: "uid", ResourceVersion: rv}}
+		}
+
+		BeforeEach(func() {
+			instance = predicate.NewResourceVersionMonotonicPredicate()
+		})
+
+		It("should return true for newer resource versions", func() {
+			Expect(instance.Create(event.CreateEvent{Object: withRV("10")})).To(BeTrue())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: withRV("10"), ObjectNew: withRV("11")})).To(BeTrue())
+		})
+
+		It("should return false for duplicate or older resource versions", func() {
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: withRV("10"), ObjectNew: withRV("12")})).To(BeTrue())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: withRV("10"), ObjectNew: withRV("12")})).To(BeFalse())
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: withRV("10"), ObjectNew: withRV("11")})).To(BeFalse())
+			Expect(instance.Create(event.CreateEvent{Object: withRV("12")})).To(BeFalse())
+		})
+
+		It("should track objects separately", func() {
+			other := withRV("5")
+			other.UID = "other"
+			Expect(instance.Create(event.CreateEvent{Object: withRV("10")})).To(BeTrue())
+			Expect(instance.Create(event.CreateEvent{Object: other})).To(BeTrue())
+		})
+
+		It("should forget deleted objects", func() {
+			Expect(instance.Create(event.CreateEvent{Object: withRV("10")})).To(BeTrue())
+			Expect(instance.Delete(event.DeleteEvent{Object: withRV("10")})).To(BeTrue())
+			Expect(instance.Create(event.CreateEvent{Object: withRV("10")})).To(BeTrue())
+		})
+
+		It("should return true for resource versions that are not integers", func() {
+			Expect(instance.Create(event.CreateEvent{Object: withRV("abc")})).To(BeTrue())
+			Expect(instance.Create(event.CreateEvent{Object: withRV("abc")})).To(BeTrue())
+			Expect(instance.Generic(event.GenericEvent{Object: withRV("abc")})).To(BeTrue())
+		})
+	})
+
 	Context("With a boolean predicate", func() {
 		funcs := func(pass bool) predicate.Funcs {
 			return predicate.Funcs{
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DedupStrategy decides what identifies a version of an object that has
+// been reconciled.
+type DedupStrategy string
+
+const (
+	// DedupByGeneration skips reconciles of objects whose metadata.generation
+	// has already been reconciled. Changes that don't increment the
+	// generation, such as writes to the status or, for most types, to the
+	// metadata, don't trigger a reconcile. Objects of types that don't
+	// maintain a generation, i.e. whose generation is always 0, are
+	// deduplicated by resourceVersion instead.
+	DedupByGeneration DedupStrategy = "Generation"
+
+	// DedupByResourceVersion skips reconciles of objects whose
+	// metadata.resourceVersion has already been reconciled. This only
+	// deduplicates reconciles triggered without any change to the object,
+	// e.g. by periodic resyncs.
+	DedupByResourceVersion DedupStrategy = "ResourceVersion"
+)
+
+// SkipUnchanged wraps an ObjectReconciler and skips reconciling objects
+// that have not changed since they were last reconciled successfully, as
+// decided by strategy. A reconcile is successful if it returns no error and
+// a zero Result; objects that requested a requeue are always reconciled again.
+//
+// Only the reconciles triggered by periodic resyncs or by events of the
+// reconciled object itself are skipped. Reconciles triggered by events of
+// other objects, e.g. owned or watched objects, may need to act on changes
+// that the reconciled object doesn't reflect, so they are never skipped.
+// The triggers are taken from TriggersFromContext, so the controller must
+// record them, see the RecordTriggers controller option; otherwise no
+// reconcile is skipped. Objects that are being deleted are always
+// reconciled.
+//
+// The last reconciled versions are only kept in memory, so every object is
+// reconciled at least once after the process starts. Entries of deleted
+// objects are evicted when the reconciler wrapped by AsReconciler observes
+// that the object doesn't exist anymore or is being deleted.
+func SkipUnchanged[object client.Object](rec ObjectReconciler[object], strategy DedupStrategy) ObjectReconciler[object] {
+	return &dedupReconciler[object]{
+		objReconciler: rec,
+		strategy:      strategy,
+		reconciled:    map[types.NamespacedName]reconciledVersion{},
+	}
+}
+
+type reconciledVersion struct {
+	uid     types.UID
+	version string
+}
+
+type dedupReconciler[object client.Object] struct {
+	objReconciler ObjectReconciler[object]
+	strategy      DedupStrategy
+
+	mu         sync.Mutex
+	reconciled map[types.NamespacedName]reconciledVersion
+}
+
+// Reconcile implements ObjectReconciler.
+func (d *dedupReconciler[object]) Reconcile(ctx context.Context, o object) (Result, error) {
+	key := client.ObjectKeyFromObject(o)
+	current := reconciledVersion{uid: o.GetUID(), version: d.versionOf(o)}
+
+	if o.GetDeletionTimestamp() != nil {
+		d.forget(key)
+		return d.objReconciler.Reconcile(ctx, o)
+	}
+
+	d.mu.Lock()
+	last, ok := d.reconciled[key]
+	d.mu.Unlock()
+	if ok && last == current && triggeredBySelf(ctx, o) {
+		return Result{}, nil
+	}
+
+	result, err := d.objReconciler.Reconcile(ctx, o)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil || !result.IsZero() {
+		delete(d.reconciled, key)
+		return result, err
+	}
+	d.reconciled[key] = current
+	return result, nil
+}
+
+// triggeredBySelf returns whether the reconcile of o was only triggered by
+// resyncs or by events of o itself.
+func triggeredBySelf(ctx context.Context, o client.Object) bool {
+	triggers := TriggersFromContext(ctx)
+	for _, trigger := range triggers {
+		if trigger.Resync {
+			continue
+		}
+		if trigger.Object == nil || trigger.Object.GetUID() != o.GetUID() {
+			return false
+		}
+	}
+	return len(triggers) > 0
+}
+
+// forget implements objectForgetter.
+func (d *dedupReconciler[object]) forget(key types.NamespacedName) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.reconciled, key)
+}
+
+func (d *dedupReconciler[object]) versionOf(o object) string {
+	if d.strategy == DedupByResourceVersion || o.GetGeneration() == 0 {
+		return "rv:" + o.GetResourceVersion()
+	}
+	return strconv.FormatInt(o.GetGeneration(), 10)
+}
+
+// objectForgetter is implemented by ObjectReconcilers that keep state about
+// objects, to be notified by AsReconciler of objects that don't exist
+// anymore.
+type objectForgetter interface {
+	forget(key types.NamespacedName)
+}
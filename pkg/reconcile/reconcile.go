@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -52,6 +53,53 @@ type Request struct {
 	types.NamespacedName
 }
 
+// ClusterRequest is a request to reconcile an object of one of several
+// clusters, identified by their name. It lets a single controller reconcile
+// the objects of many clusters: handlers set the cluster the event came from,
+// e.g. with handler.ForCluster, and the reconciler routes its client access
+// to that cluster, e.g. with cluster.ForClusters.
+type ClusterRequest struct {
+	// ClusterName is the name of the cluster of the object. It is empty for
+	// the cluster of the manager.
+	ClusterName string
+
+	// Request identifies the object within its cluster.
+	Request
+}
+
+// String returns the request as cluster/namespace/name, or namespace/name if
+// it has no cluster name.
+func (r ClusterRequest) String() string {
+	if r.ClusterName == "" {
+		return r.Request.String()
+	}
+	return r.ClusterName + "/" + r.Request.String()
+}
+
+// ExternalRequest is a request to reconcile a resource outside the cluster,
+// such as a bucket of a cloud provider, that is identified by an ID which
+// doesn't correspond to any object. Controllers reconciling ExternalRequests
+// are built with builder.TypedControllerManagedBy, fed by sources such as
+// source.Requests and source.Poll, and handlers such as
+// handler.TypedEnqueueRequestsFromMapFunc mapping objects to the external
+// resources they depend on.
+type ExternalRequest struct {
+	// Kind is the kind of the resource, which lets a controller reconcile
+	// several kinds of external resources. It may be empty.
+	Kind string
+
+	// ID identifies the resource within its kind.
+	ID string
+}
+
+// String returns the request as kind/id, or id if it has no kind.
+func (r ExternalRequest) String() string {
+	if r.Kind == "" {
+		return r.ID
+	}
+	return r.Kind + "/" + r.ID
+}
+
 /*
 Reconciler implements a Kubernetes API for a specific Resource by Creating, Updating or Deleting Kubernetes
 objects, or by making changes to systems external to the cluster (e.g. cloudproviders, github, etc).
@@ -148,6 +196,9 @@ type objectReconcilerAdapter[object client.Object] struct {
 func (a *objectReconcilerAdapter[object]) Reconcile(ctx context.Context, req Request) (Result, error) {
 	o := reflect.New(reflect.TypeOf(*new(object)).Elem()).Interface().(object)
 	if err := a.client.Get(ctx, req.NamespacedName, o); err != nil {
+		if forgetter, ok := a.objReconciler.(objectForgetter); ok && apierrors.IsNotFound(err) {
+			forgetter.forget(req.NamespacedName)
+		}
 		return Result{}, client.IgnoreNotFound(err)
 	}
 
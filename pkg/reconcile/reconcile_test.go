@@ -27,7 +27,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -185,4 +187,176 @@ var _ = Describe("reconcile", func() {
 			})
 		})
 	})
+
+	Describe("SkipUnchanged", func() {
+		var calls int
+		var rec *mockObjectReconciler
+		var result reconcile.Result
+		var ctx context.Context
+
+		BeforeEach(func() {
+			calls = 0
+			ctx = reconcile.WithTriggers(context.Background(), []reconcile.Trigger{{
+				EventType: reconcile.EventUpdate,
+				Object:    &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "uid"}},
+			}})
+			result = reconcile.Result{}
+			rec = &mockObjectReconciler{
+				reconcileFunc: func(context.Context, *corev1.ConfigMap) (reconcile.Result, error) {
+					calls++
+					return result, nil
+				},
+			}
+		})
+
+		newConfigMap := func(generation int64, resourceVersion string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            "test",
+				UID:             "uid",
+				Generation:      generation,
+				ResourceVersion: resourceVersion,
+			}}
+		}
+
+		It("should skip objects whose generation was already reconciled", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByGeneration)
+
+			_, err := reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, newConfigMap(1, "2"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+
+			_, err = reconciler.Reconcile(ctx, newConfigMap(2, "3"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should skip objects whose resourceVersion was already reconciled", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByResourceVersion)
+
+			_, err := reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+
+			_, err = reconciler.Reconcile(ctx, newConfigMap(1, "2"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should not skip reconciles triggered by other objects", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByResourceVersion)
+
+			_, err := reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			owned := reconcile.WithTriggers(context.Background(), []reconcile.Trigger{{
+				EventType: reconcile.EventUpdate,
+				Object:    &corev1.Secret{ObjectMeta: metav1.ObjectMeta{UID: "owned"}},
+			}})
+			_, err = reconciler.Reconcile(owned, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should skip reconciles triggered by resyncs", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByResourceVersion)
+
+			_, err := reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			resync := reconcile.WithTriggers(context.Background(), []reconcile.Trigger{{
+				EventType: reconcile.EventUpdate,
+				Object:    &corev1.Secret{ObjectMeta: metav1.ObjectMeta{UID: "owned"}},
+				Resync:    true,
+			}})
+			_, err = reconciler.Reconcile(resync, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should not skip reconciles without triggers", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByResourceVersion)
+
+			_, err := reconciler.Reconcile(context.Background(), newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(context.Background(), newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should not skip objects that requested a requeue", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByGeneration)
+			result = reconcile.Result{RequeueAfter: time.Minute}
+
+			_, err := reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, newConfigMap(1, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should deduplicate objects without a generation by resourceVersion", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByGeneration)
+
+			_, err := reconciler.Reconcile(ctx, newConfigMap(0, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, newConfigMap(0, "1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+
+			_, err = reconciler.Reconcile(ctx, newConfigMap(0, "2"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should always reconcile objects that are being deleted", func() {
+			reconciler := reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByGeneration)
+			deleting := newConfigMap(1, "1")
+			deleting.DeletionTimestamp = ptr.To(metav1.Now())
+
+			_, err := reconciler.Reconcile(ctx, deleting)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, deleting)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should forget objects that don't exist anymore", func() {
+			cm := newConfigMap(1, "")
+			cl := fake.NewClientBuilder().WithObjects(cm).Build()
+			reconciler := reconcile.AsReconciler(cl, reconcile.SkipUnchanged[*corev1.ConfigMap](rec, reconcile.DedupByGeneration))
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cm)}
+
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+
+			Expect(cl.Delete(context.Background(), cm)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			// A recreated object with the same name, UID and generation is
+			// reconciled again, because the entry was evicted on NotFound.
+			cm = newConfigMap(1, "")
+			Expect(cl.Create(context.Background(), cm)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+	})
+
+	Describe("RequeueUnlessChanged", func() {
+		It("should register the timer with the controller", func() {
+			var registered time.Duration
+			ctx := reconcile.WithRequeueTimer(context.Background(), func(after time.Duration) { registered = after })
+			Expect(reconcile.RequeueUnlessChanged(ctx, time.Hour)).To(BeTrue())
+			Expect(registered).To(Equal(time.Hour))
+		})
+
+		It("should return false if the controller doesn't support it", func() {
+			Expect(reconcile.RequeueUnlessChanged(context.Background(), time.Hour)).To(BeFalse())
+		})
+	})
 })
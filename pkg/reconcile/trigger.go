@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EventType is the type of an event that triggered a reconcile.
+type EventType string
+
+const (
+	// EventCreate is the type of Create events.
+	EventCreate EventType = "Create"
+	// EventUpdate is the type of Update events.
+	EventUpdate EventType = "Update"
+	// EventDelete is the type of Delete events.
+	EventDelete EventType = "Delete"
+	// EventGeneric is the type of Generic events.
+	EventGeneric EventType = "Generic"
+)
+
+// Trigger describes an event that caused a request to be enqueued.
+type Trigger struct {
+	// EventType is the type of the event.
+	EventType EventType
+
+	// Object is the object of the event. For Update events, it is the new
+	// object. It may be nil for sources that don't provide objects.
+	Object client.Object
+
+	// Resync is true for Update events emitted by a periodic resync of an
+	// informer, for which the object didn't change.
+	Resync bool
+}
+
+type triggersKey struct{}
+
+// WithTriggers returns a copy of ctx that carries the given triggers.
+func WithTriggers(ctx context.Context, triggers []Trigger) context.Context {
+	return context.WithValue(ctx, triggersKey{}, triggers)
+}
+
+// TriggersFromContext returns the events that caused the request being
+// reconciled to be enqueued, oldest first. Events enqueuing a request that
+// is already queued are coalesced, so a single reconcile may have multiple
+// triggers. It returns no triggers for requeues, e.g. after an error or a
+// RequeueAfter result, and if the controller doesn't record triggers.
+// See the RecordTriggers controller option.
+func TriggersFromContext(ctx context.Context) []Trigger {
+	triggers, _ := ctx.Value(triggersKey{}).([]Trigger)
+	return triggers
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"time"
+)
+
+type requeueTimerKey struct{}
+
+// WithRequeueTimer returns a copy of ctx in which RequeueUnlessChanged calls
+// register. It is used by controllers to hand their timers to Reconcilers.
+func WithRequeueTimer(ctx context.Context, register func(after time.Duration)) context.Context {
+	return context.WithValue(ctx, requeueTimerKey{}, register)
+}
+
+// RequeueUnlessChanged asks the controller to reconcile the request being
+// reconciled again after the given duration, unless it is reconciled before,
+// e.g. because its object changed, in which case the timer is cancelled and
+// that reconcile may ask again. Calling it again during the same reconcile
+// replaces the previous duration.
+//
+// Unlike Result.RequeueAfter, which keeps a timer per request in the
+// workqueue, the timers are grouped in buckets of the controller's
+// RequeueTimerResolution, which is cheaper for large numbers of long delays,
+// e.g. expiring certificates or leases, at the cost of firing up to one
+// resolution late.
+//
+// It returns false, and does nothing, if the controller doesn't support it,
+// i.e. if its RequeueTimerResolution isn't set.
+func RequeueUnlessChanged(ctx context.Context, after time.Duration) bool {
+	register, ok := ctx.Value(requeueTimerKey{}).(func(time.Duration))
+	if !ok {
+		return false
+	}
+	register(after)
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// Event is an event recorded by an event recorder, as passed to a Sink.
+type Event struct {
+	// Object is the object the event was recorded for. It must not be
+	// modified.
+	Object runtime.Object
+
+	// Regarding is the reference of Object, if it could be built.
+	Regarding corev1.ObjectReference
+
+	// Component is the name of the recorder that recorded the event.
+	Component string
+
+	// Type is the type of the event, Normal or Warning.
+	Type string
+
+	// Reason is the reason of the event.
+	Reason string
+
+	// Message is the formatted message of the event.
+	Message string
+
+	// Annotations are the annotations of the event, if recorded with
+	// AnnotatedEventf.
+	Annotations map[string]string
+
+	// Timestamp is the time the event was recorded.
+	Timestamp time.Time
+}
+
+// Sink receives a copy of the events recorded by the event recorders, in
+// addition to their emission to the API server, e.g. to mirror them to
+// structured logs or to an exporter in clusters where Event objects are
+// garbage collected aggressively. Sinks are called synchronously when an
+// event is recorded, so they must not block.
+type Sink interface {
+	Record(event Event)
+}
+
+// SinkFunc is a function implementing Sink.
+type SinkFunc func(event Event)
+
+// Record implements Sink.
+func (f SinkFunc) Record(event Event) {
+	f(event)
+}
+
+// NewLogSink returns a Sink logging every event with logger, at the info
+// level.
+func NewLogSink(logger logr.Logger) Sink {
+	return SinkFunc(func(e Event) {
+		keysAndValues := []interface{}{
+			"type", e.Type,
+			"reason", e.Reason,
+			"component", e.Component,
+			"objectKind", e.Regarding.Kind,
+			"object", klog.KRef(e.Regarding.Namespace, e.Regarding.Name),
+		}
+		for k, v := range e.Annotations {
+			keysAndValues = append(keysAndValues, "annotation."+k, v)
+		}
+		logger.Info(e.Message, keysAndValues...)
+	})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package replay records the events delivered to a controller and replays
+them against a reconciler, to reproduce issues seen in production
+deterministically in a test.
+
+Events are recorded as gzip-compressed, newline-delimited JSON by a
+predicate that is added to the controller:
+
+	f, err := os.Create("events.ndjson.gz")
+	...
+	recorder := replay.NewRecorder(f, mgr.GetScheme())
+	defer recorder.Close()
+
+	err = builder.ControllerManagedBy(mgr).
+		For(&appsv1.Deployment{}).
+		WithEventFilter(recorder.Predicate()).
+		Complete(r)
+
+A test replays the recording with a Replayer, which applies every event to
+a client, typically a fake one, turns it into requests using the event
+handler of the controller and reconciles them one at a time:
+
+	replayer := &replay.Replayer{Client: c, Reconciler: r}
+	err := replayer.Replay(ctx, f)
+*/
+package replay
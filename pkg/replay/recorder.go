@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Event is a recorded event.
+type Event struct {
+	// Time is the time at which the event was recorded.
+	Time time.Time `json:"time"`
+
+	// Type is the type of the event.
+	Type reconcile.EventType `json:"type"`
+
+	// Object is the object of the event. For Update events, it is the new
+	// object.
+	Object *unstructured.Unstructured `json:"object"`
+
+	// OldObject is the old object of Update events.
+	OldObject *unstructured.Unstructured `json:"oldObject,omitempty"`
+
+	// IsInInitialList is set for Create events triggered by the initial
+	// list of an informer.
+	IsInInitialList bool `json:"isInInitialList,omitempty"`
+
+	// DeleteStateUnknown is set for Delete events that were missed and
+	// whose object may be stale.
+	DeleteStateUnknown bool `json:"deleteStateUnknown,omitempty"`
+}
+
+// Recorder records events as gzip-compressed, newline-delimited JSON. It is
+// safe for concurrent use.
+type Recorder struct {
+	scheme *runtime.Scheme
+
+	mu      sync.Mutex
+	gz      *gzip.Writer
+	encoder *json.Encoder
+	err     error
+	closed  bool
+}
+
+// NewRecorder returns a Recorder writing to w. The scheme is used to
+// determine the kind of typed objects. The Recorder must be closed to
+// flush the recording.
+func NewRecorder(w io.Writer, scheme *runtime.Scheme) *Recorder {
+	gz := gzip.NewWriter(w)
+	return &Recorder{
+		scheme:  scheme,
+		gz:      gz,
+		encoder: json.NewEncoder(gz),
+	}
+}
+
+// Predicate returns a predicate that records every event and lets it
+// through. Adding it to a controller, e.g. with the builder's
+// WithEventFilter, records all the events delivered to it, except those of
+// raw sources.
+func (r *Recorder) Predicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			_ = r.record(Event{Type: reconcile.EventCreate, IsInInitialList: e.IsInInitialList}, e.Object, nil)
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			_ = r.record(Event{Type: reconcile.EventUpdate}, e.ObjectNew, e.ObjectOld)
+			return true
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			_ = r.record(Event{Type: reconcile.EventDelete, DeleteStateUnknown: e.DeleteStateUnknown}, e.Object, nil)
+			return true
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			_ = r.record(Event{Type: reconcile.EventGeneric}, e.Object, nil)
+			return true
+		},
+	}
+}
+
+// Record records an event of the given type. old is only used for Update
+// events.
+func (r *Recorder) Record(eventType reconcile.EventType, obj, old client.Object) error {
+	return r.record(Event{Type: eventType}, obj, old)
+}
+
+func (r *Recorder) record(e Event, obj, old client.Object) error {
+	e.Time = time.Now()
+	var err error
+	if e.Object, err = r.toUnstructured(obj); err != nil {
+		return r.fail(err)
+	}
+	if old != nil {
+		if e.OldObject, err = r.toUnstructured(old); err != nil {
+			return r.fail(err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errors.New("recorder is closed")
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.encoder.Encode(&e); err != nil {
+		r.err = fmt.Errorf("failed to record event: %w", err)
+	}
+	return r.err
+}
+
+// Flush writes the buffered events, so that a recording can be read while
+// the Recorder is still in use.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil || r.closed {
+		return r.err
+	}
+	return r.gz.Flush()
+}
+
+// Close flushes the recording. It returns the first error that occurred
+// while recording, if any. It does not close the underlying writer.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return r.err
+	}
+	r.closed = true
+	if err := r.gz.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+func (r *Recorder) fail(err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+	return err
+}
+
+func (r *Recorder) toUnstructured(obj client.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.DeepCopy(), nil
+	}
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record event for %T: %w", obj, err)
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record event for %T: %w", obj, err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	return u, nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReadEvents reads the events of a recording made by a Recorder.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	defer gz.Close()
+
+	var events []Event
+	decoder := json.NewDecoder(bufio.NewReader(gz))
+	for {
+		var e Event
+		if err := decoder.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return events, nil
+			}
+			// A recording that was flushed but not closed ends abruptly.
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return events, nil
+			}
+			return nil, fmt.Errorf("failed to read event %d of recording: %w", len(events), err)
+		}
+		if e.Object == nil {
+			return nil, fmt.Errorf("event %d of recording has no object", len(events))
+		}
+		events = append(events, e)
+	}
+}
+
+// Replayer replays recorded events against a reconciler, one event at a
+// time: each event is applied to Client, turned into requests by Handler,
+// and the requests are reconciled before the next event is replayed.
+// Results requesting a requeue are ignored, so that replays are
+// deterministic.
+type Replayer struct {
+	// Scheme is used to convert recorded objects to typed objects. Objects
+	// of kinds it doesn't recognize are replayed as unstructured objects.
+	// Defaults to the scheme of Client if set, and to the client-go scheme
+	// otherwise.
+	Scheme *runtime.Scheme
+
+	// Client, if set, has the objects of the events applied to it before
+	// they are reconciled, so that the reconciler observes the recorded
+	// state. It is typically a fake client. Objects are updated
+	// unconditionally, including their status if the client has no status
+	// subresource for their kind.
+	Client client.Client
+
+	// Handler maps events to requests. Defaults to EnqueueRequestForObject.
+	Handler handler.EventHandler
+
+	// Reconciler reconciles the requests. It is required. The context it is
+	// called with carries the replayed event as its trigger, see
+	// reconcile.TriggersFromContext.
+	Reconciler reconcile.Reconciler
+}
+
+// Replay replays the events of a recording made by a Recorder. Errors
+// returned by the reconciler don't stop the replay, and are returned
+// aggregated.
+func (p *Replayer) Replay(ctx context.Context, r io.Reader) error {
+	events, err := ReadEvents(r)
+	if err != nil {
+		return err
+	}
+	return p.ReplayEvents(ctx, events)
+}
+
+// ReplayEvents replays the given events, see Replay.
+func (p *Replayer) ReplayEvents(ctx context.Context, events []Event) error {
+	if p.Reconciler == nil {
+		return errors.New("must provide a Reconciler")
+	}
+	h := p.Handler
+	if h == nil {
+		h = &handler.EnqueueRequestForObject{}
+	}
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+
+	var errs []error
+	for i, e := range events {
+		obj, err := p.toObject(e.Object)
+		if err != nil {
+			return fmt.Errorf("failed to replay event %d: %w", i, err)
+		}
+		if err := p.apply(ctx, e.Type, obj); err != nil {
+			return fmt.Errorf("failed to replay event %d: %w", i, err)
+		}
+
+		switch e.Type {
+		case reconcile.EventCreate:
+			h.Create(ctx, event.CreateEvent{Object: obj, IsInInitialList: e.IsInInitialList}, queue)
+		case reconcile.EventUpdate:
+			old := obj
+			if e.OldObject != nil {
+				if old, err = p.toObject(e.OldObject); err != nil {
+					return fmt.Errorf("failed to replay event %d: %w", i, err)
+				}
+			}
+			h.Update(ctx, event.UpdateEvent{ObjectOld: old, ObjectNew: obj}, queue)
+		case reconcile.EventDelete:
+			h.Delete(ctx, event.DeleteEvent{Object: obj, DeleteStateUnknown: e.DeleteStateUnknown}, queue)
+		case reconcile.EventGeneric:
+			h.Generic(ctx, event.GenericEvent{Object: obj}, queue)
+		default:
+			return fmt.Errorf("failed to replay event %d: unknown event type %q", i, e.Type)
+		}
+
+		reconcileCtx := reconcile.WithTriggers(ctx, []reconcile.Trigger{{EventType: e.Type, Object: obj}})
+		for queue.Len() > 0 {
+			req, _ := queue.Get()
+			if _, err := p.Reconciler.Reconcile(reconcileCtx, req); err != nil {
+				errs = append(errs, fmt.Errorf("failed to reconcile %s after event %d: %w", req, i, err))
+			}
+			queue.Forget(req)
+			queue.Done(req)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// apply applies an event to the client.
+func (p *Replayer) apply(ctx context.Context, eventType reconcile.EventType, obj client.Object) error {
+	if p.Client == nil {
+		return nil
+	}
+	obj = obj.DeepCopyObject().(client.Object)
+	if eventType == reconcile.EventDelete {
+		return client.IgnoreNotFound(p.Client.Delete(ctx, obj))
+	}
+
+	existing := obj.DeepCopyObject().(client.Object)
+	if err := p.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		obj.SetResourceVersion("")
+		return p.Client.Create(ctx, obj)
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return p.Client.Update(ctx, obj)
+}
+
+func (p *Replayer) toObject(u *unstructured.Unstructured) (client.Object, error) {
+	s := p.Scheme
+	if s == nil && p.Client != nil {
+		s = p.Client.Scheme()
+	}
+	if s == nil {
+		s = scheme.Scheme
+	}
+
+	gvk := u.GroupVersionKind()
+	if !s.Recognizes(gvk) {
+		return u.DeepCopy(), nil
+	}
+	typed, err := s.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return nil, fmt.Errorf("failed to convert %s to %T: %w", gvk, typed, err)
+	}
+	obj, ok := typed.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a client.Object", typed)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return obj, nil
+}
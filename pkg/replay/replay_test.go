@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/replay"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	cm := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", ResourceVersion: "1"},
+			Data:       map[string]string{"key": value},
+		}
+	}
+
+	var recording bytes.Buffer
+	recorder := replay.NewRecorder(&recording, scheme.Scheme)
+	p := recorder.Predicate()
+	p.Create(event.CreateEvent{Object: cm("a"), IsInInitialList: true})
+	p.Update(event.UpdateEvent{ObjectOld: cm("a"), ObjectNew: cm("b")})
+	p.Delete(event.DeleteEvent{Object: cm("b")})
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("unexpected error closing recorder: %v", err)
+	}
+
+	events, err := replay.ReadEvents(bytes.NewReader(recording.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error reading events: %v", err)
+	}
+	var types []reconcile.EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	if expected := []reconcile.EventType{reconcile.EventCreate, reconcile.EventUpdate, reconcile.EventDelete}; !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected events %v, got %v", expected, types)
+	}
+	if !events[0].IsInInitialList || events[1].OldObject == nil || events[0].Object.GetKind() != "ConfigMap" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	c := fake.NewClientBuilder().Build()
+	var observed []string
+	replayer := &replay.Replayer{
+		Client: c,
+		Reconciler: reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			triggers := reconcile.TriggersFromContext(ctx)
+			if len(triggers) != 1 {
+				t.Errorf("expected one trigger, got %v", triggers)
+			}
+			obj := &corev1.ConfigMap{}
+			if err := c.Get(ctx, req.NamespacedName, obj); err != nil {
+				if apierrors.IsNotFound(err) {
+					observed = append(observed, "gone")
+					return reconcile.Result{}, nil
+				}
+				return reconcile.Result{}, err
+			}
+			observed = append(observed, obj.Data["key"])
+			return reconcile.Result{}, nil
+		}),
+	}
+	if err := replayer.Replay(context.Background(), bytes.NewReader(recording.Bytes())); err != nil {
+		t.Fatalf("unexpected error replaying: %v", err)
+	}
+	if expected := []string{"a", "b", "gone"}; !reflect.DeepEqual(observed, expected) {
+		t.Errorf("expected reconciler to observe %v, got %v", expected, observed)
+	}
+}
+
+func TestReplayAggregatesReconcileErrors(t *testing.T) {
+	var recording bytes.Buffer
+	recorder := replay.NewRecorder(&recording, scheme.Scheme)
+	for _, name := range []string{"a", "b"} {
+		if err := recorder.Record(reconcile.EventGeneric, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil); err != nil {
+			t.Fatalf("unexpected error recording: %v", err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("unexpected error closing recorder: %v", err)
+	}
+
+	calls := 0
+	replayer := &replay.Replayer{
+		Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			calls++
+			return reconcile.Result{}, errors.New("boom")
+		}),
+	}
+	err := replayer.Replay(context.Background(), &recording)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 2 {
+		t.Errorf("expected both events to be replayed, got %d reconciles", calls)
+	}
+}
+
+func TestRecorderRejectsUnknownTypes(t *testing.T) {
+	recorder := replay.NewRecorder(&bytes.Buffer{}, scheme.Scheme)
+	type unknown struct{ corev1.ConfigMap }
+	if err := recorder.Record(reconcile.EventCreate, &unknown{}, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := recorder.Close(); err == nil {
+		t.Fatal("expected the recording error to be returned by Close")
+	}
+}
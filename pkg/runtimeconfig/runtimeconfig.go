@@ -0,0 +1,309 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runtimeconfig provides an optional controller that tunes the
+// settings of a running manager, such as its log level, the concurrency of
+// its controllers and its feature gates, from a ControllerManagerConfiguration
+// object, making operator tuning a Kubernetes-native operation:
+//
+//	kubectl patch controllermanagerconfiguration controller-manager \
+//	    --type merge -p '{"spec":{"logLevel":"debug"}}'
+//
+// The ControllerManagerConfiguration CRD, returned by CRD, must be
+// installed in the cluster, and the manager must be allowed to watch the
+// objects of that CRD and to update their status.
+package runtimeconfig
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/featuregate"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtimeconfig/v1alpha1"
+)
+
+// DefaultName is the default name of the ControllerManagerConfiguration
+// object of a manager.
+const DefaultName = "controller-manager"
+
+//go:embed crd.yaml
+var crdYAML []byte
+
+// CRD returns the ControllerManagerConfiguration CustomResourceDefinition.
+func CRD() (*apiextensionsv1.CustomResourceDefinition, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.UnmarshalStrict(crdYAML, crd); err != nil {
+		return nil, fmt.Errorf("failed to decode the ControllerManagerConfiguration CRD: %w", err)
+	}
+	return crd, nil
+}
+
+// Options configures the controller added by SetupWithManager.
+type Options struct {
+	// Namespace is the namespace of the ControllerManagerConfiguration
+	// object of the manager, typically the one it runs in. It is required.
+	Namespace string
+
+	// Name is the name of the ControllerManagerConfiguration object of the
+	// manager. Defaults to DefaultName.
+	Name string
+
+	// LogLevel is the level of the logger of the manager, e.g. the one
+	// passed to zap.Level when creating it. The log level can't be changed
+	// if it is unset.
+	LogLevel *zap.AtomicLevel
+}
+
+// SetupWithManager adds to mgr a controller applying the settings of its
+// ControllerManagerConfiguration object. The controller runs on every
+// replica of the manager, since the settings are those of each process,
+// but only the leader reports them in the status of the object.
+//
+// Settings removed from the object get back to the values the manager had
+// when they were first changed, and all of them do when the object is
+// deleted.
+func SetupWithManager(mgr manager.Manager, opts Options) error {
+	if opts.Namespace == "" {
+		return errors.New("must provide the namespace of the ControllerManagerConfiguration")
+	}
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if err := v1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: opts.Namespace, Name: opts.Name}
+	r := newReconciler(mgr.GetClient(), mgr, opts.LogLevel)
+	return builder.ControllerManagedBy(mgr).
+		Named("runtimeconfig").
+		For(&v1alpha1.ControllerManagerConfiguration{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return client.ObjectKeyFromObject(obj) == key
+		}))).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(r)
+}
+
+// settings are the settings of a manager that can be changed while it runs.
+type settings interface {
+	GetControllers() []manager.ControllerInfo
+	SetMaxConcurrentReconciles(controllerName string, n int) error
+	GetFeatureGates() *featuregate.Gates
+	IsLeader() bool
+}
+
+type reconciler struct {
+	client   client.Client
+	settings settings
+	logLevel *zap.AtomicLevel
+
+	// mu guards the values of the settings before they were changed.
+	mu                  sync.Mutex
+	initialLogLevel     *zapcore.Level
+	initialConcurrency  map[string]int
+	initialFeatureGates map[string]bool
+}
+
+func newReconciler(c client.Client, s settings, logLevel *zap.AtomicLevel) *reconciler {
+	return &reconciler{
+		client:              c,
+		settings:            s,
+		logLevel:            logLevel,
+		initialConcurrency:  map[string]int{},
+		initialFeatureGates: map[string]bool{},
+	}
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	config := &v1alpha1.ControllerManagerConfiguration{}
+	if err := r.client.Get(ctx, req.NamespacedName, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, r.apply(v1alpha1.ControllerManagerConfigurationSpec{})
+		}
+		return reconcile.Result{}, err
+	}
+
+	applyErr := r.apply(config.Spec)
+	if !r.settings.IsLeader() {
+		return reconcile.Result{}, terminal(applyErr)
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Applied",
+		ObservedGeneration: config.Generation,
+	}
+	if applyErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Invalid"
+		condition.Message = applyErr.Error()
+	}
+	original := config.DeepCopy()
+	config.Status.ObservedGeneration = config.Generation
+	meta.SetStatusCondition(&config.Status.Conditions, condition)
+	if err := r.client.Status().Patch(ctx, config, client.MergeFrom(original)); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, terminal(applyErr)
+}
+
+// apply applies spec to the settings, and restores the settings it doesn't
+// set to their initial values.
+func (r *reconciler) apply(spec v1alpha1.ControllerManagerConfigurationSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(
+		r.applyLogLevel(spec.LogLevel),
+		r.applyConcurrency(spec.MaxConcurrentReconciles),
+		r.applyFeatureGates(spec.FeatureGates),
+	)
+}
+
+func (r *reconciler) applyLogLevel(value string) error {
+	if value == "" {
+		if r.initialLogLevel != nil {
+			r.logLevel.SetLevel(*r.initialLogLevel)
+			r.initialLogLevel = nil
+		}
+		return nil
+	}
+	if r.logLevel == nil {
+		return errors.New("the log level of the manager can't be changed")
+	}
+	level, err := parseLogLevel(value)
+	if err != nil {
+		return err
+	}
+	if r.initialLogLevel == nil {
+		r.initialLogLevel = ptr.To(r.logLevel.Level())
+	}
+	r.logLevel.SetLevel(level)
+	return nil
+}
+
+func (r *reconciler) applyConcurrency(concurrency map[string]int) error {
+	var errs []error
+	for name, initial := range r.initialConcurrency {
+		if _, ok := concurrency[name]; !ok {
+			if err := r.settings.SetMaxConcurrentReconciles(name, initial); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			delete(r.initialConcurrency, name)
+		}
+	}
+
+	current := map[string]int{}
+	for _, info := range r.settings.GetControllers() {
+		current[info.Name] = info.MaxConcurrentReconciles
+	}
+	for name, n := range concurrency {
+		initial, ok := current[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no controller named %q", name))
+			continue
+		}
+		if n <= 0 {
+			errs = append(errs, fmt.Errorf("invalid maximum number of concurrent reconciles %d for controller %q", n, name))
+			continue
+		}
+		if err := r.settings.SetMaxConcurrentReconciles(name, n); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := r.initialConcurrency[name]; !ok {
+			r.initialConcurrency[name] = initial
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *reconciler) applyFeatureGates(gates map[string]bool) error {
+	featureGates := r.settings.GetFeatureGates()
+	restore := map[string]bool{}
+	for name, initial := range r.initialFeatureGates {
+		if _, ok := gates[name]; !ok {
+			restore[name] = initial
+		}
+	}
+	if err := featureGates.SetFromMap(restore); err != nil {
+		return err
+	}
+	for name := range restore {
+		delete(r.initialFeatureGates, name)
+	}
+
+	initial := map[string]bool{}
+	for name := range gates {
+		if _, ok := r.initialFeatureGates[name]; !ok {
+			initial[name] = featureGates.Enabled(featuregate.Feature(name))
+		}
+	}
+	if err := featureGates.SetFromMap(gates); err != nil {
+		return err
+	}
+	for name, enabled := range initial {
+		r.initialFeatureGates[name] = enabled
+	}
+	return nil
+}
+
+// parseLogLevel parses a log level as accepted by the zap-log-level flag.
+func parseLogLevel(value string) (zapcore.Level, error) {
+	switch value {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	verbosity, err := strconv.Atoi(value)
+	if err != nil || verbosity <= 0 {
+		return 0, fmt.Errorf("invalid log level %q, must be one of debug, info or error, or an integer greater than zero", value)
+	}
+	return zapcore.Level(-verbosity), nil
+}
+
+// terminal wraps err, if any, so that invalid configurations are not
+// retried until they are changed.
+func terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return reconcile.TerminalError(err)
+}
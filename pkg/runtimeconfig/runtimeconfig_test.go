@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/featuregate"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtimeconfig/v1alpha1"
+)
+
+type fakeSettings struct {
+	concurrency map[string]int
+	gates       *featuregate.Gates
+	leader      bool
+}
+
+func (s *fakeSettings) GetControllers() []manager.ControllerInfo {
+	var infos []manager.ControllerInfo
+	for name, n := range s.concurrency {
+		infos = append(infos, manager.ControllerInfo{Name: name, MaxConcurrentReconciles: n})
+	}
+	return infos
+}
+
+func (s *fakeSettings) SetMaxConcurrentReconciles(name string, n int) error {
+	if _, ok := s.concurrency[name]; !ok {
+		return fmt.Errorf("no controller named %q", name)
+	}
+	s.concurrency[name] = n
+	return nil
+}
+
+func (s *fakeSettings) GetFeatureGates() *featuregate.Gates { return s.gates }
+
+func (s *fakeSettings) IsLeader() bool { return s.leader }
+
+const testFeature featuregate.Feature = "TestFeature"
+
+func newTestReconciler(t *testing.T, objs ...client.Object) (*reconciler, *fakeSettings, *zap.AtomicLevel, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.ControllerManagerConfiguration{}).
+		Build()
+	gates := featuregate.New()
+	if err := gates.Add(map[featuregate.Feature]featuregate.FeatureSpec{testFeature: {Default: false}}); err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSettings{concurrency: map[string]int{"pods": 1}, gates: gates, leader: true}
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	return newReconciler(c, s, &level), s, &level, c
+}
+
+var key = types.NamespacedName{Namespace: "system", Name: DefaultName}
+
+func TestReconcileAppliesAndRestoresSettings(t *testing.T) {
+	config := &v1alpha1.ControllerManagerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 2},
+		Spec: v1alpha1.ControllerManagerConfigurationSpec{
+			LogLevel:                "3",
+			MaxConcurrentReconciles: map[string]int{"pods": 4},
+			FeatureGates:            map[string]bool{string(testFeature): true},
+		},
+	}
+	r, s, level, c := newTestReconciler(t, config)
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level.Level() != zapcore.Level(-3) {
+		t.Errorf("expected log level -3, got %v", level.Level())
+	}
+	if s.concurrency["pods"] != 4 {
+		t.Errorf("expected concurrency 4, got %d", s.concurrency["pods"])
+	}
+	if !s.gates.Enabled(testFeature) {
+		t.Errorf("expected %s to be enabled", testFeature)
+	}
+	if err := c.Get(ctx, key, config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Status.ObservedGeneration != 2 || !meta.IsStatusConditionTrue(config.Status.Conditions, v1alpha1.ConditionApplied) {
+		t.Errorf("expected the configuration to be reported as applied, got %+v", config.Status)
+	}
+
+	// Removing settings restores their initial values.
+	config.Spec = v1alpha1.ControllerManagerConfigurationSpec{MaxConcurrentReconciles: map[string]int{"pods": 2}}
+	if err := c.Update(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("expected the log level to be restored, got %v", level.Level())
+	}
+	if s.gates.Enabled(testFeature) {
+		t.Errorf("expected %s to be restored", testFeature)
+	}
+	if s.concurrency["pods"] != 2 {
+		t.Errorf("expected concurrency 2, got %d", s.concurrency["pods"])
+	}
+
+	// Deleting the configuration restores all of them.
+	if err := c.Delete(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.concurrency["pods"] != 1 {
+		t.Errorf("expected the concurrency to be restored, got %d", s.concurrency["pods"])
+	}
+}
+
+func TestReconcileReportsInvalidSettings(t *testing.T) {
+	config := &v1alpha1.ControllerManagerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1},
+		Spec: v1alpha1.ControllerManagerConfigurationSpec{
+			LogLevel:                "verbose",
+			MaxConcurrentReconciles: map[string]int{"unknown": 2},
+		},
+	}
+	r, _, _, c := newTestReconciler(t, config)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	if !errors.Is(err, reconcile.TerminalError(nil)) {
+		t.Fatalf("expected a terminal error, got %v", err)
+	}
+	if err := c.Get(ctx, key, config); err != nil {
+		t.Fatal(err)
+	}
+	condition := meta.FindStatusCondition(config.Status.Conditions, v1alpha1.ConditionApplied)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "Invalid" {
+		t.Errorf("expected the configuration to be reported as invalid, got %+v", condition)
+	}
+}
+
+func TestReconcileOnlyReportsStatusAsLeader(t *testing.T) {
+	config := &v1alpha1.ControllerManagerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1},
+		Spec:       v1alpha1.ControllerManagerConfigurationSpec{LogLevel: "debug"},
+	}
+	r, s, level, c := newTestReconciler(t, config)
+	s.leader = false
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level.Level() != zapcore.DebugLevel {
+		t.Errorf("expected log level debug, got %v", level.Level())
+	}
+	if err := c.Get(ctx, key, config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Status.Conditions) != 0 {
+		t.Errorf("expected no status from a replica that is not the leader, got %+v", config.Status)
+	}
+}
+
+func TestCRD(t *testing.T) {
+	crd, err := CRD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crd.Spec.Group != v1alpha1.GroupVersion.Group || crd.Spec.Versions[0].Name != v1alpha1.GroupVersion.Version {
+		t.Errorf("unexpected CRD group version %s/%s", crd.Spec.Group, crd.Spec.Versions[0].Name)
+	}
+}
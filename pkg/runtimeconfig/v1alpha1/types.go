@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionApplied is the type of the condition reporting whether the
+// configuration has been applied to the manager.
+const ConditionApplied = "Applied"
+
+// ControllerManagerConfigurationSpec defines the settings of a manager that
+// can be changed while it runs. Unset settings keep, or get back to, the
+// values the manager was started with.
+type ControllerManagerConfigurationSpec struct {
+	// LogLevel is the level of the logger of the manager: one of debug,
+	// info or error, or an integer greater than zero, the verbosity of the
+	// most detailed logs to emit.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// MaxConcurrentReconciles maps the names of controllers to their
+	// maximum number of concurrent Reconciles.
+	// +optional
+	MaxConcurrentReconciles map[string]int `json:"maxConcurrentReconciles,omitempty"`
+
+	// FeatureGates maps the names of features to whether they are enabled.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// ControllerManagerConfigurationStatus reports whether the configuration
+// has been applied.
+type ControllerManagerConfigurationStatus struct {
+	// ObservedGeneration is the generation of the configuration last
+	// applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether the configuration has been applied.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// ControllerManagerConfiguration tunes the settings of a running manager.
+type ControllerManagerConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ControllerManagerConfigurationSpec   `json:"spec,omitempty"`
+	Status ControllerManagerConfigurationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ControllerManagerConfigurationList contains a list of
+// ControllerManagerConfiguration.
+type ControllerManagerConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ControllerManagerConfiguration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ControllerManagerConfiguration{}, &ControllerManagerConfigurationList{})
+}
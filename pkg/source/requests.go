@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+var pollLog = logf.RuntimeLog.WithName("source").WithName("Poll")
+
+// Requests returns a source that enqueues the requests received from ch as
+// they are. Unlike Channel, it doesn't require an object nor a handler, which
+// makes it suited to controllers reconciling resources outside the cluster
+// that are identified by keys which don't correspond to any object, such as
+// reconcile.ExternalRequest:
+//
+//	requests := make(chan reconcile.ExternalRequest)
+//	builder.TypedControllerManagedBy[reconcile.ExternalRequest](mgr).
+//		Named("bucket").
+//		WatchesRawSource(source.Requests(requests)).
+//		Complete(r)
+//
+// The source stops when ch is closed or the controller stops.
+func Requests[request comparable](ch <-chan request) TypedSource[request] {
+	return &requestsSource[request]{source: ch}
+}
+
+type requestsSource[request comparable] struct {
+	source <-chan request
+}
+
+func (rs *requestsSource[request]) String() string {
+	return fmt.Sprintf("requests source: %p", rs)
+}
+
+// Start implements Source and should only be called by the Controller.
+func (rs *requestsSource[request]) Start(ctx context.Context, queue workqueue.TypedRateLimitingInterface[request]) error {
+	if rs.source == nil {
+		return errors.New("must specify a channel of requests")
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case req, stillOpen := <-rs.source:
+				if !stillOpen {
+					return
+				}
+				queue.Add(req)
+			}
+		}
+	}()
+	return nil
+}
+
+// ListFunc lists the requests of the resources that exist outside the cluster.
+type ListFunc[request comparable] func(context.Context) ([]request, error)
+
+// Poll returns a source that calls list every interval, starting
+// immediately, and enqueues all the requests it returns. It periodically
+// reconciles every resource of an external system, e.g. the buckets of a
+// cloud provider, so that resources created or changed out of band are
+// noticed. Requests that are already queued are not queued twice. Errors of
+// list are logged and the next poll is attempted after interval.
+func Poll[request comparable](interval time.Duration, list ListFunc[request]) TypedSource[request] {
+	return &pollSource[request]{interval: interval, list: list}
+}
+
+type pollSource[request comparable] struct {
+	interval time.Duration
+	list     ListFunc[request]
+}
+
+func (ps *pollSource[request]) String() string {
+	return fmt.Sprintf("poll source: %p", ps)
+}
+
+// Start implements Source and should only be called by the Controller.
+func (ps *pollSource[request]) Start(ctx context.Context, queue workqueue.TypedRateLimitingInterface[request]) error {
+	if ps.list == nil {
+		return errors.New("must specify a ListFunc")
+	}
+	if ps.interval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		requests, err := ps.list(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				pollLog.Error(err, "failed to list requests")
+			}
+			return
+		}
+		for _, req := range requests {
+			queue.Add(req)
+		}
+	}, ps.interval)
+	return nil
+}
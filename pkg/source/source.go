@@ -51,6 +51,8 @@ type Source = TypedSource[reconcile.Request]
 //
 // * Use Channel for events originating outside the cluster (e.g. GitHub Webhook callback, Polling external urls).
 //
+// * Use Requests or Poll for requests that don't correspond to objects (e.g. IDs of resources of a cloud provider).
+//
 // Users may build their own Source implementations.
 type TypedSource[request comparable] interface {
 	// Start is internal and should be called only by the Controller to start the source.
@@ -94,6 +96,62 @@ func TypedKind[object client.Object, request comparable](
 	}
 }
 
+// KindOptions configures a Kind source created with KindWithOptions.
+type KindOptions[object client.Object] struct {
+	// Predicates filter the events before they are handed to the handler.
+	Predicates []predicate.TypedPredicate[object]
+
+	// PageSize, if positive, is the number of objects requested per page
+	// when the informer lists the objects, to limit the load the initial list
+	// of big types, e.g. Pods or Secrets, puts on the API server. It only
+	// applies if the informer is created by this source.
+	PageSize int64
+
+	// Informer, if set, is the informer the source registers its handler
+	// with, instead of getting one from the cache. It allows several
+	// sources, possibly of different managers, to share an informer
+	// explicitly. The source waits for that informer to sync.
+	Informer cache.Informer
+
+	// Lazy makes the controller start its workers without waiting for the
+	// informer to list the objects: the requests of the listed objects are
+	// queued as the list comes in instead of all at once after it.
+	Lazy bool
+}
+
+// KindWithOptions creates a KindSource with the given cache provider and
+// options. The cache may be nil if the options set an Informer.
+func KindWithOptions[object client.Object](
+	cache cache.Cache,
+	obj object,
+	handler handler.TypedEventHandler[object, reconcile.Request],
+	opts KindOptions[object],
+) SyncingSource {
+	return TypedKindWithOptions(cache, obj, handler, opts)
+}
+
+// TypedKindWithOptions creates a KindSource with the given cache provider and
+// options. The cache may be nil if the options set an Informer.
+func TypedKindWithOptions[object client.Object, request comparable](
+	c cache.Cache,
+	obj object,
+	handler handler.TypedEventHandler[object, request],
+	opts KindOptions[object],
+) TypedSyncingSource[request] {
+	ks := &internal.Kind[object, request]{
+		Type:       obj,
+		Cache:      c,
+		Informer:   opts.Informer,
+		Lazy:       opts.Lazy,
+		Handler:    handler,
+		Predicates: opts.Predicates,
+	}
+	if opts.PageSize > 0 {
+		ks.GetOptions = append(ks.GetOptions, cache.WithInformerPageSize(opts.PageSize))
+	}
+	return ks
+}
+
 var _ Source = &channel[string, reconcile.Request]{}
 
 // ChannelOpt allows to configure a source.Channel.
@@ -210,7 +268,7 @@ func (cs *channel[object, request]) Start(
 				func() {
 					ctx, cancel := context.WithCancel(ctx)
 					defer cancel()
-					cs.handler.Generic(ctx, evt, queue)
+					cs.handler.Generic(ctx, evt, internal.WithTrigger(queue, reconcile.EventGeneric, evt.Object))
 				}()
 			}
 		}
@@ -26,6 +26,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -253,6 +254,45 @@ var _ = Describe("Source", func() {
 		})
 	})
 
+	Describe("KindWithOptions", func() {
+		It("should register with the given informer instead of the cache", func() {
+			i := &controllertest.FakeInformer{Synced: true}
+			q := workqueue.NewTypedRateLimitingQueueWithConfig(
+				workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+				workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+					Name: "test",
+				})
+			defer q.ShutDown()
+
+			instance := source.KindWithOptions(nil, &corev1.Pod{}, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{},
+				source.KindOptions[*corev1.Pod]{Informer: i})
+			Expect(instance.Start(ctx, q)).To(Succeed())
+			Expect(instance.WaitForSync(ctx)).To(Succeed())
+
+			i.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}})
+			Eventually(q.Len).Should(Equal(1))
+		})
+
+		It("should wait for the given informer to sync", func() {
+			i := &controllertest.FakeInformer{}
+			instance := source.KindWithOptions(nil, &corev1.Pod{}, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{},
+				source.KindOptions[*corev1.Pod]{Informer: i})
+			Expect(instance.Start(ctx, nil)).To(Succeed())
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+			Expect(instance.WaitForSync(timeoutCtx)).To(MatchError(ContainSubstring("timed out waiting for cache to be synced")))
+		})
+
+		It("should not wait for the cache to sync when lazy", func() {
+			f := false
+			instance := source.KindWithOptions[client.Object](&informertest.FakeInformers{Synced: &f}, &corev1.Pod{}, &handler.EnqueueRequestForObject{},
+				source.KindOptions[client.Object]{Lazy: true})
+			Expect(instance.Start(ctx, nil)).To(Succeed())
+			Expect(instance.WaitForSync(ctx)).To(Succeed())
+		})
+	})
+
 	Describe("Func", func() {
 		It("should be called from Start", func() {
 			run := false
@@ -275,6 +315,82 @@ var _ = Describe("Source", func() {
 		})
 	})
 
+	Describe("Requests", func() {
+		It("should enqueue the requests received from the channel", func() {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.ExternalRequest]())
+			defer q.ShutDown()
+			ch := make(chan reconcile.ExternalRequest)
+			Expect(source.Requests(ch).Start(ctx, q)).To(Succeed())
+
+			ch <- reconcile.ExternalRequest{Kind: "bucket", ID: "b-1"}
+			Eventually(q.Len).Should(Equal(1))
+			req, _ := q.Get()
+			Expect(req).To(Equal(reconcile.ExternalRequest{Kind: "bucket", ID: "b-1"}))
+			Expect(req.String()).To(Equal("bucket/b-1"))
+		})
+
+		It("should get error if no channel specified", func() {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+			defer q.ShutDown()
+			Expect(source.Requests[string](nil).Start(ctx, q)).NotTo(Succeed())
+		})
+	})
+
+	Describe("Poll", func() {
+		It("should periodically enqueue the listed requests", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.ExternalRequest]())
+			defer q.ShutDown()
+
+			polls := make(chan struct{}, 10)
+			src := source.Poll(10*time.Millisecond, func(context.Context) ([]reconcile.ExternalRequest, error) {
+				polls <- struct{}{}
+				return []reconcile.ExternalRequest{{ID: "a"}, {ID: "b"}}, nil
+			})
+			Expect(src.Start(ctx, q)).To(Succeed())
+
+			By("polling more than once")
+			Eventually(polls).Should(Receive())
+			Eventually(polls).Should(Receive())
+
+			By("not queueing requests twice")
+			Expect(q.Len()).To(Equal(2))
+			first, _ := q.Get()
+			second, _ := q.Get()
+			Expect([]reconcile.ExternalRequest{first, second}).To(ConsistOf(
+				reconcile.ExternalRequest{ID: "a"}, reconcile.ExternalRequest{ID: "b"}))
+		})
+
+		It("should keep polling after errors", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+			defer q.ShutDown()
+
+			calls := 0
+			polled := make(chan struct{})
+			src := source.Poll(10*time.Millisecond, func(context.Context) ([]string, error) {
+				calls++
+				if calls == 1 {
+					return nil, fmt.Errorf("expected error")
+				}
+				if calls == 2 {
+					close(polled)
+				}
+				return []string{"a"}, nil
+			})
+			Expect(src.Start(ctx, q)).To(Succeed())
+			Eventually(polled).Should(BeClosed())
+			Eventually(q.Len).Should(Equal(1))
+		})
+
+		It("should get error if the interval is not positive", func() {
+			src := source.Poll(0, func(context.Context) ([]string, error) { return nil, nil })
+			Expect(src.Start(ctx, nil)).NotTo(Succeed())
+		})
+	})
+
 	Describe("Channel", func() {
 		var ctx context.Context
 		var cancel context.CancelFunc
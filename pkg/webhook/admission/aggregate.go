@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// AggregateValidateFunc validates the object of an admission request against
+// the existing objects it is aggregated with, e.g. the other objects of its
+// namespace. A non-nil error denies the request.
+type AggregateValidateFunc func(ctx context.Context, obj client.Object, existing []client.Object) error
+
+// AggregateValidation configures a handler returned by
+// WithAggregateValidation.
+type AggregateValidation struct {
+	// Scheme is used to decode objects and map the list type to a kind. It
+	// is required.
+	Scheme *runtime.Scheme
+
+	// Reader reads the existing objects. It is typically the cache of a
+	// manager, which reads fast but may lag behind the API server, or its
+	// API reader for consistent reads at the cost of a list request per
+	// admission request. It is required.
+	Reader client.Reader
+
+	// List is an empty list of the type of the objects the webhook admits,
+	// e.g. &corev1.ConfigMapList{}. It is required.
+	List client.ObjectList
+
+	// ListOptions returns the options selecting the objects the object of a
+	// request is aggregated with. Only the namespace and label selector of
+	// the options are used to select recently admitted objects. Defaults to
+	// the objects of the namespace of the request.
+	ListOptions func(req Request) []client.ListOption
+
+	// Validate validates the object of a create request against the objects
+	// it is aggregated with. It is required.
+	Validate AggregateValidateFunc
+
+	// ReservationTTL is how long objects that were admitted are counted as
+	// existing objects while Reader doesn't return them. Defaults to 30
+	// seconds; it should exceed the lag of Reader.
+	ReservationTTL time.Duration
+}
+
+// WithAggregateValidation returns a handler validating created objects
+// against aggregates of existing objects, e.g. to allow no more than N
+// objects of a kind per namespace.
+//
+// Reads from a cache, and even from the API server, don't return objects
+// admitted by concurrent requests that were not persisted yet, which lets
+// bursts of requests exceed a limit. The handler mitigates that by recording
+// the objects it admits in a local reservation ledger. Reserved objects are
+// passed to Validate with the existing ones until Reader returns them or
+// their reservation expires, and requests are validated one at a time. The
+// ledger is local to the process: with several replicas of a webhook, each
+// replica may admit objects concurrently. Dry-run requests don't reserve
+// objects, and reservations of objects that are eventually not created, e.g.
+// because another webhook denied them, only expire after ReservationTTL.
+//
+// Requests other than creations are allowed.
+func WithAggregateValidation(validation AggregateValidation) (Handler, error) {
+	switch {
+	case validation.Scheme == nil:
+		return nil, errors.New("must provide a Scheme")
+	case validation.Reader == nil:
+		return nil, errors.New("must provide a Reader")
+	case validation.List == nil:
+		return nil, errors.New("must provide a List")
+	case validation.Validate == nil:
+		return nil, errors.New("must provide a Validate function")
+	}
+	gvk, err := apiutil.GVKForObject(validation.List, validation.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	if !validation.Scheme.Recognizes(gvk) {
+		return nil, fmt.Errorf("kind %s of the items of %T is not registered in the scheme", gvk, validation.List)
+	}
+	if validation.ListOptions == nil {
+		validation.ListOptions = func(req Request) []client.ListOption {
+			return []client.ListOption{client.InNamespace(req.Namespace)}
+		}
+	}
+	if validation.ReservationTTL <= 0 {
+		validation.ReservationTTL = 30 * time.Second
+	}
+	return &aggregateValidator{
+		validation:   validation,
+		gvk:          gvk,
+		decoder:      NewDecoder(validation.Scheme),
+		reservations: map[types.NamespacedName]reservation{},
+	}, nil
+}
+
+// MaxObjectsPerScope returns an AggregateValidateFunc that allows no more
+// than limit objects in the scope selected by the ListOptions of an
+// AggregateValidation, which is a namespace by default.
+func MaxObjectsPerScope(limit int) AggregateValidateFunc {
+	return func(_ context.Context, _ client.Object, existing []client.Object) error {
+		if len(existing) >= limit {
+			return fmt.Errorf("exceeded the limit of %d objects", limit)
+		}
+		return nil
+	}
+}
+
+type reservation struct {
+	object  client.Object
+	expires time.Time
+}
+
+type aggregateValidator struct {
+	validation AggregateValidation
+	gvk        schema.GroupVersionKind
+	decoder    Decoder
+
+	// mu serializes validations, and guards reservations.
+	mu           sync.Mutex
+	reservations map[types.NamespacedName]reservation
+}
+
+// Handle implements Handler.
+func (v *aggregateValidator) Handle(ctx context.Context, req Request) Response {
+	if req.Operation != admissionv1.Create {
+		return Allowed("")
+	}
+	obj, err := v.newObject()
+	if err != nil {
+		return Errored(http.StatusInternalServerError, err)
+	}
+	if err := v.decoder.Decode(req, obj); err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(req.Namespace)
+	}
+	if obj.GetName() == "" {
+		// Objects created with a generated name have no name yet.
+		obj.SetName(req.Name)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	opts := v.validation.ListOptions(req)
+	existing, err := v.existing(ctx, opts)
+	if err != nil {
+		return Errored(http.StatusInternalServerError, err)
+	}
+	if err := v.validation.Validate(NewContextWithRequest(ctx, req), obj, existing); err != nil {
+		var apiStatus apierrors.APIStatus
+		if errors.As(err, &apiStatus) {
+			return validationResponseFromStatus(false, apiStatus.Status())
+		}
+		return Denied(err.Error())
+	}
+
+	if req.DryRun == nil || !*req.DryRun {
+		key := client.ObjectKeyFromObject(obj)
+		if key.Name == "" {
+			// Reservations of objects without a name can't be matched with
+			// the objects read, so they only expire.
+			key.Name = "generated-" + string(req.UID)
+		}
+		v.reservations[key] = reservation{object: obj, expires: time.Now().Add(v.validation.ReservationTTL)}
+	}
+	return Allowed("")
+}
+
+// existing returns the objects selected by opts, and the objects reserved
+// in their scope that were not read. Reservations of objects that were read,
+// or that expired, are dropped.
+func (v *aggregateValidator) existing(ctx context.Context, opts []client.ListOption) ([]client.Object, error) {
+	list := v.validation.List.DeepCopyObject().(client.ObjectList)
+	if err := v.validation.Reader.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list existing objects: %w", err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]client.Object, 0, len(items))
+	read := make(map[types.NamespacedName]struct{}, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("list item of type %T is not a client.Object", item)
+		}
+		existing = append(existing, obj)
+		read[client.ObjectKeyFromObject(obj)] = struct{}{}
+	}
+
+	listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+	now := time.Now()
+	for key, r := range v.reservations {
+		if _, ok := read[key]; ok || now.After(r.expires) {
+			delete(v.reservations, key)
+			continue
+		}
+		if listOpts.Namespace != "" && r.object.GetNamespace() != listOpts.Namespace {
+			continue
+		}
+		if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labels.Set(r.object.GetLabels())) {
+			continue
+		}
+		existing = append(existing, r.object)
+	}
+	return existing, nil
+}
+
+func (v *aggregateValidator) newObject() (client.Object, error) {
+	obj, err := v.validation.Scheme.New(v.gvk)
+	if err != nil {
+		return nil, err
+	}
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("object of type %T is not a client.Object", obj)
+	}
+	return clientObj, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WithAggregateValidation", func() {
+	var reader client.Client
+	var handler Handler
+
+	createRequest := func(namespace, name string, labels map[string]string) Request {
+		raw, err := json.Marshal(&corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UID:       types.UID(name),
+			Operation: admissionv1.Create,
+			Namespace: namespace,
+			Name:      name,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	BeforeEach(func() {
+		reader = fake.NewClientBuilder().WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"}},
+		).Build()
+		var err error
+		handler, err = WithAggregateValidation(AggregateValidation{
+			Scheme:   scheme.Scheme,
+			Reader:   reader,
+			List:     &corev1.ConfigMapList{},
+			Validate: MaxObjectsPerScope(2),
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should count the objects admitted but not read yet", func() {
+		Expect(handler.Handle(context.Background(), createRequest("default", "first", nil)).Allowed).To(BeTrue())
+
+		resp := handler.Handle(context.Background(), createRequest("default", "second", nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(Equal("exceeded the limit of 2 objects"))
+
+		Expect(handler.Handle(context.Background(), createRequest("other", "second", nil)).Allowed).To(BeTrue())
+	})
+
+	It("should drop reservations once the objects are read", func() {
+		Expect(handler.Handle(context.Background(), createRequest("default", "first", nil)).Allowed).To(BeTrue())
+		Expect(reader.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "first"}})).To(Succeed())
+		Expect(handler.Handle(context.Background(), createRequest("default", "second", nil)).Allowed).To(BeFalse())
+
+		Expect(reader.Delete(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "first"}})).To(Succeed())
+		Expect(handler.Handle(context.Background(), createRequest("default", "second", nil)).Allowed).To(BeTrue())
+	})
+
+	It("should not reserve objects of dry-run requests", func() {
+		req := createRequest("default", "first", nil)
+		req.DryRun = ptr.To(true)
+		Expect(handler.Handle(context.Background(), req).Allowed).To(BeTrue())
+		Expect(handler.Handle(context.Background(), createRequest("default", "second", nil)).Allowed).To(BeTrue())
+	})
+
+	It("should only count the reservations selected by the list options", func() {
+		var err error
+		handler, err = WithAggregateValidation(AggregateValidation{
+			Scheme: scheme.Scheme,
+			Reader: reader,
+			List:   &corev1.ConfigMapList{},
+			ListOptions: func(req Request) []client.ListOption {
+				return []client.ListOption{client.InNamespace(req.Namespace), client.MatchingLabels{"tier": "gold"}}
+			},
+			Validate: MaxObjectsPerScope(1),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		gold := map[string]string{"tier": "gold"}
+		Expect(handler.Handle(context.Background(), createRequest("default", "first", gold)).Allowed).To(BeTrue())
+		Expect(handler.Handle(context.Background(), createRequest("default", "second", gold)).Allowed).To(BeFalse())
+		Expect(handler.Handle(context.Background(), createRequest("default", "third", nil)).Allowed).To(BeFalse())
+	})
+
+	It("should pass the object and the existing objects to Validate", func() {
+		var names []string
+		var err error
+		handler, err = WithAggregateValidation(AggregateValidation{
+			Scheme: scheme.Scheme,
+			Reader: reader,
+			List:   &corev1.ConfigMapList{},
+			Validate: func(ctx context.Context, obj client.Object, existing []client.Object) error {
+				if _, err := RequestFromContext(ctx); err != nil {
+					return err
+				}
+				names = []string{obj.GetName()}
+				for _, e := range existing {
+					names = append(names, e.GetName())
+				}
+				return errors.New("denied")
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := handler.Handle(context.Background(), createRequest("default", "first", nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(Equal("denied"))
+		Expect(names).To(Equal([]string{"first", "existing"}))
+	})
+
+	It("should allow requests other than creations", func() {
+		req := createRequest("default", "first", nil)
+		req.Operation = admissionv1.Delete
+		Expect(handler.Handle(context.Background(), req).Allowed).To(BeTrue())
+	})
+
+	It("should fail for lists of kinds that are not registered", func() {
+		_, err := WithAggregateValidation(AggregateValidation{
+			Scheme:   runtime.NewScheme(),
+			Reader:   reader,
+			List:     &corev1.ConfigMapList{},
+			Validate: MaxObjectsPerScope(1),
+		})
+		Expect(err).To(MatchError(ContainSubstring("no kind is registered")))
+	})
+})
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackpressureCheck reports why admission requests should be deferred, e.g.
+// because the controllers whose cache the handler reads lag behind, or an
+// empty string if they can be handled.
+type BackpressureCheck func(ctx context.Context) string
+
+// BackpressureOptions configures a handler returned by WithBackpressure.
+type BackpressureOptions struct {
+	// Check reports whether requests should be deferred. It is required.
+	Check BackpressureCheck
+
+	// Wait is how long a request waits for the pressure to go away before
+	// it is denied. Defaults to zero, which denies requests immediately.
+	// It must be lower than the timeout of the webhook configuration.
+	Wait time.Duration
+
+	// PollInterval is the interval at which Check is called while a
+	// request waits. Defaults to 100ms.
+	PollInterval time.Duration
+
+	// RetryAfter is the delay after which clients are told to retry denied
+	// requests. Defaults to one second.
+	RetryAfter time.Duration
+}
+
+// WithBackpressure returns a handler that consults opts.Check before
+// delegating requests to h, so that admission decisions are not based on a
+// badly lagging cache. Under pressure, requests wait up to opts.Wait for it
+// to go away and are then denied with a 429 Too Many Requests status that
+// asks clients to retry after opts.RetryAfter. Clients such as kubectl and
+// client-go retry those requests automatically.
+func WithBackpressure(h Handler, opts BackpressureOptions) Handler {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 100 * time.Millisecond
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = time.Second
+	}
+	return &backpressureHandler{handler: h, opts: opts}
+}
+
+type backpressureHandler struct {
+	handler Handler
+	opts    BackpressureOptions
+}
+
+// Handle implements Handler.
+func (b *backpressureHandler) Handle(ctx context.Context, req Request) Response {
+	reason := b.opts.Check(ctx)
+	if reason != "" && b.opts.Wait > 0 {
+		reason = b.wait(ctx, reason)
+	}
+	if reason != "" {
+		return tooManyRequests(reason, b.opts.RetryAfter)
+	}
+	return b.handler.Handle(ctx, req)
+}
+
+// wait polls the check until it reports no pressure, or the wait times out,
+// and returns the last reason reported.
+func (b *backpressureHandler) wait(ctx context.Context, reason string) string {
+	ctx, cancel := context.WithTimeout(ctx, b.opts.Wait)
+	defer cancel()
+	ticker := time.NewTicker(b.opts.PollInterval)
+	defer ticker.Stop()
+	for reason != "" {
+		select {
+		case <-ctx.Done():
+			return reason
+		case <-ticker.C:
+		}
+		reason = b.opts.Check(ctx)
+	}
+	return reason
+}
+
+func tooManyRequests(reason string, retryAfter time.Duration) Response {
+	return Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusTooManyRequests,
+				Reason:  metav1.StatusReasonTooManyRequests,
+				Message: fmt.Sprintf("admission deferred, retry later: %s", reason),
+				Details: &metav1.StatusDetails{
+					RetryAfterSeconds: int32(math.Ceil(retryAfter.Seconds())),
+				},
+			},
+		},
+	}
+}
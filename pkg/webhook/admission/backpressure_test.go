@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("WithBackpressure", func() {
+	var handled atomic.Int32
+	allow := HandlerFunc(func(ctx context.Context, req Request) Response {
+		handled.Add(1)
+		return Allowed("")
+	})
+
+	BeforeEach(func() {
+		handled.Store(0)
+	})
+
+	It("should delegate requests when there is no pressure", func() {
+		h := WithBackpressure(allow, BackpressureOptions{
+			Check: func(context.Context) string { return "" },
+		})
+		Expect(h.Handle(context.Background(), Request{}).Allowed).To(BeTrue())
+		Expect(handled.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should deny requests with a retry delay under pressure", func() {
+		h := WithBackpressure(allow, BackpressureOptions{
+			Check:      func(context.Context) string { return "cache is lagging" },
+			RetryAfter: 1500 * time.Millisecond,
+		})
+		resp := h.Handle(context.Background(), Request{})
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(BeEquivalentTo(http.StatusTooManyRequests))
+		Expect(resp.Result.Reason).To(Equal(metav1.StatusReasonTooManyRequests))
+		Expect(resp.Result.Message).To(ContainSubstring("cache is lagging"))
+		Expect(resp.Result.Details.RetryAfterSeconds).To(BeEquivalentTo(2))
+		Expect(handled.Load()).To(BeZero())
+	})
+
+	It("should defer requests until the pressure goes away", func() {
+		var checks atomic.Int32
+		h := WithBackpressure(allow, BackpressureOptions{
+			Check: func(context.Context) string {
+				if checks.Add(1) < 3 {
+					return "cache is lagging"
+				}
+				return ""
+			},
+			Wait:         5 * time.Second,
+			PollInterval: 10 * time.Millisecond,
+		})
+		Expect(h.Handle(context.Background(), Request{}).Allowed).To(BeTrue())
+		Expect(checks.Load()).To(BeEquivalentTo(3))
+		Expect(handled.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should deny requests when the pressure outlasts the wait", func() {
+		h := WithBackpressure(allow, BackpressureOptions{
+			Check:        func(context.Context) string { return "cache is lagging" },
+			Wait:         50 * time.Millisecond,
+			PollInterval: 10 * time.Millisecond,
+		})
+		Expect(h.Handle(context.Background(), Request{}).Result.Code).To(BeEquivalentTo(http.StatusTooManyRequests))
+		Expect(handled.Load()).To(BeZero())
+	})
+})
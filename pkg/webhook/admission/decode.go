@@ -18,14 +18,24 @@ package admission
 
 import (
 	"fmt"
+	"reflect"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 // Decoder knows how to decode the contents of an admission
 // request into a concrete object.
+//
+// If the object in the request is of a different version than the passed-in
+// object, the decoder converts it using the scheme: hub and spoke types
+// implementing the interfaces in pkg/conversion are converted through
+// ConvertTo and ConvertFrom, all other types through the conversion functions
+// registered with the scheme. This lets a single handler written against the
+// hub version serve every version of a kind.
 type Decoder interface {
 	// Decode decodes the inlined object in the AdmissionRequest into the passed-in runtime.Object.
 	// If you want decode the OldObject in the AdmissionRequest, use DecodeRaw.
@@ -40,15 +50,20 @@ type Decoder interface {
 // decoder knows how to decode the contents of an admission
 // request into a concrete object.
 type decoder struct {
+	scheme *runtime.Scheme
 	codecs serializer.CodecFactory
 }
 
 // NewDecoder creates a decoder given the runtime.Scheme.
 func NewDecoder(scheme *runtime.Scheme) Decoder {
+	return newDecoder(scheme)
+}
+
+func newDecoder(scheme *runtime.Scheme) *decoder {
 	if scheme == nil {
 		panic("scheme should never be nil")
 	}
-	return &decoder{codecs: serializer.NewCodecFactory(scheme)}
+	return &decoder{scheme: scheme, codecs: serializer.NewCodecFactory(scheme)}
 }
 
 // Decode decodes the inlined object in the AdmissionRequest into the passed-in runtime.Object.
@@ -88,5 +103,71 @@ func (d *decoder) DecodeRaw(rawObj runtime.RawExtension, into runtime.Object) er
 	}
 
 	deserializer := d.codecs.UniversalDeserializer()
-	return runtime.DecodeInto(deserializer, rawObj.Raw, into)
+	out, gvk, err := deserializer.Decode(rawObj.Raw, nil, into)
+	if err != nil {
+		return err
+	}
+	if out == into {
+		return nil
+	}
+
+	// The object is of another version (or kind) than into; try to
+	// convert it into the version the caller asked for.
+	if err := d.convert(out, into); err != nil {
+		return fmt.Errorf("unable to decode %s into %T: %w", gvk, into, err)
+	}
+	return nil
+}
+
+// encodeAs converts obj back into the version of the object in rawObj, so
+// that changes made to an object decoded by DecodeRaw can be expressed in
+// the version the API server sent. If no conversion happened during decoding,
+// obj is returned unchanged.
+func (d *decoder) encodeAs(rawObj runtime.RawExtension, obj runtime.Object) (runtime.Object, error) {
+	if _, isUnstructured := obj.(runtime.Unstructured); isUnstructured {
+		return obj, nil
+	}
+	out, _, err := d.codecs.UniversalDeserializer().Decode(rawObj.Raw, nil, obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+	gvk := out.GetObjectKind().GroupVersionKind()
+	if reflect.TypeOf(out) == reflect.TypeOf(obj) {
+		return obj, nil
+	}
+	if err := d.convert(obj, out); err != nil {
+		return nil, err
+	}
+	out.GetObjectKind().SetGroupVersionKind(gvk)
+	return out, nil
+}
+
+// convert converts in into out, preferring the hub and spoke interfaces
+// over the conversion functions registered with the scheme.
+func (d *decoder) convert(in, out runtime.Object) error {
+	if hub, ok := out.(conversion.Hub); ok {
+		if spoke, ok := in.(conversion.Convertible); ok {
+			return d.setKind(out, spoke.ConvertTo(hub))
+		}
+	}
+	if hub, ok := in.(conversion.Hub); ok {
+		if spoke, ok := out.(conversion.Convertible); ok {
+			return d.setKind(out, spoke.ConvertFrom(hub))
+		}
+	}
+	return d.setKind(out, d.scheme.Convert(in, out, nil))
+}
+
+// setKind populates the TypeMeta of a freshly converted object, which
+// conversion functions usually leave alone.
+func (d *decoder) setKind(obj runtime.Object, err error) error {
+	if err != nil {
+		return err
+	}
+	gvks, _, err := d.scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	return nil
 }
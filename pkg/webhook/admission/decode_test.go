@@ -26,6 +26,9 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+
+	jobsv1 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v1"
+	jobsv2 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v2"
 )
 
 var _ = Describe("Admission Webhook Decoder", func() {
@@ -186,3 +189,57 @@ var _ = Describe("Admission Webhook Decoder", func() {
 		Expect(decoder.DecodeRaw(req2.Object, &target3)).To(Succeed())
 	})
 })
+
+var _ = Describe("Admission Webhook Decoder with multiple versions", func() {
+	var jobsDecoder *decoder
+	BeforeEach(func() {
+		jobsScheme := runtime.NewScheme()
+		Expect(jobsv1.AddToScheme(jobsScheme)).To(Succeed())
+		Expect(jobsv2.AddToScheme(jobsScheme)).To(Succeed())
+		jobsDecoder = NewDecoder(jobsScheme).(*decoder)
+	})
+
+	v1Job := runtime.RawExtension{
+		Raw: []byte(`{
+    "apiVersion": "jobs.testprojects.kb.io/v1",
+    "kind": "ExternalJob",
+    "metadata": {
+        "name": "foo",
+        "namespace": "default"
+    },
+    "spec": {
+        "runAt": "every 2 seconds"
+    }
+}`),
+	}
+
+	It("should convert a spoke version into the hub", func() {
+		job := &jobsv2.ExternalJob{}
+		Expect(jobsDecoder.DecodeRaw(v1Job, job)).To(Succeed())
+		Expect(job.APIVersion).To(Equal("jobs.testprojects.kb.io/v2"))
+		Expect(job.Kind).To(Equal("ExternalJob"))
+		Expect(job.Name).To(Equal("foo"))
+		Expect(job.Spec.ScheduleAt).To(Equal("every 2 seconds"))
+	})
+
+	It("should convert the hub back into the version of the request", func() {
+		job := &jobsv2.ExternalJob{}
+		Expect(jobsDecoder.DecodeRaw(v1Job, job)).To(Succeed())
+		job.Spec.ScheduleAt = "every 3 seconds"
+
+		out, err := jobsDecoder.encodeAs(v1Job, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(BeAssignableToTypeOf(&jobsv1.ExternalJob{}))
+		Expect(out.(*jobsv1.ExternalJob).APIVersion).To(Equal("jobs.testprojects.kb.io/v1"))
+		Expect(out.(*jobsv1.ExternalJob).Spec.RunAt).To(Equal("every 3 seconds"))
+	})
+
+	It("should leave objects decoded without conversion unchanged", func() {
+		job := &jobsv1.ExternalJob{}
+		Expect(jobsDecoder.DecodeRaw(v1Job, job)).To(Succeed())
+
+		out, err := jobsDecoder.encodeAs(v1Job, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(BeIdenticalTo(job))
+	})
+})
@@ -36,14 +36,16 @@ type CustomDefaulter interface {
 // WithCustomDefaulter creates a new Webhook for a CustomDefaulter interface.
 func WithCustomDefaulter(scheme *runtime.Scheme, obj runtime.Object, defaulter CustomDefaulter) *Webhook {
 	return &Webhook{
-		Handler: &defaulterForType{object: obj, defaulter: defaulter, decoder: NewDecoder(scheme)},
+		Handler: &defaulterForType{object: obj, defaulter: defaulter, decoder: newDecoder(scheme)},
 	}
 }
 
 type defaulterForType struct {
 	defaulter CustomDefaulter
 	object    runtime.Object
-	decoder   Decoder
+	// decoder is the decoder of the package rather than a Decoder, since
+	// the object is converted back with it.
+	decoder *decoder
 }
 
 // Handle handles admission requests.
@@ -85,6 +87,13 @@ func (h *defaulterForType) Handle(ctx context.Context, req Request) Response {
 		return Denied(err.Error())
 	}
 
+	// Convert the object back into the version of the request, in case the
+	// decoder converted it, so the patch applies to what was sent.
+	obj, err := h.decoder.encodeAs(req.Object, obj)
+	if err != nil {
+		return Errored(http.StatusInternalServerError, err)
+	}
+
 	// Create the patch
 	marshalled, err := json.Marshal(obj)
 	if err != nil {
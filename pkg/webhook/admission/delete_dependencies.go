@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// defaultMaxListedDependents is the default number of dependents named in
+// the message of denied deletes.
+const defaultMaxListedDependents = 10
+
+// DependentsFunc returns the objects that depend on obj, and prevent it
+// from being deleted, reading them from reader.
+type DependentsFunc func(ctx context.Context, reader client.Reader, obj client.Object) ([]client.Object, error)
+
+// DeleteDependencies configures a webhook returned by
+// WithDeleteDependencies.
+type DeleteDependencies struct {
+	// Dependents returns the dependents of the objects being deleted. It is
+	// required.
+	Dependents DependentsFunc
+
+	// Cache is the reader Dependents reads from, typically the cache of the
+	// manager. It is required.
+	Cache client.Reader
+
+	// APIReader, if set, is used to confirm the dependents found in the
+	// Cache before denying a delete, by calling Dependents again with it.
+	// This avoids denials caused by a stale cache that still holds
+	// dependents which have been deleted, e.g. when dependents and the
+	// object they depend on are deleted in quick succession. Typically the
+	// APIReader of the manager.
+	APIReader client.Reader
+
+	// DryRunWarnOnly allows dry-run deletes that would be denied, with a
+	// warning naming the dependents, so that tools previewing deletions,
+	// such as kubectl delete --dry-run=server, report all the blocked
+	// deletions instead of failing at the first.
+	DryRunWarnOnly bool
+
+	// MaxListed is the maximum number of dependents named in the message of
+	// denied deletes. Defaults to 10.
+	MaxListed int
+}
+
+// WithDeleteDependencies creates a validating Webhook that denies the
+// deletion of objects of the type of obj while they have dependents, e.g.
+// a storage class still used by volumes. Operations other than DELETE are
+// allowed, so the webhook is meant to be registered for DELETE operations
+// only.
+//
+// Dependents are read from a cache, which may lag behind the API server. A
+// dependent created right before the delete may be missed; dependents that
+// were deleted but are still cached are confirmed with the APIReader, if
+// set, before denying the delete. Use the APIReader as Cache to always read
+// live objects, at the expense of load on the API server.
+func WithDeleteDependencies(scheme *runtime.Scheme, obj client.Object, dependencies DeleteDependencies) *Webhook {
+	if dependencies.MaxListed <= 0 {
+		dependencies.MaxListed = defaultMaxListedDependents
+	}
+	return &Webhook{
+		Handler: &deleteDependenciesHandler{
+			scheme:       scheme,
+			object:       obj,
+			decoder:      NewDecoder(scheme),
+			dependencies: dependencies,
+		},
+	}
+}
+
+type deleteDependenciesHandler struct {
+	scheme       *runtime.Scheme
+	object       client.Object
+	decoder      Decoder
+	dependencies DeleteDependencies
+}
+
+// Handle implements Handler.
+func (h *deleteDependenciesHandler) Handle(ctx context.Context, req Request) Response {
+	if req.Operation != admissionv1.Delete {
+		return Allowed("")
+	}
+	ctx = NewContextWithRequest(ctx, req)
+
+	// OldObject contains the object being deleted.
+	obj := h.object.DeepCopyObject().(client.Object)
+	if err := h.decoder.DecodeRaw(req.OldObject, obj); err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+
+	dependents, err := h.dependencies.Dependents(ctx, h.dependencies.Cache, obj)
+	if err != nil {
+		return Errored(http.StatusInternalServerError, fmt.Errorf("failed to get dependents: %w", err))
+	}
+	if len(dependents) > 0 && h.dependencies.APIReader != nil {
+		dependents, err = h.dependencies.Dependents(ctx, h.dependencies.APIReader, obj)
+		if err != nil {
+			return Errored(http.StatusInternalServerError, fmt.Errorf("failed to confirm dependents: %w", err))
+		}
+	}
+	if len(dependents) == 0 {
+		return Allowed("")
+	}
+
+	message := h.message(obj, dependents)
+	if h.dependencies.DryRunWarnOnly && req.DryRun != nil && *req.DryRun {
+		return Allowed("").WithWarnings(message)
+	}
+	return Denied(message)
+}
+
+func (h *deleteDependenciesHandler) message(obj client.Object, dependents []client.Object) string {
+	listed := dependents
+	if len(listed) > h.dependencies.MaxListed {
+		listed = listed[:h.dependencies.MaxListed]
+	}
+	names := make([]string, 0, len(listed)+1)
+	for _, dependent := range listed {
+		names = append(names, h.describe(dependent))
+	}
+	if len(dependents) > len(listed) {
+		names = append(names, fmt.Sprintf("and %d more", len(dependents)-len(listed)))
+	}
+	return fmt.Sprintf("%s can't be deleted while it has dependents: %s", h.describe(obj), strings.Join(names, ", "))
+}
+
+// describe returns the kind and name of obj.
+func (h *deleteDependenciesHandler) describe(obj client.Object) string {
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := apiutil.GVKForObject(obj, h.scheme); err == nil {
+		kind = gvk.Kind
+	}
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", kind, obj.GetName())
+	}
+	return fmt.Sprintf("%s %s", kind, client.ObjectKeyFromObject(obj))
+}
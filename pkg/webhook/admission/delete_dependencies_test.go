@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WithDeleteDependencies", func() {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"}}
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"config": "config"}}}
+	}
+	podsUsingConfig := func(ctx context.Context, reader client.Reader, obj client.Object) ([]client.Object, error) {
+		pods := &corev1.PodList{}
+		if err := reader.List(ctx, pods, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{"config": obj.GetName()}); err != nil {
+			return nil, err
+		}
+		var dependents []client.Object
+		for i := range pods.Items {
+			dependents = append(dependents, &pods.Items[i])
+		}
+		return dependents, nil
+	}
+	deleteRequest := func(dryRun bool) Request {
+		raw, err := json.Marshal(configMap)
+		Expect(err).NotTo(HaveOccurred())
+		return Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Delete,
+			OldObject: runtime.RawExtension{Raw: raw},
+			DryRun:    ptr.To(dryRun),
+		}}
+	}
+
+	It("should deny deletes of objects with dependents", func() {
+		cache := fake.NewClientBuilder().WithObjects(pod("a"), pod("b"), pod("c")).Build()
+		webhook := WithDeleteDependencies(scheme.Scheme, &corev1.ConfigMap{}, DeleteDependencies{
+			Dependents: podsUsingConfig,
+			Cache:      cache,
+			MaxListed:  2,
+		})
+		resp := webhook.Handle(context.Background(), deleteRequest(false))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(Equal("ConfigMap default/config can't be deleted while it has dependents: Pod default/a, Pod default/b, and 1 more"))
+	})
+
+	It("should allow deletes of objects without dependents", func() {
+		webhook := WithDeleteDependencies(scheme.Scheme, &corev1.ConfigMap{}, DeleteDependencies{
+			Dependents: podsUsingConfig,
+			Cache:      fake.NewClientBuilder().Build(),
+		})
+		Expect(webhook.Handle(context.Background(), deleteRequest(false)).Allowed).To(BeTrue())
+	})
+
+	It("should confirm the dependents found in the cache with the APIReader", func() {
+		webhook := WithDeleteDependencies(scheme.Scheme, &corev1.ConfigMap{}, DeleteDependencies{
+			Dependents: podsUsingConfig,
+			Cache:      fake.NewClientBuilder().WithObjects(pod("stale")).Build(),
+			APIReader:  fake.NewClientBuilder().Build(),
+		})
+		Expect(webhook.Handle(context.Background(), deleteRequest(false)).Allowed).To(BeTrue())
+	})
+
+	It("should only warn about dependents on dry-run deletes if configured to", func() {
+		webhook := WithDeleteDependencies(scheme.Scheme, &corev1.ConfigMap{}, DeleteDependencies{
+			Dependents:     podsUsingConfig,
+			Cache:          fake.NewClientBuilder().WithObjects(pod("a")).Build(),
+			DryRunWarnOnly: true,
+		})
+		resp := webhook.Handle(context.Background(), deleteRequest(true))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(ConsistOf(ContainSubstring("Pod default/a")))
+		Expect(webhook.Handle(context.Background(), deleteRequest(false)).Allowed).To(BeFalse())
+	})
+
+	It("should allow operations other than deletes", func() {
+		webhook := WithDeleteDependencies(scheme.Scheme, &corev1.ConfigMap{}, DeleteDependencies{
+			Dependents: podsUsingConfig,
+			Cache:      fake.NewClientBuilder().WithObjects(pod("a")).Build(),
+		})
+		Expect(webhook.Handle(context.Background(), Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create}}).Allowed).To(BeTrue())
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DecodeObjects decodes the object and the old object of req into obj and
+// oldObj. Either is left untouched if req doesn't carry it, e.g. the old
+// object of a CREATE or the object of a DELETE.
+func DecodeObjects(decoder Decoder, req Request, obj, oldObj runtime.Object) error {
+	if len(req.Object.Raw) == 0 && len(req.OldObject.Raw) == 0 {
+		return fmt.Errorf("there is no content to decode")
+	}
+	if len(req.Object.Raw) > 0 {
+		if err := decoder.DecodeRaw(req.Object, obj); err != nil {
+			return err
+		}
+	}
+	if len(req.OldObject.Raw) > 0 {
+		if err := decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ignoredPaths are the fields maintained by the API server on every write,
+// which Diff doesn't report.
+var ignoredPaths = []string{"metadata.managedFields", "metadata.resourceVersion"}
+
+// Changes are the paths of the fields that differ between two objects, in
+// the format of field.Path, e.g. "spec.template.spec.containers[0].image".
+// Map keys are reported as fields, e.g.
+// "metadata.labels.app.kubernetes.io/name".
+type Changes []string
+
+// Has reports whether the field at path, or any field below it, changed.
+func (c Changes) Has(path string) bool {
+	for _, changed := range c {
+		if changed == path || strings.HasPrefix(changed, path+".") || strings.HasPrefix(changed, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff returns the paths of the fields that differ between oldObj and obj,
+// sorted. The fields of a list are compared element by element if both
+// lists have the same length, and the list is reported as a whole
+// otherwise. The resourceVersion and managedFields of the objects are
+// ignored.
+func Diff(oldObj, obj runtime.Object) (Changes, error) {
+	oldContent, err := toUnstructured(oldObj)
+	if err != nil {
+		return nil, err
+	}
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes Changes
+	diff(nil, oldContent, content, &changes)
+	sort.Strings(changes)
+	return changes, nil
+}
+
+func toUnstructured(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func diff(path *field.Path, oldValue, value interface{}, changes *Changes) {
+	if path != nil {
+		for _, ignored := range ignoredPaths {
+			if path.String() == ignored {
+				return
+			}
+		}
+	}
+
+	switch old := oldValue.(type) {
+	case map[string]interface{}:
+		if current, ok := value.(map[string]interface{}); ok {
+			for key, oldField := range old {
+				diff(child(path, key), oldField, current[key], changes)
+			}
+			for key, newField := range current {
+				if _, ok := old[key]; !ok {
+					diff(child(path, key), nil, newField, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if current, ok := value.([]interface{}); ok && len(current) == len(old) {
+			for i := range old {
+				diff(path.Index(i), old[i], current[i], changes)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, value) {
+		*changes = append(*changes, path.String())
+	}
+}
+
+func child(path *field.Path, name string) *field.Path {
+	if path == nil {
+		return field.NewPath(name)
+	}
+	return path.Child(name)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("DecodeObjects", func() {
+	decoder := NewDecoder(scheme.Scheme)
+	pod := func(image string) runtime.RawExtension {
+		return runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo"}, "spec": {"containers": [{"name": "bar", "image": "` + image + `"}]}}`)}
+	}
+
+	It("should decode the object and the old object of an update", func() {
+		req := Request{AdmissionRequest: admissionv1.AdmissionRequest{Object: pod("bar:v2"), OldObject: pod("bar:v1")}}
+		obj, oldObj := &corev1.Pod{}, &corev1.Pod{}
+		Expect(DecodeObjects(decoder, req, obj, oldObj)).To(Succeed())
+		Expect(obj.Spec.Containers[0].Image).To(Equal("bar:v2"))
+		Expect(oldObj.Spec.Containers[0].Image).To(Equal("bar:v1"))
+	})
+
+	It("should leave the objects missing from the request untouched", func() {
+		req := Request{AdmissionRequest: admissionv1.AdmissionRequest{Object: pod("bar:v2")}}
+		obj, oldObj := &corev1.Pod{}, &corev1.Pod{}
+		Expect(DecodeObjects(decoder, req, obj, oldObj)).To(Succeed())
+		Expect(obj.Name).To(Equal("foo"))
+		Expect(oldObj.Name).To(BeEmpty())
+	})
+
+	It("should fail if the request carries no object", func() {
+		Expect(DecodeObjects(decoder, Request{}, &corev1.Pod{}, &corev1.Pod{})).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Diff", func() {
+	deployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "1", Labels: map[string]string{"app": "foo"}},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "bar", Image: "bar:v1"}},
+				}},
+			},
+		}
+	}
+
+	It("should report the paths of the changed fields", func() {
+		oldObj, obj := deployment(), deployment()
+		obj.ResourceVersion = "2"
+		obj.Labels["app.kubernetes.io/name"] = "foo"
+		obj.Spec.Replicas = ptr.To[int32](2)
+		obj.Spec.Template.Spec.Containers[0].Image = "bar:v2"
+
+		changes, err := Diff(oldObj, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal(Changes{
+			"metadata.labels.app.kubernetes.io/name",
+			"spec.replicas",
+			"spec.template.spec.containers[0].image",
+		}))
+		Expect(changes.Has("spec")).To(BeTrue())
+		Expect(changes.Has("spec.template.spec.containers")).To(BeTrue())
+		Expect(changes.Has("spec.template.metadata")).To(BeFalse())
+		Expect(changes.Has("spec.replica")).To(BeFalse())
+	})
+
+	It("should report lists whose length changed as a whole", func() {
+		oldObj, obj := deployment(), deployment()
+		obj.Spec.Template.Spec.Containers = append(obj.Spec.Template.Spec.Containers, corev1.Container{Name: "baz"})
+
+		changes, err := Diff(oldObj, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal(Changes{"spec.template.spec.containers"}))
+	})
+
+	It("should diff unstructured objects", func() {
+		oldObj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"size": int64(1)}}}
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"size": int64(2)}}}
+
+		changes, err := Diff(oldObj, obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal(Changes{"spec.size"}))
+	})
+})
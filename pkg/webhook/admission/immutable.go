@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// pathSegment matches the segments of a path in the format of Changes:
+// field names and list indexes.
+var pathSegment = regexp.MustCompile(`[^.\[\]]+|\[[^\]]*\]`)
+
+// ValidateImmutableFields returns an error for each field at one of paths
+// that differs between oldObj and obj. Paths are in the format of Changes,
+// and may use [*] to match any element of a list, e.g.
+// "spec.volumes[*].name". A field is considered changed if it, a field
+// below it or a field above it changed; in particular all the fields of a
+// list whose length changed are.
+//
+// It is meant to be called from CustomValidator.ValidateUpdate, wrapping
+// the errors with apierrors.NewInvalid.
+func ValidateImmutableFields(oldObj, obj runtime.Object, paths ...string) (field.ErrorList, error) {
+	changes, err := Diff(oldObj, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs field.ErrorList
+	for _, changed := range changes {
+		changedSegments := pathSegment.FindAllString(changed, -1)
+		for _, path := range paths {
+			if matchSegments(pathSegment.FindAllString(path, -1), changedSegments) {
+				detail := "field is immutable"
+				if path != changed {
+					detail = fmt.Sprintf("%s (immutable: %s)", detail, path)
+				}
+				errs = append(errs, field.Forbidden(field.NewPath(changed), detail))
+				break
+			}
+		}
+	}
+	return errs, nil
+}
+
+// matchSegments reports whether one of path and changed is a prefix of the
+// other, an index of path being [*] matching any index of changed.
+func matchSegments(path, changed []string) bool {
+	for i := 0; i < len(path) && i < len(changed); i++ {
+		if path[i] == changed[i] {
+			continue
+		}
+		if path[i] == "[*]" && len(changed[i]) > 0 && changed[i][0] == '[' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// WithImmutableFields creates a validating Webhook that denies the updates
+// changing any of the fields at paths, see ValidateImmutableFields.
+// Objects are decoded as unstructured, so it serves any kind, including
+// custom resources without Go types. Operations other than UPDATE are
+// allowed.
+func WithImmutableFields(paths ...string) *Webhook {
+	return &Webhook{
+		Handler: &immutableFieldsHandler{paths: paths, decoder: NewDecoder(runtime.NewScheme())},
+	}
+}
+
+type immutableFieldsHandler struct {
+	paths   []string
+	decoder Decoder
+}
+
+// Handle implements Handler.
+func (h *immutableFieldsHandler) Handle(_ context.Context, req Request) Response {
+	if req.Operation != admissionv1.Update {
+		return Allowed("")
+	}
+
+	obj, oldObj := &unstructured.Unstructured{}, &unstructured.Unstructured{}
+	if err := h.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	if err := h.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+
+	errs, err := ValidateImmutableFields(oldObj, obj, h.paths...)
+	if err != nil {
+		return Errored(http.StatusInternalServerError, err)
+	}
+	if len(errs) == 0 {
+		return Allowed("")
+	}
+	gk := schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}
+	return validationResponseFromStatus(false, apierrors.NewInvalid(gk, req.Name, errs).Status())
+}
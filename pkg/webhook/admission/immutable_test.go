@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Immutable fields", func() {
+	pod := func(image, volume string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node",
+				Containers: []corev1.Container{{Name: "bar", Image: image}},
+				Volumes:    []corev1.Volume{{Name: volume}},
+			},
+		}
+	}
+
+	It("should report the immutable fields that changed", func() {
+		errs, err := ValidateImmutableFields(pod("bar:v1", "data"), pod("bar:v2", "cache"), "spec.nodeName", "spec.volumes[*].name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.volumes[0].name"))
+		Expect(errs[0].Error()).To(ContainSubstring("field is immutable (immutable: spec.volumes[*].name)"))
+	})
+
+	It("should report immutable fields below or above a changed field", func() {
+		oldObj, obj := pod("bar:v1", "data"), pod("bar:v1", "data")
+		obj.Spec.Volumes = append(obj.Spec.Volumes, corev1.Volume{Name: "cache"})
+		errs, err := ValidateImmutableFields(oldObj, obj, "spec.volumes[*].name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.volumes"))
+
+		errs, err = ValidateImmutableFields(pod("bar:v1", "data"), pod("bar:v2", "data"), "spec.containers")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.containers[0].image"))
+	})
+
+	Describe("WithImmutableFields", func() {
+		handler := WithImmutableFields("spec.nodeName")
+		request := func(operation admissionv1.Operation, oldObj, obj *corev1.Pod) Request {
+			return Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+				Name:      "foo",
+				Object:    runtime.RawExtension{Object: obj},
+				OldObject: runtime.RawExtension{Object: oldObj},
+			}}
+		}
+		encode := func(req Request) Request {
+			for _, raw := range []*runtime.RawExtension{&req.Object, &req.OldObject} {
+				if raw.Object != nil {
+					data, err := json.Marshal(raw.Object)
+					Expect(err).NotTo(HaveOccurred())
+					raw.Raw = data
+				}
+			}
+			return req
+		}
+
+		It("should deny updates changing immutable fields", func() {
+			oldObj, obj := pod("bar:v1", "data"), pod("bar:v1", "data")
+			obj.Spec.NodeName = "other"
+			resp := handler.Handle(context.Background(), encode(request(admissionv1.Update, oldObj, obj)))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(Equal(int32(http.StatusUnprocessableEntity)))
+			Expect(resp.Result.Message).To(ContainSubstring(`Pod "foo" is invalid: spec.nodeName: Forbidden: field is immutable`))
+		})
+
+		It("should allow updates changing other fields and other operations", func() {
+			resp := handler.Handle(context.Background(), encode(request(admissionv1.Update, pod("bar:v1", "data"), pod("bar:v2", "data"))))
+			Expect(resp.Allowed).To(BeTrue())
+
+			resp = handler.Handle(context.Background(), encode(request(admissionv1.Create, nil, pod("bar:v1", "data"))))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReplayProtectionOptions configures a handler returned by WithReplayProtection.
+type ReplayProtectionOptions struct {
+	// TTL is how long the response to a request is kept to answer its
+	// retries. Defaults to one minute.
+	TTL time.Duration
+
+	// MaxEntries bounds the number of responses kept. The oldest responses
+	// are dropped first. Defaults to 10000.
+	MaxEntries int
+
+	// Key returns the idempotency key of a request: requests with the same
+	// key are handled once. Requests whose key is empty are always handled.
+	// Defaults to the UID of the request, which the API server keeps when it
+	// retries a request.
+	Key func(req Request) string
+}
+
+// WithReplayProtection returns a handler that deduplicates retried admission
+// requests, so that handlers calling external systems don't cause their side
+// effects twice. The response to a request is kept for opts.TTL and returned
+// for the requests with the same key; a request received while another one
+// with the same key is being handled waits for its response. Responses
+// asking clients to retry, i.e. with a 429 or 5xx code, are not kept.
+func WithReplayProtection(h Handler, opts ReplayProtectionOptions) Handler {
+	if opts.TTL <= 0 {
+		opts.TTL = time.Minute
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	if opts.Key == nil {
+		opts.Key = func(req Request) string { return string(req.UID) }
+	}
+	return &replayHandler{
+		handler: h,
+		opts:    opts,
+		now:     time.Now,
+		entries: map[string]*replayEntry{},
+	}
+}
+
+type replayHandler struct {
+	handler Handler
+	opts    ReplayProtectionOptions
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*replayEntry
+	// order holds the keys of entries, oldest first.
+	order []string
+}
+
+type replayEntry struct {
+	// done is closed once response is set.
+	done     chan struct{}
+	response Response
+	// expires is set once the response is kept.
+	expires time.Time
+}
+
+// Handle implements Handler.
+func (r *replayHandler) Handle(ctx context.Context, req Request) Response {
+	key := r.opts.Key(req)
+	if key == "" {
+		return r.handler.Handle(ctx, req)
+	}
+
+	r.mu.Lock()
+	r.pruneLocked()
+	if entry, ok := r.entries[key]; ok {
+		r.mu.Unlock()
+		select {
+		case <-entry.done:
+			if !entry.expires.IsZero() {
+				return entry.response
+			}
+			// The response was not kept, handle the retry.
+			return r.Handle(ctx, req)
+		case <-ctx.Done():
+			return Errored(http.StatusServiceUnavailable, ctx.Err())
+		}
+	}
+	entry := &replayEntry{done: make(chan struct{})}
+	r.entries[key] = entry
+	r.order = append(r.order, key)
+	r.mu.Unlock()
+
+	response := r.handler.Handle(ctx, req)
+
+	r.mu.Lock()
+	entry.response = response
+	if retryable(response) {
+		r.deleteLocked(key, entry)
+	} else {
+		entry.expires = r.now().Add(r.opts.TTL)
+	}
+	close(entry.done)
+	r.mu.Unlock()
+	return response
+}
+
+// pruneLocked drops the expired entries, and the oldest entries beyond
+// MaxEntries.
+func (r *replayHandler) pruneLocked() {
+	now := r.now()
+	for len(r.order) > 0 {
+		key := r.order[0]
+		entry, ok := r.entries[key]
+		switch {
+		case !ok:
+		case len(r.entries) >= r.opts.MaxEntries:
+			delete(r.entries, key)
+		case entry.expires.IsZero(), now.Before(entry.expires):
+			// Entries are kept in the order they were added, so the
+			// next ones expire later. Entries still being handled
+			// expire after this one.
+			return
+		default:
+			delete(r.entries, key)
+		}
+		r.order = r.order[1:]
+	}
+}
+
+// deleteLocked drops the entry of key if it is entry.
+func (r *replayHandler) deleteLocked(key string, entry *replayEntry) {
+	if r.entries[key] == entry {
+		delete(r.entries, key)
+	}
+}
+
+// retryable returns whether response asks clients to retry the request.
+func retryable(response Response) bool {
+	if response.Result == nil {
+		return false
+	}
+	code := response.Result.Code
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
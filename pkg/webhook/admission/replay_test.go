@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("WithReplayProtection", func() {
+	var handled atomic.Int32
+	var response func() Response
+	handler := HandlerFunc(func(ctx context.Context, req Request) Response {
+		handled.Add(1)
+		return response()
+	})
+	request := func(uid string) Request {
+		return Request{AdmissionRequest: admissionv1.AdmissionRequest{UID: types.UID("uid-" + uid)}}
+	}
+
+	BeforeEach(func() {
+		handled.Store(0)
+		response = func() Response { return Denied("denied") }
+	})
+
+	It("should handle each request once while its response is kept", func() {
+		h := WithReplayProtection(handler, ReplayProtectionOptions{})
+		Expect(h.Handle(context.Background(), request("a")).Result.Message).To(Equal("denied"))
+		Expect(h.Handle(context.Background(), request("a")).Result.Message).To(Equal("denied"))
+		Expect(handled.Load()).To(BeEquivalentTo(1))
+
+		Expect(h.Handle(context.Background(), request("b")).Allowed).To(BeFalse())
+		Expect(handled.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should handle requests again once their response expired", func() {
+		h := WithReplayProtection(handler, ReplayProtectionOptions{TTL: time.Minute}).(*replayHandler)
+		now := time.Now()
+		h.now = func() time.Time { return now }
+		h.Handle(context.Background(), request("a"))
+
+		now = now.Add(2 * time.Minute)
+		h.Handle(context.Background(), request("a"))
+		Expect(handled.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should drop the oldest responses beyond MaxEntries", func() {
+		h := WithReplayProtection(handler, ReplayProtectionOptions{MaxEntries: 1})
+		h.Handle(context.Background(), request("a"))
+		h.Handle(context.Background(), request("b"))
+		h.Handle(context.Background(), request("a"))
+		Expect(handled.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should not keep responses asking clients to retry", func() {
+		response = func() Response { return Errored(http.StatusInternalServerError, errors.New("unavailable")) }
+		h := WithReplayProtection(handler, ReplayProtectionOptions{})
+		h.Handle(context.Background(), request("a"))
+		h.Handle(context.Background(), request("a"))
+		Expect(handled.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should make concurrent duplicates wait for the response", func() {
+		release := make(chan struct{})
+		response = func() Response {
+			<-release
+			return Allowed("")
+		}
+		h := WithReplayProtection(handler, ReplayProtectionOptions{})
+		responses := make(chan Response, 2)
+		for range 2 {
+			go func() { responses <- h.Handle(context.Background(), request("a")) }()
+		}
+		Eventually(handled.Load).Should(BeEquivalentTo(1))
+		close(release)
+		Expect((<-responses).Allowed).To(BeTrue())
+		Expect((<-responses).Allowed).To(BeTrue())
+		Expect(handled.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("should always handle requests without a key", func() {
+		h := WithReplayProtection(handler, ReplayProtectionOptions{})
+		h.Handle(context.Background(), Request{})
+		h.Handle(context.Background(), Request{})
+		Expect(handled.Load()).To(BeEquivalentTo(2))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// RequireClientCert returns a Filter that rejects requests that did not
+// present a client certificate verified against the server's ClientCAName.
+// If commonNames is not empty, the certificate's subject common name must
+// also be one of them.
+//
+// It is meant to be used together with Options.ClientCertOptional, to
+// require client certificates on a subset of the server's paths.
+func RequireClientCert(commonNames ...string) Filter {
+	allowed := make(map[string]struct{}, len(commonNames))
+	for _, cn := range commonNames {
+		allowed[cn] = struct{}{}
+	}
+
+	return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+				log.V(4).Info("Request without verified client certificate rejected")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if len(allowed) > 0 {
+				cert := req.TLS.VerifiedChains[0][0]
+				if _, ok := allowed[cert.Subject.CommonName]; !ok {
+					log.V(4).Info("Request with unexpected client certificate rejected", "commonName", cert.Subject.CommonName)
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+			}
+
+			handler.ServeHTTP(w, req)
+		}), nil
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var _ = Describe("RequireClientCert", func() {
+	serve := func(filter webhook.Filter, state *tls.ConnectionState) int {
+		handler, err := filter(logr.Discard(), &testHandler{})
+		Expect(err).NotTo(HaveOccurred())
+
+		req := httptest.NewRequest(http.MethodGet, "/somepath", nil)
+		req.TLS = state
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	verified := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+
+	It("should reject requests without a verified client certificate", func() {
+		Expect(serve(webhook.RequireClientCert(), nil)).To(Equal(http.StatusUnauthorized))
+		Expect(serve(webhook.RequireClientCert(), &tls.ConnectionState{})).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should accept requests with a verified client certificate", func() {
+		Expect(serve(webhook.RequireClientCert(), verified("anyone"))).To(Equal(http.StatusOK))
+	})
+
+	It("should only accept the configured common names", func() {
+		Expect(serve(webhook.RequireClientCert("admin"), verified("admin"))).To(Equal(http.StatusOK))
+		Expect(serve(webhook.RequireClientCert("admin"), verified("someone"))).To(Equal(http.StatusForbidden))
+	})
+})
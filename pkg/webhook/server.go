@@ -29,9 +29,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
+
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
 )
 
@@ -67,6 +70,18 @@ type Server interface {
 	WebhookMux() *http.ServeMux
 }
 
+// CertificateExpiryCheckerProvider is implemented by the Servers that
+// monitor the expiry of the certificate they serve, like DefaultServer.
+type CertificateExpiryCheckerProvider interface {
+	// CertificateExpiryChecker returns an healthz.Checker which fails when
+	// the certificate served by the server expires within the
+	// CertificateExpiryThreshold of its options, or before the server has
+	// been started.
+	CertificateExpiryChecker() healthz.Checker
+}
+
+var _ CertificateExpiryCheckerProvider = &DefaultServer{}
+
 // Options are all the available options for a webhook.Server
 type Options struct {
 	// Host is the address that the server will listen on.
@@ -95,14 +110,54 @@ type Options struct {
 	// Defaults to "", which means server does not verify client's certificate.
 	ClientCAName string
 
+	// ClientCertOptional makes client certificates optional when ClientCAName is set:
+	// certificates are verified if presented, but connections without one are accepted.
+	// Use RequireClientCert in PathFilters to require a verified client certificate
+	// on specific paths only.
+	ClientCertOptional bool
+
 	// TLSOpts is used to allow configuring the TLS config used for the server.
 	// This also allows providing a certificate via GetCertificate.
 	TLSOpts []func(*tls.Config)
 
+	// CertificateSource provides the serving certificate, e.g. from a SPIFFE
+	// Workload API or a cloud certificate issuer, instead of reading it from
+	// CertDir. The server starts the source and stops it on shutdown.
+	//
+	// Note: This option is only used when TLSOpts does not set GetCertificate.
+	CertificateSource certwatcher.Source
+
+	// CertificateExpiryThreshold is how long before the served certificate
+	// expires the checker returned by CertificateExpiryChecker starts
+	// failing. Defaults to zero, which only fails once it has expired. The
+	// expiry time of the certificate is exported in the
+	// certwatcher_certificate_expiration_timestamp_seconds metric with the
+	// name "webhook".
+	CertificateExpiryThreshold time.Duration
+
 	// WebhookMux is the multiplexer that handles different webhooks.
 	WebhookMux *http.ServeMux
+
+	// PathFilters maps a path to filters that are added around the handler
+	// registered on that path, e.g. to authenticate and authorize requests
+	// to sensitive non-admission handlers. Filters are applied in order, so
+	// the first filter is the outermost one.
+	PathFilters map[string][]Filter
+
+	// AdmissionReplayProtection, if set, makes the admission webhooks
+	// registered on the server deduplicate the requests the API server
+	// retries, so that webhooks calling external systems don't cause their
+	// side effects twice. See admission.WithReplayProtection.
+	AdmissionReplayProtection *admission.ReplayProtectionOptions
 }
 
+// Filter is a func that is added around a handler registered on the webhook server.
+// It has the same signature as the metrics server Filter, so filters like
+// [sigs.k8s.io/controller-runtime/pkg/metrics/filters.WithAuthenticationAndAuthorization]
+// can be converted and reused to delegate authentication and authorization to the
+// kube-apiserver.
+type Filter func(log logr.Logger, handler http.Handler) (http.Handler, error)
+
 // NewServer constructs a new webhook.Server from the provided options.
 func NewServer(o Options) Server {
 	return &DefaultServer{
@@ -127,6 +182,10 @@ type DefaultServer struct {
 	// mu protects access to the webhook map & setFields for Start, Register, etc
 	mu sync.Mutex
 
+	// expiryMonitor monitors the expiry of the served certificate once the
+	// server is started.
+	expiryMonitor *certwatcher.ExpiryMonitor
+
 	webhookMux *http.ServeMux
 }
 
@@ -167,7 +226,8 @@ func (*DefaultServer) NeedLeaderElection() bool {
 }
 
 // Register marks the given webhook as being served at the given path.
-// It panics if two hooks are registered on the same path.
+// It panics if two hooks are registered on the same path, or if one
+// of the filters configured for the path fails.
 func (s *DefaultServer) Register(path string, hook http.Handler) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -176,10 +236,33 @@ func (s *DefaultServer) Register(path string, hook http.Handler) {
 	if _, found := s.webhooks[path]; found {
 		panic(fmt.Errorf("can't register duplicate path: %v", path))
 	}
+	regLog := log.WithValues("path", path)
+
+	served := hook
+	if wh, ok := hook.(*admission.Webhook); ok && s.Options.AdmissionReplayProtection != nil && wh.Handler != nil {
+		// The protected handler is served by a copy of the webhook, so that
+		// the webhook of the caller is left unchanged.
+		served = &admission.Webhook{
+			Handler:         admission.WithReplayProtection(wh.Handler, *s.Options.AdmissionReplayProtection),
+			RecoverPanic:    wh.RecoverPanic,
+			WithContextFunc: wh.WithContextFunc,
+			LogConstructor:  wh.LogConstructor,
+		}
+	}
+
+	filtered := served
+	filters := s.Options.PathFilters[path]
+	for i := len(filters) - 1; i >= 0; i-- {
+		var err error
+		filtered, err = filters[i](regLog, filtered)
+		if err != nil {
+			panic(fmt.Errorf("failed to add filter to path %v: %w", path, err))
+		}
+	}
+
 	s.webhooks[path] = hook
-	s.webhookMux.Handle(path, metrics.InstrumentedHook(path, hook))
+	s.webhookMux.Handle(path, metrics.InstrumentedHook(path, filtered))
 
-	regLog := log.WithValues("path", path)
 	regLog.Info("Registering webhook")
 }
 
@@ -199,23 +282,29 @@ func (s *DefaultServer) Start(ctx context.Context) error {
 	}
 
 	if cfg.GetCertificate == nil {
-		certPath := filepath.Join(s.Options.CertDir, s.Options.CertName)
-		keyPath := filepath.Join(s.Options.CertDir, s.Options.KeyName)
-
-		// Create the certificate watcher and
-		// set the config's GetCertificate on the TLSConfig
-		certWatcher, err := certwatcher.New(certPath, keyPath)
-		if err != nil {
-			return err
+		certSource := s.Options.CertificateSource
+		if certSource == nil {
+			certPath := filepath.Join(s.Options.CertDir, s.Options.CertName)
+			keyPath := filepath.Join(s.Options.CertDir, s.Options.KeyName)
+
+			// Create the certificate watcher and
+			// set the config's GetCertificate on the TLSConfig
+			certWatcher, err := certwatcher.New(certPath, keyPath)
+			if err != nil {
+				return err
+			}
+			certSource = certWatcher
 		}
-		cfg.GetCertificate = certWatcher.GetCertificate
+		cfg.GetCertificate = certSource.GetCertificate
 
 		go func() {
-			if err := certWatcher.Start(ctx); err != nil {
+			if err := certSource.Start(ctx); err != nil {
 				log.Error(err, "certificate watcher error")
 			}
 		}()
 	}
+	expiryMonitor := certwatcher.NewExpiryMonitor("webhook", cfg.GetCertificate, s.Options.CertificateExpiryThreshold)
+	cfg.GetCertificate = expiryMonitor.GetCertificate
 
 	// Load CA to verify client certificate, if configured.
 	if s.Options.ClientCAName != "" {
@@ -232,6 +321,9 @@ func (s *DefaultServer) Start(ctx context.Context) error {
 
 		cfg.ClientCAs = certPool
 		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		if s.Options.ClientCertOptional {
+			cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
 	}
 
 	listener, err := tls.Listen("tcp", net.JoinHostPort(s.Options.Host, strconv.Itoa(s.Options.Port)), cfg)
@@ -259,6 +351,7 @@ func (s *DefaultServer) Start(ctx context.Context) error {
 
 	s.mu.Lock()
 	s.started = true
+	s.expiryMonitor = expiryMonitor
 	s.mu.Unlock()
 	if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
 		return err
@@ -296,6 +389,25 @@ func (s *DefaultServer) StartedChecker() healthz.Checker {
 	}
 }
 
+// CertificateExpiryChecker returns an healthz.Checker which fails when the
+// served certificate expires within the CertificateExpiryThreshold of the
+// options of the server, or before the server has been started.
+func (s *DefaultServer) CertificateExpiryChecker() healthz.Checker {
+	return func(req *http.Request) error {
+		s.mu.Lock()
+		expiryMonitor := s.expiryMonitor
+		s.mu.Unlock()
+
+		if expiryMonitor == nil {
+			return fmt.Errorf("webhook server has not been started yet")
+		}
+		if err := expiryMonitor.Check(req); err != nil {
+			return fmt.Errorf("webhook server certificate: %w", err)
+		}
+		return nil
+	}
+}
+
 // WebhookMux returns the servers WebhookMux
 func (s *DefaultServer) WebhookMux() *http.ServeMux {
 	return s.webhookMux
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"path"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -32,6 +33,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var _ = Describe("Webhook Server", func() {
@@ -140,6 +142,69 @@ var _ = Describe("Webhook Server", func() {
 		})
 	})
 
+	It("should check the expiry of the served certificate", func() {
+		Expect(server.(webhook.CertificateExpiryCheckerProvider).CertificateExpiryChecker()(nil)).To(MatchError(ContainSubstring("not been started")))
+
+		doneCh := startServer()
+		Expect(server.(webhook.CertificateExpiryCheckerProvider).CertificateExpiryChecker()(nil)).To(Succeed())
+
+		ctxCancel()
+		Eventually(doneCh, "4s").Should(BeClosed())
+
+		ctx, ctxCancel = context.WithCancel(context.Background())
+		server = webhook.NewServer(webhook.Options{
+			Host:                       servingOpts.LocalServingHost,
+			Port:                       servingOpts.LocalServingPort,
+			CertDir:                    servingOpts.LocalServingCertDir,
+			CertificateExpiryThreshold: 100 * 365 * 24 * time.Hour,
+		})
+		doneCh = startServer()
+		Expect(server.(webhook.CertificateExpiryCheckerProvider).CertificateExpiryChecker()(nil)).To(MatchError(ContainSubstring("webhook server certificate: certificate expires at")))
+
+		ctxCancel()
+		Eventually(doneCh, "4s").Should(BeClosed())
+	})
+
+	It("should not modify the webhooks it protects against replays", func() {
+		server = webhook.NewServer(webhook.Options{
+			AdmissionReplayProtection: &admission.ReplayProtectionOptions{},
+		})
+		handler := admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+			return admission.Allowed("")
+		})
+		wh := &admission.Webhook{Handler: handler}
+		server.Register("/replay-protected", wh)
+		Expect(reflect.ValueOf(wh.Handler).Pointer()).To(Equal(reflect.ValueOf(handler).Pointer()))
+	})
+
+	It("should apply the filters configured for a path", func() {
+		server = webhook.NewServer(webhook.Options{
+			Host:    servingOpts.LocalServingHost,
+			Port:    servingOpts.LocalServingPort,
+			CertDir: servingOpts.LocalServingCertDir,
+			PathFilters: map[string][]webhook.Filter{
+				"/protected": {webhook.RequireClientCert()},
+			},
+		})
+		server.Register("/protected", &testHandler{})
+		server.Register("/unprotected", &testHandler{})
+
+		doneCh := startServer()
+
+		resp, err := client.Get(fmt.Sprintf("https://%s/protected", testHostPort))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+
+		resp, err = client.Get(fmt.Sprintf("https://%s/unprotected", testHostPort))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		ctxCancel()
+		Eventually(doneCh, "4s").Should(BeClosed())
+	})
+
 	Context("when registering webhooks after starting", func() {
 		var (
 			doneCh <-chan struct{}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workflow expresses a reconcile as a sequence of ordered steps,
+// standardizing the "phase machine" many operators implement by hand.
+//
+// Each step reports its progress in a status condition of the object, named
+// after the step, and the overall progress in a ready condition. A step is
+// skipped once it completed for the current generation of the object, or
+// when its Done function reports it completed, so steps must be idempotent.
+// The reconcile is requeued after each completed step, so that its progress
+// is persisted before the next step runs, and polled while a step is in
+// progress:
+//
+//	wf := &workflow.Workflow[*v1.Database]{
+//		Client: r.Client,
+//		Steps: []workflow.Step[*v1.Database]{
+//			{Name: "VolumeProvisioned", Run: r.provisionVolume},
+//			{Name: "SchemaMigrated", Run: r.migrateSchema},
+//		},
+//	}
+//	return wf.Run(ctx, db)
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultReadyCondition is the default type of the condition reporting
+	// the overall progress of a workflow.
+	DefaultReadyCondition = "Ready"
+
+	// DefaultPollInterval is the default interval at which a step in
+	// progress is run again.
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Reasons of the conditions set by a Workflow.
+const (
+	ReasonCompleted  = "Completed"
+	ReasonInProgress = "InProgress"
+	ReasonFailed     = "Failed"
+	ReasonPending    = "Pending"
+)
+
+// Step is a step of a Workflow.
+type Step[T client.Object] struct {
+	// Name is the name of the step, and the type of the condition reporting
+	// its progress. It is required.
+	Name string
+
+	// Done, if set, reports whether the step already completed, e.g.
+	// because the resources it creates exist. Defaults to whether the
+	// condition of the step is true for the current generation of the
+	// object.
+	Done func(ctx context.Context, obj T) (bool, error)
+
+	// Run performs the step, and returns whether it completed. Steps that
+	// did not complete, e.g. because they wait for a resource to become
+	// ready, are run again after the poll interval of the workflow. It is
+	// required.
+	Run func(ctx context.Context, obj T) (bool, error)
+}
+
+// Workflow runs ordered steps against an object, reporting their progress
+// in the status conditions of the object.
+type Workflow[T client.Object] struct {
+	// Client is used to update the status of the object. It is required.
+	Client client.Client
+
+	// Steps are the steps of the workflow, in order.
+	Steps []Step[T]
+
+	// Conditions returns the conditions of obj. Defaults to the
+	// Status.Conditions field of the object, which must be a
+	// []metav1.Condition.
+	Conditions func(obj T) *[]metav1.Condition
+
+	// ReadyCondition is the type of the condition reporting the overall
+	// progress of the workflow. Defaults to DefaultReadyCondition.
+	ReadyCondition string
+
+	// PollInterval is the interval at which a step that did not complete is
+	// run again. Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Run runs the first step of the workflow that has not completed yet, and
+// updates the status of obj. It is meant to be returned from Reconcile.
+func (w *Workflow[T]) Run(ctx context.Context, obj T) (reconcile.Result, error) {
+	conditions, err := w.conditions(obj)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	original := append([]metav1.Condition(nil), *conditions...)
+
+	set := func(conditionType string, status metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: obj.GetGeneration(),
+		})
+	}
+	update := func(result reconcile.Result, runErr error) (reconcile.Result, error) {
+		if !reflect.DeepEqual(original, *conditions) {
+			if err := w.Client.Status().Update(ctx, obj); err != nil {
+				return reconcile.Result{}, errors.Join(runErr, err)
+			}
+		}
+		return result, runErr
+	}
+
+	for i, step := range w.Steps {
+		done, err := w.done(ctx, obj, step, *conditions)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to check whether step %s is done: %w", step.Name, err)
+		}
+		if done {
+			set(step.Name, metav1.ConditionTrue, ReasonCompleted, "")
+			continue
+		}
+
+		done, err = step.Run(ctx, obj)
+		switch {
+		case err != nil:
+			set(step.Name, metav1.ConditionFalse, ReasonFailed, err.Error())
+			set(w.readyCondition(), metav1.ConditionFalse, ReasonFailed, fmt.Sprintf("Step %s failed", step.Name))
+			return update(reconcile.Result{}, fmt.Errorf("step %s failed: %w", step.Name, err))
+		case !done:
+			set(step.Name, metav1.ConditionFalse, ReasonInProgress, "")
+			set(w.readyCondition(), metav1.ConditionFalse, ReasonInProgress, fmt.Sprintf("Step %s in progress", step.Name))
+			return update(reconcile.Result{RequeueAfter: w.pollInterval()}, nil)
+		}
+
+		set(step.Name, metav1.ConditionTrue, ReasonCompleted, "")
+		if i < len(w.Steps)-1 {
+			next := w.Steps[i+1].Name
+			set(next, metav1.ConditionFalse, ReasonPending, "")
+			set(w.readyCondition(), metav1.ConditionFalse, ReasonInProgress, fmt.Sprintf("Step %s pending", next))
+			return update(reconcile.Result{Requeue: true}, nil)
+		}
+	}
+
+	set(w.readyCondition(), metav1.ConditionTrue, ReasonCompleted, "")
+	return update(reconcile.Result{}, nil)
+}
+
+// done reports whether step completed.
+func (w *Workflow[T]) done(ctx context.Context, obj T, step Step[T], conditions []metav1.Condition) (bool, error) {
+	if step.Done != nil {
+		return step.Done(ctx, obj)
+	}
+	condition := meta.FindStatusCondition(conditions, step.Name)
+	return condition != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == obj.GetGeneration(), nil
+}
+
+func (w *Workflow[T]) conditions(obj T) (*[]metav1.Condition, error) {
+	if w.Conditions != nil {
+		return w.Conditions(obj), nil
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+		if status := v.Elem().FieldByName("Status"); status.Kind() == reflect.Struct {
+			if field := status.FieldByName("Conditions"); field.IsValid() && field.CanAddr() {
+				if conditions, ok := field.Addr().Interface().(*[]metav1.Condition); ok {
+					return conditions, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("%T has no Status.Conditions field of type []metav1.Condition, must specify Conditions", obj)
+}
+
+func (w *Workflow[T]) readyCondition() string {
+	if w.ReadyCondition != "" {
+		return w.ReadyCondition
+	}
+	return DefaultReadyCondition
+}
+
+func (w *Workflow[T]) pollInterval() time.Duration {
+	if w.PollInterval > 0 {
+		return w.PollInterval
+	}
+	return DefaultPollInterval
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/workflow"
+)
+
+func TestWorkflow(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Workflow Suite")
+}
+
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            DatabaseStatus `json:"status,omitempty"`
+}
+
+type DatabaseStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+func (d *Database) DeepCopyObject() runtime.Object {
+	out := *d
+	d.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status.Conditions = nil
+	for _, c := range d.Status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, *c.DeepCopy())
+	}
+	return &out
+}
+
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Database `json:"items"`
+}
+
+func (l *DatabaseList) DeepCopyObject() runtime.Object {
+	out := *l
+	out.Items = nil
+	for i := range l.Items {
+		out.Items = append(out.Items, *l.Items[i].DeepCopyObject().(*Database))
+	}
+	return &out
+}
+
+var _ = Describe("Workflow", func() {
+	var (
+		ctx   context.Context
+		c     client.Client
+		db    *Database
+		ran   []string
+		ready bool
+		wf    *workflow.Workflow[*Database]
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		s := runtime.NewScheme()
+		gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+		s.AddKnownTypes(gv, &Database{}, &DatabaseList{})
+		metav1.AddToGroupVersion(s, gv)
+
+		db = &Database{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db", Generation: 1}}
+		c = fake.NewClientBuilder().WithScheme(s).WithObjects(db).WithStatusSubresource(db).Build()
+		ran = nil
+		ready = false
+		step := func(name string, done func() bool) workflow.Step[*Database] {
+			return workflow.Step[*Database]{
+				Name: name,
+				Run: func(context.Context, *Database) (bool, error) {
+					ran = append(ran, name)
+					return done(), nil
+				},
+			}
+		}
+		wf = &workflow.Workflow[*Database]{
+			Client: c,
+			Steps: []workflow.Step[*Database]{
+				step("VolumeProvisioned", func() bool { return true }),
+				step("SchemaMigrated", func() bool { return ready }),
+			},
+		}
+	})
+
+	run := func() reconcile.Result {
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(db), db)).To(Succeed())
+		result, err := wf.Run(ctx, db)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	It("should run the steps in order, reporting their progress", func() {
+		Expect(run()).To(Equal(reconcile.Result{Requeue: true}))
+		Expect(ran).To(Equal([]string{"VolumeProvisioned"}))
+		Expect(meta.IsStatusConditionTrue(db.Status.Conditions, "VolumeProvisioned")).To(BeTrue())
+		Expect(meta.FindStatusCondition(db.Status.Conditions, "SchemaMigrated").Reason).To(Equal(workflow.ReasonPending))
+		Expect(meta.FindStatusCondition(db.Status.Conditions, workflow.DefaultReadyCondition).Reason).To(Equal(workflow.ReasonInProgress))
+
+		Expect(run()).To(Equal(reconcile.Result{RequeueAfter: workflow.DefaultPollInterval}))
+		Expect(ran).To(Equal([]string{"VolumeProvisioned", "SchemaMigrated"}))
+		Expect(meta.FindStatusCondition(db.Status.Conditions, "SchemaMigrated").Reason).To(Equal(workflow.ReasonInProgress))
+
+		ready = true
+		Expect(run()).To(Equal(reconcile.Result{}))
+		Expect(ran).To(Equal([]string{"VolumeProvisioned", "SchemaMigrated", "SchemaMigrated"}))
+		Expect(meta.IsStatusConditionTrue(db.Status.Conditions, "SchemaMigrated")).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(db.Status.Conditions, workflow.DefaultReadyCondition)).To(BeTrue())
+
+		By("running no step once all completed")
+		Expect(run()).To(Equal(reconcile.Result{}))
+		Expect(ran).To(HaveLen(3))
+	})
+
+	It("should run the steps again for a new generation", func() {
+		ready = true
+		run()
+		run()
+		Expect(ran).To(HaveLen(2))
+
+		db.Generation = 2
+		Expect(c.Update(ctx, db)).To(Succeed())
+		run()
+		Expect(ran).To(Equal([]string{"VolumeProvisioned", "SchemaMigrated", "VolumeProvisioned"}))
+	})
+
+	It("should skip the steps reported done", func() {
+		wf.Steps[0].Done = func(context.Context, *Database) (bool, error) { return true, nil }
+		run()
+		Expect(ran).To(Equal([]string{"SchemaMigrated"}))
+		Expect(meta.IsStatusConditionTrue(db.Status.Conditions, "VolumeProvisioned")).To(BeTrue())
+	})
+
+	It("should report failed steps", func() {
+		wf.Steps[0].Run = func(context.Context, *Database) (bool, error) { return false, errors.New("out of space") }
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(db), db)).To(Succeed())
+		_, err := wf.Run(ctx, db)
+		Expect(err).To(MatchError(ContainSubstring("out of space")))
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(db), db)).To(Succeed())
+		condition := meta.FindStatusCondition(db.Status.Conditions, "VolumeProvisioned")
+		Expect(condition.Reason).To(Equal(workflow.ReasonFailed))
+		Expect(condition.Message).To(Equal("out of space"))
+	})
+})